@@ -15,87 +15,104 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Authenticates a user with the provided credentials",
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Returns the public keys access tokens are signed with as a JSON Web Key Set, so other services can verify them. Tokens name their key in the kid header.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "User"
                 ],
-                "summary": "Login a user",
-                "operationId": "login-user",
-                "parameters": [
-                    {
-                        "description": "User Credentials",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
+                "summary": "Get token verification keys",
+                "operationId": "get-jwks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_auth.LoginRequest"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.KeysResponse"
                         }
                     }
+                }
+            }
+        },
+        "/api-key": {
+            "get": {
+                "description": "Retrieves the API keys of the user with their scopes, expiry and when they were last used",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Key"
                 ],
+                "summary": "List API keys",
+                "operationId": "list-api-keys",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_auth.LoginResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_api_key.ListAPIKeysResponse"
                         }
                     }
                 }
-            }
-        },
-        "/auth/logout": {
+            },
             "post": {
-                "description": "Logs out a user by invalidating their authentication token",
+                "description": "Issues a key for scripts and integrations, sent as \"Authorization: Bearer fk_...\". The key is returned only once.\nScopes: read allows every read; budgets:write, categories:write, transactions:write, recurring:write, limits:write and rules:write\neach allow changes to one part of the API. Account and session endpoints, budget members, invitations and budget deletion always need a signed-in session.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "API Key"
                 ],
-                "summary": "Logout a user",
-                "operationId": "logout-user",
+                "summary": "Create an API key",
+                "operationId": "create-api-key",
                 "parameters": [
                     {
-                        "description": "Authentication Token",
-                        "name": "token",
+                        "description": "API key",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_auth.LogoutRequest"
+                            "$ref": "#/definitions/finly-backend_internal_service_api_key.CreateAPIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_auth.LogoutResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_api_key.CreateAPIKeyResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Email address is not verified",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
                         }
                     }
                 }
             }
         },
-        "/auth/me": {
-            "get": {
-                "description": "Retrieves information about the currently authenticated user",
+        "/api-key/{id}": {
+            "delete": {
+                "description": "Revokes an API key. Requests made with it are rejected immediately.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "API Key"
                 ],
-                "summary": "Get user information",
-                "operationId": "get-user-info",
+                "summary": "Delete an API key",
+                "operationId": "delete-api-key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Authentication Token",
-                        "name": "token",
-                        "in": "header",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -103,31 +120,38 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_auth.MeResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_api_key.DeleteAPIKeyResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/refresh": {
+        "/auth/2fa/confirm": {
             "post": {
-                "description": "Refreshes a user's authentication token by generating a new one",
+                "description": "Confirms the enrolled secret with a code of the authenticator app and returns the recovery codes, which are shown only once",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "User"
                 ],
-                "summary": "Refresh a user token",
-                "operationId": "refresh-token",
+                "summary": "Enable two-factor authentication",
+                "operationId": "confirm-two-factor",
                 "parameters": [
                     {
+                        "type": "string",
                         "description": "Authentication Token",
                         "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Authenticator code",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_auth.RefreshTokenRequest"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.ConfirmTwoFactorRequest"
                         }
                     }
                 ],
@@ -135,228 +159,266 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_auth.RefreshTokenResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.ConfirmTwoFactorResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/register": {
+        "/auth/2fa/disable": {
             "post": {
-                "description": "Registers a new user with the provided details",
+                "description": "Turns two-factor authentication off after checking the password and an authenticator or recovery code",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "User"
                 ],
-                "summary": "Register a new user",
-                "operationId": "register-user",
+                "summary": "Disable two-factor authentication",
+                "operationId": "disable-two-factor",
                 "parameters": [
                     {
-                        "description": "User Details",
-                        "name": "user",
+                        "type": "string",
+                        "description": "Authentication Token",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Password and code",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_auth.RegisterRequest"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.DisableTwoFactorRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_auth.RegisterResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.DisableTwoFactorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, see the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
                         }
                     }
                 }
             }
         },
-        "/budget": {
+        "/auth/2fa/enroll": {
             "post": {
-                "description": "Creates a new budget for the user with the provided details",
+                "description": "Creates an authenticator secret and its otpauth URI. Two-factor authentication is enabled once a code is confirmed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Budget"
+                    "User"
                 ],
-                "summary": "Create a new budget",
-                "operationId": "create-budget",
+                "summary": "Start two-factor enrolment",
+                "operationId": "enroll-two-factor",
                 "parameters": [
                     {
-                        "description": "Budget Details",
-                        "name": "budget",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_budget.CreateBudgetRequest"
-                        }
+                        "type": "string",
+                        "description": "Authentication Token",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_budget.CreateBudgetResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.EnrollTwoFactorResponse"
                         }
                     }
                 }
             }
         },
-        "/budget/{budget_id}": {
-            "get": {
-                "description": "Retrieves a budget by its ID for the specified user",
+        "/auth/2fa/recovery-codes": {
+            "post": {
+                "description": "Replaces all recovery codes with new ones after checking an authenticator or recovery code",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Budget"
+                    "User"
                 ],
-                "summary": "Get budget by ID",
-                "operationId": "get-budget-by-id",
+                "summary": "Regenerate recovery codes",
+                "operationId": "regenerate-recovery-codes",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "BudgetObject ID",
-                        "name": "budget_id",
-                        "in": "path",
+                        "description": "Authentication Token",
+                        "name": "token",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "header",
-                        "required": true
+                        "description": "Authenticator or recovery code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.RegenerateRecoveryCodesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_budget.GetBudgetByIDResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.RegenerateRecoveryCodesResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, see the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
                         }
                     }
                 }
             }
         },
-        "/budget/{budget_id}/balance": {
-            "get": {
-                "description": "Retrieves the current balance of a budget for the specified user",
+        "/auth/2fa/verify": {
+            "post": {
+                "description": "Exchanges the challenge token of a login and an authenticator or recovery code for a token pair",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Budget"
+                    "User"
                 ],
-                "summary": "Get current balance",
-                "operationId": "get-current-balance",
+                "summary": "Complete a two-factor sign-in",
+                "operationId": "verify-two-factor",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "BudgetObject ID",
-                        "name": "budget_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "header",
-                        "required": true
+                        "description": "Challenge token and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.VerifyTwoFactorRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_budget.GetCurrentBalanceResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.VerifyTwoFactorResponse"
                         }
                     }
                 }
             }
         },
-        "/budget/{budget_id}/history": {
-            "get": {
-                "description": "Retrieves the history of a budget for the specified user",
+        "/auth/email/change": {
+            "post": {
+                "description": "Mails a confirmation link to the new address; the email changes once the link is opened through GET /auth/verify",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Budget"
+                    "User"
                 ],
-                "summary": "Get budget history",
-                "operationId": "get-budget-history",
+                "summary": "Change email",
+                "operationId": "change-email",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "BudgetObject ID",
-                        "name": "budget_id",
-                        "in": "path",
+                        "description": "Authentication Token",
+                        "name": "token",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "header",
-                        "required": true
+                        "description": "Password and new email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.ChangeEmailRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_budget.GetBudgetHistoryResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.ChangeEmailResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, see the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
                         }
                     }
                 }
             }
         },
-        "/category": {
-            "get": {
-                "description": "Retrieves all categories for the user",
+        "/auth/login": {
+            "post": {
+                "description": "Authenticates a user with the provided credentials. For a user with two-factor authentication the response holds a challenge token instead of the token pair, to be exchanged at /auth/2fa/verify.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "User"
+                ],
+                "summary": "Login a user",
+                "operationId": "login-user",
+                "parameters": [
+                    {
+                        "description": "User Credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.LoginRequest"
+                        }
+                    }
                 ],
-                "summary": "List all categories",
-                "operationId": "list-categories",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/finly-backend_internal_service_category.GetCategoryByIDResponse"
-                            }
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.LoginResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, see the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/logout": {
             "post": {
-                "description": "Creates a new category for the user with the provided details",
+                "description": "Logs out a user by ending the session of their authentication token",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "User"
                 ],
-                "summary": "Create a new category",
-                "operationId": "create-category",
+                "summary": "Logout a user",
+                "operationId": "logout-user",
                 "parameters": [
                     {
-                        "description": "CategoryObject Details",
-                        "name": "category",
+                        "description": "Authentication Token",
+                        "name": "token",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_category.CreateCategoryRequest"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.LogoutRequest"
                         }
                     }
                 ],
@@ -364,29 +426,29 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_category.CreateCategoryResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.LogoutResponse"
                         }
                     }
                 }
             }
         },
-        "/category/{id}": {
+        "/auth/me": {
             "get": {
-                "description": "Retrieves the category details for the given ID",
+                "description": "Retrieves information about the currently authenticated user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "User"
                 ],
-                "summary": "Get category by ID",
-                "operationId": "get-category-by-id",
+                "summary": "Get user information",
+                "operationId": "get-user-info",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "CategoryObject ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Authentication Token",
+                        "name": "token",
+                        "in": "header",
                         "required": true
                     }
                 ],
@@ -394,87 +456,162 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_category.GetCategoryByIDResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.MeResponse"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Deletes the category with the given ID",
+                "description": "Deletes the current user with all budgets, transactions and other data after checking the password, and a two-factor code when enabled. Every session is signed out.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "User"
                 ],
-                "summary": "Delete a category",
-                "operationId": "delete-category",
+                "summary": "Delete account",
+                "operationId": "delete-me",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "CategoryObject ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Authentication Token",
+                        "name": "token",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Password and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.DeleteMeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_category.DeleteCategoryResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.DeleteMeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Custom categories of the user are still used in budgets of other users",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, see the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Changes the first and last name of the current user; fields left out keep their value",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update profile",
+                "operationId": "update-me",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication Token",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Profile fields",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.UpdateMeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.UpdateMeResponse"
                         }
                     }
                 }
             }
         },
-        "/transaction": {
-            "get": {
-                "description": "Retrieves a list of transactions for the user",
+        "/auth/password/change": {
+            "post": {
+                "description": "Sets a new password after checking the current one and signs out every other session",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transaction"
+                    "User"
                 ],
-                "summary": "List transactions",
-                "operationId": "list-transactions",
+                "summary": "Change password",
+                "operationId": "change-password",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query",
+                        "description": "Authentication Token",
+                        "name": "token",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Current and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.ChangePasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_transaction.ListTransactionResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.ChangePasswordResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, see the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/password/forgot": {
             "post": {
-                "description": "Creates a new transaction for the user with the provided details",
+                "description": "Sends a link to reset the password to the email, if it belongs to an account. The response doesn't tell whether it does.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transaction"
+                    "User"
                 ],
-                "summary": "Create a new transaction",
-                "operationId": "create-transaction",
+                "summary": "Request a password reset",
+                "operationId": "forgot-password",
                 "parameters": [
                     {
-                        "description": "TransactionObject Details",
-                        "name": "transaction",
+                        "description": "Account email",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_transaction.CreateTransactionRequest"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.ForgotPasswordRequest"
                         }
                     }
                 ],
@@ -482,29 +619,125 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_transaction.CreateTransactionResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.ForgotPasswordResponse"
                         }
                     }
                 }
             }
         },
-        "/transaction/{id}": {
-            "delete": {
-                "description": "Deletes an existing transaction by its ID",
+        "/auth/password/reset": {
+            "post": {
+                "description": "Sets a new password with the token of a reset link. The link works once and every session of the user is signed out.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transaction"
+                    "User"
                 ],
-                "summary": "Delete a transaction",
-                "operationId": "delete-transaction",
+                "summary": "Reset the password",
+                "operationId": "reset-password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.ResetPasswordResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token for a new access token and refresh token.\nEvery refresh token can be used once; using it again revokes the whole session.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Refresh a user token",
+                "operationId": "refresh-token",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.RefreshTokenResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Registers a new user with the provided details",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Register a new user",
+                "operationId": "register-user",
+                "parameters": [
+                    {
+                        "description": "User Details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.RegisterResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify": {
+            "get": {
+                "description": "Confirms the email address of an account with the token of a verification link",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Verify the email address",
+                "operationId": "verify-email",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "TransactionObject ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Verification token",
+                        "name": "token",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -512,36 +745,95 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_transaction.DeleteTransactionResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.VerifyEmailResponse"
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Updates an existing transaction with the provided details",
+            }
+        },
+        "/auth/verify/resend": {
+            "post": {
+                "description": "Sends a new verification link to the email address of the authenticated user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transaction"
+                    "User"
                 ],
-                "summary": "Update a transaction",
-                "operationId": "update-transaction",
+                "summary": "Resend the verification link",
+                "operationId": "resend-verification",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "TransactionObject ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Authentication Token",
+                        "name": "token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_auth.ResendVerificationResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget": {
+            "get": {
+                "description": "Retrieves all budgets of the user, optionally including archived ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "List budgets",
+                "operationId": "list-budgets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "description": "TransactionObject Details",
-                        "name": "transaction",
+                        "type": "boolean",
+                        "description": "Include archived budgets",
+                        "name": "include_archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget.ListBudgetsResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new budget for the user with the provided details",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Create a new budget",
+                "operationId": "create-budget",
+                "parameters": [
+                    {
+                        "description": "Budget Details",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_transaction.UpdateTransactionRequest"
+                            "$ref": "#/definitions/finly-backend_internal_service_budget.CreateBudgetRequest"
                         }
                     }
                 ],
@@ -549,7 +841,2206 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/finly-backend_internal_service_transaction.UpdateTransactionResponse"
+                            "$ref": "#/definitions/finly-backend_internal_service_budget.CreateBudgetResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget/invitations": {
+            "get": {
+                "description": "Lists the pending invitations sent to the email of the user, once it is verified",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "List my invitations",
+                "operationId": "list-my-budget-invitations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget_member.ListMyInvitationsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Email address is not verified",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget/invitations/{id}/accept": {
+            "post": {
+                "description": "Joins the budget with the role of the invitation. The invitation must have been sent to the email of the user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Accept an invitation",
+                "operationId": "accept-budget-invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invitation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget_member.AcceptInvitationResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Email address is not verified",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget/invitations/{id}/decline": {
+            "post": {
+                "description": "Declines an invitation sent to the email of the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Decline an invitation",
+                "operationId": "decline-budget-invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invitation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget_member.DeclineInvitationResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Email address is not verified",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget/{budget_id}": {
+            "get": {
+                "description": "Retrieves a budget by its ID for the specified user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Get budget by ID",
+                "operationId": "get-budget-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "BudgetObject ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget.GetBudgetByIDResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a budget together with its history and transactions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Delete a budget",
+                "operationId": "delete-budget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "BudgetObject ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget.DeleteBudgetResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates the name or currency of a budget. The currency cannot change once the budget has transactions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Update a budget",
+                "operationId": "update-budget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "BudgetObject ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Budget Details",
+                        "name": "budget",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget.UpdateBudgetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget.UpdateBudgetResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The currency of a budget with transactions cannot change",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget/{budget_id}/archive": {
+            "post": {
+                "description": "Hides a budget from the default list without deleting its data. An archived budget is read-only: transactions, transfers, imports and recurring runs against it are rejected until it is unarchived",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Archive a budget",
+                "operationId": "archive-budget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "BudgetObject ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget.ArchiveBudgetResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget/{budget_id}/balance": {
+            "get": {
+                "description": "Retrieves the current balance of a budget for the specified user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Get current balance",
+                "operationId": "get-current-balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "BudgetObject ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget.GetCurrentBalanceResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget/{budget_id}/history": {
+            "get": {
+                "description": "Retrieves the history of a budget for the specified user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Get budget history",
+                "operationId": "get-budget-history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "BudgetObject ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget.GetBudgetHistoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget/{budget_id}/invitations": {
+            "get": {
+                "description": "Lists the invitations to the budget that have not been answered and have not expired. Only the owner can list them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "List the pending invitations of a budget",
+                "operationId": "list-budget-invitations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget_member.ListBudgetInvitationsResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Emails an invitation to join the budget as an editor or a viewer. It can be answered for 7 days by whoever signs in\nwith the email. Inviting the same email again renews the pending invitation. Only the owner can invite.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Invite someone to a budget",
+                "operationId": "create-budget-invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Invitee",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget_member.CreateInvitationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget_member.CreateInvitationResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget/{budget_id}/invitations/{id}": {
+            "delete": {
+                "description": "Withdraws a pending invitation to the budget. Only the owner can revoke invitations.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Revoke an invitation",
+                "operationId": "revoke-budget-invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invitation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget_member.RevokeInvitationResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget/{budget_id}/members": {
+            "get": {
+                "description": "Lists the members of a budget with their roles, the owner first. Any member can list them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "List budget members",
+                "operationId": "list-budget-members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget_member.ListMembersResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget/{budget_id}/members/{user_id}": {
+            "delete": {
+                "description": "The owner removes a member, or a member removes themselves to leave the budget.\nTransactions the member created stay in the budget.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Remove a member",
+                "operationId": "remove-budget-member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Member user ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget_member.RemoveMemberResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Makes a member an editor or a viewer. Only the owner can change roles, and the owner's role can't be changed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Change the role of a member",
+                "operationId": "update-budget-member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Member user ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget_member.UpdateMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget_member.UpdateMemberResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget/{budget_id}/unarchive": {
+            "post": {
+                "description": "Restores an archived budget to the default list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budget"
+                ],
+                "summary": "Unarchive a budget",
+                "operationId": "unarchive-budget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "BudgetObject ID",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_budget.UnarchiveBudgetResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/category": {
+            "get": {
+                "description": "Retrieves all categories for the user as a tree, with subcategories nested under their parents.\nHidden categories are left out unless include_hidden is true.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Category"
+                ],
+                "summary": "List all categories",
+                "operationId": "list-categories",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Include hidden categories",
+                        "name": "include_hidden",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/finly-backend_internal_service_category.GetCategoryByIDResponse"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new category for the user with the provided details",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Category"
+                ],
+                "summary": "Create a new category",
+                "operationId": "create-category",
+                "parameters": [
+                    {
+                        "description": "CategoryObject Details",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.CreateCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.CreateCategoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/category/order": {
+            "put": {
+                "description": "Lists the given categories first, in the given order. Categories left out are listed after them, oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Category"
+                ],
+                "summary": "Reorder categories",
+                "operationId": "reorder-categories",
+                "parameters": [
+                    {
+                        "description": "Category IDs in order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.ReorderCategoriesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.ReorderCategoriesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/category/{id}": {
+            "get": {
+                "description": "Retrieves the category details for the given ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Category"
+                ],
+                "summary": "Get category by ID",
+                "operationId": "get-category-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CategoryObject ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.GetCategoryByIDResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes the category with the given ID. Its subcategories become top-level categories.\nA category used by transactions is only deleted with reassign_to, which moves them to that category.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Category"
+                ],
+                "summary": "Delete a category",
+                "operationId": "delete-category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CategoryObject ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category to move the transactions to",
+                        "name": "reassign_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.DeleteCategoryResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Renames a category or changes its icon or color. Fields left out are kept.\nChanges to a default category only apply to the user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Category"
+                ],
+                "summary": "Update a category",
+                "operationId": "update-category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CategoryObject ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changed fields",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.UpdateCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.UpdateCategoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/category/{id}/hidden": {
+            "put": {
+                "description": "Hides a category and its subcategories from the category lists of the user, or shows them again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Category"
+                ],
+                "summary": "Hide or show a category",
+                "operationId": "set-category-hidden",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CategoryObject ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Whether the category is hidden",
+                        "name": "hidden",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.SetCategoryHiddenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.SetCategoryHiddenResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/category/{id}/merge": {
+            "post": {
+                "description": "Moves the transactions, recurring transactions and limits of a custom category to target_id\nand deletes it. Its subcategories become top-level categories.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Category"
+                ],
+                "summary": "Merge a category into another",
+                "operationId": "merge-category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CategoryObject ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target category",
+                        "name": "target",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.MergeCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.MergeCategoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/category/{id}/override": {
+            "delete": {
+                "description": "Drops the name, icon, color and hiding the user set on a default category",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Category"
+                ],
+                "summary": "Reset a category override",
+                "operationId": "reset-category-override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CategoryObject ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.ResetCategoryOverrideResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/category/{id}/parent": {
+            "put": {
+                "description": "Makes a custom category a subcategory of parent_id, or a top-level category when parent_id is empty.\nA category cannot be moved under itself or one of its subcategories.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Category"
+                ],
+                "summary": "Move a category",
+                "operationId": "move-category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CategoryObject ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New parent",
+                        "name": "parent",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.MoveCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category.MoveCategoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/export": {
+            "post": {
+                "description": "Queues a ZIP archive of the profile, budgets, budget history, transactions and custom categories,\neach as JSON and CSV. The archive is built in the background; poll the export until it is completed.\nWhile an export is queued or running, it is returned instead of starting another one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Request a personal data export",
+                "operationId": "create-data-export",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_data_export.CreateDataExportResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Email address is not verified",
+                        "schema": {
+                            "$ref": "#/definitions/echo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/export/{id}": {
+            "get": {
+                "description": "Retrieves the status of a data export: pending, running, completed or failed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Get a data export",
+                "operationId": "get-data-export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_data_export.GetDataExportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/export/{id}/download": {
+            "get": {
+                "description": "Downloads the ZIP archive of a completed export. Archives can be downloaded until they expire.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Download a data export",
+                "operationId": "download-data-export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/limit": {
+            "get": {
+                "description": "Retrieves the limits of a budget with the amount spent, remaining and used percentage in the current period",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Limit"
+                ],
+                "summary": "List category limits with their status",
+                "operationId": "list-limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "budget_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category_limit.ListLimitsResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Limits what may be spent on a category of a budget per month, per week or within a custom range.\nWithdrawals over the limit return warnings, or are rejected when the limit is enforced.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Limit"
+                ],
+                "summary": "Create a category spending limit",
+                "operationId": "create-limit",
+                "parameters": [
+                    {
+                        "description": "Limit Details",
+                        "name": "limit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category_limit.CreateLimitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category_limit.CreateLimitResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/limit/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Limit"
+                ],
+                "summary": "Delete a category limit",
+                "operationId": "delete-limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Limit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category_limit.DeleteLimitResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Changes the amount or enforcement of a limit, or the range of a custom limit",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Limit"
+                ],
+                "summary": "Update a category limit",
+                "operationId": "update-limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Limit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Limit Details",
+                        "name": "limit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category_limit.UpdateLimitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_category_limit.UpdateLimitResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring": {
+            "get": {
+                "description": "Retrieves all recurring transaction rules of the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "List recurring transactions",
+                "operationId": "list-recurring",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_recurring.ListRecurringResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a rule that books a transaction on a daily, weekly, monthly or cron schedule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "Create a recurring transaction",
+                "operationId": "create-recurring",
+                "parameters": [
+                    {
+                        "description": "Recurring Transaction Details",
+                        "name": "recurring",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_recurring.CreateRecurringRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_recurring.CreateRecurringResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring/{id}": {
+            "get": {
+                "description": "Retrieves a recurring transaction rule by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "Get a recurring transaction",
+                "operationId": "get-recurring",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recurring Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_recurring.GetRecurringByIDResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a recurring transaction rule; transactions it already created are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "Delete a recurring transaction",
+                "operationId": "delete-recurring",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recurring Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_recurring.DeleteRecurringResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Changes the amount, note, category or end date of a rule, or pauses and resumes it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurring"
+                ],
+                "summary": "Update a recurring transaction",
+                "operationId": "update-recurring",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recurring Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Recurring Transaction Details",
+                        "name": "recurring",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_recurring.UpdateRecurringRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_recurring.UpdateRecurringResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/categories": {
+            "get": {
+                "description": "Sums deposits and withdrawals per category within [from, to) and compares them with the previous period",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Totals of a budget by category",
+                "operationId": "report-categories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "budget_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the period (RFC 3339, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period (RFC 3339, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_report.CategoriesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/summary": {
+            "get": {
+                "description": "Sums the transactions of a budget within [from, to) and compares them with the previous period.\nWithout from and to the current calendar month is reported.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Income, expense and net cash flow of a budget",
+                "operationId": "report-summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "budget_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the period (RFC 3339, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period (RFC 3339, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_report.SummaryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/timeline": {
+            "get": {
+                "description": "Sums the transactions of a budget per day, week or month within [from, to),\ncompared bucket by bucket with the previous period",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Cash flow of a budget over time",
+                "operationId": "report-timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "budget_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the period (RFC 3339, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period (RFC 3339, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "default": "day",
+                        "description": "Bucket size",
+                        "name": "granularity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_report.TimelineResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rule": {
+            "get": {
+                "description": "Retrieves the transaction rules of the user in the order they run",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "List transaction rules",
+                "operationId": "list-rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction_rule.ListRulesResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a rule that sets the category or adds a tag to matching transactions created or imported without a category",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Create a transaction rule",
+                "operationId": "create-rule",
+                "parameters": [
+                    {
+                        "description": "Transaction Rule Details",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction_rule.CreateRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction_rule.CreateRuleResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rule/apply": {
+            "post": {
+                "description": "Runs the rules over past transactions of a budget or of the user, optionally within a date range, and updates their categories and tags",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Re-apply transaction rules",
+                "operationId": "apply-rules",
+                "parameters": [
+                    {
+                        "description": "Transactions to re-apply the rules to",
+                        "name": "apply",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction_rule.ApplyRulesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction_rule.ApplyRulesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rule/{id}": {
+            "get": {
+                "description": "Retrieves a transaction rule by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Get a transaction rule",
+                "operationId": "get-rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction_rule.GetRuleByIDResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the conditions, actions and name of a rule; the priority is kept when none is given",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Update a transaction rule",
+                "operationId": "update-rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transaction Rule Details",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction_rule.UpdateRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction_rule.UpdateRuleResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a transaction rule; transactions it already categorized are kept as they are",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Delete a transaction rule",
+                "operationId": "delete-rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction_rule.DeleteRuleResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/session": {
+            "get": {
+                "description": "Retrieves the devices the user is signed in on, with their IP address and when they were last used",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session"
+                ],
+                "summary": "List active sessions",
+                "operationId": "list-sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_session.ListSessionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/others": {
+            "delete": {
+                "description": "Signs out every device except the one making the request",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Revoke all other sessions",
+                "operationId": "revoke-other-sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_session.RevokeOtherSessionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/{id}": {
+            "delete": {
+                "description": "Signs a device out. Its access and refresh tokens stop working immediately.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Revoke a session",
+                "operationId": "revoke-session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_session.RevokeSessionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction": {
+            "get": {
+                "description": "Retrieves a filtered, sorted page of the user's transactions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "List transactions",
+                "operationId": "list-transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or before (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "budget_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "deposit",
+                            "withdrawal"
+                        ],
+                        "type": "string",
+                        "description": "Transaction type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum amount",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum amount",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text search in note",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at_desc",
+                            "created_at_asc",
+                            "amount_desc",
+                            "amount_asc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (1-100, default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.ListTransactionResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new transaction for the user with the provided details. Without a category_id, the user's transaction rules pick the category and tags.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Create a new transaction",
+                "operationId": "create-transaction",
+                "parameters": [
+                    {
+                        "description": "TransactionObject Details",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.CreateTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.CreateTransactionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/export": {
+            "get": {
+                "description": "Streams every transaction matching the list filters as CSV, JSON Lines or an OFX statement,\nwith category names and the budget balance after each transaction. Rows are oldest first by default.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/x-ofx"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Export transactions",
+                "operationId": "export-transactions",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "jsonl",
+                            "ofx"
+                        ],
+                        "type": "string",
+                        "description": "Export format (default csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or before (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Budget ID, required for OFX",
+                        "name": "budget_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "deposit",
+                            "withdrawal",
+                            "transfer_in",
+                            "transfer_out"
+                        ],
+                        "type": "string",
+                        "description": "Transaction type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum amount",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum amount",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text search in note",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at_desc",
+                            "created_at_asc",
+                            "amount_desc",
+                            "amount_asc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order (default created_at_asc)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/import": {
+            "post": {
+                "description": "Books every row of the uploaded statement into a budget at its statement date, all or nothing.\nCSV columns are referenced by header name or 1-based position. Rows with errors are reported and nothing is imported.\nRows already imported into the budget (same FITID, or same date, amount, type and note) are skipped.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Import transactions from a CSV bank statement",
+                "operationId": "import-transactions",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Bank statement (CSV, OFX/QFX or QIF)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Budget to import into",
+                        "name": "budget_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category assigned to the imported transactions; without it, transaction rules categorize each row",
+                        "name": "category_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "File format: csv, ofx, qfx or qif (default csv)",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date column (CSV only, required there)",
+                        "name": "date_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signed amount column",
+                        "name": "amount_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Outgoing amount column, used with credit_column",
+                        "name": "debit_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Incoming amount column, used with debit_column",
+                        "name": "credit_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column with the direction (debit/credit, withdrawal/deposit)",
+                        "name": "type_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Note column",
+                        "name": "note_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date format for CSV and QIF, e.g. DD.MM.YYYY (default YYYY-MM-DD for CSV)",
+                        "name": "date_format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Decimal separator: . or , (default .)",
+                        "name": "decimal_separator",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field delimiter (default ,)",
+                        "name": "delimiter",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "The file has no header row",
+                        "name": "no_header",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only preview the import",
+                        "name": "dry_run",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run preview",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.ImportTransactionsResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.ImportTransactionsResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Some rows failed, nothing was imported",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.ImportTransactionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/transfer": {
+            "post": {
+                "description": "Moves money from one of the user's budgets to another as a linked pair of transactions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Create a transfer",
+                "operationId": "create-transfer",
+                "parameters": [
+                    {
+                        "description": "Transfer Details",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.CreateTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.CreateTransferResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/transfer/{transfer_id}": {
+            "delete": {
+                "description": "Deletes both legs of a transfer and restores the affected balances",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Delete a transfer",
+                "operationId": "delete-transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "transfer_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.DeleteTransferResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates the amount and/or note of both legs of a transfer",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Update a transfer",
+                "operationId": "update-transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "transfer_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transfer Details",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.UpdateTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.UpdateTransferResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/{id}": {
+            "delete": {
+                "description": "Deletes an existing transaction by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Delete a transaction",
+                "operationId": "delete-transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TransactionObject ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.DeleteTransactionResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates an existing transaction with the provided details",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Update a transaction",
+                "operationId": "update-transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TransactionObject ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "TransactionObject Details",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.UpdateTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/finly-backend_internal_service_transaction.UpdateTransactionResponse"
                         }
                     }
                 }
@@ -557,171 +3048,2033 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "echo.HTTPError": {
+            "type": "object",
+            "properties": {
+                "message": {}
+            }
+        },
+        "finly-backend_internal_domain_enums_e_api_key_scope.Enum": {
+            "type": "string",
+            "enum": [
+                "read",
+                "budgets:write",
+                "categories:write",
+                "transactions:write",
+                "recurring:write",
+                "limits:write",
+                "rules:write"
+            ],
+            "x-enum-varnames": [
+                "Read",
+                "BudgetsWrite",
+                "CategoriesWrite",
+                "TransactionsWrite",
+                "RecurringWrite",
+                "LimitsWrite",
+                "RulesWrite"
+            ]
+        },
+        "finly-backend_internal_domain_enums_e_budget_role.Enum": {
+            "type": "string",
+            "enum": [
+                "owner",
+                "editor",
+                "viewer"
+            ],
+            "x-enum-varnames": [
+                "Owner",
+                "Editor",
+                "Viewer"
+            ]
+        },
+        "finly-backend_internal_domain_enums_e_limit_period.Enum": {
+            "type": "string",
+            "enum": [
+                "month",
+                "week",
+                "custom"
+            ],
+            "x-enum-varnames": [
+                "Month",
+                "Week",
+                "Custom"
+            ]
+        },
+        "finly-backend_internal_domain_enums_e_recurrence_frequency.Enum": {
+            "type": "string",
+            "enum": [
+                "daily",
+                "weekly",
+                "monthly",
+                "cron"
+            ],
+            "x-enum-varnames": [
+                "Daily",
+                "Weekly",
+                "Monthly",
+                "Cron"
+            ]
+        },
+        "finly-backend_internal_domain_enums_e_report_granularity.Enum": {
+            "type": "string",
+            "enum": [
+                "day",
+                "week",
+                "month"
+            ],
+            "x-enum-varnames": [
+                "Day",
+                "Week",
+                "Month"
+            ]
+        },
         "finly-backend_internal_domain_enums_e_transaction_type.Enum": {
             "type": "string",
             "enum": [
                 "deposit",
                 "withdrawal",
+                "transfer_in",
+                "transfer_out",
                 "initial"
             ],
-            "x-enum-varnames": [
-                "Deposit",
-                "Withdrawal",
-                "Initial"
-            ]
+            "x-enum-varnames": [
+                "Deposit",
+                "Withdrawal",
+                "TransferIn",
+                "TransferOut",
+                "Initial"
+            ]
+        },
+        "finly-backend_internal_service_api_key.APIKeyObject": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_api_key.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes",
+                "userID"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_domain_enums_e_api_key_scope.Enum"
+                    }
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_api_key.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_api_key.DeleteAPIKeyResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_api_key.ListAPIKeysResponse": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_api_key.APIKeyObject"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.ChangeEmailRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "userID"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.ChangeEmailResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password",
+                "userID"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "new_password": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 8
+                },
+                "sessionID": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.ChangePasswordResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.ConfirmTwoFactorRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "userID"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.ConfirmTwoFactorResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.DeleteMeRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "userID"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.DeleteMeResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.DisableTwoFactorRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "password",
+                "userID"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.DisableTwoFactorResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.EnrollTwoFactorResponse": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.ForgotPasswordResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.KeysResponse": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_pkg_security.JWK"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 5
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "challenge_expires_in": {
+                    "type": "integer"
+                },
+                "challenge_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "two_factor_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.LogoutRequest": {
+            "type": "object",
+            "required": [
+                "authToken"
+            ],
+            "properties": {
+                "authToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.LogoutResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.MeResponse": {
+            "type": "object",
+            "required": [
+                "email",
+                "first_name",
+                "last_name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.RefreshTokenResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.RegenerateRecoveryCodesRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "userID"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.RegenerateRecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "first_name",
+                "last_name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 8
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.ResendVerificationResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 8
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.ResetPasswordResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.UpdateMeRequest": {
+            "type": "object",
+            "required": [
+                "userID"
+            ],
+            "properties": {
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.UpdateMeResponse": {
+            "type": "object",
+            "required": [
+                "email",
+                "first_name",
+                "last_name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.VerifyEmailResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.VerifyTwoFactorRequest": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "finly-backend_internal_service_auth.VerifyTwoFactorResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget.ArchiveBudgetResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_budget.BudgetHistory": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "budget_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget.BudgetObject": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget.CreateBudgetRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency",
+                "userID"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget.CreateBudgetResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget.DeleteBudgetResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_budget.GetBudgetByIDResponse": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget.GetBudgetHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "budget_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_budget.BudgetHistory"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_budget.GetCurrentBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget.ListBudgetsResponse": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_budget.BudgetObject"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_budget.UnarchiveBudgetResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_budget.UpdateBudgetRequest": {
+            "type": "object",
+            "required": [
+                "budgetID",
+                "userID"
+            ],
+            "properties": {
+                "budgetID": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget.UpdateBudgetResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_budget_member.AcceptInvitationResponse": {
+            "type": "object",
+            "properties": {
+                "budget_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget_member.CreateInvitationRequest": {
+            "type": "object",
+            "required": [
+                "budgetID",
+                "email",
+                "role",
+                "userID"
+            ],
+            "properties": {
+                "budgetID": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "role": {
+                    "enum": [
+                        "editor",
+                        "viewer"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/finly-backend_internal_domain_enums_e_budget_role.Enum"
+                        }
+                    ]
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget_member.CreateInvitationResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget_member.DeclineInvitationResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_budget_member.InvitationObject": {
+            "type": "object",
+            "properties": {
+                "budget_id": {
+                    "type": "string"
+                },
+                "budget_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inviter_email": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget_member.ListBudgetInvitationsResponse": {
+            "type": "object",
+            "properties": {
+                "invitations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_budget_member.InvitationObject"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_budget_member.ListMembersResponse": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_budget_member.MemberObject"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_budget_member.ListMyInvitationsResponse": {
+            "type": "object",
+            "properties": {
+                "invitations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_budget_member.InvitationObject"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_budget_member.MemberObject": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "joined_at": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget_member.RemoveMemberResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_budget_member.RevokeInvitationResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_budget_member.UpdateMemberRequest": {
+            "type": "object",
+            "required": [
+                "budgetID",
+                "memberID",
+                "role",
+                "userID"
+            ],
+            "properties": {
+                "budgetID": {
+                    "type": "string"
+                },
+                "memberID": {
+                    "type": "string"
+                },
+                "role": {
+                    "enum": [
+                        "editor",
+                        "viewer"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/finly-backend_internal_domain_enums_e_budget_role.Enum"
+                        }
+                    ]
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_budget_member.UpdateMemberResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_category.CategoryObject": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_category.CategoryObject"
+                    }
+                },
+                "color": {
+                    "type": "string",
+                    "maxLength": 7
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hidden": {
+                    "type": "boolean"
+                },
+                "icon": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_user_category": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category.CreateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "userID"
+            ],
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_category.CategoryObject"
+                    }
+                },
+                "color": {
+                    "type": "string",
+                    "maxLength": 7
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hidden": {
+                    "type": "boolean"
+                },
+                "icon": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_user_category": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category.CreateCategoryResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category.DeleteCategoryResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_category.GetCategoryByIDResponse": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_category.CategoryObject"
+                    }
+                },
+                "color": {
+                    "type": "string",
+                    "maxLength": 7
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hidden": {
+                    "type": "boolean"
+                },
+                "icon": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_user_category": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category.ListCustomCategoriesResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_category.CategoryObject"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_category.MergeCategoryRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "target_id",
+                "userID"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category.MergeCategoryResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_category.MoveCategoryRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "userID"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category.MoveCategoryResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_category.ReorderCategoriesRequest": {
+            "type": "object",
+            "required": [
+                "category_ids",
+                "userID"
+            ],
+            "properties": {
+                "category_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category.ReorderCategoriesResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_category.ResetCategoryOverrideResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_category.SetCategoryHiddenRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "userID"
+            ],
+            "properties": {
+                "hidden": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category.SetCategoryHiddenResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_category.UpdateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "userID"
+            ],
+            "properties": {
+                "color": {
+                    "type": "string",
+                    "maxLength": 7
+                },
+                "icon": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category.UpdateCategoryResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_category_limit.CreateLimitRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "budget_id",
+                "category_id",
+                "period",
+                "userID"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "budget_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "enforce": {
+                    "type": "boolean"
+                },
+                "period": {
+                    "enum": [
+                        "month",
+                        "week",
+                        "custom"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/finly-backend_internal_domain_enums_e_limit_period.Enum"
+                        }
+                    ]
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category_limit.CreateLimitResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category_limit.DeleteLimitResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_category_limit.LimitStatusObject": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "budget_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "enforce": {
+                    "type": "boolean"
+                },
+                "exceeded": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "period": {
+                    "$ref": "#/definitions/finly-backend_internal_domain_enums_e_limit_period.Enum"
+                },
+                "period_end": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "remaining": {
+                    "type": "number"
+                },
+                "spent": {
+                    "type": "number"
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category_limit.ListLimitsResponse": {
+            "type": "object",
+            "properties": {
+                "limits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_category_limit.LimitStatusObject"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_category_limit.UpdateLimitRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "userID"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "enforce": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_category_limit.UpdateLimitResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_data_export.CreateDataExportResponse": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_data_export.GetDataExportResponse": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_recurring.CreateRecurringRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "budget_id",
+                "category_id",
+                "frequency",
+                "start_at",
+                "type",
+                "userID"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "budget_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "cron_expr": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "cron"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/finly-backend_internal_domain_enums_e_recurrence_frequency.Enum"
+                        }
+                    ]
+                },
+                "interval": {
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 1
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "deposit",
+                        "withdrawal"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/finly-backend_internal_domain_enums_e_transaction_type.Enum"
+                        }
+                    ]
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_recurring.CreateRecurringResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_recurring.DeleteRecurringResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_recurring.GetRecurringByIDResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "budget_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "cron_expr": {
+                    "type": "string"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "$ref": "#/definitions/finly-backend_internal_domain_enums_e_recurrence_frequency.Enum"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/finly-backend_internal_domain_enums_e_transaction_type.Enum"
+                }
+            }
+        },
+        "finly-backend_internal_service_recurring.ListRecurringResponse": {
+            "type": "object",
+            "properties": {
+                "recurring": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_recurring.RecurringObject"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_recurring.RecurringObject": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "budget_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "cron_expr": {
+                    "type": "string"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "$ref": "#/definitions/finly-backend_internal_domain_enums_e_recurrence_frequency.Enum"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/finly-backend_internal_domain_enums_e_transaction_type.Enum"
+                }
+            }
+        },
+        "finly-backend_internal_service_recurring.UpdateRecurringRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "userID"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_recurring.UpdateRecurringResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_report.BucketObject": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.CountComparison"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "expense": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.Comparison"
+                },
+                "income": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.Comparison"
+                },
+                "net": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.Comparison"
+                },
+                "previous_start": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_report.CategoriesResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_report.CategoryReportObject"
+                    }
+                },
+                "period": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.PeriodObject"
+                },
+                "previous_period": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.PeriodObject"
+                }
+            }
         },
-        "finly-backend_internal_service_auth.LoginRequest": {
+        "finly-backend_internal_service_report.CategoryReportObject": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "category_id": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 5
+                "category_name": {
+                    "type": "string"
+                },
+                "count": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.CountComparison"
+                },
+                "expense": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.Comparison"
+                },
+                "income": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.Comparison"
+                },
+                "share": {
+                    "type": "number"
                 }
             }
         },
-        "finly-backend_internal_service_auth.LoginResponse": {
+        "finly-backend_internal_service_report.Comparison": {
             "type": "object",
             "properties": {
-                "token": {
-                    "type": "string"
+                "change": {
+                    "type": "number"
+                },
+                "change_percent": {
+                    "type": "number"
+                },
+                "current": {
+                    "type": "number"
+                },
+                "previous": {
+                    "type": "number"
                 }
             }
         },
-        "finly-backend_internal_service_auth.LogoutRequest": {
+        "finly-backend_internal_service_report.CountComparison": {
             "type": "object",
-            "required": [
-                "authToken"
-            ],
             "properties": {
-                "authToken": {
-                    "type": "string"
+                "current": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "type": "integer"
                 }
             }
         },
-        "finly-backend_internal_service_auth.LogoutResponse": {
+        "finly-backend_internal_service_report.PeriodObject": {
             "type": "object",
             "properties": {
-                "message": {
+                "from": {
+                    "type": "string"
+                },
+                "to": {
                     "type": "string"
                 }
             }
         },
-        "finly-backend_internal_service_auth.MeResponse": {
+        "finly-backend_internal_service_report.SummaryResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "first_name",
-                "last_name"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "count": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.CountComparison"
                 },
-                "first_name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                "expense": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.Comparison"
                 },
-                "last_name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                "income": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.Comparison"
+                },
+                "net": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.Comparison"
+                },
+                "period": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.PeriodObject"
+                },
+                "previous_period": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.PeriodObject"
+                },
+                "transfers_in": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.Comparison"
+                },
+                "transfers_out": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.Comparison"
                 }
             }
         },
-        "finly-backend_internal_service_auth.RefreshTokenRequest": {
+        "finly-backend_internal_service_report.TimelineResponse": {
             "type": "object",
-            "required": [
-                "authToken"
-            ],
             "properties": {
-                "authToken": {
-                    "type": "string"
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_report.BucketObject"
+                    }
+                },
+                "granularity": {
+                    "$ref": "#/definitions/finly-backend_internal_domain_enums_e_report_granularity.Enum"
+                },
+                "period": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.PeriodObject"
+                },
+                "previous_period": {
+                    "$ref": "#/definitions/finly-backend_internal_service_report.PeriodObject"
                 }
             }
         },
-        "finly-backend_internal_service_auth.RefreshTokenResponse": {
+        "finly-backend_internal_service_session.ListSessionsResponse": {
             "type": "object",
             "properties": {
-                "token": {
-                    "type": "string"
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_session.SessionObject"
+                    }
                 }
             }
         },
-        "finly-backend_internal_service_auth.RegisterRequest": {
+        "finly-backend_internal_service_session.RevokeOtherSessionsResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "first_name",
-                "last_name",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "first_name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
-                },
-                "last_name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
-                },
-                "password": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 8
+                "revoked": {
+                    "type": "integer"
                 }
             }
         },
-        "finly-backend_internal_service_auth.RegisterResponse": {
+        "finly-backend_internal_service_session.RevokeSessionResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_session.SessionObject": {
             "type": "object",
             "properties": {
-                "token": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "user_agent": {
                     "type": "string"
                 }
             }
         },
-        "finly-backend_internal_service_budget.BudgetHistory": {
+        "finly-backend_internal_service_transaction.CreateTransactionRequest": {
             "type": "object",
+            "required": [
+                "amount",
+                "budget_id",
+                "type",
+                "userID"
+            ],
             "properties": {
-                "balance": {
+                "amount": {
                     "type": "number"
                 },
                 "budget_id": {
                     "type": "string"
                 },
-                "created_at": {
+                "category_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "deposit",
+                        "withdrawal"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/finly-backend_internal_domain_enums_e_transaction_type.Enum"
+                        }
+                    ]
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_transaction.CreateTransactionResponse": {
+            "type": "object",
+            "properties": {
+                "category_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_transaction.LimitWarning"
+                    }
                 }
             }
         },
-        "finly-backend_internal_service_budget.CreateBudgetRequest": {
+        "finly-backend_internal_service_transaction.CreateTransferRequest": {
             "type": "object",
             "required": [
                 "amount",
-                "currency",
+                "from_budget_id",
+                "to_budget_id",
                 "userID"
             ],
             "properties": {
                 "amount": {
                     "type": "number"
                 },
-                "currency": {
+                "category_id": {
+                    "type": "string"
+                },
+                "from_budget_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "to_budget_id": {
                     "type": "string"
                 },
                 "userID": {
@@ -729,169 +5082,319 @@ const docTemplate = `{
                 }
             }
         },
-        "finly-backend_internal_service_budget.CreateBudgetResponse": {
+        "finly-backend_internal_service_transaction.CreateTransferResponse": {
             "type": "object",
             "properties": {
-                "id": {
+                "incoming_transaction_id": {
+                    "type": "string"
+                },
+                "outgoing_transaction_id": {
+                    "type": "string"
+                },
+                "transfer_id": {
                     "type": "string"
                 }
             }
         },
-        "finly-backend_internal_service_budget.GetBudgetByIDResponse": {
+        "finly-backend_internal_service_transaction.DeleteTransactionResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_transaction.DeleteTransferResponse": {
+            "type": "object"
+        },
+        "finly-backend_internal_service_transaction.ImportRowResult": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "amount": {
+                    "type": "number"
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "category_id": {
                     "type": "string"
                 },
-                "currency": {
+                "date": {
                     "type": "string"
                 },
-                "id": {
+                "duplicate": {
+                    "type": "boolean"
+                },
+                "error": {
                     "type": "string"
                 },
-                "updated_at": {
+                "external_id": {
                     "type": "string"
                 },
-                "user_id": {
+                "line": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "transaction_id": {
                     "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/finly-backend_internal_domain_enums_e_transaction_type.Enum"
                 }
             }
         },
-        "finly-backend_internal_service_budget.GetBudgetHistoryResponse": {
+        "finly-backend_internal_service_transaction.ImportTransactionsResponse": {
             "type": "object",
             "properties": {
-                "budget_history": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_transaction.ImportRowResult"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "finly-backend_internal_service_transaction.LimitWarning": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "number"
+                },
+                "limit_id": {
+                    "type": "string"
+                },
+                "over_by": {
+                    "type": "number"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "spent": {
+                    "type": "number"
+                }
+            }
+        },
+        "finly-backend_internal_service_transaction.ListTransactionResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "transactions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/finly-backend_internal_service_budget.BudgetHistory"
+                        "$ref": "#/definitions/finly-backend_internal_service_transaction.TransactionObject"
                     }
                 }
             }
         },
-        "finly-backend_internal_service_budget.GetCurrentBalanceResponse": {
+        "finly-backend_internal_service_transaction.TransactionObject": {
             "type": "object",
             "properties": {
-                "balance": {
+                "amount": {
                     "type": "number"
-                }
-            }
-        },
-        "finly-backend_internal_service_category.CategoryObject": {
-            "type": "object",
-            "required": [
-                "name"
-            ],
-            "properties": {
+                },
+                "budget_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "created_by": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "is_user_category": {
-                    "type": "boolean"
+                "note": {
+                    "type": "string"
                 },
-                "name": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "transfer_id": {
                     "type": "string"
                 },
+                "type": {
+                    "$ref": "#/definitions/finly-backend_internal_domain_enums_e_transaction_type.Enum"
+                },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
-        "finly-backend_internal_service_category.CreateCategoryRequest": {
+        "finly-backend_internal_service_transaction.UpdateTransactionRequest": {
             "type": "object",
             "required": [
-                "name",
+                "transactionID",
                 "userID"
             ],
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "amount": {
+                    "type": "number"
                 },
-                "id": {
-                    "type": "string"
+                "budget_id": {
+                    "type": "integer"
                 },
-                "is_user_category": {
-                    "type": "boolean"
+                "category_id": {
+                    "type": "string"
                 },
-                "name": {
+                "note": {
                     "type": "string"
                 },
-                "userID": {
+                "transactionID": {
                     "type": "string"
                 },
-                "user_id": {
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "deposit",
+                        "withdrawal"
+                    ]
+                },
+                "userID": {
                     "type": "string"
                 }
             }
         },
-        "finly-backend_internal_service_category.CreateCategoryResponse": {
+        "finly-backend_internal_service_transaction.UpdateTransactionResponse": {
             "type": "object",
             "properties": {
-                "id": {
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/finly-backend_internal_service_transaction.LimitWarning"
+                    }
+                }
+            }
+        },
+        "finly-backend_internal_service_transaction.UpdateTransferRequest": {
+            "type": "object",
+            "required": [
+                "transferID",
+                "userID"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "transferID": {
+                    "type": "string"
+                },
+                "userID": {
                     "type": "string"
                 }
             }
         },
-        "finly-backend_internal_service_category.DeleteCategoryResponse": {
+        "finly-backend_internal_service_transaction.UpdateTransferResponse": {
             "type": "object"
         },
-        "finly-backend_internal_service_category.GetCategoryByIDResponse": {
+        "finly-backend_internal_service_transaction_rule.ApplyRulesRequest": {
             "type": "object",
             "required": [
-                "name"
+                "userID"
             ],
             "properties": {
-                "created_at": {
+                "budget_id": {
                     "type": "string"
                 },
-                "id": {
+                "from": {
                     "type": "string"
                 },
-                "is_user_category": {
-                    "type": "boolean"
-                },
-                "name": {
+                "to": {
                     "type": "string"
                 },
-                "user_id": {
+                "userID": {
                     "type": "string"
                 }
             }
         },
-        "finly-backend_internal_service_category.ListCustomCategoriesResponse": {
+        "finly-backend_internal_service_transaction_rule.ApplyRulesResponse": {
             "type": "object",
             "properties": {
-                "categories": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/finly-backend_internal_service_category.CategoryObject"
-                    }
+                "checked": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
                 }
             }
         },
-        "finly-backend_internal_service_transaction.CreateTransactionRequest": {
+        "finly-backend_internal_service_transaction_rule.CreateRuleRequest": {
             "type": "object",
             "required": [
-                "amount",
-                "budget_id",
-                "category_id",
-                "type",
+                "name",
                 "userID"
             ],
             "properties": {
-                "amount": {
-                    "type": "number"
-                },
                 "budget_id": {
                     "type": "string"
                 },
                 "category_id": {
                     "type": "string"
                 },
-                "note": {
-                    "type": "string"
+                "max_amount": {
+                    "type": "number"
+                },
+                "min_amount": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "note_contains": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "note_pattern": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "priority": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "tag": {
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "type": {
                     "enum": [
@@ -909,7 +5412,7 @@ const docTemplate = `{
                 }
             }
         },
-        "finly-backend_internal_service_transaction.CreateTransactionResponse": {
+        "finly-backend_internal_service_transaction_rule.CreateRuleResponse": {
             "type": "object",
             "properties": {
                 "id": {
@@ -917,26 +5420,67 @@ const docTemplate = `{
                 }
             }
         },
-        "finly-backend_internal_service_transaction.DeleteTransactionResponse": {
+        "finly-backend_internal_service_transaction_rule.DeleteRuleResponse": {
             "type": "object"
         },
-        "finly-backend_internal_service_transaction.ListTransactionResponse": {
+        "finly-backend_internal_service_transaction_rule.GetRuleByIDResponse": {
             "type": "object",
             "properties": {
-                "transactions": {
+                "budget_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_amount": {
+                    "type": "number"
+                },
+                "min_amount": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "note_contains": {
+                    "type": "string"
+                },
+                "note_pattern": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "tag": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/finly-backend_internal_domain_enums_e_transaction_type.Enum"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "finly-backend_internal_service_transaction_rule.ListRulesResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/finly-backend_internal_service_transaction.TransactionObject"
+                        "$ref": "#/definitions/finly-backend_internal_service_transaction_rule.RuleObject"
                     }
                 }
             }
         },
-        "finly-backend_internal_service_transaction.TransactionObject": {
+        "finly-backend_internal_service_transaction_rule.RuleObject": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "number"
-                },
                 "budget_id": {
                     "type": "string"
                 },
@@ -949,49 +5493,125 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
-                "note": {
+                "max_amount": {
+                    "type": "number"
+                },
+                "min_amount": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "note_contains": {
+                    "type": "string"
+                },
+                "note_pattern": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "tag": {
                     "type": "string"
                 },
                 "type": {
                     "$ref": "#/definitions/finly-backend_internal_domain_enums_e_transaction_type.Enum"
                 },
-                "user_id": {
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "finly-backend_internal_service_transaction.UpdateTransactionRequest": {
+        "finly-backend_internal_service_transaction_rule.UpdateRuleRequest": {
             "type": "object",
             "required": [
-                "transactionID",
+                "id",
+                "name",
                 "userID"
             ],
             "properties": {
-                "amount": {
-                    "type": "number"
-                },
                 "budget_id": {
-                    "type": "integer"
+                    "type": "string"
                 },
                 "category_id": {
                     "type": "string"
                 },
-                "note": {
+                "id": {
                     "type": "string"
                 },
-                "transactionID": {
-                    "type": "string"
+                "max_amount": {
+                    "type": "number"
+                },
+                "min_amount": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "note_contains": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "note_pattern": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "priority": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "tag": {
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "type": {
-                    "type": "string"
+                    "enum": [
+                        "deposit",
+                        "withdrawal"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/finly-backend_internal_domain_enums_e_transaction_type.Enum"
+                        }
+                    ]
                 },
                 "userID": {
                     "type": "string"
                 }
             }
         },
-        "finly-backend_internal_service_transaction.UpdateTransactionResponse": {
+        "finly-backend_internal_service_transaction_rule.UpdateRuleResponse": {
             "type": "object"
+        },
+        "finly-backend_pkg_security.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
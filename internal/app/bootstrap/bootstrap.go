@@ -7,15 +7,28 @@ import (
 	"finly-backend/internal/repository"
 	"finly-backend/internal/service"
 	"finly-backend/internal/transport/http/router"
+	"finly-backend/internal/transport/http/validation"
 	"finly-backend/pkg/db"
 	"finly-backend/pkg/logger"
+	"finly-backend/pkg/mailer"
+	"finly-backend/pkg/scheduler"
+	"finly-backend/pkg/security"
 	"finly-backend/pkg/server"
 	"fmt"
+	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
+)
+
+const (
+	recurringSchedulerInterval  = time.Minute
+	dataExportSchedulerInterval = 15 * time.Second
 )
 
 func Website() {
@@ -27,6 +40,12 @@ func Website() {
 		panic(err)
 	}
 
+	keys, err := security.LoadKeySet(cfg.JWTKeyID, cfg.JWTSigningKey, cfg.JWTRetiredKeys)
+	if err != nil {
+		panic(err)
+	}
+	security.UseKeys(keys)
+
 	postgres, err := db.NewPostgresDB(cfg)
 	if err != nil {
 		panic(err)
@@ -37,10 +56,28 @@ func Website() {
 		panic(err)
 	}
 
+	mail, err := mailer.NewMailer(cfg)
+	if err != nil {
+		panic(err)
+	}
+
 	repo := repository.NewRepository(postgres, redis)
-	services := service.NewService(repo)
-	srv := server.NewServer(cfg.HTTPPort)
-	router.RegisterRoutes(srv, services)
+	services := service.NewService(repo, cfg, mail)
+	validator, err := validation.New()
+	if err != nil {
+		panic(err)
+	}
+
+	srv := server.NewServer(cfg.HTTPPort, validator)
+	if srv.IPExtractor, err = ipExtractor(cfg.TrustedProxies); err != nil {
+		panic(err)
+	}
+	router.RegisterRoutes(srv, services, cfg.RequireVerifiedEmail)
+
+	recurringScheduler := scheduler.New("recurring-transactions", recurringSchedulerInterval, services.Recurring.RunDue)
+	recurringScheduler.Start(ctx)
+	dataExportScheduler := scheduler.New("data-exports", dataExportSchedulerInterval, services.DataExport.RunPending)
+	dataExportScheduler.Start(ctx)
 
 	zap.L().Sugar().Infof("Finly backend started on port %s", cfg.HTTPPort)
 	go func() {
@@ -56,6 +93,9 @@ func Website() {
 	<-quit
 	zap.L().Sugar().Info("Finly backend shutting down")
 
+	recurringScheduler.Stop()
+	dataExportScheduler.Stop()
+
 	if err = srv.Shutdown(ctx); err != nil {
 		zap.L().Sugar().Fatalf("error with shutting down server: %s", err.Error())
 	}
@@ -68,3 +108,22 @@ func Website() {
 		zap.L().Fatal(fmt.Sprintf("error with closing db: %s", err.Error()))
 	}
 }
+
+// ipExtractor picks the client IP that sessions and sign-in throttling see. Without trusted
+// proxies it is the address of the connection, as any header could be forged; behind
+// proxies it is read from X-Forwarded-For, skipping only the listed proxies.
+func ipExtractor(trustedProxies string) (echo.IPExtractor, error) {
+	if strings.TrimSpace(trustedProxies) == "" {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range strings.Split(trustedProxies, ",") {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q: %v", cidr, err)
+		}
+		options = append(options, echo.TrustIPRange(network))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
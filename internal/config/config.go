@@ -25,6 +25,26 @@ type Config struct {
 	RedisPort     string `mapstructure:"REDIS_PORT" validate:"required"`
 	RedisPassword string `mapstructure:"REDIS_PASSWORD"`
 	RedisDB       int    `mapstructure:"REDIS_DB"`
+
+	// JWT configuration
+	JWTKeyID       string `mapstructure:"JWT_KEY_ID" validate:"required"`
+	JWTSigningKey  string `mapstructure:"JWT_SIGNING_KEY" validate:"required"`
+	JWTRetiredKeys string `mapstructure:"JWT_RETIRED_KEYS"`
+
+	// AppURL is the address of the web app, which links in emails point to
+	AppURL string `mapstructure:"APP_URL" validate:"required,url"`
+
+	// Mail configuration
+	Mailer   string `mapstructure:"MAILER" validate:"omitempty,oneof=log file"`
+	MailFrom string `mapstructure:"MAIL_FROM"`
+	MailDir  string `mapstructure:"MAIL_DIR" validate:"required_if=Mailer file"`
+
+	// RequireVerifiedEmail blocks writes of users who haven't verified their email yet
+	RequireVerifiedEmail bool `mapstructure:"REQUIRE_VERIFIED_EMAIL"`
+
+	// TrustedProxies lists the comma separated CIDR ranges of the reverse proxies in front of
+	// the server, whose X-Forwarded-For header is trusted for the client IP
+	TrustedProxies string `mapstructure:"TRUSTED_PROXIES"`
 }
 
 func NewConfig() (*Config, error) {
@@ -84,6 +104,14 @@ func loadStagingConfig(cfg *Config) error {
 	cfg.RedisHost = getEnv("REDIS_HOST", "")
 	cfg.RedisPort = getEnv("REDIS_PORT", "")
 	cfg.RedisPassword = os.Getenv("REDIS_PASSWORD")
+	cfg.JWTKeyID = getEnv("JWT_KEY_ID", "")
+	cfg.JWTSigningKey = getEnv("JWT_SIGNING_KEY", "")
+	cfg.JWTRetiredKeys = os.Getenv("JWT_RETIRED_KEYS")
+	cfg.AppURL = getEnv("APP_URL", "")
+	cfg.Mailer = getEnv("MAILER", "log")
+	cfg.MailFrom = os.Getenv("MAIL_FROM")
+	cfg.MailDir = os.Getenv("MAIL_DIR")
+	cfg.TrustedProxies = os.Getenv("TRUSTED_PROXIES")
 
 	redisDB := os.Getenv("REDIS_DB")
 	if redisDB != "" {
@@ -93,6 +121,15 @@ func loadStagingConfig(cfg *Config) error {
 		}
 		cfg.RedisDB = redisDBInt
 	}
+
+	requireVerifiedEmail := os.Getenv("REQUIRE_VERIFIED_EMAIL")
+	if requireVerifiedEmail != "" {
+		required, err := strconv.ParseBool(requireVerifiedEmail)
+		if err != nil {
+			return fmt.Errorf("failed to convert REQUIRE_VERIFIED_EMAIL to bool: %v", err)
+		}
+		cfg.RequireVerifiedEmail = required
+	}
 	return nil
 }
 
@@ -21,6 +21,9 @@ func TestNewConfig_Load(t *testing.T) {
 	t.Setenv("REDIS_PASSWORD", "redis_password")
 	t.Setenv("REDIS_DB", "0")
 	t.Setenv("HTTP_PORT", "8080")
+	t.Setenv("JWT_KEY_ID", "key1")
+	t.Setenv("JWT_SIGNING_KEY", "0123456789abcdef0123456789abcdef")
+	t.Setenv("APP_URL", "http://localhost:5173")
 
 	// Mock the loadConfig function
 	viper.SetConfigFile(".env.test")
@@ -35,6 +38,11 @@ func TestNewConfig_Load(t *testing.T) {
 	viper.Set("REDIS_PASSWORD", "redis_password")
 	viper.Set("REDIS_DB", 0)
 	viper.Set("HTTP_PORT", "8080")
+	viper.Set("JWT_KEY_ID", "key1")
+	viper.Set("JWT_SIGNING_KEY", "0123456789abcdef0123456789abcdef")
+	viper.Set("APP_URL", "http://localhost:5173")
+	viper.Set("MAILER", "file")
+	viper.Set("MAIL_DIR", "tmp/mail")
 
 	cfg, err := NewConfig()
 	require.NoError(t, err)
@@ -51,6 +59,11 @@ func TestNewConfig_Load(t *testing.T) {
 	assert.Equal(t, "redis_password", cfg.RedisPassword)
 	assert.Equal(t, 0, cfg.RedisDB)
 	assert.Equal(t, "8080", cfg.HTTPPort)
+	assert.Equal(t, "key1", cfg.JWTKeyID)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", cfg.JWTSigningKey)
+	assert.Equal(t, "http://localhost:5173", cfg.AppURL)
+	assert.Equal(t, "file", cfg.Mailer)
+	assert.Equal(t, "tmp/mail", cfg.MailDir)
 }
 
 func TestNewConfig_InvalidEnv(t *testing.T) {
@@ -76,6 +89,11 @@ func TestLoadStagingConfig(t *testing.T) {
 	t.Setenv("REDIS_PORT", "6380")
 	t.Setenv("REDIS_PASSWORD", "staging_redis_password")
 	t.Setenv("REDIS_DB", "1")
+	t.Setenv("JWT_KEY_ID", "2025-03")
+	t.Setenv("JWT_SIGNING_KEY", "staging_signing_key")
+	t.Setenv("JWT_RETIRED_KEYS", "2025-01=staging_retired_key")
+	t.Setenv("APP_URL", "https://finly.click")
+	t.Setenv("REQUIRE_VERIFIED_EMAIL", "true")
 
 	cfg := &Config{}
 	err := loadStagingConfig(cfg)
@@ -93,4 +111,10 @@ func TestLoadStagingConfig(t *testing.T) {
 	assert.Equal(t, "6380", cfg.RedisPort)
 	assert.Equal(t, "staging_redis_password", cfg.RedisPassword)
 	assert.Equal(t, 1, cfg.RedisDB)
+	assert.Equal(t, "2025-03", cfg.JWTKeyID)
+	assert.Equal(t, "staging_signing_key", cfg.JWTSigningKey)
+	assert.Equal(t, "2025-01=staging_retired_key", cfg.JWTRetiredKeys)
+	assert.Equal(t, "https://finly.click", cfg.AppURL)
+	assert.Equal(t, "log", cfg.Mailer)
+	assert.True(t, cfg.RequireVerifiedEmail)
 }
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"time"
+)
+
+// APIKey lets scripts call the API on behalf of a user without signing in. Only the hash
+// of the key is stored; Prefix is kept so the user can tell their keys apart.
+type APIKey struct {
+	ID         string         `db:"id"`
+	UserID     string         `db:"user_id"`
+	Name       string         `db:"name"`
+	Prefix     string         `db:"prefix"`
+	KeyHash    string         `db:"key_hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	ExpiresAt  sql.NullTime   `db:"expires_at"`
+	LastUsedAt sql.NullTime   `db:"last_used_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
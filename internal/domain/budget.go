@@ -1,13 +1,18 @@
 package domain
 
 import (
+	"database/sql"
 	"time"
 )
 
 type Budget struct {
-	ID        string    `db:"id"`
-	UserID    string    `db:"user_id"`
-	Currency  string    `db:"currency"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID         string       `db:"id"`
+	UserID     string       `db:"user_id"`
+	Name       string       `db:"name"`
+	Currency   string       `db:"currency"`
+	ArchivedAt sql.NullTime `db:"archived_at"`
+	CreatedAt  time.Time    `db:"created_at"`
+	UpdatedAt  time.Time    `db:"updated_at"`
+	// Role is the role of the user the budget was loaded for; UserID is always the owner.
+	Role string `db:"role"`
 }
@@ -9,6 +9,6 @@ type BudgetHistory struct {
 	ID            string         `db:"id"`
 	TransactionID sql.NullString `db:"transaction_id"`
 	BudgetID      string         `db:"budget_id"`
-	Balance       float64        `db:"balance"`
+	Balance       Money          `db:"balance"`
 	CreatedAt     time.Time      `db:"created_at"`
 }
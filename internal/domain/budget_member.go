@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// BudgetMember is a user a budget is shared with, along with the user's profile so
+// members can tell each other apart.
+type BudgetMember struct {
+	BudgetID  string    `db:"budget_id"`
+	UserID    string    `db:"user_id"`
+	Role      string    `db:"role"`
+	Email     string    `db:"email"`
+	FirstName string    `db:"first_name"`
+	LastName  string    `db:"last_name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// BudgetInvitation asks whoever signs in with Email to join a budget. BudgetName and
+// InviterEmail are joined in so the invitee knows what they are accepting.
+type BudgetInvitation struct {
+	ID           string       `db:"id"`
+	BudgetID     string       `db:"budget_id"`
+	BudgetName   string       `db:"budget_name"`
+	InvitedBy    string       `db:"invited_by"`
+	InviterEmail string       `db:"inviter_email"`
+	Email        string       `db:"email"`
+	Role         string       `db:"role"`
+	Status       string       `db:"status"`
+	ExpiresAt    time.Time    `db:"expires_at"`
+	RespondedAt  sql.NullTime `db:"responded_at"`
+	CreatedAt    time.Time    `db:"created_at"`
+}
@@ -5,11 +5,32 @@ import (
 	"time"
 )
 
+// Category is a default category when UserID is null and a custom category of the user
+// otherwise. A custom category can be a subcategory of a default one or of another custom one.
+// Hidden and Position come from the overrides of the user the category was listed for, which
+// also replace Name, Icon and Color when set.
 type Category struct {
 	ID             string         `db:"id"`
 	UserID         sql.NullString `db:"user_id"`
+	ParentID       sql.NullString `db:"parent_id"`
 	Name           string         `db:"name"`
+	Icon           string         `db:"icon"`
+	Color          string         `db:"color"`
 	IsUserCategory bool           `db:"is_user_category"`
+	Hidden         bool           `db:"hidden"`
+	Position       sql.NullInt32  `db:"position"`
 	CreatedAt      time.Time      `db:"created_at"`
 	UpdatedAt      time.Time      `db:"updated_at"`
 }
+
+// CategoryOverride changes how a category is shown to one user without changing the category,
+// so that default categories, which are shared by every user, can be renamed or hidden.
+// Fields that are not set are left as they are when an override is saved.
+type CategoryOverride struct {
+	UserID     string         `db:"user_id"`
+	CategoryID string         `db:"category_id"`
+	Name       sql.NullString `db:"name"`
+	Icon       sql.NullString `db:"icon"`
+	Color      sql.NullString `db:"color"`
+	Hidden     sql.NullBool   `db:"hidden"`
+}
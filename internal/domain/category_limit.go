@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"database/sql"
+	"finly-backend/internal/domain/enums/e_limit_period"
+	"time"
+)
+
+// CategoryLimit caps what may be spent on a category of a budget within a period.
+// Month and week limits repeat every calendar month or ISO week (in UTC); a custom
+// limit covers the fixed range [StartsAt, EndsAt).
+type CategoryLimit struct {
+	ID         string       `db:"id"`
+	UserID     string       `db:"user_id"`
+	BudgetID   string       `db:"budget_id"`
+	CategoryID string       `db:"category_id"`
+	Amount     Money        `db:"amount"`
+	Period     string       `db:"period"`
+	StartsAt   sql.NullTime `db:"starts_at"`
+	EndsAt     sql.NullTime `db:"ends_at"`
+	Enforce    bool         `db:"enforce"`
+	CreatedAt  time.Time    `db:"created_at"`
+	UpdatedAt  time.Time    `db:"updated_at"`
+}
+
+// Window returns the period of the limit that applies at the given time and whether the
+// limit is in effect then. Only a custom limit can be out of effect.
+func (l *CategoryLimit) Window(at time.Time) (time.Time, time.Time, bool) {
+	at = at.UTC()
+	switch l.Period {
+	case e_limit_period.Month.String():
+		start := time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0), true
+	case e_limit_period.Week.String():
+		day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7), true
+	default:
+		start, end := l.StartsAt.Time.UTC(), l.EndsAt.Time.UTC()
+		return start, end, !at.Before(start) && at.Before(end)
+	}
+}
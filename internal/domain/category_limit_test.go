@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryLimit_Window(t *testing.T) {
+	date := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2025, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	custom := &CategoryLimit{
+		Period:   "custom",
+		StartsAt: sql.NullTime{Time: date(time.March, 10, 0), Valid: true},
+		EndsAt:   sql.NullTime{Time: date(time.March, 20, 0), Valid: true},
+	}
+
+	tests := []struct {
+		name          string
+		limit         *CategoryLimit
+		at            time.Time
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectedOK    bool
+	}{
+		{name: "Month", limit: &CategoryLimit{Period: "month"}, at: date(time.December, 31, 23), expectedStart: date(time.December, 1, 0), expectedEnd: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), expectedOK: true},
+		{name: "Week starts on Monday", limit: &CategoryLimit{Period: "week"}, at: date(time.March, 16, 12), expectedStart: date(time.March, 10, 0), expectedEnd: date(time.March, 17, 0), expectedOK: true},
+		{name: "Week on a Monday", limit: &CategoryLimit{Period: "week"}, at: date(time.March, 17, 0), expectedStart: date(time.March, 17, 0), expectedEnd: date(time.March, 24, 0), expectedOK: true},
+		{name: "Custom in effect", limit: custom, at: date(time.March, 10, 0), expectedStart: date(time.March, 10, 0), expectedEnd: date(time.March, 20, 0), expectedOK: true},
+		{name: "Custom ended", limit: custom, at: date(time.March, 20, 0), expectedStart: date(time.March, 10, 0), expectedEnd: date(time.March, 20, 0), expectedOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := tt.limit.Window(tt.at)
+			assert.Equal(t, tt.expectedStart, start)
+			assert.Equal(t, tt.expectedEnd, end)
+			assert.Equal(t, tt.expectedOK, ok)
+		})
+	}
+}
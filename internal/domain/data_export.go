@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DataExport is a job that builds an archive of everything stored about a user. The
+// archive itself is loaded only for download.
+type DataExport struct {
+	ID          string         `db:"id"`
+	UserID      string         `db:"user_id"`
+	Status      string         `db:"status"`
+	Attempts    int            `db:"attempts"`
+	Size        sql.NullInt64  `db:"size"`
+	Error       sql.NullString `db:"error"`
+	StartedAt   sql.NullTime   `db:"started_at"`
+	CompletedAt sql.NullTime   `db:"completed_at"`
+	ExpiresAt   sql.NullTime   `db:"expires_at"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
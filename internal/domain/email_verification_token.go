@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// EmailVerificationToken confirms that a user owns an email address. The address is kept
+// with the token, so a link sent before the email was changed can't verify the new one.
+type EmailVerificationToken struct {
+	ID        string       `db:"id"`
+	UserID    string       `db:"user_id"`
+	Email     string       `db:"email"`
+	TokenHash string       `db:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
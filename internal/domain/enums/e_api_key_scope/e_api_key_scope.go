@@ -0,0 +1,28 @@
+package e_api_key_scope
+
+type Enum string
+
+// Read allows every read an API key can make. Each write scope allows changes to one
+// part of the API.
+const (
+	Read              Enum = "read"
+	BudgetsWrite      Enum = "budgets:write"
+	CategoriesWrite   Enum = "categories:write"
+	TransactionsWrite Enum = "transactions:write"
+	RecurringWrite    Enum = "recurring:write"
+	LimitsWrite       Enum = "limits:write"
+	RulesWrite        Enum = "rules:write"
+)
+
+func (r *Enum) IsValid() bool {
+	switch *r {
+	case Read, BudgetsWrite, CategoriesWrite, TransactionsWrite, RecurringWrite, LimitsWrite, RulesWrite:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r Enum) String() string {
+	return string(r)
+}
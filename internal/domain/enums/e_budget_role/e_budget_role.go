@@ -0,0 +1,35 @@
+package e_budget_role
+
+type Enum string
+
+// Viewer can read a budget and its transactions, Editor can also add and change
+// transactions, and Owner can also change the budget itself and manage its members.
+const (
+	Owner  Enum = "owner"
+	Editor Enum = "editor"
+	Viewer Enum = "viewer"
+)
+
+var rank = map[Enum]int{
+	Viewer: 1,
+	Editor: 2,
+	Owner:  3,
+}
+
+func (r *Enum) IsValid() bool {
+	switch *r {
+	case Owner, Editor, Viewer:
+		return true
+	default:
+		return false
+	}
+}
+
+// Allows reports whether the role may do what requires the given role.
+func (r Enum) Allows(required Enum) bool {
+	return rank[r] > 0 && rank[r] >= rank[required]
+}
+
+func (r Enum) String() string {
+	return string(r)
+}
@@ -0,0 +1,22 @@
+package e_limit_period
+
+type Enum string
+
+const (
+	Month  Enum = "month"
+	Week   Enum = "week"
+	Custom Enum = "custom"
+)
+
+func (r *Enum) IsValid() bool {
+	switch *r {
+	case Month, Week, Custom:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r Enum) String() string {
+	return string(r)
+}
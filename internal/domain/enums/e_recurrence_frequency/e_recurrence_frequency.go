@@ -0,0 +1,23 @@
+package e_recurrence_frequency
+
+type Enum string
+
+const (
+	Daily   Enum = "daily"
+	Weekly  Enum = "weekly"
+	Monthly Enum = "monthly"
+	Cron    Enum = "cron"
+)
+
+func (r *Enum) IsValid() bool {
+	switch *r {
+	case Daily, Weekly, Monthly, Cron:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r Enum) String() string {
+	return string(r)
+}
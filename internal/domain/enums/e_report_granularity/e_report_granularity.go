@@ -0,0 +1,22 @@
+package e_report_granularity
+
+type Enum string
+
+const (
+	Day   Enum = "day"
+	Week  Enum = "week"
+	Month Enum = "month"
+)
+
+func (r *Enum) IsValid() bool {
+	switch *r {
+	case Day, Week, Month:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r Enum) String() string {
+	return string(r)
+}
@@ -3,14 +3,16 @@ package e_transaction_type
 type Enum string
 
 const (
-	Deposit    Enum = "deposit"
-	Withdrawal Enum = "withdrawal"
-	Initial    Enum = "initial"
+	Deposit     Enum = "deposit"
+	Withdrawal  Enum = "withdrawal"
+	TransferIn  Enum = "transfer_in"
+	TransferOut Enum = "transfer_out"
+	Initial     Enum = "initial"
 )
 
 func (r *Enum) IsValid() bool {
 	switch *r {
-	case Deposit, Withdrawal:
+	case Deposit, Withdrawal, TransferIn, TransferOut:
 		return true
 	case Initial:
 		return false
@@ -22,3 +24,8 @@ func (r *Enum) IsValid() bool {
 func (r Enum) String() string {
 	return string(r)
 }
+
+// IsTransfer reports whether the type is one leg of a transfer between budgets.
+func (r Enum) IsTransfer() bool {
+	return r == TransferIn || r == TransferOut
+}
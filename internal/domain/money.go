@@ -0,0 +1,257 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+const (
+	moneyScale    = 2
+	centsPerUnit  = 100
+	moneyMaxDigit = 18
+
+	// maxCents is the largest amount the DECIMAL(15, 2) ledger columns hold: 13 integer digits.
+	maxCents = 9_999_999_999_999_99
+)
+
+var (
+	ErrInvalidMoney   error = moneyError("invalid money amount")
+	ErrMoneyPrecision error = moneyError("money amount has more than two decimal places")
+	ErrMoneyOverflow  error = moneyError("money amount is out of range")
+
+	// plainDecimal is the only notation amounts are accepted in. big.Rat alone would also
+	// take fractions, exponents and other bases.
+	plainDecimal = regexp.MustCompile(`^-?\d+(\.\d{1,2})?$`)
+	// tooPrecise tells amounts with more decimal places apart from malformed ones.
+	tooPrecise = regexp.MustCompile(`^-?\d+\.\d{3,}$`)
+)
+
+// moneyError is the type of the errors amounts are rejected with. InvalidValue tells
+// request binding the client sent the amount, so it is answered with 400.
+type moneyError string
+
+func (e moneyError) Error() string { return string(e) }
+
+func (e moneyError) InvalidValue() bool { return true }
+
+// Money is an exact monetary amount kept in minor units (cents).
+// It mirrors the DECIMAL(15, 2) columns of the ledger, so balances never drift
+// the way float64 arithmetic does.
+type Money struct {
+	cents int64
+}
+
+func NewMoneyFromCents(cents int64) Money {
+	return Money{cents: cents}
+}
+
+// ParseMoney parses a decimal string such as "12", "-3.5" or "1049.99".
+// Values with more than two decimal places are rejected rather than rounded, and so are
+// values the ledger columns can't hold.
+func ParseMoney(s string) (Money, error) {
+	m, err := parseMoney(s)
+	if err != nil {
+		return Money{}, err
+	}
+	if !m.inRange() {
+		return Money{}, ErrMoneyOverflow
+	}
+	return m, nil
+}
+
+// parseMoney is ParseMoney without the column range, for sums read from the database.
+func parseMoney(s string) (Money, error) {
+	if len(s) > moneyMaxDigit+3 {
+		return Money{}, ErrInvalidMoney
+	}
+	if !plainDecimal.MatchString(s) {
+		if tooPrecise.MatchString(s) {
+			return Money{}, ErrMoneyPrecision
+		}
+		return Money{}, ErrInvalidMoney
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Money{}, ErrInvalidMoney
+	}
+
+	r.Mul(r, big.NewRat(centsPerUnit, 1))
+	if !r.IsInt() {
+		return Money{}, ErrMoneyPrecision
+	}
+
+	cents := r.Num()
+	if !cents.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return Money{cents: cents.Int64()}, nil
+}
+
+// MustParseMoney is like ParseMoney but panics on invalid input.
+// It is intended for constants and tests.
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(fmt.Sprintf("domain: MustParseMoney(%q): %v", s, err))
+	}
+	return m
+}
+
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+// Add returns m + other. It returns ErrMoneyOverflow if the sum is beyond what the ledger
+// columns hold.
+func (m Money) Add(other Money) (Money, error) {
+	if (other.cents > 0 && m.cents > math.MaxInt64-other.cents) || (other.cents < 0 && m.cents < math.MinInt64-other.cents) {
+		return Money{}, ErrMoneyOverflow
+	}
+	sum := Money{cents: m.cents + other.cents}
+	if !sum.inRange() {
+		return Money{}, ErrMoneyOverflow
+	}
+	return sum, nil
+}
+
+// Sub returns m - other. Like Add, it returns ErrMoneyOverflow if the difference doesn't fit.
+func (m Money) Sub(other Money) (Money, error) {
+	if other.cents == math.MinInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+	return m.Add(other.Neg())
+}
+
+func (m Money) inRange() bool {
+	return m.cents >= -maxCents && m.cents <= maxCents
+}
+
+func (m Money) Neg() Money {
+	return Money{cents: -m.cents}
+}
+
+func (m Money) Abs() Money {
+	if m.cents < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// Cmp returns -1, 0 or +1 depending on whether m is less than, equal to or greater than other.
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.cents < other.cents:
+		return -1
+	case m.cents > other.cents:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m Money) Equal(other Money) bool {
+	return m.cents == other.cents
+}
+
+func (m Money) LessThan(other Money) bool {
+	return m.cents < other.cents
+}
+
+func (m Money) IsZero() bool {
+	return m.cents == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.cents < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.cents > 0
+}
+
+// String formats the amount with exactly two decimal places, e.g. "-12.50".
+func (m Money) String() string {
+	cents := m.cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+	}
+
+	abs := cents
+	if abs < 0 {
+		abs = -abs
+	}
+
+	return fmt.Sprintf("%s%d.%0*d", sign, abs/centsPerUnit, moneyScale, abs%centsPerUnit)
+}
+
+// MarshalJSON encodes the amount as a JSON number with two decimal places.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts both JSON numbers and quoted decimal strings.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		s, err := strconv.Unquote(string(data))
+		if err != nil {
+			return ErrInvalidMoney
+		}
+		data = []byte(s)
+	}
+
+	parsed, err := ParseMoney(string(data))
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL/NUMERIC columns.
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*m = Money{}
+		return nil
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case int64:
+		*m = Money{cents: v * centsPerUnit}
+		return nil
+	case float64:
+		return m.scanString(strconv.FormatFloat(v, 'f', moneyScale, 64))
+	default:
+		return fmt.Errorf("cannot scan %T into domain.Money", src)
+	}
+}
+
+// scanString takes amounts beyond the column range too, as sums of columns may be.
+func (m *Money) scanString(s string) error {
+	parsed, err := parseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, sending the amount as an exact decimal string.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    int64
+		expectedErr error
+	}{
+		{name: "Integer", input: "12", expected: 1200},
+		{name: "One decimal", input: "-3.5", expected: -350},
+		{name: "Two decimals", input: "1049.99", expected: 104999},
+		{name: "Too precise", input: "0.001", expectedErr: ErrMoneyPrecision},
+		{name: "Trailing zeros past the cents", input: "10.500", expectedErr: ErrMoneyPrecision},
+		{name: "Exponent", input: "1e3", expectedErr: ErrInvalidMoney},
+		{name: "Hexadecimal", input: "0x10", expectedErr: ErrInvalidMoney},
+		{name: "Missing decimals", input: "5.", expectedErr: ErrInvalidMoney},
+		{name: "Fraction", input: "1/3", expectedErr: ErrInvalidMoney},
+		{name: "Garbage", input: "abc", expectedErr: ErrInvalidMoney},
+		{name: "Empty", input: "", expectedErr: ErrInvalidMoney},
+		{name: "Largest column value", input: "9999999999999.99", expected: 999999999999999},
+		{name: "Beyond the column", input: "10000000000000", expectedErr: ErrMoneyOverflow},
+		{name: "Beyond the column negative", input: "-90000000000000000", expectedErr: ErrMoneyOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseMoney(tt.input)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, m.Cents())
+		})
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	var err error
+	balance := MustParseMoney("0.00")
+	for i := 0; i < 1000; i++ {
+		balance, err = balance.Add(MustParseMoney("0.10"))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, "100.00", balance.String())
+
+	balance, err = balance.Sub(MustParseMoney("100.01"))
+	assert.NoError(t, err)
+	assert.True(t, balance.IsNegative())
+	assert.Equal(t, "-0.01", balance.String())
+	assert.Equal(t, "0.01", balance.Neg().String())
+	assert.Equal(t, -1, balance.Cmp(Money{}))
+	assert.True(t, balance.LessThan(Money{}))
+}
+
+func TestMoney_Overflow(t *testing.T) {
+	largest := MustParseMoney("9999999999999.99")
+	smallest := largest.Neg()
+	cent := NewMoneyFromCents(1)
+
+	for name, op := range map[string]func() (Money, error){
+		"Add above the column":    func() (Money, error) { return largest.Add(cent) },
+		"Add below the column":    func() (Money, error) { return smallest.Add(cent.Neg()) },
+		"Sub below the column":    func() (Money, error) { return smallest.Sub(cent) },
+		"Sub above the column":    func() (Money, error) { return largest.Sub(cent.Neg()) },
+		"Add past int64":          func() (Money, error) { return NewMoneyFromCents(math.MaxInt64).Add(cent) },
+		"Sub of the smallest int": func() (Money, error) { return largest.Sub(NewMoneyFromCents(math.MinInt64)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := op()
+			assert.ErrorIs(t, err, ErrMoneyOverflow)
+		})
+	}
+
+	below, err := largest.Sub(cent)
+	assert.NoError(t, err)
+	back, err := below.Add(cent)
+	assert.NoError(t, err)
+	assert.Equal(t, largest, back)
+}
+
+func TestMoney_JSON(t *testing.T) {
+	type payload struct {
+		Amount Money `json:"amount"`
+	}
+
+	data, err := json.Marshal(payload{Amount: MustParseMoney("19.9")})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount":19.90}`, string(data))
+
+	var decoded payload
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":0.1}`), &decoded))
+	assert.Equal(t, int64(10), decoded.Amount.Cents())
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":"250.25"}`), &decoded))
+	assert.Equal(t, int64(25025), decoded.Amount.Cents())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":1.005}`), &decoded))
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount":90000000000000000}`), &decoded), ErrMoneyOverflow)
+}
+
+func TestMoney_Scan(t *testing.T) {
+	var m Money
+
+	assert.NoError(t, m.Scan([]byte("1234.56")))
+	assert.Equal(t, int64(123456), m.Cents())
+
+	assert.NoError(t, m.Scan(float64(0.3)))
+	assert.Equal(t, int64(30), m.Cents())
+
+	assert.NoError(t, m.Scan(int64(7)))
+	assert.Equal(t, int64(700), m.Cents())
+
+	// Sums of the columns may be beyond the range of a single column.
+	assert.NoError(t, m.Scan("20000000000000.00"))
+	assert.Equal(t, int64(2000000000000000), m.Cents())
+
+	assert.NoError(t, m.Scan(nil))
+	assert.True(t, m.IsZero())
+
+	assert.Error(t, m.Scan(true))
+
+	v, err := MustParseMoney("-5.1").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "-5.10", v)
+}
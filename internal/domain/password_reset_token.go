@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PasswordResetToken lets a user who forgot their password set a new one. Only the hash
+// of the token sent by mail is stored.
+type PasswordResetToken struct {
+	ID        string       `db:"id"`
+	UserID    string       `db:"user_id"`
+	TokenHash string       `db:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
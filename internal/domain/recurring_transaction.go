@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+type RecurringTransaction struct {
+	ID              string         `db:"id"`
+	UserID          string         `db:"user_id"`
+	BudgetID        string         `db:"budget_id"`
+	CategoryID      string         `db:"category_id"`
+	Amount          Money          `db:"amount"`
+	TransactionType string         `db:"transaction_type"`
+	Note            string         `db:"note"`
+	Frequency       string         `db:"frequency"`
+	IntervalCount   int            `db:"interval_count"`
+	CronExpr        sql.NullString `db:"cron_expr"`
+	StartAt         time.Time      `db:"start_at"`
+	EndAt           sql.NullTime   `db:"end_at"`
+	NextRunAt       sql.NullTime   `db:"next_run_at"`
+	LastRunAt       sql.NullTime   `db:"last_run_at"`
+	IsActive        bool           `db:"is_active"`
+	CreatedAt       time.Time      `db:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at"`
+}
+
+// RecurringTransactionRun records a single occurrence of a recurring transaction.
+// The (recurring_transaction_id, occurrence_at) pair is unique, which is what makes the scheduler idempotent.
+type RecurringTransactionRun struct {
+	ID                     string         `db:"id"`
+	RecurringTransactionID string         `db:"recurring_transaction_id"`
+	OccurrenceAt           time.Time      `db:"occurrence_at"`
+	TransactionID          sql.NullString `db:"transaction_id"`
+	Status                 string         `db:"status"`
+	Error                  sql.NullString `db:"error"`
+	CreatedAt              time.Time      `db:"created_at"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RefreshToken is one link of a token family. Logging in starts a family; every refresh
+// marks the presented token as rotated and adds its successor to the same family.
+type RefreshToken struct {
+	ID        string       `db:"id"`
+	UserID    string       `db:"user_id"`
+	FamilyID  string       `db:"family_id"`
+	TokenHash string       `db:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	RotatedAt sql.NullTime `db:"rotated_at"`
+	RevokedAt sql.NullTime `db:"revoked_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
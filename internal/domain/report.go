@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// CashFlow sums a budget's transactions by type. Transfers are kept apart from income
+// and expense, as they only move money between the user's own budgets.
+type CashFlow struct {
+	Income       Money `db:"income"`
+	Expense      Money `db:"expense"`
+	TransfersIn  Money `db:"transfers_in"`
+	TransfersOut Money `db:"transfers_out"`
+	Count        int   `db:"count"`
+}
+
+// Net is the change of the balance caused by the summed transactions.
+func (c CashFlow) Net() (Money, error) {
+	net, err := c.Income.Sub(c.Expense)
+	if err != nil {
+		return Money{}, err
+	}
+	if net, err = net.Add(c.TransfersIn); err != nil {
+		return Money{}, err
+	}
+	return net.Sub(c.TransfersOut)
+}
+
+type CategoryTotal struct {
+	CategoryID   string `db:"category_id"`
+	CategoryName string `db:"category_name"`
+	Income       Money  `db:"income"`
+	Expense      Money  `db:"expense"`
+	Count        int    `db:"count"`
+}
+
+// PeriodTotal is the cash flow of one day, week or month, starting at Period.
+type PeriodTotal struct {
+	Period time.Time `db:"period"`
+	CashFlow
+}
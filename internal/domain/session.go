@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Session is one signed-in device. Its ID is the family ID of its refresh tokens and
+// the sid claim of its access tokens.
+type Session struct {
+	ID         string       `db:"id"`
+	UserID     string       `db:"user_id"`
+	UserAgent  string       `db:"user_agent"`
+	IP         string       `db:"ip"`
+	CreatedAt  time.Time    `db:"created_at"`
+	LastSeenAt time.Time    `db:"last_seen_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at"`
+}
@@ -1,14 +1,48 @@
 package domain
 
-import "time"
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"slices"
+	"time"
+)
 
+// TransferCategoryID is the seeded default category used for transfers between budgets.
+const TransferCategoryID = "0b7c6a1e-4f3d-4e2a-9c8b-5d6e7f8a9b0c"
+
+// Transaction belongs to the owner of its budget, UserID. CreatedBy is the member who
+// added it, which is only unset once that member deleted their account.
 type Transaction struct {
-	ID              string    `db:"id"`
-	UserID          string    `db:"user_id"`
-	BudgetID        string    `db:"budget_id"`
-	CategoryID      string    `db:"category_id"`
-	Amount          float64   `db:"amount"`
-	TransactionType string    `db:"transaction_type"`
-	Note            string    `db:"note"`
-	CreatedAt       time.Time `db:"created_at"`
+	ID              string         `db:"id"`
+	UserID          string         `db:"user_id"`
+	CreatedBy       sql.NullString `db:"created_by"`
+	BudgetID        string         `db:"budget_id"`
+	CategoryID      string         `db:"category_id"`
+	Amount          Money          `db:"amount"`
+	TransactionType string         `db:"transaction_type"`
+	Note            string         `db:"note"`
+	TransferID      sql.NullString `db:"transfer_id"`
+	ExternalID      sql.NullString `db:"external_id"`
+	Tags            pq.StringArray `db:"tags"`
+	CreatedAt       time.Time      `db:"created_at"`
+}
+
+// AddTags adds the tags the transaction does not have yet and reports whether any was added.
+func (t *Transaction) AddTags(tags ...string) bool {
+	added := false
+	for _, tag := range tags {
+		if !slices.Contains(t.Tags, tag) {
+			t.Tags = append(t.Tags, tag)
+			added = true
+		}
+	}
+	return added
+}
+
+// TransactionExport is a transaction as it is exported, with the name of its category
+// and the budget balance right after it. Balance is nil when no history entry exists.
+type TransactionExport struct {
+	Transaction
+	CategoryName string `db:"category_name"`
+	Balance      *Money `db:"balance"`
 }
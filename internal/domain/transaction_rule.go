@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TransactionRule categorizes or tags the transactions that meet every condition it sets.
+// A rule without conditions matches every transaction. The rules of a user run in
+// ascending Priority, so a lower value wins when two rules set a category.
+type TransactionRule struct {
+	ID              string         `db:"id"`
+	UserID          string         `db:"user_id"`
+	Name            string         `db:"name"`
+	Priority        int            `db:"priority"`
+	NoteContains    sql.NullString `db:"note_contains"`
+	NotePattern     sql.NullString `db:"note_pattern"`
+	MinAmount       *Money         `db:"min_amount"`
+	MaxAmount       *Money         `db:"max_amount"`
+	TransactionType sql.NullString `db:"transaction_type"`
+	BudgetID        sql.NullString `db:"budget_id"`
+	CategoryID      sql.NullString `db:"category_id"`
+	Tag             sql.NullString `db:"tag"`
+	CreatedAt       time.Time      `db:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at"`
+}
+
+// CompileNotePattern compiles the note pattern of a rule. Patterns ignore case, like
+// NoteContains does.
+func CompileNotePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// RuleSet runs rules over transactions in the order they were given.
+type RuleSet struct {
+	rules      []*TransactionRule
+	patterns   []*regexp.Regexp
+	categories map[string]bool
+}
+
+// NewRuleSet compiles the note patterns of the rules once for all the transactions they
+// run over. A rule whose pattern does not compile never matches.
+func NewRuleSet(rules []*TransactionRule) *RuleSet {
+	set := &RuleSet{rules: rules, patterns: make([]*regexp.Regexp, len(rules))}
+	for i, rule := range rules {
+		if rule.NotePattern.Valid {
+			set.patterns[i], _ = CompileNotePattern(rule.NotePattern.String)
+		}
+	}
+	return set
+}
+
+// OnlyCategories makes the set ignore the categories rules set outside of categoryIDs, such
+// as custom categories of another user. Those rules still tag the transactions they match.
+func (s *RuleSet) OnlyCategories(categoryIDs map[string]bool) *RuleSet {
+	s.categories = categoryIDs
+	return s
+}
+
+// Match returns the category set by the first matching rule that sets one, or an empty
+// string when none does, and the tags of every matching rule. Transfers never match.
+func (s *RuleSet) Match(t *Transaction) (string, []string) {
+	var (
+		categoryID string
+		tags       []string
+	)
+
+	if t.TransferID.Valid {
+		return "", nil
+	}
+
+	for i, rule := range s.rules {
+		if !s.matches(i, t) {
+			continue
+		}
+		if categoryID == "" && rule.CategoryID.Valid && (s.categories == nil || s.categories[rule.CategoryID.String]) {
+			categoryID = rule.CategoryID.String
+		}
+		if rule.Tag.Valid {
+			tags = append(tags, rule.Tag.String)
+		}
+	}
+
+	return categoryID, tags
+}
+
+func (s *RuleSet) matches(i int, t *Transaction) bool {
+	rule := s.rules[i]
+
+	if rule.NoteContains.Valid && !strings.Contains(strings.ToLower(t.Note), strings.ToLower(rule.NoteContains.String)) {
+		return false
+	}
+	if rule.NotePattern.Valid && (s.patterns[i] == nil || !s.patterns[i].MatchString(t.Note)) {
+		return false
+	}
+	if rule.MinAmount != nil && t.Amount.LessThan(*rule.MinAmount) {
+		return false
+	}
+	if rule.MaxAmount != nil && rule.MaxAmount.LessThan(t.Amount) {
+		return false
+	}
+	if rule.TransactionType.Valid && rule.TransactionType.String != t.TransactionType {
+		return false
+	}
+	if rule.BudgetID.Valid && rule.BudgetID.String != t.BudgetID {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleSet_Match(t *testing.T) {
+	valid := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+	money := func(s string) *Money {
+		m := MustParseMoney(s)
+		return &m
+	}
+
+	rules := NewRuleSet([]*TransactionRule{
+		{Name: "Netflix", NotePattern: valid(`netflix\.com`), MinAmount: money("9.99"), MaxAmount: money("9.99"), CategoryID: valid("entertainment")},
+		{Name: "Uber", NoteContains: valid("UBER"), CategoryID: valid("transport"), Tag: valid("rides")},
+		{Name: "Salary", TransactionType: valid("deposit"), BudgetID: valid("checking"), CategoryID: valid("salary")},
+		{Name: "Broken pattern", NotePattern: valid("("), Tag: valid("never")},
+		{Name: "Everything", Tag: valid("reviewed")},
+	})
+
+	tests := []struct {
+		name             string
+		transaction      *Transaction
+		expectedCategory string
+		expectedTags     []string
+	}{
+		{
+			name:             "Pattern and exact amount",
+			transaction:      &Transaction{Note: "NETFLIX.COM 866-579", Amount: MustParseMoney("9.99"), TransactionType: "withdrawal"},
+			expectedCategory: "entertainment",
+			expectedTags:     []string{"reviewed"},
+		},
+		{
+			name:         "Amount out of range",
+			transaction:  &Transaction{Note: "netflix.com", Amount: MustParseMoney("15.49"), TransactionType: "withdrawal"},
+			expectedTags: []string{"reviewed"},
+		},
+		{
+			name:             "Note contains ignores case",
+			transaction:      &Transaction{Note: "Uber *trip", Amount: MustParseMoney("12.00"), TransactionType: "withdrawal"},
+			expectedCategory: "transport",
+			expectedTags:     []string{"rides", "reviewed"},
+		},
+		{
+			name:             "Type and budget",
+			transaction:      &Transaction{BudgetID: "checking", Amount: MustParseMoney("2500.00"), TransactionType: "deposit"},
+			expectedCategory: "salary",
+			expectedTags:     []string{"reviewed"},
+		},
+		{
+			name:         "Other budget",
+			transaction:  &Transaction{BudgetID: "savings", Amount: MustParseMoney("2500.00"), TransactionType: "deposit"},
+			expectedTags: []string{"reviewed"},
+		},
+		{
+			name:        "Transfers never match",
+			transaction: &Transaction{Note: "uber", TransferID: valid("transfer1"), TransactionType: "transfer_out"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categoryID, tags := rules.Match(tt.transaction)
+			assert.Equal(t, tt.expectedCategory, categoryID)
+			assert.Equal(t, tt.expectedTags, tags)
+		})
+	}
+
+	t.Run("Categories outside the allowed ones are skipped", func(t *testing.T) {
+		restricted := NewRuleSet([]*TransactionRule{
+			{Name: "Uber", NoteContains: valid("UBER"), CategoryID: valid("rides"), Tag: valid("uber")},
+			{Name: "Fallback", CategoryID: valid("transport")},
+		}).OnlyCategories(map[string]bool{"transport": true})
+
+		categoryID, tags := restricted.Match(&Transaction{Note: "Uber *trip", Amount: MustParseMoney("12.00"), TransactionType: "withdrawal"})
+		assert.Equal(t, "transport", categoryID)
+		assert.Equal(t, []string{"uber"}, tags)
+	})
+}
+
+func TestTransaction_AddTags(t *testing.T) {
+	transaction := &Transaction{Tags: []string{"rides"}}
+
+	assert.False(t, transaction.AddTags("rides"))
+	assert.True(t, transaction.AddTags("rides", "reviewed"))
+	assert.Equal(t, []string{"rides", "reviewed"}, []string(transaction.Tags))
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TOTPCredential is the authenticator app secret of a user. Two-factor authentication is
+// on once the credential is confirmed with a code; until then it is only enrolled.
+type TOTPCredential struct {
+	UserID       string       `db:"user_id"`
+	Secret       string       `db:"secret"`
+	ConfirmedAt  sql.NullTime `db:"confirmed_at"`
+	LastUsedStep int64        `db:"last_used_step"`
+	CreatedAt    time.Time    `db:"created_at"`
+}
+
+// TwoFactorChallenge is a sign-in that passed the password check and waits for a second
+// factor. It lives in Redis under the hash of its token.
+type TwoFactorChallenge struct {
+	UserID   string `redis:"user_id"`
+	Email    string `redis:"email"`
+	Attempts int    `redis:"attempts"`
+}
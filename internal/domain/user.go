@@ -1,15 +1,17 @@
 package domain
 
 import (
+	"database/sql"
 	"time"
 )
 
 type User struct {
-	ID           string    `db:"id"`
-	Email        string    `db:"email"`
-	PasswordHash string    `db:"password_hash"`
-	FirstName    string    `db:"first_name"`
-	LastName     string    `db:"last_name"`
-	CreatedAt    time.Time `db:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at"`
+	ID              string       `db:"id"`
+	Email           string       `db:"email"`
+	PasswordHash    string       `db:"password_hash"`
+	FirstName       string       `db:"first_name"`
+	LastName        string       `db:"last_name"`
+	EmailVerifiedAt sql.NullTime `db:"email_verified_at"`
+	CreatedAt       time.Time    `db:"created_at"`
+	UpdatedAt       time.Time    `db:"updated_at"`
 }
@@ -0,0 +1,147 @@
+package api_key
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/db"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"time"
+)
+
+type APIKey interface {
+	Create(ctx context.Context, key *domain.APIKey) (string, error)
+	GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	ListByUserID(ctx context.Context, userID string) ([]*domain.APIKey, error)
+	Delete(ctx context.Context, keyID, userID string) (bool, error)
+	Touch(ctx context.Context, keyID string) error
+	CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error)
+}
+
+const (
+	APIKeyTable = "api_keys"
+
+	TTL_GetAPIKeyCache = 5 * time.Minute
+
+	// touchInterval limits how often last_used_at is written for a busy key.
+	touchInterval = time.Minute
+
+	cacheKeyAPIKeyByHash = "api_key:%s"
+	cacheKeyAPIKeyUsed   = "api_key:used:%s"
+)
+
+type APIKeyRepository struct {
+	postgres *sqlx.DB
+	redis    *redis.Client
+}
+
+func NewAPIKeyRepository(postgres *sqlx.DB, redis *redis.Client) *APIKeyRepository {
+	return &APIKeyRepository{
+		postgres: postgres,
+		redis:    redis,
+	}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) (string, error) {
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`, APIKeyTable)
+
+	var id string
+	if err := r.postgres.QueryRowContext(ctx, query, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.ExpiresAt).Scan(&id); err != nil {
+		zap.L().Sugar().Errorf("Failed to create API key, userID: %s, error: %v", key.UserID, err)
+		return "", err
+	}
+
+	zap.L().Sugar().Infof("API key created, keyID: %s, userID: %s", id, key.UserID)
+	return id, nil
+}
+
+// GetByHash is read on every request made with an API key, so it is cached; deleting
+// the key invalidates the entry.
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	cacheKey := fmt.Sprintf(cacheKeyAPIKeyByHash, keyHash)
+
+	fetch := func() (*domain.APIKey, error) {
+		var key domain.APIKey
+		query := fmt.Sprintf("SELECT * FROM %s WHERE key_hash = $1", APIKeyTable)
+		if err := r.postgres.GetContext(ctx, &key, query, keyHash); err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				zap.L().Sugar().Errorf("Failed to fetch API key from DB, error: %v", err)
+			}
+			return nil, err
+		}
+		return &key, nil
+	}
+
+	return db.WithCache(ctx, r.redis, cacheKey, TTL_GetAPIKeyCache, fetch)
+}
+
+func (r *APIKeyRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1 ORDER BY created_at DESC, id", APIKeyTable)
+	if err := r.postgres.SelectContext(ctx, &keys, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to list API keys, userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Delete removes a key of the user. It reports false when there was no such key.
+func (r *APIKeyRepository) Delete(ctx context.Context, keyID, userID string) (bool, error) {
+	var keyHashes []string
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2 RETURNING key_hash", APIKeyTable)
+	if err := r.postgres.SelectContext(ctx, &keyHashes, query, keyID, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to delete API key, keyID: %s, userID: %s, error: %v", keyID, userID, err)
+		return false, err
+	}
+	if len(keyHashes) == 0 {
+		return false, nil
+	}
+
+	if err := r.redis.Del(ctx, fmt.Sprintf(cacheKeyAPIKeyByHash, keyHashes[0])).Err(); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after delete, keyID: %s, error: %v", keyID, err)
+	}
+
+	zap.L().Sugar().Infof("API key deleted, keyID: %s, userID: %s", keyID, userID)
+	return true, nil
+}
+
+// Touch records that the key was just used. Writes are throttled to one per
+// touchInterval, so last_used_at is only that precise.
+func (r *APIKeyRepository) Touch(ctx context.Context, keyID string) error {
+	first, err := r.redis.SetNX(ctx, fmt.Sprintf(cacheKeyAPIKeyUsed, keyID), 1, touchInterval).Result()
+	if err != nil {
+		zap.L().Sugar().Warnf("Failed to throttle API key touch, keyID: %s, error: %v", keyID, err)
+		return err
+	}
+	if !first {
+		return nil
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET last_used_at = CURRENT_TIMESTAMP WHERE id = $1", APIKeyTable)
+	if _, err = r.postgres.ExecContext(ctx, query, keyID); err != nil {
+		zap.L().Sugar().Errorf("Failed to touch API key, keyID: %s, error: %v", keyID, err)
+		return err
+	}
+	return nil
+}
+
+// CacheKeysByUserTX lists the cache keys of the user's API keys.
+func (r *APIKeyRepository) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	var keyHashes []string
+	query := fmt.Sprintf("SELECT key_hash FROM %s WHERE user_id = $1", APIKeyTable)
+	if err := tx.SelectContext(ctx, &keyHashes, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to list API key hashes, userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(keyHashes))
+	for _, hash := range keyHashes {
+		keys = append(keys, fmt.Sprintf(cacheKeyAPIKeyByHash, hash))
+	}
+	return keys, nil
+}
@@ -0,0 +1,191 @@
+package api_key
+
+import (
+	"context"
+	"database/sql"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/testutil"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "name", "prefix", "key_hash", "scopes", "expires_at", "last_used_at", "created_at"}
+
+	t.Run("Create", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewAPIKeyRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			key := &domain.APIKey{UserID: "user1", Name: "Import script", Prefix: "fk_abcdef", KeyHash: "hash1", Scopes: pq.StringArray{"read"}}
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", APIKeyTable)).
+				WithArgs("user1", "Import script", "fk_abcdef", "hash1", key.Scopes, key.ExpiresAt).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("key1"))
+
+			id, err := repo.Create(ctx, key)
+			assert.NoError(t, err)
+			assert.Equal(t, "key1", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("GetByHash", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewAPIKeyRepository(sqlxDB, redisClient)
+
+		t.Run("CacheMiss", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE key_hash = \\$1", APIKeyTable)).
+				WithArgs("hash1").
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("key1", "user1", "Import script", "fk_abcdef", "hash1", "{read,transactions:write}", nil, nil, now))
+
+			key, err := repo.GetByHash(ctx, "hash1")
+			assert.NoError(t, err)
+			assert.Equal(t, "user1", key.UserID)
+			assert.Equal(t, pq.StringArray{"read", "transactions:write"}, key.Scopes)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(cacheKeyAPIKeyByHash, "hash1")).Result()
+			assert.Equal(t, int64(1), exists)
+		})
+
+		t.Run("CacheHit", func(t *testing.T) {
+			key, err := repo.GetByHash(ctx, "hash1")
+			assert.NoError(t, err)
+			assert.Equal(t, pq.StringArray{"read", "transactions:write"}, key.Scopes)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE key_hash = \\$1", APIKeyTable)).
+				WithArgs("hash2").
+				WillReturnError(sql.ErrNoRows)
+
+			key, err := repo.GetByHash(ctx, "hash2")
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			assert.Nil(t, key)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ListByUserID", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewAPIKeyRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE user_id = \\$1", APIKeyTable)).
+				WithArgs("user1").
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("key1", "user1", "Import script", "fk_abcdef", "hash1", "{read}", nil, now, now))
+
+			keys, err := repo.ListByUserID(ctx, "user1")
+			assert.NoError(t, err)
+			if assert.Len(t, keys, 1) {
+				assert.True(t, keys[0].LastUsedAt.Valid)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewAPIKeyRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			cacheKey := fmt.Sprintf(cacheKeyAPIKeyByHash, "hash1")
+			mr.Set(cacheKey, "cached")
+			mock.ExpectQuery(fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND user_id = \\$2 RETURNING key_hash", APIKeyTable)).
+				WithArgs("key1", "user1").
+				WillReturnRows(sqlmock.NewRows([]string{"key_hash"}).AddRow("hash1"))
+
+			deleted, err := repo.Delete(ctx, "key1", "user1")
+			assert.NoError(t, err)
+			assert.True(t, deleted)
+			assert.False(t, mr.Exists(cacheKey))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("DELETE FROM %s", APIKeyTable)).
+				WithArgs("key1", "user2").
+				WillReturnRows(sqlmock.NewRows([]string{"key_hash"}))
+
+			deleted, err := repo.Delete(ctx, "key1", "user2")
+			assert.NoError(t, err)
+			assert.False(t, deleted)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("Touch", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewAPIKeyRepository(sqlxDB, redisClient)
+
+		t.Run("Throttled", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET last_used_at", APIKeyTable)).
+				WithArgs("key1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			assert.NoError(t, repo.Touch(ctx, "key1"))
+			assert.NoError(t, repo.Touch(ctx, "key1"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			mr.FastForward(touchInterval)
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET last_used_at", APIKeyTable)).
+				WithArgs("key1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			assert.NoError(t, repo.Touch(ctx, "key1"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("CacheKeysByUserTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewAPIKeyRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT key_hash FROM %s WHERE user_id", APIKeyTable)).
+				WithArgs("user1").
+				WillReturnRows(sqlmock.NewRows([]string{"key_hash"}).AddRow("hash1"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.CacheKeysByUserTX(ctx, tx, "user1")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{fmt.Sprintf(cacheKeyAPIKeyByHash, "hash1")}, keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+}
@@ -14,6 +14,7 @@ import (
 	domain "finly-backend/internal/domain"
 	reflect "reflect"
 
+	sqlx "github.com/jmoiron/sqlx"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,18 +42,33 @@ func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
 	return m.recorder
 }
 
-// AddTokenToBlacklist mocks base method.
-func (m *MockAuth) AddTokenToBlacklist(ctx context.Context, token string, ttlSeconds float64) error {
+// ChangeEmailTX mocks base method.
+func (m *MockAuth) ChangeEmailTX(ctx context.Context, tx *sqlx.Tx, userID, email string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddTokenToBlacklist", ctx, token, ttlSeconds)
+	ret := m.ctrl.Call(m, "ChangeEmailTX", ctx, tx, userID, email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeEmailTX indicates an expected call of ChangeEmailTX.
+func (mr *MockAuthMockRecorder) ChangeEmailTX(ctx, tx, userID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeEmailTX", reflect.TypeOf((*MockAuth)(nil).ChangeEmailTX), ctx, tx, userID, email)
+}
+
+// DeleteTX mocks base method.
+func (m *MockAuth) DeleteTX(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTX", ctx, tx, userID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AddTokenToBlacklist indicates an expected call of AddTokenToBlacklist.
-func (mr *MockAuthMockRecorder) AddTokenToBlacklist(ctx, token, ttlSeconds any) *gomock.Call {
+// DeleteTX indicates an expected call of DeleteTX.
+func (mr *MockAuthMockRecorder) DeleteTX(ctx, tx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTokenToBlacklist", reflect.TypeOf((*MockAuth)(nil).AddTokenToBlacklist), ctx, token, ttlSeconds)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTX", reflect.TypeOf((*MockAuth)(nil).DeleteTX), ctx, tx, userID)
 }
 
 // GetUserByEmail mocks base method.
@@ -85,19 +101,47 @@ func (mr *MockAuthMockRecorder) GetUserByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockAuth)(nil).GetUserByID), ctx, id)
 }
 
-// IsTokenBlacklisted mocks base method.
-func (m *MockAuth) IsTokenBlacklisted(ctx context.Context, token string) (bool, error) {
+// InvalidateCache mocks base method.
+func (m *MockAuth) InvalidateCache(ctx context.Context, userID, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateCache", ctx, userID, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateCache indicates an expected call of InvalidateCache.
+func (mr *MockAuthMockRecorder) InvalidateCache(ctx, userID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateCache", reflect.TypeOf((*MockAuth)(nil).InvalidateCache), ctx, userID, email)
+}
+
+// MarkEmailVerifiedTX mocks base method.
+func (m *MockAuth) MarkEmailVerifiedTX(ctx context.Context, tx *sqlx.Tx, userID, email string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsTokenBlacklisted", ctx, token)
+	ret := m.ctrl.Call(m, "MarkEmailVerifiedTX", ctx, tx, userID, email)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsTokenBlacklisted indicates an expected call of IsTokenBlacklisted.
-func (mr *MockAuthMockRecorder) IsTokenBlacklisted(ctx, token any) *gomock.Call {
+// MarkEmailVerifiedTX indicates an expected call of MarkEmailVerifiedTX.
+func (mr *MockAuthMockRecorder) MarkEmailVerifiedTX(ctx, tx, userID, email any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenBlacklisted", reflect.TypeOf((*MockAuth)(nil).IsTokenBlacklisted), ctx, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerifiedTX", reflect.TypeOf((*MockAuth)(nil).MarkEmailVerifiedTX), ctx, tx, userID, email)
+}
+
+// PurgeCache mocks base method.
+func (m *MockAuth) PurgeCache(ctx context.Context, keys []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeCache", ctx, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeCache indicates an expected call of PurgeCache.
+func (mr *MockAuthMockRecorder) PurgeCache(ctx, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeCache", reflect.TypeOf((*MockAuth)(nil).PurgeCache), ctx, keys)
 }
 
 // Register mocks base method.
@@ -115,16 +159,30 @@ func (mr *MockAuthMockRecorder) Register(ctx, email, passwordHash, firstName, la
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuth)(nil).Register), ctx, email, passwordHash, firstName, lastName)
 }
 
-// RemoveToken mocks base method.
-func (m *MockAuth) RemoveToken(ctx context.Context, token string) error {
+// UpdatePasswordTX mocks base method.
+func (m *MockAuth) UpdatePasswordTX(ctx context.Context, tx *sqlx.Tx, userID, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordTX", ctx, tx, userID, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordTX indicates an expected call of UpdatePasswordTX.
+func (mr *MockAuthMockRecorder) UpdatePasswordTX(ctx, tx, userID, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordTX", reflect.TypeOf((*MockAuth)(nil).UpdatePasswordTX), ctx, tx, userID, passwordHash)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockAuth) UpdateProfile(ctx context.Context, userID, firstName, lastName string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveToken", ctx, token)
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, userID, firstName, lastName)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RemoveToken indicates an expected call of RemoveToken.
-func (mr *MockAuthMockRecorder) RemoveToken(ctx, token any) *gomock.Call {
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockAuthMockRecorder) UpdateProfile(ctx, userID, firstName, lastName any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveToken", reflect.TypeOf((*MockAuth)(nil).RemoveToken), ctx, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockAuth)(nil).UpdateProfile), ctx, userID, firstName, lastName)
 }
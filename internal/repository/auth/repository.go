@@ -2,11 +2,13 @@ package auth
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"finly-backend/internal/domain"
 	"finly-backend/pkg/db"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"time"
@@ -16,10 +18,13 @@ type Auth interface {
 	Register(ctx context.Context, email, passwordHash, firstName, lastName string) (string, error)
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetUserByID(ctx context.Context, id string) (*domain.User, error)
-
-	AddTokenToBlacklist(ctx context.Context, token string, ttlSeconds float64) error
-	IsTokenBlacklisted(ctx context.Context, token string) (bool, error)
-	RemoveToken(ctx context.Context, token string) error
+	UpdatePasswordTX(ctx context.Context, tx *sqlx.Tx, userID, passwordHash string) error
+	MarkEmailVerifiedTX(ctx context.Context, tx *sqlx.Tx, userID, email string) (bool, error)
+	UpdateProfile(ctx context.Context, userID, firstName, lastName string) error
+	ChangeEmailTX(ctx context.Context, tx *sqlx.Tx, userID, email string) (string, error)
+	DeleteTX(ctx context.Context, tx *sqlx.Tx, userID string) error
+	InvalidateCache(ctx context.Context, userID, email string) error
+	PurgeCache(ctx context.Context, keys []string) error
 }
 
 const (
@@ -29,8 +34,21 @@ const (
 
 	cacheKeyUserByID    = "user:id:%s"
 	cacheKeyUserByEmail = "user:email:%s"
+
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+
+	// purgeBatchSize caps the number of keys deleted by a single DEL.
+	purgeBatchSize = 500
 )
 
+// ErrEmailTaken is returned when another user already has the email.
+var ErrEmailTaken = errors.New("email is already taken")
+
+// ErrUserInUse is returned when the user cannot be deleted because budgets of other users
+// still use custom categories of the user.
+var ErrUserInUse = errors.New("user is still referenced by other users")
+
 type AuthRepository struct {
 	postgres *sqlx.DB
 	redis    *redis.Client
@@ -72,6 +90,21 @@ func (a *AuthRepository) InvalidateCache(ctx context.Context, userID, email stri
 	return nil
 }
 
+// PurgeCache deletes keys other repositories cached for a user, collected before the
+// user was deleted.
+func (a *AuthRepository) PurgeCache(ctx context.Context, keys []string) error {
+	for start := 0; start < len(keys); start += purgeBatchSize {
+		batch := keys[start:min(start+purgeBatchSize, len(keys))]
+		if err := a.redis.Del(ctx, batch...).Err(); err != nil {
+			zap.L().Sugar().Warnf("Failed to purge cache, keys: %d, error: %v", len(keys), err)
+			return err
+		}
+	}
+
+	zap.L().Sugar().Infof("Cache purged, keys: %d", len(keys))
+	return nil
+}
+
 func (a *AuthRepository) Register(ctx context.Context, email, passwordHash, firstName, lastName string) (string, error) {
 	query := fmt.Sprintf("INSERT INTO %s (email, password_hash, first_name, last_name) VALUES ($1, $2, $3, $4) RETURNING id", UsersTable)
 
@@ -138,33 +171,99 @@ func (a *AuthRepository) GetUserByID(ctx context.Context, id string) (*domain.Us
 	return result, nil
 }
 
-func (a *AuthRepository) AddTokenToBlacklist(ctx context.Context, token string, ttlSeconds float64) error {
-	if err := a.redis.Set(ctx, token, "blacklisted", time.Duration(ttlSeconds)*time.Second).Err(); err != nil {
-		zap.L().Sugar().Errorf("Failed to add token to blacklist, token: %s, error: %v", token, err)
+// UpdatePasswordTX sets a new password hash. The caller invalidates the cached user once
+// the transaction is committed; dropping it earlier would let a concurrent read cache the
+// old hash again.
+func (a *AuthRepository) UpdatePasswordTX(ctx context.Context, tx *sqlx.Tx, userID, passwordHash string) error {
+	query := fmt.Sprintf("UPDATE %s SET password_hash = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2", UsersTable)
+	if _, err := tx.ExecContext(ctx, query, passwordHash, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to update password, userID: %s, error: %v", userID, err)
 		return err
 	}
-	zap.L().Sugar().Infof("Token added to blacklist, token: %s", token)
+
+	zap.L().Sugar().Infof("Password updated, userID: %s", userID)
 	return nil
 }
 
-func (a *AuthRepository) IsTokenBlacklisted(ctx context.Context, token string) (bool, error) {
-	val, err := a.redis.Get(ctx, token).Result()
-	if errors.Is(err, redis.Nil) {
-		zap.L().Sugar().Infof("Token not blacklisted, token: %s", token)
-		return false, nil
-	} else if err != nil {
-		zap.L().Sugar().Errorf("Redis error while checking blacklist, token: %s, error: %v", token, err)
+// MarkEmailVerifiedTX records that the user owns email. It reports false when email is
+// no longer the address of the user. Like UpdatePasswordTX, it leaves the cache to the caller.
+func (a *AuthRepository) MarkEmailVerifiedTX(ctx context.Context, tx *sqlx.Tx, userID, email string) (bool, error) {
+	query := fmt.Sprintf(`UPDATE %s SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND email = $2`, UsersTable)
+	res, err := tx.ExecContext(ctx, query, userID, email)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to mark email as verified, userID: %s, error: %v", userID, err)
+		return false, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
 		return false, err
 	}
-	zap.L().Sugar().Infof("Token blacklisted, token: %s", token)
-	return val == "blacklisted", nil
+
+	zap.L().Sugar().Infof("Email verified, userID: %s, email: %s", userID, email)
+	return rows > 0, nil
 }
 
-func (a *AuthRepository) RemoveToken(ctx context.Context, token string) error {
-	if err := a.redis.Del(ctx, token).Err(); err != nil {
-		zap.L().Sugar().Errorf("Failed to remove token from blacklist, token: %s, error: %v", token, err)
+func (a *AuthRepository) UpdateProfile(ctx context.Context, userID, firstName, lastName string) error {
+	query := fmt.Sprintf("UPDATE %s SET first_name = $1, last_name = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3", UsersTable)
+	if _, err := a.postgres.ExecContext(ctx, query, firstName, lastName, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to update profile, userID: %s, error: %v", userID, err)
 		return err
 	}
-	zap.L().Sugar().Infof("Token removed from blacklist, token: %s", token)
+
+	if err := a.InvalidateCache(ctx, userID, ""); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after profile update, userID: %s, error: %v", userID, err)
+	}
+
+	zap.L().Sugar().Infof("Profile updated, userID: %s", userID)
+	return nil
+}
+
+// ChangeEmailTX replaces the email of the user with a verified one and returns the
+// previous address, whose cache the caller drops together with the new one after commit.
+func (a *AuthRepository) ChangeEmailTX(ctx context.Context, tx *sqlx.Tx, userID, email string) (string, error) {
+	query := fmt.Sprintf(`UPDATE %[1]s u SET email = $1, email_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		FROM %[1]s old WHERE u.id = $2 AND old.id = u.id RETURNING old.email`, UsersTable)
+
+	var previous string
+	if err := tx.QueryRowxContext(ctx, query, email, userID).Scan(&previous); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return "", ErrEmailTaken
+		}
+		zap.L().Sugar().Errorf("Failed to change email, userID: %s, error: %v", userID, err)
+		return "", err
+	}
+
+	zap.L().Sugar().Infof("Email changed, userID: %s", userID)
+	return previous, nil
+}
+
+// DeleteTX deletes the user. Everything the user owns goes with it through the
+// ON DELETE CASCADE foreign keys. ErrUserInUse is returned while budgets of other users
+// still use custom categories of the user.
+func (a *AuthRepository) DeleteTX(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", UsersTable)
+	res, err := tx.ExecContext(ctx, query, userID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			zap.L().Sugar().Warnf("User is still referenced, userID: %s, constraint: %s", userID, pqErr.Constraint)
+			return ErrUserInUse
+		}
+		zap.L().Sugar().Errorf("Failed to delete user, userID: %s, error: %v", userID, err)
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	zap.L().Sugar().Infof("User deleted, userID: %s", userID)
 	return nil
 }
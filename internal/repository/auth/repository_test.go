@@ -2,12 +2,14 @@ package auth
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"finly-backend/internal/domain"
 	"finly-backend/pkg/testutil"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"testing"
@@ -147,7 +149,7 @@ func TestAuthRepository(t *testing.T) {
 		})
 	})
 
-	t.Run("AddTokenToBlacklist", func(t *testing.T) {
+	t.Run("UpdatePasswordTX", func(t *testing.T) {
 		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
 		defer sqlxDB.Close()
 		defer mr.Close()
@@ -156,20 +158,31 @@ func TestAuthRepository(t *testing.T) {
 		repo := NewAuthRepository(sqlxDB, redisClient)
 
 		t.Run("Success", func(t *testing.T) {
-			token := "testtoken"
-			ttl := 3600.0
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET password_hash = \\$1", UsersTable)).
+				WithArgs("newhash", "123").
+				WillReturnResult(sqlmock.NewResult(0, 1))
 
-			err := repo.AddTokenToBlacklist(ctx, token, ttl)
+			tx, _ := sqlxDB.Beginx()
+			err := repo.UpdatePasswordTX(ctx, tx, "123", "newhash")
 			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
 
-			val, err := redisClient.Get(ctx, token).Result()
-			assert.NoError(t, err)
-			assert.Equal(t, "blacklisted", val)
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET password_hash = \\$1", UsersTable)).
+				WithArgs("newhash", "123").
+				WillReturnError(fmt.Errorf("db error"))
+
+			tx, _ := sqlxDB.Beginx()
+			err := repo.UpdatePasswordTX(ctx, tx, "123", "newhash")
+			assert.Error(t, err)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	})
 
-	t.Run("IsTokenBlacklisted", func(t *testing.T) {
+	t.Run("MarkEmailVerifiedTX", func(t *testing.T) {
 		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
 		defer sqlxDB.Close()
 		defer mr.Close()
@@ -177,27 +190,56 @@ func TestAuthRepository(t *testing.T) {
 		zap.ReplaceGlobals(logger)
 		repo := NewAuthRepository(sqlxDB, redisClient)
 
-		t.Run("Blacklisted", func(t *testing.T) {
-			token := "testtoken"
-			redisClient.Set(ctx, token, "blacklisted", 0)
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET email_verified_at", UsersTable)).
+				WithArgs("123", "test@example.com").
+				WillReturnResult(sqlmock.NewResult(0, 1))
 
-			isBlacklisted, err := repo.IsTokenBlacklisted(ctx, token)
+			tx, _ := sqlxDB.Beginx()
+			verified, err := repo.MarkEmailVerifiedTX(ctx, tx, "123", "test@example.com")
 			assert.NoError(t, err)
-			assert.True(t, isBlacklisted)
+			assert.True(t, verified)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 
-		t.Run("NotBlacklisted", func(t *testing.T) {
-			token := "testtoken2"
+		t.Run("EmailChanged", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET email_verified_at", UsersTable)).
+				WithArgs("123", "old@example.com").
+				WillReturnResult(sqlmock.NewResult(0, 0))
 
-			isBlacklisted, err := repo.IsTokenBlacklisted(ctx, token)
+			tx, _ := sqlxDB.Beginx()
+			verified, err := repo.MarkEmailVerifiedTX(ctx, tx, "123", "old@example.com")
 			assert.NoError(t, err)
-			assert.False(t, isBlacklisted)
+			assert.False(t, verified)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	})
+	t.Run("UpdateProfile", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewAuthRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			cacheKey := fmt.Sprintf(cacheKeyUserByID, "123")
+			redisClient.Set(ctx, cacheKey, "data", 0)
 
-	t.Run("RemoveToken", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET first_name", UsersTable)).
+				WithArgs("Jane", "Doe", "123").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.UpdateProfile(ctx, "123", "Jane", "Doe")
+			assert.NoError(t, err)
+			assert.False(t, mr.Exists(cacheKey))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ChangeEmailTX", func(t *testing.T) {
 		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
 		defer sqlxDB.Close()
 		defer mr.Close()
@@ -206,15 +248,95 @@ func TestAuthRepository(t *testing.T) {
 		repo := NewAuthRepository(sqlxDB, redisClient)
 
 		t.Run("Success", func(t *testing.T) {
-			token := "testtoken"
-			redisClient.Set(ctx, token, "blacklisted", 0)
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s u SET email", UsersTable)).
+				WithArgs("new@example.com", "123").
+				WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("old@example.com"))
 
-			err := repo.RemoveToken(ctx, token)
+			tx, _ := sqlxDB.Beginx()
+			previous, err := repo.ChangeEmailTX(ctx, tx, "123", "new@example.com")
 			assert.NoError(t, err)
+			assert.Equal(t, "old@example.com", previous)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("EmailTaken", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s u SET email", UsersTable)).
+				WithArgs("taken@example.com", "123").
+				WillReturnError(&pq.Error{Code: uniqueViolation})
 
-			exists, _ := redisClient.Exists(ctx, token).Result()
-			assert.Equal(t, int64(0), exists)
+			tx, _ := sqlxDB.Beginx()
+			_, err := repo.ChangeEmailTX(ctx, tx, "123", "taken@example.com")
+			assert.ErrorIs(t, err, ErrEmailTaken)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	})
+
+	t.Run("DeleteTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewAuthRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE id", UsersTable)).
+				WithArgs("123").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			tx, _ := sqlxDB.Beginx()
+			err := repo.DeleteTX(ctx, tx, "123")
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE id", UsersTable)).
+				WithArgs("123").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			tx, _ := sqlxDB.Beginx()
+			err := repo.DeleteTX(ctx, tx, "123")
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("CategoryUsedInForeignBudget", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE id", UsersTable)).
+				WithArgs("123").
+				WillReturnError(&pq.Error{Code: "23503", Constraint: "transactions_category_id_fkey"})
+
+			tx, _ := sqlxDB.Beginx()
+			err := repo.DeleteTX(ctx, tx, "123")
+			assert.ErrorIs(t, err, ErrUserInUse)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("PurgeCache", func(t *testing.T) {
+		sqlxDB, _, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewAuthRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			keys := make([]string, purgeBatchSize+1)
+			for i := range keys {
+				keys[i] = fmt.Sprintf("key:%d", i)
+				redisClient.Set(ctx, keys[i], "data", 0)
+			}
+			redisClient.Set(ctx, "other", "data", 0)
+
+			err := repo.PurgeCache(ctx, keys)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"other"}, mr.Keys())
+		})
+	})
 }
@@ -42,34 +42,77 @@ func (m *MockBudget) EXPECT() *MockBudgetMockRecorder {
 	return m.recorder
 }
 
+// AddMemberTX mocks base method.
+func (m *MockBudget) AddMemberTX(ctx context.Context, tx *sqlx.Tx, budgetID, userID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMemberTX", ctx, tx, budgetID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMemberTX indicates an expected call of AddMemberTX.
+func (mr *MockBudgetMockRecorder) AddMemberTX(ctx, tx, budgetID, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMemberTX", reflect.TypeOf((*MockBudget)(nil).AddMemberTX), ctx, tx, budgetID, userID, role)
+}
+
+// CacheKeysByUserTX mocks base method.
+func (m *MockBudget) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheKeysByUserTX", ctx, tx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CacheKeysByUserTX indicates an expected call of CacheKeysByUserTX.
+func (mr *MockBudgetMockRecorder) CacheKeysByUserTX(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheKeysByUserTX", reflect.TypeOf((*MockBudget)(nil).CacheKeysByUserTX), ctx, tx, userID)
+}
+
 // CreateTX mocks base method.
-func (m *MockBudget) CreateTX(ctx context.Context, tx *sqlx.Tx, userID, currency string) (string, error) {
+func (m *MockBudget) CreateTX(ctx context.Context, tx *sqlx.Tx, userID, name, currency string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTX", ctx, tx, userID, currency)
+	ret := m.ctrl.Call(m, "CreateTX", ctx, tx, userID, name, currency)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateTX indicates an expected call of CreateTX.
-func (mr *MockBudgetMockRecorder) CreateTX(ctx, tx, userID, currency any) *gomock.Call {
+func (mr *MockBudgetMockRecorder) CreateTX(ctx, tx, userID, name, currency any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTX", reflect.TypeOf((*MockBudget)(nil).CreateTX), ctx, tx, userID, currency)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTX", reflect.TypeOf((*MockBudget)(nil).CreateTX), ctx, tx, userID, name, currency)
 }
 
-// GetByUserID mocks base method.
-func (m *MockBudget) GetByUserID(ctx context.Context, userID string) (*domain.Budget, error) {
+// Delete mocks base method.
+func (m *MockBudget) Delete(ctx context.Context, budgetID, userID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret := m.ctrl.Call(m, "Delete", ctx, budgetID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBudgetMockRecorder) Delete(ctx, budgetID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBudget)(nil).Delete), ctx, budgetID, userID)
+}
+
+// GetByID mocks base method.
+func (m *MockBudget) GetByID(ctx context.Context, budgetID, userID string) (*domain.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, budgetID, userID)
 	ret0, _ := ret[0].(*domain.Budget)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByUserID indicates an expected call of GetByUserID.
-func (mr *MockBudgetMockRecorder) GetByUserID(ctx, userID any) *gomock.Call {
+// GetByID indicates an expected call of GetByID.
+func (mr *MockBudgetMockRecorder) GetByID(ctx, budgetID, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockBudget)(nil).GetByUserID), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBudget)(nil).GetByID), ctx, budgetID, userID)
 }
 
 // GetDB mocks base method.
@@ -85,3 +128,119 @@ func (mr *MockBudgetMockRecorder) GetDB() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDB", reflect.TypeOf((*MockBudget)(nil).GetDB))
 }
+
+// InvalidateCache mocks base method.
+func (m *MockBudget) InvalidateCache(ctx context.Context, userID, budgetID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateCache", ctx, userID, budgetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateCache indicates an expected call of InvalidateCache.
+func (mr *MockBudgetMockRecorder) InvalidateCache(ctx, userID, budgetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateCache", reflect.TypeOf((*MockBudget)(nil).InvalidateCache), ctx, userID, budgetID)
+}
+
+// ListByUserID mocks base method.
+func (m *MockBudget) ListByUserID(ctx context.Context, userID string) ([]*domain.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockBudgetMockRecorder) ListByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockBudget)(nil).ListByUserID), ctx, userID)
+}
+
+// ListMembers mocks base method.
+func (m *MockBudget) ListMembers(ctx context.Context, budgetID string) ([]*domain.BudgetMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, budgetID)
+	ret0, _ := ret[0].([]*domain.BudgetMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockBudgetMockRecorder) ListMembers(ctx, budgetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockBudget)(nil).ListMembers), ctx, budgetID)
+}
+
+// LockTX mocks base method.
+func (m *MockBudget) LockTX(ctx context.Context, tx *sqlx.Tx, budgetID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockTX", ctx, tx, budgetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockTX indicates an expected call of LockTX.
+func (mr *MockBudgetMockRecorder) LockTX(ctx, tx, budgetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockTX", reflect.TypeOf((*MockBudget)(nil).LockTX), ctx, tx, budgetID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockBudget) RemoveMember(ctx context.Context, budgetID, userID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, budgetID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockBudgetMockRecorder) RemoveMember(ctx, budgetID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockBudget)(nil).RemoveMember), ctx, budgetID, userID)
+}
+
+// SetArchived mocks base method.
+func (m *MockBudget) SetArchived(ctx context.Context, budgetID, userID string, archived bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetArchived", ctx, budgetID, userID, archived)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetArchived indicates an expected call of SetArchived.
+func (mr *MockBudgetMockRecorder) SetArchived(ctx, budgetID, userID, archived any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArchived", reflect.TypeOf((*MockBudget)(nil).SetArchived), ctx, budgetID, userID, archived)
+}
+
+// Update mocks base method.
+func (m *MockBudget) Update(ctx context.Context, budgetID, userID, name, currency string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, budgetID, userID, name, currency)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBudgetMockRecorder) Update(ctx, budgetID, userID, name, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBudget)(nil).Update), ctx, budgetID, userID, name, currency)
+}
+
+// UpdateMemberRole mocks base method.
+func (m *MockBudget) UpdateMemberRole(ctx context.Context, budgetID, userID, role string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberRole", ctx, budgetID, userID, role)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMemberRole indicates an expected call of UpdateMemberRole.
+func (mr *MockBudgetMockRecorder) UpdateMemberRole(ctx, budgetID, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRole", reflect.TypeOf((*MockBudget)(nil).UpdateMemberRole), ctx, budgetID, userID, role)
+}
@@ -2,7 +2,10 @@ package budget
 
 import (
 	"context"
+	"errors"
 	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_budget_role"
+	"finly-backend/internal/repository/auth"
 	"finly-backend/pkg/db"
 	"fmt"
 	"github.com/jmoiron/sqlx"
@@ -13,18 +16,36 @@ import (
 
 type Budget interface {
 	GetDB() *sqlx.DB
-	CreateTX(ctx context.Context, tx *sqlx.Tx, userID, currency string) (string, error)
-	GetByUserID(ctx context.Context, userID string) (*domain.Budget, error)
+	CreateTX(ctx context.Context, tx *sqlx.Tx, userID, name, currency string) (string, error)
+	GetByID(ctx context.Context, budgetID, userID string) (*domain.Budget, error)
+	ListByUserID(ctx context.Context, userID string) ([]*domain.Budget, error)
+	Update(ctx context.Context, budgetID, userID, name, currency string) error
+	SetArchived(ctx context.Context, budgetID, userID string, archived bool) error
+	Delete(ctx context.Context, budgetID, userID string) error
+	ListMembers(ctx context.Context, budgetID string) ([]*domain.BudgetMember, error)
+	AddMemberTX(ctx context.Context, tx *sqlx.Tx, budgetID, userID, role string) error
+	InvalidateCache(ctx context.Context, userID, budgetID string) error
+	UpdateMemberRole(ctx context.Context, budgetID, userID, role string) (bool, error)
+	RemoveMember(ctx context.Context, budgetID, userID string) (bool, error)
+	LockTX(ctx context.Context, tx *sqlx.Tx, budgetID string) error
+	CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error)
 }
 
 const (
-	BudgetTable = "budgets"
+	BudgetTable  = "budgets"
+	MembersTable = "budget_members"
 
-	TTL_GetBudgetByUserIDCache = 30 * time.Minute
+	TTL_ListBudgetsByUserIDCache = 30 * time.Minute
+	TTL_GetBudgetByIDCache       = 30 * time.Minute
 
-	cacheKeyBudgetByUser = "budget:user:%s"
+	cacheKeyBudgetsByUser     = "budgets:user:%s"
+	cacheKeyBudgetByIDAndUser = "budget:%s:user:%s"
 )
 
+// ErrCurrencyLocked is returned when the currency of a budget that already has transactions
+// would change.
+var ErrCurrencyLocked = errors.New("budget currency is locked")
+
 type BudgetRepository struct {
 	postgres *sqlx.DB
 	redis    *redis.Client
@@ -41,35 +62,57 @@ func (b *BudgetRepository) GetDB() *sqlx.DB {
 	return b.postgres
 }
 
-func (b *BudgetRepository) cacheKeys(userID string) []string {
-	return []string{
-		fmt.Sprintf(cacheKeyBudgetByUser, userID),
+func (b *BudgetRepository) cacheKeys(userID, budgetID string) []string {
+	keys := []string{
+		fmt.Sprintf(cacheKeyBudgetsByUser, userID),
+	}
+	if budgetID != "" {
+		keys = append(keys, fmt.Sprintf(cacheKeyBudgetByIDAndUser, budgetID, userID))
 	}
+	return keys
 }
 
-func (b *BudgetRepository) InvalidateCache(ctx context.Context, userID string) error {
-	zap.L().Sugar().Infof("Invalidating cache for userID: %s", userID)
+func (b *BudgetRepository) InvalidateCache(ctx context.Context, userID, budgetID string) error {
+	zap.L().Sugar().Infof("Invalidating cache for userID: %s, budgetID: %s", userID, budgetID)
 
-	keys := b.cacheKeys(userID)
+	keys := b.cacheKeys(userID, budgetID)
 	if err := b.redis.Del(ctx, keys...).Err(); err != nil {
-		zap.L().Sugar().Warnf("Failed to invalidate cache for userID: %s, error: %v", userID, err)
+		zap.L().Sugar().Warnf("Failed to invalidate cache for userID: %s, budgetID: %s, error: %v", userID, budgetID, err)
 		return err
 	}
 
-	zap.L().Sugar().Infof("Cache invalidated for userID: %s", userID)
+	zap.L().Sugar().Infof("Cache invalidated for userID: %s, budgetID: %s", userID, budgetID)
 	return nil
 }
 
-func (b *BudgetRepository) CreateTX(ctx context.Context, tx *sqlx.Tx, userID, currency string) (string, error) {
-	query := fmt.Sprintf("INSERT INTO %s (user_id, currency) VALUES ($1, $2) RETURNING id", BudgetTable)
+// invalidateMembers drops the cached budget of every member, as each member has their own
+// copy of it.
+func (b *BudgetRepository) invalidateMembers(ctx context.Context, budgetID string, memberIDs []string) {
+	var keys []string
+	for _, id := range memberIDs {
+		keys = append(keys, b.cacheKeys(id, budgetID)...)
+	}
+	if len(keys) == 0 {
+		return
+	}
+	if err := b.redis.Del(ctx, keys...).Err(); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache of members, budgetID: %s, error: %v", budgetID, err)
+	}
+}
+
+// CreateTX creates the budget along with the membership of its owner.
+func (b *BudgetRepository) CreateTX(ctx context.Context, tx *sqlx.Tx, userID, name, currency string) (string, error) {
+	query := fmt.Sprintf(`WITH created AS (INSERT INTO %s (user_id, name, currency) VALUES ($1, $2, $3) RETURNING id)
+		INSERT INTO %s (budget_id, user_id, role) SELECT id, $1, '%s' FROM created RETURNING budget_id`,
+		BudgetTable, MembersTable, e_budget_role.Owner)
 
 	var id string
-	if err := tx.QueryRowContext(ctx, query, userID, currency).Scan(&id); err != nil {
+	if err := tx.QueryRowContext(ctx, query, userID, name, currency).Scan(&id); err != nil {
 		zap.L().Sugar().Errorf("Failed to create budget for userID: %s, currency: %s, error: %v", userID, currency, err)
 		return "", err
 	}
 
-	if err := b.InvalidateCache(ctx, userID); err != nil {
+	if err := b.InvalidateCache(ctx, userID, id); err != nil {
 		zap.L().Sugar().Warnf("Failed to invalidate cache after create, userID: %s, error: %v", userID, err)
 	} else {
 		zap.L().Sugar().Infof("Cache invalidated after create for userID: %s", userID)
@@ -78,29 +121,218 @@ func (b *BudgetRepository) CreateTX(ctx context.Context, tx *sqlx.Tx, userID, cu
 	return id, nil
 }
 
-func (b *BudgetRepository) GetByUserID(ctx context.Context, userID string) (*domain.Budget, error) {
-	if userID == "" {
-		return nil, fmt.Errorf("userID cannot be empty")
+// GetByID loads the budget if the user is one of its members, along with the role of the user.
+func (b *BudgetRepository) GetByID(ctx context.Context, budgetID, userID string) (*domain.Budget, error) {
+	if budgetID == "" || userID == "" {
+		return nil, fmt.Errorf("budgetID and userID cannot be empty")
 	}
 
-	cacheKey := fmt.Sprintf(cacheKeyBudgetByUser, userID)
+	cacheKey := fmt.Sprintf(cacheKeyBudgetByIDAndUser, budgetID, userID)
 
 	fetch := func() (*domain.Budget, error) {
 		var budget domain.Budget
-		query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1", BudgetTable)
-		if err := b.postgres.GetContext(ctx, &budget, query, userID); err != nil {
-			zap.L().Sugar().Errorf("Failed to fetch budget from DB, userID: %s, error: %v", userID, err)
+		query := fmt.Sprintf(`SELECT b.*, m.role FROM %s b JOIN %s m ON m.budget_id = b.id
+			WHERE b.id = $1 AND m.user_id = $2`, BudgetTable, MembersTable)
+		if err := b.postgres.GetContext(ctx, &budget, query, budgetID, userID); err != nil {
+			zap.L().Sugar().Errorf("Failed to fetch budget from DB, budgetID: %s, userID: %s, error: %v", budgetID, userID, err)
 			return nil, err
 		}
-		zap.L().Sugar().Infof("Fetched budget from DB for userID: %s, budgetID: %s", userID, budget.ID)
 		return &budget, nil
 	}
 
-	result, err := db.WithCache(ctx, b.redis, cacheKey, TTL_GetBudgetByUserIDCache, fetch)
+	return db.WithCache(ctx, b.redis, cacheKey, TTL_GetBudgetByIDCache, fetch)
+}
+
+// ListByUserID lists the budgets the user owns or is a member of.
+func (b *BudgetRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.Budget, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("userID cannot be empty")
+	}
+
+	cacheKey := fmt.Sprintf(cacheKeyBudgetsByUser, userID)
+
+	fetch := func() ([]*domain.Budget, error) {
+		var budgets []*domain.Budget
+		query := fmt.Sprintf(`SELECT b.*, m.role FROM %s b JOIN %s m ON m.budget_id = b.id
+			WHERE m.user_id = $1 ORDER BY b.created_at ASC`, BudgetTable, MembersTable)
+		if err := b.postgres.SelectContext(ctx, &budgets, query, userID); err != nil {
+			zap.L().Sugar().Errorf("Failed to fetch budgets from DB, userID: %s, error: %v", userID, err)
+			return nil, err
+		}
+		zap.L().Sugar().Infof("Fetched %d budgets from DB for userID: %s", len(budgets), userID)
+		return budgets, nil
+	}
+
+	return db.WithCache(ctx, b.redis, cacheKey, TTL_ListBudgetsByUserIDCache, fetch)
+}
+
+// Update renames a budget or changes its currency. The currency is only changed while the
+// budget has no transactions; otherwise nothing is updated and ErrCurrencyLocked is returned.
+func (b *BudgetRepository) Update(ctx context.Context, budgetID, userID, name, currency string) error {
+	query := fmt.Sprintf(`WITH updated AS (
+			UPDATE %s b
+			SET name = COALESCE(NULLIF($1, ''), name), currency = COALESCE(NULLIF($2, ''), currency), updated_at = CURRENT_TIMESTAMP
+			WHERE id = $3 AND user_id = $4
+				AND ($2 = '' OR $2 = currency OR NOT EXISTS (SELECT 1 FROM transactions t WHERE t.budget_id = b.id))
+			RETURNING id
+		)
+		SELECT m.user_id FROM %s m JOIN updated u ON u.id = m.budget_id`, BudgetTable, MembersTable)
+	var memberIDs []string
+	if err := b.postgres.SelectContext(ctx, &memberIDs, query, name, currency, budgetID, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to update budget, budgetID: %s, userID: %s, error: %v", budgetID, userID, err)
+		return err
+	}
+	// The owner is always a member, so an updated budget returns at least one row.
+	if len(memberIDs) == 0 && currency != "" {
+		zap.L().Sugar().Warnf("Budget currency is locked, budgetID: %s, userID: %s", budgetID, userID)
+		return ErrCurrencyLocked
+	}
+
+	b.invalidateMembers(ctx, budgetID, append(memberIDs, userID))
+
+	zap.L().Sugar().Infof("Budget updated, budgetID: %s, userID: %s", budgetID, userID)
+	return nil
+}
+
+func (b *BudgetRepository) SetArchived(ctx context.Context, budgetID, userID string, archived bool) error {
+	query := fmt.Sprintf(`WITH updated AS (
+			UPDATE %s
+			SET archived_at = CASE WHEN $1 THEN COALESCE(archived_at, CURRENT_TIMESTAMP) END, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $2 AND user_id = $3
+			RETURNING id
+		)
+		SELECT m.user_id FROM %s m JOIN updated u ON u.id = m.budget_id`, BudgetTable, MembersTable)
+	var memberIDs []string
+	if err := b.postgres.SelectContext(ctx, &memberIDs, query, archived, budgetID, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to set archived=%t for budgetID: %s, userID: %s, error: %v", archived, budgetID, userID, err)
+		return err
+	}
+
+	b.invalidateMembers(ctx, budgetID, append(memberIDs, userID))
+
+	zap.L().Sugar().Infof("Budget archived=%t, budgetID: %s, userID: %s", archived, budgetID, userID)
+	return nil
+}
+
+// Delete deletes the budget. The members are listed in the same statement, as their
+// memberships are gone once it has run.
+func (b *BudgetRepository) Delete(ctx context.Context, budgetID, userID string) error {
+	query := fmt.Sprintf(`WITH deleted AS (DELETE FROM %s WHERE id = $1 AND user_id = $2 RETURNING id)
+		SELECT m.user_id FROM %s m JOIN deleted d ON d.id = m.budget_id`, BudgetTable, MembersTable)
+	var memberIDs []string
+	if err := b.postgres.SelectContext(ctx, &memberIDs, query, budgetID, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to delete budget, budgetID: %s, userID: %s, error: %v", budgetID, userID, err)
+		return err
+	}
+
+	b.invalidateMembers(ctx, budgetID, append(memberIDs, userID))
+
+	zap.L().Sugar().Infof("Budget deleted, budgetID: %s, userID: %s", budgetID, userID)
+	return nil
+}
+
+// ListMembers lists the members of the budget, the owner first.
+func (b *BudgetRepository) ListMembers(ctx context.Context, budgetID string) ([]*domain.BudgetMember, error) {
+	query := fmt.Sprintf(`SELECT m.budget_id, m.user_id, m.role, u.email, u.first_name, u.last_name, m.created_at
+		FROM %s m JOIN %s u ON u.id = m.user_id
+		WHERE m.budget_id = $1
+		ORDER BY m.role = '%s' DESC, m.created_at ASC`, MembersTable, auth.UsersTable, e_budget_role.Owner)
+
+	var members []*domain.BudgetMember
+	if err := b.postgres.SelectContext(ctx, &members, query, budgetID); err != nil {
+		zap.L().Sugar().Errorf("Failed to list members, budgetID: %s, error: %v", budgetID, err)
+		return nil, err
+	}
+	return members, nil
+}
+
+// AddMemberTX adds the user to the budget. A user who already is a member keeps their role.
+// The cached budgets of the user are dropped by the caller once tx is committed.
+func (b *BudgetRepository) AddMemberTX(ctx context.Context, tx *sqlx.Tx, budgetID, userID, role string) error {
+	query := fmt.Sprintf(`INSERT INTO %s (budget_id, user_id, role) VALUES ($1, $2, $3)
+		ON CONFLICT (budget_id, user_id) DO NOTHING`, MembersTable)
+	if _, err := tx.ExecContext(ctx, query, budgetID, userID, role); err != nil {
+		zap.L().Sugar().Errorf("Failed to add member, budgetID: %s, userID: %s, error: %v", budgetID, userID, err)
+		return err
+	}
+
+	zap.L().Sugar().Infof("Member added, budgetID: %s, userID: %s, role: %s", budgetID, userID, role)
+	return nil
+}
+
+// UpdateMemberRole changes the role of a member. The owner can't be changed; it returns
+// false when there is no such member.
+func (b *BudgetRepository) UpdateMemberRole(ctx context.Context, budgetID, userID, role string) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET role = $1 WHERE budget_id = $2 AND user_id = $3 AND role <> '%s'",
+		MembersTable, e_budget_role.Owner)
+	res, err := b.postgres.ExecContext(ctx, query, role, budgetID, userID)
 	if err != nil {
+		zap.L().Sugar().Errorf("Failed to update member role, budgetID: %s, userID: %s, error: %v", budgetID, userID, err)
+		return false, err
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	b.invalidateMembers(ctx, budgetID, []string{userID})
+	return updated > 0, nil
+}
+
+// RemoveMember removes a member from the budget. The owner can't be removed; it returns
+// false when there is no such member.
+func (b *BudgetRepository) RemoveMember(ctx context.Context, budgetID, userID string) (bool, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE budget_id = $1 AND user_id = $2 AND role <> '%s'",
+		MembersTable, e_budget_role.Owner)
+	res, err := b.postgres.ExecContext(ctx, query, budgetID, userID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to remove member, budgetID: %s, userID: %s, error: %v", budgetID, userID, err)
+		return false, err
+	}
+
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	b.invalidateMembers(ctx, budgetID, []string{userID})
+	return removed > 0, nil
+}
+
+// LockTX locks the budget row until tx ends, so writes to the budget that must see each
+// other, such as withdrawals checked against an enforced category limit, run one at a time.
+func (b *BudgetRepository) LockTX(ctx context.Context, tx *sqlx.Tx, budgetID string) error {
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 FOR UPDATE", BudgetTable)
+	if _, err := tx.ExecContext(ctx, query, budgetID); err != nil {
+		zap.L().Sugar().Errorf("Failed to lock budget, budgetID: %s, error: %v", budgetID, err)
+		return err
+	}
+	return nil
+}
+
+// CacheKeysByUserTX lists the cache keys of every budget the user is a member of, so they
+// can be purged once the user is deleted. For budgets the user owns, the keys of the other
+// members are listed too, as those budgets are deleted along with the user.
+func (b *BudgetRepository) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	var members []struct {
+		BudgetID string `db:"budget_id"`
+		UserID   string `db:"user_id"`
+	}
+	query := fmt.Sprintf(`SELECT m.budget_id, m.user_id FROM %s m
+		WHERE m.user_id = $1 OR m.budget_id IN (SELECT id FROM %s WHERE user_id = $1)`, MembersTable, BudgetTable)
+	if err := tx.SelectContext(ctx, &members, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to list budget IDs, userID: %s, error: %v", userID, err)
 		return nil, err
 	}
 
-	zap.L().Sugar().Infof("Fetched budget with cache for userID: %s", userID)
-	return result, nil
+	keys := b.cacheKeys(userID, "")
+	for _, m := range members {
+		if m.UserID == userID {
+			keys = append(keys, b.cacheKeys(userID, m.BudgetID)[1:]...)
+			continue
+		}
+		keys = append(keys, b.cacheKeys(m.UserID, m.BudgetID)...)
+	}
+	return keys, nil
 }
@@ -2,6 +2,7 @@ package budget
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"finly-backend/internal/domain"
@@ -41,8 +42,19 @@ func TestBudgetRepository(t *testing.T) {
 
 		t.Run("Success", func(t *testing.T) {
 			userID := "123"
-			expected := []string{fmt.Sprintf(cacheKeyBudgetByUser, userID)}
-			keys := repo.cacheKeys(userID)
+			budgetID := "456"
+			expected := []string{
+				fmt.Sprintf(cacheKeyBudgetsByUser, userID),
+				fmt.Sprintf(cacheKeyBudgetByIDAndUser, budgetID, userID),
+			}
+			keys := repo.cacheKeys(userID, budgetID)
+			assert.Equal(t, expected, keys)
+		})
+
+		t.Run("WithoutBudgetID", func(t *testing.T) {
+			userID := "123"
+			expected := []string{fmt.Sprintf(cacheKeyBudgetsByUser, userID)}
+			keys := repo.cacheKeys(userID, "")
 			assert.Equal(t, expected, keys)
 		})
 	})
@@ -57,11 +69,12 @@ func TestBudgetRepository(t *testing.T) {
 
 		t.Run("Success", func(t *testing.T) {
 			userID := "123"
-			cacheKey := fmt.Sprintf(cacheKeyBudgetByUser, userID)
+			budgetID := "456"
+			cacheKey := fmt.Sprintf(cacheKeyBudgetByIDAndUser, budgetID, userID)
 
 			redisClient.Set(ctx, cacheKey, "data", 0)
 
-			err := repo.InvalidateCache(ctx, userID)
+			err := repo.InvalidateCache(ctx, userID, budgetID)
 			assert.NoError(t, err)
 
 			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
@@ -74,7 +87,7 @@ func TestBudgetRepository(t *testing.T) {
 
 			mr.Close()
 
-			err := repo.InvalidateCache(ctx, userID)
+			err := repo.InvalidateCache(ctx, userID, "")
 			assert.Error(t, err)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
@@ -90,20 +103,21 @@ func TestBudgetRepository(t *testing.T) {
 
 		t.Run("Success", func(t *testing.T) {
 			userID := "123"
+			name := "Main"
 			currency := "USD"
 			budgetID := "456"
-			cacheKey := fmt.Sprintf(cacheKeyBudgetByUser, userID)
+			cacheKey := fmt.Sprintf(cacheKeyBudgetsByUser, userID)
 
 			mock.ExpectBegin()
-			query := fmt.Sprintf("INSERT INTO %s \\(user_id, currency\\) VALUES \\(\\$1, \\$2\\) RETURNING id", BudgetTable)
+			query := fmt.Sprintf("INSERT INTO %s \\(user_id, name, currency\\) VALUES \\(\\$1, \\$2, \\$3\\) RETURNING id", BudgetTable)
 			mock.ExpectQuery(query).
-				WithArgs(userID, currency).
+				WithArgs(userID, name, currency).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(budgetID))
 
 			tx, err := sqlxDB.Beginx()
 			assert.NoError(t, err)
 
-			id, err := repo.CreateTX(ctx, tx, userID, currency)
+			id, err := repo.CreateTX(ctx, tx, userID, name, currency)
 			assert.NoError(t, err)
 			assert.Equal(t, budgetID, id)
 
@@ -114,19 +128,20 @@ func TestBudgetRepository(t *testing.T) {
 
 		t.Run("DatabaseError", func(t *testing.T) {
 			userID := "123"
+			name := "Main"
 			currency := "USD"
 
 			mock.ExpectBegin()
-			query := fmt.Sprintf("INSERT INTO %s \\(user_id, currency\\) VALUES \\(\\$1, \\$2\\) RETURNING id", BudgetTable)
+			query := fmt.Sprintf("INSERT INTO %s \\(user_id, name, currency\\) VALUES \\(\\$1, \\$2, \\$3\\) RETURNING id", BudgetTable)
 			mock.ExpectQuery(query).
-				WithArgs(userID, currency).
+				WithArgs(userID, name, currency).
 				WillReturnError(errors.New("db error"))
 			mock.ExpectRollback()
 
 			tx, err := sqlxDB.Beginx()
 			assert.NoError(t, err)
 
-			id, err := repo.CreateTX(ctx, tx, userID, currency)
+			id, err := repo.CreateTX(ctx, tx, userID, name, currency)
 			assert.Error(t, err)
 			assert.Empty(t, id)
 
@@ -139,13 +154,14 @@ func TestBudgetRepository(t *testing.T) {
 
 		t.Run("CacheInvalidateError", func(t *testing.T) {
 			userID := "123"
+			name := "Main"
 			currency := "USD"
 			budgetID := "456"
 
 			mock.ExpectBegin()
-			query := fmt.Sprintf("INSERT INTO %s \\(user_id, currency\\) VALUES \\(\\$1, \\$2\\) RETURNING id", BudgetTable)
+			query := fmt.Sprintf("INSERT INTO %s \\(user_id, name, currency\\) VALUES \\(\\$1, \\$2, \\$3\\) RETURNING id", BudgetTable)
 			mock.ExpectQuery(query).
-				WithArgs(userID, currency).
+				WithArgs(userID, name, currency).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(budgetID))
 
 			mr.Close()
@@ -153,14 +169,14 @@ func TestBudgetRepository(t *testing.T) {
 			tx, err := sqlxDB.Beginx()
 			assert.NoError(t, err)
 
-			id, err := repo.CreateTX(ctx, tx, userID, currency)
+			id, err := repo.CreateTX(ctx, tx, userID, name, currency)
 			assert.NoError(t, err)
 			assert.Equal(t, budgetID, id)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	})
 
-	t.Run("GetByUserID", func(t *testing.T) {
+	t.Run("GetByID", func(t *testing.T) {
 		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
 		defer sqlxDB.Close()
 		defer mr.Close()
@@ -170,16 +186,17 @@ func TestBudgetRepository(t *testing.T) {
 
 		t.Run("CacheHit", func(t *testing.T) {
 			userID := "123"
-			cacheKey := fmt.Sprintf(cacheKeyBudgetByUser, userID)
-			budget := &domain.Budget{ID: "456", UserID: userID, Currency: "USD"}
+			budgetID := "456"
+			cacheKey := fmt.Sprintf(cacheKeyBudgetByIDAndUser, budgetID, userID)
+			budget := &domain.Budget{ID: budgetID, UserID: userID, Name: "Main", Currency: "USD"}
 
 			data, err := json.Marshal(budget)
 			assert.NoError(t, err)
 
-			err = redisClient.Set(ctx, cacheKey, data, TTL_GetBudgetByUserIDCache).Err()
+			err = redisClient.Set(ctx, cacheKey, data, TTL_GetBudgetByIDCache).Err()
 			assert.NoError(t, err)
 
-			result, err := repo.GetByUserID(ctx, userID)
+			result, err := repo.GetByID(ctx, budgetID, userID)
 			assert.NoError(t, err)
 			assert.Equal(t, budget, result)
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -187,43 +204,389 @@ func TestBudgetRepository(t *testing.T) {
 
 		t.Run("CacheMiss", func(t *testing.T) {
 			userID := "1234"
-			budget := &domain.Budget{ID: "456", UserID: userID, Currency: "USD"}
+			budgetID := "456"
+			budget := &domain.Budget{ID: budgetID, UserID: userID, Name: "Cash", Currency: "USD"}
 
-			query := fmt.Sprintf("SELECT \\* FROM %s WHERE user_id = \\$1", BudgetTable)
+			query := fmt.Sprintf("SELECT b.\\*, m.role FROM %s b JOIN %s m ON m.budget_id = b.id\\s+WHERE b.id = \\$1 AND m.user_id = \\$2", BudgetTable, MembersTable)
 			mock.ExpectQuery(query).
-				WithArgs(userID).
-				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "currency"}).
-					AddRow(budget.ID, budget.UserID, budget.Currency))
+				WithArgs(budgetID, userID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "currency"}).
+					AddRow(budget.ID, budget.UserID, budget.Name, budget.Currency))
 
-			result, err := repo.GetByUserID(ctx, userID)
+			result, err := repo.GetByID(ctx, budgetID, userID)
 			assert.NoError(t, err)
 			assert.Equal(t, budget, result)
 			assert.NoError(t, mock.ExpectationsWereMet())
 
-			cached, err := redisClient.Get(ctx, fmt.Sprintf(cacheKeyBudgetByUser, userID)).Result()
+			cached, err := redisClient.Get(ctx, fmt.Sprintf(cacheKeyBudgetByIDAndUser, budgetID, userID)).Result()
 			assert.NoError(t, err)
 			assert.NotEmpty(t, cached)
 		})
 
-		t.Run("EmptyUserID", func(t *testing.T) {
-			result, err := repo.GetByUserID(ctx, "")
+		t.Run("EmptyIDs", func(t *testing.T) {
+			result, err := repo.GetByID(ctx, "", "123")
 			assert.Error(t, err)
 			assert.Nil(t, result)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 
+		t.Run("NotFound", func(t *testing.T) {
+			userID := "12345"
+			budgetID := "789"
+
+			query := fmt.Sprintf("SELECT b.\\*, m.role FROM %s b JOIN %s m ON m.budget_id = b.id\\s+WHERE b.id = \\$1 AND m.user_id = \\$2", BudgetTable, MembersTable)
+			mock.ExpectQuery(query).
+				WithArgs(budgetID, userID).
+				WillReturnError(sql.ErrNoRows)
+
+			result, err := repo.GetByID(ctx, budgetID, userID)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			assert.Nil(t, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ListByUserID", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetRepository(sqlxDB, redisClient)
+
+		t.Run("CacheMiss", func(t *testing.T) {
+			userID := "123"
+			budgets := []*domain.Budget{
+				{ID: "1", UserID: userID, Name: "Cash", Currency: "USD"},
+				{ID: "2", UserID: userID, Name: "Card", Currency: "EUR"},
+			}
+
+			query := fmt.Sprintf("SELECT b.\\*, m.role FROM %s b JOIN %s m ON m.budget_id = b.id\\s+WHERE m.user_id = \\$1 ORDER BY b.created_at ASC", BudgetTable, MembersTable)
+			mock.ExpectQuery(query).
+				WithArgs(userID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "currency"}).
+					AddRow(budgets[0].ID, budgets[0].UserID, budgets[0].Name, budgets[0].Currency).
+					AddRow(budgets[1].ID, budgets[1].UserID, budgets[1].Name, budgets[1].Currency))
+
+			result, err := repo.ListByUserID(ctx, userID)
+			assert.NoError(t, err)
+			assert.Equal(t, budgets, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			cached, err := redisClient.Get(ctx, fmt.Sprintf(cacheKeyBudgetsByUser, userID)).Result()
+			assert.NoError(t, err)
+			assert.NotEmpty(t, cached)
+		})
+
+		t.Run("CacheHit", func(t *testing.T) {
+			result, err := repo.ListByUserID(ctx, "123")
+			assert.NoError(t, err)
+			assert.Len(t, result, 2)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("EmptyUserID", func(t *testing.T) {
+			result, err := repo.ListByUserID(ctx, "")
+			assert.Error(t, err)
+			assert.Nil(t, result)
+		})
+
 		t.Run("DatabaseError", func(t *testing.T) {
 			userID := "12345"
 
-			query := fmt.Sprintf("SELECT \\* FROM %s WHERE user_id = \\$1", BudgetTable)
+			query := fmt.Sprintf("SELECT b.\\*, m.role FROM %s b JOIN %s m ON m.budget_id = b.id\\s+WHERE m.user_id = \\$1 ORDER BY b.created_at ASC", BudgetTable, MembersTable)
 			mock.ExpectQuery(query).
 				WithArgs(userID).
 				WillReturnError(errors.New("db error"))
 
-			result, err := repo.GetByUserID(ctx, userID)
+			result, err := repo.ListByUserID(ctx, userID)
 			assert.Error(t, err)
 			assert.Nil(t, result)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	})
+
+	t.Run("Update", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			userID := "123"
+			budgetID := "456"
+			cacheKey := fmt.Sprintf(cacheKeyBudgetsByUser, userID)
+			memberCacheKey := fmt.Sprintf(cacheKeyBudgetByIDAndUser, budgetID, "789")
+			redisClient.Set(ctx, cacheKey, "data", 0)
+			redisClient.Set(ctx, memberCacheKey, "data", 0)
+
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s", BudgetTable)).
+				WithArgs("Savings", "", budgetID, userID).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID).AddRow("789"))
+
+			err := repo.Update(ctx, budgetID, userID, "Savings", "")
+			assert.NoError(t, err)
+
+			exists, _ := redisClient.Exists(ctx, cacheKey, memberCacheKey).Result()
+			assert.Equal(t, int64(0), exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s", BudgetTable)).
+				WithArgs("", "EUR", "456", "123").
+				WillReturnError(errors.New("db error"))
+
+			err := repo.Update(ctx, "456", "123", "", "EUR")
+			assert.Error(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("CurrencyLocked", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s b(.+)NOT EXISTS \\(SELECT 1 FROM transactions t WHERE t.budget_id = b.id\\)", BudgetTable)).
+				WithArgs("", "EUR", "456", "123").
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+			err := repo.Update(ctx, "456", "123", "", "EUR")
+			assert.ErrorIs(t, err, ErrCurrencyLocked)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("SetArchived", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s\\s+SET archived_at", BudgetTable)).
+				WithArgs(true, "456", "123").
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("123"))
+
+			err := repo.SetArchived(ctx, "456", "123", true)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s\\s+SET archived_at", BudgetTable)).
+				WithArgs(false, "456", "123").
+				WillReturnError(errors.New("db error"))
+
+			err := repo.SetArchived(ctx, "456", "123", false)
+			assert.Error(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			userID := "123"
+			budgetID := "456"
+			cacheKey := fmt.Sprintf(cacheKeyBudgetByIDAndUser, budgetID, userID)
+			redisClient.Set(ctx, cacheKey, "data", 0)
+
+			query := fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND user_id = \\$2", BudgetTable)
+			mock.ExpectQuery(query).
+				WithArgs(budgetID, userID).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
+
+			err := repo.Delete(ctx, budgetID, userID)
+			assert.NoError(t, err)
+
+			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
+			assert.Equal(t, int64(0), exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			query := fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND user_id = \\$2", BudgetTable)
+			mock.ExpectQuery(query).
+				WithArgs("456", "123").
+				WillReturnError(errors.New("db error"))
+
+			err := repo.Delete(ctx, "456", "123")
+			assert.Error(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+	t.Run("ListMembers", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("FROM %s m JOIN users u ON u.id = m.user_id", MembersTable)).
+				WithArgs("456").
+				WillReturnRows(sqlmock.NewRows([]string{"budget_id", "user_id", "role", "email"}).
+					AddRow("456", "123", "owner", "owner@example.com").
+					AddRow("456", "789", "viewer", "viewer@example.com"))
+
+			members, err := repo.ListMembers(ctx, "456")
+			assert.NoError(t, err)
+			assert.Len(t, members, 2)
+			assert.Equal(t, "viewer", members[1].Role)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("AddMemberTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			cacheKey := fmt.Sprintf(cacheKeyBudgetsByUser, "789")
+			redisClient.Set(ctx, cacheKey, "data", 0)
+
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("INSERT INTO %s \\(budget_id, user_id, role\\)", MembersTable)).
+				WithArgs("456", "789", "editor").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			tx, _ := sqlxDB.Beginx()
+			err := repo.AddMemberTX(ctx, tx, "456", "789", "editor")
+			assert.NoError(t, err)
+
+			// The cached budgets are dropped by the caller once tx is committed.
+			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
+			assert.Equal(t, int64(1), exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("UpdateMemberRole", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			cacheKey := fmt.Sprintf(cacheKeyBudgetByIDAndUser, "456", "789")
+			redisClient.Set(ctx, cacheKey, "data", 0)
+
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET role = \\$1 WHERE budget_id = \\$2 AND user_id = \\$3 AND role <> 'owner'", MembersTable)).
+				WithArgs("viewer", "456", "789").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			updated, err := repo.UpdateMemberRole(ctx, "456", "789", "viewer")
+			assert.NoError(t, err)
+			assert.True(t, updated)
+
+			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
+			assert.Equal(t, int64(0), exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET role", MembersTable)).
+				WithArgs("viewer", "456", "123").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			updated, err := repo.UpdateMemberRole(ctx, "456", "123", "viewer")
+			assert.NoError(t, err)
+			assert.False(t, updated)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("RemoveMember", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE budget_id = \\$1 AND user_id = \\$2 AND role <> 'owner'", MembersTable)).
+				WithArgs("456", "789").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			removed, err := repo.RemoveMember(ctx, "456", "789")
+			assert.NoError(t, err)
+			assert.True(t, removed)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("DELETE FROM %s", MembersTable)).
+				WithArgs("456", "789").
+				WillReturnError(errors.New("db error"))
+
+			removed, err := repo.RemoveMember(ctx, "456", "789")
+			assert.Error(t, err)
+			assert.False(t, removed)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("LockTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("SELECT id FROM %s WHERE id = \\$1 FOR UPDATE", BudgetTable)).
+				WithArgs("budget1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			tx, _ := sqlxDB.Beginx()
+			assert.NoError(t, repo.LockTX(ctx, tx, "budget1"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("CacheKeysByUserTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT m.budget_id, m.user_id FROM %s m", MembersTable)).
+				WithArgs("user1").
+				WillReturnRows(sqlmock.NewRows([]string{"budget_id", "user_id"}).
+					AddRow("budget1", "user1").
+					AddRow("budget1", "user2"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.CacheKeysByUserTX(ctx, tx, "user1")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{
+				fmt.Sprintf(cacheKeyBudgetsByUser, "user1"),
+				fmt.Sprintf(cacheKeyBudgetByIDAndUser, "budget1", "user1"),
+				fmt.Sprintf(cacheKeyBudgetsByUser, "user2"),
+				fmt.Sprintf(cacheKeyBudgetByIDAndUser, "budget1", "user2"),
+			}, keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
 }
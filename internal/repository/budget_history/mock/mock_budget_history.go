@@ -43,8 +43,23 @@ func (m *MockBudgetHistory) EXPECT() *MockBudgetHistoryMockRecorder {
 	return m.recorder
 }
 
+// CacheKeysByUserTX mocks base method.
+func (m *MockBudgetHistory) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheKeysByUserTX", ctx, tx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CacheKeysByUserTX indicates an expected call of CacheKeysByUserTX.
+func (mr *MockBudgetHistoryMockRecorder) CacheKeysByUserTX(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheKeysByUserTX", reflect.TypeOf((*MockBudgetHistory)(nil).CacheKeysByUserTX), ctx, tx, userID)
+}
+
 // Create mocks base method.
-func (m *MockBudgetHistory) Create(ctx context.Context, budgetID string, amount float64) (string, error) {
+func (m *MockBudgetHistory) Create(ctx context.Context, budgetID string, amount domain.Money) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, budgetID, amount)
 	ret0, _ := ret[0].(string)
@@ -58,8 +73,23 @@ func (mr *MockBudgetHistoryMockRecorder) Create(ctx, budgetID, amount any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBudgetHistory)(nil).Create), ctx, budgetID, amount)
 }
 
+// CreateAtTX mocks base method.
+func (m *MockBudgetHistory) CreateAtTX(ctx context.Context, tx *sqlx.Tx, budgetID, transactionID string, amount domain.Money, createdAt time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAtTX", ctx, tx, budgetID, transactionID, amount, createdAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAtTX indicates an expected call of CreateAtTX.
+func (mr *MockBudgetHistoryMockRecorder) CreateAtTX(ctx, tx, budgetID, transactionID, amount, createdAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAtTX", reflect.TypeOf((*MockBudgetHistory)(nil).CreateAtTX), ctx, tx, budgetID, transactionID, amount, createdAt)
+}
+
 // CreateInitialTX mocks base method.
-func (m *MockBudgetHistory) CreateInitialTX(ctx context.Context, tx *sqlx.Tx, budgetID string, amount float64) (string, error) {
+func (m *MockBudgetHistory) CreateInitialTX(ctx context.Context, tx *sqlx.Tx, budgetID string, amount domain.Money) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateInitialTX", ctx, tx, budgetID, amount)
 	ret0, _ := ret[0].(string)
@@ -74,7 +104,7 @@ func (mr *MockBudgetHistoryMockRecorder) CreateInitialTX(ctx, tx, budgetID, amou
 }
 
 // CreateTX mocks base method.
-func (m *MockBudgetHistory) CreateTX(ctx context.Context, tx *sqlx.Tx, budgetID, transactionID string, amount float64) (string, error) {
+func (m *MockBudgetHistory) CreateTX(ctx context.Context, tx *sqlx.Tx, budgetID, transactionID string, amount domain.Money) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateTX", ctx, tx, budgetID, transactionID, amount)
 	ret0, _ := ret[0].(string)
@@ -89,10 +119,10 @@ func (mr *MockBudgetHistoryMockRecorder) CreateTX(ctx, tx, budgetID, transaction
 }
 
 // GetCurrentBalance mocks base method.
-func (m *MockBudgetHistory) GetCurrentBalance(ctx context.Context, budgetID string) (float64, error) {
+func (m *MockBudgetHistory) GetCurrentBalance(ctx context.Context, budgetID string) (domain.Money, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetCurrentBalance", ctx, budgetID)
-	ret0, _ := ret[0].(float64)
+	ret0, _ := ret[0].(domain.Money)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -148,8 +178,37 @@ func (mr *MockBudgetHistoryMockRecorder) ListFromDate(ctx, budgetID, fromDate, i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFromDate", reflect.TypeOf((*MockBudgetHistory)(nil).ListFromDate), ctx, budgetID, fromDate, inclusive)
 }
 
+// ListSinceTX mocks base method.
+func (m *MockBudgetHistory) ListSinceTX(ctx context.Context, tx *sqlx.Tx, budgetID string, at time.Time) ([]*domain.BudgetHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSinceTX", ctx, tx, budgetID, at)
+	ret0, _ := ret[0].([]*domain.BudgetHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSinceTX indicates an expected call of ListSinceTX.
+func (mr *MockBudgetHistoryMockRecorder) ListSinceTX(ctx, tx, budgetID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSinceTX", reflect.TypeOf((*MockBudgetHistory)(nil).ListSinceTX), ctx, tx, budgetID, at)
+}
+
+// UpdateBalanceByIDTX mocks base method.
+func (m *MockBudgetHistory) UpdateBalanceByIDTX(ctx context.Context, tx *sqlx.Tx, budgetID, historyID string, amount domain.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBalanceByIDTX", ctx, tx, budgetID, historyID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBalanceByIDTX indicates an expected call of UpdateBalanceByIDTX.
+func (mr *MockBudgetHistoryMockRecorder) UpdateBalanceByIDTX(ctx, tx, budgetID, historyID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBalanceByIDTX", reflect.TypeOf((*MockBudgetHistory)(nil).UpdateBalanceByIDTX), ctx, tx, budgetID, historyID, amount)
+}
+
 // UpdateBalanceTX mocks base method.
-func (m *MockBudgetHistory) UpdateBalanceTX(ctx context.Context, tx *sqlx.Tx, transactionID string, amount float64) error {
+func (m *MockBudgetHistory) UpdateBalanceTX(ctx context.Context, tx *sqlx.Tx, transactionID string, amount domain.Money) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateBalanceTX", ctx, tx, transactionID, amount)
 	ret0, _ := ret[0].(error)
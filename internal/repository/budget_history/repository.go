@@ -3,6 +3,7 @@ package budget_history
 import (
 	"context"
 	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/budget"
 	"finly-backend/pkg/db"
 	"fmt"
 	"github.com/jmoiron/sqlx"
@@ -12,14 +13,18 @@ import (
 )
 
 type BudgetHistory interface {
-	Create(ctx context.Context, budgetID string, amount float64) (string, error)
-	CreateTX(ctx context.Context, tx *sqlx.Tx, budgetID, transactionID string, amount float64) (string, error)
-	CreateInitialTX(ctx context.Context, tx *sqlx.Tx, budgetID string, amount float64) (string, error)
+	Create(ctx context.Context, budgetID string, amount domain.Money) (string, error)
+	CreateTX(ctx context.Context, tx *sqlx.Tx, budgetID, transactionID string, amount domain.Money) (string, error)
+	CreateInitialTX(ctx context.Context, tx *sqlx.Tx, budgetID string, amount domain.Money) (string, error)
 	GetLastByBudgetID(ctx context.Context, budgetID string) (*domain.BudgetHistory, error)
 	List(ctx context.Context, budgetID string) ([]*domain.BudgetHistory, error)
 	ListFromDate(ctx context.Context, budgetID string, fromDate time.Time, inclusive bool) ([]*domain.BudgetHistory, error)
-	UpdateBalanceTX(ctx context.Context, tx *sqlx.Tx, transactionID string, amount float64) error
-	GetCurrentBalance(ctx context.Context, budgetID string) (float64, error)
+	UpdateBalanceTX(ctx context.Context, tx *sqlx.Tx, transactionID string, amount domain.Money) error
+	ListSinceTX(ctx context.Context, tx *sqlx.Tx, budgetID string, at time.Time) ([]*domain.BudgetHistory, error)
+	CreateAtTX(ctx context.Context, tx *sqlx.Tx, budgetID, transactionID string, amount domain.Money, createdAt time.Time) (string, error)
+	UpdateBalanceByIDTX(ctx context.Context, tx *sqlx.Tx, budgetID, historyID string, amount domain.Money) error
+	GetCurrentBalance(ctx context.Context, budgetID string) (domain.Money, error)
+	CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error)
 }
 
 const (
@@ -74,7 +79,7 @@ func (b BudgetHistoryRepository) InvalidateCache(ctx context.Context, budgetID s
 	return nil
 }
 
-func (b BudgetHistoryRepository) CreateInitialTX(ctx context.Context, tx *sqlx.Tx, budgetID string, amount float64) (string, error) {
+func (b BudgetHistoryRepository) CreateInitialTX(ctx context.Context, tx *sqlx.Tx, budgetID string, amount domain.Money) (string, error) {
 	query := fmt.Sprintf("INSERT INTO %s (budget_id, balance) VALUES ($1, $2) RETURNING id", BudgetHistoryTable)
 
 	var id string
@@ -91,7 +96,7 @@ func (b BudgetHistoryRepository) CreateInitialTX(ctx context.Context, tx *sqlx.T
 	return id, nil
 }
 
-func (b BudgetHistoryRepository) CreateTX(ctx context.Context, tx *sqlx.Tx, budgetID, transactionID string, amount float64) (string, error) {
+func (b BudgetHistoryRepository) CreateTX(ctx context.Context, tx *sqlx.Tx, budgetID, transactionID string, amount domain.Money) (string, error) {
 	query := fmt.Sprintf("INSERT INTO %s (budget_id, balance, transaction_id) VALUES ($1, $2, $3) RETURNING id", BudgetHistoryTable)
 
 	var id string
@@ -132,7 +137,7 @@ func (b BudgetHistoryRepository) GetLastByBudgetID(ctx context.Context, budgetID
 	return result, nil
 }
 
-func (b BudgetHistoryRepository) Create(ctx context.Context, budgetID string, amount float64) (string, error) {
+func (b BudgetHistoryRepository) Create(ctx context.Context, budgetID string, amount domain.Money) (string, error) {
 	query := fmt.Sprintf("INSERT INTO %s (budget_id, balance) VALUES ($1, $2) RETURNING id", BudgetHistoryTable)
 
 	var id string
@@ -206,7 +211,7 @@ func (b BudgetHistoryRepository) ListFromDate(ctx context.Context, budgetID stri
 	return result, nil
 }
 
-func (b BudgetHistoryRepository) UpdateBalanceTX(ctx context.Context, tx *sqlx.Tx, transactionID string, amount float64) error {
+func (b BudgetHistoryRepository) UpdateBalanceTX(ctx context.Context, tx *sqlx.Tx, transactionID string, amount domain.Money) error {
 	query := fmt.Sprintf("UPDATE %s SET balance = $1 WHERE transaction_id = $2", BudgetHistoryTable)
 
 	if _, err := tx.ExecContext(ctx, query, amount, transactionID); err != nil {
@@ -218,16 +223,80 @@ func (b BudgetHistoryRepository) UpdateBalanceTX(ctx context.Context, tx *sqlx.T
 	return nil
 }
 
-func (b BudgetHistoryRepository) GetCurrentBalance(ctx context.Context, budgetID string) (float64, error) {
+// ListSinceTX locks and returns the entry in effect at the given time followed by every later entry,
+// oldest first. It is used to book back-dated transactions into the middle of the balance history.
+func (b BudgetHistoryRepository) ListSinceTX(ctx context.Context, tx *sqlx.Tx, budgetID string, at time.Time) ([]*domain.BudgetHistory, error) {
+	var histories []*domain.BudgetHistory
+	query := fmt.Sprintf(`SELECT * FROM %[1]s WHERE budget_id = $1 AND created_at >= COALESCE(
+		(SELECT MAX(created_at) FROM %[1]s WHERE budget_id = $1 AND created_at <= $2), '-infinity')
+		ORDER BY created_at ASC FOR UPDATE`, BudgetHistoryTable)
+	if err := tx.SelectContext(ctx, &histories, query, budgetID, at); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch budget history since %v, budgetID: %s, error: %v", at, budgetID, err)
+		return nil, err
+	}
+	return histories, nil
+}
+
+func (b BudgetHistoryRepository) CreateAtTX(ctx context.Context, tx *sqlx.Tx, budgetID, transactionID string, amount domain.Money, createdAt time.Time) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (budget_id, balance, transaction_id, created_at) VALUES ($1, $2, $3, $4) RETURNING id", BudgetHistoryTable)
+
+	var id string
+	if err := tx.QueryRowContext(ctx, query, budgetID, amount, transactionID, createdAt).Scan(&id); err != nil {
+		zap.L().Sugar().Errorf("Failed to create budget history, budgetID: %s, transactionID: %s, error: %v", budgetID, transactionID, err)
+		return "", err
+	}
+
+	if err := b.InvalidateCache(ctx, budgetID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after create, budgetID: %s, transactionID: %s, error: %v", budgetID, transactionID, err)
+	}
+
+	return id, nil
+}
+
+// UpdateBalanceByIDTX updates a single entry, including the opening entry that has no transaction.
+func (b BudgetHistoryRepository) UpdateBalanceByIDTX(ctx context.Context, tx *sqlx.Tx, budgetID, historyID string, amount domain.Money) error {
+	query := fmt.Sprintf("UPDATE %s SET balance = $1 WHERE id = $2 AND budget_id = $3", BudgetHistoryTable)
+
+	if _, err := tx.ExecContext(ctx, query, amount, historyID, budgetID); err != nil {
+		zap.L().Sugar().Errorf("Failed to update balance for historyID: %s, error: %v", historyID, err)
+		return err
+	}
+
+	if err := b.InvalidateCache(ctx, budgetID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after balance update, budgetID: %s, error: %v", budgetID, err)
+	}
+
+	return nil
+}
+
+func (b BudgetHistoryRepository) GetCurrentBalance(ctx context.Context, budgetID string) (domain.Money, error) {
 	if budgetID == "" {
-		return 0, fmt.Errorf("budgetID cannot be empty")
+		return domain.Money{}, fmt.Errorf("budgetID cannot be empty")
 	}
 
-	var balance float64
+	var balance domain.Money
 	query := fmt.Sprintf("SELECT balance FROM %s WHERE budget_id = $1 ORDER BY created_at DESC LIMIT 1", BudgetHistoryTable)
 	if err := b.postgres.GetContext(ctx, &balance, query, budgetID); err != nil {
 		zap.L().Sugar().Errorf("Failed to fetch current balance from DB, budgetID: %s, error: %v", budgetID, err)
-		return 0, err
+		return domain.Money{}, err
 	}
 	return balance, nil
 }
+
+// CacheKeysByUserTX lists the cache keys of the history of every budget of the user.
+// Histories cached from a date are left to expire, as their keys can't be listed and
+// nobody can read them once the user is gone.
+func (b BudgetHistoryRepository) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	var ids []string
+	query := fmt.Sprintf("SELECT id FROM %s WHERE user_id = $1", budget.BudgetTable)
+	if err := tx.SelectContext(ctx, &ids, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to list budget IDs, userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+
+	var keys []string
+	for _, id := range ids {
+		keys = append(keys, b.cacheKeys(id)...)
+	}
+	return keys, nil
+}
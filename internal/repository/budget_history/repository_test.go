@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/budget"
 	"finly-backend/pkg/testutil"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
@@ -92,7 +93,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 
 		t.Run("Success", func(t *testing.T) {
 			budgetID := "123"
-			amount := 100.0
+			amount := domain.MustParseMoney("100.00")
 			historyID := "456"
 			cacheKey := fmt.Sprintf(cacheKeyLastHistory, budgetID)
 
@@ -116,7 +117,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 
 		t.Run("DatabaseError", func(t *testing.T) {
 			budgetID := "123"
-			amount := 100.0
+			amount := domain.MustParseMoney("100.00")
 
 			mock.ExpectBegin()
 			query := fmt.Sprintf("INSERT INTO %s \\(budget_id, balance\\) VALUES \\(\\$1, \\$2\\) RETURNING id", BudgetHistoryTable)
@@ -149,7 +150,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 		t.Run("Success", func(t *testing.T) {
 			budgetID := "123"
 			transactionID := "789"
-			amount := 100.0
+			amount := domain.MustParseMoney("100.00")
 			historyID := "456"
 			cacheKey := fmt.Sprintf(cacheKeyLastHistory, budgetID)
 
@@ -174,7 +175,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 		t.Run("DatabaseError", func(t *testing.T) {
 			budgetID := "123"
 			transactionID := "789"
-			amount := 100.0
+			amount := domain.MustParseMoney("100.00")
 
 			mock.ExpectBegin()
 			query := fmt.Sprintf("INSERT INTO %s \\(budget_id, balance, transaction_id\\) VALUES \\(\\$1, \\$2, \\$3\\) RETURNING id", BudgetHistoryTable)
@@ -207,7 +208,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 		t.Run("CacheHit", func(t *testing.T) {
 			budgetID := "123"
 			cacheKey := fmt.Sprintf(cacheKeyLastHistory, budgetID)
-			history := &domain.BudgetHistory{ID: "456", BudgetID: budgetID, Balance: 100.0}
+			history := &domain.BudgetHistory{ID: "456", BudgetID: budgetID, Balance: domain.MustParseMoney("100.00")}
 
 			data, err := json.Marshal(history)
 			assert.NoError(t, err)
@@ -223,7 +224,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 
 		t.Run("CacheMiss", func(t *testing.T) {
 			budgetID := "1234"
-			history := &domain.BudgetHistory{ID: "456", BudgetID: budgetID, Balance: 100.0}
+			history := &domain.BudgetHistory{ID: "456", BudgetID: budgetID, Balance: domain.MustParseMoney("100.00")}
 			cacheKey := fmt.Sprintf(cacheKeyLastHistory, budgetID)
 
 			query := fmt.Sprintf("SELECT \\* FROM %s WHERE budget_id = \\$1 ORDER BY created_at DESC LIMIT 1", BudgetHistoryTable)
@@ -260,7 +261,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 
 		t.Run("Success", func(t *testing.T) {
 			budgetID := "123"
-			amount := 100.0
+			amount := domain.MustParseMoney("100.00")
 			historyID := "456"
 			cacheKey := fmt.Sprintf(cacheKeyLastHistory, budgetID)
 
@@ -280,7 +281,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 
 		t.Run("DatabaseError", func(t *testing.T) {
 			budgetID := "123"
-			amount := 100.0
+			amount := domain.MustParseMoney("100.00")
 
 			query := fmt.Sprintf("INSERT INTO %s \\(budget_id, balance\\) VALUES \\(\\$1, \\$2\\) RETURNING id", BudgetHistoryTable)
 			mock.ExpectQuery(query).
@@ -306,7 +307,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 			budgetID := "123"
 			cacheKey := fmt.Sprintf(cacheKeyListHistory, budgetID)
 			histories := []*domain.BudgetHistory{
-				{ID: "456", BudgetID: budgetID, Balance: 100.0},
+				{ID: "456", BudgetID: budgetID, Balance: domain.MustParseMoney("100.00")},
 			}
 
 			data, err := json.Marshal(histories)
@@ -325,7 +326,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 			budgetID := "12345"
 			cacheKey := fmt.Sprintf(cacheKeyListHistory, budgetID)
 			histories := []*domain.BudgetHistory{
-				{ID: "456", BudgetID: budgetID, Balance: 100.0},
+				{ID: "456", BudgetID: budgetID, Balance: domain.MustParseMoney("100.00")},
 			}
 
 			query := fmt.Sprintf("SELECT \\* FROM %s WHERE budget_id = \\$1 ORDER BY created_at ASC", BudgetHistoryTable)
@@ -366,7 +367,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 			inclusive := true
 			cacheKey := fmt.Sprintf(cacheKeyHistoryFrom, budgetID, fromDate.Unix(), inclusive)
 			histories := []*domain.BudgetHistory{
-				{ID: "456", BudgetID: budgetID, Balance: 100.0},
+				{ID: "456", BudgetID: budgetID, Balance: domain.MustParseMoney("100.00")},
 			}
 
 			data, err := json.Marshal(histories)
@@ -386,7 +387,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 			fromDate := time.Now()
 			inclusive := true
 			histories := []*domain.BudgetHistory{
-				{ID: "456", BudgetID: budgetID, Balance: 100.0},
+				{ID: "456", BudgetID: budgetID, Balance: domain.MustParseMoney("100.00")},
 			}
 
 			query := fmt.Sprintf("SELECT \\* FROM %s WHERE budget_id = \\$1 AND created_at \\>= \\$2 ORDER BY created_at ASC", BudgetHistoryTable)
@@ -423,7 +424,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 
 		t.Run("Success", func(t *testing.T) {
 			transactionID := "789"
-			amount := 100.0
+			amount := domain.MustParseMoney("100.00")
 
 			mock.ExpectBegin()
 			query := fmt.Sprintf("UPDATE %s SET balance = \\$1 WHERE transaction_id = \\$2", BudgetHistoryTable)
@@ -441,7 +442,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 
 		t.Run("DatabaseError", func(t *testing.T) {
 			transactionID := "789"
-			amount := 100.0
+			amount := domain.MustParseMoney("100.00")
 
 			mock.ExpectBegin()
 			query := fmt.Sprintf("UPDATE %s SET balance = \\$1 WHERE transaction_id = \\$2", BudgetHistoryTable)
@@ -472,7 +473,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 
 		t.Run("Success", func(t *testing.T) {
 			budgetID := "123"
-			expectedBalance := 100.0
+			expectedBalance := domain.MustParseMoney("100.00")
 
 			query := fmt.Sprintf("SELECT balance FROM %s WHERE budget_id = \\$1 ORDER BY created_at DESC LIMIT 1", BudgetHistoryTable)
 			mock.ExpectQuery(query).
@@ -488,7 +489,7 @@ func TestBudgetHistoryRepository(t *testing.T) {
 		t.Run("EmptyBudgetID", func(t *testing.T) {
 			result, err := repo.GetCurrentBalance(ctx, "")
 			assert.Error(t, err)
-			assert.Equal(t, 0.0, result)
+			assert.Equal(t, domain.Money{}, result)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 
@@ -502,7 +503,113 @@ func TestBudgetHistoryRepository(t *testing.T) {
 
 			result, err := repo.GetCurrentBalance(ctx, budgetID)
 			assert.Error(t, err)
-			assert.Equal(t, 0.0, result)
+			assert.Equal(t, domain.Money{}, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ListSinceTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetHistoryRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			at := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE budget_id = \\$1 AND created_at >= COALESCE", BudgetHistoryTable)).
+				WithArgs("123", at).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "budget_id", "balance"}).
+					AddRow("h0", "123", "100.00").
+					AddRow("h1", "123", "80.00"))
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			result, err := repo.ListSinceTX(ctx, tx, "123", at)
+			assert.NoError(t, err)
+			assert.Len(t, result, 2)
+			assert.Equal(t, domain.MustParseMoney("80.00"), result[1].Balance)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("CreateAtTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetHistoryRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			at := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+			amount := domain.MustParseMoney("1000.50")
+			redisClient.Set(ctx, fmt.Sprintf(cacheKeyLastHistory, "123"), "data", 0)
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s \\(budget_id, balance, transaction_id, created_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\) RETURNING id", BudgetHistoryTable)).
+				WithArgs("123", amount, "789", at).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("456"))
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			id, err := repo.CreateAtTX(ctx, tx, "123", "789", amount, at)
+			assert.NoError(t, err)
+			assert.Equal(t, "456", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(cacheKeyLastHistory, "123")).Result()
+			assert.Equal(t, int64(0), exists)
+		})
+	})
+
+	t.Run("UpdateBalanceByIDTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetHistoryRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			amount := domain.MustParseMoney("1100.50")
+
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET balance = \\$1 WHERE id = \\$2 AND budget_id = \\$3", BudgetHistoryTable)).
+				WithArgs(amount, "h0", "123").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			err = repo.UpdateBalanceByIDTX(ctx, tx, "123", "h0", amount)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+	t.Run("CacheKeysByUserTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetHistoryRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT id FROM %s WHERE user_id", budget.BudgetTable)).
+				WithArgs("user1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("budget1"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.CacheKeysByUserTX(ctx, tx, "user1")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{fmt.Sprintf(cacheKeyLastHistory, "budget1"), fmt.Sprintf(cacheKeyListHistory, "budget1"), fmt.Sprintf(cacheKeyBalance, "budget1")}, keys)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	})
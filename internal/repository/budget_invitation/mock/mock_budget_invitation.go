@@ -0,0 +1,134 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/budget_invitation/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/budget_invitation/repository.go -destination=internal/repository/budget_invitation/mock/mock_budget_invitation.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "finly-backend/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	sqlx "github.com/jmoiron/sqlx"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBudgetInvitation is a mock of BudgetInvitation interface.
+type MockBudgetInvitation struct {
+	ctrl     *gomock.Controller
+	recorder *MockBudgetInvitationMockRecorder
+	isgomock struct{}
+}
+
+// MockBudgetInvitationMockRecorder is the mock recorder for MockBudgetInvitation.
+type MockBudgetInvitationMockRecorder struct {
+	mock *MockBudgetInvitation
+}
+
+// NewMockBudgetInvitation creates a new mock instance.
+func NewMockBudgetInvitation(ctrl *gomock.Controller) *MockBudgetInvitation {
+	mock := &MockBudgetInvitation{ctrl: ctrl}
+	mock.recorder = &MockBudgetInvitationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBudgetInvitation) EXPECT() *MockBudgetInvitationMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBudgetInvitation) Create(ctx context.Context, budgetID, invitedBy, email, role string, expiresAt time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, budgetID, invitedBy, email, role, expiresAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBudgetInvitationMockRecorder) Create(ctx, budgetID, invitedBy, email, role, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBudgetInvitation)(nil).Create), ctx, budgetID, invitedBy, email, role, expiresAt)
+}
+
+// Delete mocks base method.
+func (m *MockBudgetInvitation) Delete(ctx context.Context, invitationID, budgetID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, invitationID, budgetID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBudgetInvitationMockRecorder) Delete(ctx, invitationID, budgetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBudgetInvitation)(nil).Delete), ctx, invitationID, budgetID)
+}
+
+// GetByID mocks base method.
+func (m *MockBudgetInvitation) GetByID(ctx context.Context, invitationID string) (*domain.BudgetInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, invitationID)
+	ret0, _ := ret[0].(*domain.BudgetInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockBudgetInvitationMockRecorder) GetByID(ctx, invitationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBudgetInvitation)(nil).GetByID), ctx, invitationID)
+}
+
+// ListPendingByBudget mocks base method.
+func (m *MockBudgetInvitation) ListPendingByBudget(ctx context.Context, budgetID string, now time.Time) ([]*domain.BudgetInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingByBudget", ctx, budgetID, now)
+	ret0, _ := ret[0].([]*domain.BudgetInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingByBudget indicates an expected call of ListPendingByBudget.
+func (mr *MockBudgetInvitationMockRecorder) ListPendingByBudget(ctx, budgetID, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingByBudget", reflect.TypeOf((*MockBudgetInvitation)(nil).ListPendingByBudget), ctx, budgetID, now)
+}
+
+// ListPendingByEmail mocks base method.
+func (m *MockBudgetInvitation) ListPendingByEmail(ctx context.Context, email string, now time.Time) ([]*domain.BudgetInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingByEmail", ctx, email, now)
+	ret0, _ := ret[0].([]*domain.BudgetInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingByEmail indicates an expected call of ListPendingByEmail.
+func (mr *MockBudgetInvitationMockRecorder) ListPendingByEmail(ctx, email, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingByEmail", reflect.TypeOf((*MockBudgetInvitation)(nil).ListPendingByEmail), ctx, email, now)
+}
+
+// RespondTX mocks base method.
+func (m *MockBudgetInvitation) RespondTX(ctx context.Context, tx *sqlx.Tx, invitationID, email, status string, now time.Time) (*domain.BudgetInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondTX", ctx, tx, invitationID, email, status, now)
+	ret0, _ := ret[0].(*domain.BudgetInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondTX indicates an expected call of RespondTX.
+func (mr *MockBudgetInvitationMockRecorder) RespondTX(ctx, tx, invitationID, email, status, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondTX", reflect.TypeOf((*MockBudgetInvitation)(nil).RespondTX), ctx, tx, invitationID, email, status, now)
+}
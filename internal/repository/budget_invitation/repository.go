@@ -0,0 +1,145 @@
+package budget_invitation
+
+import (
+	"context"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/auth"
+	"finly-backend/internal/repository/budget"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"time"
+)
+
+type BudgetInvitation interface {
+	Create(ctx context.Context, budgetID, invitedBy, email, role string, expiresAt time.Time) (string, error)
+	GetByID(ctx context.Context, invitationID string) (*domain.BudgetInvitation, error)
+	ListPendingByEmail(ctx context.Context, email string, now time.Time) ([]*domain.BudgetInvitation, error)
+	ListPendingByBudget(ctx context.Context, budgetID string, now time.Time) ([]*domain.BudgetInvitation, error)
+	RespondTX(ctx context.Context, tx *sqlx.Tx, invitationID, email, status string, now time.Time) (*domain.BudgetInvitation, error)
+	Delete(ctx context.Context, invitationID, budgetID string) (bool, error)
+}
+
+const (
+	BudgetInvitationTable = "budget_invitations"
+
+	StatusPending  = "pending"
+	StatusAccepted = "accepted"
+	StatusDeclined = "declined"
+)
+
+// columns joins in the budget name and the inviter, so the invitee knows what they are
+// accepting. Queries name the invitations i, the budgets b and the inviters u.
+const columns = `i.id, i.budget_id, b.name AS budget_name, i.invited_by, u.email AS inviter_email,
+	i.email, i.role, i.status, i.expires_at, i.responded_at, i.created_at`
+
+// BudgetInvitationRepository is not cached, as invitations are read far less often than
+// they change.
+type BudgetInvitationRepository struct {
+	postgres *sqlx.DB
+	redis    *redis.Client
+}
+
+func NewBudgetInvitationRepository(postgres *sqlx.DB, redis *redis.Client) *BudgetInvitationRepository {
+	return &BudgetInvitationRepository{
+		postgres: postgres,
+		redis:    redis,
+	}
+}
+
+func joins(from string) string {
+	return fmt.Sprintf("%s i JOIN %s b ON b.id = i.budget_id JOIN %s u ON u.id = i.invited_by",
+		from, budget.BudgetTable, auth.UsersTable)
+}
+
+// Create invites the email to the budget. Inviting an email that already has a pending
+// invitation renews that invitation with the new role and expiry.
+func (r *BudgetInvitationRepository) Create(ctx context.Context, budgetID, invitedBy, email, role string, expiresAt time.Time) (string, error) {
+	query := fmt.Sprintf(`INSERT INTO %s (budget_id, invited_by, email, role, expires_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (budget_id, lower(email)) WHERE status = '%s'
+		DO UPDATE SET invited_by = EXCLUDED.invited_by, role = EXCLUDED.role, expires_at = EXCLUDED.expires_at, created_at = CURRENT_TIMESTAMP
+		RETURNING id`, BudgetInvitationTable, StatusPending)
+
+	var id string
+	if err := r.postgres.QueryRowContext(ctx, query, budgetID, invitedBy, email, role, expiresAt).Scan(&id); err != nil {
+		zap.L().Sugar().Errorf("Failed to create budget invitation, budgetID: %s, error: %v", budgetID, err)
+		return "", err
+	}
+
+	zap.L().Sugar().Infof("Budget invitation created, invitationID: %s, budgetID: %s", id, budgetID)
+	return id, nil
+}
+
+func (r *BudgetInvitationRepository) GetByID(ctx context.Context, invitationID string) (*domain.BudgetInvitation, error) {
+	var invitation domain.BudgetInvitation
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE i.id = $1", columns, joins(BudgetInvitationTable))
+	if err := r.postgres.GetContext(ctx, &invitation, query, invitationID); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch budget invitation, invitationID: %s, error: %v", invitationID, err)
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// ListPendingByEmail lists the invitations sent to the email that can still be answered.
+func (r *BudgetInvitationRepository) ListPendingByEmail(ctx context.Context, email string, now time.Time) ([]*domain.BudgetInvitation, error) {
+	var invitations []*domain.BudgetInvitation
+	query := fmt.Sprintf(`SELECT %s FROM %s
+		WHERE lower(i.email) = lower($1) AND i.status = $2 AND i.expires_at > $3
+		ORDER BY i.created_at DESC`, columns, joins(BudgetInvitationTable))
+	if err := r.postgres.SelectContext(ctx, &invitations, query, email, StatusPending, now); err != nil {
+		zap.L().Sugar().Errorf("Failed to list budget invitations by email, error: %v", err)
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// ListPendingByBudget lists the invitations to the budget that can still be answered.
+func (r *BudgetInvitationRepository) ListPendingByBudget(ctx context.Context, budgetID string, now time.Time) ([]*domain.BudgetInvitation, error) {
+	var invitations []*domain.BudgetInvitation
+	query := fmt.Sprintf(`SELECT %s FROM %s
+		WHERE i.budget_id = $1 AND i.status = $2 AND i.expires_at > $3
+		ORDER BY i.created_at DESC`, columns, joins(BudgetInvitationTable))
+	if err := r.postgres.SelectContext(ctx, &invitations, query, budgetID, StatusPending, now); err != nil {
+		zap.L().Sugar().Errorf("Failed to list budget invitations, budgetID: %s, error: %v", budgetID, err)
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// RespondTX accepts or declines a pending invitation sent to the email. It returns
+// sql.ErrNoRows when there is no such invitation, it was already answered or it expired.
+func (r *BudgetInvitationRepository) RespondTX(ctx context.Context, tx *sqlx.Tx, invitationID, email, status string, now time.Time) (*domain.BudgetInvitation, error) {
+	query := fmt.Sprintf(`WITH responded AS (
+			UPDATE %s SET status = $1, responded_at = $2
+			WHERE id = $3 AND lower(email) = lower($4) AND status = $5 AND expires_at > $2
+			RETURNING *
+		)
+		SELECT %s FROM %s`, BudgetInvitationTable, columns, joins("responded"))
+
+	var invitation domain.BudgetInvitation
+	if err := tx.GetContext(ctx, &invitation, query, status, now, invitationID, email, StatusPending); err != nil {
+		zap.L().Sugar().Errorf("Failed to set status=%s of budget invitation, invitationID: %s, error: %v", status, invitationID, err)
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Budget invitation %s, invitationID: %s", status, invitationID)
+	return &invitation, nil
+}
+
+// Delete revokes a pending invitation to the budget. It returns false when there is no such
+// invitation.
+func (r *BudgetInvitationRepository) Delete(ctx context.Context, invitationID, budgetID string) (bool, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND budget_id = $2 AND status = $3", BudgetInvitationTable)
+	res, err := r.postgres.ExecContext(ctx, query, invitationID, budgetID, StatusPending)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to delete budget invitation, invitationID: %s, error: %v", invitationID, err)
+		return false, err
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
@@ -0,0 +1,127 @@
+package budget_invitation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/pkg/testutil"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestBudgetInvitationRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	rowColumns := []string{"id", "budget_id", "budget_name", "invited_by", "inviter_email", "email", "role", "status", "expires_at", "responded_at", "created_at"}
+
+	t.Run("Create", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetInvitationRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s .* ON CONFLICT \\(budget_id, lower\\(email\\)\\) WHERE status = 'pending'", BudgetInvitationTable)).
+				WithArgs("budget1", "user1", "friend@example.com", "editor", now).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("invitation1"))
+
+			id, err := repo.Create(ctx, "budget1", "user1", "friend@example.com", "editor", now)
+			assert.NoError(t, err)
+			assert.Equal(t, "invitation1", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", BudgetInvitationTable)).
+				WithArgs("budget1", "user1", "friend@example.com", "editor", now).
+				WillReturnError(errors.New("db error"))
+
+			id, err := repo.Create(ctx, "budget1", "user1", "friend@example.com", "editor", now)
+			assert.Error(t, err)
+			assert.Empty(t, id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ListPendingByEmail", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetInvitationRepository(sqlxDB, redisClient)
+
+		mock.ExpectQuery("WHERE lower\\(i.email\\) = lower\\(\\$1\\) AND i.status = \\$2 AND i.expires_at > \\$3").
+			WithArgs("Friend@example.com", StatusPending, now).
+			WillReturnRows(sqlmock.NewRows(rowColumns).
+				AddRow("invitation1", "budget1", "Household", "user1", "owner@example.com", "friend@example.com", "editor", StatusPending, now.Add(time.Hour), nil, now))
+
+		invitations, err := repo.ListPendingByEmail(ctx, "Friend@example.com", now)
+		assert.NoError(t, err)
+		assert.Len(t, invitations, 1)
+		assert.Equal(t, "Household", invitations[0].BudgetName)
+		assert.Equal(t, "owner@example.com", invitations[0].InviterEmail)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("RespondTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetInvitationRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s SET status = \\$1, responded_at = \\$2", BudgetInvitationTable)).
+				WithArgs(StatusAccepted, now, "invitation1", "friend@example.com", StatusPending).
+				WillReturnRows(sqlmock.NewRows(rowColumns).
+					AddRow("invitation1", "budget1", "Household", "user1", "owner@example.com", "friend@example.com", "viewer", StatusAccepted, now.Add(time.Hour), now, now))
+
+			tx, _ := sqlxDB.Beginx()
+			invitation, err := repo.RespondTX(ctx, tx, "invitation1", "friend@example.com", StatusAccepted, now)
+			assert.NoError(t, err)
+			assert.Equal(t, "budget1", invitation.BudgetID)
+			assert.Equal(t, "viewer", invitation.Role)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NotPending", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s SET status", BudgetInvitationTable)).
+				WithArgs(StatusDeclined, now, "invitation1", "friend@example.com", StatusPending).
+				WillReturnError(sql.ErrNoRows)
+
+			tx, _ := sqlxDB.Beginx()
+			invitation, err := repo.RespondTX(ctx, tx, "invitation1", "friend@example.com", StatusDeclined, now)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			assert.Nil(t, invitation)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewBudgetInvitationRepository(sqlxDB, redisClient)
+
+		mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND budget_id = \\$2 AND status = \\$3", BudgetInvitationTable)).
+			WithArgs("invitation1", "budget1", StatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		deleted, err := repo.Delete(ctx, "invitation1", "budget1")
+		assert.NoError(t, err)
+		assert.False(t, deleted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: internal/repository/category/category.go
+// Source: internal/repository/category/repository.go
 //
 // Generated by this command:
 //
-//	mockgen -source=internal/repository/category/category.go -destination=internal/repository/category/mock/mock_category.go -package=mock
+//	mockgen -source=internal/repository/category/repository.go -destination=internal/repository/category/mock/mock_category.go -package=mock
 //
 
 // Package mock is a generated GoMock package.
@@ -11,9 +11,11 @@ package mock
 
 import (
 	context "context"
+	sql "database/sql"
 	domain "finly-backend/internal/domain"
 	reflect "reflect"
 
+	sqlx "github.com/jmoiron/sqlx"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,19 +43,34 @@ func (m *MockCategory) EXPECT() *MockCategoryMockRecorder {
 	return m.recorder
 }
 
+// CacheKeysByUserTX mocks base method.
+func (m *MockCategory) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheKeysByUserTX", ctx, tx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CacheKeysByUserTX indicates an expected call of CacheKeysByUserTX.
+func (mr *MockCategoryMockRecorder) CacheKeysByUserTX(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheKeysByUserTX", reflect.TypeOf((*MockCategory)(nil).CacheKeysByUserTX), ctx, tx, userID)
+}
+
 // Create mocks base method.
-func (m *MockCategory) Create(ctx context.Context, userID, name string) (string, error) {
+func (m *MockCategory) Create(ctx context.Context, category *domain.Category) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, userID, name)
+	ret := m.ctrl.Call(m, "Create", ctx, category)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockCategoryMockRecorder) Create(ctx, userID, name any) *gomock.Call {
+func (mr *MockCategoryMockRecorder) Create(ctx, category any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCategory)(nil).Create), ctx, userID, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCategory)(nil).Create), ctx, category)
 }
 
 // Delete mocks base method.
@@ -70,6 +87,21 @@ func (mr *MockCategoryMockRecorder) Delete(ctx, categoryID, userID any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCategory)(nil).Delete), ctx, categoryID, userID)
 }
 
+// DeleteTX mocks base method.
+func (m *MockCategory) DeleteTX(ctx context.Context, tx *sqlx.Tx, categoryID, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTX", ctx, tx, categoryID, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTX indicates an expected call of DeleteTX.
+func (mr *MockCategoryMockRecorder) DeleteTX(ctx, tx, categoryID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTX", reflect.TypeOf((*MockCategory)(nil).DeleteTX), ctx, tx, categoryID, userID)
+}
+
 // GetByID mocks base method.
 func (m *MockCategory) GetByID(ctx context.Context, categoryID, userID string) (*domain.Category, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +117,35 @@ func (mr *MockCategoryMockRecorder) GetByID(ctx, categoryID, userID any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCategory)(nil).GetByID), ctx, categoryID, userID)
 }
 
+// GetDB mocks base method.
+func (m *MockCategory) GetDB() *sqlx.DB {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDB")
+	ret0, _ := ret[0].(*sqlx.DB)
+	return ret0
+}
+
+// GetDB indicates an expected call of GetDB.
+func (mr *MockCategoryMockRecorder) GetDB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDB", reflect.TypeOf((*MockCategory)(nil).GetDB))
+}
+
+// IsDescendantTX mocks base method.
+func (m *MockCategory) IsDescendantTX(ctx context.Context, tx *sqlx.Tx, categoryID, ancestorID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDescendantTX", ctx, tx, categoryID, ancestorID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDescendantTX indicates an expected call of IsDescendantTX.
+func (mr *MockCategoryMockRecorder) IsDescendantTX(ctx, tx, categoryID, ancestorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDescendantTX", reflect.TypeOf((*MockCategory)(nil).IsDescendantTX), ctx, tx, categoryID, ancestorID)
+}
+
 // List mocks base method.
 func (m *MockCategory) List(ctx context.Context, userID string) ([]*domain.Category, error) {
 	m.ctrl.T.Helper()
@@ -114,3 +175,103 @@ func (mr *MockCategoryMockRecorder) ListCustom(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustom", reflect.TypeOf((*MockCategory)(nil).ListCustom), ctx, userID)
 }
+
+// LockTreeTX mocks base method.
+func (m *MockCategory) LockTreeTX(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockTreeTX", ctx, tx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockTreeTX indicates an expected call of LockTreeTX.
+func (mr *MockCategoryMockRecorder) LockTreeTX(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockTreeTX", reflect.TypeOf((*MockCategory)(nil).LockTreeTX), ctx, tx, userID)
+}
+
+// MoveTX mocks base method.
+func (m *MockCategory) MoveTX(ctx context.Context, tx *sqlx.Tx, categoryID, userID string, parentID sql.NullString) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTX", ctx, tx, categoryID, userID, parentID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveTX indicates an expected call of MoveTX.
+func (mr *MockCategoryMockRecorder) MoveTX(ctx, tx, categoryID, userID, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTX", reflect.TypeOf((*MockCategory)(nil).MoveTX), ctx, tx, categoryID, userID, parentID)
+}
+
+// Override mocks base method.
+func (m *MockCategory) Override(ctx context.Context, override *domain.CategoryOverride) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Override", ctx, override)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Override indicates an expected call of Override.
+func (mr *MockCategoryMockRecorder) Override(ctx, override any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Override", reflect.TypeOf((*MockCategory)(nil).Override), ctx, override)
+}
+
+// PurgeCache mocks base method.
+func (m *MockCategory) PurgeCache(ctx context.Context, userID string, keys []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeCache", ctx, userID, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeCache indicates an expected call of PurgeCache.
+func (mr *MockCategoryMockRecorder) PurgeCache(ctx, userID, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeCache", reflect.TypeOf((*MockCategory)(nil).PurgeCache), ctx, userID, keys)
+}
+
+// Reorder mocks base method.
+func (m *MockCategory) Reorder(ctx context.Context, userID string, categoryIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, userID, categoryIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockCategoryMockRecorder) Reorder(ctx, userID, categoryIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockCategory)(nil).Reorder), ctx, userID, categoryIDs)
+}
+
+// ResetOverride mocks base method.
+func (m *MockCategory) ResetOverride(ctx context.Context, categoryID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetOverride", ctx, categoryID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetOverride indicates an expected call of ResetOverride.
+func (mr *MockCategoryMockRecorder) ResetOverride(ctx, categoryID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetOverride", reflect.TypeOf((*MockCategory)(nil).ResetOverride), ctx, categoryID, userID)
+}
+
+// Update mocks base method.
+func (m *MockCategory) Update(ctx context.Context, category *domain.Category) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, category)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCategoryMockRecorder) Update(ctx, category any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCategory)(nil).Update), ctx, category)
+}
@@ -2,25 +2,40 @@ package category
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"finly-backend/internal/domain"
 	"finly-backend/pkg/db"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"time"
 )
 
 type Category interface {
-	Create(ctx context.Context, userID, name string) (string, error)
+	GetDB() *sqlx.DB
+	Create(ctx context.Context, category *domain.Category) (string, error)
 	GetByID(ctx context.Context, categoryID, userID string) (*domain.Category, error)
 	List(ctx context.Context, userID string) ([]*domain.Category, error)
 	ListCustom(ctx context.Context, userID string) ([]*domain.Category, error)
+	Update(ctx context.Context, category *domain.Category) (bool, error)
+	LockTreeTX(ctx context.Context, tx *sqlx.Tx, userID string) error
+	IsDescendantTX(ctx context.Context, tx *sqlx.Tx, categoryID, ancestorID string) (bool, error)
+	MoveTX(ctx context.Context, tx *sqlx.Tx, categoryID, userID string, parentID sql.NullString) ([]string, error)
 	Delete(ctx context.Context, categoryID, userID string) error
+	Override(ctx context.Context, override *domain.CategoryOverride) error
+	ResetOverride(ctx context.Context, categoryID, userID string) error
+	Reorder(ctx context.Context, userID string, categoryIDs []string) error
+	DeleteTX(ctx context.Context, tx *sqlx.Tx, categoryID, userID string) ([]string, error)
+	PurgeCache(ctx context.Context, userID string, keys []string) error
+	CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error)
 }
 
 const (
-	CategoryTable = "categories"
+	CategoryTable         = "categories"
+	CategoryOverrideTable = "category_overrides"
 
 	TTL_ListCategoriesCache       = 1 * time.Hour
 	TTL_ListCategoriesCustomCache = 1 * time.Hour
@@ -29,8 +44,28 @@ const (
 	cacheKeyCategoryByIDAndUser    = "category:%s:user:%s"
 	cacheKeyCategoriesByUser       = "categories:user:%s"
 	cacheKeyCustomCategoriesByUser = "categories:custom:user:%s"
+
+	// CacheKeyVersion holds a counter that is bumped whenever the category tree of a user
+	// changes, so reports that roll subcategories up to their parents are recomputed.
+	CacheKeyVersion = "categories:version:user:%s"
+
+	foreignKeyViolation = "23503"
 )
 
+// listQuery selects the categories matching where, with the overrides of the user $1 applied,
+// in the order the user chose and then oldest first.
+const listQuery = `SELECT c.id, c.user_id, c.parent_id,
+		COALESCE(o.name, c.name) AS name, COALESCE(o.icon, c.icon) AS icon, COALESCE(o.color, c.color) AS color,
+		c.is_user_category, COALESCE(o.hidden, false) AS hidden, o.position, c.created_at, c.updated_at
+	FROM ` + CategoryTable + ` c
+	LEFT JOIN ` + CategoryOverrideTable + ` o ON o.category_id = c.id AND o.user_id = $1
+	WHERE %s
+	ORDER BY o.position NULLS LAST, c.created_at, c.id`
+
+// ErrCategoryInUse is returned when a category to delete is still used by transactions or
+// recurring transactions.
+var ErrCategoryInUse = errors.New("category is in use")
+
 type CategoryRepository struct {
 	postgres *sqlx.DB
 	redis    *redis.Client
@@ -64,12 +99,41 @@ func (c *CategoryRepository) InvalidateCache(ctx context.Context, userID, catego
 	return nil
 }
 
-func (c *CategoryRepository) Create(ctx context.Context, userID, name string) (string, error) {
-	query := fmt.Sprintf("INSERT INTO %s (user_id, name) VALUES ($1, $2) RETURNING id", CategoryTable)
+// bumpVersion marks the category tree of the user as changed.
+func (c *CategoryRepository) bumpVersion(ctx context.Context, userID string) {
+	if err := c.redis.Incr(ctx, fmt.Sprintf(CacheKeyVersion, userID)).Err(); err != nil {
+		zap.L().Sugar().Warnf("Failed to bump category version for userID: %s, error: %v", userID, err)
+	}
+}
+
+// PurgeCache deletes keys collected while categories were merged or deleted in a database
+// transaction, and marks the category tree of the user as changed.
+func (c *CategoryRepository) PurgeCache(ctx context.Context, userID string, keys []string) error {
+	defer c.bumpVersion(ctx, userID)
+
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := c.redis.Del(ctx, keys...).Err(); err != nil {
+		zap.L().Sugar().Warnf("Failed to purge category cache for userID: %s, keys: %d, error: %v", userID, len(keys), err)
+		return err
+	}
+	return nil
+}
+
+func (c *CategoryRepository) GetDB() *sqlx.DB {
+	return c.postgres
+}
+
+func (c *CategoryRepository) Create(ctx context.Context, category *domain.Category) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, name, icon, color, parent_id) VALUES ($1, $2, $3, $4, $5) RETURNING id", CategoryTable)
 
 	var id string
-	if err := c.postgres.QueryRowContext(ctx, query, userID, name).Scan(&id); err != nil {
-		zap.L().Sugar().Errorf("Failed to create category for userID: %s, name: %s, error: %v", userID, name, err)
+	userID := category.UserID.String
+	if err := c.postgres.QueryRowContext(ctx, query,
+		userID, category.Name, category.Icon, category.Color, category.ParentID,
+	).Scan(&id); err != nil {
+		zap.L().Sugar().Errorf("Failed to create category for userID: %s, name: %s, error: %v", userID, category.Name, err)
 		return "", err
 	}
 
@@ -108,7 +172,7 @@ func (c *CategoryRepository) List(ctx context.Context, userID string) ([]*domain
 	cacheKey := fmt.Sprintf(cacheKeyCategoriesByUser, userID)
 	fetch := func() ([]*domain.Category, error) {
 		var categories []*domain.Category
-		query := fmt.Sprintf("SELECT * FROM %s WHERE (user_id = $1 AND is_user_category = true) OR is_user_category = false", CategoryTable)
+		query := fmt.Sprintf(listQuery, "(c.user_id = $1 AND c.is_user_category = true) OR c.is_user_category = false")
 		if err := c.postgres.SelectContext(ctx, &categories, query, userID); err != nil {
 			zap.L().Sugar().Errorf("Failed to fetch categories from DB for userID: %s, error: %v", userID, err)
 			return nil, err
@@ -119,14 +183,100 @@ func (c *CategoryRepository) List(ctx context.Context, userID string) ([]*domain
 	return db.WithCache(ctx, c.redis, cacheKey, TTL_ListCategoriesCache, fetch)
 }
 
+// Update saves the name, icon and color of a custom category of the user. It returns false
+// when there is no such category.
+func (c *CategoryRepository) Update(ctx context.Context, category *domain.Category) (bool, error) {
+	query := fmt.Sprintf(`UPDATE %s SET name = $1, icon = $2, color = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND user_id = $5 AND is_user_category = true`, CategoryTable)
+	userID := category.UserID.String
+	res, err := c.postgres.ExecContext(ctx, query, category.Name, category.Icon, category.Color, category.ID, userID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to update category for categoryID: %s, userID: %s, error: %v", category.ID, userID, err)
+		return false, err
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if updated == 0 {
+		return false, nil
+	}
+
+	if err = c.InvalidateCache(ctx, userID, category.ID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after update for categoryID: %s, userID: %s, error: %v", category.ID, userID, err)
+	}
+	c.bumpVersion(ctx, userID)
+
+	zap.L().Sugar().Infof("Updated categoryID: %s for userID: %s", category.ID, userID)
+	return true, nil
+}
+
+// LockTreeTX locks the custom categories of the user until tx ends, so that concurrent moves
+// of the user's categories see each other's result and cannot close a cycle together.
+func (c *CategoryRepository) LockTreeTX(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	query := fmt.Sprintf("SELECT id FROM %s WHERE user_id = $1 ORDER BY id FOR UPDATE", CategoryTable)
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to lock categories for userID: %s, error: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
+// IsDescendantTX reports whether categoryID is ancestorID or lies below it in the tree, as
+// stored within tx. The walk stops at a category it has already visited.
+func (c *CategoryRepository) IsDescendantTX(ctx context.Context, tx *sqlx.Tx, categoryID, ancestorID string) (bool, error) {
+	query := fmt.Sprintf(`WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, ARRAY[id] AS path FROM %[1]s WHERE id = $1
+			UNION ALL
+			SELECT c.id, c.parent_id, a.path || c.id FROM %[1]s c
+			JOIN ancestors a ON c.id = a.parent_id
+			WHERE NOT c.id = ANY(a.path)
+		)
+		SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)`, CategoryTable)
+
+	var descendant bool
+	if err := tx.QueryRowxContext(ctx, query, categoryID, ancestorID).Scan(&descendant); err != nil {
+		zap.L().Sugar().Errorf("Failed to walk category tree from categoryID: %s, error: %v", categoryID, err)
+		return false, err
+	}
+	return descendant, nil
+}
+
+// MoveTX makes a custom category of the user a subcategory of parentID, or a top-level
+// category when parentID is null. It returns sql.ErrNoRows when there is no such category,
+// and otherwise the cache keys to pass to PurgeCache once tx is committed.
+func (c *CategoryRepository) MoveTX(ctx context.Context, tx *sqlx.Tx, categoryID, userID string, parentID sql.NullString) ([]string, error) {
+	query := fmt.Sprintf(`UPDATE %s SET parent_id = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND user_id = $3 AND is_user_category = true`, CategoryTable)
+	res, err := tx.ExecContext(ctx, query, parentID, categoryID, userID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to move category for categoryID: %s, userID: %s, error: %v", categoryID, userID, err)
+		return nil, err
+	}
+
+	moved, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if moved == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	zap.L().Sugar().Infof("Moved categoryID: %s under parentID: %s for userID: %s", categoryID, parentID.String, userID)
+	return c.cacheKeys(userID, categoryID), nil
+}
+
+// Delete deletes a custom category of the user. Its subcategories are kept and become
+// top-level categories. ErrCategoryInUse is returned while transactions or recurring
+// transactions use the category.
 func (c *CategoryRepository) Delete(ctx context.Context, categoryID, userID string) error {
-	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2 AND is_user_category = true", CategoryTable)
-	if _, err := c.postgres.ExecContext(ctx, query, categoryID, userID); err != nil {
-		zap.L().Sugar().Errorf("Failed to delete category for categoryID: %s, userID: %s, error: %v", categoryID, userID, err)
+	keys, err := c.delete(ctx, c.postgres, categoryID, userID)
+	if err != nil {
 		return err
 	}
 
-	if err := c.InvalidateCache(ctx, userID, categoryID); err != nil {
+	if err = c.PurgeCache(ctx, userID, keys); err != nil {
 		zap.L().Sugar().Warnf("Failed to invalidate cache after delete for categoryID: %s, userID: %s, error: %v", categoryID, userID, err)
 	}
 
@@ -134,6 +284,103 @@ func (c *CategoryRepository) Delete(ctx context.Context, categoryID, userID stri
 	return nil
 }
 
+// Override saves the set fields of an override of a category for the user.
+func (c *CategoryRepository) Override(ctx context.Context, override *domain.CategoryOverride) error {
+	query := fmt.Sprintf(`INSERT INTO %[1]s AS o (user_id, category_id, name, icon, color, hidden)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, false))
+		ON CONFLICT (user_id, category_id) DO UPDATE SET
+			name = COALESCE($3, o.name),
+			icon = COALESCE($4, o.icon),
+			color = COALESCE($5, o.color),
+			hidden = COALESCE($6, o.hidden),
+			updated_at = CURRENT_TIMESTAMP`, CategoryOverrideTable)
+	if _, err := c.postgres.ExecContext(ctx, query,
+		override.UserID, override.CategoryID, override.Name, override.Icon, override.Color, override.Hidden,
+	); err != nil {
+		zap.L().Sugar().Errorf("Failed to override category for categoryID: %s, userID: %s, error: %v", override.CategoryID, override.UserID, err)
+		return err
+	}
+
+	if err := c.InvalidateCache(ctx, override.UserID, override.CategoryID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after override for categoryID: %s, userID: %s, error: %v", override.CategoryID, override.UserID, err)
+	}
+	c.bumpVersion(ctx, override.UserID)
+
+	zap.L().Sugar().Infof("Overrode categoryID: %s for userID: %s", override.CategoryID, override.UserID)
+	return nil
+}
+
+// ResetOverride shows a category to the user as it is again. Its position is kept.
+func (c *CategoryRepository) ResetOverride(ctx context.Context, categoryID, userID string) error {
+	query := fmt.Sprintf(`UPDATE %s SET name = NULL, icon = NULL, color = NULL, hidden = false, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND category_id = $2`, CategoryOverrideTable)
+	if _, err := c.postgres.ExecContext(ctx, query, userID, categoryID); err != nil {
+		zap.L().Sugar().Errorf("Failed to reset category override for categoryID: %s, userID: %s, error: %v", categoryID, userID, err)
+		return err
+	}
+
+	if err := c.InvalidateCache(ctx, userID, categoryID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after override reset for categoryID: %s, userID: %s, error: %v", categoryID, userID, err)
+	}
+	c.bumpVersion(ctx, userID)
+
+	zap.L().Sugar().Infof("Reset override of categoryID: %s for userID: %s", categoryID, userID)
+	return nil
+}
+
+// Reorder lists the given categories to the user in the given order, ahead of every other
+// category, which go back to being listed oldest first.
+func (c *CategoryRepository) Reorder(ctx context.Context, userID string, categoryIDs []string) error {
+	query := fmt.Sprintf(`WITH cleared AS (
+			UPDATE %[1]s SET position = NULL, updated_at = CURRENT_TIMESTAMP
+			WHERE user_id = $1 AND position IS NOT NULL AND NOT (category_id = ANY($2::uuid[]))
+		)
+		INSERT INTO %[1]s (user_id, category_id, position)
+		SELECT $1, id, ord - 1 FROM unnest($2::uuid[]) WITH ORDINALITY AS ordered (id, ord)
+		ON CONFLICT (user_id, category_id) DO UPDATE SET position = EXCLUDED.position, updated_at = CURRENT_TIMESTAMP`,
+		CategoryOverrideTable)
+	if _, err := c.postgres.ExecContext(ctx, query, userID, pq.Array(categoryIDs)); err != nil {
+		zap.L().Sugar().Errorf("Failed to reorder categories for userID: %s, error: %v", userID, err)
+		return err
+	}
+
+	if err := c.InvalidateCache(ctx, userID, ""); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after reorder for userID: %s, error: %v", userID, err)
+	}
+
+	zap.L().Sugar().Infof("Reordered %d categories for userID: %s", len(categoryIDs), userID)
+	return nil
+}
+
+// DeleteTX deletes a custom category of the user like Delete, within tx. It returns the cache
+// keys to pass to PurgeCache once tx is committed.
+func (c *CategoryRepository) DeleteTX(ctx context.Context, tx *sqlx.Tx, categoryID, userID string) ([]string, error) {
+	return c.delete(ctx, tx, categoryID, userID)
+}
+
+func (c *CategoryRepository) delete(ctx context.Context, q sqlx.QueryerContext, categoryID, userID string) ([]string, error) {
+	query := fmt.Sprintf(`WITH deleted AS (
+			DELETE FROM %[1]s WHERE id = $1 AND user_id = $2 AND is_user_category = true RETURNING id
+		)
+		SELECT c.id FROM %[1]s c JOIN deleted d ON c.parent_id = d.id`, CategoryTable)
+
+	var children []string
+	if err := sqlx.SelectContext(ctx, q, &children, query, categoryID, userID); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return nil, ErrCategoryInUse
+		}
+		zap.L().Sugar().Errorf("Failed to delete category for categoryID: %s, userID: %s, error: %v", categoryID, userID, err)
+		return nil, err
+	}
+
+	keys := c.cacheKeys(userID, categoryID)
+	for _, id := range children {
+		keys = append(keys, c.cacheKeys(userID, id)[0])
+	}
+	return keys, nil
+}
+
 func (c *CategoryRepository) ListCustom(ctx context.Context, userID string) ([]*domain.Category, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("userID cannot be empty")
@@ -142,7 +389,7 @@ func (c *CategoryRepository) ListCustom(ctx context.Context, userID string) ([]*
 	cacheKey := fmt.Sprintf(cacheKeyCustomCategoriesByUser, userID)
 	fetch := func() ([]*domain.Category, error) {
 		var categories []*domain.Category
-		query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1 AND is_user_category = true", CategoryTable)
+		query := fmt.Sprintf(listQuery, "c.user_id = $1 AND c.is_user_category = true")
 		if err := c.postgres.SelectContext(ctx, &categories, query, userID); err != nil {
 			zap.L().Sugar().Errorf("Failed to fetch custom categories from DB for userID: %s, error: %v", userID, err)
 			return nil, err
@@ -152,3 +399,20 @@ func (c *CategoryRepository) ListCustom(ctx context.Context, userID string) ([]*
 
 	return db.WithCache(ctx, c.redis, cacheKey, TTL_ListCategoriesCustomCache, fetch)
 }
+
+// CacheKeysByUserTX lists the cache keys of the user's categories. Default categories
+// are cached per user as well, so their keys are listed too.
+func (c *CategoryRepository) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	var ids []string
+	query := fmt.Sprintf("SELECT id FROM %s WHERE user_id = $1 OR user_id IS NULL", CategoryTable)
+	if err := tx.SelectContext(ctx, &ids, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to list category IDs, userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+
+	keys := append(c.cacheKeys(userID, "")[1:], fmt.Sprintf(CacheKeyVersion, userID))
+	for _, id := range ids {
+		keys = append(keys, c.cacheKeys(userID, id)[0])
+	}
+	return keys, nil
+}
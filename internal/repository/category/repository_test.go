@@ -9,6 +9,7 @@ import (
 	"finly-backend/pkg/testutil"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"testing"
@@ -85,12 +86,12 @@ func TestCategoryRepository(t *testing.T) {
 			categoryID := "456"
 			cacheKey := fmt.Sprintf(cacheKeyCategoriesByUser, userID)
 
-			query := fmt.Sprintf("INSERT INTO %s \\(user_id, name\\) VALUES \\(\\$1, \\$2\\) RETURNING id", CategoryTable)
+			query := fmt.Sprintf("INSERT INTO %s \\(user_id, name, icon, color, parent_id\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\) RETURNING id", CategoryTable)
 			mock.ExpectQuery(query).
-				WithArgs(userID, name).
+				WithArgs(userID, name, "", "", sql.NullString{}).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(categoryID))
 
-			id, err := repo.Create(ctx, userID, name)
+			id, err := repo.Create(ctx, &domain.Category{UserID: sql.NullString{String: userID, Valid: true}, Name: name})
 			assert.NoError(t, err)
 			assert.Equal(t, categoryID, id)
 
@@ -99,16 +100,36 @@ func TestCategoryRepository(t *testing.T) {
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 
+		t.Run("Subcategory", func(t *testing.T) {
+			parentID := sql.NullString{String: "789", Valid: true}
+
+			query := fmt.Sprintf("INSERT INTO %s \\(user_id, name, icon, color, parent_id\\)", CategoryTable)
+			mock.ExpectQuery(query).
+				WithArgs("123", "Groceries", "cart", "#2e7d32", parentID).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("456"))
+
+			id, err := repo.Create(ctx, &domain.Category{
+				UserID:   sql.NullString{String: "123", Valid: true},
+				ParentID: parentID,
+				Name:     "Groceries",
+				Icon:     "cart",
+				Color:    "#2e7d32",
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "456", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
 		t.Run("DatabaseError", func(t *testing.T) {
 			userID := "123"
 			name := "Test CategoryObject"
 
-			query := fmt.Sprintf("INSERT INTO %s \\(user_id, name\\) VALUES \\(\\$1, \\$2\\) RETURNING id", CategoryTable)
+			query := fmt.Sprintf("INSERT INTO %s \\(user_id, name, icon, color, parent_id\\)", CategoryTable)
 			mock.ExpectQuery(query).
-				WithArgs(userID, name).
+				WithArgs(userID, name, "", "", sql.NullString{}).
 				WillReturnError(errors.New("db error"))
 
-			id, err := repo.Create(ctx, userID, name)
+			id, err := repo.Create(ctx, &domain.Category{UserID: sql.NullString{String: userID, Valid: true}, Name: name})
 			assert.Error(t, err)
 			assert.Empty(t, id)
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -222,7 +243,7 @@ func TestCategoryRepository(t *testing.T) {
 				},
 			}
 
-			query := fmt.Sprintf("SELECT \\* FROM %s WHERE \\(user_id = \\$1 AND is_user_category = true\\) OR is_user_category = false", CategoryTable)
+			query := fmt.Sprintf("SELECT (.+) FROM %s c LEFT JOIN %s o ON o.category_id = c.id AND o.user_id = \\$1 WHERE \\(c.user_id = \\$1 AND c.is_user_category = true\\) OR c.is_user_category = false ORDER BY o.position NULLS LAST", CategoryTable, CategoryOverrideTable)
 			mock.ExpectQuery(query).
 				WithArgs(userID).
 				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "is_user_category"}).
@@ -246,6 +267,284 @@ func TestCategoryRepository(t *testing.T) {
 		})
 	})
 
+	t.Run("Update", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryRepository(sqlxDB, redisClient)
+
+		query := fmt.Sprintf("UPDATE %s SET name = \\$1, icon = \\$2, color = \\$3(.+)WHERE id = \\$4 AND user_id = \\$5 AND is_user_category = true", CategoryTable)
+		category := &domain.Category{
+			ID:     "456",
+			UserID: sql.NullString{String: "123", Valid: true},
+			Name:   "Eating out",
+			Icon:   "fork",
+			Color:  "#ff5722",
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			cacheKey := fmt.Sprintf(cacheKeyCategoryByIDAndUser, "456", "123")
+			redisClient.Set(ctx, cacheKey, "data", 0)
+
+			mock.ExpectExec(query).
+				WithArgs("Eating out", "fork", "#ff5722", "456", "123").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			updated, err := repo.Update(ctx, category)
+			assert.NoError(t, err)
+			assert.True(t, updated)
+
+			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
+			assert.Equal(t, int64(0), exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			mock.ExpectExec(query).
+				WithArgs("Eating out", "fork", "#ff5722", "456", "123").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			updated, err := repo.Update(ctx, category)
+			assert.NoError(t, err)
+			assert.False(t, updated)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("MoveTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryRepository(sqlxDB, redisClient)
+
+		query := fmt.Sprintf("UPDATE %s SET parent_id = \\$1(.+)WHERE id = \\$2 AND user_id = \\$3 AND is_user_category = true", CategoryTable)
+		parentID := sql.NullString{String: "789", Valid: true}
+
+		t.Run("Success", func(t *testing.T) {
+			cacheKey := fmt.Sprintf(cacheKeyCategoriesByUser, "123")
+			redisClient.Set(ctx, cacheKey, "data", 0)
+
+			mock.ExpectBegin()
+			mock.ExpectExec(query).
+				WithArgs(parentID, "456", "123").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.MoveTX(ctx, tx, "456", "123", parentID)
+			assert.NoError(t, err)
+			assert.Equal(t, repo.cacheKeys("123", "456"), keys)
+
+			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
+			assert.Equal(t, int64(1), exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(query).
+				WithArgs(sql.NullString{}, "456", "123").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			tx, _ := sqlxDB.Beginx()
+			_, err := repo.MoveTX(ctx, tx, "456", "123", sql.NullString{})
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(query).
+				WithArgs(parentID, "456", "123").
+				WillReturnError(errors.New("db error"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.MoveTX(ctx, tx, "456", "123", parentID)
+			assert.Error(t, err)
+			assert.Nil(t, keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("LockTreeTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("SELECT id FROM %s WHERE user_id = \\$1 ORDER BY id FOR UPDATE", CategoryTable)).
+				WithArgs("123").
+				WillReturnResult(sqlmock.NewResult(0, 3))
+
+			tx, _ := sqlxDB.Beginx()
+			assert.NoError(t, repo.LockTreeTX(ctx, tx, "123"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("IsDescendantTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryRepository(sqlxDB, redisClient)
+
+		query := fmt.Sprintf("WITH RECURSIVE ancestors AS(.+)FROM %s WHERE id = \\$1(.+)WHERE NOT c.id = ANY\\(a.path\\)(.+)WHERE id = \\$2", CategoryTable)
+
+		t.Run("Descendant", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(query).
+				WithArgs("789", "456").
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+			tx, _ := sqlxDB.Beginx()
+			descendant, err := repo.IsDescendantTX(ctx, tx, "789", "456")
+			assert.NoError(t, err)
+			assert.True(t, descendant)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(query).
+				WithArgs("789", "456").
+				WillReturnError(errors.New("db error"))
+
+			tx, _ := sqlxDB.Beginx()
+			descendant, err := repo.IsDescendantTX(ctx, tx, "789", "456")
+			assert.Error(t, err)
+			assert.False(t, descendant)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryRepository(sqlxDB, redisClient)
+
+		query := fmt.Sprintf("INSERT INTO %s AS o \\(user_id, category_id, name, icon, color, hidden\\)(.+)ON CONFLICT \\(user_id, category_id\\) DO UPDATE SET name = COALESCE\\(\\$3, o.name\\)", CategoryOverrideTable)
+		override := &domain.CategoryOverride{
+			UserID:     "123",
+			CategoryID: "456",
+			Name:       sql.NullString{String: "Groceries", Valid: true},
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			cacheKey := fmt.Sprintf(cacheKeyCategoriesByUser, "123")
+			redisClient.Set(ctx, cacheKey, "data", 0)
+
+			mock.ExpectExec(query).
+				WithArgs("123", "456", override.Name, sql.NullString{}, sql.NullString{}, sql.NullBool{}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.Override(ctx, override)
+			assert.NoError(t, err)
+
+			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
+			assert.Equal(t, int64(0), exists)
+			version, _ := redisClient.Get(ctx, fmt.Sprintf(CacheKeyVersion, "123")).Int64()
+			assert.Equal(t, int64(1), version)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectExec(query).
+				WithArgs("123", "456", override.Name, sql.NullString{}, sql.NullString{}, sql.NullBool{}).
+				WillReturnError(errors.New("db error"))
+
+			err := repo.Override(ctx, override)
+			assert.Error(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ResetOverride", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryRepository(sqlxDB, redisClient)
+
+		query := fmt.Sprintf("UPDATE %s SET name = NULL, icon = NULL, color = NULL, hidden = false(.+)WHERE user_id = \\$1 AND category_id = \\$2", CategoryOverrideTable)
+
+		t.Run("Success", func(t *testing.T) {
+			cacheKey := fmt.Sprintf(cacheKeyCategoriesByUser, "123")
+			redisClient.Set(ctx, cacheKey, "data", 0)
+
+			mock.ExpectExec(query).
+				WithArgs("123", "456").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.ResetOverride(ctx, "456", "123")
+			assert.NoError(t, err)
+
+			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
+			assert.Equal(t, int64(0), exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectExec(query).
+				WithArgs("123", "456").
+				WillReturnError(errors.New("db error"))
+
+			err := repo.ResetOverride(ctx, "456", "123")
+			assert.Error(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("Reorder", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryRepository(sqlxDB, redisClient)
+
+		query := fmt.Sprintf("UPDATE %[1]s SET position = NULL(.+)INSERT INTO %[1]s \\(user_id, category_id, position\\)(.+)unnest\\(\\$2::uuid\\[\\]\\) WITH ORDINALITY", CategoryOverrideTable)
+		categoryIDs := []string{"456", "789"}
+
+		t.Run("Success", func(t *testing.T) {
+			cacheKey := fmt.Sprintf(cacheKeyCustomCategoriesByUser, "123")
+			redisClient.Set(ctx, cacheKey, "data", 0)
+
+			mock.ExpectExec(query).
+				WithArgs("123", pq.Array(categoryIDs)).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+
+			err := repo.Reorder(ctx, "123", categoryIDs)
+			assert.NoError(t, err)
+
+			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
+			assert.Equal(t, int64(0), exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectExec(query).
+				WithArgs("123", pq.Array(categoryIDs)).
+				WillReturnError(errors.New("db error"))
+
+			err := repo.Reorder(ctx, "123", categoryIDs)
+			assert.Error(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
 		defer sqlxDB.Close()
@@ -254,21 +553,27 @@ func TestCategoryRepository(t *testing.T) {
 		zap.ReplaceGlobals(logger)
 		repo := NewCategoryRepository(sqlxDB, redisClient)
 
+		query := fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND user_id = \\$2 AND is_user_category = true RETURNING id", CategoryTable)
+
 		t.Run("Success", func(t *testing.T) {
 			userID := "123"
 			categoryID := "456"
 			cacheKey := fmt.Sprintf(cacheKeyCategoryByIDAndUser, categoryID, userID)
+			childKey := fmt.Sprintf(cacheKeyCategoryByIDAndUser, "789", userID)
+			redisClient.Set(ctx, cacheKey, "data", 0)
+			redisClient.Set(ctx, childKey, "data", 0)
 
-			query := fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND user_id = \\$2 AND is_user_category = true", CategoryTable)
-			mock.ExpectExec(query).
+			mock.ExpectQuery(query).
 				WithArgs(categoryID, userID).
-				WillReturnResult(sqlmock.NewResult(1, 1))
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("789"))
 
 			err := repo.Delete(ctx, categoryID, userID)
 			assert.NoError(t, err)
 
-			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
+			exists, _ := redisClient.Exists(ctx, cacheKey, childKey).Result()
 			assert.Equal(t, int64(0), exists)
+			version, _ := redisClient.Get(ctx, fmt.Sprintf(CacheKeyVersion, userID)).Int64()
+			assert.Equal(t, int64(1), version)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 
@@ -276,8 +581,7 @@ func TestCategoryRepository(t *testing.T) {
 			userID := "123"
 			categoryID := "456"
 
-			query := fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND user_id = \\$2 AND is_user_category = true", CategoryTable)
-			mock.ExpectExec(query).
+			mock.ExpectQuery(query).
 				WithArgs(categoryID, userID).
 				WillReturnError(errors.New("db error"))
 
@@ -285,6 +589,29 @@ func TestCategoryRepository(t *testing.T) {
 			assert.Error(t, err)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
+
+		t.Run("InUse", func(t *testing.T) {
+			mock.ExpectQuery(query).
+				WithArgs("456", "123").
+				WillReturnError(&pq.Error{Code: foreignKeyViolation})
+
+			err := repo.Delete(ctx, "456", "123")
+			assert.ErrorIs(t, err, ErrCategoryInUse)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DeleteTX", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(query).
+				WithArgs("456", "123").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("789"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.DeleteTX(ctx, tx, "456", "123")
+			assert.NoError(t, err)
+			assert.Equal(t, append(repo.cacheKeys("123", "456"), fmt.Sprintf(cacheKeyCategoryByIDAndUser, "789", "123")), keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
 	})
 
 	t.Run("ListCustom", func(t *testing.T) {
@@ -328,7 +655,7 @@ func TestCategoryRepository(t *testing.T) {
 				},
 			}
 
-			query := fmt.Sprintf("SELECT \\* FROM %s WHERE user_id = \\$1 AND is_user_category = true", CategoryTable)
+			query := fmt.Sprintf("SELECT (.+) FROM %s c LEFT JOIN %s o ON o.category_id = c.id AND o.user_id = \\$1 WHERE c.user_id = \\$1 AND c.is_user_category = true ORDER BY o.position NULLS LAST", CategoryTable, CategoryOverrideTable)
 			mock.ExpectQuery(query).
 				WithArgs(userID).
 				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "is_user_category"}).
@@ -351,4 +678,25 @@ func TestCategoryRepository(t *testing.T) {
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	})
+	t.Run("CacheKeysByUserTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT id FROM %s WHERE user_id", CategoryTable)).
+				WithArgs("user1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("category1"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.CacheKeysByUserTX(ctx, tx, "user1")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{fmt.Sprintf(cacheKeyCategoriesByUser, "user1"), fmt.Sprintf(cacheKeyCustomCategoriesByUser, "user1"), fmt.Sprintf(CacheKeyVersion, "user1"), fmt.Sprintf(cacheKeyCategoryByIDAndUser, "category1", "user1")}, keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
 }
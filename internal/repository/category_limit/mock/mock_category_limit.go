@@ -0,0 +1,192 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/category_limit/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/category_limit/repository.go -destination=internal/repository/category_limit/mock/mock_category_limit.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "finly-backend/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	sqlx "github.com/jmoiron/sqlx"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCategoryLimit is a mock of CategoryLimit interface.
+type MockCategoryLimit struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryLimitMockRecorder
+	isgomock struct{}
+}
+
+// MockCategoryLimitMockRecorder is the mock recorder for MockCategoryLimit.
+type MockCategoryLimitMockRecorder struct {
+	mock *MockCategoryLimit
+}
+
+// NewMockCategoryLimit creates a new mock instance.
+func NewMockCategoryLimit(ctrl *gomock.Controller) *MockCategoryLimit {
+	mock := &MockCategoryLimit{ctrl: ctrl}
+	mock.recorder = &MockCategoryLimitMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryLimit) EXPECT() *MockCategoryLimitMockRecorder {
+	return m.recorder
+}
+
+// CacheKeysByUserTX mocks base method.
+func (m *MockCategoryLimit) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheKeysByUserTX", ctx, tx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CacheKeysByUserTX indicates an expected call of CacheKeysByUserTX.
+func (mr *MockCategoryLimitMockRecorder) CacheKeysByUserTX(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheKeysByUserTX", reflect.TypeOf((*MockCategoryLimit)(nil).CacheKeysByUserTX), ctx, tx, userID)
+}
+
+// Create mocks base method.
+func (m *MockCategoryLimit) Create(ctx context.Context, limit *domain.CategoryLimit) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, limit)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCategoryLimitMockRecorder) Create(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCategoryLimit)(nil).Create), ctx, limit)
+}
+
+// Delete mocks base method.
+func (m *MockCategoryLimit) Delete(ctx context.Context, limitID, userID, budgetID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, limitID, userID, budgetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCategoryLimitMockRecorder) Delete(ctx, limitID, userID, budgetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCategoryLimit)(nil).Delete), ctx, limitID, userID, budgetID)
+}
+
+// GetByID mocks base method.
+func (m *MockCategoryLimit) GetByID(ctx context.Context, limitID, userID string) (*domain.CategoryLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, limitID, userID)
+	ret0, _ := ret[0].(*domain.CategoryLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCategoryLimitMockRecorder) GetByID(ctx, limitID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCategoryLimit)(nil).GetByID), ctx, limitID, userID)
+}
+
+// ListByBudgetID mocks base method.
+func (m *MockCategoryLimit) ListByBudgetID(ctx context.Context, budgetID, userID string) ([]*domain.CategoryLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByBudgetID", ctx, budgetID, userID)
+	ret0, _ := ret[0].([]*domain.CategoryLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByBudgetID indicates an expected call of ListByBudgetID.
+func (mr *MockCategoryLimitMockRecorder) ListByBudgetID(ctx, budgetID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByBudgetID", reflect.TypeOf((*MockCategoryLimit)(nil).ListByBudgetID), ctx, budgetID, userID)
+}
+
+// ListByCategoryTX mocks base method.
+func (m *MockCategoryLimit) ListByCategoryTX(ctx context.Context, tx *sqlx.Tx, budgetID, categoryID string) ([]*domain.CategoryLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCategoryTX", ctx, tx, budgetID, categoryID)
+	ret0, _ := ret[0].([]*domain.CategoryLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByCategoryTX indicates an expected call of ListByCategoryTX.
+func (mr *MockCategoryLimitMockRecorder) ListByCategoryTX(ctx, tx, budgetID, categoryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCategoryTX", reflect.TypeOf((*MockCategoryLimit)(nil).ListByCategoryTX), ctx, tx, budgetID, categoryID)
+}
+
+// ReassignCategoryTX mocks base method.
+func (m *MockCategoryLimit) ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignCategoryTX", ctx, tx, fromCategoryID, toCategoryID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReassignCategoryTX indicates an expected call of ReassignCategoryTX.
+func (mr *MockCategoryLimitMockRecorder) ReassignCategoryTX(ctx, tx, fromCategoryID, toCategoryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignCategoryTX", reflect.TypeOf((*MockCategoryLimit)(nil).ReassignCategoryTX), ctx, tx, fromCategoryID, toCategoryID)
+}
+
+// Spent mocks base method.
+func (m *MockCategoryLimit) Spent(ctx context.Context, budgetID, categoryID string, from, to time.Time) (domain.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Spent", ctx, budgetID, categoryID, from, to)
+	ret0, _ := ret[0].(domain.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Spent indicates an expected call of Spent.
+func (mr *MockCategoryLimitMockRecorder) Spent(ctx, budgetID, categoryID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Spent", reflect.TypeOf((*MockCategoryLimit)(nil).Spent), ctx, budgetID, categoryID, from, to)
+}
+
+// SpentTX mocks base method.
+func (m *MockCategoryLimit) SpentTX(ctx context.Context, tx *sqlx.Tx, budgetID, categoryID string, from, to time.Time) (domain.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SpentTX", ctx, tx, budgetID, categoryID, from, to)
+	ret0, _ := ret[0].(domain.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SpentTX indicates an expected call of SpentTX.
+func (mr *MockCategoryLimitMockRecorder) SpentTX(ctx, tx, budgetID, categoryID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpentTX", reflect.TypeOf((*MockCategoryLimit)(nil).SpentTX), ctx, tx, budgetID, categoryID, from, to)
+}
+
+// Update mocks base method.
+func (m *MockCategoryLimit) Update(ctx context.Context, limit *domain.CategoryLimit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCategoryLimitMockRecorder) Update(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCategoryLimit)(nil).Update), ctx, limit)
+}
@@ -0,0 +1,280 @@
+package category_limit
+
+import (
+	"context"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/budget"
+	"finly-backend/internal/repository/transaction"
+	"finly-backend/pkg/db"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CategoryLimit interface {
+	Create(ctx context.Context, limit *domain.CategoryLimit) (string, error)
+	GetByID(ctx context.Context, limitID, userID string) (*domain.CategoryLimit, error)
+	ListByBudgetID(ctx context.Context, budgetID, userID string) ([]*domain.CategoryLimit, error)
+	ListByCategoryTX(ctx context.Context, tx *sqlx.Tx, budgetID, categoryID string) ([]*domain.CategoryLimit, error)
+	Update(ctx context.Context, limit *domain.CategoryLimit) error
+	Delete(ctx context.Context, limitID, userID, budgetID string) error
+	Spent(ctx context.Context, budgetID, categoryID string, from, to time.Time) (domain.Money, error)
+	SpentTX(ctx context.Context, tx *sqlx.Tx, budgetID, categoryID string, from, to time.Time) (domain.Money, error)
+	ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error)
+	CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error)
+}
+
+const (
+	CategoryLimitTable = "category_limits"
+
+	TTL_ListCategoryLimitsByBudgetCache = 30 * time.Minute
+	TTL_GetCategoryLimitByIDCache       = 30 * time.Minute
+
+	cacheKeyCategoryLimitsByBudget   = "category_limit:budget:%s:user:%s"
+	cacheKeyCategoryLimitByIDAndUser = "category_limit:%s:user:%s"
+
+	uniqueViolation = "23505"
+)
+
+// ErrDuplicateLimit is returned when a budget already has a month or week limit of the same category.
+var ErrDuplicateLimit = errors.New("category limit already exists")
+
+type CategoryLimitRepository struct {
+	postgres *sqlx.DB
+	redis    *redis.Client
+}
+
+func NewCategoryLimitRepository(postgres *sqlx.DB, redis *redis.Client) *CategoryLimitRepository {
+	return &CategoryLimitRepository{
+		postgres: postgres,
+		redis:    redis,
+	}
+}
+
+func (r *CategoryLimitRepository) cacheKeys(userID, budgetID, limitID string) []string {
+	keys := []string{
+		fmt.Sprintf(cacheKeyCategoryLimitsByBudget, budgetID, userID),
+	}
+	if limitID != "" {
+		keys = append(keys, fmt.Sprintf(cacheKeyCategoryLimitByIDAndUser, limitID, userID))
+	}
+	return keys
+}
+
+func (r *CategoryLimitRepository) InvalidateCache(ctx context.Context, userID, budgetID, limitID string) error {
+	zap.L().Sugar().Infof("Invalidating category limit cache for userID: %s, budgetID: %s, limitID: %s", userID, budgetID, limitID)
+
+	keys := r.cacheKeys(userID, budgetID, limitID)
+	if err := r.redis.Del(ctx, keys...).Err(); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate category limit cache for userID: %s, limitID: %s, error: %v", userID, limitID, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CategoryLimitRepository) Create(ctx context.Context, limit *domain.CategoryLimit) (string, error) {
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, budget_id, category_id, amount, period, starts_at, ends_at, enforce)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`, CategoryLimitTable)
+
+	var id string
+	if err := r.postgres.QueryRowContext(ctx, query,
+		limit.UserID, limit.BudgetID, limit.CategoryID, limit.Amount, limit.Period, limit.StartsAt, limit.EndsAt, limit.Enforce,
+	).Scan(&id); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return "", ErrDuplicateLimit
+		}
+		zap.L().Sugar().Errorf("Failed to create category limit for userID: %s, budgetID: %s, error: %v", limit.UserID, limit.BudgetID, err)
+		return "", err
+	}
+
+	if err := r.InvalidateCache(ctx, limit.UserID, limit.BudgetID, id); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after create, userID: %s, error: %v", limit.UserID, err)
+	}
+
+	zap.L().Sugar().Infof("Category limit created, limitID: %s, userID: %s", id, limit.UserID)
+	return id, nil
+}
+
+func (r *CategoryLimitRepository) GetByID(ctx context.Context, limitID, userID string) (*domain.CategoryLimit, error) {
+	if limitID == "" || userID == "" {
+		return nil, fmt.Errorf("limitID and userID cannot be empty")
+	}
+
+	cacheKey := fmt.Sprintf(cacheKeyCategoryLimitByIDAndUser, limitID, userID)
+
+	fetch := func() (*domain.CategoryLimit, error) {
+		var limit domain.CategoryLimit
+		query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1 AND user_id = $2", CategoryLimitTable)
+		if err := r.postgres.GetContext(ctx, &limit, query, limitID, userID); err != nil {
+			zap.L().Sugar().Errorf("Failed to fetch category limit from DB, limitID: %s, userID: %s, error: %v", limitID, userID, err)
+			return nil, err
+		}
+		return &limit, nil
+	}
+
+	return db.WithCache(ctx, r.redis, cacheKey, TTL_GetCategoryLimitByIDCache, fetch)
+}
+
+func (r *CategoryLimitRepository) ListByBudgetID(ctx context.Context, budgetID, userID string) ([]*domain.CategoryLimit, error) {
+	if budgetID == "" || userID == "" {
+		return nil, fmt.Errorf("budgetID and userID cannot be empty")
+	}
+
+	cacheKey := fmt.Sprintf(cacheKeyCategoryLimitsByBudget, budgetID, userID)
+
+	fetch := func() ([]*domain.CategoryLimit, error) {
+		var limits []*domain.CategoryLimit
+		query := fmt.Sprintf("SELECT * FROM %s WHERE budget_id = $1 AND user_id = $2 ORDER BY created_at ASC, id ASC", CategoryLimitTable)
+		if err := r.postgres.SelectContext(ctx, &limits, query, budgetID, userID); err != nil {
+			zap.L().Sugar().Errorf("Failed to fetch category limits from DB, budgetID: %s, userID: %s, error: %v", budgetID, userID, err)
+			return nil, err
+		}
+		return limits, nil
+	}
+
+	return db.WithCache(ctx, r.redis, cacheKey, TTL_ListCategoryLimitsByBudgetCache, fetch)
+}
+
+// ListByCategoryTX returns the limits of a category in a budget and locks them until the
+// transaction ends, so concurrent withdrawals are checked against the limit one at a time.
+func (r *CategoryLimitRepository) ListByCategoryTX(ctx context.Context, tx *sqlx.Tx, budgetID, categoryID string) ([]*domain.CategoryLimit, error) {
+	var limits []*domain.CategoryLimit
+	query := fmt.Sprintf("SELECT * FROM %s WHERE budget_id = $1 AND category_id = $2 ORDER BY id FOR UPDATE", CategoryLimitTable)
+	if err := tx.SelectContext(ctx, &limits, query, budgetID, categoryID); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch category limits, budgetID: %s, categoryID: %s, error: %v", budgetID, categoryID, err)
+		return nil, err
+	}
+	return limits, nil
+}
+
+func (r *CategoryLimitRepository) Update(ctx context.Context, limit *domain.CategoryLimit) error {
+	query := fmt.Sprintf(`UPDATE %s
+		SET amount = $1, starts_at = $2, ends_at = $3, enforce = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5 AND user_id = $6`, CategoryLimitTable)
+	if _, err := r.postgres.ExecContext(ctx, query,
+		limit.Amount, limit.StartsAt, limit.EndsAt, limit.Enforce, limit.ID, limit.UserID,
+	); err != nil {
+		zap.L().Sugar().Errorf("Failed to update category limit, limitID: %s, userID: %s, error: %v", limit.ID, limit.UserID, err)
+		return err
+	}
+
+	if err := r.InvalidateCache(ctx, limit.UserID, limit.BudgetID, limit.ID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after update, limitID: %s, error: %v", limit.ID, err)
+	}
+
+	zap.L().Sugar().Infof("Category limit updated, limitID: %s, userID: %s", limit.ID, limit.UserID)
+	return nil
+}
+
+func (r *CategoryLimitRepository) Delete(ctx context.Context, limitID, userID, budgetID string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", CategoryLimitTable)
+	if _, err := r.postgres.ExecContext(ctx, query, limitID, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to delete category limit, limitID: %s, userID: %s, error: %v", limitID, userID, err)
+		return err
+	}
+
+	if err := r.InvalidateCache(ctx, userID, budgetID, limitID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after delete, limitID: %s, error: %v", limitID, err)
+	}
+
+	zap.L().Sugar().Infof("Category limit deleted, limitID: %s, userID: %s", limitID, userID)
+	return nil
+}
+
+// Spent sums the withdrawals booked on a category of a budget within [from, to).
+// It always reads from the database, as spending changes with every transaction.
+func (r *CategoryLimitRepository) Spent(ctx context.Context, budgetID, categoryID string, from, to time.Time) (domain.Money, error) {
+	return r.spent(ctx, r.postgres, budgetID, categoryID, from, to)
+}
+
+func (r *CategoryLimitRepository) SpentTX(ctx context.Context, tx *sqlx.Tx, budgetID, categoryID string, from, to time.Time) (domain.Money, error) {
+	return r.spent(ctx, tx, budgetID, categoryID, from, to)
+}
+
+func (r *CategoryLimitRepository) spent(ctx context.Context, q sqlx.QueryerContext, budgetID, categoryID string, from, to time.Time) (domain.Money, error) {
+	var spent domain.Money
+	query := fmt.Sprintf(`SELECT COALESCE(SUM(amount), 0) FROM %s
+		WHERE budget_id = $1 AND category_id = $2 AND transaction_type = 'withdrawal' AND created_at >= $3 AND created_at < $4`,
+		transaction.TransactionTable)
+	if err := sqlx.GetContext(ctx, q, &spent, query, budgetID, categoryID, from, to); err != nil {
+		zap.L().Sugar().Errorf("Failed to sum spending, budgetID: %s, categoryID: %s, error: %v", budgetID, categoryID, err)
+		return domain.Money{}, err
+	}
+	return spent, nil
+}
+
+// ReassignCategoryTX moves the limits of fromCategoryID to toCategoryID. When toCategoryID
+// already has a month or week limit for the same budget and period, the two are combined:
+// the amounts are added up and the limit is enforced if either was. The limit of
+// fromCategoryID is then deleted together with fromCategoryID. It returns the cache keys of
+// every limit of fromCategoryID and of every combined limit, to be deleted once tx is committed.
+func (r *CategoryLimitRepository) ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error) {
+	var limits []*domain.CategoryLimit
+	query := fmt.Sprintf("SELECT * FROM %s WHERE category_id = $1", CategoryLimitTable)
+	if err := tx.SelectContext(ctx, &limits, query, fromCategoryID); err != nil {
+		zap.L().Sugar().Errorf("Failed to list category limits, categoryID: %s, error: %v", fromCategoryID, err)
+		return nil, err
+	}
+	if len(limits) == 0 {
+		return nil, nil
+	}
+
+	var combined []*domain.CategoryLimit
+	query = fmt.Sprintf(`UPDATE %[1]s o SET amount = o.amount + l.amount, enforce = o.enforce OR l.enforce, updated_at = CURRENT_TIMESTAMP
+		FROM %[1]s l
+		WHERE l.category_id = $2 AND o.category_id = $1 AND o.budget_id = l.budget_id AND o.period = l.period AND l.period <> 'custom'
+		RETURNING o.*`, CategoryLimitTable)
+	if err := tx.SelectContext(ctx, &combined, query, toCategoryID, fromCategoryID); err != nil {
+		zap.L().Sugar().Errorf("Failed to combine category limits, fromCategoryID: %s, toCategoryID: %s, error: %v", fromCategoryID, toCategoryID, err)
+		return nil, err
+	}
+
+	query = fmt.Sprintf(`UPDATE %[1]s l SET category_id = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE l.category_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM %[1]s o
+			WHERE o.budget_id = l.budget_id AND o.category_id = $1 AND o.period = l.period AND l.period <> 'custom'
+		)`, CategoryLimitTable)
+	if _, err := tx.ExecContext(ctx, query, toCategoryID, fromCategoryID); err != nil {
+		zap.L().Sugar().Errorf("Failed to reassign category limits, fromCategoryID: %s, toCategoryID: %s, error: %v", fromCategoryID, toCategoryID, err)
+		return nil, err
+	}
+
+	var keys []string
+	for _, limit := range append(limits, combined...) {
+		keys = append(keys, r.cacheKeys(limit.UserID, limit.BudgetID, limit.ID)...)
+	}
+	return keys, nil
+}
+
+// CacheKeysByUserTX lists the cache keys of the user's limits and of the limit lists of
+// every budget of the user.
+func (r *CategoryLimitRepository) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	var limits []*domain.CategoryLimit
+	query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1", CategoryLimitTable)
+	if err := tx.SelectContext(ctx, &limits, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to list category limits, userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+
+	var budgetIDs []string
+	query = fmt.Sprintf("SELECT id FROM %s WHERE user_id = $1", budget.BudgetTable)
+	if err := tx.SelectContext(ctx, &budgetIDs, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to list budget IDs, userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+
+	var keys []string
+	for _, budgetID := range budgetIDs {
+		keys = append(keys, r.cacheKeys(userID, budgetID, "")...)
+	}
+	for _, limit := range limits {
+		keys = append(keys, r.cacheKeys(userID, limit.BudgetID, limit.ID)[1:]...)
+	}
+	return keys, nil
+}
@@ -0,0 +1,252 @@
+package category_limit
+
+import (
+	"context"
+	"database/sql"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/budget"
+	"finly-backend/pkg/testutil"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestCategoryLimitRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cacheKeys", func(t *testing.T) {
+		sqlxDB, _, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryLimitRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			expected := []string{
+				fmt.Sprintf(cacheKeyCategoryLimitsByBudget, "789", "123"),
+				fmt.Sprintf(cacheKeyCategoryLimitByIDAndUser, "456", "123"),
+			}
+			assert.Equal(t, expected, repo.cacheKeys("123", "789", "456"))
+		})
+
+		t.Run("WithoutLimitID", func(t *testing.T) {
+			assert.Equal(t, []string{fmt.Sprintf(cacheKeyCategoryLimitsByBudget, "789", "123")}, repo.cacheKeys("123", "789", ""))
+		})
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryLimitRepository(sqlxDB, redisClient)
+
+		limit := &domain.CategoryLimit{
+			UserID: "123", BudgetID: "789", CategoryID: "101",
+			Amount: domain.MustParseMoney("400.00"), Period: "month",
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			redisClient.Set(ctx, fmt.Sprintf(cacheKeyCategoryLimitsByBudget, "789", "123"), "data", 0)
+
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", CategoryLimitTable)).
+				WithArgs("123", "789", "101", limit.Amount, "month", limit.StartsAt, limit.EndsAt, false).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("limit1"))
+
+			id, err := repo.Create(ctx, limit)
+			assert.NoError(t, err)
+			assert.Equal(t, "limit1", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(cacheKeyCategoryLimitsByBudget, "789", "123")).Result()
+			assert.Equal(t, int64(0), exists)
+		})
+
+		t.Run("Duplicate", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", CategoryLimitTable)).
+				WithArgs("123", "789", "101", limit.Amount, "month", limit.StartsAt, limit.EndsAt, false).
+				WillReturnError(&pq.Error{Code: uniqueViolation})
+
+			_, err := repo.Create(ctx, limit)
+			assert.ErrorIs(t, err, ErrDuplicateLimit)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ListByCategoryTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryLimitRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE budget_id = \\$1 AND category_id = \\$2 ORDER BY id FOR UPDATE", CategoryLimitTable)).
+				WithArgs("789", "101").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "budget_id", "category_id", "amount", "period", "enforce"}).
+					AddRow("limit1", "789", "101", "400.00", "month", true))
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			limits, err := repo.ListByCategoryTX(ctx, tx, "789", "101")
+			assert.NoError(t, err)
+			assert.Equal(t, []*domain.CategoryLimit{{
+				ID: "limit1", BudgetID: "789", CategoryID: "101", Amount: domain.MustParseMoney("400.00"), Period: "month", Enforce: true,
+			}}, limits)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("Spent", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryLimitRepository(sqlxDB, redisClient)
+
+		from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 1, 0)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery("SELECT COALESCE\\(SUM\\(amount\\), 0\\) FROM transactions\\s+WHERE budget_id = \\$1 AND category_id = \\$2 AND transaction_type = 'withdrawal'").
+				WithArgs("789", "101", from, to).
+				WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow("125.50"))
+
+			spent, err := repo.Spent(ctx, "789", "101", from, to)
+			assert.NoError(t, err)
+			assert.Equal(t, domain.MustParseMoney("125.50"), spent)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("Error", func(t *testing.T) {
+			mock.ExpectQuery("SELECT COALESCE\\(SUM\\(amount\\), 0\\) FROM transactions").
+				WithArgs("789", "101", from, to).
+				WillReturnError(sql.ErrConnDone)
+
+			_, err := repo.Spent(ctx, "789", "101", from, to)
+			assert.ErrorIs(t, err, sql.ErrConnDone)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryLimitRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			redisClient.Set(ctx, fmt.Sprintf(cacheKeyCategoryLimitByIDAndUser, "limit1", "123"), "data", 0)
+
+			mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND user_id = \\$2", CategoryLimitTable)).
+				WithArgs("limit1", "123").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.Delete(ctx, "limit1", "123", "789")
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(cacheKeyCategoryLimitByIDAndUser, "limit1", "123")).Result()
+			assert.Equal(t, int64(0), exists)
+		})
+	})
+	t.Run("ReassignCategoryTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryLimitRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE category_id = \\$1", CategoryLimitTable)).
+				WithArgs("category1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "budget_id"}).AddRow("limit1", "user1", "budget1"))
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s o SET amount = o.amount \\+ l.amount", CategoryLimitTable)).
+				WithArgs("category2", "category1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s l SET category_id = \\$1(.+)WHERE l.category_id = \\$2 AND NOT EXISTS", CategoryLimitTable)).
+				WithArgs("category2", "category1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.ReassignCategoryTX(ctx, tx, "category1", "category2")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{fmt.Sprintf(cacheKeyCategoryLimitsByBudget, "budget1", "user1"), fmt.Sprintf(cacheKeyCategoryLimitByIDAndUser, "limit1", "user1")}, keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("ConflictingLimitsAreCombined", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE category_id = \\$1", CategoryLimitTable)).
+				WithArgs("category1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "budget_id"}).AddRow("limit1", "user1", "budget1"))
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s o SET amount = o.amount \\+ l.amount, enforce = o.enforce OR l.enforce(.+)RETURNING o.\\*", CategoryLimitTable)).
+				WithArgs("category2", "category1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "budget_id"}).AddRow("limit2", "user1", "budget1"))
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s l SET category_id = \\$1", CategoryLimitTable)).
+				WithArgs("category2", "category1").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.ReassignCategoryTX(ctx, tx, "category1", "category2")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{
+				fmt.Sprintf(cacheKeyCategoryLimitsByBudget, "budget1", "user1"), fmt.Sprintf(cacheKeyCategoryLimitByIDAndUser, "limit1", "user1"),
+				fmt.Sprintf(cacheKeyCategoryLimitsByBudget, "budget1", "user1"), fmt.Sprintf(cacheKeyCategoryLimitByIDAndUser, "limit2", "user1"),
+			}, keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NoLimits", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE category_id = \\$1", CategoryLimitTable)).
+				WithArgs("category1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.ReassignCategoryTX(ctx, tx, "category1", "category2")
+			assert.NoError(t, err)
+			assert.Empty(t, keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("CacheKeysByUserTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewCategoryLimitRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE user_id", CategoryLimitTable)).
+				WithArgs("user1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "budget_id"}).AddRow("limit1", "budget1"))
+			mock.ExpectQuery(fmt.Sprintf("SELECT id FROM %s WHERE user_id", budget.BudgetTable)).
+				WithArgs("user1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("budget1"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.CacheKeysByUserTX(ctx, tx, "user1")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{fmt.Sprintf(cacheKeyCategoryLimitsByBudget, "budget1", "user1"), fmt.Sprintf(cacheKeyCategoryLimitByIDAndUser, "limit1", "user1")}, keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+}
@@ -0,0 +1,161 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/data_export/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/data_export/repository.go -destination=internal/repository/data_export/mock/mock_data_export.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "finly-backend/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDataExport is a mock of DataExport interface.
+type MockDataExport struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportMockRecorder
+	isgomock struct{}
+}
+
+// MockDataExportMockRecorder is the mock recorder for MockDataExport.
+type MockDataExportMockRecorder struct {
+	mock *MockDataExport
+}
+
+// NewMockDataExport creates a new mock instance.
+func NewMockDataExport(ctrl *gomock.Controller) *MockDataExport {
+	mock := &MockDataExport{ctrl: ctrl}
+	mock.recorder = &MockDataExportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExport) EXPECT() *MockDataExportMockRecorder {
+	return m.recorder
+}
+
+// ClaimNext mocks base method.
+func (m *MockDataExport) ClaimNext(ctx context.Context, staleBefore time.Time) (*domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNext", ctx, staleBefore)
+	ret0, _ := ret[0].(*domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNext indicates an expected call of ClaimNext.
+func (mr *MockDataExportMockRecorder) ClaimNext(ctx, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNext", reflect.TypeOf((*MockDataExport)(nil).ClaimNext), ctx, staleBefore)
+}
+
+// Complete mocks base method.
+func (m *MockDataExport) Complete(ctx context.Context, exportID string, archive []byte, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, exportID, archive, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockDataExportMockRecorder) Complete(ctx, exportID, archive, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockDataExport)(nil).Complete), ctx, exportID, archive, expiresAt)
+}
+
+// Create mocks base method.
+func (m *MockDataExport) Create(ctx context.Context, userID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDataExportMockRecorder) Create(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDataExport)(nil).Create), ctx, userID)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockDataExport) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockDataExportMockRecorder) DeleteExpired(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockDataExport)(nil).DeleteExpired), ctx, now)
+}
+
+// Fail mocks base method.
+func (m *MockDataExport) Fail(ctx context.Context, exportID, reason string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", ctx, exportID, reason, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockDataExportMockRecorder) Fail(ctx, exportID, reason, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockDataExport)(nil).Fail), ctx, exportID, reason, expiresAt)
+}
+
+// GetActiveByUserID mocks base method.
+func (m *MockDataExport) GetActiveByUserID(ctx context.Context, userID string) (*domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveByUserID", ctx, userID)
+	ret0, _ := ret[0].(*domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveByUserID indicates an expected call of GetActiveByUserID.
+func (mr *MockDataExportMockRecorder) GetActiveByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveByUserID", reflect.TypeOf((*MockDataExport)(nil).GetActiveByUserID), ctx, userID)
+}
+
+// GetArchive mocks base method.
+func (m *MockDataExport) GetArchive(ctx context.Context, exportID, userID string, now time.Time) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchive", ctx, exportID, userID, now)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchive indicates an expected call of GetArchive.
+func (mr *MockDataExportMockRecorder) GetArchive(ctx, exportID, userID, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchive", reflect.TypeOf((*MockDataExport)(nil).GetArchive), ctx, exportID, userID, now)
+}
+
+// GetByID mocks base method.
+func (m *MockDataExport) GetByID(ctx context.Context, exportID, userID string) (*domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, exportID, userID)
+	ret0, _ := ret[0].(*domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDataExportMockRecorder) GetByID(ctx, exportID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDataExport)(nil).GetByID), ctx, exportID, userID)
+}
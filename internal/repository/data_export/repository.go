@@ -0,0 +1,159 @@
+package data_export
+
+import (
+	"context"
+	"errors"
+	"finly-backend/internal/domain"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"time"
+)
+
+type DataExport interface {
+	Create(ctx context.Context, userID string) (string, error)
+	GetByID(ctx context.Context, exportID, userID string) (*domain.DataExport, error)
+	GetActiveByUserID(ctx context.Context, userID string) (*domain.DataExport, error)
+	ClaimNext(ctx context.Context, staleBefore time.Time) (*domain.DataExport, error)
+	Complete(ctx context.Context, exportID string, archive []byte, expiresAt time.Time) error
+	Fail(ctx context.Context, exportID, reason string, expiresAt time.Time) error
+	GetArchive(ctx context.Context, exportID, userID string, now time.Time) ([]byte, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+const (
+	DataExportTable = "data_exports"
+
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+
+	// columns leaves out the archive, which is only read for download.
+	columns = "id, user_id, status, attempts, size, error, started_at, completed_at, expires_at, created_at"
+
+	uniqueViolation = "23505"
+)
+
+// ErrExportInProgress is returned when the user already has a pending or running export.
+var ErrExportInProgress = errors.New("data export already in progress")
+
+// DataExportRepository is not cached, as the status of a job changes while it runs.
+type DataExportRepository struct {
+	postgres *sqlx.DB
+	redis    *redis.Client
+}
+
+func NewDataExportRepository(postgres *sqlx.DB, redis *redis.Client) *DataExportRepository {
+	return &DataExportRepository{
+		postgres: postgres,
+		redis:    redis,
+	}
+}
+
+func (r *DataExportRepository) Create(ctx context.Context, userID string) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, status) VALUES ($1, $2) RETURNING id", DataExportTable)
+
+	var id string
+	if err := r.postgres.QueryRowContext(ctx, query, userID, StatusPending).Scan(&id); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return "", ErrExportInProgress
+		}
+		zap.L().Sugar().Errorf("Failed to create data export, userID: %s, error: %v", userID, err)
+		return "", err
+	}
+
+	zap.L().Sugar().Infof("Data export created, exportID: %s, userID: %s", id, userID)
+	return id, nil
+}
+
+func (r *DataExportRepository) GetByID(ctx context.Context, exportID, userID string) (*domain.DataExport, error) {
+	var export domain.DataExport
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 AND user_id = $2", columns, DataExportTable)
+	if err := r.postgres.GetContext(ctx, &export, query, exportID, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch data export, exportID: %s, userID: %s, error: %v", exportID, userID, err)
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *DataExportRepository) GetActiveByUserID(ctx context.Context, userID string) (*domain.DataExport, error) {
+	var export domain.DataExport
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = $1 AND status IN ($2, $3)", columns, DataExportTable)
+	if err := r.postgres.GetContext(ctx, &export, query, userID, StatusPending, StatusRunning); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch active data export, userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ClaimNext marks the oldest pending export as running and returns it. An export that has
+// been running since before staleBefore is claimed again, as its worker is assumed dead.
+// Concurrent workers never claim the same export. It returns sql.ErrNoRows when there is
+// nothing to do.
+func (r *DataExportRepository) ClaimNext(ctx context.Context, staleBefore time.Time) (*domain.DataExport, error) {
+	query := fmt.Sprintf(`UPDATE %[1]s SET status = $1, attempts = attempts + 1, started_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id FROM %[1]s
+			WHERE status = $2 OR (status = $1 AND started_at < $3)
+			ORDER BY created_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING %[2]s`, DataExportTable, columns)
+
+	var export domain.DataExport
+	if err := r.postgres.GetContext(ctx, &export, query, StatusRunning, StatusPending, staleBefore); err != nil {
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Data export claimed, exportID: %s, attempt: %d", export.ID, export.Attempts)
+	return &export, nil
+}
+
+func (r *DataExportRepository) Complete(ctx context.Context, exportID string, archive []byte, expiresAt time.Time) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = $1, archive = $2, size = $3, completed_at = CURRENT_TIMESTAMP, expires_at = $4
+		WHERE id = $5`, DataExportTable)
+	if _, err := r.postgres.ExecContext(ctx, query, StatusCompleted, archive, len(archive), expiresAt, exportID); err != nil {
+		zap.L().Sugar().Errorf("Failed to complete data export, exportID: %s, error: %v", exportID, err)
+		return err
+	}
+
+	zap.L().Sugar().Infof("Data export completed, exportID: %s, size: %d", exportID, len(archive))
+	return nil
+}
+
+func (r *DataExportRepository) Fail(ctx context.Context, exportID, reason string, expiresAt time.Time) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = $1, error = $2, completed_at = CURRENT_TIMESTAMP, expires_at = $3
+		WHERE id = $4`, DataExportTable)
+	if _, err := r.postgres.ExecContext(ctx, query, StatusFailed, reason, expiresAt, exportID); err != nil {
+		zap.L().Sugar().Errorf("Failed to mark data export as failed, exportID: %s, error: %v", exportID, err)
+		return err
+	}
+	return nil
+}
+
+// GetArchive returns the archive of a completed export that has not expired yet.
+func (r *DataExportRepository) GetArchive(ctx context.Context, exportID, userID string, now time.Time) ([]byte, error) {
+	var archive []byte
+	query := fmt.Sprintf("SELECT archive FROM %s WHERE id = $1 AND user_id = $2 AND status = $3 AND expires_at > $4", DataExportTable)
+	if err := r.postgres.GetContext(ctx, &archive, query, exportID, userID, StatusCompleted, now); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch data export archive, exportID: %s, userID: %s, error: %v", exportID, userID, err)
+		return nil, err
+	}
+	return archive, nil
+}
+
+// DeleteExpired removes finished exports whose download window has passed.
+func (r *DataExportRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at <= $1", DataExportTable)
+	res, err := r.postgres.ExecContext(ctx, query, now)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to delete expired data exports, error: %v", err)
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,197 @@
+package data_export
+
+import (
+	"context"
+	"database/sql"
+	"finly-backend/pkg/testutil"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestDataExportRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	rowColumns := []string{"id", "user_id", "status", "attempts", "size", "error", "started_at", "completed_at", "expires_at", "created_at"}
+
+	t.Run("Create", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewDataExportRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", DataExportTable)).
+				WithArgs("user1", StatusPending).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("export1"))
+
+			id, err := repo.Create(ctx, "user1")
+			assert.NoError(t, err)
+			assert.Equal(t, "export1", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("InProgress", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", DataExportTable)).
+				WithArgs("user1", StatusPending).
+				WillReturnError(&pq.Error{Code: uniqueViolation})
+
+			id, err := repo.Create(ctx, "user1")
+			assert.ErrorIs(t, err, ErrExportInProgress)
+			assert.Empty(t, id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewDataExportRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("SELECT (.+) FROM %s WHERE id", DataExportTable)).
+				WithArgs("export1", "user1").
+				WillReturnRows(sqlmock.NewRows(rowColumns).
+					AddRow("export1", "user1", StatusCompleted, 1, 2048, nil, now, now, now.Add(time.Hour), now))
+
+			export, err := repo.GetByID(ctx, "export1", "user1")
+			assert.NoError(t, err)
+			assert.Equal(t, StatusCompleted, export.Status)
+			assert.Equal(t, int64(2048), export.Size.Int64)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("SELECT (.+) FROM %s WHERE id", DataExportTable)).
+				WithArgs("export1", "user2").
+				WillReturnError(sql.ErrNoRows)
+
+			export, err := repo.GetByID(ctx, "export1", "user2")
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			assert.Nil(t, export)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ClaimNext", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewDataExportRepository(sqlxDB, redisClient)
+		staleBefore := now.Add(-time.Hour)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s SET status = (.+) FOR UPDATE SKIP LOCKED", DataExportTable)).
+				WithArgs(StatusRunning, StatusPending, staleBefore).
+				WillReturnRows(sqlmock.NewRows(rowColumns).
+					AddRow("export1", "user1", StatusRunning, 1, nil, nil, now, nil, nil, now))
+
+			export, err := repo.ClaimNext(ctx, staleBefore)
+			assert.NoError(t, err)
+			assert.Equal(t, "export1", export.ID)
+			assert.Equal(t, 1, export.Attempts)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NothingToDo", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s SET status", DataExportTable)).
+				WithArgs(StatusRunning, StatusPending, staleBefore).
+				WillReturnRows(sqlmock.NewRows(rowColumns))
+
+			export, err := repo.ClaimNext(ctx, staleBefore)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			assert.Nil(t, export)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("Complete", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewDataExportRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			archive := []byte("zip")
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET status", DataExportTable)).
+				WithArgs(StatusCompleted, archive, 3, now, "export1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.Complete(ctx, "export1", archive, now)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewDataExportRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET status", DataExportTable)).
+				WithArgs(StatusFailed, "boom", now, "export1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.Fail(ctx, "export1", "boom", now)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("GetArchive", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewDataExportRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("SELECT archive FROM %s", DataExportTable)).
+				WithArgs("export1", "user1", StatusCompleted, now).
+				WillReturnRows(sqlmock.NewRows([]string{"archive"}).AddRow([]byte("zip")))
+
+			archive, err := repo.GetArchive(ctx, "export1", "user1", now)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("zip"), archive)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("DeleteExpired", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewDataExportRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE expires_at", DataExportTable)).
+				WithArgs(now).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+
+			deleted, err := repo.DeleteExpired(ctx, now)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(2), deleted)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/email_verification/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/email_verification/repository.go -destination=internal/repository/email_verification/mock/mock_email_verification.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "finly-backend/internal/domain"
+	reflect "reflect"
+
+	sqlx "github.com/jmoiron/sqlx"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEmailVerification is a mock of EmailVerification interface.
+type MockEmailVerification struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailVerificationMockRecorder
+	isgomock struct{}
+}
+
+// MockEmailVerificationMockRecorder is the mock recorder for MockEmailVerification.
+type MockEmailVerificationMockRecorder struct {
+	mock *MockEmailVerification
+}
+
+// NewMockEmailVerification creates a new mock instance.
+func NewMockEmailVerification(ctrl *gomock.Controller) *MockEmailVerification {
+	mock := &MockEmailVerification{ctrl: ctrl}
+	mock.recorder = &MockEmailVerificationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailVerification) EXPECT() *MockEmailVerificationMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockEmailVerification) Create(ctx context.Context, token *domain.EmailVerificationToken) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEmailVerificationMockRecorder) Create(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEmailVerification)(nil).Create), ctx, token)
+}
+
+// GetByHashTX mocks base method.
+func (m *MockEmailVerification) GetByHashTX(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*domain.EmailVerificationToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHashTX", ctx, tx, tokenHash)
+	ret0, _ := ret[0].(*domain.EmailVerificationToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHashTX indicates an expected call of GetByHashTX.
+func (mr *MockEmailVerificationMockRecorder) GetByHashTX(ctx, tx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHashTX", reflect.TypeOf((*MockEmailVerification)(nil).GetByHashTX), ctx, tx, tokenHash)
+}
+
+// GetDB mocks base method.
+func (m *MockEmailVerification) GetDB() *sqlx.DB {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDB")
+	ret0, _ := ret[0].(*sqlx.DB)
+	return ret0
+}
+
+// GetDB indicates an expected call of GetDB.
+func (mr *MockEmailVerificationMockRecorder) GetDB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDB", reflect.TypeOf((*MockEmailVerification)(nil).GetDB))
+}
+
+// MarkUsedByUserTX mocks base method.
+func (m *MockEmailVerification) MarkUsedByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUsedByUserTX", ctx, tx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkUsedByUserTX indicates an expected call of MarkUsedByUserTX.
+func (mr *MockEmailVerificationMockRecorder) MarkUsedByUserTX(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUsedByUserTX", reflect.TypeOf((*MockEmailVerification)(nil).MarkUsedByUserTX), ctx, tx, userID)
+}
@@ -0,0 +1,74 @@
+package email_verification
+
+import (
+	"context"
+	"finly-backend/internal/domain"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type EmailVerification interface {
+	GetDB() *sqlx.DB
+	Create(ctx context.Context, token *domain.EmailVerificationToken) (string, error)
+	GetByHashTX(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*domain.EmailVerificationToken, error)
+	MarkUsedByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) error
+}
+
+const EmailVerificationTokenTable = "email_verification_tokens"
+
+// EmailVerificationRepository is not cached, as a token is read once and then used up.
+type EmailVerificationRepository struct {
+	postgres *sqlx.DB
+	redis    *redis.Client
+}
+
+func NewEmailVerificationRepository(postgres *sqlx.DB, redis *redis.Client) *EmailVerificationRepository {
+	return &EmailVerificationRepository{
+		postgres: postgres,
+		redis:    redis,
+	}
+}
+
+func (r *EmailVerificationRepository) GetDB() *sqlx.DB {
+	return r.postgres
+}
+
+func (r *EmailVerificationRepository) Create(ctx context.Context, token *domain.EmailVerificationToken) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, email, token_hash, expires_at) VALUES ($1, $2, $3, $4) RETURNING id", EmailVerificationTokenTable)
+
+	var id string
+	if err := r.postgres.QueryRowContext(ctx, query, token.UserID, token.Email, token.TokenHash, token.ExpiresAt).Scan(&id); err != nil {
+		zap.L().Sugar().Errorf("Failed to create email verification token, userID: %s, error: %v", token.UserID, err)
+		return "", err
+	}
+
+	zap.L().Sugar().Infof("Email verification token created, tokenID: %s, userID: %s", id, token.UserID)
+	return id, nil
+}
+
+// GetByHashTX looks a token up by its hash and locks it until the transaction ends, so
+// the same token cannot be used twice by concurrent requests.
+func (r *EmailVerificationRepository) GetByHashTX(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*domain.EmailVerificationToken, error) {
+	var token domain.EmailVerificationToken
+	query := fmt.Sprintf("SELECT * FROM %s WHERE token_hash = $1 FOR UPDATE", EmailVerificationTokenTable)
+	if err := tx.GetContext(ctx, &token, query, tokenHash); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch email verification token, error: %v", err)
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsedByUserTX uses up every outstanding token of the user, so the links of earlier
+// requests stop working once the email is verified.
+func (r *EmailVerificationRepository) MarkUsedByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	query := fmt.Sprintf("UPDATE %s SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND used_at IS NULL", EmailVerificationTokenTable)
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to mark email verification tokens as used, userID: %s, error: %v", userID, err)
+		return err
+	}
+
+	zap.L().Sugar().Infof("Email verification tokens used up, userID: %s", userID)
+	return nil
+}
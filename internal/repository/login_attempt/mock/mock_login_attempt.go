@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/login_attempt/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/login_attempt/repository.go -destination=internal/repository/login_attempt/mock/mock_login_attempt.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginAttempt is a mock of LoginAttempt interface.
+type MockLoginAttempt struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginAttemptMockRecorder is the mock recorder for MockLoginAttempt.
+type MockLoginAttemptMockRecorder struct {
+	mock *MockLoginAttempt
+}
+
+// NewMockLoginAttempt creates a new mock instance.
+func NewMockLoginAttempt(ctrl *gomock.Controller) *MockLoginAttempt {
+	mock := &MockLoginAttempt{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttempt) EXPECT() *MockLoginAttemptMockRecorder {
+	return m.recorder
+}
+
+// LockedFor mocks base method.
+func (m *MockLoginAttempt) LockedFor(ctx context.Context, scope, subject string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockedFor", ctx, scope, subject)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockedFor indicates an expected call of LockedFor.
+func (mr *MockLoginAttemptMockRecorder) LockedFor(ctx, scope, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockedFor", reflect.TypeOf((*MockLoginAttempt)(nil).LockedFor), ctx, scope, subject)
+}
+
+// RecordAttempt mocks base method.
+func (m *MockLoginAttempt) RecordAttempt(ctx context.Context, scope, subject string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAttempt", ctx, scope, subject)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordAttempt indicates an expected call of RecordAttempt.
+func (mr *MockLoginAttemptMockRecorder) RecordAttempt(ctx, scope, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAttempt", reflect.TypeOf((*MockLoginAttempt)(nil).RecordAttempt), ctx, scope, subject)
+}
+
+// Release mocks base method.
+func (m *MockLoginAttempt) Release(ctx context.Context, scope, subject string, unlock bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, scope, subject, unlock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockLoginAttemptMockRecorder) Release(ctx, scope, subject, unlock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockLoginAttempt)(nil).Release), ctx, scope, subject, unlock)
+}
+
+// Reset mocks base method.
+func (m *MockLoginAttempt) Reset(ctx context.Context, scope, subject string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, scope, subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginAttemptMockRecorder) Reset(ctx, scope, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginAttempt)(nil).Reset), ctx, scope, subject)
+}
+
+// TryLock mocks base method.
+func (m *MockLoginAttempt) TryLock(ctx context.Context, scope, subject string, d time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLock", ctx, scope, subject, d)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryLock indicates an expected call of TryLock.
+func (mr *MockLoginAttemptMockRecorder) TryLock(ctx, scope, subject, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLock", reflect.TypeOf((*MockLoginAttempt)(nil).TryLock), ctx, scope, subject, d)
+}
@@ -0,0 +1,110 @@
+package login_attempt
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"time"
+)
+
+// LoginAttempt counts sign-in attempts of a subject, such as an email or an IP address,
+// and locks it out for a while. The scope tells subjects of different kinds apart.
+type LoginAttempt interface {
+	LockedFor(ctx context.Context, scope, subject string) (time.Duration, error)
+	RecordAttempt(ctx context.Context, scope, subject string) (int64, error)
+	TryLock(ctx context.Context, scope, subject string, d time.Duration) (bool, error)
+	Release(ctx context.Context, scope, subject string, unlock bool) error
+	Reset(ctx context.Context, scope, subject string) error
+}
+
+const (
+	// TTL_LoginFailures is how long attempts are counted; the count starts over this long
+	// after the first attempt.
+	TTL_LoginFailures = time.Hour
+
+	cacheKeyLoginFailures = "login:failures:%s:%s"
+	cacheKeyLoginLock     = "login:lock:%s:%s"
+)
+
+// LoginAttemptRepository only uses Redis: the counters are short-lived and losing them
+// merely resets the backoff.
+type LoginAttemptRepository struct {
+	redis *redis.Client
+}
+
+func NewLoginAttemptRepository(redis *redis.Client) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		redis: redis,
+	}
+}
+
+// LockedFor returns how long the subject is still locked out, or zero.
+func (r *LoginAttemptRepository) LockedFor(ctx context.Context, scope, subject string) (time.Duration, error) {
+	ttl, err := r.redis.PTTL(ctx, fmt.Sprintf(cacheKeyLoginLock, scope, subject)).Result()
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to read login lock, scope: %s, error: %v", scope, err)
+		return 0, err
+	}
+	// PTTL is negative for a missing key or a key without expiry.
+	return max(ttl, 0), nil
+}
+
+// RecordAttempt counts a sign-in attempt and returns the attempts within the window.
+func (r *LoginAttemptRepository) RecordAttempt(ctx context.Context, scope, subject string) (int64, error) {
+	key := fmt.Sprintf(cacheKeyLoginFailures, scope, subject)
+
+	var attempts *redis.IntCmd
+	_, err := r.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		attempts = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, TTL_LoginFailures)
+		return nil
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to count login attempt, scope: %s, error: %v", scope, err)
+		return 0, err
+	}
+	return attempts.Val(), nil
+}
+
+// TryLock locks the subject for d unless it is locked already, and reports whether it did.
+func (r *LoginAttemptRepository) TryLock(ctx context.Context, scope, subject string, d time.Duration) (bool, error) {
+	locked, err := r.redis.SetNX(ctx, fmt.Sprintf(cacheKeyLoginLock, scope, subject), 1, d).Result()
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to lock login, scope: %s, error: %v", scope, err)
+		return false, err
+	}
+	return locked, nil
+}
+
+// Release takes back an attempt that didn't fail, and the lock it took if unlock is set.
+func (r *LoginAttemptRepository) Release(ctx context.Context, scope, subject string, unlock bool) error {
+	key := fmt.Sprintf(cacheKeyLoginFailures, scope, subject)
+
+	_, err := r.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Decr(ctx, key)
+		pipe.ExpireNX(ctx, key, TTL_LoginFailures)
+		if unlock {
+			pipe.Del(ctx, fmt.Sprintf(cacheKeyLoginLock, scope, subject))
+		}
+		return nil
+	})
+	if err != nil {
+		zap.L().Sugar().Warnf("Failed to release login attempt, scope: %s, error: %v", scope, err)
+		return err
+	}
+	return nil
+}
+
+// Reset forgets the attempts and the lock of the subject after a successful sign-in.
+func (r *LoginAttemptRepository) Reset(ctx context.Context, scope, subject string) error {
+	keys := []string{
+		fmt.Sprintf(cacheKeyLoginFailures, scope, subject),
+		fmt.Sprintf(cacheKeyLoginLock, scope, subject),
+	}
+	if err := r.redis.Del(ctx, keys...).Err(); err != nil {
+		zap.L().Sugar().Warnf("Failed to reset login attempts, scope: %s, error: %v", scope, err)
+		return err
+	}
+	return nil
+}
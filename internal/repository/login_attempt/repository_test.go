@@ -0,0 +1,109 @@
+package login_attempt
+
+import (
+	"context"
+	"finly-backend/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestLoginAttemptRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("RecordAttempt", func(t *testing.T) {
+		sqlxDB, _, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewLoginAttemptRepository(redisClient)
+
+		for expected := int64(1); expected <= 3; expected++ {
+			failures, err := repo.RecordAttempt(ctx, "email", "test@example.com")
+			assert.NoError(t, err)
+			assert.Equal(t, expected, failures)
+		}
+		assert.Equal(t, TTL_LoginFailures, mr.TTL("login:failures:email:test@example.com"))
+
+		mr.FastForward(TTL_LoginFailures)
+		failures, err := repo.RecordAttempt(ctx, "email", "test@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), failures)
+	})
+
+	t.Run("Lock", func(t *testing.T) {
+		sqlxDB, _, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewLoginAttemptRepository(redisClient)
+
+		lockedFor, err := repo.LockedFor(ctx, "ip", "10.0.0.1")
+		assert.NoError(t, err)
+		assert.Zero(t, lockedFor)
+
+		locked, err := repo.TryLock(ctx, "ip", "10.0.0.1", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, locked)
+
+		locked, err = repo.TryLock(ctx, "ip", "10.0.0.1", time.Hour)
+		assert.NoError(t, err)
+		assert.False(t, locked)
+
+		lockedFor, err = repo.LockedFor(ctx, "ip", "10.0.0.1")
+		assert.NoError(t, err)
+		assert.Equal(t, time.Minute, lockedFor)
+
+		lockedFor, err = repo.LockedFor(ctx, "email", "10.0.0.1")
+		assert.NoError(t, err)
+		assert.Zero(t, lockedFor)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		sqlxDB, _, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewLoginAttemptRepository(redisClient)
+
+		_, err := repo.RecordAttempt(ctx, "email", "test@example.com")
+		assert.NoError(t, err)
+		_, err = repo.TryLock(ctx, "email", "test@example.com", time.Minute)
+		assert.NoError(t, err)
+
+		assert.NoError(t, repo.Reset(ctx, "email", "test@example.com"))
+		assert.False(t, mr.Exists("login:failures:email:test@example.com"))
+		assert.False(t, mr.Exists("login:lock:email:test@example.com"))
+	})
+	t.Run("Release", func(t *testing.T) {
+		sqlxDB, _, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewLoginAttemptRepository(redisClient)
+
+		for range 2 {
+			_, err := repo.RecordAttempt(ctx, "ip", "10.0.0.1")
+			assert.NoError(t, err)
+		}
+		_, err := repo.TryLock(ctx, "ip", "10.0.0.1", time.Minute)
+		assert.NoError(t, err)
+
+		assert.NoError(t, repo.Release(ctx, "ip", "10.0.0.1", false))
+		attempts, err := mr.Get("login:failures:ip:10.0.0.1")
+		assert.NoError(t, err)
+		assert.Equal(t, "1", attempts)
+		assert.True(t, mr.Exists("login:lock:ip:10.0.0.1"))
+
+		assert.NoError(t, repo.Release(ctx, "ip", "10.0.0.1", true))
+		attempts, err = mr.Get("login:failures:ip:10.0.0.1")
+		assert.NoError(t, err)
+		assert.Equal(t, "0", attempts)
+		assert.False(t, mr.Exists("login:lock:ip:10.0.0.1"))
+	})
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/password_reset/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/password_reset/repository.go -destination=internal/repository/password_reset/mock/mock_password_reset.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "finly-backend/internal/domain"
+	reflect "reflect"
+
+	sqlx "github.com/jmoiron/sqlx"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPasswordReset is a mock of PasswordReset interface.
+type MockPasswordReset struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordResetMockRecorder
+	isgomock struct{}
+}
+
+// MockPasswordResetMockRecorder is the mock recorder for MockPasswordReset.
+type MockPasswordResetMockRecorder struct {
+	mock *MockPasswordReset
+}
+
+// NewMockPasswordReset creates a new mock instance.
+func NewMockPasswordReset(ctrl *gomock.Controller) *MockPasswordReset {
+	mock := &MockPasswordReset{ctrl: ctrl}
+	mock.recorder = &MockPasswordResetMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordReset) EXPECT() *MockPasswordResetMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPasswordReset) Create(ctx context.Context, token *domain.PasswordResetToken) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPasswordResetMockRecorder) Create(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPasswordReset)(nil).Create), ctx, token)
+}
+
+// GetByHashTX mocks base method.
+func (m *MockPasswordReset) GetByHashTX(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*domain.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHashTX", ctx, tx, tokenHash)
+	ret0, _ := ret[0].(*domain.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHashTX indicates an expected call of GetByHashTX.
+func (mr *MockPasswordResetMockRecorder) GetByHashTX(ctx, tx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHashTX", reflect.TypeOf((*MockPasswordReset)(nil).GetByHashTX), ctx, tx, tokenHash)
+}
+
+// GetDB mocks base method.
+func (m *MockPasswordReset) GetDB() *sqlx.DB {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDB")
+	ret0, _ := ret[0].(*sqlx.DB)
+	return ret0
+}
+
+// GetDB indicates an expected call of GetDB.
+func (mr *MockPasswordResetMockRecorder) GetDB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDB", reflect.TypeOf((*MockPasswordReset)(nil).GetDB))
+}
+
+// MarkUsedByUserTX mocks base method.
+func (m *MockPasswordReset) MarkUsedByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUsedByUserTX", ctx, tx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkUsedByUserTX indicates an expected call of MarkUsedByUserTX.
+func (mr *MockPasswordResetMockRecorder) MarkUsedByUserTX(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUsedByUserTX", reflect.TypeOf((*MockPasswordReset)(nil).MarkUsedByUserTX), ctx, tx, userID)
+}
@@ -0,0 +1,74 @@
+package password_reset
+
+import (
+	"context"
+	"finly-backend/internal/domain"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type PasswordReset interface {
+	GetDB() *sqlx.DB
+	Create(ctx context.Context, token *domain.PasswordResetToken) (string, error)
+	GetByHashTX(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*domain.PasswordResetToken, error)
+	MarkUsedByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) error
+}
+
+const PasswordResetTokenTable = "password_reset_tokens"
+
+// PasswordResetRepository is not cached, as a token is read once and then used up.
+type PasswordResetRepository struct {
+	postgres *sqlx.DB
+	redis    *redis.Client
+}
+
+func NewPasswordResetRepository(postgres *sqlx.DB, redis *redis.Client) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		postgres: postgres,
+		redis:    redis,
+	}
+}
+
+func (r *PasswordResetRepository) GetDB() *sqlx.DB {
+	return r.postgres
+}
+
+func (r *PasswordResetRepository) Create(ctx context.Context, token *domain.PasswordResetToken) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING id", PasswordResetTokenTable)
+
+	var id string
+	if err := r.postgres.QueryRowContext(ctx, query, token.UserID, token.TokenHash, token.ExpiresAt).Scan(&id); err != nil {
+		zap.L().Sugar().Errorf("Failed to create password reset token, userID: %s, error: %v", token.UserID, err)
+		return "", err
+	}
+
+	zap.L().Sugar().Infof("Password reset token created, tokenID: %s, userID: %s", id, token.UserID)
+	return id, nil
+}
+
+// GetByHashTX looks a token up by its hash and locks it until the transaction ends, so
+// the same token cannot be used twice by concurrent requests.
+func (r *PasswordResetRepository) GetByHashTX(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+	query := fmt.Sprintf("SELECT * FROM %s WHERE token_hash = $1 FOR UPDATE", PasswordResetTokenTable)
+	if err := tx.GetContext(ctx, &token, query, tokenHash); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch password reset token, error: %v", err)
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsedByUserTX uses up every outstanding token of the user, so the links of earlier
+// requests stop working once the password has been reset.
+func (r *PasswordResetRepository) MarkUsedByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	query := fmt.Sprintf("UPDATE %s SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND used_at IS NULL", PasswordResetTokenTable)
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to mark password reset tokens as used, userID: %s, error: %v", userID, err)
+		return err
+	}
+
+	zap.L().Sugar().Infof("Password reset tokens used up, userID: %s", userID)
+	return nil
+}
@@ -0,0 +1,96 @@
+package password_reset
+
+import (
+	"context"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/testutil"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestPasswordResetRepository(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Create", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewPasswordResetRepository(sqlxDB, redisClient)
+
+		token := &domain.PasswordResetToken{UserID: "123", TokenHash: "hash", ExpiresAt: expiresAt}
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", PasswordResetTokenTable)).
+				WithArgs("123", "hash", expiresAt).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("token1"))
+
+			id, err := repo.Create(ctx, token)
+			assert.NoError(t, err)
+			assert.Equal(t, "token1", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", PasswordResetTokenTable)).
+				WithArgs("123", "hash", expiresAt).
+				WillReturnError(fmt.Errorf("db error"))
+
+			id, err := repo.Create(ctx, token)
+			assert.Error(t, err)
+			assert.Empty(t, id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("GetByHashTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewPasswordResetRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE token_hash = \\$1 FOR UPDATE", PasswordResetTokenTable)).
+				WithArgs("hash").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "token_hash", "expires_at", "used_at", "created_at"}).
+					AddRow("token1", "123", "hash", expiresAt, nil, expiresAt.Add(-time.Hour)))
+
+			tx, _ := sqlxDB.Beginx()
+			token, err := repo.GetByHashTX(ctx, tx, "hash")
+			assert.NoError(t, err)
+			assert.Equal(t, "123", token.UserID)
+			assert.False(t, token.UsedAt.Valid)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("MarkUsedByUserTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewPasswordResetRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET used_at = CURRENT_TIMESTAMP WHERE user_id = \\$1 AND used_at IS NULL", PasswordResetTokenTable)).
+				WithArgs("123").
+				WillReturnResult(sqlmock.NewResult(0, 2))
+
+			tx, _ := sqlxDB.Beginx()
+			err := repo.MarkUsedByUserTX(ctx, tx, "123")
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+}
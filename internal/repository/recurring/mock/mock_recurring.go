@@ -0,0 +1,265 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/recurring/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/recurring/repository.go -destination=internal/repository/recurring/mock/mock_recurring.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	sql "database/sql"
+	domain "finly-backend/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	sqlx "github.com/jmoiron/sqlx"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRecurring is a mock of Recurring interface.
+type MockRecurring struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecurringMockRecorder
+	isgomock struct{}
+}
+
+// MockRecurringMockRecorder is the mock recorder for MockRecurring.
+type MockRecurringMockRecorder struct {
+	mock *MockRecurring
+}
+
+// NewMockRecurring creates a new mock instance.
+func NewMockRecurring(ctrl *gomock.Controller) *MockRecurring {
+	mock := &MockRecurring{ctrl: ctrl}
+	mock.recorder = &MockRecurringMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecurring) EXPECT() *MockRecurringMockRecorder {
+	return m.recorder
+}
+
+// Advance mocks base method.
+func (m *MockRecurring) Advance(ctx context.Context, rule *domain.RecurringTransaction, lastRunAt time.Time, nextRunAt sql.NullTime) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Advance", ctx, rule, lastRunAt, nextRunAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Advance indicates an expected call of Advance.
+func (mr *MockRecurringMockRecorder) Advance(ctx, rule, lastRunAt, nextRunAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Advance", reflect.TypeOf((*MockRecurring)(nil).Advance), ctx, rule, lastRunAt, nextRunAt)
+}
+
+// CacheKeysByUserTX mocks base method.
+func (m *MockRecurring) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheKeysByUserTX", ctx, tx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CacheKeysByUserTX indicates an expected call of CacheKeysByUserTX.
+func (mr *MockRecurringMockRecorder) CacheKeysByUserTX(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheKeysByUserTX", reflect.TypeOf((*MockRecurring)(nil).CacheKeysByUserTX), ctx, tx, userID)
+}
+
+// ClaimRun mocks base method.
+func (m *MockRecurring) ClaimRun(ctx context.Context, ruleID string, occurrenceAt time.Time) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimRun", ctx, ruleID, occurrenceAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ClaimRun indicates an expected call of ClaimRun.
+func (mr *MockRecurringMockRecorder) ClaimRun(ctx, ruleID, occurrenceAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimRun", reflect.TypeOf((*MockRecurring)(nil).ClaimRun), ctx, ruleID, occurrenceAt)
+}
+
+// CompleteRun mocks base method.
+func (m *MockRecurring) CompleteRun(ctx context.Context, runID, transactionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRun", ctx, runID, transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteRun indicates an expected call of CompleteRun.
+func (mr *MockRecurringMockRecorder) CompleteRun(ctx, runID, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRun", reflect.TypeOf((*MockRecurring)(nil).CompleteRun), ctx, runID, transactionID)
+}
+
+// Create mocks base method.
+func (m *MockRecurring) Create(ctx context.Context, rule *domain.RecurringTransaction) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, rule)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRecurringMockRecorder) Create(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRecurring)(nil).Create), ctx, rule)
+}
+
+// Delete mocks base method.
+func (m *MockRecurring) Delete(ctx context.Context, ruleID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, ruleID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRecurringMockRecorder) Delete(ctx, ruleID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRecurring)(nil).Delete), ctx, ruleID, userID)
+}
+
+// FailRun mocks base method.
+func (m *MockRecurring) FailRun(ctx context.Context, runID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailRun", ctx, runID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailRun indicates an expected call of FailRun.
+func (mr *MockRecurringMockRecorder) FailRun(ctx, runID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailRun", reflect.TypeOf((*MockRecurring)(nil).FailRun), ctx, runID, reason)
+}
+
+// GetByID mocks base method.
+func (m *MockRecurring) GetByID(ctx context.Context, ruleID, userID string) (*domain.RecurringTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, ruleID, userID)
+	ret0, _ := ret[0].(*domain.RecurringTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRecurringMockRecorder) GetByID(ctx, ruleID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRecurring)(nil).GetByID), ctx, ruleID, userID)
+}
+
+// GetForRun mocks base method.
+func (m *MockRecurring) GetForRun(ctx context.Context, ruleID string) (*domain.RecurringTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForRun", ctx, ruleID)
+	ret0, _ := ret[0].(*domain.RecurringTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForRun indicates an expected call of GetForRun.
+func (mr *MockRecurringMockRecorder) GetForRun(ctx, ruleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForRun", reflect.TypeOf((*MockRecurring)(nil).GetForRun), ctx, ruleID)
+}
+
+// ListByUserID mocks base method.
+func (m *MockRecurring) ListByUserID(ctx context.Context, userID string) ([]*domain.RecurringTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*domain.RecurringTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockRecurringMockRecorder) ListByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockRecurring)(nil).ListByUserID), ctx, userID)
+}
+
+// ListDue mocks base method.
+func (m *MockRecurring) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.RecurringTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, now, limit)
+	ret0, _ := ret[0].([]*domain.RecurringTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockRecurringMockRecorder) ListDue(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockRecurring)(nil).ListDue), ctx, now, limit)
+}
+
+// ReassignCategoryTX mocks base method.
+func (m *MockRecurring) ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignCategoryTX", ctx, tx, fromCategoryID, toCategoryID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReassignCategoryTX indicates an expected call of ReassignCategoryTX.
+func (mr *MockRecurringMockRecorder) ReassignCategoryTX(ctx, tx, fromCategoryID, toCategoryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignCategoryTX", reflect.TypeOf((*MockRecurring)(nil).ReassignCategoryTX), ctx, tx, fromCategoryID, toCategoryID)
+}
+
+// ReleaseRun mocks base method.
+func (m *MockRecurring) ReleaseRun(ctx context.Context, runID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseRun", ctx, runID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseRun indicates an expected call of ReleaseRun.
+func (mr *MockRecurringMockRecorder) ReleaseRun(ctx, runID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseRun", reflect.TypeOf((*MockRecurring)(nil).ReleaseRun), ctx, runID)
+}
+
+// RunStatus mocks base method.
+func (m *MockRecurring) RunStatus(ctx context.Context, ruleID string, occurrenceAt time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunStatus", ctx, ruleID, occurrenceAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunStatus indicates an expected call of RunStatus.
+func (mr *MockRecurringMockRecorder) RunStatus(ctx, ruleID, occurrenceAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunStatus", reflect.TypeOf((*MockRecurring)(nil).RunStatus), ctx, ruleID, occurrenceAt)
+}
+
+// Update mocks base method.
+func (m *MockRecurring) Update(ctx context.Context, rule *domain.RecurringTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRecurringMockRecorder) Update(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRecurring)(nil).Update), ctx, rule)
+}
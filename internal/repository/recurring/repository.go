@@ -0,0 +1,338 @@
+package recurring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/db"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"time"
+)
+
+type Recurring interface {
+	Create(ctx context.Context, rule *domain.RecurringTransaction) (string, error)
+	GetByID(ctx context.Context, ruleID, userID string) (*domain.RecurringTransaction, error)
+	ListByUserID(ctx context.Context, userID string) ([]*domain.RecurringTransaction, error)
+	Update(ctx context.Context, rule *domain.RecurringTransaction) error
+	Delete(ctx context.Context, ruleID, userID string) error
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.RecurringTransaction, error)
+	GetForRun(ctx context.Context, ruleID string) (*domain.RecurringTransaction, error)
+	Advance(ctx context.Context, rule *domain.RecurringTransaction, lastRunAt time.Time, nextRunAt sql.NullTime) error
+	ClaimRun(ctx context.Context, ruleID string, occurrenceAt time.Time) (string, bool, error)
+	RunStatus(ctx context.Context, ruleID string, occurrenceAt time.Time) (string, error)
+	CompleteRun(ctx context.Context, runID, transactionID string) error
+	FailRun(ctx context.Context, runID, reason string) error
+	ReleaseRun(ctx context.Context, runID string) error
+	ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error)
+	CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error)
+}
+
+const (
+	RecurringTable    = "recurring_transactions"
+	RecurringRunTable = "recurring_transaction_runs"
+
+	TTL_ListRecurringByUserIDCache = 30 * time.Minute
+	TTL_GetRecurringByIDCache      = 30 * time.Minute
+
+	cacheKeyRecurringByUser      = "recurring:user:%s"
+	cacheKeyRecurringByIDAndUser = "recurring:%s:user:%s"
+
+	RunStatusPending   = "pending"
+	RunStatusCompleted = "completed"
+	RunStatusFailed    = "failed"
+
+	// StaleRunAfter is how long a run may stay pending before another tick takes it over.
+	StaleRunAfter = 10 * time.Minute
+)
+
+type RecurringRepository struct {
+	postgres *sqlx.DB
+	redis    *redis.Client
+}
+
+func NewRecurringRepository(postgres *sqlx.DB, redis *redis.Client) *RecurringRepository {
+	return &RecurringRepository{
+		postgres: postgres,
+		redis:    redis,
+	}
+}
+
+func (r *RecurringRepository) cacheKeys(userID, ruleID string) []string {
+	keys := []string{
+		fmt.Sprintf(cacheKeyRecurringByUser, userID),
+	}
+	if ruleID != "" {
+		keys = append(keys, fmt.Sprintf(cacheKeyRecurringByIDAndUser, ruleID, userID))
+	}
+	return keys
+}
+
+func (r *RecurringRepository) InvalidateCache(ctx context.Context, userID, ruleID string) error {
+	zap.L().Sugar().Infof("Invalidating recurring cache for userID: %s, ruleID: %s", userID, ruleID)
+
+	keys := r.cacheKeys(userID, ruleID)
+	if err := r.redis.Del(ctx, keys...).Err(); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate recurring cache for userID: %s, ruleID: %s, error: %v", userID, ruleID, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *RecurringRepository) Create(ctx context.Context, rule *domain.RecurringTransaction) (string, error) {
+	query := fmt.Sprintf(`INSERT INTO %s
+		(user_id, budget_id, category_id, amount, transaction_type, note, frequency, interval_count, cron_expr, start_at, end_at, next_run_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`, RecurringTable)
+
+	var id string
+	if err := r.postgres.QueryRowContext(ctx, query,
+		rule.UserID, rule.BudgetID, rule.CategoryID, rule.Amount, rule.TransactionType, rule.Note,
+		rule.Frequency, rule.IntervalCount, rule.CronExpr, rule.StartAt, rule.EndAt, rule.NextRunAt,
+	).Scan(&id); err != nil {
+		zap.L().Sugar().Errorf("Failed to create recurring transaction for userID: %s, error: %v", rule.UserID, err)
+		return "", err
+	}
+
+	if err := r.InvalidateCache(ctx, rule.UserID, id); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after create, userID: %s, error: %v", rule.UserID, err)
+	}
+
+	zap.L().Sugar().Infof("Recurring transaction created, ruleID: %s, userID: %s", id, rule.UserID)
+	return id, nil
+}
+
+func (r *RecurringRepository) GetByID(ctx context.Context, ruleID, userID string) (*domain.RecurringTransaction, error) {
+	if ruleID == "" || userID == "" {
+		return nil, fmt.Errorf("ruleID and userID cannot be empty")
+	}
+
+	cacheKey := fmt.Sprintf(cacheKeyRecurringByIDAndUser, ruleID, userID)
+
+	fetch := func() (*domain.RecurringTransaction, error) {
+		var rule domain.RecurringTransaction
+		query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1 AND user_id = $2", RecurringTable)
+		if err := r.postgres.GetContext(ctx, &rule, query, ruleID, userID); err != nil {
+			zap.L().Sugar().Errorf("Failed to fetch recurring transaction from DB, ruleID: %s, userID: %s, error: %v", ruleID, userID, err)
+			return nil, err
+		}
+		return &rule, nil
+	}
+
+	return db.WithCache(ctx, r.redis, cacheKey, TTL_GetRecurringByIDCache, fetch)
+}
+
+func (r *RecurringRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.RecurringTransaction, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("userID cannot be empty")
+	}
+
+	cacheKey := fmt.Sprintf(cacheKeyRecurringByUser, userID)
+
+	fetch := func() ([]*domain.RecurringTransaction, error) {
+		var rules []*domain.RecurringTransaction
+		query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1 ORDER BY created_at ASC", RecurringTable)
+		if err := r.postgres.SelectContext(ctx, &rules, query, userID); err != nil {
+			zap.L().Sugar().Errorf("Failed to fetch recurring transactions from DB, userID: %s, error: %v", userID, err)
+			return nil, err
+		}
+		return rules, nil
+	}
+
+	return db.WithCache(ctx, r.redis, cacheKey, TTL_ListRecurringByUserIDCache, fetch)
+}
+
+func (r *RecurringRepository) Update(ctx context.Context, rule *domain.RecurringTransaction) error {
+	query := fmt.Sprintf(`UPDATE %s
+		SET category_id = $1, amount = $2, note = $3, end_at = $4, next_run_at = $5, is_active = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $7 AND user_id = $8`, RecurringTable)
+	if _, err := r.postgres.ExecContext(ctx, query,
+		rule.CategoryID, rule.Amount, rule.Note, rule.EndAt, rule.NextRunAt, rule.IsActive, rule.ID, rule.UserID,
+	); err != nil {
+		zap.L().Sugar().Errorf("Failed to update recurring transaction, ruleID: %s, userID: %s, error: %v", rule.ID, rule.UserID, err)
+		return err
+	}
+
+	if err := r.InvalidateCache(ctx, rule.UserID, rule.ID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after update, ruleID: %s, error: %v", rule.ID, err)
+	}
+
+	zap.L().Sugar().Infof("Recurring transaction updated, ruleID: %s, userID: %s", rule.ID, rule.UserID)
+	return nil
+}
+
+func (r *RecurringRepository) Delete(ctx context.Context, ruleID, userID string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", RecurringTable)
+	if _, err := r.postgres.ExecContext(ctx, query, ruleID, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to delete recurring transaction, ruleID: %s, userID: %s, error: %v", ruleID, userID, err)
+		return err
+	}
+
+	if err := r.InvalidateCache(ctx, userID, ruleID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after delete, ruleID: %s, error: %v", ruleID, err)
+	}
+
+	zap.L().Sugar().Infof("Recurring transaction deleted, ruleID: %s, userID: %s", ruleID, userID)
+	return nil
+}
+
+// ListDue returns active rules whose next run is at or before now, oldest first.
+// It always reads from the database because the scheduler must not act on stale state.
+func (r *RecurringRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.RecurringTransaction, error) {
+	var rules []*domain.RecurringTransaction
+	query := fmt.Sprintf(`SELECT * FROM %s
+		WHERE is_active AND next_run_at IS NOT NULL AND next_run_at <= $1
+		ORDER BY next_run_at ASC LIMIT $2`, RecurringTable)
+	if err := r.postgres.SelectContext(ctx, &rules, query, now, limit); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch due recurring transactions, error: %v", err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetForRun reads the rule from the database, bypassing the cache, so the scheduler books an
+// occurrence with the rule as it is now rather than as it was listed.
+func (r *RecurringRepository) GetForRun(ctx context.Context, ruleID string) (*domain.RecurringTransaction, error) {
+	var rule domain.RecurringTransaction
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1", RecurringTable)
+	if err := r.postgres.GetContext(ctx, &rule, query, ruleID); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			zap.L().Sugar().Errorf("Failed to fetch recurring transaction for run, ruleID: %s, error: %v", ruleID, err)
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Advance moves the rule from the occurrence at lastRunAt to its next one. An invalid
+// nextRunAt means the schedule is exhausted and the rule is deactivated. It returns
+// sql.ErrNoRows if the rule is no longer due at lastRunAt, e.g. because it was edited or
+// deleted in the meantime, so a stale schedule never overwrites the new one.
+func (r *RecurringRepository) Advance(ctx context.Context, rule *domain.RecurringTransaction, lastRunAt time.Time, nextRunAt sql.NullTime) error {
+	query := fmt.Sprintf(`UPDATE %s
+		SET last_run_at = $1, next_run_at = $2, is_active = is_active AND $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND next_run_at = $1`, RecurringTable)
+	res, err := r.postgres.ExecContext(ctx, query, lastRunAt, nextRunAt, nextRunAt.Valid, rule.ID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to advance recurring transaction, ruleID: %s, error: %v", rule.ID, err)
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		zap.L().Sugar().Warnf("Recurring transaction is no longer due at %v, ruleID: %s", lastRunAt, rule.ID)
+		return sql.ErrNoRows
+	}
+
+	if err := r.InvalidateCache(ctx, rule.UserID, rule.ID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after advance, ruleID: %s, error: %v", rule.ID, err)
+	}
+
+	return nil
+}
+
+// ClaimRun reserves an occurrence before its transaction is created.
+// It reports false if the occurrence was already claimed, e.g. by an earlier tick or another instance.
+// A claim still pending after StaleRunAfter was left by an instance that stopped before booking the
+// occurrence, and is taken over so the occurrence is not lost.
+func (r *RecurringRepository) ClaimRun(ctx context.Context, ruleID string, occurrenceAt time.Time) (string, bool, error) {
+	query := fmt.Sprintf(`INSERT INTO %s AS run (recurring_transaction_id, occurrence_at, status) VALUES ($1, $2, $3)
+		ON CONFLICT (recurring_transaction_id, occurrence_at) DO UPDATE SET created_at = CURRENT_TIMESTAMP
+		WHERE run.status = $3 AND run.created_at < CURRENT_TIMESTAMP - make_interval(secs => $4)
+		RETURNING id`, RecurringRunTable)
+
+	var id string
+	if err := r.postgres.QueryRowContext(ctx, query, ruleID, occurrenceAt, RunStatusPending, StaleRunAfter.Seconds()).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+		zap.L().Sugar().Errorf("Failed to claim run, ruleID: %s, occurrenceAt: %v, error: %v", ruleID, occurrenceAt, err)
+		return "", false, err
+	}
+
+	return id, true, nil
+}
+
+// RunStatus returns the status of the run of an occurrence that could not be claimed. It
+// returns sql.ErrNoRows if the run was released in the meantime.
+func (r *RecurringRepository) RunStatus(ctx context.Context, ruleID string, occurrenceAt time.Time) (string, error) {
+	var status string
+	query := fmt.Sprintf("SELECT status FROM %s WHERE recurring_transaction_id = $1 AND occurrence_at = $2", RecurringRunTable)
+	if err := r.postgres.QueryRowContext(ctx, query, ruleID, occurrenceAt).Scan(&status); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			zap.L().Sugar().Errorf("Failed to get run status, ruleID: %s, occurrenceAt: %v, error: %v", ruleID, occurrenceAt, err)
+		}
+		return "", err
+	}
+	return status, nil
+}
+
+// CompleteRun marks a run as completed. transactionID is empty when the occurrence had already
+// been booked by an earlier attempt whose run was never completed.
+func (r *RecurringRepository) CompleteRun(ctx context.Context, runID, transactionID string) error {
+	query := fmt.Sprintf("UPDATE %s SET status = $1, transaction_id = $2 WHERE id = $3", RecurringRunTable)
+	if _, err := r.postgres.ExecContext(ctx, query, RunStatusCompleted, sql.NullString{String: transactionID, Valid: transactionID != ""}, runID); err != nil {
+		zap.L().Sugar().Errorf("Failed to complete run, runID: %s, error: %v", runID, err)
+		return err
+	}
+	return nil
+}
+
+func (r *RecurringRepository) FailRun(ctx context.Context, runID, reason string) error {
+	query := fmt.Sprintf("UPDATE %s SET status = $1, error = $2 WHERE id = $3", RecurringRunTable)
+	if _, err := r.postgres.ExecContext(ctx, query, RunStatusFailed, reason, runID); err != nil {
+		zap.L().Sugar().Errorf("Failed to mark run as failed, runID: %s, error: %v", runID, err)
+		return err
+	}
+	return nil
+}
+
+// ReleaseRun drops a claim so the occurrence is retried on the next tick.
+func (r *RecurringRepository) ReleaseRun(ctx context.Context, runID string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND status = $2", RecurringRunTable)
+	if _, err := r.postgres.ExecContext(ctx, query, runID, RunStatusPending); err != nil {
+		zap.L().Sugar().Errorf("Failed to release run, runID: %s, error: %v", runID, err)
+		return err
+	}
+	return nil
+}
+
+// ReassignCategoryTX moves every recurring rule of fromCategoryID to toCategoryID. It returns
+// the cache keys of the moved rules, to be deleted once tx is committed.
+func (r *RecurringRepository) ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error) {
+	var rules []*domain.RecurringTransaction
+	query := fmt.Sprintf(`UPDATE %s SET category_id = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE category_id = $2 RETURNING *`, RecurringTable)
+	if err := tx.SelectContext(ctx, &rules, query, toCategoryID, fromCategoryID); err != nil {
+		zap.L().Sugar().Errorf("Failed to reassign recurring rules, fromCategoryID: %s, toCategoryID: %s, error: %v", fromCategoryID, toCategoryID, err)
+		return nil, err
+	}
+
+	var keys []string
+	for _, rule := range rules {
+		keys = append(keys, r.cacheKeys(rule.UserID, rule.ID)...)
+	}
+	return keys, nil
+}
+
+// CacheKeysByUserTX lists the cache keys of the user's recurring rules.
+func (r *RecurringRepository) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	var ids []string
+	query := fmt.Sprintf("SELECT id FROM %s WHERE user_id = $1", RecurringTable)
+	if err := tx.SelectContext(ctx, &ids, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to list recurring rule IDs, userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+
+	keys := r.cacheKeys(userID, "")
+	for _, id := range ids {
+		keys = append(keys, r.cacheKeys(userID, id)[1:]...)
+	}
+	return keys, nil
+}
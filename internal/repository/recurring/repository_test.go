@@ -0,0 +1,307 @@
+package recurring
+
+import (
+	"context"
+	"database/sql"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/testutil"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestRecurringRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cacheKeys", func(t *testing.T) {
+		sqlxDB, _, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRecurringRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			expected := []string{
+				fmt.Sprintf(cacheKeyRecurringByUser, "123"),
+				fmt.Sprintf(cacheKeyRecurringByIDAndUser, "456", "123"),
+			}
+			assert.Equal(t, expected, repo.cacheKeys("123", "456"))
+		})
+
+		t.Run("WithoutRuleID", func(t *testing.T) {
+			assert.Equal(t, []string{fmt.Sprintf(cacheKeyRecurringByUser, "123")}, repo.cacheKeys("123", ""))
+		})
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRecurringRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+			rule := &domain.RecurringTransaction{
+				UserID: "123", BudgetID: "789", CategoryID: "101",
+				Amount: domain.MustParseMoney("12.50"), TransactionType: "withdrawal", Note: "Gym",
+				Frequency: "monthly", IntervalCount: 1, StartAt: start,
+				NextRunAt: sql.NullTime{Time: start, Valid: true},
+			}
+
+			redisClient.Set(ctx, fmt.Sprintf(cacheKeyRecurringByUser, "123"), "data", 0)
+
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", RecurringTable)).
+				WithArgs("123", "789", "101", rule.Amount, "withdrawal", "Gym", "monthly", 1, rule.CronExpr, start, rule.EndAt, rule.NextRunAt).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rule1"))
+
+			id, err := repo.Create(ctx, rule)
+			assert.NoError(t, err)
+			assert.Equal(t, "rule1", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(cacheKeyRecurringByUser, "123")).Result()
+			assert.Equal(t, int64(0), exists)
+		})
+	})
+
+	t.Run("ListDue", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRecurringRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			now := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s\\s+WHERE is_active AND next_run_at IS NOT NULL AND next_run_at <= \\$1\\s+ORDER BY next_run_at ASC LIMIT \\$2", RecurringTable)).
+				WithArgs(now, 100).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow("rule1", "123"))
+
+			rules, err := repo.ListDue(ctx, now, 100)
+			assert.NoError(t, err)
+			assert.Len(t, rules, 1)
+			assert.Equal(t, "rule1", rules[0].ID)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("Advance", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRecurringRepository(sqlxDB, redisClient)
+
+		t.Run("Deactivates exhausted rule", func(t *testing.T) {
+			last := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+			rule := &domain.RecurringTransaction{ID: "rule1", UserID: "123"}
+
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s", RecurringTable)).
+				WithArgs(last, sql.NullTime{}, false, "rule1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.Advance(ctx, rule, last, sql.NullTime{})
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("Rule changed in the meantime", func(t *testing.T) {
+			last := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+			next := sql.NullTime{Time: last.AddDate(0, 0, 1), Valid: true}
+			rule := &domain.RecurringTransaction{ID: "rule1", UserID: "123"}
+
+			redisClient.Set(ctx, fmt.Sprintf(cacheKeyRecurringByIDAndUser, "rule1", "123"), "data", 0)
+
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s(.+)WHERE id = \\$4 AND next_run_at = \\$1", RecurringTable)).
+				WithArgs(last, next, true, "rule1").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			err := repo.Advance(ctx, rule, last, next)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(cacheKeyRecurringByIDAndUser, "rule1", "123")).Result()
+			assert.Equal(t, int64(1), exists)
+		})
+	})
+
+	t.Run("GetForRun", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRecurringRepository(sqlxDB, redisClient)
+		query := fmt.Sprintf("SELECT \\* FROM %s WHERE id = \\$1", RecurringTable)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery(query).
+				WithArgs("rule1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow("rule1", "123"))
+
+			rule, err := repo.GetForRun(ctx, "rule1")
+			assert.NoError(t, err)
+			assert.Equal(t, "rule1", rule.ID)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("Deleted", func(t *testing.T) {
+			mock.ExpectQuery(query).
+				WithArgs("rule1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			_, err := repo.GetForRun(ctx, "rule1")
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ClaimRun", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRecurringRepository(sqlxDB, redisClient)
+		occurrenceAt := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+		query := fmt.Sprintf("INSERT INTO %s AS run \\(recurring_transaction_id, occurrence_at, status\\)(.+)WHERE run.status = \\$3 AND run.created_at <", RecurringRunTable)
+
+		t.Run("Claimed", func(t *testing.T) {
+			mock.ExpectQuery(query).
+				WithArgs("rule1", occurrenceAt, RunStatusPending, StaleRunAfter.Seconds()).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("run1"))
+
+			id, claimed, err := repo.ClaimRun(ctx, "rule1", occurrenceAt)
+			assert.NoError(t, err)
+			assert.True(t, claimed)
+			assert.Equal(t, "run1", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("AlreadyClaimed", func(t *testing.T) {
+			mock.ExpectQuery(query).
+				WithArgs("rule1", occurrenceAt, RunStatusPending, StaleRunAfter.Seconds()).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			id, claimed, err := repo.ClaimRun(ctx, "rule1", occurrenceAt)
+			assert.NoError(t, err)
+			assert.False(t, claimed)
+			assert.Empty(t, id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("RunStatus", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRecurringRepository(sqlxDB, redisClient)
+		occurrenceAt := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+		query := fmt.Sprintf("SELECT status FROM %s WHERE recurring_transaction_id = \\$1 AND occurrence_at = \\$2", RecurringRunTable)
+
+		t.Run("Pending", func(t *testing.T) {
+			mock.ExpectQuery(query).
+				WithArgs("rule1", occurrenceAt).
+				WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(RunStatusPending))
+
+			status, err := repo.RunStatus(ctx, "rule1", occurrenceAt)
+			assert.NoError(t, err)
+			assert.Equal(t, RunStatusPending, status)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("Released", func(t *testing.T) {
+			mock.ExpectQuery(query).
+				WithArgs("rule1", occurrenceAt).
+				WillReturnRows(sqlmock.NewRows([]string{"status"}))
+
+			_, err := repo.RunStatus(ctx, "rule1", occurrenceAt)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("CompleteRun", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRecurringRepository(sqlxDB, redisClient)
+		query := fmt.Sprintf("UPDATE %s SET status = \\$1, transaction_id = \\$2 WHERE id = \\$3", RecurringRunTable)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectExec(query).
+				WithArgs(RunStatusCompleted, sql.NullString{String: "t1", Valid: true}, "run1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.CompleteRun(ctx, "run1", "t1")
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("BookedEarlier", func(t *testing.T) {
+			mock.ExpectExec(query).
+				WithArgs(RunStatusCompleted, sql.NullString{}, "run1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.CompleteRun(ctx, "run1", "")
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+	t.Run("ReassignCategoryTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRecurringRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s SET category_id = \\$1(.+)WHERE category_id = \\$2 RETURNING", RecurringTable)).
+				WithArgs("category2", "category1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow("rule1", "user1"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.ReassignCategoryTX(ctx, tx, "category1", "category2")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{fmt.Sprintf(cacheKeyRecurringByUser, "user1"), fmt.Sprintf(cacheKeyRecurringByIDAndUser, "rule1", "user1")}, keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("CacheKeysByUserTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRecurringRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT id FROM %s WHERE user_id", RecurringTable)).
+				WithArgs("user1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rule1"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.CacheKeysByUserTX(ctx, tx, "user1")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{fmt.Sprintf(cacheKeyRecurringByUser, "user1"), fmt.Sprintf(cacheKeyRecurringByIDAndUser, "rule1", "user1")}, keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+}
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/refresh_token/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/refresh_token/repository.go -destination=internal/repository/refresh_token/mock/mock_refresh_token.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "finly-backend/internal/domain"
+	reflect "reflect"
+
+	sqlx "github.com/jmoiron/sqlx"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRefreshToken is a mock of RefreshToken interface.
+type MockRefreshToken struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokenMockRecorder
+	isgomock struct{}
+}
+
+// MockRefreshTokenMockRecorder is the mock recorder for MockRefreshToken.
+type MockRefreshTokenMockRecorder struct {
+	mock *MockRefreshToken
+}
+
+// NewMockRefreshToken creates a new mock instance.
+func NewMockRefreshToken(ctrl *gomock.Controller) *MockRefreshToken {
+	mock := &MockRefreshToken{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokenMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshToken) EXPECT() *MockRefreshTokenMockRecorder {
+	return m.recorder
+}
+
+// CreateTX mocks base method.
+func (m *MockRefreshToken) CreateTX(ctx context.Context, tx *sqlx.Tx, token *domain.RefreshToken) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTX", ctx, tx, token)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTX indicates an expected call of CreateTX.
+func (mr *MockRefreshTokenMockRecorder) CreateTX(ctx, tx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTX", reflect.TypeOf((*MockRefreshToken)(nil).CreateTX), ctx, tx, token)
+}
+
+// GetByHashTX mocks base method.
+func (m *MockRefreshToken) GetByHashTX(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*domain.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHashTX", ctx, tx, tokenHash)
+	ret0, _ := ret[0].(*domain.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHashTX indicates an expected call of GetByHashTX.
+func (mr *MockRefreshTokenMockRecorder) GetByHashTX(ctx, tx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHashTX", reflect.TypeOf((*MockRefreshToken)(nil).GetByHashTX), ctx, tx, tokenHash)
+}
+
+// GetDB mocks base method.
+func (m *MockRefreshToken) GetDB() *sqlx.DB {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDB")
+	ret0, _ := ret[0].(*sqlx.DB)
+	return ret0
+}
+
+// GetDB indicates an expected call of GetDB.
+func (mr *MockRefreshTokenMockRecorder) GetDB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDB", reflect.TypeOf((*MockRefreshToken)(nil).GetDB))
+}
+
+// MarkRotatedTX mocks base method.
+func (m *MockRefreshToken) MarkRotatedTX(ctx context.Context, tx *sqlx.Tx, tokenID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRotatedTX", ctx, tx, tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRotatedTX indicates an expected call of MarkRotatedTX.
+func (mr *MockRefreshTokenMockRecorder) MarkRotatedTX(ctx, tx, tokenID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRotatedTX", reflect.TypeOf((*MockRefreshToken)(nil).MarkRotatedTX), ctx, tx, tokenID)
+}
+
+// RevokeFamilyTX mocks base method.
+func (m *MockRefreshToken) RevokeFamilyTX(ctx context.Context, tx *sqlx.Tx, familyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamilyTX", ctx, tx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamilyTX indicates an expected call of RevokeFamilyTX.
+func (mr *MockRefreshTokenMockRecorder) RevokeFamilyTX(ctx, tx, familyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamilyTX", reflect.TypeOf((*MockRefreshToken)(nil).RevokeFamilyTX), ctx, tx, familyID)
+}
@@ -0,0 +1,83 @@
+package refresh_token
+
+import (
+	"context"
+	"finly-backend/internal/domain"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+type RefreshToken interface {
+	GetDB() *sqlx.DB
+	CreateTX(ctx context.Context, tx *sqlx.Tx, token *domain.RefreshToken) (string, error)
+	GetByHashTX(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*domain.RefreshToken, error)
+	MarkRotatedTX(ctx context.Context, tx *sqlx.Tx, tokenID string) error
+	RevokeFamilyTX(ctx context.Context, tx *sqlx.Tx, familyID string) error
+}
+
+const RefreshTokenTable = "refresh_tokens"
+
+// RefreshTokenRepository is not cached: every lookup has to see rotations and
+// revocations that happened a moment before.
+type RefreshTokenRepository struct {
+	postgres *sqlx.DB
+}
+
+func NewRefreshTokenRepository(postgres *sqlx.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		postgres: postgres,
+	}
+}
+
+func (r *RefreshTokenRepository) GetDB() *sqlx.DB {
+	return r.postgres
+}
+
+func (r *RefreshTokenRepository) CreateTX(ctx context.Context, tx *sqlx.Tx, token *domain.RefreshToken) (string, error) {
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4) RETURNING id`, RefreshTokenTable)
+
+	var id string
+	if err := tx.QueryRowxContext(ctx, query, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt).Scan(&id); err != nil {
+		zap.L().Sugar().Errorf("Failed to create refresh token, userID: %s, familyID: %s, error: %v", token.UserID, token.FamilyID, err)
+		return "", err
+	}
+
+	zap.L().Sugar().Infof("Refresh token created, tokenID: %s, familyID: %s", id, token.FamilyID)
+	return id, nil
+}
+
+// GetByHashTX looks a token up by its hash and locks it until the transaction ends,
+// so two concurrent refreshes with the same token cannot both rotate it.
+func (r *RefreshTokenRepository) GetByHashTX(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	query := fmt.Sprintf("SELECT * FROM %s WHERE token_hash = $1 FOR UPDATE", RefreshTokenTable)
+	if err := tx.GetContext(ctx, &token, query, tokenHash); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch refresh token, error: %v", err)
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *RefreshTokenRepository) MarkRotatedTX(ctx context.Context, tx *sqlx.Tx, tokenID string) error {
+	query := fmt.Sprintf("UPDATE %s SET rotated_at = CURRENT_TIMESTAMP WHERE id = $1", RefreshTokenTable)
+	if _, err := tx.ExecContext(ctx, query, tokenID); err != nil {
+		zap.L().Sugar().Errorf("Failed to mark refresh token as rotated, tokenID: %s, error: %v", tokenID, err)
+		return err
+	}
+	return nil
+}
+
+// RevokeFamilyTX revokes every token of a family, so the session it belongs to can't
+// be refreshed anymore.
+func (r *RefreshTokenRepository) RevokeFamilyTX(ctx context.Context, tx *sqlx.Tx, familyID string) error {
+	query := fmt.Sprintf("UPDATE %s SET revoked_at = CURRENT_TIMESTAMP WHERE family_id = $1 AND revoked_at IS NULL", RefreshTokenTable)
+	if _, err := tx.ExecContext(ctx, query, familyID); err != nil {
+		zap.L().Sugar().Errorf("Failed to revoke refresh token family, familyID: %s, error: %v", familyID, err)
+		return err
+	}
+
+	zap.L().Sugar().Infof("Refresh token family revoked, familyID: %s", familyID)
+	return nil
+}
@@ -0,0 +1,130 @@
+package refresh_token
+
+import (
+	"context"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/testutil"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenRepository(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("CreateTX", func(t *testing.T) {
+		sqlxDB, mock, _, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRefreshTokenRepository(sqlxDB)
+
+		token := &domain.RefreshToken{UserID: "123", FamilyID: "fam1", TokenHash: "hash", ExpiresAt: expiresAt}
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", RefreshTokenTable)).
+				WithArgs("123", "fam1", "hash", expiresAt).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("token1"))
+
+			tx, _ := sqlxDB.Beginx()
+			id, err := repo.CreateTX(ctx, tx, token)
+			assert.NoError(t, err)
+			assert.Equal(t, "token1", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", RefreshTokenTable)).
+				WithArgs("123", "fam1", "hash", expiresAt).
+				WillReturnError(fmt.Errorf("db error"))
+
+			tx, _ := sqlxDB.Beginx()
+			id, err := repo.CreateTX(ctx, tx, token)
+			assert.Error(t, err)
+			assert.Empty(t, id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("GetByHashTX", func(t *testing.T) {
+		sqlxDB, mock, _, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRefreshTokenRepository(sqlxDB)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE token_hash = \\$1 FOR UPDATE", RefreshTokenTable)).
+				WithArgs("hash").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "family_id", "token_hash", "expires_at", "rotated_at", "revoked_at", "created_at"}).
+					AddRow("token1", "123", "fam1", "hash", expiresAt, nil, nil, expiresAt.Add(-time.Hour)))
+
+			tx, _ := sqlxDB.Beginx()
+			token, err := repo.GetByHashTX(ctx, tx, "hash")
+			assert.NoError(t, err)
+			assert.Equal(t, "fam1", token.FamilyID)
+			assert.False(t, token.RotatedAt.Valid)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("MarkRotatedTX", func(t *testing.T) {
+		sqlxDB, mock, _, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRefreshTokenRepository(sqlxDB)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET rotated_at", RefreshTokenTable)).
+				WithArgs("token1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			tx, _ := sqlxDB.Beginx()
+			assert.NoError(t, repo.MarkRotatedTX(ctx, tx, "token1"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("RevokeFamilyTX", func(t *testing.T) {
+		sqlxDB, mock, _, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewRefreshTokenRepository(sqlxDB)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET revoked_at = CURRENT_TIMESTAMP WHERE family_id = \\$1", RefreshTokenTable)).
+				WithArgs("fam1").
+				WillReturnResult(sqlmock.NewResult(0, 3))
+
+			tx, _ := sqlxDB.Beginx()
+			assert.NoError(t, repo.RevokeFamilyTX(ctx, tx, "fam1"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET revoked_at", RefreshTokenTable)).
+				WithArgs("fam1").
+				WillReturnError(fmt.Errorf("db error"))
+
+			tx, _ := sqlxDB.Beginx()
+			assert.Error(t, repo.RevokeFamilyTX(ctx, tx, "fam1"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+}
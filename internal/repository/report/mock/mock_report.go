@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/report/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/report/repository.go -destination=internal/repository/report/mock/mock_report.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "finly-backend/internal/domain"
+	e_report_granularity "finly-backend/internal/domain/enums/e_report_granularity"
+	report "finly-backend/internal/repository/report"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReport is a mock of Report interface.
+type MockReport struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportMockRecorder
+	isgomock struct{}
+}
+
+// MockReportMockRecorder is the mock recorder for MockReport.
+type MockReportMockRecorder struct {
+	mock *MockReport
+}
+
+// NewMockReport creates a new mock instance.
+func NewMockReport(ctrl *gomock.Controller) *MockReport {
+	mock := &MockReport{ctrl: ctrl}
+	mock.recorder = &MockReportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReport) EXPECT() *MockReportMockRecorder {
+	return m.recorder
+}
+
+// ByCategory mocks base method.
+func (m *MockReport) ByCategory(ctx context.Context, filter report.Filter) ([]*domain.CategoryTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ByCategory", ctx, filter)
+	ret0, _ := ret[0].([]*domain.CategoryTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ByCategory indicates an expected call of ByCategory.
+func (mr *MockReportMockRecorder) ByCategory(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ByCategory", reflect.TypeOf((*MockReport)(nil).ByCategory), ctx, filter)
+}
+
+// ByPeriod mocks base method.
+func (m *MockReport) ByPeriod(ctx context.Context, filter report.Filter, granularity e_report_granularity.Enum) ([]*domain.PeriodTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ByPeriod", ctx, filter, granularity)
+	ret0, _ := ret[0].([]*domain.PeriodTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ByPeriod indicates an expected call of ByPeriod.
+func (mr *MockReportMockRecorder) ByPeriod(ctx, filter, granularity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ByPeriod", reflect.TypeOf((*MockReport)(nil).ByPeriod), ctx, filter, granularity)
+}
+
+// Totals mocks base method.
+func (m *MockReport) Totals(ctx context.Context, filter report.Filter) (*domain.CashFlow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Totals", ctx, filter)
+	ret0, _ := ret[0].(*domain.CashFlow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Totals indicates an expected call of Totals.
+func (mr *MockReportMockRecorder) Totals(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Totals", reflect.TypeOf((*MockReport)(nil).Totals), ctx, filter)
+}
@@ -0,0 +1,173 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_report_granularity"
+	"finly-backend/internal/repository/category"
+	"finly-backend/internal/repository/transaction"
+	"finly-backend/pkg/db"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"time"
+)
+
+type Report interface {
+	Totals(ctx context.Context, filter Filter) (*domain.CashFlow, error)
+	ByCategory(ctx context.Context, filter Filter) ([]*domain.CategoryTotal, error)
+	ByPeriod(ctx context.Context, filter Filter, granularity e_report_granularity.Enum) ([]*domain.PeriodTotal, error)
+}
+
+const (
+	TTL_ReportCache = 10 * time.Minute
+
+	cacheKeyTotals     = "report:totals:user:%s:budget:%s:from:%d:to:%d:v%d"
+	cacheKeyByCategory = "report:category:user:%s:budget:%s:from:%d:to:%d:c%d:v%d"
+	cacheKeyByPeriod   = "report:period:%s:user:%s:budget:%s:from:%d:to:%d:v%d"
+
+	cashFlowColumns = `COALESCE(SUM(amount) FILTER (WHERE transaction_type = 'deposit'), 0) AS income,
+		COALESCE(SUM(amount) FILTER (WHERE transaction_type = 'withdrawal'), 0) AS expense,
+		COALESCE(SUM(amount) FILTER (WHERE transaction_type = 'transfer_in'), 0) AS transfers_in,
+		COALESCE(SUM(amount) FILTER (WHERE transaction_type = 'transfer_out'), 0) AS transfers_out,
+		COUNT(*) AS count`
+)
+
+// Filter selects the transactions of a user's budget created within [From, To).
+type Filter struct {
+	UserID   string
+	BudgetID string
+	From     time.Time
+	To       time.Time
+}
+
+type ReportRepository struct {
+	postgres *sqlx.DB
+	redis    *redis.Client
+}
+
+func NewReportRepository(postgres *sqlx.DB, redis *redis.Client) *ReportRepository {
+	return &ReportRepository{
+		postgres: postgres,
+		redis:    redis,
+	}
+}
+
+// version returns the user's transaction version, which is part of every report cache key.
+// Reports are never deleted from the cache; a write to the transactions bumps the version
+// and the stale entries expire on their own.
+func (r *ReportRepository) version(ctx context.Context, userID string) (int64, error) {
+	version, err := r.redis.Get(ctx, fmt.Sprintf(transaction.CacheKeyVersion, userID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return version, err
+}
+
+// withCache caches a report under the key built by format and args with the current
+// version appended. When the version cannot be read the report is computed uncached.
+func withCache[T any](ctx context.Context, r *ReportRepository, userID, format string, fetch func() (T, error), args ...any) (T, error) {
+	version, err := r.version(ctx, userID)
+	if err != nil {
+		zap.L().Sugar().Warnf("Failed to read transaction version, userID: %s, error: %v", userID, err)
+		return fetch()
+	}
+
+	cacheKey := fmt.Sprintf(format, append(args, version)...)
+	return db.WithCache(ctx, r.redis, cacheKey, TTL_ReportCache, fetch)
+}
+
+func (r *ReportRepository) Totals(ctx context.Context, filter Filter) (*domain.CashFlow, error) {
+	fetch := func() (*domain.CashFlow, error) {
+		var totals domain.CashFlow
+		query := fmt.Sprintf(`SELECT %s FROM %s
+			WHERE user_id = $1 AND budget_id = $2 AND created_at >= $3 AND created_at < $4`,
+			cashFlowColumns, transaction.TransactionTable)
+		if err := r.postgres.GetContext(ctx, &totals, query, filter.UserID, filter.BudgetID, filter.From, filter.To); err != nil {
+			zap.L().Sugar().Errorf("Failed to aggregate totals, userID: %s, budgetID: %s, error: %v", filter.UserID, filter.BudgetID, err)
+			return nil, err
+		}
+		return &totals, nil
+	}
+
+	return withCache(ctx, r, filter.UserID, cacheKeyTotals, fetch,
+		filter.UserID, filter.BudgetID, filter.From.Unix(), filter.To.Unix())
+}
+
+// ByCategory sums deposits and withdrawals per top-level category, largest expense first.
+// Transactions of subcategories count towards the category at the root of their tree, which
+// is named as the user renamed it. The walk never revisits a category, so a cycle in the
+// stored tree cannot make it recurse forever.
+// Transfers have no category of their own and are left out.
+func (r *ReportRepository) ByCategory(ctx context.Context, filter Filter) ([]*domain.CategoryTotal, error) {
+	fetch := func() ([]*domain.CategoryTotal, error) {
+		var totals []*domain.CategoryTotal
+		query := fmt.Sprintf(`WITH RECURSIVE tree AS (
+				SELECT c.id, c.id AS root_id, COALESCE(o.name, c.name) AS root_name, ARRAY[c.id] AS path FROM %[2]s c
+				LEFT JOIN %[3]s o ON o.category_id = c.id AND o.user_id = $1
+				WHERE c.parent_id IS NULL AND (c.user_id = $1 OR c.user_id IS NULL)
+				UNION ALL
+				SELECT c.id, tree.root_id, tree.root_name, tree.path || c.id FROM %[2]s c
+				JOIN tree ON c.parent_id = tree.id
+				WHERE c.user_id = $1 AND NOT c.id = ANY(tree.path)
+			)
+			SELECT COALESCE(tree.root_id, t.category_id) AS category_id, COALESCE(tree.root_name, '') AS category_name,
+				COALESCE(SUM(t.amount) FILTER (WHERE t.transaction_type = 'deposit'), 0) AS income,
+				COALESCE(SUM(t.amount) FILTER (WHERE t.transaction_type = 'withdrawal'), 0) AS expense,
+				COUNT(*) AS count
+			FROM %[1]s t
+			LEFT JOIN tree ON tree.id = t.category_id
+			WHERE t.user_id = $1 AND t.budget_id = $2 AND t.created_at >= $3 AND t.created_at < $4
+				AND t.transaction_type IN ('deposit', 'withdrawal')
+			GROUP BY 1, 2
+			ORDER BY expense DESC, income DESC, category_id`,
+			transaction.TransactionTable, category.CategoryTable, category.CategoryOverrideTable)
+		if err := r.postgres.SelectContext(ctx, &totals, query, filter.UserID, filter.BudgetID, filter.From, filter.To); err != nil {
+			zap.L().Sugar().Errorf("Failed to aggregate totals by category, userID: %s, budgetID: %s, error: %v", filter.UserID, filter.BudgetID, err)
+			return nil, err
+		}
+		return totals, nil
+	}
+
+	return withCache(ctx, r, filter.UserID, cacheKeyByCategory, fetch,
+		filter.UserID, filter.BudgetID, filter.From.Unix(), filter.To.Unix(), r.categoryVersion(ctx, filter.UserID))
+}
+
+// categoryVersion returns the version of the user's category tree, so that totals by
+// category are recomputed after categories are moved or deleted.
+func (r *ReportRepository) categoryVersion(ctx context.Context, userID string) int64 {
+	version, err := r.redis.Get(ctx, fmt.Sprintf(category.CacheKeyVersion, userID)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		zap.L().Sugar().Warnf("Failed to read category version, userID: %s, error: %v", userID, err)
+	}
+	return version
+}
+
+// ByPeriod returns the cash flow of every day, week or month that has transactions, oldest first.
+// Weeks start on Monday, as date_trunc defines them.
+func (r *ReportRepository) ByPeriod(ctx context.Context, filter Filter, granularity e_report_granularity.Enum) ([]*domain.PeriodTotal, error) {
+	if !granularity.IsValid() {
+		return nil, fmt.Errorf("invalid granularity: %s", granularity)
+	}
+
+	fetch := func() ([]*domain.PeriodTotal, error) {
+		var totals []*domain.PeriodTotal
+		query := fmt.Sprintf(`SELECT date_trunc($5, created_at) AS period, %s FROM %s
+			WHERE user_id = $1 AND budget_id = $2 AND created_at >= $3 AND created_at < $4
+			GROUP BY period
+			ORDER BY period`,
+			cashFlowColumns, transaction.TransactionTable)
+		if err := r.postgres.SelectContext(ctx, &totals, query,
+			filter.UserID, filter.BudgetID, filter.From, filter.To, granularity.String(),
+		); err != nil {
+			zap.L().Sugar().Errorf("Failed to aggregate totals by %s, userID: %s, budgetID: %s, error: %v", granularity, filter.UserID, filter.BudgetID, err)
+			return nil, err
+		}
+		return totals, nil
+	}
+
+	return withCache(ctx, r, filter.UserID, cacheKeyByPeriod, fetch,
+		granularity.String(), filter.UserID, filter.BudgetID, filter.From.Unix(), filter.To.Unix())
+}
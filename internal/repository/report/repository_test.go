@@ -0,0 +1,167 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_report_granularity"
+	"finly-backend/internal/repository/category"
+	"finly-backend/internal/repository/transaction"
+	"finly-backend/pkg/testutil"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestReportRepository(t *testing.T) {
+	ctx := context.Background()
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	filter := Filter{UserID: "123", BudgetID: "789", From: from, To: to}
+	cashFlowColumns := []string{"income", "expense", "transfers_in", "transfers_out", "count"}
+
+	t.Run("Totals", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewReportRepository(sqlxDB, redisClient)
+
+		expected := &domain.CashFlow{
+			Income:       domain.MustParseMoney("1500.00"),
+			Expense:      domain.MustParseMoney("420.50"),
+			TransfersIn:  domain.MustParseMoney("0.00"),
+			TransfersOut: domain.MustParseMoney("100.00"),
+			Count:        7,
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("SELECT (.+) FROM %s", transaction.TransactionTable)).
+				WithArgs("123", "789", from, to).
+				WillReturnRows(sqlmock.NewRows(cashFlowColumns).AddRow("1500.00", "420.50", "0.00", "100.00", 7))
+
+			totals, err := repo.Totals(ctx, filter)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, totals)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("Cached", func(t *testing.T) {
+			totals, err := repo.Totals(ctx, filter)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, totals)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("StaleAfterTransactionWrite", func(t *testing.T) {
+			redisClient.Incr(ctx, fmt.Sprintf(transaction.CacheKeyVersion, "123"))
+
+			mock.ExpectQuery(fmt.Sprintf("SELECT (.+) FROM %s", transaction.TransactionTable)).
+				WithArgs("123", "789", from, to).
+				WillReturnRows(sqlmock.NewRows(cashFlowColumns).AddRow("1500.00", "500.00", "0.00", "100.00", 8))
+
+			totals, err := repo.Totals(ctx, filter)
+			assert.NoError(t, err)
+			assert.Equal(t, domain.MustParseMoney("500.00"), totals.Expense)
+			assert.Equal(t, 8, totals.Count)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DBError", func(t *testing.T) {
+			other := Filter{UserID: "123", BudgetID: "000", From: from, To: to}
+			mock.ExpectQuery(fmt.Sprintf("SELECT (.+) FROM %s", transaction.TransactionTable)).
+				WithArgs("123", "000", from, to).
+				WillReturnError(fmt.Errorf("db error"))
+
+			totals, err := repo.Totals(ctx, other)
+			assert.Error(t, err)
+			assert.Nil(t, totals)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ByCategory", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewReportRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery("WITH RECURSIVE tree (.+) NOT c.id = ANY\\(tree.path\\)(.+) LEFT JOIN tree ON tree.id = t.category_id").
+				WithArgs("123", "789", from, to).
+				WillReturnRows(sqlmock.NewRows([]string{"category_id", "category_name", "income", "expense", "count"}).
+					AddRow("c1", "Groceries", "0.00", "320.00", 5).
+					AddRow("c2", "Salary", "1500.00", "0.00", 1))
+
+			totals, err := repo.ByCategory(ctx, filter)
+			assert.NoError(t, err)
+			assert.Len(t, totals, 2)
+			assert.Equal(t, "Groceries", totals[0].CategoryName)
+			assert.Equal(t, domain.MustParseMoney("320.00"), totals[0].Expense)
+			assert.Equal(t, domain.MustParseMoney("1500.00"), totals[1].Income)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			cached, err := redisClient.Get(ctx, fmt.Sprintf(cacheKeyByCategory, "123", "789", from.Unix(), to.Unix(), 0, 0)).Result()
+			assert.NoError(t, err)
+			var decoded []*domain.CategoryTotal
+			assert.NoError(t, json.Unmarshal([]byte(cached), &decoded))
+			assert.Equal(t, totals, decoded)
+		})
+
+		t.Run("CategoryTreeChanged", func(t *testing.T) {
+			redisClient.Set(ctx, fmt.Sprintf(category.CacheKeyVersion, "123"), 2, 0)
+
+			mock.ExpectQuery("WITH RECURSIVE tree (.+) LEFT JOIN tree ON tree.id = t.category_id").
+				WithArgs("123", "789", from, to).
+				WillReturnRows(sqlmock.NewRows([]string{"category_id", "category_name", "income", "expense", "count"}).
+					AddRow("c3", "Food", "0.00", "410.00", 7))
+
+			totals, err := repo.ByCategory(ctx, filter)
+			assert.NoError(t, err)
+			assert.Len(t, totals, 1)
+			assert.Equal(t, "Food", totals[0].CategoryName)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(cacheKeyByCategory, "123", "789", from.Unix(), to.Unix(), 2, 0)).Result()
+			assert.Equal(t, int64(1), exists)
+		})
+	})
+
+	t.Run("ByPeriod", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewReportRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			week := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+			mock.ExpectQuery("SELECT date_trunc(.+) GROUP BY period").
+				WithArgs("123", "789", from, to, "week").
+				WillReturnRows(sqlmock.NewRows(append([]string{"period"}, cashFlowColumns...)).
+					AddRow(week, "0.00", "80.00", "0.00", "0.00", 2))
+
+			totals, err := repo.ByPeriod(ctx, filter, e_report_granularity.Week)
+			assert.NoError(t, err)
+			assert.Len(t, totals, 1)
+			assert.Equal(t, week, totals[0].Period)
+			assert.Equal(t, domain.MustParseMoney("80.00"), totals[0].Expense)
+			assert.Equal(t, 2, totals[0].Count)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("InvalidGranularity", func(t *testing.T) {
+			totals, err := repo.ByPeriod(ctx, filter, "year")
+			assert.Error(t, err)
+			assert.Nil(t, totals)
+		})
+	})
+}
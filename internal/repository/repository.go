@@ -1,11 +1,24 @@
 package repository
 
 import (
+	"finly-backend/internal/repository/api_key"
 	"finly-backend/internal/repository/auth"
 	"finly-backend/internal/repository/budget"
 	"finly-backend/internal/repository/budget_history"
+	"finly-backend/internal/repository/budget_invitation"
 	"finly-backend/internal/repository/category"
+	"finly-backend/internal/repository/category_limit"
+	"finly-backend/internal/repository/data_export"
+	"finly-backend/internal/repository/email_verification"
+	"finly-backend/internal/repository/login_attempt"
+	"finly-backend/internal/repository/password_reset"
+	"finly-backend/internal/repository/recurring"
+	"finly-backend/internal/repository/refresh_token"
+	"finly-backend/internal/repository/report"
+	"finly-backend/internal/repository/session"
 	"finly-backend/internal/repository/transaction"
+	"finly-backend/internal/repository/transaction_rule"
+	"finly-backend/internal/repository/two_factor"
 	"github.com/jmoiron/sqlx"
 	"github.com/redis/go-redis/v9"
 )
@@ -16,14 +29,40 @@ type Repository struct {
 	category.Category
 	transaction.Transaction
 	budget_history.BudgetHistory
+	recurring.Recurring
+	category_limit.CategoryLimit
+	report.Report
+	refresh_token.RefreshToken
+	session.Session
+	password_reset.PasswordReset
+	email_verification.EmailVerification
+	two_factor.TwoFactor
+	login_attempt.LoginAttempt
+	data_export.DataExport
+	api_key.APIKey
+	budget_invitation.BudgetInvitation
+	transaction_rule.TransactionRule
 }
 
 func NewRepository(postgres *sqlx.DB, redis *redis.Client) *Repository {
 	return &Repository{
-		Auth:          auth.NewAuthRepository(postgres, redis),
-		Budget:        budget.NewBudgetRepository(postgres, redis),
-		Category:      category.NewCategoryRepository(postgres, redis),
-		Transaction:   transaction.NewTransactionRepository(postgres, redis),
-		BudgetHistory: budget_history.NewBudgetHistoryRepository(postgres, redis),
+		Auth:              auth.NewAuthRepository(postgres, redis),
+		Budget:            budget.NewBudgetRepository(postgres, redis),
+		Category:          category.NewCategoryRepository(postgres, redis),
+		Transaction:       transaction.NewTransactionRepository(postgres, redis),
+		BudgetHistory:     budget_history.NewBudgetHistoryRepository(postgres, redis),
+		Recurring:         recurring.NewRecurringRepository(postgres, redis),
+		CategoryLimit:     category_limit.NewCategoryLimitRepository(postgres, redis),
+		Report:            report.NewReportRepository(postgres, redis),
+		RefreshToken:      refresh_token.NewRefreshTokenRepository(postgres),
+		Session:           session.NewSessionRepository(postgres, redis),
+		PasswordReset:     password_reset.NewPasswordResetRepository(postgres, redis),
+		EmailVerification: email_verification.NewEmailVerificationRepository(postgres, redis),
+		TwoFactor:         two_factor.NewTwoFactorRepository(postgres, redis),
+		LoginAttempt:      login_attempt.NewLoginAttemptRepository(redis),
+		DataExport:        data_export.NewDataExportRepository(postgres, redis),
+		APIKey:            api_key.NewAPIKeyRepository(postgres, redis),
+		BudgetInvitation:  budget_invitation.NewBudgetInvitationRepository(postgres, redis),
+		TransactionRule:   transaction_rule.NewTransactionRuleRepository(postgres, redis),
 	}
 }
@@ -0,0 +1,181 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/session/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/session/repository.go -destination=internal/repository/session/mock/mock_session.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "finly-backend/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	sqlx "github.com/jmoiron/sqlx"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSession is a mock of Session interface.
+type MockSession struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionMockRecorder
+	isgomock struct{}
+}
+
+// MockSessionMockRecorder is the mock recorder for MockSession.
+type MockSessionMockRecorder struct {
+	mock *MockSession
+}
+
+// NewMockSession creates a new mock instance.
+func NewMockSession(ctrl *gomock.Controller) *MockSession {
+	mock := &MockSession{ctrl: ctrl}
+	mock.recorder = &MockSessionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSession) EXPECT() *MockSessionMockRecorder {
+	return m.recorder
+}
+
+// CreateTX mocks base method.
+func (m *MockSession) CreateTX(ctx context.Context, tx *sqlx.Tx, session *domain.Session) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTX", ctx, tx, session)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTX indicates an expected call of CreateTX.
+func (mr *MockSessionMockRecorder) CreateTX(ctx, tx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTX", reflect.TypeOf((*MockSession)(nil).CreateTX), ctx, tx, session)
+}
+
+// GetByID mocks base method.
+func (m *MockSession) GetByID(ctx context.Context, sessionID string) (*domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, sessionID)
+	ret0, _ := ret[0].(*domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockSessionMockRecorder) GetByID(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSession)(nil).GetByID), ctx, sessionID)
+}
+
+// GetDB mocks base method.
+func (m *MockSession) GetDB() *sqlx.DB {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDB")
+	ret0, _ := ret[0].(*sqlx.DB)
+	return ret0
+}
+
+// GetDB indicates an expected call of GetDB.
+func (mr *MockSessionMockRecorder) GetDB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDB", reflect.TypeOf((*MockSession)(nil).GetDB))
+}
+
+// InvalidateCache mocks base method.
+func (m *MockSession) InvalidateCache(ctx context.Context, sessionIDs ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range sessionIDs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InvalidateCache", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateCache indicates an expected call of InvalidateCache.
+func (mr *MockSessionMockRecorder) InvalidateCache(ctx any, sessionIDs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, sessionIDs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateCache", reflect.TypeOf((*MockSession)(nil).InvalidateCache), varargs...)
+}
+
+// ListActiveByUserID mocks base method.
+func (m *MockSession) ListActiveByUserID(ctx context.Context, userID string, seenAfter time.Time) ([]*domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveByUserID", ctx, userID, seenAfter)
+	ret0, _ := ret[0].([]*domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveByUserID indicates an expected call of ListActiveByUserID.
+func (mr *MockSessionMockRecorder) ListActiveByUserID(ctx, userID, seenAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveByUserID", reflect.TypeOf((*MockSession)(nil).ListActiveByUserID), ctx, userID, seenAfter)
+}
+
+// RevokeAllTX mocks base method.
+func (m *MockSession) RevokeAllTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllTX", ctx, tx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAllTX indicates an expected call of RevokeAllTX.
+func (mr *MockSessionMockRecorder) RevokeAllTX(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllTX", reflect.TypeOf((*MockSession)(nil).RevokeAllTX), ctx, tx, userID)
+}
+
+// RevokeOthersTX mocks base method.
+func (m *MockSession) RevokeOthersTX(ctx context.Context, tx *sqlx.Tx, userID, keepSessionID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOthersTX", ctx, tx, userID, keepSessionID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOthersTX indicates an expected call of RevokeOthersTX.
+func (mr *MockSessionMockRecorder) RevokeOthersTX(ctx, tx, userID, keepSessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOthersTX", reflect.TypeOf((*MockSession)(nil).RevokeOthersTX), ctx, tx, userID, keepSessionID)
+}
+
+// RevokeTX mocks base method.
+func (m *MockSession) RevokeTX(ctx context.Context, tx *sqlx.Tx, sessionID, userID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeTX", ctx, tx, sessionID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeTX indicates an expected call of RevokeTX.
+func (mr *MockSessionMockRecorder) RevokeTX(ctx, tx, sessionID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeTX", reflect.TypeOf((*MockSession)(nil).RevokeTX), ctx, tx, sessionID, userID)
+}
+
+// Touch mocks base method.
+func (m *MockSession) Touch(ctx context.Context, sessionID, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, sessionID, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockSessionMockRecorder) Touch(ctx, sessionID, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockSession)(nil).Touch), ctx, sessionID, ip)
+}
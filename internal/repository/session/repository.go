@@ -0,0 +1,190 @@
+package session
+
+import (
+	"context"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/db"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"time"
+)
+
+type Session interface {
+	GetDB() *sqlx.DB
+	InvalidateCache(ctx context.Context, sessionIDs ...string) error
+	CreateTX(ctx context.Context, tx *sqlx.Tx, session *domain.Session) (string, error)
+	GetByID(ctx context.Context, sessionID string) (*domain.Session, error)
+	ListActiveByUserID(ctx context.Context, userID string, seenAfter time.Time) ([]*domain.Session, error)
+	RevokeTX(ctx context.Context, tx *sqlx.Tx, sessionID, userID string) (bool, error)
+	RevokeOthersTX(ctx context.Context, tx *sqlx.Tx, userID, keepSessionID string) ([]string, error)
+	RevokeAllTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error)
+	Touch(ctx context.Context, sessionID, ip string) error
+}
+
+const (
+	SessionTable = "sessions"
+
+	TTL_GetSessionCache = 5 * time.Minute
+
+	// touchInterval limits how often last_seen_at is written for a busy session.
+	touchInterval = time.Minute
+
+	cacheKeySessionByID = "session:%s"
+	cacheKeySessionSeen = "session:seen:%s"
+)
+
+type SessionRepository struct {
+	postgres *sqlx.DB
+	redis    *redis.Client
+}
+
+func NewSessionRepository(postgres *sqlx.DB, redis *redis.Client) *SessionRepository {
+	return &SessionRepository{
+		postgres: postgres,
+		redis:    redis,
+	}
+}
+
+func (r *SessionRepository) GetDB() *sqlx.DB {
+	return r.postgres
+}
+
+func (r *SessionRepository) InvalidateCache(ctx context.Context, sessionIDs ...string) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	zap.L().Sugar().Infof("Invalidating session cache for sessionIDs: %v", sessionIDs)
+
+	keys := make([]string, 0, len(sessionIDs))
+	for _, id := range sessionIDs {
+		keys = append(keys, fmt.Sprintf(cacheKeySessionByID, id))
+	}
+	if err := r.redis.Del(ctx, keys...).Err(); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate session cache for sessionIDs: %v, error: %v", sessionIDs, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) CreateTX(ctx context.Context, tx *sqlx.Tx, session *domain.Session) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, user_agent, ip) VALUES ($1, $2, $3) RETURNING id", SessionTable)
+
+	var id string
+	if err := tx.QueryRowxContext(ctx, query, session.UserID, session.UserAgent, session.IP).Scan(&id); err != nil {
+		zap.L().Sugar().Errorf("Failed to create session, userID: %s, error: %v", session.UserID, err)
+		return "", err
+	}
+
+	zap.L().Sugar().Infof("Session created, sessionID: %s, userID: %s", id, session.UserID)
+	return id, nil
+}
+
+// GetByID is read on every authenticated request, so it is cached; whoever revokes a
+// session invalidates the entry.
+func (r *SessionRepository) GetByID(ctx context.Context, sessionID string) (*domain.Session, error) {
+	if sessionID == "" {
+		return nil, fmt.Errorf("sessionID cannot be empty")
+	}
+
+	cacheKey := fmt.Sprintf(cacheKeySessionByID, sessionID)
+
+	fetch := func() (*domain.Session, error) {
+		var session domain.Session
+		query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1", SessionTable)
+		if err := r.postgres.GetContext(ctx, &session, query, sessionID); err != nil {
+			zap.L().Sugar().Errorf("Failed to fetch session from DB, sessionID: %s, error: %v", sessionID, err)
+			return nil, err
+		}
+		return &session, nil
+	}
+
+	return db.WithCache(ctx, r.redis, cacheKey, TTL_GetSessionCache, fetch)
+}
+
+// ListActiveByUserID returns the sessions that are not revoked and were used after
+// seenAfter, most recently used first.
+func (r *SessionRepository) ListActiveByUserID(ctx context.Context, userID string, seenAfter time.Time) ([]*domain.Session, error) {
+	var sessions []*domain.Session
+	query := fmt.Sprintf(`SELECT * FROM %s
+		WHERE user_id = $1 AND revoked_at IS NULL AND last_seen_at > $2
+		ORDER BY last_seen_at DESC, id`, SessionTable)
+	if err := r.postgres.SelectContext(ctx, &sessions, query, userID, seenAfter); err != nil {
+		zap.L().Sugar().Errorf("Failed to list sessions, userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RevokeTX revokes a session of the user. It reports false when there was no such
+// active session. The cached session is left for the caller to invalidate once tx is
+// committed, so a read in between can't cache it again as active.
+func (r *SessionRepository) RevokeTX(ctx context.Context, tx *sqlx.Tx, sessionID, userID string) (bool, error) {
+	query := fmt.Sprintf(`UPDATE %s SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`, SessionTable)
+	res, err := tx.ExecContext(ctx, query, sessionID, userID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to revoke session, sessionID: %s, userID: %s, error: %v", sessionID, userID, err)
+		return false, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	zap.L().Sugar().Infof("Session revoked, sessionID: %s, userID: %s", sessionID, userID)
+	return rows > 0, nil
+}
+
+// RevokeOthersTX revokes every active session of the user except keepSessionID and
+// returns the IDs of the revoked sessions to invalidate once tx is committed.
+func (r *SessionRepository) RevokeOthersTX(ctx context.Context, tx *sqlx.Tx, userID, keepSessionID string) ([]string, error) {
+	query := fmt.Sprintf(`UPDATE %s SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL
+		RETURNING id`, SessionTable)
+	return r.revokeTX(ctx, tx, userID, query, userID, keepSessionID)
+}
+
+// RevokeAllTX revokes every active session of the user and returns their IDs to
+// invalidate once tx is committed.
+func (r *SessionRepository) RevokeAllTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	query := fmt.Sprintf(`UPDATE %s SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND revoked_at IS NULL
+		RETURNING id`, SessionTable)
+	return r.revokeTX(ctx, tx, userID, query, userID)
+}
+
+func (r *SessionRepository) revokeTX(ctx context.Context, tx *sqlx.Tx, userID, query string, args ...any) ([]string, error) {
+	var ids []string
+	if err := tx.SelectContext(ctx, &ids, query, args...); err != nil {
+		zap.L().Sugar().Errorf("Failed to revoke sessions, userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Revoked %d sessions of userID: %s", len(ids), userID)
+	return ids, nil
+}
+
+// Touch records that the session was just used from ip. Writes are throttled to one
+// per touchInterval, so last_seen_at is only that precise.
+func (r *SessionRepository) Touch(ctx context.Context, sessionID, ip string) error {
+	first, err := r.redis.SetNX(ctx, fmt.Sprintf(cacheKeySessionSeen, sessionID), ip, touchInterval).Result()
+	if err != nil {
+		zap.L().Sugar().Warnf("Failed to throttle session touch, sessionID: %s, error: %v", sessionID, err)
+		return err
+	}
+	if !first {
+		return nil
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET last_seen_at = CURRENT_TIMESTAMP, ip = $2 WHERE id = $1", SessionTable)
+	if _, err = r.postgres.ExecContext(ctx, query, sessionID, ip); err != nil {
+		zap.L().Sugar().Errorf("Failed to touch session, sessionID: %s, error: %v", sessionID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package session
+
+import (
+	"context"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/testutil"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestSessionRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "user_agent", "ip", "created_at", "last_seen_at", "revoked_at"}
+
+	t.Run("CreateTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewSessionRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", SessionTable)).
+				WithArgs("123", "curl/8.0", "10.0.0.1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("session1"))
+
+			tx, _ := sqlxDB.Beginx()
+			id, err := repo.CreateTX(ctx, tx, &domain.Session{UserID: "123", UserAgent: "curl/8.0", IP: "10.0.0.1"})
+			assert.NoError(t, err)
+			assert.Equal(t, "session1", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewSessionRepository(sqlxDB, redisClient)
+
+		t.Run("CacheMiss", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE id = \\$1", SessionTable)).
+				WithArgs("session1").
+				WillReturnRows(sqlmock.NewRows(columns).AddRow("session1", "123", "curl/8.0", "10.0.0.1", now, now, nil))
+
+			session, err := repo.GetByID(ctx, "session1")
+			assert.NoError(t, err)
+			assert.Equal(t, "123", session.UserID)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(cacheKeySessionByID, "session1")).Result()
+			assert.Equal(t, int64(1), exists)
+		})
+
+		t.Run("CacheHit", func(t *testing.T) {
+			session, err := repo.GetByID(ctx, "session1")
+			assert.NoError(t, err)
+			assert.Equal(t, "curl/8.0", session.UserAgent)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("EmptyID", func(t *testing.T) {
+			_, err := repo.GetByID(ctx, "")
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("ListActiveByUserID", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewSessionRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			seenAfter := now.Add(-time.Hour)
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s\\s+WHERE user_id = \\$1 AND revoked_at IS NULL", SessionTable)).
+				WithArgs("123", seenAfter).
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("session1", "123", "curl/8.0", "10.0.0.1", now, now, nil).
+					AddRow("session2", "123", "Firefox", "10.0.0.2", now, now.Add(-time.Minute), nil))
+
+			sessions, err := repo.ListActiveByUserID(ctx, "123", seenAfter)
+			assert.NoError(t, err)
+			assert.Len(t, sessions, 2)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("RevokeTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewSessionRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			redisClient.Set(ctx, fmt.Sprintf(cacheKeySessionByID, "session1"), "data", 0)
+
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET revoked_at", SessionTable)).
+				WithArgs("session1", "123").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			tx, _ := sqlxDB.Beginx()
+			revoked, err := repo.RevokeTX(ctx, tx, "session1", "123")
+			assert.NoError(t, err)
+			assert.True(t, revoked)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			// The cache is only invalidated once the caller committed.
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(cacheKeySessionByID, "session1")).Result()
+			assert.Equal(t, int64(1), exists)
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET revoked_at", SessionTable)).
+				WithArgs("session9", "123").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			tx, _ := sqlxDB.Beginx()
+			revoked, err := repo.RevokeTX(ctx, tx, "session9", "123")
+			assert.NoError(t, err)
+			assert.False(t, revoked)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("RevokeOthersTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewSessionRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			redisClient.Set(ctx, fmt.Sprintf(cacheKeySessionByID, "session2"), "data", 0)
+			redisClient.Set(ctx, fmt.Sprintf(cacheKeySessionByID, "session1"), "data", 0)
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s SET revoked_at (.+) RETURNING id", SessionTable)).
+				WithArgs("123", "session1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("session2").AddRow("session3"))
+
+			tx, _ := sqlxDB.Beginx()
+			ids, err := repo.RevokeOthersTX(ctx, tx, "123", "session1")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"session2", "session3"}, ids)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(cacheKeySessionByID, "session2")).Result()
+			assert.Equal(t, int64(1), exists)
+		})
+	})
+
+	t.Run("RevokeAllTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewSessionRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			redisClient.Set(ctx, fmt.Sprintf(cacheKeySessionByID, "session1"), "data", 0)
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s SET revoked_at (.+) WHERE user_id = \\$1 AND revoked_at IS NULL RETURNING id", SessionTable)).
+				WithArgs("123").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("session1").AddRow("session2"))
+
+			tx, _ := sqlxDB.Beginx()
+			ids, err := repo.RevokeAllTX(ctx, tx, "123")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"session1", "session2"}, ids)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("InvalidateCache", func(t *testing.T) {
+		sqlxDB, _, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewSessionRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			redisClient.Set(ctx, fmt.Sprintf(cacheKeySessionByID, "session1"), "data", 0)
+			redisClient.Set(ctx, fmt.Sprintf(cacheKeySessionByID, "session2"), "data", 0)
+
+			err := repo.InvalidateCache(ctx, "session1")
+			assert.NoError(t, err)
+
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(cacheKeySessionByID, "session1")).Result()
+			assert.Equal(t, int64(0), exists)
+			exists, _ = redisClient.Exists(ctx, fmt.Sprintf(cacheKeySessionByID, "session2")).Result()
+			assert.Equal(t, int64(1), exists)
+		})
+
+		t.Run("NoSessions", func(t *testing.T) {
+			assert.NoError(t, repo.InvalidateCache(ctx))
+		})
+	})
+
+	t.Run("Touch", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewSessionRepository(sqlxDB, redisClient)
+
+		t.Run("Throttled", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET last_seen_at", SessionTable)).
+				WithArgs("session1", "10.0.0.1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			assert.NoError(t, repo.Touch(ctx, "session1", "10.0.0.1"))
+			assert.NoError(t, repo.Touch(ctx, "session1", "10.0.0.1"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			mr.FastForward(touchInterval)
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET last_seen_at", SessionTable)).
+				WithArgs("session1", "10.0.0.2").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			assert.NoError(t, repo.Touch(ctx, "session1", "10.0.0.2"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+}
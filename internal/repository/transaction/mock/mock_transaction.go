@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: internal/repository/transaction/transaction.go
+// Source: internal/repository/transaction/repository.go
 //
 // Generated by this command:
 //
-//	mockgen -source=internal/repository/transaction/transaction.go -destination=internal/repository/transaction/mock/mock_transaction.go -package=mock
+//	mockgen -source=internal/repository/transaction/repository.go -destination=internal/repository/transaction/mock/mock_transaction.go -package=mock
 //
 
 // Package mock is a generated GoMock package.
@@ -12,6 +12,7 @@ package mock
 import (
 	context "context"
 	domain "finly-backend/internal/domain"
+	transaction "finly-backend/internal/repository/transaction"
 	reflect "reflect"
 
 	sqlx "github.com/jmoiron/sqlx"
@@ -42,19 +43,77 @@ func (m *MockTransaction) EXPECT() *MockTransactionMockRecorder {
 	return m.recorder
 }
 
+// BumpVersion mocks base method.
+func (m *MockTransaction) BumpVersion(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BumpVersion", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BumpVersion indicates an expected call of BumpVersion.
+func (mr *MockTransactionMockRecorder) BumpVersion(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BumpVersion", reflect.TypeOf((*MockTransaction)(nil).BumpVersion), ctx, userID)
+}
+
+// CacheKeysByUserTX mocks base method.
+func (m *MockTransaction) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheKeysByUserTX", ctx, tx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CacheKeysByUserTX indicates an expected call of CacheKeysByUserTX.
+func (mr *MockTransactionMockRecorder) CacheKeysByUserTX(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheKeysByUserTX", reflect.TypeOf((*MockTransaction)(nil).CacheKeysByUserTX), ctx, tx, userID)
+}
+
+// CategorizeTX mocks base method.
+func (m *MockTransaction) CategorizeTX(ctx context.Context, tx *sqlx.Tx, transactionID, userID, categoryID string, tags []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CategorizeTX", ctx, tx, transactionID, userID, categoryID, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CategorizeTX indicates an expected call of CategorizeTX.
+func (mr *MockTransactionMockRecorder) CategorizeTX(ctx, tx, transactionID, userID, categoryID, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CategorizeTX", reflect.TypeOf((*MockTransaction)(nil).CategorizeTX), ctx, tx, transactionID, userID, categoryID, tags)
+}
+
 // CreateTX mocks base method.
-func (m *MockTransaction) CreateTX(ctx context.Context, tx *sqlx.Tx, userID, budgetID, categoryID, transactionType, note string, amount float64) (string, error) {
+func (m *MockTransaction) CreateTX(ctx context.Context, tx *sqlx.Tx, userID, createdBy, budgetID, categoryID, transactionType, note string, amount domain.Money) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTX", ctx, tx, userID, budgetID, categoryID, transactionType, note, amount)
+	ret := m.ctrl.Call(m, "CreateTX", ctx, tx, userID, createdBy, budgetID, categoryID, transactionType, note, amount)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateTX indicates an expected call of CreateTX.
-func (mr *MockTransactionMockRecorder) CreateTX(ctx, tx, userID, budgetID, categoryID, transactionType, note, amount any) *gomock.Call {
+func (mr *MockTransactionMockRecorder) CreateTX(ctx, tx, userID, createdBy, budgetID, categoryID, transactionType, note, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTX", reflect.TypeOf((*MockTransaction)(nil).CreateTX), ctx, tx, userID, createdBy, budgetID, categoryID, transactionType, note, amount)
+}
+
+// CreateTransferLegTX mocks base method.
+func (m *MockTransaction) CreateTransferLegTX(ctx context.Context, tx *sqlx.Tx, transferID, userID, createdBy, budgetID, categoryID, transactionType, note string, amount domain.Money) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferLegTX", ctx, tx, transferID, userID, createdBy, budgetID, categoryID, transactionType, note, amount)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferLegTX indicates an expected call of CreateTransferLegTX.
+func (mr *MockTransactionMockRecorder) CreateTransferLegTX(ctx, tx, transferID, userID, createdBy, budgetID, categoryID, transactionType, note, amount any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTX", reflect.TypeOf((*MockTransaction)(nil).CreateTX), ctx, tx, userID, budgetID, categoryID, transactionType, note, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferLegTX", reflect.TypeOf((*MockTransaction)(nil).CreateTransferLegTX), ctx, tx, transferID, userID, createdBy, budgetID, categoryID, transactionType, note, amount)
 }
 
 // DeleteTX mocks base method.
@@ -71,19 +130,48 @@ func (mr *MockTransactionMockRecorder) DeleteTX(ctx, tx, transactionID, userID a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTX", reflect.TypeOf((*MockTransaction)(nil).DeleteTX), ctx, tx, transactionID, userID)
 }
 
+// Export mocks base method.
+func (m *MockTransaction) Export(ctx context.Context, filter transaction.ListFilter, fn func(*domain.TransactionExport) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockTransactionMockRecorder) Export(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockTransaction)(nil).Export), ctx, filter, fn)
+}
+
 // GetByID mocks base method.
-func (m *MockTransaction) GetByID(ctx context.Context, transactionID, userID string) (*domain.Transaction, error) {
+func (m *MockTransaction) GetByID(ctx context.Context, transactionID string) (*domain.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", ctx, transactionID, userID)
+	ret := m.ctrl.Call(m, "GetByID", ctx, transactionID)
 	ret0, _ := ret[0].(*domain.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockTransactionMockRecorder) GetByID(ctx, transactionID, userID any) *gomock.Call {
+func (mr *MockTransactionMockRecorder) GetByID(ctx, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTransaction)(nil).GetByID), ctx, transactionID)
+}
+
+// GetByIDTX mocks base method.
+func (m *MockTransaction) GetByIDTX(ctx context.Context, tx *sqlx.Tx, transactionID string) (*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDTX", ctx, tx, transactionID)
+	ret0, _ := ret[0].(*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDTX indicates an expected call of GetByIDTX.
+func (mr *MockTransactionMockRecorder) GetByIDTX(ctx, tx, transactionID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTransaction)(nil).GetByID), ctx, transactionID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDTX", reflect.TypeOf((*MockTransaction)(nil).GetByIDTX), ctx, tx, transactionID)
 }
 
 // GetDB mocks base method.
@@ -100,23 +188,98 @@ func (mr *MockTransactionMockRecorder) GetDB() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDB", reflect.TypeOf((*MockTransaction)(nil).GetDB))
 }
 
+// ImportTX mocks base method.
+func (m *MockTransaction) ImportTX(ctx context.Context, tx *sqlx.Tx, arg2 *domain.Transaction) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTX", ctx, tx, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportTX indicates an expected call of ImportTX.
+func (mr *MockTransactionMockRecorder) ImportTX(ctx, tx, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTX", reflect.TypeOf((*MockTransaction)(nil).ImportTX), ctx, tx, arg2)
+}
+
+// InvalidateCache mocks base method.
+func (m *MockTransaction) InvalidateCache(ctx context.Context, userID, transactionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateCache", ctx, userID, transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateCache indicates an expected call of InvalidateCache.
+func (mr *MockTransactionMockRecorder) InvalidateCache(ctx, userID, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateCache", reflect.TypeOf((*MockTransaction)(nil).InvalidateCache), ctx, userID, transactionID)
+}
+
 // List mocks base method.
-func (m *MockTransaction) List(ctx context.Context, userID string) ([]*domain.Transaction, error) {
+func (m *MockTransaction) List(ctx context.Context, filter transaction.ListFilter) ([]*domain.Transaction, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]*domain.Transaction)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransactionMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransaction)(nil).List), ctx, filter)
+}
+
+// ListByTransferIDTX mocks base method.
+func (m *MockTransaction) ListByTransferIDTX(ctx context.Context, tx *sqlx.Tx, transferID string) ([]*domain.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret := m.ctrl.Call(m, "ListByTransferIDTX", ctx, tx, transferID)
 	ret0, _ := ret[0].([]*domain.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// List indicates an expected call of List.
-func (mr *MockTransactionMockRecorder) List(ctx, userID any) *gomock.Call {
+// ListByTransferIDTX indicates an expected call of ListByTransferIDTX.
+func (mr *MockTransactionMockRecorder) ListByTransferIDTX(ctx, tx, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTransferIDTX", reflect.TypeOf((*MockTransaction)(nil).ListByTransferIDTX), ctx, tx, transferID)
+}
+
+// ListExternalIDsTX mocks base method.
+func (m *MockTransaction) ListExternalIDsTX(ctx context.Context, tx *sqlx.Tx, budgetID string, externalIDs []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExternalIDsTX", ctx, tx, budgetID, externalIDs)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExternalIDsTX indicates an expected call of ListExternalIDsTX.
+func (mr *MockTransactionMockRecorder) ListExternalIDsTX(ctx, tx, budgetID, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalIDsTX", reflect.TypeOf((*MockTransaction)(nil).ListExternalIDsTX), ctx, tx, budgetID, externalIDs)
+}
+
+// ReassignCategoryTX mocks base method.
+func (m *MockTransaction) ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignCategoryTX", ctx, tx, fromCategoryID, toCategoryID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReassignCategoryTX indicates an expected call of ReassignCategoryTX.
+func (mr *MockTransactionMockRecorder) ReassignCategoryTX(ctx, tx, fromCategoryID, toCategoryID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransaction)(nil).List), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignCategoryTX", reflect.TypeOf((*MockTransaction)(nil).ReassignCategoryTX), ctx, tx, fromCategoryID, toCategoryID)
 }
 
 // UpdateTX mocks base method.
-func (m *MockTransaction) UpdateTX(ctx context.Context, tx *sqlx.Tx, transactionID, userID, categoryID, transactionType, note string, amount float64) error {
+func (m *MockTransaction) UpdateTX(ctx context.Context, tx *sqlx.Tx, transactionID, userID, categoryID, transactionType, note string, amount domain.Money) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateTX", ctx, tx, transactionID, userID, categoryID, transactionType, note, amount)
 	ret0, _ := ret[0].(error)
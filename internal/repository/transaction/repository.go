@@ -2,34 +2,92 @@ package transaction
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/budget_history"
+	"finly-backend/internal/repository/category"
 	"finly-backend/pkg/db"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"strings"
 	"time"
 )
 
 type Transaction interface {
-	CreateTX(ctx context.Context, tx *sqlx.Tx, userID, budgetID, categoryID, transactionType, note string, amount float64) (string, error)
+	CreateTX(ctx context.Context, tx *sqlx.Tx, userID, createdBy, budgetID, categoryID, transactionType, note string, amount domain.Money) (string, error)
+	ImportTX(ctx context.Context, tx *sqlx.Tx, transaction *domain.Transaction) (string, error)
+	ListExternalIDsTX(ctx context.Context, tx *sqlx.Tx, budgetID string, externalIDs []string) ([]string, error)
+	CreateTransferLegTX(ctx context.Context, tx *sqlx.Tx, transferID, userID, createdBy, budgetID, categoryID, transactionType, note string, amount domain.Money) (string, error)
+	ListByTransferIDTX(ctx context.Context, tx *sqlx.Tx, transferID string) ([]*domain.Transaction, error)
 	GetDB() *sqlx.DB
-	List(ctx context.Context, userID string) ([]*domain.Transaction, error)
-	UpdateTX(ctx context.Context, tx *sqlx.Tx, transactionID, userID string, categoryID, transactionType, note string, amount float64) error
+	List(ctx context.Context, filter ListFilter) ([]*domain.Transaction, int, error)
+	Export(ctx context.Context, filter ListFilter, fn func(*domain.TransactionExport) error) error
+	UpdateTX(ctx context.Context, tx *sqlx.Tx, transactionID, userID string, categoryID, transactionType, note string, amount domain.Money) error
+	CategorizeTX(ctx context.Context, tx *sqlx.Tx, transactionID, userID, categoryID string, tags []string) error
 	DeleteTX(ctx context.Context, tx *sqlx.Tx, transactionID, userID string) error
-	GetByID(ctx context.Context, transactionID, userID string) (*domain.Transaction, error)
+	GetByID(ctx context.Context, transactionID string) (*domain.Transaction, error)
+	GetByIDTX(ctx context.Context, tx *sqlx.Tx, transactionID string) (*domain.Transaction, error)
+	InvalidateCache(ctx context.Context, userID, transactionID string) error
+	BumpVersion(ctx context.Context, userID string) error
+	ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error)
+	CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error)
 }
 
 const (
 	TransactionTable = "transactions"
 
-	TTL_ListTransactionsCache   = 5 * time.Minute
 	TTL_GetByIDTransactionCache = 15 * time.Minute
 
-	cacheKeyTransactionByIDAndUser = "transaction:%s:user:%s"
-	cacheKeyTransactionsByUser     = "transactions:user:%s"
+	cacheKeyTransactionByID = "transaction:%s"
+
+	// CacheKeyVersion holds a counter that is bumped on every write to a user's transactions.
+	// Caches of data derived from many transactions, such as reports, put it into their keys
+	// so a write makes them stale without having to find and delete every entry.
+	CacheKeyVersion = "transaction:version:user:%s"
+
+	SortByCreatedAt = "created_at"
+	SortByAmount    = "amount"
+
+	uniqueViolation = "23505"
 )
 
+// ErrDuplicateExternalID is returned when the budget already has a transaction with the
+// external ID.
+var ErrDuplicateExternalID = errors.New("transaction with this external ID already exists")
+
+// ListFilter narrows down and pages a user's transactions.
+// Zero-valued fields are ignored; only UserID is mandatory.
+// UserID matches the transactions of the budgets the user owns. BudgetIDs, when set, is
+// matched instead, so transactions of budgets shared with the user can be read too.
+type ListFilter struct {
+	UserID     string
+	BudgetIDs  []string
+	BudgetID   string
+	CategoryID string
+	Type       string
+	From       *time.Time
+	To         *time.Time
+	MinAmount  *domain.Money
+	MaxAmount  *domain.Money
+	Note       string
+
+	SortBy   string
+	SortDesc bool
+	After    *ListCursor
+	Limit    int
+}
+
+// ListCursor is the sort key of the last row of the previous page.
+type ListCursor struct {
+	CreatedAt time.Time
+	Amount    domain.Money
+	ID        string
+}
+
 type TransactionRepository struct {
 	postgres *sqlx.DB
 	redis    *redis.Client
@@ -42,34 +100,46 @@ func NewTransactionRepository(postgres *sqlx.DB, redis *redis.Client) *Transacti
 	}
 }
 
-func (t *TransactionRepository) cacheKeys(userID, transactionID string) []string {
+func (t *TransactionRepository) cacheKeys(transactionID string) []string {
 	return []string{
-		fmt.Sprintf(cacheKeyTransactionByIDAndUser, transactionID, userID),
-		fmt.Sprintf(cacheKeyTransactionsByUser, userID),
+		fmt.Sprintf(cacheKeyTransactionByID, transactionID),
 	}
 }
 
 func (t *TransactionRepository) InvalidateCache(ctx context.Context, userID, transactionID string) error {
 	zap.L().Sugar().Infof("Invalidating transaction cache for userID: %s, transactionID: %s", userID, transactionID)
 
-	keys := t.cacheKeys(userID, transactionID)
+	keys := t.cacheKeys(transactionID)
 	if err := t.redis.Del(ctx, keys...).Err(); err != nil {
 		zap.L().Sugar().Warnf("Failed to invalidate transaction cache for userID: %s, transactionID: %s, error: %v", userID, transactionID, err)
 		return err
 	}
+	if err := t.BumpVersion(ctx, userID); err != nil {
+		return err
+	}
 
 	zap.L().Sugar().Infof("Transaction cache invalidated for userID: %s, transactionID: %s", userID, transactionID)
 	return nil
 }
 
+// BumpVersion makes everything cached under the transaction version of the user stale.
+func (t *TransactionRepository) BumpVersion(ctx context.Context, userID string) error {
+	if err := t.redis.Incr(ctx, fmt.Sprintf(CacheKeyVersion, userID)).Err(); err != nil {
+		zap.L().Sugar().Warnf("Failed to bump transaction version for userID: %s, error: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
 func (t *TransactionRepository) GetDB() *sqlx.DB {
 	return t.postgres
 }
 
-func (t *TransactionRepository) CreateTX(ctx context.Context, tx *sqlx.Tx, userID string, budgetID string, categoryID string, transactionType string, note string, amount float64) (string, error) {
-	query := fmt.Sprintf("INSERT INTO %s (user_id, budget_id, category_id, amount, transaction_type, note) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id", TransactionTable)
+// CreateTX creates a transaction in a budget owned by userID on behalf of the member createdBy.
+func (t *TransactionRepository) CreateTX(ctx context.Context, tx *sqlx.Tx, userID string, createdBy string, budgetID string, categoryID string, transactionType string, note string, amount domain.Money) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, created_by, budget_id, category_id, amount, transaction_type, note) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id", TransactionTable)
 	var transactionID string
-	if err := tx.QueryRowContext(ctx, query, userID, budgetID, categoryID, amount, transactionType, note).Scan(&transactionID); err != nil {
+	if err := tx.QueryRowContext(ctx, query, userID, createdBy, budgetID, categoryID, amount, transactionType, note).Scan(&transactionID); err != nil {
 		zap.L().Sugar().Errorf("Error creating transaction, userID: %s, error: %v", userID, err)
 		return "", err
 	}
@@ -82,59 +152,298 @@ func (t *TransactionRepository) CreateTX(ctx context.Context, tx *sqlx.Tx, userI
 	return transactionID, nil
 }
 
-func (t *TransactionRepository) List(ctx context.Context, userID string) ([]*domain.Transaction, error) {
-	cacheKey := fmt.Sprintf(cacheKeyTransactionsByUser, userID)
-	fetch := func() ([]*domain.Transaction, error) {
-		var transactions []*domain.Transaction
-		query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1 ORDER BY created_at DESC", TransactionTable)
-		if err := t.postgres.SelectContext(ctx, &transactions, query, userID); err != nil {
-			zap.L().Sugar().Errorf("Failed to fetch transactions from DB for userID: %s, error: %v", userID, err)
-			return nil, err
+// ImportTX inserts a transaction that keeps its original booking time instead of the current one.
+func (t *TransactionRepository) ImportTX(ctx context.Context, tx *sqlx.Tx, transaction *domain.Transaction) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, created_by, budget_id, category_id, amount, transaction_type, note, external_id, tags, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, '{}'::text[]), $10) RETURNING id", TransactionTable)
+	var transactionID string
+	if err := tx.QueryRowContext(ctx, query,
+		transaction.UserID, transaction.CreatedBy, transaction.BudgetID, transaction.CategoryID, transaction.Amount, transaction.TransactionType, transaction.Note, transaction.ExternalID, transaction.Tags, transaction.CreatedAt,
+	).Scan(&transactionID); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return "", ErrDuplicateExternalID
+		}
+		zap.L().Sugar().Errorf("Error importing transaction, userID: %s, error: %v", transaction.UserID, err)
+		return "", err
+	}
+
+	if err := t.InvalidateCache(ctx, transaction.UserID, transactionID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after import, userID: %s, transactionID: %s, error: %v", transaction.UserID, transactionID, err)
+	}
+
+	return transactionID, nil
+}
+
+// ListExternalIDsTX returns which of the given external IDs were already imported into the budget.
+func (t *TransactionRepository) ListExternalIDsTX(ctx context.Context, tx *sqlx.Tx, budgetID string, externalIDs []string) ([]string, error) {
+	var existing []string
+	query := fmt.Sprintf("SELECT external_id FROM %s WHERE budget_id = $1 AND external_id = ANY($2)", TransactionTable)
+	if err := tx.SelectContext(ctx, &existing, query, budgetID, pq.Array(externalIDs)); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch external IDs, budgetID: %s, error: %v", budgetID, err)
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (t *TransactionRepository) CreateTransferLegTX(ctx context.Context, tx *sqlx.Tx, transferID, userID, createdBy, budgetID, categoryID, transactionType, note string, amount domain.Money) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, created_by, budget_id, category_id, amount, transaction_type, note, transfer_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id", TransactionTable)
+	var transactionID string
+	if err := tx.QueryRowContext(ctx, query, userID, createdBy, budgetID, categoryID, amount, transactionType, note, transferID).Scan(&transactionID); err != nil {
+		zap.L().Sugar().Errorf("Error creating transfer leg, transferID: %s, userID: %s, error: %v", transferID, userID, err)
+		return "", err
+	}
+
+	if err := t.InvalidateCache(ctx, userID, transactionID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after transfer create, userID: %s, transactionID: %s, error: %v", userID, transactionID, err)
+	}
+
+	zap.L().Sugar().Infof("Transfer leg created successfully, transferID: %s, transactionID: %s", transferID, transactionID)
+	return transactionID, nil
+}
+
+// ListByTransferIDTX locks and returns both legs of a transfer, outgoing leg first. Callers
+// check that the user may change the budgets of the legs.
+func (t *TransactionRepository) ListByTransferIDTX(ctx context.Context, tx *sqlx.Tx, transferID string) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+	query := fmt.Sprintf("SELECT * FROM %s WHERE transfer_id = $1 ORDER BY transaction_type DESC FOR UPDATE", TransactionTable)
+	if err := tx.SelectContext(ctx, &transactions, query, transferID); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch transfer legs, transferID: %s, error: %v", transferID, err)
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// List returns one page of transactions matching the filter together with the total
+// number of matching rows. Pages are keyset-based, so results are read straight from
+// the database instead of the cache.
+func (t *TransactionRepository) List(ctx context.Context, filter ListFilter) ([]*domain.Transaction, int, error) {
+	if filter.UserID == "" {
+		return nil, 0, fmt.Errorf("userID cannot be empty")
+	}
+
+	conditions, args := filter.conditions()
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", TransactionTable, strings.Join(conditions, " AND "))
+	if err := t.postgres.GetContext(ctx, &total, countQuery, args...); err != nil {
+		zap.L().Sugar().Errorf("Failed to count transactions for userID: %s, error: %v", filter.UserID, err)
+		return nil, 0, err
+	}
+
+	sortColumn, direction, comparison := filter.order()
+
+	if filter.After != nil {
+		var key any = filter.After.CreatedAt
+		if sortColumn == SortByAmount {
+			key = filter.After.Amount
+		}
+		args = append(args, key, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d, $%d)", sortColumn, comparison, len(args)-1, len(args)))
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s ORDER BY %s %s, id %s",
+		TransactionTable, strings.Join(conditions, " AND "), sortColumn, direction, direction)
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	var transactions []*domain.Transaction
+	if err := t.postgres.SelectContext(ctx, &transactions, query, args...); err != nil {
+		zap.L().Sugar().Errorf("Failed to fetch transactions from DB for userID: %s, error: %v", filter.UserID, err)
+		return nil, 0, err
+	}
+
+	return transactions, total, nil
+}
+
+// Export streams every transaction matching the filter to fn in the filter's sort order,
+// with its category name as the user sees it and the budget balance recorded right after it. Rows are read
+// from an open cursor and handed over one by one, so the result set is never held in
+// memory. Limit and After are ignored.
+func (t *TransactionRepository) Export(ctx context.Context, filter ListFilter, fn func(*domain.TransactionExport) error) error {
+	if filter.UserID == "" {
+		return fmt.Errorf("userID cannot be empty")
+	}
+
+	conditions, args := filter.conditions()
+	sortColumn, direction, _ := filter.order()
+
+	args = append(args, filter.UserID)
+	query := fmt.Sprintf(`SELECT t.*, COALESCE(o.name, c.name, '') AS category_name, h.balance
+		FROM (SELECT * FROM %s WHERE %s) t
+		LEFT JOIN %s c ON c.id = t.category_id
+		LEFT JOIN %s o ON o.category_id = t.category_id AND o.user_id = $%d
+		LEFT JOIN %s h ON h.transaction_id = t.id
+		ORDER BY t.%s %s, t.id %s`,
+		TransactionTable, strings.Join(conditions, " AND "), category.CategoryTable,
+		category.CategoryOverrideTable, len(args), budget_history.BudgetHistoryTable,
+		sortColumn, direction, direction)
+
+	rows, err := t.postgres.QueryxContext(ctx, query, args...)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to export transactions for userID: %s, error: %v", filter.UserID, err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row domain.TransactionExport
+		if err = rows.StructScan(&row); err != nil {
+			zap.L().Sugar().Errorf("Failed to scan exported transaction for userID: %s, error: %v", filter.UserID, err)
+			return err
+		}
+		if err = fn(&row); err != nil {
+			return err
 		}
-		return transactions, nil
 	}
 
-	return db.WithCache(ctx, t.redis, cacheKey, TTL_ListTransactionsCache, fetch)
+	return rows.Err()
+}
+
+// order returns the sort column, its direction and the keyset comparison operator.
+func (f ListFilter) order() (string, string, string) {
+	sortColumn := SortByCreatedAt
+	if f.SortBy == SortByAmount {
+		sortColumn = SortByAmount
+	}
+	if f.SortDesc {
+		return sortColumn, "DESC", "<"
+	}
+	return sortColumn, "ASC", ">"
+}
+
+func (f ListFilter) conditions() ([]string, []any) {
+	conditions := []string{"user_id = $1"}
+	args := []any{f.UserID}
+	if len(f.BudgetIDs) > 0 {
+		conditions = []string{"budget_id = ANY($1)"}
+		args = []any{pq.Array(f.BudgetIDs)}
+	}
+
+	add := func(expr string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(expr, len(args)))
+	}
+
+	if f.BudgetID != "" {
+		add("budget_id = $%d", f.BudgetID)
+	}
+	if f.CategoryID != "" {
+		add("category_id = $%d", f.CategoryID)
+	}
+	if f.Type != "" {
+		add("transaction_type = $%d", f.Type)
+	}
+	if f.From != nil {
+		add("created_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("created_at <= $%d", *f.To)
+	}
+	if f.MinAmount != nil {
+		add("amount >= $%d", *f.MinAmount)
+	}
+	if f.MaxAmount != nil {
+		add("amount <= $%d", *f.MaxAmount)
+	}
+	if f.Note != "" {
+		add(`note ILIKE $%d ESCAPE '\'`, "%"+escapeLike(f.Note)+"%")
+	}
+
+	return conditions, args
 }
 
-func (t *TransactionRepository) UpdateTX(ctx context.Context, tx *sqlx.Tx, transactionID, userID string, categoryID, transactionType, note string, amount float64) error {
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// UpdateTX changes a transaction of userID. It returns sql.ErrNoRows when there is no such
+// transaction. The cache is left for the caller to invalidate once tx is committed, so a
+// read in between can't cache the old row again.
+func (t *TransactionRepository) UpdateTX(ctx context.Context, tx *sqlx.Tx, transactionID, userID string, categoryID, transactionType, note string, amount domain.Money) error {
 	query := fmt.Sprintf("UPDATE %s SET category_id = $1, transaction_type = $2, note = $3, amount = $4 WHERE id = $5 AND user_id = $6", TransactionTable)
-	if _, err := tx.ExecContext(ctx, query, categoryID, transactionType, note, amount, transactionID, userID); err != nil {
+	res, err := tx.ExecContext(ctx, query, categoryID, transactionType, note, amount, transactionID, userID)
+	if err != nil {
 		zap.L().Sugar().Errorf("Error updating transaction, transactionID: %s, userID: %s, error: %v", transactionID, userID, err)
 		return err
 	}
 
-	if err := t.InvalidateCache(ctx, userID, transactionID); err != nil {
-		zap.L().Sugar().Warnf("Failed to invalidate cache after update, userID: %s, transactionID: %s, error: %v", userID, transactionID, err)
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
 	}
 
 	zap.L().Sugar().Infof("Transaction updated successfully, transactionID: %s, userID: %s", transactionID, userID)
 	return nil
 }
 
+// CategorizeTX sets the category and the tags of a transaction, as transaction rules do.
+// Like UpdateTX, it leaves the cache to the caller.
+func (t *TransactionRepository) CategorizeTX(ctx context.Context, tx *sqlx.Tx, transactionID, userID, categoryID string, tags []string) error {
+	query := fmt.Sprintf("UPDATE %s SET category_id = $1, tags = $2 WHERE id = $3 AND user_id = $4", TransactionTable)
+	if _, err := tx.ExecContext(ctx, query, categoryID, pq.Array(tags), transactionID, userID); err != nil {
+		zap.L().Sugar().Errorf("Error categorizing transaction, transactionID: %s, userID: %s, error: %v", transactionID, userID, err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteTX deletes a transaction of userID. Like UpdateTX, it returns sql.ErrNoRows when
+// there is no such transaction and leaves the cache to the caller.
 func (t *TransactionRepository) DeleteTX(ctx context.Context, tx *sqlx.Tx, transactionID, userID string) error {
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", TransactionTable)
-	_, err := tx.ExecContext(ctx, query, transactionID, userID)
+	res, err := tx.ExecContext(ctx, query, transactionID, userID)
 	if err != nil {
 		zap.L().Sugar().Errorf("Error deleting transaction, transactionID: %s, userID: %s, error: %v", transactionID, userID, err)
 		return err
 	}
 
-	if err := t.InvalidateCache(ctx, userID, transactionID); err != nil {
-		zap.L().Sugar().Warnf("Failed to invalidate cache after delete, userID: %s, transactionID: %s, error: %v", userID, transactionID, err)
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
 	}
 
 	zap.L().Sugar().Infof("Transaction deleted successfully, transactionID: %s, userID: %s", transactionID, userID)
 	return nil
 }
 
-func (t *TransactionRepository) GetByID(ctx context.Context, transactionID, userID string) (*domain.Transaction, error) {
-	cacheKey := fmt.Sprintf(cacheKeyTransactionByIDAndUser, transactionID, userID)
+// ReassignCategoryTX moves every transaction of fromCategoryID to toCategoryID. It returns the
+// cache keys of the moved transactions, to be deleted once tx is committed; the version of
+// the owner of the category is bumped by the caller then too.
+func (t *TransactionRepository) ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error) {
+	var moved []string
+	query := fmt.Sprintf("UPDATE %s SET category_id = $1 WHERE category_id = $2 RETURNING id", TransactionTable)
+	if err := tx.SelectContext(ctx, &moved, query, toCategoryID, fromCategoryID); err != nil {
+		zap.L().Sugar().Errorf("Error reassigning transactions, fromCategoryID: %s, toCategoryID: %s, error: %v", fromCategoryID, toCategoryID, err)
+		return nil, err
+	}
+
+	var keys []string
+	for _, id := range moved {
+		keys = append(keys, t.cacheKeys(id)...)
+	}
+
+	zap.L().Sugar().Infof("Reassigned %d transactions from categoryID: %s to categoryID: %s", len(moved), fromCategoryID, toCategoryID)
+	return keys, nil
+}
+
+// GetByID loads a transaction of any budget. Callers check that the user is a member of
+// its budget, so the cache holds one copy however many members read it.
+func (t *TransactionRepository) GetByID(ctx context.Context, transactionID string) (*domain.Transaction, error) {
+	cacheKey := fmt.Sprintf(cacheKeyTransactionByID, transactionID)
 	fetch := func() (*domain.Transaction, error) {
 		var transaction domain.Transaction
-		query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1 AND user_id = $2", TransactionTable)
-		if err := t.postgres.GetContext(ctx, &transaction, query, transactionID, userID); err != nil {
-			zap.L().Sugar().Errorf("Failed to fetch transaction from DB, transactionID: %s, userID: %s, error: %v", transactionID, userID, err)
+		query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1", TransactionTable)
+		if err := t.postgres.GetContext(ctx, &transaction, query, transactionID); err != nil {
+			zap.L().Sugar().Errorf("Failed to fetch transaction from DB, transactionID: %s, error: %v", transactionID, err)
 			return nil, err
 		}
 		return &transaction, nil
@@ -142,3 +451,35 @@ func (t *TransactionRepository) GetByID(ctx context.Context, transactionID, user
 
 	return db.WithCache(ctx, t.redis, cacheKey, TTL_GetByIDTransactionCache, fetch)
 }
+
+// GetByIDTX locks and returns a transaction, read past the cache, so its amount and type
+// can't change until tx ends. It returns sql.ErrNoRows when the transaction is gone.
+func (t *TransactionRepository) GetByIDTX(ctx context.Context, tx *sqlx.Tx, transactionID string) (*domain.Transaction, error) {
+	var transaction domain.Transaction
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1 FOR UPDATE", TransactionTable)
+	if err := tx.GetContext(ctx, &transaction, query, transactionID); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			zap.L().Sugar().Errorf("Failed to lock transaction, transactionID: %s, error: %v", transactionID, err)
+		}
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// CacheKeysByUserTX lists the cache keys of the user's transactions and the transaction
+// version. Reports cached under that version are left to expire, as nobody can read them
+// once the user is gone.
+func (t *TransactionRepository) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	var ids []string
+	query := fmt.Sprintf("SELECT id FROM %s WHERE user_id = $1", TransactionTable)
+	if err := tx.SelectContext(ctx, &ids, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to list transaction IDs, userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+
+	keys := []string{fmt.Sprintf(CacheKeyVersion, userID)}
+	for _, id := range ids {
+		keys = append(keys, t.cacheKeys(id)...)
+	}
+	return keys, nil
+}
@@ -2,15 +2,19 @@ package transaction
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"finly-backend/internal/domain"
 	"finly-backend/pkg/testutil"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"testing"
+	"time"
 )
 
 func TestTransactionRepository(t *testing.T) {
@@ -25,13 +29,11 @@ func TestTransactionRepository(t *testing.T) {
 		repo := NewTransactionRepository(sqlxDB, redisClient)
 
 		t.Run("Success", func(t *testing.T) {
-			userID := "123"
 			transactionID := "456"
 			expected := []string{
-				fmt.Sprintf(cacheKeyTransactionByIDAndUser, transactionID, userID),
-				fmt.Sprintf(cacheKeyTransactionsByUser, userID),
+				fmt.Sprintf(cacheKeyTransactionByID, transactionID),
 			}
-			keys := repo.cacheKeys(userID, transactionID)
+			keys := repo.cacheKeys(transactionID)
 			assert.Equal(t, expected, keys)
 		})
 	})
@@ -47,7 +49,7 @@ func TestTransactionRepository(t *testing.T) {
 		t.Run("Success", func(t *testing.T) {
 			userID := "123"
 			transactionID := "456"
-			cacheKey := fmt.Sprintf(cacheKeyTransactionByIDAndUser, transactionID, userID)
+			cacheKey := fmt.Sprintf(cacheKeyTransactionByID, transactionID)
 			redisClient.Set(ctx, cacheKey, "data", 0)
 
 			err := repo.InvalidateCache(ctx, userID, transactionID)
@@ -55,6 +57,9 @@ func TestTransactionRepository(t *testing.T) {
 
 			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
 			assert.Equal(t, int64(0), exists)
+
+			version, _ := redisClient.Get(ctx, fmt.Sprintf(CacheKeyVersion, userID)).Int64()
+			assert.Equal(t, int64(1), version)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 
@@ -97,20 +102,20 @@ func TestTransactionRepository(t *testing.T) {
 			categoryID := "101"
 			transactionType := "expense"
 			note := "Test transaction"
-			amount := 50.0
+			amount := domain.MustParseMoney("50.00")
 			transactionID := "456"
-			cacheKey := fmt.Sprintf(cacheKeyTransactionsByUser, userID)
+			cacheKey := fmt.Sprintf(cacheKeyTransactionByID, transactionID)
 
 			mock.ExpectBegin()
-			query := fmt.Sprintf("INSERT INTO %s \\(user_id, budget_id, category_id, amount, transaction_type, note\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6\\) RETURNING id", TransactionTable)
+			query := fmt.Sprintf("INSERT INTO %s \\(user_id, created_by, budget_id, category_id, amount, transaction_type, note\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7\\) RETURNING id", TransactionTable)
 			mock.ExpectQuery(query).
-				WithArgs(userID, budgetID, categoryID, amount, transactionType, note).
+				WithArgs(userID, userID, budgetID, categoryID, amount, transactionType, note).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(transactionID))
 
 			tx, err := sqlxDB.Beginx()
 			assert.NoError(t, err)
 
-			id, err := repo.CreateTX(ctx, tx, userID, budgetID, categoryID, transactionType, note, amount)
+			id, err := repo.CreateTX(ctx, tx, userID, userID, budgetID, categoryID, transactionType, note, amount)
 			assert.NoError(t, err)
 			assert.Equal(t, transactionID, id)
 
@@ -125,19 +130,19 @@ func TestTransactionRepository(t *testing.T) {
 			categoryID := "101"
 			transactionType := "expense"
 			note := "Test transaction"
-			amount := 50.0
+			amount := domain.MustParseMoney("50.00")
 
 			mock.ExpectBegin()
-			query := fmt.Sprintf("INSERT INTO %s \\(user_id, budget_id, category_id, amount, transaction_type, note\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6\\) RETURNING id", TransactionTable)
+			query := fmt.Sprintf("INSERT INTO %s \\(user_id, created_by, budget_id, category_id, amount, transaction_type, note\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7\\) RETURNING id", TransactionTable)
 			mock.ExpectQuery(query).
-				WithArgs(userID, budgetID, categoryID, amount, transactionType, note).
+				WithArgs(userID, userID, budgetID, categoryID, amount, transactionType, note).
 				WillReturnError(errors.New("db error"))
 			mock.ExpectRollback()
 
 			tx, err := sqlxDB.Beginx()
 			assert.NoError(t, err)
 
-			id, err := repo.CreateTX(ctx, tx, userID, budgetID, categoryID, transactionType, note, amount)
+			id, err := repo.CreateTX(ctx, tx, userID, userID, budgetID, categoryID, transactionType, note, amount)
 			assert.Error(t, err)
 			assert.Empty(t, id)
 
@@ -147,7 +152,7 @@ func TestTransactionRepository(t *testing.T) {
 		})
 	})
 
-	t.Run("List", func(t *testing.T) {
+	t.Run("CreateTransferLegTX", func(t *testing.T) {
 		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
 		defer sqlxDB.Close()
 		defer mr.Close()
@@ -155,72 +160,389 @@ func TestTransactionRepository(t *testing.T) {
 		zap.ReplaceGlobals(logger)
 		repo := NewTransactionRepository(sqlxDB, redisClient)
 
-		t.Run("CacheHit", func(t *testing.T) {
-			userID := "123"
-			cacheKey := fmt.Sprintf(cacheKeyTransactionsByUser, userID)
-			transactions := []*domain.Transaction{
-				{
-					ID:              "456",
-					UserID:          userID,
-					BudgetID:        "789",
-					CategoryID:      "101",
-					Amount:          50.0,
-					TransactionType: "expense",
-					Note:            "Test transaction",
-				},
+		t.Run("Success", func(t *testing.T) {
+			amount := domain.MustParseMoney("40.00")
+
+			mock.ExpectBegin()
+			query := fmt.Sprintf("INSERT INTO %s \\(user_id, created_by, budget_id, category_id, amount, transaction_type, note, transfer_id\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$8\\) RETURNING id", TransactionTable)
+			mock.ExpectQuery(query).
+				WithArgs("123", "321", "789", "101", amount, "transfer_out", "note", "transfer1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("456"))
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			id, err := repo.CreateTransferLegTX(ctx, tx, "transfer1", "123", "321", "789", "101", "transfer_out", "note", amount)
+			assert.NoError(t, err)
+			assert.Equal(t, "456", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ImportTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			imported := &domain.Transaction{
+				UserID: "123", CreatedBy: sql.NullString{String: "321", Valid: true}, BudgetID: "789", CategoryID: "101",
+				Amount: domain.MustParseMoney("30.00"), TransactionType: "withdrawal", Note: "Groceries",
+				ExternalID: sql.NullString{String: "fitid:A1", Valid: true},
+				Tags:       []string{"groceries"},
+				CreatedAt:  time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
 			}
 
-			data, err := json.Marshal(transactions)
+			mock.ExpectBegin()
+			query := fmt.Sprintf("INSERT INTO %s \\(user_id, created_by, budget_id, category_id, amount, transaction_type, note, external_id, tags, created_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$8, COALESCE\\(\\$9, '{}'::text\\[\\]\\), \\$10\\) RETURNING id", TransactionTable)
+			mock.ExpectQuery(query).
+				WithArgs("123", imported.CreatedBy, "789", "101", imported.Amount, "withdrawal", "Groceries", imported.ExternalID, imported.Tags, imported.CreatedAt).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("456"))
+
+			tx, err := sqlxDB.Beginx()
 			assert.NoError(t, err)
 
-			err = redisClient.Set(ctx, cacheKey, data, TTL_ListTransactionsCache).Err()
+			id, err := repo.ImportTX(ctx, tx, imported)
 			assert.NoError(t, err)
+			assert.Equal(t, "456", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DuplicateExternalID", func(t *testing.T) {
+			imported := &domain.Transaction{
+				UserID: "123", BudgetID: "789", CategoryID: "101",
+				Amount: domain.MustParseMoney("30.00"), TransactionType: "withdrawal",
+				ExternalID: sql.NullString{String: "fitid:A1", Valid: true},
+				CreatedAt:  time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+			}
 
-			result, err := repo.List(ctx, userID)
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", TransactionTable)).
+				WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_transactions_budget_external_id"})
+
+			tx, err := sqlxDB.Beginx()
 			assert.NoError(t, err)
-			assert.Equal(t, transactions, result)
+
+			_, err = repo.ImportTX(ctx, tx, imported)
+			assert.ErrorIs(t, err, ErrDuplicateExternalID)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
+	})
 
-		t.Run("CacheMiss", func(t *testing.T) {
-			userID := "12345"
-			cacheKey := fmt.Sprintf(cacheKeyTransactionsByUser, userID)
+	t.Run("ListExternalIDsTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			ids := []string{"fitid:A1", "fitid:A2"}
+
+			mock.ExpectBegin()
+			query := fmt.Sprintf("SELECT external_id FROM %s WHERE budget_id = \\$1 AND external_id = ANY\\(\\$2\\)", TransactionTable)
+			mock.ExpectQuery(query).
+				WithArgs("789", pq.Array(ids)).
+				WillReturnRows(sqlmock.NewRows([]string{"external_id"}).AddRow("fitid:A1"))
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			existing, err := repo.ListExternalIDsTX(ctx, tx, "789", ids)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"fitid:A1"}, existing)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ListByTransferIDTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			query := fmt.Sprintf("SELECT \\* FROM %s WHERE transfer_id = \\$1 ORDER BY transaction_type DESC FOR UPDATE", TransactionTable)
+			mock.ExpectQuery(query).
+				WithArgs("transfer1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_type", "transfer_id"}).
+					AddRow("out1", "transfer_out", "transfer1").
+					AddRow("in1", "transfer_in", "transfer1"))
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			legs, err := repo.ListByTransferIDTX(ctx, tx, "transfer1")
+			assert.NoError(t, err)
+			assert.Len(t, legs, 2)
+			assert.Equal(t, "out1", legs[0].ID)
+			assert.Equal(t, "transfer1", legs[1].TransferID.String)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("GetByIDTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRepository(sqlxDB, redisClient)
+		query := fmt.Sprintf("SELECT \\* FROM %s WHERE id = \\$1 FOR UPDATE", TransactionTable)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(query).
+				WithArgs("456").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "amount"}).AddRow("456", "75.00"))
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			transaction, err := repo.GetByIDTX(ctx, tx, "456")
+			assert.NoError(t, err)
+			assert.Equal(t, domain.MustParseMoney("75.00"), transaction.Amount)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(query).
+				WithArgs("456").
+				WillReturnError(sql.ErrNoRows)
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			_, err = repo.GetByIDTX(ctx, tx, "456")
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("List", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRepository(sqlxDB, redisClient)
+
+		columns := []string{"id", "user_id", "budget_id", "category_id", "amount", "transaction_type", "note"}
+
+		t.Run("UserOnly", func(t *testing.T) {
+			userID := "123"
 			transactions := []*domain.Transaction{
 				{
 					ID:              "456",
 					UserID:          userID,
 					BudgetID:        "789",
 					CategoryID:      "101",
-					Amount:          50.0,
-					TransactionType: "expense",
+					Amount:          domain.MustParseMoney("50.00"),
+					TransactionType: "withdrawal",
 					Note:            "Test transaction",
 				},
 			}
 
-			query := fmt.Sprintf("SELECT \\* FROM %s WHERE user_id = \\$1 ORDER BY created_at DESC", TransactionTable)
-			mock.ExpectQuery(query).
+			mock.ExpectQuery(fmt.Sprintf("SELECT COUNT\\(\\*\\) FROM %s WHERE user_id = \\$1$", TransactionTable)).
 				WithArgs(userID).
-				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "budget_id", "category_id", "amount", "transaction_type", "note"}).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE user_id = \\$1 ORDER BY created_at ASC, id ASC$", TransactionTable)).
+				WithArgs(userID).
+				WillReturnRows(sqlmock.NewRows(columns).
 					AddRow(transactions[0].ID, transactions[0].UserID, transactions[0].BudgetID, transactions[0].CategoryID, transactions[0].Amount, transactions[0].TransactionType, transactions[0].Note))
 
-			result, err := repo.List(ctx, userID)
+			result, total, err := repo.List(ctx, ListFilter{UserID: userID})
 			assert.NoError(t, err)
+			assert.Equal(t, 1, total)
 			assert.Equal(t, transactions, result)
 			assert.NoError(t, mock.ExpectationsWereMet())
+		})
 
-			cached, err := redisClient.Get(ctx, cacheKey).Result()
+		t.Run("SharedBudgets", func(t *testing.T) {
+			budgetIDs := []string{"789", "790"}
+
+			mock.ExpectQuery(fmt.Sprintf("SELECT COUNT\\(\\*\\) FROM %s WHERE budget_id = ANY\\(\\$1\\)$", TransactionTable)).
+				WithArgs(pq.Array(budgetIDs)).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE budget_id = ANY\\(\\$1\\) ORDER BY created_at ASC, id ASC$", TransactionTable)).
+				WithArgs(pq.Array(budgetIDs)).
+				WillReturnRows(sqlmock.NewRows(columns))
+
+			result, total, err := repo.List(ctx, ListFilter{UserID: "123", BudgetIDs: budgetIDs})
 			assert.NoError(t, err)
-			assert.NotEmpty(t, cached)
+			assert.Zero(t, total)
+			assert.Empty(t, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("FiltersAndCursor", func(t *testing.T) {
+			userID := "123"
+			from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			minAmount := domain.MustParseMoney("10.00")
+			filter := ListFilter{
+				UserID:    userID,
+				BudgetID:  "789",
+				Type:      "withdrawal",
+				From:      &from,
+				MinAmount: &minAmount,
+				Note:      "50%_off",
+				SortBy:    SortByAmount,
+				SortDesc:  true,
+				After:     &ListCursor{Amount: domain.MustParseMoney("20.00"), ID: "999"},
+				Limit:     11,
+			}
+
+			where := "WHERE user_id = \\$1 AND budget_id = \\$2 AND transaction_type = \\$3 AND created_at >= \\$4 AND amount >= \\$5 AND note ILIKE \\$6 ESCAPE '\\\\'"
+			filterArgs := []driver.Value{userID, "789", "withdrawal", from, minAmount, `%50\%\_off%`}
+
+			mock.ExpectQuery(fmt.Sprintf("SELECT COUNT\\(\\*\\) FROM %s %s$", TransactionTable, where)).
+				WithArgs(filterArgs...).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s %s AND \\(amount, id\\) < \\(\\$7, \\$8\\) ORDER BY amount DESC, id DESC LIMIT \\$9$", TransactionTable, where)).
+				WithArgs(append(filterArgs, domain.MustParseMoney("20.00"), "999", 11)...).
+				WillReturnRows(sqlmock.NewRows(columns))
+
+			result, total, err := repo.List(ctx, filter)
+			assert.NoError(t, err)
+			assert.Equal(t, 42, total)
+			assert.Empty(t, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("CountError", func(t *testing.T) {
+			mock.ExpectQuery("SELECT COUNT").
+				WithArgs("123").
+				WillReturnError(errors.New("db error"))
+
+			result, total, err := repo.List(ctx, ListFilter{UserID: "123"})
+			assert.Error(t, err)
+			assert.Zero(t, total)
+			assert.Nil(t, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 
 		t.Run("EmptyUserID", func(t *testing.T) {
-			result, err := repo.List(ctx, "")
+			result, _, err := repo.List(ctx, ListFilter{})
 			assert.Error(t, err)
 			assert.Nil(t, result)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	})
 
+	t.Run("Export", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRepository(sqlxDB, redisClient)
+
+		columns := []string{"id", "user_id", "budget_id", "category_id", "amount", "transaction_type", "note", "category_name", "balance"}
+		query := fmt.Sprintf("SELECT t\\.\\*, COALESCE\\(o\\.name, c\\.name, ''\\) AS category_name, h\\.balance\\s+"+
+			"FROM \\(SELECT \\* FROM %s WHERE user_id = \\$1 AND budget_id = \\$2\\) t\\s+"+
+			"LEFT JOIN categories c ON c\\.id = t\\.category_id\\s+"+
+			"LEFT JOIN category_overrides o ON o\\.category_id = t\\.category_id AND o\\.user_id = \\$3\\s+"+
+			"LEFT JOIN budgets_history h ON h\\.transaction_id = t\\.id\\s+"+
+			"ORDER BY t\\.created_at DESC, t\\.id DESC$", TransactionTable)
+		filter := ListFilter{UserID: "123", BudgetID: "789", SortDesc: true, Limit: 10}
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectQuery(query).
+				WithArgs("123", "789", "123").
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("456", "123", "789", "101", "50.00", "withdrawal", "Lunch", "Food & Drink", "150.00").
+					AddRow("457", "123", "789", "101", "20.00", "deposit", "", "", nil))
+
+			var rows []*domain.TransactionExport
+			err := repo.Export(ctx, filter, func(row *domain.TransactionExport) error {
+				rows = append(rows, row)
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.Len(t, rows, 2)
+			assert.Equal(t, "Food & Drink", rows[0].CategoryName)
+			assert.Equal(t, domain.MustParseMoney("150.00"), *rows[0].Balance)
+			assert.Equal(t, domain.MustParseMoney("50.00"), rows[0].Amount)
+			assert.Nil(t, rows[1].Balance)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("CallbackError", func(t *testing.T) {
+			mock.ExpectQuery(query).
+				WithArgs("123", "789", "123").
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("456", "123", "789", "101", "50.00", "withdrawal", "Lunch", "Food & Drink", "150.00").
+					AddRow("457", "123", "789", "101", "20.00", "deposit", "", "", nil))
+
+			stop := errors.New("client gone")
+			calls := 0
+			err := repo.Export(ctx, filter, func(*domain.TransactionExport) error {
+				calls++
+				return stop
+			})
+			assert.ErrorIs(t, err, stop)
+			assert.Equal(t, 1, calls)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("EmptyUserID", func(t *testing.T) {
+			err := repo.Export(ctx, ListFilter{}, func(*domain.TransactionExport) error { return nil })
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("CategorizeTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			cacheKey := fmt.Sprintf(cacheKeyTransactionByID, "456")
+			redisClient.Set(ctx, cacheKey, "data", 0)
+
+			mock.ExpectBegin()
+			query := fmt.Sprintf("UPDATE %s SET category_id = \\$1, tags = \\$2 WHERE id = \\$3 AND user_id = \\$4", TransactionTable)
+			mock.ExpectExec(query).
+				WithArgs("101", pq.Array([]string{"rides"}), "456", "123").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			err = repo.CategorizeTX(ctx, tx, "456", "123", "101", []string{"rides"})
+			assert.NoError(t, err)
+
+			// The cached row is dropped by the caller once tx is committed.
+			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
+			assert.Equal(t, int64(1), exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET category_id", TransactionTable)).
+				WillReturnError(errors.New("db error"))
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			err = repo.CategorizeTX(ctx, tx, "456", "123", "101", nil)
+			assert.Error(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
 	t.Run("UpdateTX", func(t *testing.T) {
 		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
 		defer sqlxDB.Close()
@@ -235,8 +557,8 @@ func TestTransactionRepository(t *testing.T) {
 			categoryID := "101"
 			transactionType := "expense"
 			note := "Updated transaction"
-			amount := 75.0
-			cacheKey := fmt.Sprintf(cacheKeyTransactionByIDAndUser, transactionID, userID)
+			amount := domain.MustParseMoney("75.00")
+			cacheKey := fmt.Sprintf(cacheKeyTransactionByID, transactionID)
 
 			mock.ExpectBegin()
 			query := fmt.Sprintf("UPDATE %s SET category_id = \\$1, transaction_type = \\$2, note = \\$3, amount = \\$4 WHERE id = \\$5 AND user_id = \\$6", TransactionTable)
@@ -247,11 +569,30 @@ func TestTransactionRepository(t *testing.T) {
 			tx, err := sqlxDB.Beginx()
 			assert.NoError(t, err)
 
+			err = redisClient.Set(ctx, cacheKey, "cached", 0).Err()
+			assert.NoError(t, err)
+
 			err = repo.UpdateTX(ctx, tx, transactionID, userID, categoryID, transactionType, note, amount)
 			assert.NoError(t, err)
 
+			// The cached row is dropped by the caller once tx is committed.
 			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
-			assert.Equal(t, int64(0), exists)
+			assert.Equal(t, int64(1), exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			mock.ExpectBegin()
+			query := fmt.Sprintf("UPDATE %s SET category_id = \\$1, transaction_type = \\$2, note = \\$3, amount = \\$4 WHERE id = \\$5 AND user_id = \\$6", TransactionTable)
+			mock.ExpectExec(query).
+				WithArgs("101", "expense", "", domain.MustParseMoney("75.00"), "456", "123").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			err = repo.UpdateTX(ctx, tx, "456", "123", "101", "expense", "", domain.MustParseMoney("75.00"))
+			assert.ErrorIs(t, err, sql.ErrNoRows)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 
@@ -261,7 +602,7 @@ func TestTransactionRepository(t *testing.T) {
 			categoryID := "101"
 			transactionType := "expense"
 			note := "Updated transaction"
-			amount := 75.0
+			amount := domain.MustParseMoney("75.00")
 
 			mock.ExpectBegin()
 			query := fmt.Sprintf("UPDATE %s SET category_id = \\$1, transaction_type = \\$2, note = \\$3, amount = \\$4 WHERE id = \\$5 AND user_id = \\$6", TransactionTable)
@@ -293,7 +634,7 @@ func TestTransactionRepository(t *testing.T) {
 		t.Run("Success", func(t *testing.T) {
 			transactionID := "456"
 			userID := "123"
-			cacheKey := fmt.Sprintf(cacheKeyTransactionByIDAndUser, transactionID, userID)
+			cacheKey := fmt.Sprintf(cacheKeyTransactionByID, transactionID)
 
 			mock.ExpectBegin()
 			query := fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND user_id = \\$2", TransactionTable)
@@ -304,11 +645,29 @@ func TestTransactionRepository(t *testing.T) {
 			tx, err := sqlxDB.Beginx()
 			assert.NoError(t, err)
 
+			err = redisClient.Set(ctx, cacheKey, "cached", 0).Err()
+			assert.NoError(t, err)
+
 			err = repo.DeleteTX(ctx, tx, transactionID, userID)
 			assert.NoError(t, err)
 
 			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
-			assert.Equal(t, int64(0), exists)
+			assert.Equal(t, int64(1), exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("AlreadyDeleted", func(t *testing.T) {
+			mock.ExpectBegin()
+			query := fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND user_id = \\$2", TransactionTable)
+			mock.ExpectExec(query).
+				WithArgs("456", "123").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			tx, err := sqlxDB.Beginx()
+			assert.NoError(t, err)
+
+			err = repo.DeleteTX(ctx, tx, "456", "123")
+			assert.ErrorIs(t, err, sql.ErrNoRows)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 
@@ -346,13 +705,13 @@ func TestTransactionRepository(t *testing.T) {
 		t.Run("CacheHit", func(t *testing.T) {
 			userID := "123"
 			transactionID := "456"
-			cacheKey := fmt.Sprintf(cacheKeyTransactionByIDAndUser, transactionID, userID)
+			cacheKey := fmt.Sprintf(cacheKeyTransactionByID, transactionID)
 			transaction := &domain.Transaction{
 				ID:              transactionID,
 				UserID:          userID,
 				BudgetID:        "789",
 				CategoryID:      "101",
-				Amount:          50.0,
+				Amount:          domain.MustParseMoney("50.00"),
 				TransactionType: "expense",
 				Note:            "Test transaction",
 			}
@@ -363,7 +722,7 @@ func TestTransactionRepository(t *testing.T) {
 			err = redisClient.Set(ctx, cacheKey, data, TTL_GetByIDTransactionCache).Err()
 			assert.NoError(t, err)
 
-			result, err := repo.GetByID(ctx, transactionID, userID)
+			result, err := repo.GetByID(ctx, transactionID)
 			assert.NoError(t, err)
 			assert.Equal(t, transaction, result)
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -372,24 +731,24 @@ func TestTransactionRepository(t *testing.T) {
 		t.Run("CacheMiss", func(t *testing.T) {
 			userID := "12345"
 			transactionID := "4567"
-			cacheKey := fmt.Sprintf(cacheKeyTransactionByIDAndUser, transactionID, userID)
+			cacheKey := fmt.Sprintf(cacheKeyTransactionByID, transactionID)
 			transaction := &domain.Transaction{
 				ID:              transactionID,
 				UserID:          userID,
 				BudgetID:        "789",
 				CategoryID:      "101",
-				Amount:          50.0,
+				Amount:          domain.MustParseMoney("50.00"),
 				TransactionType: "expense",
 				Note:            "Test transaction",
 			}
 
-			query := fmt.Sprintf("SELECT \\* FROM %s WHERE id = \\$1 AND user_id = \\$2", TransactionTable)
+			query := fmt.Sprintf("SELECT \\* FROM %s WHERE id = \\$1", TransactionTable)
 			mock.ExpectQuery(query).
-				WithArgs(transactionID, userID).
+				WithArgs(transactionID).
 				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "budget_id", "category_id", "amount", "transaction_type", "note"}).
 					AddRow(transaction.ID, transaction.UserID, transaction.BudgetID, transaction.CategoryID, transaction.Amount, transaction.TransactionType, transaction.Note))
 
-			result, err := repo.GetByID(ctx, transactionID, userID)
+			result, err := repo.GetByID(ctx, transactionID)
 			assert.NoError(t, err)
 			assert.Equal(t, transaction, result)
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -400,13 +759,59 @@ func TestTransactionRepository(t *testing.T) {
 		})
 
 		t.Run("EmptyInputs", func(t *testing.T) {
-			result, err := repo.GetByID(ctx, "", "123")
+			result, err := repo.GetByID(ctx, "")
 			assert.Error(t, err)
 			assert.Nil(t, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+	t.Run("ReassignCategoryTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
 
-			result, err = repo.GetByID(ctx, "456", "")
-			assert.Error(t, err)
-			assert.Nil(t, result)
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s SET category_id = \\$1 WHERE category_id = \\$2 RETURNING id", TransactionTable)).
+				WithArgs("category2", "category1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("transaction1").AddRow("transaction2").AddRow("transaction3"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.ReassignCategoryTX(ctx, tx, "category1", "category2")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{
+				fmt.Sprintf(cacheKeyTransactionByID, "transaction1"),
+				fmt.Sprintf(cacheKeyTransactionByID, "transaction2"),
+				fmt.Sprintf(cacheKeyTransactionByID, "transaction3"),
+			}, keys)
+			// The version is bumped by the caller once tx is committed.
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(CacheKeyVersion, "user1")).Result()
+			assert.Equal(t, int64(0), exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("CacheKeysByUserTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT id FROM %s WHERE user_id", TransactionTable)).
+				WithArgs("user1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("transaction1"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.CacheKeysByUserTX(ctx, tx, "user1")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{fmt.Sprintf(CacheKeyVersion, "user1"), fmt.Sprintf(cacheKeyTransactionByID, "transaction1")}, keys)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	})
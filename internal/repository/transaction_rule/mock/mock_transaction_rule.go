@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/transaction_rule/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/transaction_rule/repository.go -destination=internal/repository/transaction_rule/mock/mock_transaction_rule.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "finly-backend/internal/domain"
+	reflect "reflect"
+
+	sqlx "github.com/jmoiron/sqlx"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransactionRule is a mock of TransactionRule interface.
+type MockTransactionRule struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionRuleMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionRuleMockRecorder is the mock recorder for MockTransactionRule.
+type MockTransactionRuleMockRecorder struct {
+	mock *MockTransactionRule
+}
+
+// NewMockTransactionRule creates a new mock instance.
+func NewMockTransactionRule(ctrl *gomock.Controller) *MockTransactionRule {
+	mock := &MockTransactionRule{ctrl: ctrl}
+	mock.recorder = &MockTransactionRuleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionRule) EXPECT() *MockTransactionRuleMockRecorder {
+	return m.recorder
+}
+
+// CacheKeysByUserTX mocks base method.
+func (m *MockTransactionRule) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheKeysByUserTX", ctx, tx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CacheKeysByUserTX indicates an expected call of CacheKeysByUserTX.
+func (mr *MockTransactionRuleMockRecorder) CacheKeysByUserTX(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheKeysByUserTX", reflect.TypeOf((*MockTransactionRule)(nil).CacheKeysByUserTX), ctx, tx, userID)
+}
+
+// Create mocks base method.
+func (m *MockTransactionRule) Create(ctx context.Context, rule *domain.TransactionRule) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, rule)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransactionRuleMockRecorder) Create(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactionRule)(nil).Create), ctx, rule)
+}
+
+// Delete mocks base method.
+func (m *MockTransactionRule) Delete(ctx context.Context, ruleID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, ruleID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTransactionRuleMockRecorder) Delete(ctx, ruleID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTransactionRule)(nil).Delete), ctx, ruleID, userID)
+}
+
+// GetByID mocks base method.
+func (m *MockTransactionRule) GetByID(ctx context.Context, ruleID, userID string) (*domain.TransactionRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, ruleID, userID)
+	ret0, _ := ret[0].(*domain.TransactionRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTransactionRuleMockRecorder) GetByID(ctx, ruleID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTransactionRule)(nil).GetByID), ctx, ruleID, userID)
+}
+
+// ListByUserID mocks base method.
+func (m *MockTransactionRule) ListByUserID(ctx context.Context, userID string) ([]*domain.TransactionRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*domain.TransactionRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockTransactionRuleMockRecorder) ListByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockTransactionRule)(nil).ListByUserID), ctx, userID)
+}
+
+// ReassignCategoryTX mocks base method.
+func (m *MockTransactionRule) ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignCategoryTX", ctx, tx, fromCategoryID, toCategoryID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReassignCategoryTX indicates an expected call of ReassignCategoryTX.
+func (mr *MockTransactionRuleMockRecorder) ReassignCategoryTX(ctx, tx, fromCategoryID, toCategoryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignCategoryTX", reflect.TypeOf((*MockTransactionRule)(nil).ReassignCategoryTX), ctx, tx, fromCategoryID, toCategoryID)
+}
+
+// Update mocks base method.
+func (m *MockTransactionRule) Update(ctx context.Context, rule *domain.TransactionRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTransactionRuleMockRecorder) Update(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTransactionRule)(nil).Update), ctx, rule)
+}
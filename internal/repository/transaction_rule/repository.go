@@ -0,0 +1,199 @@
+package transaction_rule
+
+import (
+	"context"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/db"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"time"
+)
+
+type TransactionRule interface {
+	Create(ctx context.Context, rule *domain.TransactionRule) (string, error)
+	GetByID(ctx context.Context, ruleID, userID string) (*domain.TransactionRule, error)
+	ListByUserID(ctx context.Context, userID string) ([]*domain.TransactionRule, error)
+	Update(ctx context.Context, rule *domain.TransactionRule) error
+	Delete(ctx context.Context, ruleID, userID string) error
+	ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error)
+	CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error)
+}
+
+const (
+	TransactionRuleTable = "transaction_rules"
+
+	TTL_ListRulesByUserIDCache = 30 * time.Minute
+	TTL_GetRuleByIDCache       = 30 * time.Minute
+
+	cacheKeyRulesByUser     = "rules:user:%s"
+	cacheKeyRuleByIDAndUser = "rule:%s:user:%s"
+)
+
+type TransactionRuleRepository struct {
+	postgres *sqlx.DB
+	redis    *redis.Client
+}
+
+func NewTransactionRuleRepository(postgres *sqlx.DB, redis *redis.Client) *TransactionRuleRepository {
+	return &TransactionRuleRepository{
+		postgres: postgres,
+		redis:    redis,
+	}
+}
+
+func (r *TransactionRuleRepository) cacheKeys(userID, ruleID string) []string {
+	keys := []string{
+		fmt.Sprintf(cacheKeyRulesByUser, userID),
+	}
+	if ruleID != "" {
+		keys = append(keys, fmt.Sprintf(cacheKeyRuleByIDAndUser, ruleID, userID))
+	}
+	return keys
+}
+
+func (r *TransactionRuleRepository) InvalidateCache(ctx context.Context, userID, ruleID string) error {
+	zap.L().Sugar().Infof("Invalidating transaction rule cache for userID: %s, ruleID: %s", userID, ruleID)
+
+	keys := r.cacheKeys(userID, ruleID)
+	if err := r.redis.Del(ctx, keys...).Err(); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate transaction rule cache for userID: %s, ruleID: %s, error: %v", userID, ruleID, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *TransactionRuleRepository) Create(ctx context.Context, rule *domain.TransactionRule) (string, error) {
+	query := fmt.Sprintf(`INSERT INTO %s
+		(user_id, name, priority, note_contains, note_pattern, min_amount, max_amount, transaction_type, budget_id, category_id, tag)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`, TransactionRuleTable)
+
+	var id string
+	if err := r.postgres.QueryRowContext(ctx, query,
+		rule.UserID, rule.Name, rule.Priority, rule.NoteContains, rule.NotePattern, rule.MinAmount, rule.MaxAmount,
+		rule.TransactionType, rule.BudgetID, rule.CategoryID, rule.Tag,
+	).Scan(&id); err != nil {
+		zap.L().Sugar().Errorf("Failed to create transaction rule for userID: %s, error: %v", rule.UserID, err)
+		return "", err
+	}
+
+	if err := r.InvalidateCache(ctx, rule.UserID, id); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after create, userID: %s, error: %v", rule.UserID, err)
+	}
+
+	zap.L().Sugar().Infof("Transaction rule created, ruleID: %s, userID: %s", id, rule.UserID)
+	return id, nil
+}
+
+func (r *TransactionRuleRepository) GetByID(ctx context.Context, ruleID, userID string) (*domain.TransactionRule, error) {
+	if ruleID == "" || userID == "" {
+		return nil, fmt.Errorf("ruleID and userID cannot be empty")
+	}
+
+	cacheKey := fmt.Sprintf(cacheKeyRuleByIDAndUser, ruleID, userID)
+
+	fetch := func() (*domain.TransactionRule, error) {
+		var rule domain.TransactionRule
+		query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1 AND user_id = $2", TransactionRuleTable)
+		if err := r.postgres.GetContext(ctx, &rule, query, ruleID, userID); err != nil {
+			zap.L().Sugar().Errorf("Failed to fetch transaction rule from DB, ruleID: %s, userID: %s, error: %v", ruleID, userID, err)
+			return nil, err
+		}
+		return &rule, nil
+	}
+
+	return db.WithCache(ctx, r.redis, cacheKey, TTL_GetRuleByIDCache, fetch)
+}
+
+// ListByUserID returns the rules of the user in the order they run.
+func (r *TransactionRuleRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.TransactionRule, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("userID cannot be empty")
+	}
+
+	cacheKey := fmt.Sprintf(cacheKeyRulesByUser, userID)
+
+	fetch := func() ([]*domain.TransactionRule, error) {
+		var rules []*domain.TransactionRule
+		query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1 ORDER BY priority ASC, created_at ASC, id ASC", TransactionRuleTable)
+		if err := r.postgres.SelectContext(ctx, &rules, query, userID); err != nil {
+			zap.L().Sugar().Errorf("Failed to fetch transaction rules from DB, userID: %s, error: %v", userID, err)
+			return nil, err
+		}
+		return rules, nil
+	}
+
+	return db.WithCache(ctx, r.redis, cacheKey, TTL_ListRulesByUserIDCache, fetch)
+}
+
+func (r *TransactionRuleRepository) Update(ctx context.Context, rule *domain.TransactionRule) error {
+	query := fmt.Sprintf(`UPDATE %s
+		SET name = $1, priority = $2, note_contains = $3, note_pattern = $4, min_amount = $5, max_amount = $6,
+			transaction_type = $7, budget_id = $8, category_id = $9, tag = $10, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $11 AND user_id = $12`, TransactionRuleTable)
+	if _, err := r.postgres.ExecContext(ctx, query,
+		rule.Name, rule.Priority, rule.NoteContains, rule.NotePattern, rule.MinAmount, rule.MaxAmount,
+		rule.TransactionType, rule.BudgetID, rule.CategoryID, rule.Tag, rule.ID, rule.UserID,
+	); err != nil {
+		zap.L().Sugar().Errorf("Failed to update transaction rule, ruleID: %s, userID: %s, error: %v", rule.ID, rule.UserID, err)
+		return err
+	}
+
+	if err := r.InvalidateCache(ctx, rule.UserID, rule.ID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after update, ruleID: %s, error: %v", rule.ID, err)
+	}
+
+	zap.L().Sugar().Infof("Transaction rule updated, ruleID: %s, userID: %s", rule.ID, rule.UserID)
+	return nil
+}
+
+func (r *TransactionRuleRepository) Delete(ctx context.Context, ruleID, userID string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", TransactionRuleTable)
+	if _, err := r.postgres.ExecContext(ctx, query, ruleID, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to delete transaction rule, ruleID: %s, userID: %s, error: %v", ruleID, userID, err)
+		return err
+	}
+
+	if err := r.InvalidateCache(ctx, userID, ruleID); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate cache after delete, ruleID: %s, error: %v", ruleID, err)
+	}
+
+	zap.L().Sugar().Infof("Transaction rule deleted, ruleID: %s, userID: %s", ruleID, userID)
+	return nil
+}
+
+// ReassignCategoryTX makes every rule that sets fromCategoryID set toCategoryID instead. It
+// returns the cache keys of the changed rules, to be deleted once tx is committed.
+func (r *TransactionRuleRepository) ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error) {
+	var rules []*domain.TransactionRule
+	query := fmt.Sprintf(`UPDATE %s SET category_id = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE category_id = $2 RETURNING *`, TransactionRuleTable)
+	if err := tx.SelectContext(ctx, &rules, query, toCategoryID, fromCategoryID); err != nil {
+		zap.L().Sugar().Errorf("Failed to reassign transaction rules, fromCategoryID: %s, toCategoryID: %s, error: %v", fromCategoryID, toCategoryID, err)
+		return nil, err
+	}
+
+	var keys []string
+	for _, rule := range rules {
+		keys = append(keys, r.cacheKeys(rule.UserID, rule.ID)...)
+	}
+	return keys, nil
+}
+
+// CacheKeysByUserTX lists the cache keys of the user's transaction rules.
+func (r *TransactionRuleRepository) CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error) {
+	var ids []string
+	query := fmt.Sprintf("SELECT id FROM %s WHERE user_id = $1", TransactionRuleTable)
+	if err := tx.SelectContext(ctx, &ids, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to list transaction rule IDs, userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+
+	keys := r.cacheKeys(userID, "")
+	for _, id := range ids {
+		keys = append(keys, r.cacheKeys(userID, id)[1:]...)
+	}
+	return keys, nil
+}
@@ -0,0 +1,223 @@
+package transaction_rule
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/testutil"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestTransactionRuleRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cacheKeys", func(t *testing.T) {
+		sqlxDB, _, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRuleRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			expected := []string{
+				fmt.Sprintf(cacheKeyRulesByUser, "123"),
+				fmt.Sprintf(cacheKeyRuleByIDAndUser, "456", "123"),
+			}
+			assert.Equal(t, expected, repo.cacheKeys("123", "456"))
+		})
+
+		t.Run("WithoutRuleID", func(t *testing.T) {
+			assert.Equal(t, []string{fmt.Sprintf(cacheKeyRulesByUser, "123")}, repo.cacheKeys("123", ""))
+		})
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRuleRepository(sqlxDB, redisClient)
+
+		amount := domain.MustParseMoney("9.99")
+		rule := &domain.TransactionRule{
+			UserID:      "123",
+			Name:        "Netflix",
+			Priority:    1,
+			NotePattern: sql.NullString{String: "netflix", Valid: true},
+			MinAmount:   &amount,
+			MaxAmount:   &amount,
+			CategoryID:  sql.NullString{String: "101", Valid: true},
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			redisClient.Set(ctx, fmt.Sprintf(cacheKeyRulesByUser, "123"), "data", 0)
+
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", TransactionRuleTable)).
+				WithArgs("123", "Netflix", 1, sql.NullString{}, rule.NotePattern, &amount, &amount, sql.NullString{}, sql.NullString{}, rule.CategoryID, sql.NullString{}).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rule1"))
+
+			id, err := repo.Create(ctx, rule)
+			assert.NoError(t, err)
+			assert.Equal(t, "rule1", id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			exists, _ := redisClient.Exists(ctx, fmt.Sprintf(cacheKeyRulesByUser, "123")).Result()
+			assert.Equal(t, int64(0), exists)
+		})
+
+		t.Run("DatabaseError", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("INSERT INTO %s", TransactionRuleTable)).
+				WillReturnError(errors.New("db error"))
+
+			id, err := repo.Create(ctx, rule)
+			assert.Error(t, err)
+			assert.Empty(t, id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ListByUserID", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRuleRepository(sqlxDB, redisClient)
+
+		t.Run("CacheMiss", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE user_id = \\$1 ORDER BY priority ASC", TransactionRuleTable)).
+				WithArgs("123").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "priority", "min_amount", "tag"}).
+					AddRow("rule1", "123", "Uber", 0, nil, "rides").
+					AddRow("rule2", "123", "Big spend", 1, "100.00", nil))
+
+			rules, err := repo.ListByUserID(ctx, "123")
+			assert.NoError(t, err)
+			assert.Len(t, rules, 2)
+			assert.Nil(t, rules[0].MinAmount)
+			assert.Equal(t, sql.NullString{String: "rides", Valid: true}, rules[0].Tag)
+			assert.Equal(t, domain.MustParseMoney("100.00"), *rules[1].MinAmount)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			cached, err := redisClient.Get(ctx, fmt.Sprintf(cacheKeyRulesByUser, "123")).Result()
+			assert.NoError(t, err)
+			assert.NotEmpty(t, cached)
+		})
+
+		t.Run("CacheHit", func(t *testing.T) {
+			rules, err := repo.ListByUserID(ctx, "123")
+			assert.NoError(t, err)
+			assert.Len(t, rules, 2)
+			assert.Equal(t, domain.MustParseMoney("100.00"), *rules[1].MinAmount)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("EmptyUserID", func(t *testing.T) {
+			rules, err := repo.ListByUserID(ctx, "")
+			assert.Error(t, err)
+			assert.Nil(t, rules)
+		})
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRuleRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			rule := &domain.TransactionRule{
+				ID:           "rule1",
+				UserID:       "123",
+				Name:         "Uber",
+				NoteContains: sql.NullString{String: "uber", Valid: true},
+				Tag:          sql.NullString{String: "rides", Valid: true},
+			}
+			cacheKey := fmt.Sprintf(cacheKeyRuleByIDAndUser, "rule1", "123")
+			redisClient.Set(ctx, cacheKey, "data", 0)
+
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET name = \\$1(.+)WHERE id = \\$11 AND user_id = \\$12", TransactionRuleTable)).
+				WithArgs("Uber", 0, rule.NoteContains, sql.NullString{}, nil, nil, sql.NullString{}, sql.NullString{}, sql.NullString{}, rule.Tag, "rule1", "123").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.Update(ctx, rule)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			exists, _ := redisClient.Exists(ctx, cacheKey).Result()
+			assert.Equal(t, int64(0), exists)
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRuleRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND user_id = \\$2", TransactionRuleTable)).
+				WithArgs("rule1", "123").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.Delete(ctx, "rule1", "123")
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ReassignCategoryTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRuleRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("UPDATE %s SET category_id = \\$1(.+)WHERE category_id = \\$2 RETURNING", TransactionRuleTable)).
+				WithArgs("category2", "category1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow("rule1", "user1"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.ReassignCategoryTX(ctx, tx, "category1", "category2")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{fmt.Sprintf(cacheKeyRulesByUser, "user1"), fmt.Sprintf(cacheKeyRuleByIDAndUser, "rule1", "user1")}, keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("CacheKeysByUserTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTransactionRuleRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(fmt.Sprintf("SELECT id FROM %s WHERE user_id", TransactionRuleTable)).
+				WithArgs("user1").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rule1"))
+
+			tx, _ := sqlxDB.Beginx()
+			keys, err := repo.CacheKeysByUserTX(ctx, tx, "user1")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{fmt.Sprintf(cacheKeyRulesByUser, "user1"), fmt.Sprintf(cacheKeyRuleByIDAndUser, "rule1", "user1")}, keys)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+}
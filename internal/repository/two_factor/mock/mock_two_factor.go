@@ -0,0 +1,204 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/two_factor/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/two_factor/repository.go -destination=internal/repository/two_factor/mock/mock_two_factor.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "finly-backend/internal/domain"
+	reflect "reflect"
+
+	sqlx "github.com/jmoiron/sqlx"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTwoFactor is a mock of TwoFactor interface.
+type MockTwoFactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorMockRecorder
+	isgomock struct{}
+}
+
+// MockTwoFactorMockRecorder is the mock recorder for MockTwoFactor.
+type MockTwoFactorMockRecorder struct {
+	mock *MockTwoFactor
+}
+
+// NewMockTwoFactor creates a new mock instance.
+func NewMockTwoFactor(ctrl *gomock.Controller) *MockTwoFactor {
+	mock := &MockTwoFactor{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactor) EXPECT() *MockTwoFactorMockRecorder {
+	return m.recorder
+}
+
+// AttemptChallenge mocks base method.
+func (m *MockTwoFactor) AttemptChallenge(ctx context.Context, tokenHash string) (*domain.TwoFactorChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttemptChallenge", ctx, tokenHash)
+	ret0, _ := ret[0].(*domain.TwoFactorChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttemptChallenge indicates an expected call of AttemptChallenge.
+func (mr *MockTwoFactorMockRecorder) AttemptChallenge(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttemptChallenge", reflect.TypeOf((*MockTwoFactor)(nil).AttemptChallenge), ctx, tokenHash)
+}
+
+// ConfirmTX mocks base method.
+func (m *MockTwoFactor) ConfirmTX(ctx context.Context, tx *sqlx.Tx, userID string, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTX", ctx, tx, userID, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTX indicates an expected call of ConfirmTX.
+func (mr *MockTwoFactorMockRecorder) ConfirmTX(ctx, tx, userID, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTX", reflect.TypeOf((*MockTwoFactor)(nil).ConfirmTX), ctx, tx, userID, step)
+}
+
+// CreateChallenge mocks base method.
+func (m *MockTwoFactor) CreateChallenge(ctx context.Context, tokenHash string, challenge *domain.TwoFactorChallenge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChallenge", ctx, tokenHash, challenge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateChallenge indicates an expected call of CreateChallenge.
+func (mr *MockTwoFactorMockRecorder) CreateChallenge(ctx, tokenHash, challenge any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallenge", reflect.TypeOf((*MockTwoFactor)(nil).CreateChallenge), ctx, tokenHash, challenge)
+}
+
+// DeleteChallenge mocks base method.
+func (m *MockTwoFactor) DeleteChallenge(ctx context.Context, tokenHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChallenge", ctx, tokenHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteChallenge indicates an expected call of DeleteChallenge.
+func (mr *MockTwoFactorMockRecorder) DeleteChallenge(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChallenge", reflect.TypeOf((*MockTwoFactor)(nil).DeleteChallenge), ctx, tokenHash)
+}
+
+// DeleteTX mocks base method.
+func (m *MockTwoFactor) DeleteTX(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTX", ctx, tx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTX indicates an expected call of DeleteTX.
+func (mr *MockTwoFactorMockRecorder) DeleteTX(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTX", reflect.TypeOf((*MockTwoFactor)(nil).DeleteTX), ctx, tx, userID)
+}
+
+// GetCredential mocks base method.
+func (m *MockTwoFactor) GetCredential(ctx context.Context, userID string) (*domain.TOTPCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCredential", ctx, userID)
+	ret0, _ := ret[0].(*domain.TOTPCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCredential indicates an expected call of GetCredential.
+func (mr *MockTwoFactorMockRecorder) GetCredential(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredential", reflect.TypeOf((*MockTwoFactor)(nil).GetCredential), ctx, userID)
+}
+
+// GetDB mocks base method.
+func (m *MockTwoFactor) GetDB() *sqlx.DB {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDB")
+	ret0, _ := ret[0].(*sqlx.DB)
+	return ret0
+}
+
+// GetDB indicates an expected call of GetDB.
+func (mr *MockTwoFactorMockRecorder) GetDB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDB", reflect.TypeOf((*MockTwoFactor)(nil).GetDB))
+}
+
+// ReplaceRecoveryCodesTX mocks base method.
+func (m *MockTwoFactor) ReplaceRecoveryCodesTX(ctx context.Context, tx *sqlx.Tx, userID string, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodesTX", ctx, tx, userID, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodesTX indicates an expected call of ReplaceRecoveryCodesTX.
+func (mr *MockTwoFactorMockRecorder) ReplaceRecoveryCodesTX(ctx, tx, userID, codeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodesTX", reflect.TypeOf((*MockTwoFactor)(nil).ReplaceRecoveryCodesTX), ctx, tx, userID, codeHashes)
+}
+
+// SavePending mocks base method.
+func (m *MockTwoFactor) SavePending(ctx context.Context, userID, secret string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePending", ctx, userID, secret)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SavePending indicates an expected call of SavePending.
+func (mr *MockTwoFactorMockRecorder) SavePending(ctx, userID, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePending", reflect.TypeOf((*MockTwoFactor)(nil).SavePending), ctx, userID, secret)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockTwoFactor) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userID, codeHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockTwoFactorMockRecorder) UseRecoveryCode(ctx, userID, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockTwoFactor)(nil).UseRecoveryCode), ctx, userID, codeHash)
+}
+
+// UseStep mocks base method.
+func (m *MockTwoFactor) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseStep", ctx, userID, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseStep indicates an expected call of UseStep.
+func (mr *MockTwoFactorMockRecorder) UseStep(ctx, userID, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseStep", reflect.TypeOf((*MockTwoFactor)(nil).UseStep), ctx, userID, step)
+}
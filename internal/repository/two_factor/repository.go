@@ -0,0 +1,227 @@
+package two_factor
+
+import (
+	"context"
+	"errors"
+	"finly-backend/internal/domain"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"time"
+)
+
+type TwoFactor interface {
+	GetDB() *sqlx.DB
+	GetCredential(ctx context.Context, userID string) (*domain.TOTPCredential, error)
+	SavePending(ctx context.Context, userID, secret string) (bool, error)
+	ConfirmTX(ctx context.Context, tx *sqlx.Tx, userID string, step int64) (bool, error)
+	UseStep(ctx context.Context, userID string, step int64) (bool, error)
+	DeleteTX(ctx context.Context, tx *sqlx.Tx, userID string) error
+	ReplaceRecoveryCodesTX(ctx context.Context, tx *sqlx.Tx, userID string, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+	CreateChallenge(ctx context.Context, tokenHash string, challenge *domain.TwoFactorChallenge) error
+	AttemptChallenge(ctx context.Context, tokenHash string) (*domain.TwoFactorChallenge, error)
+	DeleteChallenge(ctx context.Context, tokenHash string) (bool, error)
+}
+
+const (
+	TOTPCredentialTable = "totp_credentials"
+	RecoveryCodeTable   = "recovery_codes"
+
+	// TTL_TwoFactorChallenge is how long a user has to enter their code after the password.
+	TTL_TwoFactorChallenge = 5 * time.Minute
+
+	cacheKeyTwoFactorChallenge = "two_factor:challenge:%s"
+)
+
+// ErrChallengeNotFound is returned for a challenge that expired, was used or never existed.
+var ErrChallengeNotFound = errors.New("two-factor challenge not found")
+
+// TwoFactorRepository keeps credentials and recovery codes in Postgres without a cache,
+// as they are read once per sign-in. Sign-in challenges only live in Redis.
+type TwoFactorRepository struct {
+	postgres *sqlx.DB
+	redis    *redis.Client
+}
+
+func NewTwoFactorRepository(postgres *sqlx.DB, redis *redis.Client) *TwoFactorRepository {
+	return &TwoFactorRepository{
+		postgres: postgres,
+		redis:    redis,
+	}
+}
+
+func (r *TwoFactorRepository) GetDB() *sqlx.DB {
+	return r.postgres
+}
+
+func (r *TwoFactorRepository) GetCredential(ctx context.Context, userID string) (*domain.TOTPCredential, error) {
+	var credential domain.TOTPCredential
+	query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1", TOTPCredentialTable)
+	if err := r.postgres.GetContext(ctx, &credential, query, userID); err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// SavePending stores a new secret that still has to be confirmed, replacing an earlier
+// unconfirmed one. It reports false when two-factor authentication is already on.
+func (r *TwoFactorRepository) SavePending(ctx context.Context, userID, secret string) (bool, error) {
+	query := fmt.Sprintf(`INSERT INTO %[1]s (user_id, secret) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, created_at = CURRENT_TIMESTAMP
+		WHERE %[1]s.confirmed_at IS NULL`, TOTPCredentialTable)
+	res, err := r.postgres.ExecContext(ctx, query, userID, secret)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to save TOTP secret, userID: %s, error: %v", userID, err)
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ConfirmTX turns two-factor authentication on and records the step of the code it was
+// confirmed with, so that code can't sign in afterwards.
+func (r *TwoFactorRepository) ConfirmTX(ctx context.Context, tx *sqlx.Tx, userID string, step int64) (bool, error) {
+	query := fmt.Sprintf(`UPDATE %s SET confirmed_at = CURRENT_TIMESTAMP, last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NULL`, TOTPCredentialTable)
+	res, err := tx.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to confirm TOTP credential, userID: %s, error: %v", userID, err)
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// UseStep records that the code of a time step was used. It reports false when a code of
+// that or a later step was used before, which makes every code single-use.
+func (r *TwoFactorRepository) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	query := fmt.Sprintf(`UPDATE %s SET last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NOT NULL AND last_used_step < $2`, TOTPCredentialTable)
+	res, err := r.postgres.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to record TOTP step, userID: %s, error: %v", userID, err)
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// DeleteTX turns two-factor authentication off and drops the recovery codes.
+func (r *TwoFactorRepository) DeleteTX(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	for _, table := range []string{RecoveryCodeTable, TOTPCredentialTable} {
+		query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", table)
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			zap.L().Sugar().Errorf("Failed to delete from %s, userID: %s, error: %v", table, userID, err)
+			return err
+		}
+	}
+
+	zap.L().Sugar().Infof("Two-factor authentication removed, userID: %s", userID)
+	return nil
+}
+
+// ReplaceRecoveryCodesTX swaps all recovery codes of the user, used or not, for new ones.
+func (r *TwoFactorRepository) ReplaceRecoveryCodesTX(ctx context.Context, tx *sqlx.Tx, userID string, codeHashes []string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", RecoveryCodeTable)
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to delete recovery codes, userID: %s, error: %v", userID, err)
+		return err
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (user_id, code_hash) SELECT $1, UNNEST($2::TEXT[])", RecoveryCodeTable)
+	if _, err := tx.ExecContext(ctx, query, userID, pq.Array(codeHashes)); err != nil {
+		zap.L().Sugar().Errorf("Failed to create recovery codes, userID: %s, error: %v", userID, err)
+		return err
+	}
+
+	zap.L().Sugar().Infof("Recovery codes replaced, userID: %s", userID)
+	return nil
+}
+
+// UseRecoveryCode uses up a recovery code. It reports false when the user has no unused
+// code with that hash.
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	query := fmt.Sprintf(`UPDATE %s SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`, RecoveryCodeTable)
+	res, err := r.postgres.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to use recovery code, userID: %s, error: %v", userID, err)
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *TwoFactorRepository) CreateChallenge(ctx context.Context, tokenHash string, challenge *domain.TwoFactorChallenge) error {
+	key := fmt.Sprintf(cacheKeyTwoFactorChallenge, tokenHash)
+
+	_, err := r.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "user_id", challenge.UserID, "email", challenge.Email, "attempts", 0)
+		pipe.Expire(ctx, key, TTL_TwoFactorChallenge)
+		return nil
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to create two-factor challenge, userID: %s, error: %v", challenge.UserID, err)
+		return err
+	}
+	return nil
+}
+
+// AttemptChallenge counts an attempt to answer a challenge and returns the challenge with
+// the attempts made so far, this one included.
+func (r *TwoFactorRepository) AttemptChallenge(ctx context.Context, tokenHash string) (*domain.TwoFactorChallenge, error) {
+	key := fmt.Sprintf(cacheKeyTwoFactorChallenge, tokenHash)
+
+	var fields *redis.MapStringStringCmd
+	_, err := r.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, key, "attempts", 1)
+		// A challenge that expired just now is recreated by HIncrBy without a TTL.
+		pipe.ExpireNX(ctx, key, TTL_TwoFactorChallenge)
+		fields = pipe.HGetAll(ctx, key)
+		return nil
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to count two-factor attempt, error: %v", err)
+		return nil, err
+	}
+
+	var challenge domain.TwoFactorChallenge
+	if err = fields.Scan(&challenge); err != nil {
+		return nil, err
+	}
+	if challenge.UserID == "" {
+		r.redis.Del(ctx, key)
+		return nil, ErrChallengeNotFound
+	}
+	return &challenge, nil
+}
+
+// DeleteChallenge ends a challenge. It reports false when it had already ended, so only
+// one of concurrent correct answers signs in.
+func (r *TwoFactorRepository) DeleteChallenge(ctx context.Context, tokenHash string) (bool, error) {
+	n, err := r.redis.Del(ctx, fmt.Sprintf(cacheKeyTwoFactorChallenge, tokenHash)).Result()
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to delete two-factor challenge, error: %v", err)
+		return false, err
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,244 @@
+package two_factor
+
+import (
+	"context"
+	"database/sql"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/testutil"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestTwoFactorRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("GetCredential", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTwoFactorRepository(sqlxDB, redisClient)
+
+		t.Run("Success", func(t *testing.T) {
+			confirmedAt := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE user_id = \\$1", TOTPCredentialTable)).
+				WithArgs("123").
+				WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "confirmed_at", "last_used_step", "created_at"}).
+					AddRow("123", "SECRET", confirmedAt, 42, confirmedAt))
+
+			credential, err := repo.GetCredential(ctx, "123")
+			assert.NoError(t, err)
+			assert.Equal(t, "SECRET", credential.Secret)
+			assert.True(t, credential.ConfirmedAt.Valid)
+			assert.Equal(t, int64(42), credential.LastUsedStep)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE user_id = \\$1", TOTPCredentialTable)).
+				WithArgs("123").
+				WillReturnError(sql.ErrNoRows)
+
+			credential, err := repo.GetCredential(ctx, "123")
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			assert.Nil(t, credential)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("SavePending", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTwoFactorRepository(sqlxDB, redisClient)
+
+		t.Run("Saved", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("INSERT INTO %s", TOTPCredentialTable)).
+				WithArgs("123", "SECRET").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			saved, err := repo.SavePending(ctx, "123", "SECRET")
+			assert.NoError(t, err)
+			assert.True(t, saved)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("AlreadyConfirmed", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("INSERT INTO %s", TOTPCredentialTable)).
+				WithArgs("123", "SECRET").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			saved, err := repo.SavePending(ctx, "123", "SECRET")
+			assert.NoError(t, err)
+			assert.False(t, saved)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("ConfirmTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTwoFactorRepository(sqlxDB, redisClient)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(fmt.Sprintf("UPDATE %s SET confirmed_at = CURRENT_TIMESTAMP", TOTPCredentialTable)).
+			WithArgs("123", int64(100)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		tx, _ := sqlxDB.Beginx()
+		confirmed, err := repo.ConfirmTX(ctx, tx, "123", 100)
+		assert.NoError(t, err)
+		assert.True(t, confirmed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("UseStep", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTwoFactorRepository(sqlxDB, redisClient)
+
+		t.Run("NewStep", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET last_used_step = \\$2", TOTPCredentialTable)).
+				WithArgs("123", int64(100)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			used, err := repo.UseStep(ctx, "123", 100)
+			assert.NoError(t, err)
+			assert.True(t, used)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("StepUsedBefore", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET last_used_step = \\$2", TOTPCredentialTable)).
+				WithArgs("123", int64(100)).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			used, err := repo.UseStep(ctx, "123", 100)
+			assert.NoError(t, err)
+			assert.False(t, used)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("DeleteTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTwoFactorRepository(sqlxDB, redisClient)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE user_id = \\$1", RecoveryCodeTable)).
+			WithArgs("123").
+			WillReturnResult(sqlmock.NewResult(0, 10))
+		mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE user_id = \\$1", TOTPCredentialTable)).
+			WithArgs("123").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		tx, _ := sqlxDB.Beginx()
+		err := repo.DeleteTX(ctx, tx, "123")
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ReplaceRecoveryCodesTX", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTwoFactorRepository(sqlxDB, redisClient)
+
+		hashes := []string{"hash1", "hash2"}
+		mock.ExpectBegin()
+		mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE user_id = \\$1", RecoveryCodeTable)).
+			WithArgs("123").
+			WillReturnResult(sqlmock.NewResult(0, 10))
+		mock.ExpectExec(fmt.Sprintf("INSERT INTO %s", RecoveryCodeTable)).
+			WithArgs("123", pq.Array(hashes)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		tx, _ := sqlxDB.Beginx()
+		err := repo.ReplaceRecoveryCodesTX(ctx, tx, "123", hashes)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("UseRecoveryCode", func(t *testing.T) {
+		sqlxDB, mock, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTwoFactorRepository(sqlxDB, redisClient)
+
+		t.Run("Used", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET used_at = CURRENT_TIMESTAMP", RecoveryCodeTable)).
+				WithArgs("123", "hash").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			used, err := repo.UseRecoveryCode(ctx, "123", "hash")
+			assert.NoError(t, err)
+			assert.True(t, used)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run("UnknownOrUsedCode", func(t *testing.T) {
+			mock.ExpectExec(fmt.Sprintf("UPDATE %s SET used_at = CURRENT_TIMESTAMP", RecoveryCodeTable)).
+				WithArgs("123", "hash").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			used, err := repo.UseRecoveryCode(ctx, "123", "hash")
+			assert.NoError(t, err)
+			assert.False(t, used)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("Challenge", func(t *testing.T) {
+		sqlxDB, _, redisClient, mr, logger := testutil.SetupRepositoryTest(t)
+		defer sqlxDB.Close()
+		defer mr.Close()
+
+		zap.ReplaceGlobals(logger)
+		repo := NewTwoFactorRepository(sqlxDB, redisClient)
+
+		err := repo.CreateChallenge(ctx, "hash", &domain.TwoFactorChallenge{UserID: "123", Email: "test@example.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, TTL_TwoFactorChallenge, mr.TTL(fmt.Sprintf(cacheKeyTwoFactorChallenge, "hash")))
+
+		for attempt := 1; attempt <= 2; attempt++ {
+			challenge, err := repo.AttemptChallenge(ctx, "hash")
+			assert.NoError(t, err)
+			assert.Equal(t, &domain.TwoFactorChallenge{UserID: "123", Email: "test@example.com", Attempts: attempt}, challenge)
+		}
+
+		deleted, err := repo.DeleteChallenge(ctx, "hash")
+		assert.NoError(t, err)
+		assert.True(t, deleted)
+
+		deleted, err = repo.DeleteChallenge(ctx, "hash")
+		assert.NoError(t, err)
+		assert.False(t, deleted)
+
+		challenge, err := repo.AttemptChallenge(ctx, "hash")
+		assert.ErrorIs(t, err, ErrChallengeNotFound)
+		assert.Nil(t, challenge)
+		assert.False(t, mr.Exists(fmt.Sprintf(cacheKeyTwoFactorChallenge, "hash")))
+	})
+}
@@ -0,0 +1,20 @@
+package api_key
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+var errs = struct {
+	APIKeyNotFound *echo.HTTPError
+	InvalidAPIKey  *echo.HTTPError
+	APIKeyExpired  *echo.HTTPError
+	InvalidExpiry  *echo.HTTPError
+	DatabaseError  *echo.HTTPError
+}{
+	APIKeyNotFound: echo.NewHTTPError(http.StatusNotFound, "API key not found"),
+	InvalidAPIKey:  echo.NewHTTPError(http.StatusUnauthorized, "Invalid API key"),
+	APIKeyExpired:  echo.NewHTTPError(http.StatusUnauthorized, "API key has expired"),
+	InvalidExpiry:  echo.NewHTTPError(http.StatusBadRequest, "Expiry must be in the future"),
+	DatabaseError:  echo.NewHTTPError(http.StatusInternalServerError, "Database operation failed"),
+}
@@ -0,0 +1,44 @@
+package api_key
+
+import (
+	"finly-backend/internal/domain/enums/e_api_key_scope"
+	"time"
+)
+
+type APIKeyObject struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateAPIKeyRequest struct {
+	UserID    string                 `header:"User-Id" validate:"required"`
+	Name      string                 `json:"name" validate:"required,max=100"`
+	Scopes    []e_api_key_scope.Enum `json:"scopes" validate:"required,min=1,dive,oneof=read budgets:write categories:write transactions:write recurring:write limits:write rules:write"`
+	ExpiresAt *time.Time             `json:"expires_at"`
+}
+
+// CreateAPIKeyResponse carries the key itself, which is shown only this once.
+type CreateAPIKeyResponse struct {
+	APIKeyObject
+	Key string `json:"key"`
+}
+
+type ListAPIKeysRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+}
+
+type ListAPIKeysResponse struct {
+	Keys []*APIKeyObject `json:"keys"`
+}
+
+type DeleteAPIKeyRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	ID     string `param:"id" validate:"required,uuid"`
+}
+
+type DeleteAPIKeyResponse struct{}
@@ -0,0 +1,119 @@
+package api_key
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/api_key"
+	"finly-backend/pkg/security"
+	"go.uber.org/zap"
+	"time"
+)
+
+type APIKey interface {
+	Create(ctx context.Context, req *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	List(ctx context.Context, req *ListAPIKeysRequest) (*ListAPIKeysResponse, error)
+	Delete(ctx context.Context, req *DeleteAPIKeyRequest) (*DeleteAPIKeyResponse, error)
+	Authenticate(ctx context.Context, key string) (string, []string, error)
+}
+
+type Service struct {
+	apiKeyRepo api_key.APIKey
+}
+
+func NewService(apiKeyRepo api_key.APIKey) *Service {
+	return &Service{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Create issues a new key for the user. Only its hash is stored, so the key is returned
+// this once and can't be shown again.
+func (s *Service) Create(ctx context.Context, req *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	now := time.Now().UTC()
+	var expiresAt sql.NullTime
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, errs.InvalidExpiry
+		}
+		expiresAt = sql.NullTime{Time: req.ExpiresAt.UTC(), Valid: true}
+	}
+
+	key, prefix, err := security.GenerateAPIKey()
+	if err != nil {
+		zap.L().Sugar().Errorf("Create: failed to generate API key for userID=%s: %v", req.UserID, err)
+		return nil, err
+	}
+
+	apiKey := &domain.APIKey{
+		UserID:    req.UserID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   security.HashToken(key),
+		Scopes:    uniqueScopes(req.Scopes),
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+	if apiKey.ID, err = s.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		zap.L().Sugar().Errorf("Create: failed to store API key for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("Create: API key id=%s created for userID=%s with scopes %v", apiKey.ID, req.UserID, apiKey.Scopes)
+	return &CreateAPIKeyResponse{APIKeyObject: *convertAPIKey(apiKey), Key: key}, nil
+}
+
+func (s *Service) List(ctx context.Context, req *ListAPIKeysRequest) (*ListAPIKeysResponse, error) {
+	keys, err := s.apiKeyRepo.ListByUserID(ctx, req.UserID)
+	if err != nil {
+		zap.L().Sugar().Errorf("List: failed for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	list := make([]*APIKeyObject, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, convertAPIKey(key))
+	}
+
+	return &ListAPIKeysResponse{Keys: list}, nil
+}
+
+// Delete revokes a key of the user; requests made with it are rejected right away.
+func (s *Service) Delete(ctx context.Context, req *DeleteAPIKeyRequest) (*DeleteAPIKeyResponse, error) {
+	deleted, err := s.apiKeyRepo.Delete(ctx, req.ID, req.UserID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Delete: failed to delete API key id=%s for userID=%s: %v", req.ID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+	if !deleted {
+		return nil, errs.APIKeyNotFound
+	}
+
+	zap.L().Sugar().Infof("Delete: API key id=%s deleted for userID=%s", req.ID, req.UserID)
+	return &DeleteAPIKeyResponse{}, nil
+}
+
+// Authenticate resolves an API key to its user and scopes and records that it was used.
+// It is called by the JWT middleware for every request made with a key.
+func (s *Service) Authenticate(ctx context.Context, key string) (string, []string, error) {
+	apiKey, err := s.apiKeyRepo.GetByHash(ctx, security.HashToken(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, errs.InvalidAPIKey
+		}
+		zap.L().Sugar().Errorf("Authenticate: failed to fetch API key: %v", err)
+		return "", nil, errs.DatabaseError
+	}
+
+	if apiKey.ExpiresAt.Valid && !apiKey.ExpiresAt.Time.After(time.Now().UTC()) {
+		zap.L().Sugar().Warnf("Authenticate: rejected expired API key id=%s of userID=%s", apiKey.ID, apiKey.UserID)
+		return "", nil, errs.APIKeyExpired
+	}
+
+	if err = s.apiKeyRepo.Touch(ctx, apiKey.ID); err != nil {
+		zap.L().Sugar().Warnf("Authenticate: failed to record use of API key id=%s: %v", apiKey.ID, err)
+	}
+
+	return apiKey.UserID, apiKey.Scopes, nil
+}
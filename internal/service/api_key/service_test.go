@@ -0,0 +1,187 @@
+package api_key
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_api_key_scope"
+	"finly-backend/internal/repository/api_key/mock"
+	"finly-backend/pkg/security"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mock.NewMockAPIKey(ctrl)
+	service := NewService(mockAPIKeyRepo)
+	ctx := context.Background()
+
+	t.Run("Key created", func(t *testing.T) {
+		expiresAt := time.Now().Add(24 * time.Hour)
+		var stored *domain.APIKey
+		mockAPIKeyRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, key *domain.APIKey) (string, error) {
+			stored = key
+			return "key1", nil
+		})
+
+		resp, err := service.Create(ctx, &CreateAPIKeyRequest{
+			UserID:    "user123",
+			Name:      "Import script",
+			Scopes:    []e_api_key_scope.Enum{e_api_key_scope.Read, e_api_key_scope.TransactionsWrite, e_api_key_scope.Read},
+			ExpiresAt: &expiresAt,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "key1", resp.ID)
+		assert.True(t, security.IsAPIKey(resp.Key))
+		assert.Equal(t, resp.Key[:len(resp.Prefix)], resp.Prefix)
+		assert.Equal(t, []string{"read", "transactions:write"}, resp.Scopes)
+
+		assert.Equal(t, security.HashToken(resp.Key), stored.KeyHash)
+		assert.Equal(t, pq.StringArray{"read", "transactions:write"}, stored.Scopes)
+		assert.True(t, stored.ExpiresAt.Valid)
+	})
+
+	t.Run("Expiry in the past", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Hour)
+		resp, err := service.Create(ctx, &CreateAPIKeyRequest{
+			UserID:    "user123",
+			Name:      "Import script",
+			Scopes:    []e_api_key_scope.Enum{e_api_key_scope.Read},
+			ExpiresAt: &expiresAt,
+		})
+		assert.Equal(t, errs.InvalidExpiry, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mock.NewMockAPIKey(ctrl)
+	service := NewService(mockAPIKeyRepo)
+	ctx := context.Background()
+	created := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	mockAPIKeyRepo.EXPECT().ListByUserID(ctx, "user123").Return([]*domain.APIKey{
+		{ID: "key1", Name: "Import script", Prefix: "fk_abcdef", KeyHash: "hash1", Scopes: pq.StringArray{"read"}, LastUsedAt: sql.NullTime{Time: created, Valid: true}, CreatedAt: created},
+	}, nil)
+
+	resp, err := service.List(ctx, &ListAPIKeysRequest{UserID: "user123"})
+	assert.NoError(t, err)
+	assert.Equal(t, &ListAPIKeysResponse{Keys: []*APIKeyObject{
+		{ID: "key1", Name: "Import script", Prefix: "fk_abcdef", Scopes: []string{"read"}, LastUsedAt: &created, CreatedAt: created},
+	}}, resp)
+}
+
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mock.NewMockAPIKey(ctrl)
+	service := NewService(mockAPIKeyRepo)
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		mockSetup    func()
+		expectedResp *DeleteAPIKeyResponse
+		expectedErr  error
+	}{
+		{
+			name: "Key deleted",
+			mockSetup: func() {
+				mockAPIKeyRepo.EXPECT().Delete(ctx, "key1", "user123").Return(true, nil)
+			},
+			expectedResp: &DeleteAPIKeyResponse{},
+		},
+		{
+			name: "Key not found",
+			mockSetup: func() {
+				mockAPIKeyRepo.EXPECT().Delete(ctx, "key1", "user123").Return(false, nil)
+			},
+			expectedErr: errs.APIKeyNotFound,
+		},
+		{
+			name: "Database error",
+			mockSetup: func() {
+				mockAPIKeyRepo.EXPECT().Delete(ctx, "key1", "user123").Return(false, errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.Delete(ctx, &DeleteAPIKeyRequest{UserID: "user123", ID: "key1"})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mock.NewMockAPIKey(ctrl)
+	service := NewService(mockAPIKeyRepo)
+	ctx := context.Background()
+	hash := security.HashToken("fk_key")
+
+	tests := []struct {
+		name           string
+		mockSetup      func()
+		expectedUserID string
+		expectedScopes []string
+		expectedErr    error
+	}{
+		{
+			name: "Valid key",
+			mockSetup: func() {
+				mockAPIKeyRepo.EXPECT().GetByHash(ctx, hash).Return(&domain.APIKey{
+					ID: "key1", UserID: "user123", Scopes: pq.StringArray{"read"},
+					ExpiresAt: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+				}, nil)
+				mockAPIKeyRepo.EXPECT().Touch(ctx, "key1").Return(nil)
+			},
+			expectedUserID: "user123",
+			expectedScopes: []string{"read"},
+		},
+		{
+			name: "Unknown key",
+			mockSetup: func() {
+				mockAPIKeyRepo.EXPECT().GetByHash(ctx, hash).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.InvalidAPIKey,
+		},
+		{
+			name: "Expired key",
+			mockSetup: func() {
+				mockAPIKeyRepo.EXPECT().GetByHash(ctx, hash).Return(&domain.APIKey{
+					ID: "key1", UserID: "user123", Scopes: pq.StringArray{"read"},
+					ExpiresAt: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+				}, nil)
+			},
+			expectedErr: errs.APIKeyExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			userID, scopes, err := service.Authenticate(ctx, "fk_key")
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedUserID, userID)
+			assert.Equal(t, tt.expectedScopes, scopes)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package api_key
+
+import (
+	"database/sql"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_api_key_scope"
+	"time"
+)
+
+func convertAPIKey(key *domain.APIKey) *APIKeyObject {
+	return &APIKeyObject{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  nullTimePtr(key.ExpiresAt),
+		LastUsedAt: nullTimePtr(key.LastUsedAt),
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// uniqueScopes drops repeated scopes and keeps the order they were given in.
+func uniqueScopes(scopes []e_api_key_scope.Enum) []string {
+	seen := make(map[e_api_key_scope.Enum]bool, len(scopes))
+	unique := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope.String())
+		}
+	}
+	return unique
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
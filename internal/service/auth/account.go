@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/auth"
+	"finly-backend/pkg/mailer"
+	"finly-backend/pkg/security"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+	"strings"
+)
+
+func (s *Service) UpdateMe(ctx context.Context, req *UpdateMeRequest) (*UpdateMeResponse, error) {
+	user, err := s.getUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	firstName, lastName := user.FirstName, user.LastName
+	if req.FirstName != nil {
+		firstName = *req.FirstName
+	}
+	if req.LastName != nil {
+		lastName = *req.LastName
+	}
+
+	if err = s.authRepo.UpdateProfile(ctx, user.ID, firstName, lastName); err != nil {
+		zap.L().Sugar().Errorf("Error updating profile of userID: %s, error: %v", user.ID, err)
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Profile updated for userID: %s", user.ID)
+	return &UpdateMeResponse{
+		MeResponse: MeResponse{
+			UserInfo: UserInfo{
+				FirstName: firstName,
+				LastName:  lastName,
+				Email:     user.Email,
+			},
+			EmailVerified: user.EmailVerifiedAt.Valid,
+		},
+	}, nil
+}
+
+// ChangePassword sets a new password after checking the current one. Every other session
+// is signed out; the session that made the change stays signed in.
+func (s *Service) ChangePassword(ctx context.Context, req *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	user, err := s.getUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.verifyPassword(ctx, user, req.CurrentPassword, req.IP); err != nil {
+		zap.L().Sugar().Warnf("Invalid current password changing the password of userID: %s", user.ID)
+		return nil, err
+	}
+
+	hashedPassword, err := security.HashPassword(req.NewPassword)
+	if err != nil {
+		zap.L().Sugar().Errorf("Error hashing password, error: %v", err)
+		return nil, err
+	}
+
+	var sessionIDs []string
+	err = s.transactionExecutor.WithTransaction(ctx, s.sessionRepo.GetDB(), func(tx *sqlx.Tx) error {
+		if err := s.authRepo.UpdatePasswordTX(ctx, tx, user.ID, hashedPassword); err != nil {
+			return err
+		}
+		var err error
+		sessionIDs, err = s.revokeSessionsTX(ctx, tx, user.ID, req.SessionID)
+		return err
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("Error changing password of userID: %s, error: %v", user.ID, err)
+		return nil, err
+	}
+
+	s.invalidateSessions(ctx, sessionIDs...)
+
+	if err = s.authRepo.InvalidateCache(ctx, user.ID, user.Email); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate user cache after password change, userID: %s, error: %v", user.ID, err)
+	}
+
+	zap.L().Sugar().Infof("Password changed for userID: %s", user.ID)
+	return &ChangePasswordResponse{Message: "Password has been changed, other sessions have been signed out"}, nil
+}
+
+// ChangeEmail mails a verification link to the new address and lets the current one know
+// about the request. The account keeps its email until the link is opened, see VerifyEmail.
+// Links sent earlier stop working, so only the latest request can complete.
+func (s *Service) ChangeEmail(ctx context.Context, req *ChangeEmailRequest) (*ChangeEmailResponse, error) {
+	user, err := s.getUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.verifyPassword(ctx, user, req.Password, req.IP); err != nil {
+		zap.L().Sugar().Warnf("Invalid password changing the email of userID: %s", user.ID)
+		return nil, err
+	}
+	if err = s.requireSecondFactor(ctx, user, req.Code, req.IP); err != nil {
+		return nil, err
+	}
+	if strings.ToLower(strings.TrimSpace(req.Email)) == strings.ToLower(strings.TrimSpace(user.Email)) {
+		return nil, errs.SameEmail
+	}
+
+	if _, err = s.authRepo.GetUserByEmail(ctx, req.Email); err == nil {
+		return nil, errs.EmailInUse
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		zap.L().Sugar().Errorf("Error checking if email is taken, error: %v", err)
+		return nil, err
+	}
+
+	err = s.transactionExecutor.WithTransaction(ctx, s.verificationRepo.GetDB(), func(tx *sqlx.Tx) error {
+		return s.verificationRepo.MarkUsedByUserTX(ctx, tx, user.ID)
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("Error dropping verification links of userID: %s, error: %v", user.ID, err)
+		return nil, err
+	}
+
+	link, err := s.verificationLink(ctx, user.ID, req.Email)
+	if err != nil {
+		zap.L().Sugar().Errorf("Error creating email change link for userID: %s, error: %v", user.ID, err)
+		return nil, err
+	}
+	if err = s.mailer.Send(ctx, emailChangeMessage(user, req.Email, link)); err != nil {
+		zap.L().Sugar().Errorf("Error sending email change link to userID: %s, error: %v", user.ID, err)
+		return nil, err
+	}
+	if err = s.mailer.Send(ctx, emailChangeNoticeMessage(user, req.Email)); err != nil {
+		zap.L().Sugar().Warnf("Failed to notify the current email of userID: %s, error: %v", user.ID, err)
+	}
+
+	zap.L().Sugar().Infof("Email change requested for userID: %s", user.ID)
+	return &ChangeEmailResponse{Message: "Confirmation link has been sent to " + req.Email}, nil
+}
+
+// DeleteMe closes the account. The user row is deleted and everything the user owns goes
+// with it through the foreign keys; every session is revoked first, so no token of the
+// account works any more, and the cached data of the user is purged after commit.
+func (s *Service) DeleteMe(ctx context.Context, req *DeleteMeRequest) (*DeleteMeResponse, error) {
+	user, err := s.getUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.verifyPassword(ctx, user, req.Password, req.IP); err != nil {
+		zap.L().Sugar().Warnf("Invalid password deleting the account of userID: %s", user.ID)
+		return nil, err
+	}
+
+	if err = s.requireSecondFactor(ctx, user, req.Code, req.IP); err != nil {
+		return nil, err
+	}
+
+	var keys, sessionIDs []string
+	err = s.transactionExecutor.WithTransaction(ctx, s.sessionRepo.GetDB(), func(tx *sqlx.Tx) error {
+		var err error
+		if sessionIDs, err = s.revokeSessionsTX(ctx, tx, user.ID, ""); err != nil {
+			return err
+		}
+		for _, cache := range s.userCaches {
+			userKeys, err := cache.CacheKeysByUserTX(ctx, tx, user.ID)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, userKeys...)
+		}
+		return s.authRepo.DeleteTX(ctx, tx, user.ID)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.UserNotFound
+		}
+		if errors.Is(err, auth.ErrUserInUse) {
+			return nil, errs.UserInUse
+		}
+		zap.L().Sugar().Errorf("Error deleting account of userID: %s, error: %v", user.ID, err)
+		return nil, err
+	}
+
+	s.invalidateSessions(ctx, sessionIDs...)
+	if err = s.authRepo.PurgeCache(ctx, keys); err != nil {
+		zap.L().Sugar().Warnf("Failed to purge cache after account deletion, userID: %s, error: %v", user.ID, err)
+	}
+	if err = s.authRepo.InvalidateCache(ctx, user.ID, user.Email); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate user cache after account deletion, userID: %s, error: %v", user.ID, err)
+	}
+
+	zap.L().Sugar().Infof("Account deleted, userID: %s", user.ID)
+	return &DeleteMeResponse{Message: "Account has been deleted"}, nil
+}
+
+func (s *Service) getUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.UserNotFound
+		}
+		zap.L().Sugar().Errorf("Error fetching user info for userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+	return user, nil
+}
+
+func emailChangeMessage(user *domain.User, email, link string) mailer.Message {
+	return mailer.Message{
+		To:      email,
+		Subject: "Confirm your new email for Finly",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Please confirm that you want to use this address for your Finly account by opening the link below within %d hours:\n\n%s\n\n"+
+			"If you didn't ask for this, you can ignore this email.\n",
+			user.FirstName, int(emailVerificationTTL.Hours()), link),
+	}
+}
+
+func emailChangeNoticeMessage(user *domain.User, email string) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Your Finly email is about to change",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Someone asked to move your Finly account to %s. The change takes effect once it is confirmed from that address.\n\n"+
+			"If this wasn't you, change your password right away.\n",
+			user.FirstName, email),
+	}
+}
+
+// requireSecondFactor checks code when the user has two-factor authentication enabled.
+func (s *Service) requireSecondFactor(ctx context.Context, user *domain.User, code, ip string) error {
+	credential, err := s.twoFactorRepo.GetCredential(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		zap.L().Sugar().Errorf("Error fetching two-factor credential for userID: %s, error: %v", user.ID, err)
+		return err
+	}
+	if credential == nil || !credential.ConfirmedAt.Valid {
+		return nil
+	}
+	if code == "" {
+		return errs.InvalidTwoFactorCode
+	}
+	return s.verifySecondFactor(ctx, user, credential, code, ip)
+}
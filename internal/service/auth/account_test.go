@@ -0,0 +1,408 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/auth"
+	"finly-backend/internal/repository/auth/mock"
+	mock_budget "finly-backend/internal/repository/budget/mock"
+	mock_email_verification "finly-backend/internal/repository/email_verification/mock"
+	mock_login_attempt "finly-backend/internal/repository/login_attempt/mock"
+	mock_refresh_token "finly-backend/internal/repository/refresh_token/mock"
+	mock_session "finly-backend/internal/repository/session/mock"
+	mock_transaction "finly-backend/internal/repository/transaction/mock"
+	mock_two_factor "finly-backend/internal/repository/two_factor/mock"
+	"finly-backend/pkg/mailer"
+	"finly-backend/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpdateMe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mock.NewMockAuth(ctrl)
+	service := NewService(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	user := &domain.User{ID: "user123", Email: "test@example.com", FirstName: "John", LastName: "Doe"}
+	name := "Jane"
+
+	tests := []struct {
+		name         string
+		req          *UpdateMeRequest
+		mockSetup    func()
+		expectedResp *UpdateMeResponse
+		expectedErr  error
+	}{
+		{
+			name: "First name changed",
+			req:  &UpdateMeRequest{UserID: "user123", FirstName: &name},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockAuthRepo.EXPECT().UpdateProfile(ctx, "user123", "Jane", "Doe").Return(nil)
+			},
+			expectedResp: &UpdateMeResponse{MeResponse: MeResponse{UserInfo: UserInfo{FirstName: "Jane", LastName: "Doe", Email: "test@example.com"}}},
+		},
+		{
+			name: "User not found",
+			req:  &UpdateMeRequest{UserID: "user123", LastName: &name},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.UserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.UpdateMe(ctx, tt.req)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mock.NewMockAuth(ctrl)
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	mockRefreshTokenRepo := mock_refresh_token.NewMockRefreshToken(ctrl)
+	mockLoginAttemptRepo := mock_login_attempt.NewMockLoginAttempt(ctrl)
+	service := NewService(mockAuthRepo, nil, mockRefreshTokenRepo, mockSessionRepo, nil, nil, nil, mockLoginAttemptRepo, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	hash, _ := security.HashPassword("password123")
+	user := &domain.User{ID: "user123", Email: "test@example.com", PasswordHash: hash}
+
+	tests := []struct {
+		name         string
+		req          *ChangePasswordRequest
+		mockSetup    func()
+		expectedResp *ChangePasswordResponse
+		expectedErr  error
+	}{
+		{
+			name: "Password changed, other sessions signed out",
+			req:  &ChangePasswordRequest{UserID: "user123", SessionID: "session1", CurrentPassword: "password123", NewPassword: "newpassword"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockSessionRepo.EXPECT().GetDB().Return(nil)
+				mockAuthRepo.EXPECT().UpdatePasswordTX(ctx, gomock.Any(), "user123", gomock.Cond(func(hash string) bool {
+					return security.CheckPasswordHash("newpassword", hash)
+				})).Return(nil)
+				mockSessionRepo.EXPECT().RevokeOthersTX(ctx, gomock.Any(), "user123", "session1").Return([]string{"session2"}, nil)
+				mockRefreshTokenRepo.EXPECT().RevokeFamilyTX(ctx, gomock.Any(), "session2").Return(nil)
+				mockSessionRepo.EXPECT().InvalidateCache(ctx, "session2").Return(nil)
+				mockAuthRepo.EXPECT().InvalidateCache(ctx, "user123", "test@example.com").Return(nil)
+			},
+			expectedResp: &ChangePasswordResponse{Message: "Password has been changed, other sessions have been signed out"},
+		},
+		{
+			name: "Wrong current password",
+			req:  &ChangePasswordRequest{UserID: "user123", SessionID: "session1", IP: "203.0.113.7", CurrentPassword: "wrong", NewPassword: "newpassword"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "ip", "203.0.113.7").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "ip", "203.0.113.7").Return(int64(1), nil)
+			},
+			expectedErr: errs.InvalidCredentials,
+		},
+		{
+			name: "Email locked out",
+			req:  &ChangePasswordRequest{UserID: "user123", SessionID: "session1", CurrentPassword: "password123", NewPassword: "newpassword"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Minute, nil)
+			},
+			expectedErr: tooManyAttempts(time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.ChangePassword(ctx, tt.req)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
+
+func TestChangeEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mock.NewMockAuth(ctrl)
+	mockVerificationRepo := mock_email_verification.NewMockEmailVerification(ctrl)
+	mockTwoFactorRepo := mock_two_factor.NewMockTwoFactor(ctrl)
+	mockLoginAttemptRepo := mock_login_attempt.NewMockLoginAttempt(ctrl)
+	outbox := mailer.NewMemoryMailer()
+	service := NewService(mockAuthRepo, nil, nil, nil, nil, mockVerificationRepo, mockTwoFactorRepo, mockLoginAttemptRepo, nil, outbox, "http://localhost:5173", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	hash, _ := security.HashPassword("password123")
+	user := &domain.User{ID: "user123", Email: "test@example.com", FirstName: "John", PasswordHash: hash}
+	secret, _ := security.GenerateTOTPSecret()
+	enabled := &domain.TOTPCredential{UserID: "user123", Secret: secret, ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+
+	t.Run("Link sent to the new email", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+		mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+		mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+		mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(nil, sql.ErrNoRows)
+		mockAuthRepo.EXPECT().GetUserByEmail(ctx, "new@example.com").Return(nil, sql.ErrNoRows)
+		mockVerificationRepo.EXPECT().GetDB().Return(nil)
+		mockVerificationRepo.EXPECT().MarkUsedByUserTX(ctx, gomock.Any(), "user123").Return(nil)
+		mockVerificationRepo.EXPECT().Create(ctx, gomock.Cond(func(token *domain.EmailVerificationToken) bool {
+			return token.UserID == "user123" && token.Email == "new@example.com" && token.ExpiresAt.After(time.Now())
+		})).Return("token1", nil)
+
+		resp, err := service.ChangeEmail(ctx, &ChangeEmailRequest{UserID: "user123", Password: "password123", Email: "new@example.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, &ChangeEmailResponse{Message: "Confirmation link has been sent to new@example.com"}, resp)
+
+		sent := outbox.Sent()
+		if assert.Len(t, sent, 2) {
+			assert.Equal(t, "new@example.com", sent[0].To)
+			assert.True(t, strings.Contains(sent[0].Body, "http://localhost:5173/verify-email?token="))
+			assert.Equal(t, "test@example.com", sent[1].To)
+			assert.False(t, strings.Contains(sent[1].Body, "token="))
+		}
+	})
+
+	tests := []struct {
+		name        string
+		req         *ChangeEmailRequest
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Wrong password",
+			req:  &ChangeEmailRequest{UserID: "user123", Password: "wrong", Email: "new@example.com"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+			},
+			expectedErr: errs.InvalidCredentials,
+		},
+		{
+			name: "Same email",
+			req:  &ChangeEmailRequest{UserID: "user123", Password: "password123", Email: "test@example.com"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.SameEmail,
+		},
+		{
+			name: "Same email in another case",
+			req:  &ChangeEmailRequest{UserID: "user123", Password: "password123", Email: "Test@Example.com"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.SameEmail,
+		},
+		{
+			name: "Two-factor code missing",
+			req:  &ChangeEmailRequest{UserID: "user123", Password: "password123", Email: "new@example.com"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabled, nil)
+			},
+			expectedErr: errs.InvalidTwoFactorCode,
+		},
+		{
+			name: "Wrong two-factor code",
+			req:  &ChangeEmailRequest{UserID: "user123", Password: "password123", Email: "new@example.com", Code: "wrong"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabled, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockTwoFactorRepo.EXPECT().UseRecoveryCode(ctx, "user123", gomock.Any()).Return(false, nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+			},
+			expectedErr: errs.InvalidTwoFactorCode,
+		},
+		{
+			name: "Email in use",
+			req:  &ChangeEmailRequest{UserID: "user123", Password: "password123", Email: "taken@example.com"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(nil, sql.ErrNoRows)
+				mockAuthRepo.EXPECT().GetUserByEmail(ctx, "taken@example.com").Return(&domain.User{ID: "user456"}, nil)
+			},
+			expectedErr: errs.EmailInUse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.ChangeEmail(ctx, tt.req)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Nil(t, resp)
+		})
+	}
+}
+
+func TestDeleteMe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mock.NewMockAuth(ctrl)
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	mockRefreshTokenRepo := mock_refresh_token.NewMockRefreshToken(ctrl)
+	mockTwoFactorRepo := mock_two_factor.NewMockTwoFactor(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	mockLoginAttemptRepo := mock_login_attempt.NewMockLoginAttempt(ctrl)
+	userCaches := []UserCache{mockBudgetRepo, mockTransactionRepo}
+	service := NewService(mockAuthRepo, mockBudgetRepo, mockRefreshTokenRepo, mockSessionRepo, nil, nil, mockTwoFactorRepo, mockLoginAttemptRepo, userCaches, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	hash, _ := security.HashPassword("password123")
+	user := &domain.User{ID: "user123", Email: "test@example.com", PasswordHash: hash}
+	secret, _ := security.GenerateTOTPSecret()
+	enabled := &domain.TOTPCredential{UserID: "user123", Secret: secret, ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+
+	tests := []struct {
+		name         string
+		req          *DeleteMeRequest
+		mockSetup    func()
+		expectedResp *DeleteMeResponse
+		expectedErr  error
+	}{
+		{
+			name: "Account deleted and cache purged",
+			req:  &DeleteMeRequest{UserID: "user123", Password: "password123"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(nil, sql.ErrNoRows)
+				mockSessionRepo.EXPECT().GetDB().Return(nil)
+				mockSessionRepo.EXPECT().RevokeAllTX(ctx, gomock.Any(), "user123").Return([]string{"session1"}, nil)
+				mockRefreshTokenRepo.EXPECT().RevokeFamilyTX(ctx, gomock.Any(), "session1").Return(nil)
+				mockBudgetRepo.EXPECT().CacheKeysByUserTX(ctx, gomock.Any(), "user123").Return([]string{"budgets:user:user123"}, nil)
+				mockTransactionRepo.EXPECT().CacheKeysByUserTX(ctx, gomock.Any(), "user123").Return([]string{"transaction:version:user:user123"}, nil)
+				mockAuthRepo.EXPECT().DeleteTX(ctx, gomock.Any(), "user123").Return(nil)
+				mockSessionRepo.EXPECT().InvalidateCache(ctx, "session1").Return(nil)
+				mockAuthRepo.EXPECT().PurgeCache(ctx, []string{"budgets:user:user123", "transaction:version:user:user123"}).Return(nil)
+				mockAuthRepo.EXPECT().InvalidateCache(ctx, "user123", "test@example.com").Return(nil)
+			},
+			expectedResp: &DeleteMeResponse{Message: "Account has been deleted"},
+		},
+		{
+			name: "Wrong password",
+			req:  &DeleteMeRequest{UserID: "user123", Password: "wrong"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+			},
+			expectedErr: errs.InvalidCredentials,
+		},
+		{
+			name: "Two-factor code missing",
+			req:  &DeleteMeRequest{UserID: "user123", Password: "password123"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabled, nil)
+			},
+			expectedErr: errs.InvalidTwoFactorCode,
+		},
+		{
+			name: "Wrong two-factor code",
+			req:  &DeleteMeRequest{UserID: "user123", Password: "password123", Code: "wrong"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabled, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockTwoFactorRepo.EXPECT().UseRecoveryCode(ctx, "user123", gomock.Any()).Return(false, nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+			},
+			expectedErr: errs.InvalidTwoFactorCode,
+		},
+		{
+			name: "Nothing is purged when the deletion fails",
+			req:  &DeleteMeRequest{UserID: "user123", Password: "password123"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(nil, sql.ErrNoRows)
+				mockSessionRepo.EXPECT().GetDB().Return(nil)
+				mockSessionRepo.EXPECT().RevokeAllTX(ctx, gomock.Any(), "user123").Return(nil, nil)
+				mockBudgetRepo.EXPECT().CacheKeysByUserTX(ctx, gomock.Any(), "user123").Return(nil, errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+		{
+			name: "Categories used in budgets of other users",
+			req:  &DeleteMeRequest{UserID: "user123", Password: "password123"},
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(nil, sql.ErrNoRows)
+				mockSessionRepo.EXPECT().GetDB().Return(nil)
+				mockSessionRepo.EXPECT().RevokeAllTX(ctx, gomock.Any(), "user123").Return(nil, nil)
+				mockBudgetRepo.EXPECT().CacheKeysByUserTX(ctx, gomock.Any(), "user123").Return(nil, nil)
+				mockTransactionRepo.EXPECT().CacheKeysByUserTX(ctx, gomock.Any(), "user123").Return(nil, nil)
+				mockAuthRepo.EXPECT().DeleteTX(ctx, gomock.Any(), "user123").Return(auth.ErrUserInUse)
+			},
+			expectedErr: errs.UserInUse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.DeleteMe(ctx, tt.req)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/auth"
+	"finly-backend/pkg/mailer"
+	"finly-backend/pkg/security"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+	"time"
+)
+
+// emailVerificationTTL is how long a verification link can be used.
+const emailVerificationTTL = 48 * time.Hour
+
+// VerifyEmail confirms the address a verification token was sent to. A token sent by
+// ChangeEmail moves the account to that address; ChangeEmail uses up the earlier tokens,
+// so no older link can move it anywhere else.
+func (s *Service) VerifyEmail(ctx context.Context, req *VerifyEmailRequest) (*VerifyEmailResponse, error) {
+	var (
+		stored        *domain.EmailVerificationToken
+		previousEmail string
+	)
+	err := s.transactionExecutor.WithTransaction(ctx, s.verificationRepo.GetDB(), func(tx *sqlx.Tx) error {
+		var err error
+		if stored, err = s.verificationRepo.GetByHashTX(ctx, tx, security.HashToken(req.Token)); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.InvalidVerifyToken
+			}
+			return err
+		}
+		if stored.UsedAt.Valid || !time.Now().Before(stored.ExpiresAt) {
+			return errs.InvalidVerifyToken
+		}
+
+		verified, err := s.authRepo.MarkEmailVerifiedTX(ctx, tx, stored.UserID, stored.Email)
+		if err != nil {
+			return err
+		}
+		if !verified {
+			if previousEmail, err = s.authRepo.ChangeEmailTX(ctx, tx, stored.UserID, stored.Email); err != nil {
+				switch {
+				case errors.Is(err, sql.ErrNoRows):
+					return errs.InvalidVerifyToken
+				case errors.Is(err, auth.ErrEmailTaken):
+					return errs.EmailInUse
+				}
+				return err
+			}
+		}
+		return s.verificationRepo.MarkUsedByUserTX(ctx, tx, stored.UserID)
+	})
+	if err != nil {
+		if errors.Is(err, errs.InvalidVerifyToken) {
+			zap.L().Sugar().Warnf("Email verification with an invalid, used or outdated token")
+			return nil, errs.InvalidVerifyToken
+		}
+		if errors.Is(err, errs.EmailInUse) {
+			zap.L().Sugar().Warnf("Email change of userID: %s to an address taken meanwhile", stored.UserID)
+			return nil, errs.EmailInUse
+		}
+		zap.L().Sugar().Errorf("Error verifying email, error: %v", err)
+		return nil, err
+	}
+
+	if err = s.authRepo.InvalidateCache(ctx, stored.UserID, stored.Email); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate user cache after verification, userID: %s, error: %v", stored.UserID, err)
+	}
+
+	if previousEmail != "" {
+		if err = s.authRepo.InvalidateCache(ctx, "", previousEmail); err != nil {
+			zap.L().Sugar().Warnf("Failed to invalidate user cache after email change, userID: %s, error: %v", stored.UserID, err)
+		}
+		zap.L().Sugar().Infof("Email changed for userID: %s", stored.UserID)
+		return &VerifyEmailResponse{Message: "Email has been changed"}, nil
+	}
+
+	zap.L().Sugar().Infof("Email verified for userID: %s", stored.UserID)
+	return &VerifyEmailResponse{Message: "Email has been verified"}, nil
+}
+
+// ResendVerification mails a new verification link. Links sent earlier keep working
+// until they expire.
+func (s *Service) ResendVerification(ctx context.Context, req *ResendVerificationRequest) (*ResendVerificationResponse, error) {
+	user, err := s.authRepo.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.UserNotFound
+		}
+		zap.L().Sugar().Errorf("Error fetching user info for userID: %s, error: %v", req.UserID, err)
+		return nil, err
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		return nil, errs.AlreadyVerified
+	}
+
+	if err = s.sendVerification(ctx, user); err != nil {
+		zap.L().Sugar().Errorf("Error sending verification mail to userID: %s, error: %v", user.ID, err)
+		return nil, err
+	}
+
+	return &ResendVerificationResponse{Message: "Verification link has been sent to " + user.Email}, nil
+}
+
+// IsEmailVerified reports whether the user has verified their current email.
+func (s *Service) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	user, err := s.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, errs.UserNotFound
+		}
+		zap.L().Sugar().Errorf("Error fetching user info for userID: %s, error: %v", userID, err)
+		return false, err
+	}
+	return user.EmailVerifiedAt.Valid, nil
+}
+
+// sendVerification stores a verification token for the current email of the user and
+// mails its link.
+func (s *Service) sendVerification(ctx context.Context, user *domain.User) error {
+	link, err := s.verificationLink(ctx, user.ID, user.Email)
+	if err != nil {
+		return err
+	}
+
+	if err = s.mailer.Send(ctx, verificationMessage(user, link)); err != nil {
+		return err
+	}
+
+	zap.L().Sugar().Infof("Verification link sent to userID: %s", user.ID)
+	return nil
+}
+
+// verificationLink stores a token that verifies email for the user and returns its link.
+func (s *Service) verificationLink(ctx context.Context, userID, email string) (string, error) {
+	token, err := security.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = s.verificationRepo.Create(ctx, &domain.EmailVerificationToken{
+		UserID:    userID,
+		Email:     email,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: time.Now().UTC().Add(emailVerificationTTL),
+	}); err != nil {
+		return "", err
+	}
+
+	return s.link("/verify-email", token), nil
+}
+
+func verificationMessage(user *domain.User, link string) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email for Finly",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Please confirm that this is your email address by opening the link below within %d hours:\n\n%s\n\n"+
+			"If you didn't create a Finly account, you can ignore this email.\n",
+			user.FirstName, int(emailVerificationTTL.Hours()), link),
+	}
+}
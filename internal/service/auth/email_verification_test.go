@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/auth"
+	"finly-backend/internal/repository/auth/mock"
+	mock_email_verification "finly-backend/internal/repository/email_verification/mock"
+	"finly-backend/pkg/mailer"
+	"finly-backend/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestVerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mock.NewMockAuth(ctrl)
+	mockVerificationRepo := mock_email_verification.NewMockEmailVerification(ctrl)
+	service := NewService(mockAuthRepo, nil, nil, nil, nil, mockVerificationRepo, nil, nil, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	hash := security.HashToken("verify")
+	valid := &domain.EmailVerificationToken{ID: "token1", UserID: "user123", Email: "test@example.com", TokenHash: hash, ExpiresAt: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name         string
+		mockSetup    func()
+		expectedResp *VerifyEmailResponse
+		expectedErr  error
+	}{
+		{
+			name: "Email verified",
+			mockSetup: func() {
+				mockVerificationRepo.EXPECT().GetDB().Return(nil)
+				mockVerificationRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(valid, nil)
+				mockAuthRepo.EXPECT().MarkEmailVerifiedTX(ctx, gomock.Any(), "user123", "test@example.com").Return(true, nil)
+				mockVerificationRepo.EXPECT().MarkUsedByUserTX(ctx, gomock.Any(), "user123").Return(nil)
+				mockAuthRepo.EXPECT().InvalidateCache(ctx, "user123", "test@example.com").Return(nil)
+			},
+			expectedResp: &VerifyEmailResponse{Message: "Email has been verified"},
+		},
+		{
+			name: "Email change confirmed",
+			mockSetup: func() {
+				mockVerificationRepo.EXPECT().GetDB().Return(nil)
+				mockVerificationRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(valid, nil)
+				mockAuthRepo.EXPECT().MarkEmailVerifiedTX(ctx, gomock.Any(), "user123", "test@example.com").Return(false, nil)
+				mockAuthRepo.EXPECT().ChangeEmailTX(ctx, gomock.Any(), "user123", "test@example.com").Return("old@example.com", nil)
+				mockVerificationRepo.EXPECT().MarkUsedByUserTX(ctx, gomock.Any(), "user123").Return(nil)
+				mockAuthRepo.EXPECT().InvalidateCache(ctx, "user123", "test@example.com").Return(nil)
+				mockAuthRepo.EXPECT().InvalidateCache(ctx, "", "old@example.com").Return(nil)
+			},
+			expectedResp: &VerifyEmailResponse{Message: "Email has been changed"},
+		},
+		{
+			name: "New email taken since the link was sent",
+			mockSetup: func() {
+				mockVerificationRepo.EXPECT().GetDB().Return(nil)
+				mockVerificationRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(valid, nil)
+				mockAuthRepo.EXPECT().MarkEmailVerifiedTX(ctx, gomock.Any(), "user123", "test@example.com").Return(false, nil)
+				mockAuthRepo.EXPECT().ChangeEmailTX(ctx, gomock.Any(), "user123", "test@example.com").Return("", auth.ErrEmailTaken)
+			},
+			expectedErr: errs.EmailInUse,
+		},
+		{
+			name: "Expired token",
+			mockSetup: func() {
+				expired := *valid
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				mockVerificationRepo.EXPECT().GetDB().Return(nil)
+				mockVerificationRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(&expired, nil)
+			},
+			expectedErr: errs.InvalidVerifyToken,
+		},
+		{
+			name: "Unknown token",
+			mockSetup: func() {
+				mockVerificationRepo.EXPECT().GetDB().Return(nil)
+				mockVerificationRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.InvalidVerifyToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.VerifyEmail(ctx, &VerifyEmailRequest{Token: "verify"})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
+
+func TestResendVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mock.NewMockAuth(ctrl)
+	mockVerificationRepo := mock_email_verification.NewMockEmailVerification(ctrl)
+	outbox := mailer.NewMemoryMailer()
+	service := NewService(mockAuthRepo, nil, nil, nil, nil, mockVerificationRepo, nil, nil, nil, outbox, "http://localhost:5173", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	t.Run("Link sent", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(&domain.User{ID: "user123", Email: "test@example.com"}, nil)
+		mockVerificationRepo.EXPECT().Create(ctx, gomock.Any()).Return("token1", nil)
+
+		resp, err := service.ResendVerification(ctx, &ResendVerificationRequest{UserID: "user123"})
+		assert.NoError(t, err)
+		assert.Equal(t, &ResendVerificationResponse{Message: "Verification link has been sent to test@example.com"}, resp)
+		assert.Len(t, outbox.Sent(), 1)
+	})
+
+	t.Run("Already verified", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(&domain.User{
+			ID:              "user123",
+			EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil)
+
+		resp, err := service.ResendVerification(ctx, &ResendVerificationRequest{UserID: "user123"})
+		assert.Equal(t, errs.AlreadyVerified, err)
+		assert.Nil(t, resp)
+	})
+}
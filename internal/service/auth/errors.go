@@ -3,20 +3,57 @@ package auth
 import (
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"time"
 )
 
 var errs = struct {
-	UserAlreadyExists  *echo.HTTPError
-	InvalidCredentials *echo.HTTPError
-	TokenBlacklisted   *echo.HTTPError
-	InvalidToken       *echo.HTTPError
-	UserNotFound       *echo.HTTPError
-	TokenExpired       *echo.HTTPError
+	UserAlreadyExists    *echo.HTTPError
+	InvalidCredentials   *echo.HTTPError
+	InvalidToken         *echo.HTTPError
+	UserNotFound         *echo.HTTPError
+	TokenExpired         *echo.HTTPError
+	InvalidRefresh       *echo.HTTPError
+	RefreshReused        *echo.HTTPError
+	InvalidResetToken    *echo.HTTPError
+	InvalidVerifyToken   *echo.HTTPError
+	AlreadyVerified      *echo.HTTPError
+	TwoFactorEnabled     *echo.HTTPError
+	TwoFactorNotEnabled  *echo.HTTPError
+	InvalidTwoFactorCode *echo.HTTPError
+	InvalidChallenge     *echo.HTTPError
+	EmailInUse           *echo.HTTPError
+	SameEmail            *echo.HTTPError
+	UserInUse            *echo.HTTPError
 }{
-	UserAlreadyExists:  echo.NewHTTPError(http.StatusConflict, "User already exists"),
-	InvalidCredentials: echo.NewHTTPError(http.StatusUnauthorized, "Invalid credentials"),
-	TokenBlacklisted:   echo.NewHTTPError(http.StatusForbidden, "Token is blacklisted"),
-	InvalidToken:       echo.NewHTTPError(http.StatusUnauthorized, "Invalid token"),
-	UserNotFound:       echo.NewHTTPError(http.StatusNotFound, "User not found"),
-	TokenExpired:       echo.NewHTTPError(http.StatusUnauthorized, "Token expired"),
+	UserAlreadyExists:    echo.NewHTTPError(http.StatusConflict, "User already exists"),
+	InvalidCredentials:   echo.NewHTTPError(http.StatusUnauthorized, "Invalid credentials"),
+	InvalidToken:         echo.NewHTTPError(http.StatusUnauthorized, "Invalid token"),
+	UserNotFound:         echo.NewHTTPError(http.StatusNotFound, "User not found"),
+	TokenExpired:         echo.NewHTTPError(http.StatusUnauthorized, "Token expired"),
+	InvalidRefresh:       echo.NewHTTPError(http.StatusUnauthorized, "Invalid refresh token"),
+	RefreshReused:        echo.NewHTTPError(http.StatusUnauthorized, "Refresh token was already used, the session has been revoked"),
+	InvalidResetToken:    echo.NewHTTPError(http.StatusBadRequest, "Password reset link is invalid or has expired"),
+	InvalidVerifyToken:   echo.NewHTTPError(http.StatusBadRequest, "Verification link is invalid or has expired"),
+	AlreadyVerified:      echo.NewHTTPError(http.StatusConflict, "Email is already verified"),
+	TwoFactorEnabled:     echo.NewHTTPError(http.StatusConflict, "Two-factor authentication is already enabled"),
+	TwoFactorNotEnabled:  echo.NewHTTPError(http.StatusConflict, "Two-factor authentication is not enabled"),
+	InvalidTwoFactorCode: echo.NewHTTPError(http.StatusUnauthorized, "Invalid two-factor code"),
+	InvalidChallenge:     echo.NewHTTPError(http.StatusUnauthorized, "Sign-in challenge is invalid or has expired"),
+	EmailInUse:           echo.NewHTTPError(http.StatusConflict, "Email is already in use"),
+	SameEmail:            echo.NewHTTPError(http.StatusBadRequest, "New email is the same as the current one"),
+	UserInUse:            echo.NewHTTPError(http.StatusConflict, "Your custom categories are still used in budgets of other users"),
+}
+
+// ThrottledError rejects a sign-in while its email or IP is locked out. The handler sends
+// RetryAfter in the Retry-After header.
+type ThrottledError struct {
+	*echo.HTTPError
+	RetryAfter time.Duration
+}
+
+func tooManyAttempts(retryAfter time.Duration) *ThrottledError {
+	return &ThrottledError{
+		HTTPError:  echo.NewHTTPError(http.StatusTooManyRequests, "Too many sign-in attempts, try again later"),
+		RetryAfter: retryAfter,
+	}
 }
@@ -1,27 +1,51 @@
 package auth
 
+import "finly-backend/pkg/security"
+
 type UserInfo struct {
 	FirstName string `json:"first_name" validate:"required,min=1,max=100"`
 	LastName  string `json:"last_name" validate:"required,min=1,max=100"`
 	Email     string `json:"email" validate:"required,email"`
 }
 
+// ClientInfo describes the device a session is started or used from. It is taken from
+// the request, not from the body.
+type ClientInfo struct {
+	UserAgent string `header:"User-Agent" json:"-" swaggerignore:"true"`
+	IP        string `json:"-" swaggerignore:"true"`
+}
+
 type RegisterRequest struct {
 	UserInfo
+	ClientInfo
 	Password string `json:"password" validate:"required,min=8,max=100"`
 }
 
+// TokenPair is issued on sign-in and on every refresh. Token is a short-lived access token
+// for the Authorization header; RefreshToken is single-use and obtains the next pair.
+type TokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
 type RegisterResponse struct {
-	Token string `json:"token"`
+	TokenPair
 }
 
 type LoginRequest struct {
+	ClientInfo
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=5,max=100"`
 }
 
+// LoginResponse carries the token pair, unless the user has two-factor authentication on:
+// then it carries a challenge token, which VerifyTwoFactor exchanges for the pair.
 type LoginResponse struct {
-	Token string `json:"token"`
+	*TokenPair
+	TwoFactorRequired  bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken     string `json:"challenge_token,omitempty"`
+	ChallengeExpiresIn int    `json:"challenge_expires_in,omitempty"`
 }
 
 type LogoutRequest struct {
@@ -33,11 +57,12 @@ type LogoutResponse struct {
 }
 
 type RefreshTokenRequest struct {
-	AuthToken string `header:"Authorization" validate:"required"`
+	ClientInfo
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
 type RefreshTokenResponse struct {
-	Token string `json:"token"`
+	TokenPair
 }
 
 type MeRequest struct {
@@ -46,4 +71,149 @@ type MeRequest struct {
 
 type MeResponse struct {
 	UserInfo
+	EmailVerified bool `json:"email_verified"`
+}
+
+// KeysResponse is a JSON Web Key Set as described in RFC 7517.
+type KeysResponse struct {
+	security.JWKS
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ForgotPasswordResponse struct {
+	Message string `json:"message"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=100"`
+}
+
+type ResetPasswordResponse struct {
+	Message string `json:"message"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `query:"token" validate:"required"`
+}
+
+type VerifyEmailResponse struct {
+	Message string `json:"message"`
+}
+
+type ResendVerificationRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+}
+
+type ResendVerificationResponse struct {
+	Message string `json:"message"`
+}
+
+type EnrollTwoFactorRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+}
+
+// EnrollTwoFactorResponse holds the secret for an authenticator app, both plain for manual
+// entry and as an otpauth URI to show as a QR code.
+type EnrollTwoFactorResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type ConfirmTwoFactorRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	Code   string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// ConfirmTwoFactorResponse lists the recovery codes. They are shown only this once.
+type ConfirmTwoFactorResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// VerifyTwoFactorRequest answers a sign-in challenge with a code of the authenticator app
+// or with a recovery code.
+type VerifyTwoFactorRequest struct {
+	ClientInfo
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required,max=32"`
+}
+
+type VerifyTwoFactorResponse struct {
+	TokenPair
+}
+
+type DisableTwoFactorRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	IP       string `json:"-" swaggerignore:"true"`
+	Password string `json:"password" validate:"required,max=100"`
+	Code     string `json:"code" validate:"required,max=32"`
+}
+
+type DisableTwoFactorResponse struct {
+	Message string `json:"message"`
+}
+
+type RegenerateRecoveryCodesRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	IP     string `json:"-" swaggerignore:"true"`
+	Code   string `json:"code" validate:"required,max=32"`
+}
+
+// RegenerateRecoveryCodesResponse lists the new recovery codes; the old ones stop working.
+type RegenerateRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// UpdateMeRequest changes the name of the user. Fields left out keep their value.
+type UpdateMeRequest struct {
+	UserID    string  `header:"User-Id" validate:"required"`
+	FirstName *string `json:"first_name" validate:"omitempty,min=1,max=50"`
+	LastName  *string `json:"last_name" validate:"omitempty,min=1,max=50"`
+}
+
+type UpdateMeResponse struct {
+	MeResponse
+}
+
+type ChangePasswordRequest struct {
+	UserID          string `header:"User-Id" validate:"required"`
+	SessionID       string `header:"Session-Id"`
+	IP              string `json:"-" swaggerignore:"true"`
+	CurrentPassword string `json:"current_password" validate:"required,max=100"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=100"`
+}
+
+type ChangePasswordResponse struct {
+	Message string `json:"message"`
+}
+
+// ChangeEmailRequest asks to move the account to a new email. The change takes effect
+// once the link mailed to the new address is opened. With two-factor authentication
+// enabled, Code must hold a code of the authenticator app or a recovery code.
+type ChangeEmailRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	IP       string `json:"-" swaggerignore:"true"`
+	Password string `json:"password" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Code     string `json:"code" validate:"max=32"`
+}
+
+type ChangeEmailResponse struct {
+	Message string `json:"message"`
+}
+
+// DeleteMeRequest closes the account. With two-factor authentication enabled, Code must
+// hold a code of the authenticator app or a recovery code.
+type DeleteMeRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	IP       string `json:"-" swaggerignore:"true"`
+	Password string `json:"password" validate:"required,max=100"`
+	Code     string `json:"code" validate:"max=32"`
+}
+
+type DeleteMeResponse struct {
+	Message string `json:"message"`
 }
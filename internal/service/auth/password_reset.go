@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/mailer"
+	"finly-backend/pkg/security"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+	"net/url"
+	"time"
+)
+
+// passwordResetTTL is how long a reset link can be used.
+const passwordResetTTL = time.Hour
+
+const forgotPasswordMessage = "If an account with this email exists, we have sent a link to reset its password"
+
+// ForgotPassword mails a password reset link. The response is the same whether or not
+// the email belongs to an account, so it can't be used to find out who is registered.
+func (s *Service) ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) (*ForgotPasswordResponse, error) {
+	res := &ForgotPasswordResponse{Message: forgotPasswordMessage}
+
+	user, err := s.authRepo.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			zap.L().Sugar().Infof("Password reset requested for unknown email: %s", req.Email)
+			return res, nil
+		}
+		zap.L().Sugar().Errorf("Error getting user by email: %s, error: %v", req.Email, err)
+		return nil, err
+	}
+
+	token, err := security.GenerateOpaqueToken()
+	if err != nil {
+		zap.L().Sugar().Errorf("Error generating password reset token for userID: %s, error: %v", user.ID, err)
+		return nil, err
+	}
+
+	if _, err = s.passwordResetRepo.Create(ctx, &domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: time.Now().UTC().Add(passwordResetTTL),
+	}); err != nil {
+		zap.L().Sugar().Errorf("Error storing password reset token for userID: %s, error: %v", user.ID, err)
+		return nil, err
+	}
+
+	if err = s.mailer.Send(ctx, passwordResetMessage(user, s.link("/reset-password", token))); err != nil {
+		zap.L().Sugar().Errorf("Error sending password reset mail to userID: %s, error: %v", user.ID, err)
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Password reset link sent to userID: %s", user.ID)
+	return res, nil
+}
+
+// ResetPassword sets a new password with a reset token. All reset tokens of the user are
+// used up and every session is signed out, so whoever knew the old password loses access.
+func (s *Service) ResetPassword(ctx context.Context, req *ResetPasswordRequest) (*ResetPasswordResponse, error) {
+	hashedPassword, err := security.HashPassword(req.Password)
+	if err != nil {
+		zap.L().Sugar().Errorf("Error hashing password, error: %v", err)
+		return nil, err
+	}
+
+	var (
+		user       *domain.User
+		sessionIDs []string
+	)
+	err = s.transactionExecutor.WithTransaction(ctx, s.passwordResetRepo.GetDB(), func(tx *sqlx.Tx) error {
+		stored, err := s.passwordResetRepo.GetByHashTX(ctx, tx, security.HashToken(req.Token))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.InvalidResetToken
+			}
+			return err
+		}
+		if stored.UsedAt.Valid || !time.Now().Before(stored.ExpiresAt) {
+			return errs.InvalidResetToken
+		}
+
+		if user, err = s.authRepo.GetUserByID(ctx, stored.UserID); err != nil {
+			return err
+		}
+		if err = s.authRepo.UpdatePasswordTX(ctx, tx, user.ID, hashedPassword); err != nil {
+			return err
+		}
+		if err = s.passwordResetRepo.MarkUsedByUserTX(ctx, tx, user.ID); err != nil {
+			return err
+		}
+		sessionIDs, err = s.revokeSessionsTX(ctx, tx, user.ID, "")
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errs.InvalidResetToken) {
+			zap.L().Sugar().Warnf("Password reset with an invalid or used token")
+			return nil, errs.InvalidResetToken
+		}
+		zap.L().Sugar().Errorf("Error resetting password, error: %v", err)
+		return nil, err
+	}
+
+	s.invalidateSessions(ctx, sessionIDs...)
+	if err = s.authRepo.InvalidateCache(ctx, user.ID, user.Email); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate user cache after password reset, userID: %s, error: %v", user.ID, err)
+	}
+
+	zap.L().Sugar().Infof("Password reset for userID: %s", user.ID)
+	return &ResetPasswordResponse{Message: "Password has been reset, please log in again"}, nil
+}
+
+// link builds a link to a page of the web app that carries a token.
+func (s *Service) link(path, token string) string {
+	return s.appURL + path + "?token=" + url.QueryEscape(token)
+}
+
+func passwordResetMessage(user *domain.User, link string) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your Finly password",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"We received a request to reset the password of your Finly account. "+
+			"Open the link below within %d minutes to choose a new one:\n\n%s\n\n"+
+			"If you didn't ask for this, you can ignore this email and your password stays the same.\n",
+			user.FirstName, int(passwordResetTTL.Minutes()), link),
+	}
+}
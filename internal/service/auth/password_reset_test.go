@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/auth/mock"
+	mock_password_reset "finly-backend/internal/repository/password_reset/mock"
+	mock_refresh_token "finly-backend/internal/repository/refresh_token/mock"
+	mock_session "finly-backend/internal/repository/session/mock"
+	"finly-backend/pkg/mailer"
+	mock_mailer "finly-backend/pkg/mailer/mock"
+	"finly-backend/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForgotPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mock.NewMockAuth(ctrl)
+	mockPasswordResetRepo := mock_password_reset.NewMockPasswordReset(ctrl)
+	mockMailer := mock_mailer.NewMockMailer(ctrl)
+	service := NewService(mockAuthRepo, nil, nil, nil, mockPasswordResetRepo, nil, nil, nil, nil, mockMailer, "http://localhost:5173/", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	user := &domain.User{ID: "user123", Email: "test@example.com", FirstName: "John"}
+
+	tests := []struct {
+		name         string
+		mockSetup    func()
+		expectedResp *ForgotPasswordResponse
+		expectedErr  error
+	}{
+		{
+			name: "Link sent",
+			mockSetup: func() {
+				var hash string
+				mockAuthRepo.EXPECT().GetUserByEmail(ctx, "test@example.com").Return(user, nil)
+				mockPasswordResetRepo.EXPECT().Create(ctx, gomock.Cond(func(token *domain.PasswordResetToken) bool {
+					hash = token.TokenHash
+					return token.UserID == "user123" && token.ExpiresAt.After(time.Now().Add(59*time.Minute))
+				})).Return("token1", nil)
+				mockMailer.EXPECT().Send(ctx, gomock.Cond(func(msg mailer.Message) bool {
+					_, link, _ := strings.Cut(msg.Body, "http://localhost:5173/reset-password?token=")
+					token, _ := url.QueryUnescape(strings.Fields(link)[0])
+					return msg.To == "test@example.com" && security.HashToken(token) == hash
+				})).Return(nil)
+			},
+			expectedResp: &ForgotPasswordResponse{Message: forgotPasswordMessage},
+		},
+		{
+			name: "Unknown email gets the same response",
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByEmail(ctx, "test@example.com").Return(nil, sql.ErrNoRows)
+			},
+			expectedResp: &ForgotPasswordResponse{Message: forgotPasswordMessage},
+		},
+		{
+			name: "Mail error",
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByEmail(ctx, "test@example.com").Return(user, nil)
+				mockPasswordResetRepo.EXPECT().Create(ctx, gomock.Any()).Return("token1", nil)
+				mockMailer.EXPECT().Send(ctx, gomock.Any()).Return(errors.New("mail error"))
+			},
+			expectedErr: errors.New("mail error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.ForgotPassword(ctx, &ForgotPasswordRequest{Email: "test@example.com"})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mock.NewMockAuth(ctrl)
+	mockRefreshTokenRepo := mock_refresh_token.NewMockRefreshToken(ctrl)
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	mockPasswordResetRepo := mock_password_reset.NewMockPasswordReset(ctrl)
+	service := NewService(mockAuthRepo, nil, mockRefreshTokenRepo, mockSessionRepo, mockPasswordResetRepo, nil, nil, nil, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	hash := security.HashToken("reset")
+	user := &domain.User{ID: "user123", Email: "test@example.com"}
+	valid := &domain.PasswordResetToken{ID: "token1", UserID: "user123", TokenHash: hash, ExpiresAt: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name         string
+		mockSetup    func()
+		expectedResp *ResetPasswordResponse
+		expectedErr  error
+	}{
+		{
+			name: "Password reset and sessions revoked",
+			mockSetup: func() {
+				mockPasswordResetRepo.EXPECT().GetDB().Return(nil)
+				mockPasswordResetRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(valid, nil)
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockAuthRepo.EXPECT().UpdatePasswordTX(ctx, gomock.Any(), "user123", gomock.Cond(func(passwordHash string) bool {
+					return security.CheckPasswordHash("newpassword", passwordHash)
+				})).Return(nil)
+				mockPasswordResetRepo.EXPECT().MarkUsedByUserTX(ctx, gomock.Any(), "user123").Return(nil)
+				mockSessionRepo.EXPECT().RevokeAllTX(ctx, gomock.Any(), "user123").Return([]string{"session1", "session2"}, nil)
+				mockRefreshTokenRepo.EXPECT().RevokeFamilyTX(ctx, gomock.Any(), "session1").Return(nil)
+				mockRefreshTokenRepo.EXPECT().RevokeFamilyTX(ctx, gomock.Any(), "session2").Return(nil)
+				mockSessionRepo.EXPECT().InvalidateCache(ctx, "session1", "session2").Return(nil)
+				mockAuthRepo.EXPECT().InvalidateCache(ctx, "user123", "test@example.com").Return(nil)
+			},
+			expectedResp: &ResetPasswordResponse{Message: "Password has been reset, please log in again"},
+		},
+		{
+			name: "Unknown token",
+			mockSetup: func() {
+				mockPasswordResetRepo.EXPECT().GetDB().Return(nil)
+				mockPasswordResetRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.InvalidResetToken,
+		},
+		{
+			name: "Used token",
+			mockSetup: func() {
+				used := *valid
+				used.UsedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				mockPasswordResetRepo.EXPECT().GetDB().Return(nil)
+				mockPasswordResetRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(&used, nil)
+			},
+			expectedErr: errs.InvalidResetToken,
+		},
+		{
+			name: "Expired token",
+			mockSetup: func() {
+				expired := *valid
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				mockPasswordResetRepo.EXPECT().GetDB().Return(nil)
+				mockPasswordResetRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(&expired, nil)
+			},
+			expectedErr: errs.InvalidResetToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.ResetPassword(ctx, &ResetPasswordRequest{Token: "reset", Password: "newpassword"})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
@@ -4,11 +4,23 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"finly-backend/internal/domain"
 	"finly-backend/internal/repository/auth"
 	"finly-backend/internal/repository/budget"
+	"finly-backend/internal/repository/email_verification"
+	"finly-backend/internal/repository/login_attempt"
+	"finly-backend/internal/repository/password_reset"
+	"finly-backend/internal/repository/refresh_token"
+	"finly-backend/internal/repository/session"
+	"finly-backend/internal/repository/two_factor"
+	"finly-backend/pkg/mailer"
 	"finly-backend/pkg/security"
+	"finly-backend/pkg/transaction"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 	"strings"
+	"time"
 )
 
 type Auth interface {
@@ -17,17 +29,58 @@ type Auth interface {
 	Logout(ctx context.Context, req *LogoutRequest) (*LogoutResponse, error)
 	RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*RefreshTokenResponse, error)
 	Me(ctx context.Context, req *MeRequest) (*MeResponse, error)
+	Keys(ctx context.Context) (*KeysResponse, error)
+	ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) (*ForgotPasswordResponse, error)
+	ResetPassword(ctx context.Context, req *ResetPasswordRequest) (*ResetPasswordResponse, error)
+	VerifyEmail(ctx context.Context, req *VerifyEmailRequest) (*VerifyEmailResponse, error)
+	ResendVerification(ctx context.Context, req *ResendVerificationRequest) (*ResendVerificationResponse, error)
+	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+	EnrollTwoFactor(ctx context.Context, req *EnrollTwoFactorRequest) (*EnrollTwoFactorResponse, error)
+	ConfirmTwoFactor(ctx context.Context, req *ConfirmTwoFactorRequest) (*ConfirmTwoFactorResponse, error)
+	VerifyTwoFactor(ctx context.Context, req *VerifyTwoFactorRequest) (*VerifyTwoFactorResponse, error)
+	DisableTwoFactor(ctx context.Context, req *DisableTwoFactorRequest) (*DisableTwoFactorResponse, error)
+	RegenerateRecoveryCodes(ctx context.Context, req *RegenerateRecoveryCodesRequest) (*RegenerateRecoveryCodesResponse, error)
+	UpdateMe(ctx context.Context, req *UpdateMeRequest) (*UpdateMeResponse, error)
+	ChangePassword(ctx context.Context, req *ChangePasswordRequest) (*ChangePasswordResponse, error)
+	ChangeEmail(ctx context.Context, req *ChangeEmailRequest) (*ChangeEmailResponse, error)
+	DeleteMe(ctx context.Context, req *DeleteMeRequest) (*DeleteMeResponse, error)
+}
+
+// UserCache is implemented by the repositories that cache data of a user. Deleting an
+// account collects their keys while the rows still exist and purges them after commit.
+type UserCache interface {
+	CacheKeysByUserTX(ctx context.Context, tx *sqlx.Tx, userID string) ([]string, error)
 }
 
 type Service struct {
-	authRepo   auth.Auth
-	budgetRepo budget.Budget
+	authRepo            auth.Auth
+	budgetRepo          budget.Budget
+	refreshTokenRepo    refresh_token.RefreshToken
+	sessionRepo         session.Session
+	passwordResetRepo   password_reset.PasswordReset
+	verificationRepo    email_verification.EmailVerification
+	twoFactorRepo       two_factor.TwoFactor
+	loginAttemptRepo    login_attempt.LoginAttempt
+	userCaches          []UserCache
+	mailer              mailer.Mailer
+	appURL              string
+	transactionExecutor transaction.TransactionExecutor
 }
 
-func NewService(authRepo auth.Auth, budgetRepo budget.Budget) *Service {
+func NewService(authRepo auth.Auth, budgetRepo budget.Budget, refreshTokenRepo refresh_token.RefreshToken, sessionRepo session.Session, passwordResetRepo password_reset.PasswordReset, verificationRepo email_verification.EmailVerification, twoFactorRepo two_factor.TwoFactor, loginAttemptRepo login_attempt.LoginAttempt, userCaches []UserCache, mail mailer.Mailer, appURL string, transactionExecutor transaction.TransactionExecutor) *Service {
 	return &Service{
-		authRepo:   authRepo,
-		budgetRepo: budgetRepo,
+		authRepo:            authRepo,
+		budgetRepo:          budgetRepo,
+		refreshTokenRepo:    refreshTokenRepo,
+		sessionRepo:         sessionRepo,
+		passwordResetRepo:   passwordResetRepo,
+		verificationRepo:    verificationRepo,
+		twoFactorRepo:       twoFactorRepo,
+		loginAttemptRepo:    loginAttemptRepo,
+		userCaches:          userCaches,
+		mailer:              mail,
+		appURL:              strings.TrimSuffix(appURL, "/"),
+		transactionExecutor: transactionExecutor,
 	}
 }
 
@@ -52,91 +105,175 @@ func (s *Service) Register(ctx context.Context, req *RegisterRequest) (*Register
 		return nil, err
 	}
 
-	token, err := security.GenerateJWT(userID, req.Email)
+	user := &domain.User{ID: userID, Email: req.Email, FirstName: req.FirstName}
+	if err = s.sendVerification(ctx, user); err != nil {
+		zap.L().Sugar().Warnf("Error sending verification mail to userID: %s, error: %v", userID, err)
+	}
+
+	pair, err := s.startSession(ctx, userID, req.Email, req.ClientInfo)
 	if err != nil {
-		zap.L().Sugar().Errorf("Error generating JWT for userID: %s, email: %s, error: %v", userID, req.Email, err)
 		return nil, err
 	}
 
 	zap.L().Sugar().Infof("User registered successfully for email: %s", req.Email)
-	return &RegisterResponse{Token: token}, nil
+	return &RegisterResponse{TokenPair: pair}, nil
 }
 
+// Login checks the password of a user. Failed attempts are counted per email and IP, and
+// both are locked out for a while when they fail too often.
 func (s *Service) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+	attempt, err := s.reserveLoginAttempt(ctx, req.Email, req.IP)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := s.authRepo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			zap.L().Sugar().Warnf("Invalid credentials for email: %s", req.Email)
+			s.recordLoginFailure(ctx, attempt)
 			return nil, errs.InvalidCredentials
 		}
 		zap.L().Sugar().Errorf("Error fetching user by email: %s, error: %v", req.Email, err)
+		s.releaseLoginAttempt(ctx, attempt)
 		return nil, err
 	}
 
 	if !security.CheckPasswordHash(req.Password, user.PasswordHash) {
 		zap.L().Sugar().Warnf("Invalid password attempt for email: %s", req.Email)
+		s.recordLoginFailure(ctx, attempt)
 		return nil, errs.InvalidCredentials
 	}
 
-	token, err := security.GenerateJWT(user.ID, user.Email)
+	credential, err := s.twoFactorRepo.GetCredential(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		zap.L().Sugar().Errorf("Error fetching two-factor credential for userID: %s, error: %v", user.ID, err)
+		s.releaseLoginAttempt(ctx, attempt)
+		return nil, err
+	}
+	if credential != nil && credential.ConfirmedAt.Valid {
+		// The code is an attempt of its own.
+		s.releaseLoginAttempt(ctx, attempt)
+		return s.startChallenge(ctx, user)
+	}
+	s.resetLoginThrottle(ctx, attempt)
+
+	pair, err := s.startSession(ctx, user.ID, user.Email, req.ClientInfo)
 	if err != nil {
-		zap.L().Sugar().Errorf("Error generating JWT for userID: %s, email: %s, error: %v", user.ID, user.Email, err)
 		return nil, err
 	}
 
 	zap.L().Sugar().Infof("User logged in successfully for email: %s", req.Email)
-	return &LoginResponse{Token: token}, nil
+	return &LoginResponse{TokenPair: &pair}, nil
 }
 
+// Logout ends the session of the access token. Its access tokens are rejected from now
+// on and its refresh token can't be used anymore.
 func (s *Service) Logout(ctx context.Context, req *LogoutRequest) (*LogoutResponse, error) {
-	isBlacklisted, err := s.authRepo.IsTokenBlacklisted(ctx, req.AuthToken)
-	if err != nil {
-		zap.L().Sugar().Errorf("Error checking if token is blacklisted, token: %s, error: %v", req.AuthToken, err)
-		return nil, err
-	}
-
-	if isBlacklisted {
-		zap.L().Sugar().Infof("Token already blacklisted, token: %s", req.AuthToken)
-		return &LogoutResponse{Message: "Token is already blacklisted"}, nil
+	claims, err := security.GetUserFromToken(req.AuthToken)
+	if err != nil || claims.SessionID == "" {
+		zap.L().Sugar().Warnf("Logout with an invalid token, error: %v", err)
+		return nil, errs.InvalidToken
 	}
 
-	err = s.authRepo.AddTokenToBlacklist(ctx, req.AuthToken, security.TokenTTL.Seconds())
+	var revoked bool
+	err = s.transactionExecutor.WithTransaction(ctx, s.sessionRepo.GetDB(), func(tx *sqlx.Tx) error {
+		var err error
+		revoked, err = s.revokeSessionTX(ctx, tx, claims.SessionID, claims.UserID)
+		return err
+	})
 	if err != nil {
-		zap.L().Sugar().Errorf("Error blacklisting token: %s, error: %v", req.AuthToken, err)
+		zap.L().Sugar().Errorf("Error revoking sessionID: %s, userID: %s, error: %v", claims.SessionID, claims.UserID, err)
 		return nil, err
 	}
 
-	zap.L().Sugar().Infof("Token blacklisted successfully, token: %s", req.AuthToken)
+	if !revoked {
+		zap.L().Sugar().Infof("Session already ended, sessionID: %s", claims.SessionID)
+		return &LogoutResponse{Message: "Session is already ended"}, nil
+	}
+	s.invalidateSessions(ctx, claims.SessionID)
+
+	zap.L().Sugar().Infof("Session ended, sessionID: %s, userID: %s", claims.SessionID, claims.UserID)
 	return &LogoutResponse{Message: "Successfully logged out"}, nil
 }
 
+// RefreshToken exchanges a refresh token for a new token pair. The presented token is
+// rotated and can't be used again; presenting it a second time means it leaked, so the
+// whole family is revoked and the session has to sign in again.
 func (s *Service) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*RefreshTokenResponse, error) {
-	isBlacklisted, err := s.authRepo.IsTokenBlacklisted(ctx, req.AuthToken)
+	var (
+		stored       *domain.RefreshToken
+		refreshToken string
+		reused       bool
+	)
+
+	err := s.transactionExecutor.WithTransaction(ctx, s.refreshTokenRepo.GetDB(), func(tx *sqlx.Tx) error {
+		var err error
+		stored, err = s.refreshTokenRepo.GetByHashTX(ctx, tx, security.HashToken(req.RefreshToken))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.InvalidRefresh
+			}
+			return err
+		}
+
+		switch {
+		case stored.RevokedAt.Valid:
+			return errs.InvalidRefresh
+		case stored.RotatedAt.Valid:
+			reused = true
+			_, err = s.revokeSessionTX(ctx, tx, stored.FamilyID, stored.UserID)
+			return err
+		case !time.Now().Before(stored.ExpiresAt):
+			return errs.TokenExpired
+		}
+
+		if err = s.refreshTokenRepo.MarkRotatedTX(ctx, tx, stored.ID); err != nil {
+			return err
+		}
+
+		var next *domain.RefreshToken
+		if refreshToken, next, err = newRefreshToken(stored.UserID, stored.FamilyID); err != nil {
+			return err
+		}
+		_, err = s.refreshTokenRepo.CreateTX(ctx, tx, next)
+		return err
+	})
 	if err != nil {
-		zap.L().Sugar().Errorf("Error checking if token is blacklisted, token: %s, error: %v", req.AuthToken, err)
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) {
+			zap.L().Sugar().Errorf("Error rotating refresh token, error: %v", err)
+		}
 		return nil, err
 	}
-	if isBlacklisted {
-		zap.L().Sugar().Warnf("Token is blacklisted, token: %s", req.AuthToken)
-		return nil, errs.TokenBlacklisted
+
+	if reused {
+		s.invalidateSessions(ctx, stored.FamilyID)
+		zap.L().Sugar().Warnf("Refresh token reuse detected, revoked sessionID: %s, userID: %s", stored.FamilyID, stored.UserID)
+		return nil, errs.RefreshReused
 	}
-	user, err := security.GetUserFromToken(req.AuthToken)
-	if err != nil {
-		zap.L().Sugar().Errorf("Error extracting user from token: %s, error: %v", req.AuthToken, err)
-		return nil, err
+
+	if err = s.sessionRepo.Touch(ctx, stored.FamilyID, req.IP); err != nil {
+		zap.L().Sugar().Warnf("Failed to record session activity, sessionID: %s, error: %v", stored.FamilyID, err)
 	}
-	newToken, err := security.GenerateJWT(user.UserID, user.Email)
+
+	user, err := s.authRepo.GetUserByID(ctx, stored.UserID)
 	if err != nil {
-		zap.L().Sugar().Errorf("Error generating new JWT for userID: %s, email: %s, error: %v", user.UserID, user.Email, err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.InvalidRefresh
+		}
+		zap.L().Sugar().Errorf("Error fetching user info for userID: %s, error: %v", stored.UserID, err)
 		return nil, err
 	}
-	err = s.authRepo.RemoveToken(ctx, req.AuthToken)
+
+	pair, err := newTokenPair(user.ID, user.Email, stored.FamilyID, refreshToken)
 	if err != nil {
-		zap.L().Sugar().Errorf("Error removing old token: %s, error: %v", req.AuthToken, err)
+		zap.L().Sugar().Errorf("Error generating JWT for userID: %s, email: %s, error: %v", user.ID, user.Email, err)
 		return nil, err
 	}
-	zap.L().Sugar().Infof("Token refreshed successfully for userID: %s", user.UserID)
-	return &RefreshTokenResponse{Token: newToken}, nil
+
+	zap.L().Sugar().Infof("Token refreshed successfully for userID: %s", user.ID)
+	return &RefreshTokenResponse{TokenPair: pair}, nil
 }
 
 func (s *Service) Me(ctx context.Context, req *MeRequest) (*MeResponse, error) {
@@ -165,5 +302,96 @@ func (s *Service) Me(ctx context.Context, req *MeRequest) (*MeResponse, error) {
 			LastName:  userInfo.LastName,
 			Email:     userInfo.Email,
 		},
+		EmailVerified: userInfo.EmailVerifiedAt.Valid,
 	}, nil
 }
+
+// Keys returns the public keys access tokens can be verified with, so other services
+// don't need a shared secret. Tokens signed with HMAC keys can't be verified this way.
+func (s *Service) Keys(ctx context.Context) (*KeysResponse, error) {
+	return &KeysResponse{JWKS: security.PublicJWKS()}, nil
+}
+
+// startSession records a new session and returns its first token pair. The session ID
+// doubles as the family ID of its refresh tokens.
+func (s *Service) startSession(ctx context.Context, userID, email string, client ClientInfo) (TokenPair, error) {
+	var (
+		sessionID    string
+		refreshToken string
+	)
+
+	err := s.transactionExecutor.WithTransaction(ctx, s.sessionRepo.GetDB(), func(tx *sqlx.Tx) error {
+		var err error
+		sessionID, err = s.sessionRepo.CreateTX(ctx, tx, &domain.Session{
+			UserID:    userID,
+			UserAgent: truncate(client.UserAgent, maxUserAgentLength),
+			IP:        client.IP,
+		})
+		if err != nil {
+			return err
+		}
+
+		var record *domain.RefreshToken
+		if refreshToken, record, err = newRefreshToken(userID, sessionID); err != nil {
+			return err
+		}
+		_, err = s.refreshTokenRepo.CreateTX(ctx, tx, record)
+		return err
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("Error starting session for userID: %s, error: %v", userID, err)
+		return TokenPair{}, err
+	}
+
+	pair, err := newTokenPair(userID, email, sessionID, refreshToken)
+	if err != nil {
+		zap.L().Sugar().Errorf("Error generating JWT for userID: %s, email: %s, error: %v", userID, email, err)
+		return TokenPair{}, err
+	}
+
+	return pair, nil
+}
+
+// revokeSessionTX ends a session and revokes its refresh tokens. It reports false when
+// the session was already ended; otherwise the session has to be passed to
+// invalidateSessions once tx is committed.
+func (s *Service) revokeSessionTX(ctx context.Context, tx *sqlx.Tx, sessionID, userID string) (bool, error) {
+	revoked, err := s.sessionRepo.RevokeTX(ctx, tx, sessionID, userID)
+	if err != nil {
+		return false, err
+	}
+	return revoked, s.refreshTokenRepo.RevokeFamilyTX(ctx, tx, sessionID)
+}
+
+// revokeSessionsTX ends every session of the user but keepSessionID, or all of them when
+// keepSessionID is empty, together with their refresh tokens. It returns the ended
+// sessions to pass to invalidateSessions once tx is committed.
+func (s *Service) revokeSessionsTX(ctx context.Context, tx *sqlx.Tx, userID, keepSessionID string) ([]string, error) {
+	var (
+		sessionIDs []string
+		err        error
+	)
+	if keepSessionID == "" {
+		sessionIDs, err = s.sessionRepo.RevokeAllTX(ctx, tx, userID)
+	} else {
+		sessionIDs, err = s.sessionRepo.RevokeOthersTX(ctx, tx, userID, keepSessionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range sessionIDs {
+		if err = s.refreshTokenRepo.RevokeFamilyTX(ctx, tx, id); err != nil {
+			return nil, err
+		}
+	}
+	return sessionIDs, nil
+}
+
+// invalidateSessions drops revoked sessions from the cache. It runs after the revocation
+// is committed; before that, a request could cache the session again as active.
+func (s *Service) invalidateSessions(ctx context.Context, sessionIDs ...string) {
+	if err := s.sessionRepo.InvalidateCache(ctx, sessionIDs...); err != nil {
+		zap.L().Sugar().Warnf("Failed to invalidate session cache, sessionIDs: %v, error: %v", sessionIDs, err)
+	}
+}
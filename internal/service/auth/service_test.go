@@ -7,21 +7,41 @@ import (
 	"finly-backend/internal/domain"
 	"finly-backend/internal/repository/auth/mock"
 	mock2 "finly-backend/internal/repository/budget/mock"
+	mock_email_verification "finly-backend/internal/repository/email_verification/mock"
+	mock_login_attempt "finly-backend/internal/repository/login_attempt/mock"
+	mock_refresh_token "finly-backend/internal/repository/refresh_token/mock"
+	mock_session "finly-backend/internal/repository/session/mock"
+	mock_two_factor "finly-backend/internal/repository/two_factor/mock"
+	"finly-backend/pkg/mailer"
 	"finly-backend/pkg/security"
+	"finly-backend/pkg/testutil"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
+type mockTransactionExecutor struct{}
+
+func (m *mockTransactionExecutor) WithTransaction(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	return fn(nil)
+}
+
 func TestRegister(t *testing.T) {
+	testutil.SetupSigningKeys(t)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockAuthRepo := mock.NewMockAuth(ctrl)
 	mockBudgetRepo := mock2.NewMockBudget(ctrl)
-	service := NewService(mockAuthRepo, mockBudgetRepo)
+	mockRefreshTokenRepo := mock_refresh_token.NewMockRefreshToken(ctrl)
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	mockVerificationRepo := mock_email_verification.NewMockEmailVerification(ctrl)
+	outbox := mailer.NewMemoryMailer()
+	service := NewService(mockAuthRepo, mockBudgetRepo, mockRefreshTokenRepo, mockSessionRepo, nil, mockVerificationRepo, nil, nil, nil, outbox, "http://localhost:5173", &mockTransactionExecutor{})
 	ctx := context.Background()
 
 	tests := []struct {
@@ -38,11 +58,21 @@ func TestRegister(t *testing.T) {
 					FirstName: "John",
 					LastName:  "Doe",
 				},
-				Password: "password123",
+				ClientInfo: ClientInfo{UserAgent: "curl/8.0", IP: "10.0.0.1"},
+				Password:   "password123",
 			},
 			mockSetup: func() {
 				mockAuthRepo.EXPECT().Register(ctx, "test@example.com", gomock.Any(), "John", "Doe").
 					Return("user123", nil)
+				mockVerificationRepo.EXPECT().Create(ctx, gomock.Cond(func(token *domain.EmailVerificationToken) bool {
+					return token.UserID == "user123" && token.Email == "test@example.com" && len(token.TokenHash) == 64
+				})).Return("verification1", nil)
+				mockSessionRepo.EXPECT().GetDB().Return(nil)
+				mockSessionRepo.EXPECT().CreateTX(ctx, gomock.Any(), &domain.Session{UserID: "user123", UserAgent: "curl/8.0", IP: "10.0.0.1"}).
+					Return("session123", nil)
+				mockRefreshTokenRepo.EXPECT().CreateTX(ctx, gomock.Any(), gomock.Cond(func(token *domain.RefreshToken) bool {
+					return token.UserID == "user123" && token.FamilyID == "session123" && len(token.TokenHash) == 64
+				})).Return("token1", nil)
 			},
 			expectedErr: nil,
 		},
@@ -78,6 +108,14 @@ func TestRegister(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, "user123", claims.UserID)
 				assert.Equal(t, "test@example.com", claims.Email)
+				assert.Equal(t, "session123", claims.SessionID)
+				assert.NotEmpty(t, resp.RefreshToken)
+				assert.Equal(t, int(security.AccessTokenTTL.Seconds()), resp.ExpiresIn)
+
+				sent := outbox.Sent()
+				assert.Len(t, sent, 1)
+				assert.Equal(t, "test@example.com", sent[0].To)
+				assert.Contains(t, sent[0].Body, "http://localhost:5173/verify-email?token=")
 			} else {
 				assert.Nil(t, resp)
 			}
@@ -86,12 +124,17 @@ func TestRegister(t *testing.T) {
 }
 
 func TestLogin(t *testing.T) {
+	testutil.SetupSigningKeys(t)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockAuthRepo := mock.NewMockAuth(ctrl)
 	mockBudgetRepo := mock2.NewMockBudget(ctrl)
-	service := NewService(mockAuthRepo, mockBudgetRepo)
+	mockRefreshTokenRepo := mock_refresh_token.NewMockRefreshToken(ctrl)
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	mockTwoFactorRepo := mock_two_factor.NewMockTwoFactor(ctrl)
+	mockLoginAttemptRepo := mock_login_attempt.NewMockLoginAttempt(ctrl)
+	service := NewService(mockAuthRepo, mockBudgetRepo, mockRefreshTokenRepo, mockSessionRepo, nil, nil, mockTwoFactorRepo, mockLoginAttemptRepo, nil, nil, "", &mockTransactionExecutor{})
 	ctx := context.Background()
 
 	tests := []struct {
@@ -108,6 +151,63 @@ func TestLogin(t *testing.T) {
 				Password: "password123",
 			},
 			mockSetup: func() {
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				hashedPassword, _ := security.HashPassword("password123")
+				mockAuthRepo.EXPECT().GetUserByEmail(ctx, "test@example.com").
+					Return(&domain.User{
+						ID:           "user123",
+						Email:        "test@example.com",
+						PasswordHash: hashedPassword,
+					}, nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(nil, sql.ErrNoRows)
+				mockLoginAttemptRepo.EXPECT().Reset(ctx, "email", "test@example.com").Return(nil)
+				mockSessionRepo.EXPECT().GetDB().Return(nil)
+				mockSessionRepo.EXPECT().CreateTX(ctx, gomock.Any(), gomock.Any()).Return("session123", nil)
+				mockRefreshTokenRepo.EXPECT().CreateTX(ctx, gomock.Any(), gomock.Any()).Return("token1", nil)
+			},
+			expectedResp: &LoginResponse{TokenPair: &TokenPair{Token: "mocked_jwt_token"}},
+			expectedErr:  nil,
+		},
+		{
+			name: "Successful login takes back the attempt of the IP",
+			req: &LoginRequest{
+				ClientInfo: ClientInfo{IP: "10.0.0.1"},
+				Email:      "test@example.com",
+				Password:   "password123",
+			},
+			mockSetup: func() {
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "ip", "10.0.0.1").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "ip", "10.0.0.1").Return(ipThrottle.freeAttempts+1, nil)
+				mockLoginAttemptRepo.EXPECT().TryLock(ctx, "ip", "10.0.0.1", time.Second).Return(true, nil)
+				hashedPassword, _ := security.HashPassword("password123")
+				mockAuthRepo.EXPECT().GetUserByEmail(ctx, "test@example.com").
+					Return(&domain.User{
+						ID:           "user123",
+						Email:        "test@example.com",
+						PasswordHash: hashedPassword,
+					}, nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(nil, sql.ErrNoRows)
+				mockLoginAttemptRepo.EXPECT().Reset(ctx, "email", "test@example.com").Return(nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "ip", "10.0.0.1", true).Return(nil)
+				mockSessionRepo.EXPECT().GetDB().Return(nil)
+				mockSessionRepo.EXPECT().CreateTX(ctx, gomock.Any(), gomock.Any()).Return("session123", nil)
+				mockRefreshTokenRepo.EXPECT().CreateTX(ctx, gomock.Any(), gomock.Any()).Return("token1", nil)
+			},
+			expectedResp: &LoginResponse{TokenPair: &TokenPair{Token: "mocked_jwt_token"}},
+			expectedErr:  nil,
+		},
+		{
+			name: "Two-factor challenge",
+			req: &LoginRequest{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			mockSetup: func() {
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
 				hashedPassword, _ := security.HashPassword("password123")
 				mockAuthRepo.EXPECT().GetUserByEmail(ctx, "test@example.com").
 					Return(&domain.User{
@@ -115,8 +215,13 @@ func TestLogin(t *testing.T) {
 						Email:        "test@example.com",
 						PasswordHash: hashedPassword,
 					}, nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").
+					Return(&domain.TOTPCredential{UserID: "user123", ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockTwoFactorRepo.EXPECT().CreateChallenge(ctx, gomock.Any(), &domain.TwoFactorChallenge{UserID: "user123", Email: "test@example.com"}).
+					Return(nil)
 			},
-			expectedResp: &LoginResponse{Token: "mocked_jwt_token"},
+			expectedResp: &LoginResponse{TwoFactorRequired: true, ChallengeExpiresIn: 300},
 			expectedErr:  nil,
 		},
 		{
@@ -126,8 +231,10 @@ func TestLogin(t *testing.T) {
 				Password: "password123",
 			},
 			mockSetup: func() {
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
 				mockAuthRepo.EXPECT().GetUserByEmail(ctx, "test@example.com").
 					Return(nil, sql.ErrNoRows)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
 			},
 			expectedResp: nil,
 			expectedErr:  errs.InvalidCredentials,
@@ -139,6 +246,7 @@ func TestLogin(t *testing.T) {
 				Password: "wrongpassword",
 			},
 			mockSetup: func() {
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
 				hashedPassword, _ := security.HashPassword("password123")
 				mockAuthRepo.EXPECT().GetUserByEmail(ctx, "test@example.com").
 					Return(&domain.User{
@@ -146,10 +254,59 @@ func TestLogin(t *testing.T) {
 						Email:        "test@example.com",
 						PasswordHash: hashedPassword,
 					}, nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(5), nil)
+				mockLoginAttemptRepo.EXPECT().TryLock(ctx, "email", "test@example.com", 2*time.Second).Return(true, nil)
 			},
 			expectedResp: nil,
 			expectedErr:  errs.InvalidCredentials,
 		},
+		{
+			name: "Failure that locks out the IP",
+			req: &LoginRequest{
+				ClientInfo: ClientInfo{IP: "10.0.0.1"},
+				Email:      "Test@Example.com",
+				Password:   "wrongpassword",
+			},
+			mockSetup: func() {
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "ip", "10.0.0.1").Return(time.Duration(0), nil)
+				mockAuthRepo.EXPECT().GetUserByEmail(ctx, "Test@Example.com").Return(nil, sql.ErrNoRows)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "ip", "10.0.0.1").Return(ipThrottle.lockoutAfter, nil)
+				mockLoginAttemptRepo.EXPECT().TryLock(ctx, "ip", "10.0.0.1", ipThrottle.lockout).Return(true, nil)
+			},
+			expectedResp: nil,
+			expectedErr:  errs.InvalidCredentials,
+		},
+		{
+			name: "Locked out",
+			req: &LoginRequest{
+				ClientInfo: ClientInfo{IP: "10.0.0.1"},
+				Email:      "test@example.com",
+				Password:   "password123",
+			},
+			mockSetup: func() {
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(30*time.Second, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "ip", "10.0.0.1").Return(time.Minute, nil)
+			},
+			expectedResp: nil,
+			expectedErr:  tooManyAttempts(time.Minute),
+		},
+		{
+			name: "Parallel attempt finds the lock taken",
+			req: &LoginRequest{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			mockSetup: func() {
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(5), nil)
+				mockLoginAttemptRepo.EXPECT().TryLock(ctx, "email", "test@example.com", 2*time.Second).Return(false, nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+			},
+			expectedResp: nil,
+			expectedErr:  tooManyAttempts(2 * time.Second),
+		},
 	}
 
 	for _, tt := range tests {
@@ -157,24 +314,37 @@ func TestLogin(t *testing.T) {
 			tt.mockSetup()
 			resp, err := service.Login(ctx, tt.req)
 			assert.Equal(t, tt.expectedErr, err)
-			if tt.expectedResp != nil {
-				assert.NotEmpty(t, resp.Token)
-			} else {
+			switch {
+			case tt.expectedResp == nil:
 				assert.Nil(t, resp)
+			case tt.expectedResp.TwoFactorRequired:
+				assert.Nil(t, resp.TokenPair)
+				assert.NotEmpty(t, resp.ChallengeToken)
+				assert.Equal(t, tt.expectedResp.ChallengeExpiresIn, resp.ChallengeExpiresIn)
+			default:
+				assert.NotEmpty(t, resp.Token)
+				assert.NotEmpty(t, resp.RefreshToken)
+				assert.False(t, resp.TwoFactorRequired)
 			}
 		})
 	}
 }
 
 func TestLogout(t *testing.T) {
+	testutil.SetupSigningKeys(t)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockAuthRepo := mock.NewMockAuth(ctrl)
 	mockBudgetRepo := mock2.NewMockBudget(ctrl)
-	service := NewService(mockAuthRepo, mockBudgetRepo)
+	mockRefreshTokenRepo := mock_refresh_token.NewMockRefreshToken(ctrl)
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	service := NewService(mockAuthRepo, mockBudgetRepo, mockRefreshTokenRepo, mockSessionRepo, nil, nil, nil, nil, nil, nil, "", &mockTransactionExecutor{})
 	ctx := context.Background()
 
+	token, err := security.GenerateJWT("user123", "test@example.com", "session123")
+	require.NoError(t, err)
+
 	tests := []struct {
 		name         string
 		req          *LogoutRequest
@@ -185,29 +355,43 @@ func TestLogout(t *testing.T) {
 		{
 			name: "Successful logout",
 			req: &LogoutRequest{
-				AuthToken: "valid_token",
+				AuthToken: "Bearer " + token,
 			},
 			mockSetup: func() {
-				mockAuthRepo.EXPECT().IsTokenBlacklisted(ctx, "valid_token").
-					Return(false, nil)
-				mockAuthRepo.EXPECT().AddTokenToBlacklist(ctx, "valid_token", gomock.Any()).
+				mockSessionRepo.EXPECT().GetDB().Return(nil)
+				mockSessionRepo.EXPECT().RevokeTX(ctx, gomock.Any(), "session123", "user123").
+					Return(true, nil)
+				mockRefreshTokenRepo.EXPECT().RevokeFamilyTX(ctx, gomock.Any(), "session123").
 					Return(nil)
+				mockSessionRepo.EXPECT().InvalidateCache(ctx, "session123").Return(nil)
 			},
 			expectedResp: &LogoutResponse{Message: "Successfully logged out"},
 			expectedErr:  nil,
 		},
 		{
-			name: "Token already blacklisted",
+			name: "Session already ended",
 			req: &LogoutRequest{
-				AuthToken: "blacklisted_token",
+				AuthToken: "Bearer " + token,
 			},
 			mockSetup: func() {
-				mockAuthRepo.EXPECT().IsTokenBlacklisted(ctx, "blacklisted_token").
-					Return(true, nil)
+				mockSessionRepo.EXPECT().GetDB().Return(nil)
+				mockSessionRepo.EXPECT().RevokeTX(ctx, gomock.Any(), "session123", "user123").
+					Return(false, nil)
+				mockRefreshTokenRepo.EXPECT().RevokeFamilyTX(ctx, gomock.Any(), "session123").
+					Return(nil)
 			},
-			expectedResp: &LogoutResponse{Message: "Token is already blacklisted"},
+			expectedResp: &LogoutResponse{Message: "Session is already ended"},
 			expectedErr:  nil,
 		},
+		{
+			name: "Invalid token",
+			req: &LogoutRequest{
+				AuthToken: "Bearer invalid_token",
+			},
+			mockSetup:    func() {},
+			expectedResp: nil,
+			expectedErr:  errs.InvalidToken,
+		},
 	}
 
 	for _, tt := range tests {
@@ -221,67 +405,105 @@ func TestLogout(t *testing.T) {
 }
 
 func TestRefreshToken(t *testing.T) {
+	testutil.SetupSigningKeys(t)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockAuthRepo := mock.NewMockAuth(ctrl)
 	mockBudgetRepo := mock2.NewMockBudget(ctrl)
-	service := NewService(mockAuthRepo, mockBudgetRepo)
+	mockRefreshTokenRepo := mock_refresh_token.NewMockRefreshToken(ctrl)
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	service := NewService(mockAuthRepo, mockBudgetRepo, mockRefreshTokenRepo, mockSessionRepo, nil, nil, nil, nil, nil, nil, "", &mockTransactionExecutor{})
 	ctx := context.Background()
 
-	validToken, err := security.GenerateJWT("user123", "test@example.com")
-	if err != nil {
-		t.Fatalf("failed to generate test token: %v", err)
+	hash := security.HashToken("refresh")
+	stored := func() *domain.RefreshToken {
+		return &domain.RefreshToken{
+			ID: "token1", UserID: "user123", FamilyID: "fam1", TokenHash: hash,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
 	}
 
 	tests := []struct {
-		name         string
-		req          *RefreshTokenRequest
-		mockSetup    func()
-		expectedResp *RefreshTokenResponse
-		expectedErr  error
+		name        string
+		mockSetup   func()
+		expectedErr error
 	}{
 		{
-			name: "Successful token refresh",
-			req: &RefreshTokenRequest{
-				AuthToken: validToken,
+			name: "Successful rotation",
+			mockSetup: func() {
+				mockRefreshTokenRepo.EXPECT().GetDB().Return(nil)
+				mockRefreshTokenRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(stored(), nil)
+				mockRefreshTokenRepo.EXPECT().MarkRotatedTX(ctx, gomock.Any(), "token1").Return(nil)
+				mockRefreshTokenRepo.EXPECT().CreateTX(ctx, gomock.Any(), gomock.Cond(func(token *domain.RefreshToken) bool {
+					return token.UserID == "user123" && token.FamilyID == "fam1" && token.TokenHash != hash
+				})).Return("token2", nil)
+				mockSessionRepo.EXPECT().Touch(ctx, "fam1", "10.0.0.1").Return(nil)
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").
+					Return(&domain.User{ID: "user123", Email: "test@example.com"}, nil)
 			},
+		},
+		{
+			name: "Unknown token",
 			mockSetup: func() {
-				mockAuthRepo.EXPECT().IsTokenBlacklisted(ctx, validToken).
-					Return(false, nil)
-				mockAuthRepo.EXPECT().RemoveToken(ctx, validToken).
-					Return(nil)
+				mockRefreshTokenRepo.EXPECT().GetDB().Return(nil)
+				mockRefreshTokenRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(nil, sql.ErrNoRows)
 			},
-			expectedResp: &RefreshTokenResponse{Token: "mocked_jwt_token"},
-			expectedErr:  nil,
+			expectedErr: errs.InvalidRefresh,
 		},
 		{
-			name: "Blacklisted token",
-			req: &RefreshTokenRequest{
-				AuthToken: "blacklisted_token",
+			name: "Reused token revokes the family",
+			mockSetup: func() {
+				token := stored()
+				token.RotatedAt = sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+				mockRefreshTokenRepo.EXPECT().GetDB().Return(nil)
+				mockRefreshTokenRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(token, nil)
+				mockSessionRepo.EXPECT().RevokeTX(ctx, gomock.Any(), "fam1", "user123").Return(true, nil)
+				mockRefreshTokenRepo.EXPECT().RevokeFamilyTX(ctx, gomock.Any(), "fam1").Return(nil)
+				mockSessionRepo.EXPECT().InvalidateCache(ctx, "fam1").Return(nil)
 			},
+			expectedErr: errs.RefreshReused,
+		},
+		{
+			name: "Revoked family",
 			mockSetup: func() {
-				mockAuthRepo.EXPECT().IsTokenBlacklisted(ctx, "blacklisted_token").
-					Return(true, nil)
+				token := stored()
+				token.RevokedAt = sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+				mockRefreshTokenRepo.EXPECT().GetDB().Return(nil)
+				mockRefreshTokenRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(token, nil)
 			},
-			expectedResp: nil,
-			expectedErr:  errs.TokenBlacklisted,
+			expectedErr: errs.InvalidRefresh,
+		},
+		{
+			name: "Expired token",
+			mockSetup: func() {
+				token := stored()
+				token.ExpiresAt = time.Now().Add(-time.Minute)
+				mockRefreshTokenRepo.EXPECT().GetDB().Return(nil)
+				mockRefreshTokenRepo.EXPECT().GetByHashTX(ctx, gomock.Any(), hash).Return(token, nil)
+			},
+			expectedErr: errs.TokenExpired,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
-			resp, err := service.RefreshToken(ctx, tt.req)
+			resp, err := service.RefreshToken(ctx, &RefreshTokenRequest{
+				ClientInfo:   ClientInfo{IP: "10.0.0.1"},
+				RefreshToken: "refresh",
+			})
 			assert.Equal(t, tt.expectedErr, err)
-			if tt.expectedResp != nil {
+			if tt.expectedErr == nil {
 				assert.NotNil(t, resp)
-				assert.NotEmpty(t, resp.Token)
+				assert.NotEmpty(t, resp.RefreshToken)
+				assert.NotEqual(t, "refresh", resp.RefreshToken)
 
 				claims, err := security.GetUserFromToken(resp.Token)
 				assert.NoError(t, err)
 				assert.Equal(t, "user123", claims.UserID)
 				assert.Equal(t, "test@example.com", claims.Email)
+				assert.Equal(t, "fam1", claims.SessionID)
 			} else {
 				assert.Nil(t, resp)
 			}
@@ -290,15 +512,18 @@ func TestRefreshToken(t *testing.T) {
 }
 
 func TestMe(t *testing.T) {
+	testutil.SetupSigningKeys(t)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockAuthRepo := mock.NewMockAuth(ctrl)
 	mockBudgetRepo := mock2.NewMockBudget(ctrl)
-	service := NewService(mockAuthRepo, mockBudgetRepo)
+	mockRefreshTokenRepo := mock_refresh_token.NewMockRefreshToken(ctrl)
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	service := NewService(mockAuthRepo, mockBudgetRepo, mockRefreshTokenRepo, mockSessionRepo, nil, nil, nil, nil, nil, nil, "", &mockTransactionExecutor{})
 	ctx := context.Background()
 
-	fakeToken, err := security.GenerateJWT("user123", "test@example.com")
+	fakeToken, err := security.GenerateJWT("user123", "test@example.com", "session123")
 	require.NoError(t, err)
 
 	tests := []struct {
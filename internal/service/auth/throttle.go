@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/logger"
+	"finly-backend/pkg/security"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// throttlePolicy slows down password guessing against one subject. The first failures
+// are free; after them every failure locks the subject for twice as long as the one
+// before, and reaching lockoutAfter failures locks it out for lockout.
+type throttlePolicy struct {
+	scope        string
+	freeAttempts int64
+	lockoutAfter int64
+	lockout      time.Duration
+}
+
+const baseLoginBackoff = time.Second
+
+var (
+	emailThrottle = throttlePolicy{scope: "email", freeAttempts: 3, lockoutAfter: 10, lockout: 15 * time.Minute}
+	// ipThrottle is looser, as many users can share an address behind a NAT.
+	ipThrottle = throttlePolicy{scope: "ip", freeAttempts: 20, lockoutAfter: 30, lockout: 15 * time.Minute}
+)
+
+// delay returns how long a subject with the given failures is locked, and whether that
+// is a lockout rather than a backoff.
+func (p throttlePolicy) delay(failures int64) (time.Duration, bool) {
+	switch {
+	case failures >= p.lockoutAfter:
+		return p.lockout, true
+	case failures <= p.freeAttempts:
+		return 0, false
+	}
+	return min(baseLoginBackoff<<(failures-p.freeAttempts-1), p.lockout), false
+}
+
+type loginSubject struct {
+	policy throttlePolicy
+	id     string
+}
+
+// loginSubjects returns what a sign-in attempt is counted against. Emails are compared
+// case-insensitively, so changing the case doesn't start a new count.
+func loginSubjects(email, ip string) []loginSubject {
+	subjects := []loginSubject{{policy: emailThrottle, id: strings.ToLower(strings.TrimSpace(email))}}
+	if ip != "" {
+		subjects = append(subjects, loginSubject{policy: ipThrottle, id: ip})
+	}
+	return subjects
+}
+
+// loginAttempt is a sign-in attempt counted against its email and IP before the
+// credentials are checked, so parallel guesses can't all pass the throttle before the
+// first of them fails.
+type loginAttempt struct {
+	email    string
+	ip       string
+	subjects []reservedSubject
+}
+
+type reservedSubject struct {
+	loginSubject
+	attempts int64
+	// locked is set when the attempt took the lock of the subject.
+	locked bool
+}
+
+// reserveLoginAttempt rejects a sign-in while its email or IP is locked, and counts it
+// otherwise. An attempt past the free ones takes the lock it would earn by failing right
+// away, and is rejected if a parallel attempt took it first. It fails open: when Redis is
+// down users can still sign in.
+func (s *Service) reserveLoginAttempt(ctx context.Context, email, ip string) (*loginAttempt, error) {
+	subjects := loginSubjects(email, ip)
+
+	var retryAfter time.Duration
+	for _, subject := range subjects {
+		lockedFor, err := s.loginAttemptRepo.LockedFor(ctx, subject.policy.scope, subject.id)
+		if err != nil {
+			zap.L().Sugar().Errorf("Error checking login throttle, scope: %s, error: %v", subject.policy.scope, err)
+			continue
+		}
+		retryAfter = max(retryAfter, lockedFor)
+	}
+	if retryAfter > 0 {
+		zap.L().Sugar().Warnf("Throttled login for email: %s, ip: %s, retry after: %s", email, ip, retryAfter)
+		return nil, tooManyAttempts(retryAfter)
+	}
+
+	attempt := &loginAttempt{email: email, ip: ip}
+	for _, subject := range subjects {
+		policy := subject.policy
+		attempts, err := s.loginAttemptRepo.RecordAttempt(ctx, policy.scope, subject.id)
+		if err != nil {
+			zap.L().Sugar().Errorf("Error counting login attempt, scope: %s, error: %v", policy.scope, err)
+			continue
+		}
+		reserved := reservedSubject{loginSubject: subject, attempts: attempts}
+
+		if delay, _ := policy.delay(attempts); delay > 0 {
+			reserved.locked, err = s.loginAttemptRepo.TryLock(ctx, policy.scope, subject.id, delay)
+			if err != nil {
+				zap.L().Sugar().Errorf("Error locking login, scope: %s, error: %v", policy.scope, err)
+			} else if !reserved.locked {
+				attempt.subjects = append(attempt.subjects, reserved)
+				s.releaseLoginAttempt(ctx, attempt)
+				zap.L().Sugar().Warnf("Throttled parallel login for email: %s, ip: %s, retry after: %s", email, ip, delay)
+				return nil, tooManyAttempts(delay)
+			}
+		}
+		attempt.subjects = append(attempt.subjects, reserved)
+	}
+	return attempt, nil
+}
+
+// recordLoginFailure keeps a failed attempt counted, along with the locks it took.
+func (s *Service) recordLoginFailure(ctx context.Context, attempt *loginAttempt) {
+	for _, subject := range attempt.subjects {
+		delay, lockout := subject.policy.delay(subject.attempts)
+		if lockout && subject.locked {
+			logger.SecurityEvent("login_lockout",
+				zap.String("scope", subject.policy.scope),
+				zap.String("subject", subject.id),
+				zap.String("email", attempt.email),
+				zap.String("ip", attempt.ip),
+				zap.Int64("failures", subject.attempts),
+				zap.Duration("duration", delay),
+			)
+		}
+	}
+}
+
+// releaseLoginAttempt takes back an attempt whose credentials weren't found wrong, such as
+// one that ran into an error or a correct password before a sensitive change.
+func (s *Service) releaseLoginAttempt(ctx context.Context, attempt *loginAttempt) {
+	for _, subject := range attempt.subjects {
+		if err := s.loginAttemptRepo.Release(ctx, subject.policy.scope, subject.id, subject.locked); err != nil {
+			zap.L().Sugar().Warnf("Error releasing login attempt, scope: %s, error: %v", subject.policy.scope, err)
+		}
+	}
+}
+
+// resetLoginThrottle forgets the attempts of an email after a successful sign-in. The IP
+// only gets this attempt back, or one known password would let an address guess the others.
+func (s *Service) resetLoginThrottle(ctx context.Context, attempt *loginAttempt) {
+	if err := s.loginAttemptRepo.Reset(ctx, emailThrottle.scope, strings.ToLower(strings.TrimSpace(attempt.email))); err != nil {
+		zap.L().Sugar().Warnf("Error resetting login throttle for email: %s, error: %v", attempt.email, err)
+	}
+	for _, subject := range attempt.subjects {
+		if subject.policy.scope == emailThrottle.scope {
+			continue
+		}
+		if err := s.loginAttemptRepo.Release(ctx, subject.policy.scope, subject.id, subject.locked); err != nil {
+			zap.L().Sugar().Warnf("Error releasing login attempt, scope: %s, error: %v", subject.policy.scope, err)
+		}
+	}
+}
+
+// verifyPassword checks the password of a signed-in user before a sensitive change. Wrong
+// passwords count as failed sign-ins of the user's email and IP, so a stolen session can't
+// be used to guess the password.
+func (s *Service) verifyPassword(ctx context.Context, user *domain.User, password, ip string) error {
+	attempt, err := s.reserveLoginAttempt(ctx, user.Email, ip)
+	if err != nil {
+		return err
+	}
+	if !security.CheckPasswordHash(password, user.PasswordHash) {
+		s.recordLoginFailure(ctx, attempt)
+		return errs.InvalidCredentials
+	}
+	s.releaseLoginAttempt(ctx, attempt)
+	return nil
+}
+
+// verifySecondFactor checks a code of a signed-in user like verifyPassword checks passwords.
+func (s *Service) verifySecondFactor(ctx context.Context, user *domain.User, credential *domain.TOTPCredential, code, ip string) error {
+	attempt, err := s.reserveLoginAttempt(ctx, user.Email, ip)
+	if err != nil {
+		return err
+	}
+	if err = s.checkSecondFactor(ctx, credential, code); err != nil {
+		if errors.Is(err, errs.InvalidTwoFactorCode) {
+			s.recordLoginFailure(ctx, attempt)
+		} else {
+			s.releaseLoginAttempt(ctx, attempt)
+		}
+		return err
+	}
+	s.releaseLoginAttempt(ctx, attempt)
+	return nil
+}
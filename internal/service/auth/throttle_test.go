@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestThrottlePolicyDelay(t *testing.T) {
+	tests := []struct {
+		failures        int64
+		expectedDelay   time.Duration
+		expectedLockout bool
+	}{
+		{failures: 1},
+		{failures: 3},
+		{failures: 4, expectedDelay: time.Second},
+		{failures: 5, expectedDelay: 2 * time.Second},
+		{failures: 9, expectedDelay: 32 * time.Second},
+		{failures: 10, expectedDelay: 15 * time.Minute, expectedLockout: true},
+		{failures: 25, expectedDelay: 15 * time.Minute, expectedLockout: true},
+	}
+
+	for _, tt := range tests {
+		delay, lockout := emailThrottle.delay(tt.failures)
+		assert.Equal(t, tt.expectedDelay, delay, "failures: %d", tt.failures)
+		assert.Equal(t, tt.expectedLockout, lockout, "failures: %d", tt.failures)
+	}
+
+	// The backoff never exceeds a lockout, however many attempts are free.
+	delay, lockout := ipThrottle.delay(ipThrottle.lockoutAfter - 1)
+	assert.Equal(t, 256*time.Second, delay)
+	assert.False(t, lockout)
+}
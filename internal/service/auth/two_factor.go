@@ -0,0 +1,311 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/two_factor"
+	"finly-backend/pkg/security"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// totpIssuer names the account in authenticator apps.
+	totpIssuer = "Finly"
+
+	recoveryCodeCount = 10
+
+	// maxChallengeAttempts is how many codes can be tried for one sign-in before the
+	// password has to be entered again.
+	maxChallengeAttempts = 5
+)
+
+// EnrollTwoFactor creates a new authenticator secret. Two-factor authentication stays off
+// until the secret is confirmed with a code, so an abandoned enrolment locks nobody out.
+func (s *Service) EnrollTwoFactor(ctx context.Context, req *EnrollTwoFactorRequest) (*EnrollTwoFactorResponse, error) {
+	user, err := s.authRepo.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.UserNotFound
+		}
+		zap.L().Sugar().Errorf("Error fetching user info for userID: %s, error: %v", req.UserID, err)
+		return nil, err
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := s.twoFactorRepo.SavePending(ctx, user.ID, secret)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, errs.TwoFactorEnabled
+	}
+
+	zap.L().Sugar().Infof("Two-factor enrolment started for userID: %s", user.ID)
+	return &EnrollTwoFactorResponse{
+		Secret: secret,
+		URI:    security.TOTPURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor turns two-factor authentication on once the user proves their app
+// generates the right codes, and issues the recovery codes.
+func (s *Service) ConfirmTwoFactor(ctx context.Context, req *ConfirmTwoFactorRequest) (*ConfirmTwoFactorResponse, error) {
+	credential, err := s.twoFactorRepo.GetCredential(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.TwoFactorNotEnabled
+		}
+		zap.L().Sugar().Errorf("Error fetching two-factor credential for userID: %s, error: %v", req.UserID, err)
+		return nil, err
+	}
+	if credential.ConfirmedAt.Valid {
+		return nil, errs.TwoFactorEnabled
+	}
+
+	step, ok := security.ValidateTOTP(credential.Secret, req.Code, time.Now())
+	if !ok {
+		zap.L().Sugar().Warnf("Invalid code confirming two-factor authentication for userID: %s", req.UserID)
+		return nil, errs.InvalidTwoFactorCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.transactionExecutor.WithTransaction(ctx, s.twoFactorRepo.GetDB(), func(tx *sqlx.Tx) error {
+		confirmed, err := s.twoFactorRepo.ConfirmTX(ctx, tx, req.UserID, step)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return errs.TwoFactorEnabled
+		}
+		return s.twoFactorRepo.ReplaceRecoveryCodesTX(ctx, tx, req.UserID, hashes)
+	})
+	if err != nil {
+		if !errors.Is(err, errs.TwoFactorEnabled) {
+			zap.L().Sugar().Errorf("Error confirming two-factor authentication for userID: %s, error: %v", req.UserID, err)
+		}
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Two-factor authentication enabled for userID: %s", req.UserID)
+	return &ConfirmTwoFactorResponse{RecoveryCodes: codes}, nil
+}
+
+// VerifyTwoFactor completes a sign-in that Login answered with a challenge. A challenge
+// can be answered once and allows maxChallengeAttempts wrong codes.
+func (s *Service) VerifyTwoFactor(ctx context.Context, req *VerifyTwoFactorRequest) (*VerifyTwoFactorResponse, error) {
+	tokenHash := security.HashToken(req.ChallengeToken)
+
+	challenge, err := s.twoFactorRepo.AttemptChallenge(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, two_factor.ErrChallengeNotFound) {
+			return nil, errs.InvalidChallenge
+		}
+		return nil, err
+	}
+	if challenge.Attempts > maxChallengeAttempts {
+		zap.L().Sugar().Warnf("Too many two-factor attempts for userID: %s", challenge.UserID)
+		if _, err = s.twoFactorRepo.DeleteChallenge(ctx, tokenHash); err != nil {
+			return nil, err
+		}
+		return nil, errs.InvalidChallenge
+	}
+
+	// Wrong codes count as failed sign-ins, or every new challenge would allow more guesses.
+	attempt, err := s.reserveLoginAttempt(ctx, challenge.Email, req.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.twoFactorRepo.GetCredential(ctx, challenge.UserID)
+	if err != nil {
+		s.releaseLoginAttempt(ctx, attempt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.InvalidChallenge
+		}
+		zap.L().Sugar().Errorf("Error fetching two-factor credential for userID: %s, error: %v", challenge.UserID, err)
+		return nil, err
+	}
+
+	if err = s.checkSecondFactor(ctx, credential, req.Code); err != nil {
+		if errors.Is(err, errs.InvalidTwoFactorCode) {
+			s.recordLoginFailure(ctx, attempt)
+		} else {
+			s.releaseLoginAttempt(ctx, attempt)
+		}
+		return nil, err
+	}
+
+	answered, err := s.twoFactorRepo.DeleteChallenge(ctx, tokenHash)
+	if err != nil {
+		s.releaseLoginAttempt(ctx, attempt)
+		return nil, err
+	}
+	if !answered {
+		s.releaseLoginAttempt(ctx, attempt)
+		return nil, errs.InvalidChallenge
+	}
+	s.resetLoginThrottle(ctx, attempt)
+
+	pair, err := s.startSession(ctx, challenge.UserID, challenge.Email, req.ClientInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("User logged in with two-factor authentication, userID: %s", challenge.UserID)
+	return &VerifyTwoFactorResponse{TokenPair: pair}, nil
+}
+
+// DisableTwoFactor turns two-factor authentication off. It asks for the password and a
+// code, so a stolen session alone can't weaken the account.
+func (s *Service) DisableTwoFactor(ctx context.Context, req *DisableTwoFactorRequest) (*DisableTwoFactorResponse, error) {
+	user, err := s.authRepo.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.UserNotFound
+		}
+		zap.L().Sugar().Errorf("Error fetching user info for userID: %s, error: %v", req.UserID, err)
+		return nil, err
+	}
+
+	if err = s.verifyPassword(ctx, user, req.Password, req.IP); err != nil {
+		zap.L().Sugar().Warnf("Invalid password disabling two-factor authentication for userID: %s", req.UserID)
+		return nil, err
+	}
+
+	credential, err := s.enabledCredential(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.verifySecondFactor(ctx, user, credential, req.Code, req.IP); err != nil {
+		return nil, err
+	}
+
+	err = s.transactionExecutor.WithTransaction(ctx, s.twoFactorRepo.GetDB(), func(tx *sqlx.Tx) error {
+		return s.twoFactorRepo.DeleteTX(ctx, tx, req.UserID)
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("Error disabling two-factor authentication for userID: %s, error: %v", req.UserID, err)
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Two-factor authentication disabled for userID: %s", req.UserID)
+	return &DisableTwoFactorResponse{Message: "Two-factor authentication has been disabled"}, nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, for when they ran low or leaked.
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, req *RegenerateRecoveryCodesRequest) (*RegenerateRecoveryCodesResponse, error) {
+	user, err := s.getUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.enabledCredential(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.verifySecondFactor(ctx, user, credential, req.Code, req.IP); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.transactionExecutor.WithTransaction(ctx, s.twoFactorRepo.GetDB(), func(tx *sqlx.Tx) error {
+		return s.twoFactorRepo.ReplaceRecoveryCodesTX(ctx, tx, req.UserID, hashes)
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("Error regenerating recovery codes for userID: %s, error: %v", req.UserID, err)
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Recovery codes regenerated for userID: %s", req.UserID)
+	return &RegenerateRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// startChallenge answers a correct password of a user with two-factor authentication on.
+func (s *Service) startChallenge(ctx context.Context, user *domain.User) (*LoginResponse, error) {
+	token, err := security.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.twoFactorRepo.CreateChallenge(ctx, security.HashToken(token), &domain.TwoFactorChallenge{
+		UserID: user.ID,
+		Email:  user.Email,
+	}); err != nil {
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Two-factor challenge issued for userID: %s", user.ID)
+	return &LoginResponse{
+		TwoFactorRequired:  true,
+		ChallengeToken:     token,
+		ChallengeExpiresIn: int(two_factor.TTL_TwoFactorChallenge.Seconds()),
+	}, nil
+}
+
+func (s *Service) enabledCredential(ctx context.Context, userID string) (*domain.TOTPCredential, error) {
+	credential, err := s.twoFactorRepo.GetCredential(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.TwoFactorNotEnabled
+		}
+		zap.L().Sugar().Errorf("Error fetching two-factor credential for userID: %s, error: %v", userID, err)
+		return nil, err
+	}
+	if !credential.ConfirmedAt.Valid {
+		return nil, errs.TwoFactorNotEnabled
+	}
+	return credential, nil
+}
+
+// checkSecondFactor accepts a current authenticator code that wasn't used before, or an
+// unused recovery code, and uses it up.
+func (s *Service) checkSecondFactor(ctx context.Context, credential *domain.TOTPCredential, code string) error {
+	var (
+		ok  bool
+		err error
+	)
+	if step, valid := security.ValidateTOTP(credential.Secret, code, time.Now()); valid {
+		ok, err = s.twoFactorRepo.UseStep(ctx, credential.UserID, step)
+	} else {
+		ok, err = s.twoFactorRepo.UseRecoveryCode(ctx, credential.UserID, security.HashToken(security.NormalizeRecoveryCode(code)))
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		zap.L().Sugar().Warnf("Invalid two-factor code for userID: %s", credential.UserID)
+		return errs.InvalidTwoFactorCode
+	}
+	return nil
+}
+
+// newRecoveryCodes returns fresh recovery codes for the user and the hashes to store.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := security.GenerateRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = code
+		hashes[i] = security.HashToken(security.NormalizeRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
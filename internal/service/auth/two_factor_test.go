@@ -0,0 +1,404 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/auth/mock"
+	mock_login_attempt "finly-backend/internal/repository/login_attempt/mock"
+	mock_refresh_token "finly-backend/internal/repository/refresh_token/mock"
+	mock_session "finly-backend/internal/repository/session/mock"
+	"finly-backend/internal/repository/two_factor"
+	mock_two_factor "finly-backend/internal/repository/two_factor/mock"
+	"finly-backend/pkg/security"
+	"finly-backend/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+func currentTOTPCode(t *testing.T) string {
+	t.Helper()
+
+	code, err := security.GenerateTOTPCode(testTOTPSecret, time.Now())
+	require.NoError(t, err)
+	return code
+}
+
+func enabledCredential() *domain.TOTPCredential {
+	return &domain.TOTPCredential{
+		UserID:      "user123",
+		Secret:      testTOTPSecret,
+		ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+}
+
+func TestEnrollTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mock.NewMockAuth(ctrl)
+	mockTwoFactorRepo := mock_two_factor.NewMockTwoFactor(ctrl)
+	service := NewService(mockAuthRepo, nil, nil, nil, nil, nil, mockTwoFactorRepo, nil, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	t.Run("Secret created", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(&domain.User{ID: "user123", Email: "test@example.com"}, nil)
+		mockTwoFactorRepo.EXPECT().SavePending(ctx, "user123", gomock.Any()).Return(true, nil)
+
+		resp, err := service.EnrollTwoFactor(ctx, &EnrollTwoFactorRequest{UserID: "user123"})
+		require.NoError(t, err)
+		assert.Len(t, resp.Secret, 32)
+		assert.True(t, strings.HasPrefix(resp.URI, "otpauth://totp/Finly:test@example.com?"))
+		assert.Contains(t, resp.URI, "secret="+resp.Secret)
+	})
+
+	t.Run("Already enabled", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(&domain.User{ID: "user123", Email: "test@example.com"}, nil)
+		mockTwoFactorRepo.EXPECT().SavePending(ctx, "user123", gomock.Any()).Return(false, nil)
+
+		resp, err := service.EnrollTwoFactor(ctx, &EnrollTwoFactorRequest{UserID: "user123"})
+		assert.Equal(t, errs.TwoFactorEnabled, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestConfirmTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTwoFactorRepo := mock_two_factor.NewMockTwoFactor(ctrl)
+	service := NewService(nil, nil, nil, nil, nil, nil, mockTwoFactorRepo, nil, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	pending := &domain.TOTPCredential{UserID: "user123", Secret: testTOTPSecret}
+
+	tests := []struct {
+		name        string
+		code        string
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Enabled",
+			code: currentTOTPCode(t),
+			mockSetup: func() {
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(pending, nil)
+				mockTwoFactorRepo.EXPECT().GetDB().Return(nil)
+				mockTwoFactorRepo.EXPECT().ConfirmTX(ctx, gomock.Any(), "user123", gomock.Any()).Return(true, nil)
+				mockTwoFactorRepo.EXPECT().ReplaceRecoveryCodesTX(ctx, gomock.Any(), "user123", gomock.Len(recoveryCodeCount)).Return(nil)
+			},
+		},
+		{
+			name: "Wrong code",
+			code: "000000",
+			mockSetup: func() {
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(pending, nil)
+			},
+			expectedErr: errs.InvalidTwoFactorCode,
+		},
+		{
+			name: "Not enrolled",
+			code: currentTOTPCode(t),
+			mockSetup: func() {
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.TwoFactorNotEnabled,
+		},
+		{
+			name: "Already enabled",
+			code: currentTOTPCode(t),
+			mockSetup: func() {
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabledCredential(), nil)
+			},
+			expectedErr: errs.TwoFactorEnabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.ConfirmTwoFactor(ctx, &ConfirmTwoFactorRequest{UserID: "user123", Code: tt.code})
+			assert.Equal(t, tt.expectedErr, err)
+			if tt.expectedErr == nil {
+				assert.Len(t, resp.RecoveryCodes, recoveryCodeCount)
+			} else {
+				assert.Nil(t, resp)
+			}
+		})
+	}
+}
+
+func TestVerifyTwoFactor(t *testing.T) {
+	testutil.SetupSigningKeys(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshTokenRepo := mock_refresh_token.NewMockRefreshToken(ctrl)
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	mockTwoFactorRepo := mock_two_factor.NewMockTwoFactor(ctrl)
+	mockLoginAttemptRepo := mock_login_attempt.NewMockLoginAttempt(ctrl)
+	service := NewService(nil, nil, mockRefreshTokenRepo, mockSessionRepo, nil, nil, mockTwoFactorRepo, mockLoginAttemptRepo, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	hash := security.HashToken("challenge")
+	challenge := func(attempts int) *domain.TwoFactorChallenge {
+		return &domain.TwoFactorChallenge{UserID: "user123", Email: "test@example.com", Attempts: attempts}
+	}
+	expectSession := func() {
+		mockTwoFactorRepo.EXPECT().DeleteChallenge(ctx, hash).Return(true, nil)
+		mockLoginAttemptRepo.EXPECT().Reset(ctx, "email", "test@example.com").Return(nil)
+		mockSessionRepo.EXPECT().GetDB().Return(nil)
+		mockSessionRepo.EXPECT().CreateTX(ctx, gomock.Any(), gomock.Any()).Return("session123", nil)
+		mockRefreshTokenRepo.EXPECT().CreateTX(ctx, gomock.Any(), gomock.Any()).Return("token1", nil)
+	}
+
+	tests := []struct {
+		name        string
+		code        string
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Authenticator code",
+			code: currentTOTPCode(t),
+			mockSetup: func() {
+				mockTwoFactorRepo.EXPECT().AttemptChallenge(ctx, hash).Return(challenge(1), nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabledCredential(), nil)
+				mockTwoFactorRepo.EXPECT().UseStep(ctx, "user123", gomock.Any()).Return(true, nil)
+				expectSession()
+			},
+		},
+		{
+			name: "Recovery code",
+			code: "ABCD-efgh-ijkl-mnop",
+			mockSetup: func() {
+				mockTwoFactorRepo.EXPECT().AttemptChallenge(ctx, hash).Return(challenge(1), nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabledCredential(), nil)
+				mockTwoFactorRepo.EXPECT().UseRecoveryCode(ctx, "user123", security.HashToken("abcdefghijklmnop")).Return(true, nil)
+				expectSession()
+			},
+		},
+		{
+			name: "Code used before",
+			code: currentTOTPCode(t),
+			mockSetup: func() {
+				mockTwoFactorRepo.EXPECT().AttemptChallenge(ctx, hash).Return(challenge(2), nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabledCredential(), nil)
+				mockTwoFactorRepo.EXPECT().UseStep(ctx, "user123", gomock.Any()).Return(false, nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(2), nil)
+			},
+			expectedErr: errs.InvalidTwoFactorCode,
+		},
+		{
+			name: "Email locked out",
+			code: currentTOTPCode(t),
+			mockSetup: func() {
+				mockTwoFactorRepo.EXPECT().AttemptChallenge(ctx, hash).Return(challenge(1), nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Minute, nil)
+			},
+			expectedErr: tooManyAttempts(time.Minute),
+		},
+		{
+			name: "Too many attempts",
+			code: currentTOTPCode(t),
+			mockSetup: func() {
+				mockTwoFactorRepo.EXPECT().AttemptChallenge(ctx, hash).Return(challenge(maxChallengeAttempts+1), nil)
+				mockTwoFactorRepo.EXPECT().DeleteChallenge(ctx, hash).Return(true, nil)
+			},
+			expectedErr: errs.InvalidChallenge,
+		},
+		{
+			name: "Expired challenge",
+			code: currentTOTPCode(t),
+			mockSetup: func() {
+				mockTwoFactorRepo.EXPECT().AttemptChallenge(ctx, hash).Return(nil, two_factor.ErrChallengeNotFound)
+			},
+			expectedErr: errs.InvalidChallenge,
+		},
+		{
+			name: "Challenge answered concurrently",
+			code: currentTOTPCode(t),
+			mockSetup: func() {
+				mockTwoFactorRepo.EXPECT().AttemptChallenge(ctx, hash).Return(challenge(1), nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+				mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+				mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabledCredential(), nil)
+				mockTwoFactorRepo.EXPECT().UseStep(ctx, "user123", gomock.Any()).Return(true, nil)
+				mockTwoFactorRepo.EXPECT().DeleteChallenge(ctx, hash).Return(false, nil)
+			},
+			expectedErr: errs.InvalidChallenge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.VerifyTwoFactor(ctx, &VerifyTwoFactorRequest{ChallengeToken: "challenge", Code: tt.code})
+			assert.Equal(t, tt.expectedErr, err)
+			if tt.expectedErr == nil {
+				assert.NotEmpty(t, resp.Token)
+				assert.NotEmpty(t, resp.RefreshToken)
+			} else {
+				assert.Nil(t, resp)
+			}
+		})
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mock.NewMockAuth(ctrl)
+	mockTwoFactorRepo := mock_two_factor.NewMockTwoFactor(ctrl)
+	mockLoginAttemptRepo := mock_login_attempt.NewMockLoginAttempt(ctrl)
+	service := NewService(mockAuthRepo, nil, nil, nil, nil, nil, mockTwoFactorRepo, mockLoginAttemptRepo, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	hashedPassword, _ := security.HashPassword("password123")
+	user := &domain.User{ID: "user123", Email: "test@example.com", PasswordHash: hashedPassword}
+	expectAttempt := func() {
+		mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+		mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "ip", "203.0.113.7").Return(time.Duration(0), nil)
+		mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+		mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "ip", "203.0.113.7").Return(int64(1), nil)
+	}
+	expectRelease := func() {
+		mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+		mockLoginAttemptRepo.EXPECT().Release(ctx, "ip", "203.0.113.7", false).Return(nil)
+	}
+
+	tests := []struct {
+		name         string
+		password     string
+		code         string
+		mockSetup    func()
+		expectedResp *DisableTwoFactorResponse
+		expectedErr  error
+	}{
+		{
+			name:     "Disabled",
+			password: "password123",
+			code:     currentTOTPCode(t),
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				expectAttempt()
+				expectRelease()
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabledCredential(), nil)
+				expectAttempt()
+				expectRelease()
+				mockTwoFactorRepo.EXPECT().UseStep(ctx, "user123", gomock.Any()).Return(true, nil)
+				mockTwoFactorRepo.EXPECT().GetDB().Return(nil)
+				mockTwoFactorRepo.EXPECT().DeleteTX(ctx, gomock.Any(), "user123").Return(nil)
+			},
+			expectedResp: &DisableTwoFactorResponse{Message: "Two-factor authentication has been disabled"},
+		},
+		{
+			name:     "Wrong password",
+			password: "wrongpassword",
+			code:     currentTOTPCode(t),
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				expectAttempt()
+			},
+			expectedErr: errs.InvalidCredentials,
+		},
+		{
+			name:     "Wrong code",
+			password: "password123",
+			code:     "wrong",
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				expectAttempt()
+				expectRelease()
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabledCredential(), nil)
+				expectAttempt()
+				mockTwoFactorRepo.EXPECT().UseRecoveryCode(ctx, "user123", gomock.Any()).Return(false, nil)
+			},
+			expectedErr: errs.InvalidTwoFactorCode,
+		},
+		{
+			name:     "Throttled",
+			password: "password123",
+			code:     currentTOTPCode(t),
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Minute, nil)
+				mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "ip", "203.0.113.7").Return(time.Duration(0), nil)
+			},
+			expectedErr: tooManyAttempts(time.Minute),
+		},
+		{
+			name:     "Not enabled",
+			password: "password123",
+			code:     currentTOTPCode(t),
+			mockSetup: func() {
+				mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+				expectAttempt()
+				expectRelease()
+				mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(&domain.TOTPCredential{UserID: "user123", Secret: testTOTPSecret}, nil)
+			},
+			expectedErr: errs.TwoFactorNotEnabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.DisableTwoFactor(ctx, &DisableTwoFactorRequest{UserID: "user123", IP: "203.0.113.7", Password: tt.password, Code: tt.code})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
+
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mock.NewMockAuth(ctrl)
+	mockTwoFactorRepo := mock_two_factor.NewMockTwoFactor(ctrl)
+	mockLoginAttemptRepo := mock_login_attempt.NewMockLoginAttempt(ctrl)
+	service := NewService(mockAuthRepo, nil, nil, nil, nil, nil, mockTwoFactorRepo, mockLoginAttemptRepo, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+	user := &domain.User{ID: "user123", Email: "test@example.com"}
+
+	t.Run("Regenerated", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabledCredential(), nil)
+		mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+		mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(1), nil)
+		mockLoginAttemptRepo.EXPECT().Release(ctx, "email", "test@example.com", false).Return(nil)
+		mockTwoFactorRepo.EXPECT().UseStep(ctx, "user123", gomock.Any()).Return(true, nil)
+		mockTwoFactorRepo.EXPECT().GetDB().Return(nil)
+		mockTwoFactorRepo.EXPECT().ReplaceRecoveryCodesTX(ctx, gomock.Any(), "user123", gomock.Len(recoveryCodeCount)).Return(nil)
+
+		resp, err := service.RegenerateRecoveryCodes(ctx, &RegenerateRecoveryCodesRequest{UserID: "user123", Code: currentTOTPCode(t)})
+		require.NoError(t, err)
+		assert.Len(t, resp.RecoveryCodes, recoveryCodeCount)
+	})
+
+	t.Run("Wrong code", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockTwoFactorRepo.EXPECT().GetCredential(ctx, "user123").Return(enabledCredential(), nil)
+		mockLoginAttemptRepo.EXPECT().LockedFor(ctx, "email", "test@example.com").Return(time.Duration(0), nil)
+		mockTwoFactorRepo.EXPECT().UseRecoveryCode(ctx, "user123", gomock.Any()).Return(false, nil)
+		mockLoginAttemptRepo.EXPECT().RecordAttempt(ctx, "email", "test@example.com").Return(int64(2), nil)
+
+		resp, err := service.RegenerateRecoveryCodes(ctx, &RegenerateRecoveryCodesRequest{UserID: "user123", Code: "wrong"})
+		assert.Equal(t, errs.InvalidTwoFactorCode, err)
+		assert.Nil(t, resp)
+	})
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/security"
+	"time"
+)
+
+// maxUserAgentLength matches the user_agent column of the sessions table.
+const maxUserAgentLength = 512
+
+// newRefreshToken generates a refresh token of a family. The plain token goes to the
+// client; only the returned record with its hash is stored.
+func newRefreshToken(userID, familyID string) (string, *domain.RefreshToken, error) {
+	token, err := security.GenerateOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, &domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: time.Now().UTC().Add(security.RefreshTokenTTL),
+	}, nil
+}
+
+func newTokenPair(userID, email, sessionID, refreshToken string) (TokenPair, error) {
+	token, err := security.GenerateJWT(userID, email, sessionID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(security.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// truncate cuts s to at most n runes.
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
@@ -11,10 +11,16 @@ var errs = struct {
 	TokenBlacklisted   *echo.HTTPError
 	InvalidToken       *echo.HTTPError
 	UserNotFound       *echo.HTTPError
+	BudgetNotFound     *echo.HTTPError
+	BudgetForbidden    *echo.HTTPError
+	CurrencyLocked     *echo.HTTPError
 }{
 	UserAlreadyExists:  echo.NewHTTPError(http.StatusConflict, "User already exists"),
 	InvalidCredentials: echo.NewHTTPError(http.StatusUnauthorized, "Invalid credentials"),
 	TokenBlacklisted:   echo.NewHTTPError(http.StatusForbidden, "Token is blacklisted"),
 	InvalidToken:       echo.NewHTTPError(http.StatusUnauthorized, "Invalid token"),
 	UserNotFound:       echo.NewHTTPError(http.StatusNotFound, "User not found"),
+	BudgetNotFound:     echo.NewHTTPError(http.StatusNotFound, "Budget not found"),
+	BudgetForbidden:    echo.NewHTTPError(http.StatusForbidden, "Your role in this budget does not allow this"),
+	CurrencyLocked:     echo.NewHTTPError(http.StatusConflict, "The currency of a budget with transactions cannot change"),
 }
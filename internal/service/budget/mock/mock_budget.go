@@ -41,6 +41,21 @@ func (m *MockBudget) EXPECT() *MockBudgetMockRecorder {
 	return m.recorder
 }
 
+// Archive mocks base method.
+func (m *MockBudget) Archive(ctx context.Context, req *budget.ArchiveBudgetRequest) (*budget.ArchiveBudgetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx, req)
+	ret0, _ := ret[0].(*budget.ArchiveBudgetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockBudgetMockRecorder) Archive(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockBudget)(nil).Archive), ctx, req)
+}
+
 // Create mocks base method.
 func (m *MockBudget) Create(ctx context.Context, req *budget.CreateBudgetRequest) (*budget.CreateBudgetResponse, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +71,21 @@ func (mr *MockBudgetMockRecorder) Create(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBudget)(nil).Create), ctx, req)
 }
 
+// Delete mocks base method.
+func (m *MockBudget) Delete(ctx context.Context, req *budget.DeleteBudgetRequest) (*budget.DeleteBudgetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, req)
+	ret0, _ := ret[0].(*budget.DeleteBudgetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBudgetMockRecorder) Delete(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBudget)(nil).Delete), ctx, req)
+}
+
 // GetBudgetHistory mocks base method.
 func (m *MockBudget) GetBudgetHistory(ctx context.Context, req *budget.GetBudgetHistoryRequest) (*budget.GetBudgetHistoryResponse, error) {
 	m.ctrl.T.Helper()
@@ -71,19 +101,19 @@ func (mr *MockBudgetMockRecorder) GetBudgetHistory(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBudgetHistory", reflect.TypeOf((*MockBudget)(nil).GetBudgetHistory), ctx, req)
 }
 
-// GetByUserID mocks base method.
-func (m *MockBudget) GetByUserID(ctx context.Context, req *budget.GetBudgetByIDRequest) (*budget.GetBudgetByIDResponse, error) {
+// GetByID mocks base method.
+func (m *MockBudget) GetByID(ctx context.Context, req *budget.GetBudgetByIDRequest) (*budget.GetBudgetByIDResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserID", ctx, req)
+	ret := m.ctrl.Call(m, "GetByID", ctx, req)
 	ret0, _ := ret[0].(*budget.GetBudgetByIDResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByUserID indicates an expected call of GetByUserID.
-func (mr *MockBudgetMockRecorder) GetByUserID(ctx, req any) *gomock.Call {
+// GetByID indicates an expected call of GetByID.
+func (mr *MockBudgetMockRecorder) GetByID(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockBudget)(nil).GetByUserID), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBudget)(nil).GetByID), ctx, req)
 }
 
 // GetCurrentBalance mocks base method.
@@ -100,3 +130,48 @@ func (mr *MockBudgetMockRecorder) GetCurrentBalance(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentBalance", reflect.TypeOf((*MockBudget)(nil).GetCurrentBalance), ctx, req)
 }
+
+// List mocks base method.
+func (m *MockBudget) List(ctx context.Context, req *budget.ListBudgetsRequest) (*budget.ListBudgetsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(*budget.ListBudgetsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockBudgetMockRecorder) List(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBudget)(nil).List), ctx, req)
+}
+
+// Unarchive mocks base method.
+func (m *MockBudget) Unarchive(ctx context.Context, req *budget.UnarchiveBudgetRequest) (*budget.UnarchiveBudgetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unarchive", ctx, req)
+	ret0, _ := ret[0].(*budget.UnarchiveBudgetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unarchive indicates an expected call of Unarchive.
+func (mr *MockBudgetMockRecorder) Unarchive(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unarchive", reflect.TypeOf((*MockBudget)(nil).Unarchive), ctx, req)
+}
+
+// Update mocks base method.
+func (m *MockBudget) Update(ctx context.Context, req *budget.UpdateBudgetRequest) (*budget.UpdateBudgetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, req)
+	ret0, _ := ret[0].(*budget.UpdateBudgetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBudgetMockRecorder) Update(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBudget)(nil).Update), ctx, req)
+}
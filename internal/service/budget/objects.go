@@ -1,36 +1,84 @@
 package budget
 
 import (
+	"finly-backend/internal/domain"
 	"time"
 )
 
+const defaultBudgetName = "Main"
+
 type BudgetObject struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Currency  string    `json:"currency"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Currency   string     `json:"currency"`
+	Role       string     `json:"role"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
 }
 
 type CreateBudgetRequest struct {
-	UserID   string  `header:"User-Id" validate:"required"`
-	Currency string  `json:"currency" validate:"required"`
-	Amount   float64 `json:"amount" validate:"required"`
+	UserID   string       `header:"User-Id" validate:"required"`
+	Name     string       `json:"name" validate:"omitempty,max=100"`
+	Currency string       `json:"currency" validate:"required"`
+	Amount   domain.Money `json:"amount" validate:"required" swaggertype:"number"`
 }
 
 type CreateBudgetResponse struct {
 	ID string `json:"id"`
 }
 
+type ListBudgetsRequest struct {
+	UserID          string `header:"User-Id" validate:"required"`
+	IncludeArchived bool   `query:"include_archived"`
+}
+
+type ListBudgetsResponse struct {
+	Budgets []*BudgetObject `json:"budgets"`
+}
+
 type GetBudgetByIDRequest struct {
-	UserID string `header:"User-Id" validate:"required"`
+	UserID   string `header:"User-Id" validate:"required"`
+	BudgetID string `param:"budget_id" validate:"required"`
 }
 
 type GetBudgetByIDResponse struct {
 	*BudgetObject
 }
 
+type UpdateBudgetRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	BudgetID string `param:"budget_id" validate:"required"`
+	Name     string `json:"name,omitempty" validate:"omitempty,max=100"`
+	Currency string `json:"currency,omitempty" validate:"omitempty,len=3"`
+}
+
+type UpdateBudgetResponse struct{}
+
+type ArchiveBudgetRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	BudgetID string `param:"budget_id" validate:"required"`
+}
+
+type ArchiveBudgetResponse struct{}
+
+type UnarchiveBudgetRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	BudgetID string `param:"budget_id" validate:"required"`
+}
+
+type UnarchiveBudgetResponse struct{}
+
+type DeleteBudgetRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	BudgetID string `param:"budget_id" validate:"required"`
+}
+
+type DeleteBudgetResponse struct{}
+
 type GetBudgetHistoryRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
 	BudgetID string `param:"budget_id" validate:"required"`
 }
 
@@ -39,16 +87,34 @@ type GetBudgetHistoryResponse struct {
 }
 
 type BudgetHistory struct {
-	ID        string    `json:"id"`
-	BudgetID  string    `json:"budget_id"`
-	Balance   float64   `json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        string       `json:"id"`
+	BudgetID  string       `json:"budget_id"`
+	Balance   domain.Money `json:"balance" swaggertype:"number"`
+	CreatedAt time.Time    `json:"created_at"`
 }
 
 type GetCurrentBalanceRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
 	BudgetID string `param:"budget_id" validate:"required"`
 }
 
 type GetCurrentBalanceResponse struct {
-	Balance float64 `json:"balance"`
+	Balance domain.Money `json:"balance" swaggertype:"number"`
+}
+
+func convertBudget(budget *domain.Budget) *BudgetObject {
+	obj := &BudgetObject{
+		ID:        budget.ID,
+		UserID:    budget.UserID,
+		Name:      budget.Name,
+		Currency:  budget.Currency,
+		Role:      budget.Role,
+		CreatedAt: budget.CreatedAt,
+		UpdatedAt: budget.UpdatedAt,
+	}
+	if budget.ArchivedAt.Valid {
+		archivedAt := budget.ArchivedAt.Time
+		obj.ArchivedAt = &archivedAt
+	}
+	return obj
 }
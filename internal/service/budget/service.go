@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_budget_role"
 	"finly-backend/internal/repository/budget"
 	"finly-backend/internal/repository/budget_history"
 	"finly-backend/pkg/transaction"
@@ -13,7 +15,12 @@ import (
 
 type Budget interface {
 	Create(ctx context.Context, req *CreateBudgetRequest) (*CreateBudgetResponse, error)
-	GetByUserID(ctx context.Context, req *GetBudgetByIDRequest) (*GetBudgetByIDResponse, error)
+	List(ctx context.Context, req *ListBudgetsRequest) (*ListBudgetsResponse, error)
+	GetByID(ctx context.Context, req *GetBudgetByIDRequest) (*GetBudgetByIDResponse, error)
+	Update(ctx context.Context, req *UpdateBudgetRequest) (*UpdateBudgetResponse, error)
+	Archive(ctx context.Context, req *ArchiveBudgetRequest) (*ArchiveBudgetResponse, error)
+	Unarchive(ctx context.Context, req *UnarchiveBudgetRequest) (*UnarchiveBudgetResponse, error)
+	Delete(ctx context.Context, req *DeleteBudgetRequest) (*DeleteBudgetResponse, error)
 	GetBudgetHistory(ctx context.Context, req *GetBudgetHistoryRequest) (*GetBudgetHistoryResponse, error)
 	GetCurrentBalance(ctx context.Context, req *GetCurrentBalanceRequest) (*GetCurrentBalanceResponse, error)
 }
@@ -35,17 +42,22 @@ func NewService(budgetRepo budget.Budget, budgetHistoryRepo budget_history.Budge
 }
 
 func (s *Service) Create(ctx context.Context, req *CreateBudgetRequest) (*CreateBudgetResponse, error) {
+	name := req.Name
+	if name == "" {
+		name = defaultBudgetName
+	}
+
 	var budgetID string
 	err := s.transactionExecutor.WithTransaction(ctx, s.budgetRepo.GetDB(), func(tx *sqlx.Tx) error {
 		var err error
 
-		budgetID, err = s.budgetRepo.CreateTX(ctx, tx, req.UserID, req.Currency)
+		budgetID, err = s.budgetRepo.CreateTX(ctx, tx, req.UserID, name, req.Currency)
 		if err != nil {
 			zap.L().Sugar().Errorf("Create: failed to create budget: %v", err)
 			return err
 		}
 
-		if req.Amount != 0 {
+		if !req.Amount.IsZero() {
 			if _, err = s.budgetHistoryRepo.CreateInitialTX(ctx, tx, budgetID, req.Amount); err != nil {
 				zap.L().Sugar().Errorf("Create: failed to create initial history for budgetID=%s: %v", budgetID, err)
 				return err
@@ -64,29 +76,85 @@ func (s *Service) Create(ctx context.Context, req *CreateBudgetRequest) (*Create
 	return &CreateBudgetResponse{ID: budgetID}, nil
 }
 
-func (s *Service) GetByUserID(ctx context.Context, req *GetBudgetByIDRequest) (*GetBudgetByIDResponse, error) {
-	budget, err := s.budgetRepo.GetByUserID(ctx, req.UserID)
+func (s *Service) List(ctx context.Context, req *ListBudgetsRequest) (*ListBudgetsResponse, error) {
+	budgets, err := s.budgetRepo.ListByUserID(ctx, req.UserID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			zap.L().Sugar().Infof("GetByUserID: no budget found for userID=%s", req.UserID)
-			return &GetBudgetByIDResponse{}, nil
+		zap.L().Sugar().Errorf("List: failed for userID=%s: %v", req.UserID, err)
+		return nil, err
+	}
+
+	list := make([]*BudgetObject, 0, len(budgets))
+	for _, b := range budgets {
+		if b.ArchivedAt.Valid && !req.IncludeArchived {
+			continue
 		}
-		zap.L().Sugar().Errorf("GetByUserID: failed for userID=%s: %v", req.UserID, err)
+		list = append(list, convertBudget(b))
+	}
+
+	return &ListBudgetsResponse{Budgets: list}, nil
+}
+
+func (s *Service) GetByID(ctx context.Context, req *GetBudgetByIDRequest) (*GetBudgetByIDResponse, error) {
+	budget, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Viewer)
+	if err != nil {
 		return nil, err
 	}
 
-	return &GetBudgetByIDResponse{
-		&BudgetObject{
-			ID:        budget.ID,
-			UserID:    budget.UserID,
-			Currency:  budget.Currency,
-			CreatedAt: budget.CreatedAt,
-			UpdatedAt: budget.UpdatedAt,
-		},
-	}, nil
+	return &GetBudgetByIDResponse{convertBudget(budget)}, nil
+}
+
+func (s *Service) Update(ctx context.Context, req *UpdateBudgetRequest) (*UpdateBudgetResponse, error) {
+	if _, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Owner); err != nil {
+		return nil, err
+	}
+
+	if err := s.budgetRepo.Update(ctx, req.BudgetID, req.UserID, req.Name, req.Currency); err != nil {
+		if errors.Is(err, budget.ErrCurrencyLocked) {
+			return nil, errs.CurrencyLocked
+		}
+		zap.L().Sugar().Errorf("Update: failed for budgetID=%s, userID=%s: %v", req.BudgetID, req.UserID, err)
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Update: completed for budgetID=%s, userID=%s", req.BudgetID, req.UserID)
+	return &UpdateBudgetResponse{}, nil
+}
+
+func (s *Service) Archive(ctx context.Context, req *ArchiveBudgetRequest) (*ArchiveBudgetResponse, error) {
+	if err := s.setArchived(ctx, req.BudgetID, req.UserID, true); err != nil {
+		return nil, err
+	}
+
+	return &ArchiveBudgetResponse{}, nil
+}
+
+func (s *Service) Unarchive(ctx context.Context, req *UnarchiveBudgetRequest) (*UnarchiveBudgetResponse, error) {
+	if err := s.setArchived(ctx, req.BudgetID, req.UserID, false); err != nil {
+		return nil, err
+	}
+
+	return &UnarchiveBudgetResponse{}, nil
+}
+
+func (s *Service) Delete(ctx context.Context, req *DeleteBudgetRequest) (*DeleteBudgetResponse, error) {
+	if _, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Owner); err != nil {
+		return nil, err
+	}
+
+	if err := s.budgetRepo.Delete(ctx, req.BudgetID, req.UserID); err != nil {
+		zap.L().Sugar().Errorf("Delete: failed for budgetID=%s, userID=%s: %v", req.BudgetID, req.UserID, err)
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Delete: completed for budgetID=%s, userID=%s", req.BudgetID, req.UserID)
+	return &DeleteBudgetResponse{}, nil
 }
 
 func (s *Service) GetBudgetHistory(ctx context.Context, req *GetBudgetHistoryRequest) (*GetBudgetHistoryResponse, error) {
+	if _, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Viewer); err != nil {
+		return nil, err
+	}
+
 	budgets, err := s.budgetHistoryRepo.List(ctx, req.BudgetID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -113,6 +181,10 @@ func (s *Service) GetBudgetHistory(ctx context.Context, req *GetBudgetHistoryReq
 }
 
 func (s *Service) GetCurrentBalance(ctx context.Context, req *GetCurrentBalanceRequest) (*GetCurrentBalanceResponse, error) {
+	if _, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Viewer); err != nil {
+		return nil, err
+	}
+
 	balance, err := s.budgetHistoryRepo.GetCurrentBalance(ctx, req.BudgetID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -127,3 +199,38 @@ func (s *Service) GetCurrentBalance(ctx context.Context, req *GetCurrentBalanceR
 		Balance: balance,
 	}, nil
 }
+
+func (s *Service) setArchived(ctx context.Context, budgetID, userID string, archived bool) error {
+	if _, err := s.getBudget(ctx, budgetID, userID, e_budget_role.Owner); err != nil {
+		return err
+	}
+
+	if err := s.budgetRepo.SetArchived(ctx, budgetID, userID, archived); err != nil {
+		zap.L().Sugar().Errorf("setArchived: failed for budgetID=%s, userID=%s, archived=%t: %v", budgetID, userID, archived, err)
+		return err
+	}
+
+	zap.L().Sugar().Infof("setArchived: budgetID=%s, userID=%s, archived=%t", budgetID, userID, archived)
+	return nil
+}
+
+// getBudget loads the budget only if the user is a member of it, so a foreign budget ID is
+// indistinguishable from a missing one. Members whose role is below the required one are refused.
+func (s *Service) getBudget(ctx context.Context, budgetID, userID string, required e_budget_role.Enum) (*domain.Budget, error) {
+	budget, err := s.budgetRepo.GetByID(ctx, budgetID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			zap.L().Sugar().Warnf("budget not found for budgetID=%s, userID=%s", budgetID, userID)
+			return nil, errs.BudgetNotFound
+		}
+		zap.L().Sugar().Errorf("failed to get budgetID=%s for userID=%s: %v", budgetID, userID, err)
+		return nil, err
+	}
+
+	if !e_budget_role.Enum(budget.Role).Allows(required) {
+		zap.L().Sugar().Warnf("budgetID=%s requires role %s, userID=%s is %s", budgetID, required, userID, budget.Role)
+		return nil, errs.BudgetForbidden
+	}
+
+	return budget, nil
+}
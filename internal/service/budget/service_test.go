@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/budget"
 	"finly-backend/internal/repository/budget/mock"
 	mock_budget_history "finly-backend/internal/repository/budget_history/mock"
 	"finly-backend/pkg/transaction"
@@ -46,13 +47,13 @@ func TestCreate(t *testing.T) {
 			req: &CreateBudgetRequest{
 				UserID:   "user123",
 				Currency: "USD",
-				Amount:   100.00,
+				Amount:   domain.MustParseMoney("100.00"),
 			},
 			mockSetup: func() {
 				mockBudgetRepo.EXPECT().GetDB().Return(mockDB)
-				mockBudgetRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "USD").
+				mockBudgetRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "Main", "USD").
 					Return("budget123", nil)
-				mockBudgetHistoryRepo.EXPECT().CreateInitialTX(ctx, mockTx, "budget123", 100.00).
+				mockBudgetHistoryRepo.EXPECT().CreateInitialTX(ctx, mockTx, "budget123", domain.MustParseMoney("100.00")).
 					Return("history123", nil)
 			},
 			expectedRes: &CreateBudgetResponse{ID: "budget123"},
@@ -63,11 +64,11 @@ func TestCreate(t *testing.T) {
 			req: &CreateBudgetRequest{
 				UserID:   "user123",
 				Currency: "USD",
-				Amount:   0,
+				Amount:   domain.Money{},
 			},
 			mockSetup: func() {
 				mockBudgetRepo.EXPECT().GetDB().Return(mockDB)
-				mockBudgetRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "USD").
+				mockBudgetRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "Main", "USD").
 					Return("budget123", nil)
 				// No history call expected
 			},
@@ -79,11 +80,11 @@ func TestCreate(t *testing.T) {
 			req: &CreateBudgetRequest{
 				UserID:   "user123",
 				Currency: "USD",
-				Amount:   50,
+				Amount:   domain.MustParseMoney("50.00"),
 			},
 			mockSetup: func() {
 				mockBudgetRepo.EXPECT().GetDB().Return(mockDB)
-				mockBudgetRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "USD").
+				mockBudgetRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "Main", "USD").
 					Return("", errors.New("create error"))
 			},
 			expectedRes: nil,
@@ -94,13 +95,13 @@ func TestCreate(t *testing.T) {
 			req: &CreateBudgetRequest{
 				UserID:   "user123",
 				Currency: "USD",
-				Amount:   100,
+				Amount:   domain.MustParseMoney("100.00"),
 			},
 			mockSetup: func() {
 				mockBudgetRepo.EXPECT().GetDB().Return(mockDB)
-				mockBudgetRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "USD").
+				mockBudgetRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "Main", "USD").
 					Return("budget123", nil)
-				mockBudgetHistoryRepo.EXPECT().CreateInitialTX(ctx, mockTx, "budget123", 100.00).
+				mockBudgetHistoryRepo.EXPECT().CreateInitialTX(ctx, mockTx, "budget123", domain.MustParseMoney("100.00")).
 					Return("", errors.New("history error"))
 			},
 			expectedRes: nil,
@@ -134,7 +135,86 @@ func TestCreate(t *testing.T) {
 	}
 }
 
-func TestGetByUserID(t *testing.T) {
+func TestList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBudgetRepo := mock.NewMockBudget(ctrl)
+	mockBudgetHistoryRepo := mock_budget_history.NewMockBudgetHistory(ctrl)
+	service := NewService(mockBudgetRepo, mockBudgetHistoryRepo, transaction.NewTransactionExecutor())
+	ctx := context.Background()
+
+	createdAt := time.Now()
+	archivedAt := createdAt.Add(time.Hour)
+	budgets := []*domain.Budget{
+		{ID: "budget1", UserID: "user123", Name: "Cash", Currency: "USD", Role: "owner", CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: "budget2", UserID: "user123", Name: "Old card", Currency: "USD", Role: "owner", ArchivedAt: sql.NullTime{Time: archivedAt, Valid: true}, CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: "budget3", UserID: "user456", Name: "Household", Currency: "USD", Role: "editor", CreatedAt: createdAt, UpdatedAt: createdAt},
+	}
+
+	tests := []struct {
+		name        string
+		req         *ListBudgetsRequest
+		mockSetup   func()
+		expectedRes *ListBudgetsResponse
+		expectedErr error
+	}{
+		{
+			name: "Active budgets only",
+			req:  &ListBudgetsRequest{UserID: "user123"},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").Return(budgets, nil)
+			},
+			expectedRes: &ListBudgetsResponse{
+				Budgets: []*BudgetObject{
+					{ID: "budget1", UserID: "user123", Name: "Cash", Currency: "USD", Role: "owner", CreatedAt: createdAt, UpdatedAt: createdAt},
+					{ID: "budget3", UserID: "user456", Name: "Household", Currency: "USD", Role: "editor", CreatedAt: createdAt, UpdatedAt: createdAt},
+				},
+			},
+		},
+		{
+			name: "Including archived budgets",
+			req:  &ListBudgetsRequest{UserID: "user123", IncludeArchived: true},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").Return(budgets, nil)
+			},
+			expectedRes: &ListBudgetsResponse{
+				Budgets: []*BudgetObject{
+					{ID: "budget1", UserID: "user123", Name: "Cash", Currency: "USD", Role: "owner", CreatedAt: createdAt, UpdatedAt: createdAt},
+					{ID: "budget2", UserID: "user123", Name: "Old card", Currency: "USD", Role: "owner", ArchivedAt: &archivedAt, CreatedAt: createdAt, UpdatedAt: createdAt},
+					{ID: "budget3", UserID: "user456", Name: "Household", Currency: "USD", Role: "editor", CreatedAt: createdAt, UpdatedAt: createdAt},
+				},
+			},
+		},
+		{
+			name: "Error listing budgets",
+			req:  &ListBudgetsRequest{UserID: "user123"},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").Return(nil, errors.New("database error"))
+			},
+			expectedErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := service.List(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRes, resp)
+			}
+		})
+	}
+}
+
+func TestGetByID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -156,14 +236,17 @@ func TestGetByUserID(t *testing.T) {
 		{
 			name: "Successful get budget",
 			req: &GetBudgetByIDRequest{
-				UserID: "user123",
+				UserID:   "user123",
+				BudgetID: "budget123",
 			},
 			mockSetup: func() {
-				mockBudgetRepo.EXPECT().GetByUserID(ctx, "user123").
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").
 					Return(&domain.Budget{
 						ID:        "budget123",
 						UserID:    "user123",
+						Name:      "Main",
 						Currency:  "USD",
+						Role:      "viewer",
 						CreatedAt: createdAt,
 						UpdatedAt: updatedAt,
 					}, nil)
@@ -172,7 +255,9 @@ func TestGetByUserID(t *testing.T) {
 				BudgetObject: &BudgetObject{
 					ID:        "budget123",
 					UserID:    "user123",
+					Name:      "Main",
 					Currency:  "USD",
+					Role:      "viewer",
 					CreatedAt: createdAt,
 					UpdatedAt: updatedAt,
 				},
@@ -180,24 +265,26 @@ func TestGetByUserID(t *testing.T) {
 			expectedErr: nil,
 		},
 		{
-			name: "No budget found",
+			name: "Budget of another user",
 			req: &GetBudgetByIDRequest{
-				UserID: "user123",
+				UserID:   "user123",
+				BudgetID: "foreign",
 			},
 			mockSetup: func() {
-				mockBudgetRepo.EXPECT().GetByUserID(ctx, "user123").
+				mockBudgetRepo.EXPECT().GetByID(ctx, "foreign", "user123").
 					Return(nil, sql.ErrNoRows)
 			},
-			expectedRes: &GetBudgetByIDResponse{},
-			expectedErr: nil,
+			expectedRes: nil,
+			expectedErr: errs.BudgetNotFound,
 		},
 		{
 			name: "Error getting budget",
 			req: &GetBudgetByIDRequest{
-				UserID: "user123",
+				UserID:   "user123",
+				BudgetID: "budget123",
 			},
 			mockSetup: func() {
-				mockBudgetRepo.EXPECT().GetByUserID(ctx, "user123").
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").
 					Return(nil, errors.New("database error"))
 			},
 			expectedRes: nil,
@@ -209,7 +296,7 @@ func TestGetByUserID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			resp, err := service.GetByUserID(ctx, tt.req)
+			resp, err := service.GetByID(ctx, tt.req)
 
 			if tt.expectedErr != nil {
 				assert.Error(t, err)
@@ -223,6 +310,122 @@ func TestGetByUserID(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBudgetRepo := mock.NewMockBudget(ctrl)
+	mockBudgetHistoryRepo := mock_budget_history.NewMockBudgetHistory(ctrl)
+	service := NewService(mockBudgetRepo, mockBudgetHistoryRepo, transaction.NewTransactionExecutor())
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+		mockBudgetRepo.EXPECT().Update(ctx, "budget123", "user123", "Savings", "").Return(nil)
+
+		resp, err := service.Update(ctx, &UpdateBudgetRequest{UserID: "user123", BudgetID: "budget123", Name: "Savings"})
+		assert.NoError(t, err)
+		assert.Equal(t, &UpdateBudgetResponse{}, resp)
+	})
+
+	t.Run("Currency locked", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Currency: "USD", Role: "owner"}, nil)
+		mockBudgetRepo.EXPECT().Update(ctx, "budget123", "user123", "", "EUR").Return(budget.ErrCurrencyLocked)
+
+		resp, err := service.Update(ctx, &UpdateBudgetRequest{UserID: "user123", BudgetID: "budget123", Currency: "EUR"})
+		assert.Equal(t, errs.CurrencyLocked, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("Editor cannot update", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user789").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "editor"}, nil)
+
+		resp, err := service.Update(ctx, &UpdateBudgetRequest{UserID: "user789", BudgetID: "budget123", Name: "Savings"})
+		assert.Equal(t, errs.BudgetForbidden, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("Not owned", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").Return(nil, sql.ErrNoRows)
+
+		resp, err := service.Update(ctx, &UpdateBudgetRequest{UserID: "user456", BudgetID: "budget123", Name: "Savings"})
+		assert.Equal(t, errs.BudgetNotFound, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBudgetRepo := mock.NewMockBudget(ctrl)
+	mockBudgetHistoryRepo := mock_budget_history.NewMockBudgetHistory(ctrl)
+	service := NewService(mockBudgetRepo, mockBudgetHistoryRepo, transaction.NewTransactionExecutor())
+	ctx := context.Background()
+
+	t.Run("Archive", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+		mockBudgetRepo.EXPECT().SetArchived(ctx, "budget123", "user123", true).Return(nil)
+
+		resp, err := service.Archive(ctx, &ArchiveBudgetRequest{UserID: "user123", BudgetID: "budget123"})
+		assert.NoError(t, err)
+		assert.Equal(t, &ArchiveBudgetResponse{}, resp)
+	})
+
+	t.Run("Unarchive", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+		mockBudgetRepo.EXPECT().SetArchived(ctx, "budget123", "user123", false).Return(nil)
+
+		resp, err := service.Unarchive(ctx, &UnarchiveBudgetRequest{UserID: "user123", BudgetID: "budget123"})
+		assert.NoError(t, err)
+		assert.Equal(t, &UnarchiveBudgetResponse{}, resp)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+		mockBudgetRepo.EXPECT().SetArchived(ctx, "budget123", "user123", true).Return(errors.New("database error"))
+
+		resp, err := service.Archive(ctx, &ArchiveBudgetRequest{UserID: "user123", BudgetID: "budget123"})
+		assert.EqualError(t, err, "database error")
+		assert.Nil(t, resp)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBudgetRepo := mock.NewMockBudget(ctrl)
+	mockBudgetHistoryRepo := mock_budget_history.NewMockBudgetHistory(ctrl)
+	service := NewService(mockBudgetRepo, mockBudgetHistoryRepo, transaction.NewTransactionExecutor())
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+		mockBudgetRepo.EXPECT().Delete(ctx, "budget123", "user123").Return(nil)
+
+		resp, err := service.Delete(ctx, &DeleteBudgetRequest{UserID: "user123", BudgetID: "budget123"})
+		assert.NoError(t, err)
+		assert.Equal(t, &DeleteBudgetResponse{}, resp)
+	})
+
+	t.Run("Viewer cannot delete", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user789").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "viewer"}, nil)
+
+		resp, err := service.Delete(ctx, &DeleteBudgetRequest{UserID: "user789", BudgetID: "budget123"})
+		assert.Equal(t, errs.BudgetForbidden, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("Not owned", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").Return(nil, sql.ErrNoRows)
+
+		resp, err := service.Delete(ctx, &DeleteBudgetRequest{UserID: "user456", BudgetID: "budget123"})
+		assert.Equal(t, errs.BudgetNotFound, err)
+		assert.Nil(t, resp)
+	})
+}
+
 func TestGetBudgetHistory(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -244,21 +447,23 @@ func TestGetBudgetHistory(t *testing.T) {
 		{
 			name: "Successful get budget history",
 			req: &GetBudgetHistoryRequest{
+				UserID:   "user123",
 				BudgetID: "budget123",
 			},
 			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
 				mockBudgetHistoryRepo.EXPECT().List(ctx, "budget123").
 					Return([]*domain.BudgetHistory{
 						{
 							ID:        "history1",
 							BudgetID:  "budget123",
-							Balance:   100.00,
+							Balance:   domain.MustParseMoney("100.00"),
 							CreatedAt: createdAt,
 						},
 						{
 							ID:        "history2",
 							BudgetID:  "budget123",
-							Balance:   150.00,
+							Balance:   domain.MustParseMoney("150.00"),
 							CreatedAt: createdAt.Add(1 * time.Hour),
 						},
 					}, nil)
@@ -268,13 +473,13 @@ func TestGetBudgetHistory(t *testing.T) {
 					{
 						ID:        "history1",
 						BudgetID:  "budget123",
-						Balance:   100.00,
+						Balance:   domain.MustParseMoney("100.00"),
 						CreatedAt: createdAt,
 					},
 					{
 						ID:        "history2",
 						BudgetID:  "budget123",
-						Balance:   150.00,
+						Balance:   domain.MustParseMoney("150.00"),
 						CreatedAt: createdAt.Add(1 * time.Hour),
 					},
 				},
@@ -284,21 +489,37 @@ func TestGetBudgetHistory(t *testing.T) {
 		{
 			name: "No budget history found",
 			req: &GetBudgetHistoryRequest{
+				UserID:   "user123",
 				BudgetID: "budget123",
 			},
 			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
 				mockBudgetHistoryRepo.EXPECT().List(ctx, "budget123").
 					Return(nil, sql.ErrNoRows)
 			},
 			expectedRes: &GetBudgetHistoryResponse{},
 			expectedErr: nil,
 		},
+		{
+			name: "Budget of another user",
+			req: &GetBudgetHistoryRequest{
+				UserID:   "user456",
+				BudgetID: "budget123",
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").Return(nil, sql.ErrNoRows)
+			},
+			expectedRes: nil,
+			expectedErr: errs.BudgetNotFound,
+		},
 		{
 			name: "Error getting budget history",
 			req: &GetBudgetHistoryRequest{
+				UserID:   "user123",
 				BudgetID: "budget123",
 			},
 			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
 				mockBudgetHistoryRepo.EXPECT().List(ctx, "budget123").
 					Return(nil, errors.New("database error"))
 			},
@@ -350,37 +571,55 @@ func TestGetCurrentBalance(t *testing.T) {
 		{
 			name: "Successful get current balance",
 			req: &GetCurrentBalanceRequest{
+				UserID:   "user123",
 				BudgetID: "budget123",
 			},
 			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
 				mockBudgetHistoryRepo.EXPECT().GetCurrentBalance(ctx, "budget123").
-					Return(150.00, nil)
+					Return(domain.MustParseMoney("150.00"), nil)
 			},
 			expectedRes: &GetCurrentBalanceResponse{
-				Balance: 150.00,
+				Balance: domain.MustParseMoney("150.00"),
 			},
 			expectedErr: nil,
 		},
 		{
 			name: "No balance found",
 			req: &GetCurrentBalanceRequest{
+				UserID:   "user123",
 				BudgetID: "budget123",
 			},
 			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
 				mockBudgetHistoryRepo.EXPECT().GetCurrentBalance(ctx, "budget123").
-					Return(0.0, sql.ErrNoRows)
+					Return(domain.Money{}, sql.ErrNoRows)
 			},
 			expectedRes: &GetCurrentBalanceResponse{},
 			expectedErr: nil,
 		},
+		{
+			name: "Budget of another user",
+			req: &GetCurrentBalanceRequest{
+				UserID:   "user456",
+				BudgetID: "budget123",
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").Return(nil, sql.ErrNoRows)
+			},
+			expectedRes: nil,
+			expectedErr: errs.BudgetNotFound,
+		},
 		{
 			name: "Error getting balance",
 			req: &GetCurrentBalanceRequest{
+				UserID:   "user123",
 				BudgetID: "budget123",
 			},
 			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
 				mockBudgetHistoryRepo.EXPECT().GetCurrentBalance(ctx, "budget123").
-					Return(0.0, errors.New("database error"))
+					Return(domain.Money{}, errors.New("database error"))
 			},
 			expectedRes: nil,
 			expectedErr: errors.New("database error"),
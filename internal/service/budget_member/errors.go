@@ -0,0 +1,26 @@
+package budget_member
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+var errs = struct {
+	BudgetNotFound     *echo.HTTPError
+	Forbidden          *echo.HTTPError
+	MemberNotFound     *echo.HTTPError
+	OwnerCannotLeave   *echo.HTTPError
+	AlreadyMember      *echo.HTTPError
+	InvitationNotFound *echo.HTTPError
+	EmailNotVerified   *echo.HTTPError
+	DatabaseError      *echo.HTTPError
+}{
+	BudgetNotFound:     echo.NewHTTPError(http.StatusNotFound, "Budget not found"),
+	Forbidden:          echo.NewHTTPError(http.StatusForbidden, "Only the budget owner can manage its members"),
+	MemberNotFound:     echo.NewHTTPError(http.StatusNotFound, "Member not found"),
+	OwnerCannotLeave:   echo.NewHTTPError(http.StatusConflict, "The owner can't leave the budget, delete it instead"),
+	AlreadyMember:      echo.NewHTTPError(http.StatusConflict, "The user is already a member of the budget"),
+	InvitationNotFound: echo.NewHTTPError(http.StatusNotFound, "Invitation not found or expired"),
+	EmailNotVerified:   echo.NewHTTPError(http.StatusForbidden, "Verify your email address to see and answer invitations"),
+	DatabaseError:      echo.NewHTTPError(http.StatusInternalServerError, "Database operation failed"),
+}
@@ -0,0 +1,177 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/budget_member/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/budget_member/service.go -destination=internal/service/budget_member/mock/mock_budget_member.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	budget_member "finly-backend/internal/service/budget_member"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBudgetMember is a mock of BudgetMember interface.
+type MockBudgetMember struct {
+	ctrl     *gomock.Controller
+	recorder *MockBudgetMemberMockRecorder
+	isgomock struct{}
+}
+
+// MockBudgetMemberMockRecorder is the mock recorder for MockBudgetMember.
+type MockBudgetMemberMockRecorder struct {
+	mock *MockBudgetMember
+}
+
+// NewMockBudgetMember creates a new mock instance.
+func NewMockBudgetMember(ctrl *gomock.Controller) *MockBudgetMember {
+	mock := &MockBudgetMember{ctrl: ctrl}
+	mock.recorder = &MockBudgetMemberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBudgetMember) EXPECT() *MockBudgetMemberMockRecorder {
+	return m.recorder
+}
+
+// AcceptInvitation mocks base method.
+func (m *MockBudgetMember) AcceptInvitation(ctx context.Context, req *budget_member.RespondInvitationRequest) (*budget_member.AcceptInvitationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvitation", ctx, req)
+	ret0, _ := ret[0].(*budget_member.AcceptInvitationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptInvitation indicates an expected call of AcceptInvitation.
+func (mr *MockBudgetMemberMockRecorder) AcceptInvitation(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockBudgetMember)(nil).AcceptInvitation), ctx, req)
+}
+
+// CreateInvitation mocks base method.
+func (m *MockBudgetMember) CreateInvitation(ctx context.Context, req *budget_member.CreateInvitationRequest) (*budget_member.CreateInvitationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvitation", ctx, req)
+	ret0, _ := ret[0].(*budget_member.CreateInvitationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInvitation indicates an expected call of CreateInvitation.
+func (mr *MockBudgetMemberMockRecorder) CreateInvitation(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvitation", reflect.TypeOf((*MockBudgetMember)(nil).CreateInvitation), ctx, req)
+}
+
+// DeclineInvitation mocks base method.
+func (m *MockBudgetMember) DeclineInvitation(ctx context.Context, req *budget_member.RespondInvitationRequest) (*budget_member.DeclineInvitationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclineInvitation", ctx, req)
+	ret0, _ := ret[0].(*budget_member.DeclineInvitationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclineInvitation indicates an expected call of DeclineInvitation.
+func (mr *MockBudgetMemberMockRecorder) DeclineInvitation(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineInvitation", reflect.TypeOf((*MockBudgetMember)(nil).DeclineInvitation), ctx, req)
+}
+
+// ListBudgetInvitations mocks base method.
+func (m *MockBudgetMember) ListBudgetInvitations(ctx context.Context, req *budget_member.ListBudgetInvitationsRequest) (*budget_member.ListBudgetInvitationsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBudgetInvitations", ctx, req)
+	ret0, _ := ret[0].(*budget_member.ListBudgetInvitationsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBudgetInvitations indicates an expected call of ListBudgetInvitations.
+func (mr *MockBudgetMemberMockRecorder) ListBudgetInvitations(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBudgetInvitations", reflect.TypeOf((*MockBudgetMember)(nil).ListBudgetInvitations), ctx, req)
+}
+
+// ListMembers mocks base method.
+func (m *MockBudgetMember) ListMembers(ctx context.Context, req *budget_member.ListMembersRequest) (*budget_member.ListMembersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, req)
+	ret0, _ := ret[0].(*budget_member.ListMembersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockBudgetMemberMockRecorder) ListMembers(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockBudgetMember)(nil).ListMembers), ctx, req)
+}
+
+// ListMyInvitations mocks base method.
+func (m *MockBudgetMember) ListMyInvitations(ctx context.Context, req *budget_member.ListMyInvitationsRequest) (*budget_member.ListMyInvitationsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMyInvitations", ctx, req)
+	ret0, _ := ret[0].(*budget_member.ListMyInvitationsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMyInvitations indicates an expected call of ListMyInvitations.
+func (mr *MockBudgetMemberMockRecorder) ListMyInvitations(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMyInvitations", reflect.TypeOf((*MockBudgetMember)(nil).ListMyInvitations), ctx, req)
+}
+
+// RemoveMember mocks base method.
+func (m *MockBudgetMember) RemoveMember(ctx context.Context, req *budget_member.RemoveMemberRequest) (*budget_member.RemoveMemberResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, req)
+	ret0, _ := ret[0].(*budget_member.RemoveMemberResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockBudgetMemberMockRecorder) RemoveMember(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockBudgetMember)(nil).RemoveMember), ctx, req)
+}
+
+// RevokeInvitation mocks base method.
+func (m *MockBudgetMember) RevokeInvitation(ctx context.Context, req *budget_member.RevokeInvitationRequest) (*budget_member.RevokeInvitationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeInvitation", ctx, req)
+	ret0, _ := ret[0].(*budget_member.RevokeInvitationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeInvitation indicates an expected call of RevokeInvitation.
+func (mr *MockBudgetMemberMockRecorder) RevokeInvitation(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeInvitation", reflect.TypeOf((*MockBudgetMember)(nil).RevokeInvitation), ctx, req)
+}
+
+// UpdateMember mocks base method.
+func (m *MockBudgetMember) UpdateMember(ctx context.Context, req *budget_member.UpdateMemberRequest) (*budget_member.UpdateMemberResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMember", ctx, req)
+	ret0, _ := ret[0].(*budget_member.UpdateMemberResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMember indicates an expected call of UpdateMember.
+func (mr *MockBudgetMemberMockRecorder) UpdateMember(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMember", reflect.TypeOf((*MockBudgetMember)(nil).UpdateMember), ctx, req)
+}
@@ -0,0 +1,102 @@
+package budget_member
+
+import (
+	"finly-backend/internal/domain/enums/e_budget_role"
+	"time"
+)
+
+type MemberObject struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Role      string    `json:"role"`
+	JoinedAt  time.Time `json:"joined_at"`
+}
+
+type InvitationObject struct {
+	ID           string    `json:"id"`
+	BudgetID     string    `json:"budget_id"`
+	BudgetName   string    `json:"budget_name"`
+	InviterEmail string    `json:"inviter_email"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ListMembersRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	BudgetID string `param:"budget_id" validate:"required"`
+}
+
+type ListMembersResponse struct {
+	Members []*MemberObject `json:"members"`
+}
+
+type UpdateMemberRequest struct {
+	UserID   string             `header:"User-Id" validate:"required"`
+	BudgetID string             `param:"budget_id" validate:"required"`
+	MemberID string             `param:"user_id" validate:"required"`
+	Role     e_budget_role.Enum `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type UpdateMemberResponse struct{}
+
+// RemoveMemberRequest removes a member from the budget. Members other than the owner
+// remove themselves to leave the budget.
+type RemoveMemberRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	BudgetID string `param:"budget_id" validate:"required"`
+	MemberID string `param:"user_id" validate:"required"`
+}
+
+type RemoveMemberResponse struct{}
+
+type CreateInvitationRequest struct {
+	UserID   string             `header:"User-Id" validate:"required"`
+	BudgetID string             `param:"budget_id" validate:"required"`
+	Email    string             `json:"email" validate:"required,email,max=255"`
+	Role     e_budget_role.Enum `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type CreateInvitationResponse struct {
+	ID string `json:"id"`
+}
+
+type ListBudgetInvitationsRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	BudgetID string `param:"budget_id" validate:"required"`
+}
+
+type ListBudgetInvitationsResponse struct {
+	Invitations []*InvitationObject `json:"invitations"`
+}
+
+type RevokeInvitationRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	BudgetID string `param:"budget_id" validate:"required"`
+	ID       string `param:"id" validate:"required,uuid"`
+}
+
+type RevokeInvitationResponse struct{}
+
+type ListMyInvitationsRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+}
+
+type ListMyInvitationsResponse struct {
+	Invitations []*InvitationObject `json:"invitations"`
+}
+
+type RespondInvitationRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	ID     string `param:"id" validate:"required,uuid"`
+}
+
+type AcceptInvitationResponse struct {
+	BudgetID string `json:"budget_id"`
+	Role     string `json:"role"`
+}
+
+type DeclineInvitationResponse struct{}
@@ -0,0 +1,301 @@
+package budget_member
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_budget_role"
+	"finly-backend/internal/repository/auth"
+	"finly-backend/internal/repository/budget"
+	"finly-backend/internal/repository/budget_invitation"
+	"finly-backend/pkg/mailer"
+	"finly-backend/pkg/transaction"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// invitationTTL is how long an invitation can be answered.
+const invitationTTL = 7 * 24 * time.Hour
+
+type BudgetMember interface {
+	ListMembers(ctx context.Context, req *ListMembersRequest) (*ListMembersResponse, error)
+	UpdateMember(ctx context.Context, req *UpdateMemberRequest) (*UpdateMemberResponse, error)
+	RemoveMember(ctx context.Context, req *RemoveMemberRequest) (*RemoveMemberResponse, error)
+	CreateInvitation(ctx context.Context, req *CreateInvitationRequest) (*CreateInvitationResponse, error)
+	ListBudgetInvitations(ctx context.Context, req *ListBudgetInvitationsRequest) (*ListBudgetInvitationsResponse, error)
+	RevokeInvitation(ctx context.Context, req *RevokeInvitationRequest) (*RevokeInvitationResponse, error)
+	ListMyInvitations(ctx context.Context, req *ListMyInvitationsRequest) (*ListMyInvitationsResponse, error)
+	AcceptInvitation(ctx context.Context, req *RespondInvitationRequest) (*AcceptInvitationResponse, error)
+	DeclineInvitation(ctx context.Context, req *RespondInvitationRequest) (*DeclineInvitationResponse, error)
+}
+
+type Service struct {
+	budgetRepo     budget.Budget
+	invitationRepo budget_invitation.BudgetInvitation
+	authRepo       auth.Auth
+
+	mailer mailer.Mailer
+	appURL string
+
+	transactionExecutor transaction.TransactionExecutor
+}
+
+func NewService(budgetRepo budget.Budget, invitationRepo budget_invitation.BudgetInvitation, authRepo auth.Auth, mail mailer.Mailer, appURL string, transactionExecutor transaction.TransactionExecutor) *Service {
+	return &Service{
+		budgetRepo:     budgetRepo,
+		invitationRepo: invitationRepo,
+		authRepo:       authRepo,
+
+		mailer: mail,
+		appURL: strings.TrimSuffix(appURL, "/"),
+
+		transactionExecutor: transactionExecutor,
+	}
+}
+
+func (s *Service) ListMembers(ctx context.Context, req *ListMembersRequest) (*ListMembersResponse, error) {
+	if _, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Viewer); err != nil {
+		return nil, err
+	}
+
+	members, err := s.budgetRepo.ListMembers(ctx, req.BudgetID)
+	if err != nil {
+		zap.L().Sugar().Errorf("ListMembers: failed for budgetID=%s: %v", req.BudgetID, err)
+		return nil, errs.DatabaseError
+	}
+
+	list := make([]*MemberObject, 0, len(members))
+	for _, m := range members {
+		list = append(list, convertMember(m))
+	}
+	return &ListMembersResponse{Members: list}, nil
+}
+
+// UpdateMember changes the role of a member. The owner's role can't be changed.
+func (s *Service) UpdateMember(ctx context.Context, req *UpdateMemberRequest) (*UpdateMemberResponse, error) {
+	if _, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Owner); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.budgetRepo.UpdateMemberRole(ctx, req.BudgetID, req.MemberID, req.Role.String())
+	if err != nil {
+		zap.L().Sugar().Errorf("UpdateMember: failed for budgetID=%s, memberID=%s: %v", req.BudgetID, req.MemberID, err)
+		return nil, errs.DatabaseError
+	}
+	if !updated {
+		return nil, errs.MemberNotFound
+	}
+
+	zap.L().Sugar().Infof("UpdateMember: memberID=%s of budgetID=%s is now %s", req.MemberID, req.BudgetID, req.Role)
+	return &UpdateMemberResponse{}, nil
+}
+
+// RemoveMember lets the owner remove any other member and any other member leave.
+// Transactions the member created stay in the budget.
+func (s *Service) RemoveMember(ctx context.Context, req *RemoveMemberRequest) (*RemoveMemberResponse, error) {
+	required := e_budget_role.Owner
+	if req.MemberID == req.UserID {
+		required = e_budget_role.Viewer
+	}
+
+	b, err := s.getBudget(ctx, req.BudgetID, req.UserID, required)
+	if err != nil {
+		return nil, err
+	}
+	if req.MemberID == b.UserID {
+		return nil, errs.OwnerCannotLeave
+	}
+
+	removed, err := s.budgetRepo.RemoveMember(ctx, req.BudgetID, req.MemberID)
+	if err != nil {
+		zap.L().Sugar().Errorf("RemoveMember: failed for budgetID=%s, memberID=%s: %v", req.BudgetID, req.MemberID, err)
+		return nil, errs.DatabaseError
+	}
+	if !removed {
+		return nil, errs.MemberNotFound
+	}
+
+	zap.L().Sugar().Infof("RemoveMember: memberID=%s removed from budgetID=%s by userID=%s", req.MemberID, req.BudgetID, req.UserID)
+	return &RemoveMemberResponse{}, nil
+}
+
+// CreateInvitation invites an email to the budget and mails the invitee. Inviting an email
+// again renews its pending invitation.
+func (s *Service) CreateInvitation(ctx context.Context, req *CreateInvitationRequest) (*CreateInvitationResponse, error) {
+	b, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.budgetRepo.ListMembers(ctx, req.BudgetID)
+	if err != nil {
+		zap.L().Sugar().Errorf("CreateInvitation: failed to list members of budgetID=%s: %v", req.BudgetID, err)
+		return nil, errs.DatabaseError
+	}
+	for _, m := range members {
+		if strings.EqualFold(m.Email, req.Email) {
+			return nil, errs.AlreadyMember
+		}
+	}
+
+	inviter, err := s.authRepo.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		zap.L().Sugar().Errorf("CreateInvitation: failed to get userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	id, err := s.invitationRepo.Create(ctx, req.BudgetID, req.UserID, req.Email, req.Role.String(), time.Now().UTC().Add(invitationTTL))
+	if err != nil {
+		zap.L().Sugar().Errorf("CreateInvitation: failed for budgetID=%s: %v", req.BudgetID, err)
+		return nil, errs.DatabaseError
+	}
+
+	if err = s.mailer.Send(ctx, invitationMessage(req.Email, inviter, b, req.Role.String(), s.appURL+"/invitations")); err != nil {
+		zap.L().Sugar().Errorf("CreateInvitation: failed to mail invitationID=%s: %v", id, err)
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("CreateInvitation: invitationID=%s sent for budgetID=%s", id, req.BudgetID)
+	return &CreateInvitationResponse{ID: id}, nil
+}
+
+func (s *Service) ListBudgetInvitations(ctx context.Context, req *ListBudgetInvitationsRequest) (*ListBudgetInvitationsResponse, error) {
+	if _, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Owner); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.ListPendingByBudget(ctx, req.BudgetID, time.Now().UTC())
+	if err != nil {
+		zap.L().Sugar().Errorf("ListBudgetInvitations: failed for budgetID=%s: %v", req.BudgetID, err)
+		return nil, errs.DatabaseError
+	}
+
+	return &ListBudgetInvitationsResponse{Invitations: convertInvitations(invitations)}, nil
+}
+
+func (s *Service) RevokeInvitation(ctx context.Context, req *RevokeInvitationRequest) (*RevokeInvitationResponse, error) {
+	if _, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Owner); err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.invitationRepo.Delete(ctx, req.ID, req.BudgetID)
+	if err != nil {
+		zap.L().Sugar().Errorf("RevokeInvitation: failed for invitationID=%s: %v", req.ID, err)
+		return nil, errs.DatabaseError
+	}
+	if !deleted {
+		return nil, errs.InvitationNotFound
+	}
+
+	zap.L().Sugar().Infof("RevokeInvitation: invitationID=%s revoked by userID=%s", req.ID, req.UserID)
+	return &RevokeInvitationResponse{}, nil
+}
+
+// ListMyInvitations lists the pending invitations sent to the verified email of the user.
+func (s *Service) ListMyInvitations(ctx context.Context, req *ListMyInvitationsRequest) (*ListMyInvitationsResponse, error) {
+	user, err := s.getVerifiedUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.ListPendingByEmail(ctx, user.Email, time.Now().UTC())
+	if err != nil {
+		zap.L().Sugar().Errorf("ListMyInvitations: failed for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	return &ListMyInvitationsResponse{Invitations: convertInvitations(invitations)}, nil
+}
+
+// AcceptInvitation makes the user a member of the budget with the role they were invited as.
+func (s *Service) AcceptInvitation(ctx context.Context, req *RespondInvitationRequest) (*AcceptInvitationResponse, error) {
+	var invitation *domain.BudgetInvitation
+	err := s.respond(ctx, req, budget_invitation.StatusAccepted, func(tx *sqlx.Tx, responded *domain.BudgetInvitation) error {
+		invitation = responded
+		return s.budgetRepo.AddMemberTX(ctx, tx, responded.BudgetID, req.UserID, responded.Role)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.budgetRepo.InvalidateCache(ctx, req.UserID, invitation.BudgetID); err != nil {
+		zap.L().Sugar().Warnf("AcceptInvitation: failed to invalidate cache for userID=%s: %v", req.UserID, err)
+	}
+
+	zap.L().Sugar().Infof("AcceptInvitation: userID=%s joined budgetID=%s as %s", req.UserID, invitation.BudgetID, invitation.Role)
+	return &AcceptInvitationResponse{BudgetID: invitation.BudgetID, Role: invitation.Role}, nil
+}
+
+func (s *Service) DeclineInvitation(ctx context.Context, req *RespondInvitationRequest) (*DeclineInvitationResponse, error) {
+	if err := s.respond(ctx, req, budget_invitation.StatusDeclined, nil); err != nil {
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("DeclineInvitation: invitationID=%s declined by userID=%s", req.ID, req.UserID)
+	return &DeclineInvitationResponse{}, nil
+}
+
+// respond answers an invitation sent to the verified email of the user and runs then, if
+// set, in the same database transaction.
+func (s *Service) respond(ctx context.Context, req *RespondInvitationRequest, status string, then func(tx *sqlx.Tx, invitation *domain.BudgetInvitation) error) error {
+	user, err := s.getVerifiedUser(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.transactionExecutor.WithTransaction(ctx, s.budgetRepo.GetDB(), func(tx *sqlx.Tx) error {
+		invitation, err := s.invitationRepo.RespondTX(ctx, tx, req.ID, user.Email, status, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if then == nil {
+			return nil
+		}
+		return then(tx, invitation)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			zap.L().Sugar().Warnf("invitationID=%s can't be answered by userID=%s", req.ID, req.UserID)
+			return errs.InvitationNotFound
+		}
+		zap.L().Sugar().Errorf("failed to set status=%s of invitationID=%s: %v", status, req.ID, err)
+		return errs.DatabaseError
+	}
+	return nil
+}
+
+// getVerifiedUser loads the user only if they verified their email. Invitations are matched
+// by email, so an unverified address could claim invitations meant for someone else.
+func (s *Service) getVerifiedUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		zap.L().Sugar().Errorf("failed to get userID=%s: %v", userID, err)
+		return nil, errs.DatabaseError
+	}
+	if !user.EmailVerifiedAt.Valid {
+		zap.L().Sugar().Warnf("userID=%s with an unverified email asked for invitations", userID)
+		return nil, errs.EmailNotVerified
+	}
+	return user, nil
+}
+
+// getBudget loads the budget only if the user is a member of it, so a foreign budget ID is
+// indistinguishable from a missing one.
+func (s *Service) getBudget(ctx context.Context, budgetID, userID string, required e_budget_role.Enum) (*domain.Budget, error) {
+	b, err := s.budgetRepo.GetByID(ctx, budgetID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			zap.L().Sugar().Warnf("budget not found for budgetID=%s, userID=%s", budgetID, userID)
+			return nil, errs.BudgetNotFound
+		}
+		zap.L().Sugar().Errorf("failed to get budgetID=%s for userID=%s: %v", budgetID, userID, err)
+		return nil, errs.DatabaseError
+	}
+	if !e_budget_role.Enum(b.Role).Allows(required) {
+		return nil, errs.Forbidden
+	}
+	return b, nil
+}
@@ -0,0 +1,388 @@
+package budget_member
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_budget_role"
+	mock_auth "finly-backend/internal/repository/auth/mock"
+	mock_budget "finly-backend/internal/repository/budget/mock"
+	"finly-backend/internal/repository/budget_invitation"
+	mock_budget_invitation "finly-backend/internal/repository/budget_invitation/mock"
+	"finly-backend/pkg/mailer"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type mockTransactionExecutor struct{}
+
+func (m *mockTransactionExecutor) WithTransaction(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	return fn(nil)
+}
+
+func budgetAs(role e_budget_role.Enum) *domain.Budget {
+	return &domain.Budget{ID: "budget123", UserID: "owner1", Name: "Household", Role: role.String()}
+}
+
+func TestListMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	service := NewService(mockBudgetRepo, nil, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	joinedAt := time.Now()
+
+	t.Run("Viewer lists members", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budgetAs(e_budget_role.Viewer), nil)
+		mockBudgetRepo.EXPECT().ListMembers(ctx, "budget123").Return([]*domain.BudgetMember{
+			{BudgetID: "budget123", UserID: "owner1", Role: "owner", Email: "owner@example.com", FirstName: "Ann", CreatedAt: joinedAt},
+			{BudgetID: "budget123", UserID: "user123", Role: "viewer", Email: "test@example.com", FirstName: "John", CreatedAt: joinedAt},
+		}, nil)
+
+		resp, err := service.ListMembers(ctx, &ListMembersRequest{UserID: "user123", BudgetID: "budget123"})
+		assert.NoError(t, err)
+		assert.Equal(t, []*MemberObject{
+			{UserID: "owner1", Email: "owner@example.com", FirstName: "Ann", Role: "owner", JoinedAt: joinedAt},
+			{UserID: "user123", Email: "test@example.com", FirstName: "John", Role: "viewer", JoinedAt: joinedAt},
+		}, resp.Members)
+	})
+
+	t.Run("Not a member", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").Return(nil, sql.ErrNoRows)
+
+		resp, err := service.ListMembers(ctx, &ListMembersRequest{UserID: "user456", BudgetID: "budget123"})
+		assert.Equal(t, errs.BudgetNotFound, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestUpdateMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	service := NewService(mockBudgetRepo, nil, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		req         *UpdateMemberRequest
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Owner promotes a viewer",
+			req:  &UpdateMemberRequest{UserID: "owner1", BudgetID: "budget123", MemberID: "user123", Role: e_budget_role.Editor},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "owner1").Return(budgetAs(e_budget_role.Owner), nil)
+				mockBudgetRepo.EXPECT().UpdateMemberRole(ctx, "budget123", "user123", "editor").Return(true, nil)
+			},
+		},
+		{
+			name: "Editor cannot change roles",
+			req:  &UpdateMemberRequest{UserID: "user123", BudgetID: "budget123", MemberID: "user456", Role: e_budget_role.Editor},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budgetAs(e_budget_role.Editor), nil)
+			},
+			expectedErr: errs.Forbidden,
+		},
+		{
+			name: "Unknown member",
+			req:  &UpdateMemberRequest{UserID: "owner1", BudgetID: "budget123", MemberID: "owner1", Role: e_budget_role.Viewer},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "owner1").Return(budgetAs(e_budget_role.Owner), nil)
+				mockBudgetRepo.EXPECT().UpdateMemberRole(ctx, "budget123", "owner1", "viewer").Return(false, nil)
+			},
+			expectedErr: errs.MemberNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := service.UpdateMember(ctx, tt.req)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &UpdateMemberResponse{}, resp)
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	service := NewService(mockBudgetRepo, nil, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		req         *RemoveMemberRequest
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Owner removes a member",
+			req:  &RemoveMemberRequest{UserID: "owner1", BudgetID: "budget123", MemberID: "user123"},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "owner1").Return(budgetAs(e_budget_role.Owner), nil)
+				mockBudgetRepo.EXPECT().RemoveMember(ctx, "budget123", "user123").Return(true, nil)
+			},
+		},
+		{
+			name: "Viewer leaves",
+			req:  &RemoveMemberRequest{UserID: "user123", BudgetID: "budget123", MemberID: "user123"},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budgetAs(e_budget_role.Viewer), nil)
+				mockBudgetRepo.EXPECT().RemoveMember(ctx, "budget123", "user123").Return(true, nil)
+			},
+		},
+		{
+			name: "Editor cannot remove others",
+			req:  &RemoveMemberRequest{UserID: "user123", BudgetID: "budget123", MemberID: "user456"},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budgetAs(e_budget_role.Editor), nil)
+			},
+			expectedErr: errs.Forbidden,
+		},
+		{
+			name: "Owner cannot leave",
+			req:  &RemoveMemberRequest{UserID: "owner1", BudgetID: "budget123", MemberID: "owner1"},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "owner1").Return(budgetAs(e_budget_role.Owner), nil)
+			},
+			expectedErr: errs.OwnerCannotLeave,
+		},
+		{
+			name: "Unknown member",
+			req:  &RemoveMemberRequest{UserID: "owner1", BudgetID: "budget123", MemberID: "user789"},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "owner1").Return(budgetAs(e_budget_role.Owner), nil)
+				mockBudgetRepo.EXPECT().RemoveMember(ctx, "budget123", "user789").Return(false, nil)
+			},
+			expectedErr: errs.MemberNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := service.RemoveMember(ctx, tt.req)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &RemoveMemberResponse{}, resp)
+		})
+	}
+}
+
+func TestCreateInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockInvitationRepo := mock_budget_invitation.NewMockBudgetInvitation(ctrl)
+	mockAuthRepo := mock_auth.NewMockAuth(ctrl)
+	ctx := context.Background()
+
+	owner := &domain.User{ID: "owner1", Email: "owner@example.com", FirstName: "Ann", LastName: "Smith"}
+	members := []*domain.BudgetMember{
+		{BudgetID: "budget123", UserID: "owner1", Role: "owner", Email: "owner@example.com"},
+		{BudgetID: "budget123", UserID: "user123", Role: "viewer", Email: "test@example.com"},
+	}
+
+	t.Run("Invitation mailed", func(t *testing.T) {
+		outbox := mailer.NewMemoryMailer()
+		service := NewService(mockBudgetRepo, mockInvitationRepo, mockAuthRepo, outbox, "https://finly.click/", &mockTransactionExecutor{})
+
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "owner1").Return(budgetAs(e_budget_role.Owner), nil)
+		mockBudgetRepo.EXPECT().ListMembers(ctx, "budget123").Return(members, nil)
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "owner1").Return(owner, nil)
+		mockInvitationRepo.EXPECT().Create(ctx, "budget123", "owner1", "partner@example.com", "editor", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _, _, _ string, expiresAt time.Time) (string, error) {
+				assert.WithinDuration(t, time.Now().Add(invitationTTL), expiresAt, time.Minute)
+				return "invitation1", nil
+			})
+
+		resp, err := service.CreateInvitation(ctx, &CreateInvitationRequest{
+			UserID: "owner1", BudgetID: "budget123", Email: "partner@example.com", Role: e_budget_role.Editor,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &CreateInvitationResponse{ID: "invitation1"}, resp)
+
+		sent := outbox.Sent()
+		if assert.Len(t, sent, 1) {
+			assert.Equal(t, "partner@example.com", sent[0].To)
+			assert.Contains(t, sent[0].Body, `the budget "Household" as editor`)
+			assert.Contains(t, sent[0].Body, "https://finly.click/invitations")
+		}
+	})
+
+	t.Run("Email already a member", func(t *testing.T) {
+		service := NewService(mockBudgetRepo, mockInvitationRepo, mockAuthRepo, mailer.NewMemoryMailer(), "", &mockTransactionExecutor{})
+
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "owner1").Return(budgetAs(e_budget_role.Owner), nil)
+		mockBudgetRepo.EXPECT().ListMembers(ctx, "budget123").Return(members, nil)
+
+		resp, err := service.CreateInvitation(ctx, &CreateInvitationRequest{
+			UserID: "owner1", BudgetID: "budget123", Email: "Test@Example.com", Role: e_budget_role.Viewer,
+		})
+		assert.Equal(t, errs.AlreadyMember, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("Editor cannot invite", func(t *testing.T) {
+		service := NewService(mockBudgetRepo, mockInvitationRepo, mockAuthRepo, mailer.NewMemoryMailer(), "", &mockTransactionExecutor{})
+
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budgetAs(e_budget_role.Editor), nil)
+
+		resp, err := service.CreateInvitation(ctx, &CreateInvitationRequest{
+			UserID: "user123", BudgetID: "budget123", Email: "partner@example.com", Role: e_budget_role.Viewer,
+		})
+		assert.Equal(t, errs.Forbidden, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestRevokeInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockInvitationRepo := mock_budget_invitation.NewMockBudgetInvitation(ctrl)
+	service := NewService(mockBudgetRepo, mockInvitationRepo, nil, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	t.Run("Revoked", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "owner1").Return(budgetAs(e_budget_role.Owner), nil)
+		mockInvitationRepo.EXPECT().Delete(ctx, "invitation1", "budget123").Return(true, nil)
+
+		resp, err := service.RevokeInvitation(ctx, &RevokeInvitationRequest{UserID: "owner1", BudgetID: "budget123", ID: "invitation1"})
+		assert.NoError(t, err)
+		assert.Equal(t, &RevokeInvitationResponse{}, resp)
+	})
+
+	t.Run("Already answered", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "owner1").Return(budgetAs(e_budget_role.Owner), nil)
+		mockInvitationRepo.EXPECT().Delete(ctx, "invitation1", "budget123").Return(false, nil)
+
+		resp, err := service.RevokeInvitation(ctx, &RevokeInvitationRequest{UserID: "owner1", BudgetID: "budget123", ID: "invitation1"})
+		assert.Equal(t, errs.InvitationNotFound, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestListMyInvitations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockInvitationRepo := mock_budget_invitation.NewMockBudgetInvitation(ctrl)
+	mockAuthRepo := mock_auth.NewMockAuth(ctrl)
+	service := NewService(nil, mockInvitationRepo, mockAuthRepo, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(invitationTTL)
+	verified := sql.NullTime{Time: time.Now(), Valid: true}
+	mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(&domain.User{ID: "user123", Email: "test@example.com", EmailVerifiedAt: verified}, nil)
+	mockInvitationRepo.EXPECT().ListPendingByEmail(ctx, "test@example.com", gomock.Any()).Return([]*domain.BudgetInvitation{{
+		ID: "invitation1", BudgetID: "budget123", BudgetName: "Household", InvitedBy: "owner1", InviterEmail: "owner@example.com",
+		Email: "test@example.com", Role: "viewer", Status: budget_invitation.StatusPending, ExpiresAt: expiresAt,
+	}}, nil)
+
+	resp, err := service.ListMyInvitations(ctx, &ListMyInvitationsRequest{UserID: "user123"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*InvitationObject{{
+		ID: "invitation1", BudgetID: "budget123", BudgetName: "Household", InviterEmail: "owner@example.com",
+		Email: "test@example.com", Role: "viewer", ExpiresAt: expiresAt,
+	}}, resp.Invitations)
+
+	t.Run("Email not verified", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(&domain.User{ID: "user123", Email: "test@example.com"}, nil)
+
+		resp, err := service.ListMyInvitations(ctx, &ListMyInvitationsRequest{UserID: "user123"})
+		assert.Equal(t, errs.EmailNotVerified, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestRespondInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockInvitationRepo := mock_budget_invitation.NewMockBudgetInvitation(ctrl)
+	mockAuthRepo := mock_auth.NewMockAuth(ctrl)
+	service := NewService(mockBudgetRepo, mockInvitationRepo, mockAuthRepo, nil, "", &mockTransactionExecutor{})
+	ctx := context.Background()
+
+	user := &domain.User{ID: "user123", Email: "test@example.com", EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+	invitation := &domain.BudgetInvitation{ID: "invitation1", BudgetID: "budget123", Email: "test@example.com", Role: "editor"}
+	req := &RespondInvitationRequest{UserID: "user123", ID: "invitation1"}
+
+	t.Run("Accepted", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockBudgetRepo.EXPECT().GetDB().Return(nil)
+		mockInvitationRepo.EXPECT().RespondTX(ctx, nil, "invitation1", "test@example.com", budget_invitation.StatusAccepted, gomock.Any()).Return(invitation, nil)
+		mockBudgetRepo.EXPECT().AddMemberTX(ctx, nil, "budget123", "user123", "editor").Return(nil)
+		mockBudgetRepo.EXPECT().InvalidateCache(ctx, "user123", "budget123").Return(nil)
+
+		resp, err := service.AcceptInvitation(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, &AcceptInvitationResponse{BudgetID: "budget123", Role: "editor"}, resp)
+	})
+
+	t.Run("Accepting an expired or foreign invitation", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockBudgetRepo.EXPECT().GetDB().Return(nil)
+		mockInvitationRepo.EXPECT().RespondTX(ctx, nil, "invitation1", "test@example.com", budget_invitation.StatusAccepted, gomock.Any()).Return(nil, sql.ErrNoRows)
+
+		resp, err := service.AcceptInvitation(ctx, req)
+		assert.Equal(t, errs.InvitationNotFound, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("Membership not stored", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockBudgetRepo.EXPECT().GetDB().Return(nil)
+		mockInvitationRepo.EXPECT().RespondTX(ctx, nil, "invitation1", "test@example.com", budget_invitation.StatusAccepted, gomock.Any()).Return(invitation, nil)
+		mockBudgetRepo.EXPECT().AddMemberTX(ctx, nil, "budget123", "user123", "editor").Return(errors.New("db error"))
+
+		resp, err := service.AcceptInvitation(ctx, req)
+		assert.Equal(t, errs.DatabaseError, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("Email not verified", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(&domain.User{ID: "user123", Email: "test@example.com"}, nil)
+
+		resp, err := service.AcceptInvitation(ctx, req)
+		assert.Equal(t, errs.EmailNotVerified, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("Declined", func(t *testing.T) {
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockBudgetRepo.EXPECT().GetDB().Return(nil)
+		mockInvitationRepo.EXPECT().RespondTX(ctx, nil, "invitation1", "test@example.com", budget_invitation.StatusDeclined, gomock.Any()).Return(invitation, nil)
+
+		resp, err := service.DeclineInvitation(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, &DeclineInvitationResponse{}, resp)
+	})
+}
@@ -0,0 +1,49 @@
+package budget_member
+
+import (
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/mailer"
+	"fmt"
+)
+
+func convertMember(member *domain.BudgetMember) *MemberObject {
+	return &MemberObject{
+		UserID:    member.UserID,
+		Email:     member.Email,
+		FirstName: member.FirstName,
+		LastName:  member.LastName,
+		Role:      member.Role,
+		JoinedAt:  member.CreatedAt,
+	}
+}
+
+func convertInvitations(invitations []*domain.BudgetInvitation) []*InvitationObject {
+	list := make([]*InvitationObject, 0, len(invitations))
+	for _, i := range invitations {
+		list = append(list, &InvitationObject{
+			ID:           i.ID,
+			BudgetID:     i.BudgetID,
+			BudgetName:   i.BudgetName,
+			InviterEmail: i.InviterEmail,
+			Email:        i.Email,
+			Role:         i.Role,
+			ExpiresAt:    i.ExpiresAt,
+			CreatedAt:    i.CreatedAt,
+		})
+	}
+	return list
+}
+
+// invitationMessage is sent whether or not the email belongs to an account yet; the
+// invitation waits for whoever signs in with it.
+func invitationMessage(email string, inviter *domain.User, budget *domain.Budget, role, link string) mailer.Message {
+	return mailer.Message{
+		To:      email,
+		Subject: fmt.Sprintf("%s shared a budget with you on Finly", inviter.FirstName),
+		Body: fmt.Sprintf("Hi,\n\n"+
+			"%s %s (%s) invited you to the budget %q as %s.\n\n"+
+			"Sign in to Finly with this email, or create an account with it, to accept the invitation within %d days:\n\n%s\n\n"+
+			"If you don't know %s, you can ignore this email.\n",
+			inviter.FirstName, inviter.LastName, inviter.Email, budget.Name, role, int(invitationTTL.Hours()/24), link, inviter.FirstName),
+	}
+}
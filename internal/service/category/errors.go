@@ -11,10 +11,26 @@ var errs = struct {
 	TokenBlacklisted   *echo.HTTPError
 	InvalidToken       *echo.HTTPError
 	UserNotFound       *echo.HTTPError
+	CategoryNotFound   *echo.HTTPError
+	ParentNotFound     *echo.HTTPError
+	CategoryCycle      *echo.HTTPError
+	CategoryInUse      *echo.HTTPError
+	TargetNotFound     *echo.HTTPError
+	SameCategory       *echo.HTTPError
+	DuplicateCategory  *echo.HTTPError
+	DatabaseError      *echo.HTTPError
 }{
 	UserAlreadyExists:  echo.NewHTTPError(http.StatusConflict, "User already exists"),
 	InvalidCredentials: echo.NewHTTPError(http.StatusUnauthorized, "Invalid credentials"),
 	TokenBlacklisted:   echo.NewHTTPError(http.StatusForbidden, "Token is blacklisted"),
 	InvalidToken:       echo.NewHTTPError(http.StatusUnauthorized, "Invalid token"),
 	UserNotFound:       echo.NewHTTPError(http.StatusNotFound, "User not found"),
+	CategoryNotFound:   echo.NewHTTPError(http.StatusNotFound, "Category not found"),
+	ParentNotFound:     echo.NewHTTPError(http.StatusNotFound, "Parent category not found"),
+	CategoryCycle:      echo.NewHTTPError(http.StatusBadRequest, "A category cannot be moved under itself or one of its subcategories"),
+	CategoryInUse:      echo.NewHTTPError(http.StatusConflict, "Category is used by transactions; pass reassign_to to move them to another category"),
+	TargetNotFound:     echo.NewHTTPError(http.StatusNotFound, "Target category not found"),
+	SameCategory:       echo.NewHTTPError(http.StatusBadRequest, "A category cannot be merged into itself"),
+	DuplicateCategory:  echo.NewHTTPError(http.StatusBadRequest, "A category is listed more than once"),
+	DatabaseError:      echo.NewHTTPError(http.StatusInternalServerError, "Database operation failed"),
 }
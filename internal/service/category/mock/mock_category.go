@@ -14,6 +14,7 @@ import (
 	category "finly-backend/internal/service/category"
 	reflect "reflect"
 
+	sqlx "github.com/jmoiron/sqlx"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -115,3 +116,170 @@ func (mr *MockCategoryMockRecorder) ListCustom(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustom", reflect.TypeOf((*MockCategory)(nil).ListCustom), ctx, req)
 }
+
+// Merge mocks base method.
+func (m *MockCategory) Merge(ctx context.Context, req *category.MergeCategoryRequest) (*category.MergeCategoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, req)
+	ret0, _ := ret[0].(*category.MergeCategoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockCategoryMockRecorder) Merge(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockCategory)(nil).Merge), ctx, req)
+}
+
+// Move mocks base method.
+func (m *MockCategory) Move(ctx context.Context, req *category.MoveCategoryRequest) (*category.MoveCategoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, req)
+	ret0, _ := ret[0].(*category.MoveCategoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockCategoryMockRecorder) Move(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockCategory)(nil).Move), ctx, req)
+}
+
+// Reorder mocks base method.
+func (m *MockCategory) Reorder(ctx context.Context, req *category.ReorderCategoriesRequest) (*category.ReorderCategoriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, req)
+	ret0, _ := ret[0].(*category.ReorderCategoriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockCategoryMockRecorder) Reorder(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockCategory)(nil).Reorder), ctx, req)
+}
+
+// ResetOverride mocks base method.
+func (m *MockCategory) ResetOverride(ctx context.Context, req *category.ResetCategoryOverrideRequest) (*category.ResetCategoryOverrideResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetOverride", ctx, req)
+	ret0, _ := ret[0].(*category.ResetCategoryOverrideResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetOverride indicates an expected call of ResetOverride.
+func (mr *MockCategoryMockRecorder) ResetOverride(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetOverride", reflect.TypeOf((*MockCategory)(nil).ResetOverride), ctx, req)
+}
+
+// SetHidden mocks base method.
+func (m *MockCategory) SetHidden(ctx context.Context, req *category.SetCategoryHiddenRequest) (*category.SetCategoryHiddenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHidden", ctx, req)
+	ret0, _ := ret[0].(*category.SetCategoryHiddenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetHidden indicates an expected call of SetHidden.
+func (mr *MockCategoryMockRecorder) SetHidden(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHidden", reflect.TypeOf((*MockCategory)(nil).SetHidden), ctx, req)
+}
+
+// Update mocks base method.
+func (m *MockCategory) Update(ctx context.Context, req *category.UpdateCategoryRequest) (*category.UpdateCategoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, req)
+	ret0, _ := ret[0].(*category.UpdateCategoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCategoryMockRecorder) Update(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCategory)(nil).Update), ctx, req)
+}
+
+// MockCategoryReference is a mock of CategoryReference interface.
+type MockCategoryReference struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryReferenceMockRecorder
+	isgomock struct{}
+}
+
+// MockCategoryReferenceMockRecorder is the mock recorder for MockCategoryReference.
+type MockCategoryReferenceMockRecorder struct {
+	mock *MockCategoryReference
+}
+
+// NewMockCategoryReference creates a new mock instance.
+func NewMockCategoryReference(ctrl *gomock.Controller) *MockCategoryReference {
+	mock := &MockCategoryReference{ctrl: ctrl}
+	mock.recorder = &MockCategoryReferenceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryReference) EXPECT() *MockCategoryReferenceMockRecorder {
+	return m.recorder
+}
+
+// ReassignCategoryTX mocks base method.
+func (m *MockCategoryReference) ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignCategoryTX", ctx, tx, fromCategoryID, toCategoryID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReassignCategoryTX indicates an expected call of ReassignCategoryTX.
+func (mr *MockCategoryReferenceMockRecorder) ReassignCategoryTX(ctx, tx, fromCategoryID, toCategoryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignCategoryTX", reflect.TypeOf((*MockCategoryReference)(nil).ReassignCategoryTX), ctx, tx, fromCategoryID, toCategoryID)
+}
+
+// MockVersionedReference is a mock of VersionedReference interface.
+type MockVersionedReference struct {
+	ctrl     *gomock.Controller
+	recorder *MockVersionedReferenceMockRecorder
+	isgomock struct{}
+}
+
+// MockVersionedReferenceMockRecorder is the mock recorder for MockVersionedReference.
+type MockVersionedReferenceMockRecorder struct {
+	mock *MockVersionedReference
+}
+
+// NewMockVersionedReference creates a new mock instance.
+func NewMockVersionedReference(ctrl *gomock.Controller) *MockVersionedReference {
+	mock := &MockVersionedReference{ctrl: ctrl}
+	mock.recorder = &MockVersionedReferenceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVersionedReference) EXPECT() *MockVersionedReferenceMockRecorder {
+	return m.recorder
+}
+
+// BumpVersion mocks base method.
+func (m *MockVersionedReference) BumpVersion(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BumpVersion", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BumpVersion indicates an expected call of BumpVersion.
+func (mr *MockVersionedReferenceMockRecorder) BumpVersion(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BumpVersion", reflect.TypeOf((*MockVersionedReference)(nil).BumpVersion), ctx, userID)
+}
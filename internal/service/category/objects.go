@@ -6,11 +6,16 @@ import (
 )
 
 type CategoryObject struct {
-	ID             string    `json:"id"`
-	UserID         string    `json:"user_id"`
-	Name           string    `json:"name" validate:"required"`
-	IsUserCategory bool      `json:"is_user_category"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID             string           `json:"id"`
+	UserID         string           `json:"user_id"`
+	ParentID       string           `json:"parent_id,omitempty"`
+	Name           string           `json:"name" validate:"required,max=255"`
+	Icon           string           `json:"icon,omitempty" validate:"omitempty,max=64"`
+	Color          string           `json:"color,omitempty" validate:"omitempty,hexcolor,max=7"`
+	IsUserCategory bool             `json:"is_user_category"`
+	Hidden         bool             `json:"hidden,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	Children       []CategoryObject `json:"children,omitempty"`
 }
 
 type CreateCategoryRequest struct {
@@ -32,39 +37,126 @@ type GetCategoryByIDResponse struct {
 }
 
 type ListCategoriesRequest struct {
-	UserID string `header:"User-Id" validate:"required"`
-	Test   string `query:"test"`
+	UserID        string `header:"User-Id" validate:"required"`
+	IncludeHidden bool   `query:"include_hidden"`
 }
 
 type ListCategoriesResponse struct {
 	Categories []CategoryObject `json:"categories"`
 }
 
-type DeleteCategoryRequest struct {
+// UpdateCategoryRequest changes the fields that are set. An empty icon or color clears it.
+// For a default category the change only applies to the user.
+type UpdateCategoryRequest struct {
+	UserID string  `header:"User-Id" validate:"required"`
+	ID     string  `param:"id" validate:"required"`
+	Name   *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Icon   *string `json:"icon,omitempty" validate:"omitempty,max=64"`
+	Color  *string `json:"color,omitempty" validate:"omitempty,eq=|hexcolor,max=7"`
+}
+
+type UpdateCategoryResponse struct{}
+
+type SetCategoryHiddenRequest struct {
 	UserID string `header:"User-Id" validate:"required"`
 	ID     string `param:"id" validate:"required"`
+	Hidden bool   `json:"hidden"`
+}
+
+type SetCategoryHiddenResponse struct{}
+
+type ResetCategoryOverrideRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	ID     string `param:"id" validate:"required"`
+}
+
+type ResetCategoryOverrideResponse struct{}
+
+type ReorderCategoriesRequest struct {
+	UserID      string   `header:"User-Id" validate:"required"`
+	CategoryIDs []string `json:"category_ids" validate:"required,min=1,dive,required"`
+}
+
+type ReorderCategoriesResponse struct{}
+
+type MergeCategoryRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	ID       string `param:"id" validate:"required"`
+	TargetID string `json:"target_id" validate:"required"`
+}
+
+type MergeCategoryResponse struct{}
+
+type MoveCategoryRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	ID       string `param:"id" validate:"required"`
+	ParentID string `json:"parent_id"`
+}
+
+type MoveCategoryResponse struct{}
+
+// DeleteCategoryRequest deletes a category. When ReassignTo is set, its transactions,
+// recurring transactions and limits are moved to that category first.
+type DeleteCategoryRequest struct {
+	UserID     string `header:"User-Id" validate:"required"`
+	ID         string `param:"id" validate:"required"`
+	ReassignTo string `query:"reassign_to"`
 }
 
 type DeleteCategoryResponse struct{}
 
 type ListCustomCategoriesRequest struct {
-	UserID string `header:"User-Id" validate:"required"`
+	UserID        string `header:"User-Id" validate:"required"`
+	IncludeHidden bool   `query:"include_hidden"`
 }
 
 type ListCustomCategoriesResponse struct {
 	Categories []CategoryObject `json:"categories"`
 }
 
-func convertCategories(categories []*domain.Category) []CategoryObject {
-	categoriesResponse := make([]CategoryObject, len(categories))
-	for i, category := range categories {
-		categoriesResponse[i] = CategoryObject{
-			ID:             category.ID,
-			UserID:         category.UserID.String,
-			Name:           category.Name,
-			IsUserCategory: category.IsUserCategory,
-			CreatedAt:      category.CreatedAt,
+func convertCategory(category *domain.Category) CategoryObject {
+	return CategoryObject{
+		ID:             category.ID,
+		UserID:         category.UserID.String,
+		ParentID:       category.ParentID.String,
+		Name:           category.Name,
+		Icon:           category.Icon,
+		Color:          category.Color,
+		IsUserCategory: category.IsUserCategory,
+		Hidden:         category.Hidden,
+		CreatedAt:      category.CreatedAt,
+	}
+}
+
+// categoryTree nests every category under its parent, keeping the order of categories.
+// Categories whose parent is not in the list are returned at the top level.
+func categoryTree(categories []*domain.Category) []CategoryObject {
+	listed := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		listed[category.ID] = true
+	}
+
+	var roots []*domain.Category
+	children := make(map[string][]*domain.Category)
+	for _, category := range categories {
+		if category.ParentID.Valid && listed[category.ParentID.String] {
+			children[category.ParentID.String] = append(children[category.ParentID.String], category)
+			continue
 		}
+		roots = append(roots, category)
 	}
-	return categoriesResponse
+
+	var build func(level []*domain.Category) []CategoryObject
+	build = func(level []*domain.Category) []CategoryObject {
+		objects := make([]CategoryObject, len(level))
+		for i, category := range level {
+			objects[i] = convertCategory(category)
+			if sub := children[category.ID]; len(sub) > 0 {
+				objects[i].Children = build(sub)
+			}
+		}
+		return objects
+	}
+
+	return build(roots)
 }
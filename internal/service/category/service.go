@@ -4,7 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"finly-backend/internal/domain"
 	"finly-backend/internal/repository/category"
+	"finly-backend/pkg/transaction"
+	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
 )
 
@@ -13,21 +16,64 @@ type Category interface {
 	GetByID(ctx context.Context, req *GetCategoryByIDRequest) (*GetCategoryByIDResponse, error)
 	List(ctx context.Context, req *ListCategoriesRequest) (*ListCategoriesResponse, error)
 	ListCustom(ctx context.Context, req *ListCustomCategoriesRequest) (*ListCustomCategoriesResponse, error)
+	Update(ctx context.Context, req *UpdateCategoryRequest) (*UpdateCategoryResponse, error)
+	Move(ctx context.Context, req *MoveCategoryRequest) (*MoveCategoryResponse, error)
+	Merge(ctx context.Context, req *MergeCategoryRequest) (*MergeCategoryResponse, error)
 	Delete(ctx context.Context, req *DeleteCategoryRequest) (*DeleteCategoryResponse, error)
+	SetHidden(ctx context.Context, req *SetCategoryHiddenRequest) (*SetCategoryHiddenResponse, error)
+	ResetOverride(ctx context.Context, req *ResetCategoryOverrideRequest) (*ResetCategoryOverrideResponse, error)
+	Reorder(ctx context.Context, req *ReorderCategoriesRequest) (*ReorderCategoriesResponse, error)
+}
+
+// CategoryReference is implemented by the repositories whose rows reference a category.
+// Merging a category into another moves those rows to the other category.
+type CategoryReference interface {
+	ReassignCategoryTX(ctx context.Context, tx *sqlx.Tx, fromCategoryID, toCategoryID string) ([]string, error)
+}
+
+// VersionedReference is implemented by the references whose readers cache data derived from
+// many rows under a version of the owner, such as reports. The version is bumped once a merge
+// is committed.
+type VersionedReference interface {
+	BumpVersion(ctx context.Context, userID string) error
 }
 
 type Service struct {
-	repo category.Category
+	repo                category.Category
+	references          []CategoryReference
+	transactionExecutor transaction.TransactionExecutor
 }
 
-func NewService(repo category.Category) *Service {
+func NewService(repo category.Category, references []CategoryReference, transactionExecutor transaction.TransactionExecutor) *Service {
 	return &Service{
-		repo: repo,
+		repo:                repo,
+		references:          references,
+		transactionExecutor: transactionExecutor,
 	}
 }
 
+// Create creates a custom category, as a subcategory when ParentID is set. The parent can be
+// a default category or another custom category of the user.
 func (s *Service) Create(ctx context.Context, req *CreateCategoryRequest) (*CreateCategoryResponse, error) {
-	id, err := s.repo.Create(ctx, req.UserID, req.Name)
+	var parentID sql.NullString
+	if req.ParentID != "" {
+		categories, err := s.visible(ctx, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := categories[req.ParentID]; !ok {
+			return nil, errs.ParentNotFound
+		}
+		parentID = sql.NullString{String: req.ParentID, Valid: true}
+	}
+
+	id, err := s.repo.Create(ctx, &domain.Category{
+		UserID:   sql.NullString{String: req.UserID, Valid: true},
+		ParentID: parentID,
+		Name:     req.Name,
+		Icon:     req.Icon,
+		Color:    req.Color,
+	})
 	if err != nil {
 		zap.L().Sugar().Errorf("Create: failed for userID=%s, categoryName=%s: %v", req.UserID, req.Name, err)
 		return nil, err
@@ -44,45 +90,49 @@ func (s *Service) GetByID(ctx context.Context, req *GetCategoryByIDRequest) (*Ge
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			zap.L().Sugar().Infof("GetByID: no category found for categoryID=%s, userID=%s", req.ID, req.UserID)
-			return &GetCategoryByIDResponse{}, nil
+			return nil, errs.CategoryNotFound
 		}
 		zap.L().Sugar().Errorf("GetByID: failed for categoryID=%s, userID=%s: %v", req.ID, req.UserID, err)
 		return nil, err
 	}
 
-	return &GetCategoryByIDResponse{
-		&CategoryObject{
-			ID:             category.ID,
-			UserID:         category.UserID.String,
-			Name:           category.Name,
-			IsUserCategory: category.IsUserCategory,
-			CreatedAt:      category.CreatedAt,
-		},
-	}, nil
+	object := convertCategory(category)
+	return &GetCategoryByIDResponse{&object}, nil
 }
 
+// List returns the default and custom categories of the user as a tree. Hidden categories
+// and their subcategories are left out unless IncludeHidden is set.
 func (s *Service) List(ctx context.Context, req *ListCategoriesRequest) (*ListCategoriesResponse, error) {
 	categories, err := s.repo.List(ctx, req.UserID)
 	if err != nil {
 		zap.L().Sugar().Errorf("List: failed for userID=%s: %v", req.UserID, err)
 		return nil, err
 	}
+	if !req.IncludeHidden {
+		categories = withoutHidden(categories)
+	}
 
-	categoriesResponse := convertCategories(categories)
+	categoriesResponse := categoryTree(categories)
 
 	return &ListCategoriesResponse{
 		Categories: categoriesResponse,
 	}, nil
 }
 
+// ListCustom returns the custom categories of the user as a tree. Subcategories of
+// default categories are returned at the top level. Hidden categories are left out unless
+// IncludeHidden is set.
 func (s *Service) ListCustom(ctx context.Context, req *ListCustomCategoriesRequest) (*ListCustomCategoriesResponse, error) {
 	categories, err := s.repo.ListCustom(ctx, req.UserID)
 	if err != nil {
 		zap.L().Sugar().Errorf("ListCustom: failed for userID=%s: %v", req.UserID, err)
 		return nil, err
 	}
+	if !req.IncludeHidden {
+		categories = withoutHidden(categories)
+	}
 
-	categoriesResponse := convertCategories(categories)
+	categoriesResponse := categoryTree(categories)
 
 	zap.L().Sugar().Infof("ListCustom: found %d custom categories for userID=%s", len(categoriesResponse), req.UserID)
 	return &ListCustomCategoriesResponse{
@@ -90,8 +140,215 @@ func (s *Service) ListCustom(ctx context.Context, req *ListCustomCategoriesReque
 	}, nil
 }
 
+// Update renames a category or changes its icon or color. Custom categories are changed in
+// place; default categories are shared, so the change is saved as an override for the user.
+func (s *Service) Update(ctx context.Context, req *UpdateCategoryRequest) (*UpdateCategoryResponse, error) {
+	categories, err := s.visible(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, ok := categories[req.ID]
+	if !ok {
+		return nil, errs.CategoryNotFound
+	}
+
+	if !current.IsUserCategory {
+		if err = s.repo.Override(ctx, &domain.CategoryOverride{
+			UserID:     req.UserID,
+			CategoryID: req.ID,
+			Name:       nullString(req.Name),
+			Icon:       nullString(req.Icon),
+			Color:      nullString(req.Color),
+		}); err != nil {
+			zap.L().Sugar().Errorf("Update: failed to override categoryID=%s, userID=%s: %v", req.ID, req.UserID, err)
+			return nil, errs.DatabaseError
+		}
+
+		zap.L().Sugar().Infof("Update: default categoryID=%s overridden for userID=%s", req.ID, req.UserID)
+		return &UpdateCategoryResponse{}, nil
+	}
+
+	if req.Name != nil {
+		current.Name = *req.Name
+	}
+	if req.Icon != nil {
+		current.Icon = *req.Icon
+	}
+	if req.Color != nil {
+		current.Color = *req.Color
+	}
+
+	updated, err := s.repo.Update(ctx, current)
+	if err != nil {
+		zap.L().Sugar().Errorf("Update: failed for categoryID=%s, userID=%s: %v", req.ID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+	if !updated {
+		return nil, errs.CategoryNotFound
+	}
+
+	zap.L().Sugar().Infof("Update: categoryID=%s updated for userID=%s", req.ID, req.UserID)
+	return &UpdateCategoryResponse{}, nil
+}
+
+// SetHidden hides a category from the category lists of the user, or shows it again.
+func (s *Service) SetHidden(ctx context.Context, req *SetCategoryHiddenRequest) (*SetCategoryHiddenResponse, error) {
+	categories, err := s.visible(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := categories[req.ID]; !ok {
+		return nil, errs.CategoryNotFound
+	}
+
+	if err = s.repo.Override(ctx, &domain.CategoryOverride{
+		UserID:     req.UserID,
+		CategoryID: req.ID,
+		Hidden:     sql.NullBool{Bool: req.Hidden, Valid: true},
+	}); err != nil {
+		zap.L().Sugar().Errorf("SetHidden: failed for categoryID=%s, userID=%s: %v", req.ID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("SetHidden: categoryID=%s hidden=%t for userID=%s", req.ID, req.Hidden, req.UserID)
+	return &SetCategoryHiddenResponse{}, nil
+}
+
+// ResetOverride drops the name, icon, color and hiding the user set on a default category.
+// The category keeps its place in the order of the user.
+func (s *Service) ResetOverride(ctx context.Context, req *ResetCategoryOverrideRequest) (*ResetCategoryOverrideResponse, error) {
+	categories, err := s.visible(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := categories[req.ID]; !ok {
+		return nil, errs.CategoryNotFound
+	}
+
+	if err = s.repo.ResetOverride(ctx, req.ID, req.UserID); err != nil {
+		zap.L().Sugar().Errorf("ResetOverride: failed for categoryID=%s, userID=%s: %v", req.ID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("ResetOverride: override of categoryID=%s reset for userID=%s", req.ID, req.UserID)
+	return &ResetCategoryOverrideResponse{}, nil
+}
+
+// Reorder lists the given categories first, in the given order. Categories left out are
+// listed after them, oldest first. Subcategories are ordered among their siblings.
+func (s *Service) Reorder(ctx context.Context, req *ReorderCategoriesRequest) (*ReorderCategoriesResponse, error) {
+	categories, err := s.visible(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(req.CategoryIDs))
+	for _, id := range req.CategoryIDs {
+		if seen[id] {
+			return nil, errs.DuplicateCategory
+		}
+		seen[id] = true
+
+		if _, ok := categories[id]; !ok {
+			return nil, errs.CategoryNotFound
+		}
+	}
+
+	if err = s.repo.Reorder(ctx, req.UserID, req.CategoryIDs); err != nil {
+		zap.L().Sugar().Errorf("Reorder: failed for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("Reorder: %d categories reordered for userID=%s", len(req.CategoryIDs), req.UserID)
+	return &ReorderCategoriesResponse{}, nil
+}
+
+// Move makes a custom category a subcategory of ParentID, or a top-level category when
+// ParentID is empty. A category cannot be moved under itself or one of its subcategories.
+func (s *Service) Move(ctx context.Context, req *MoveCategoryRequest) (*MoveCategoryResponse, error) {
+	categories, err := s.visible(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, ok := categories[req.ID]
+	if !ok || !current.IsUserCategory {
+		return nil, errs.CategoryNotFound
+	}
+
+	var parentID sql.NullString
+	if req.ParentID != "" {
+		if _, ok = categories[req.ParentID]; !ok {
+			return nil, errs.ParentNotFound
+		}
+		parentID = sql.NullString{String: req.ParentID, Valid: true}
+	}
+
+	// The tree is checked as stored, with the user's categories locked, since the listed
+	// categories may be cached or moved by a concurrent request.
+	var keys []string
+	err = s.transactionExecutor.WithTransaction(ctx, s.repo.GetDB(), func(tx *sqlx.Tx) error {
+		if err := s.repo.LockTreeTX(ctx, tx, req.UserID); err != nil {
+			return err
+		}
+
+		if parentID.Valid {
+			cycle, err := s.repo.IsDescendantTX(ctx, tx, req.ParentID, req.ID)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return errs.CategoryCycle
+			}
+		}
+
+		keys, err = s.repo.MoveTX(ctx, tx, req.ID, req.UserID, parentID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errs.CategoryCycle) {
+			return nil, errs.CategoryCycle
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.CategoryNotFound
+		}
+		zap.L().Sugar().Errorf("Move: failed for categoryID=%s, userID=%s: %v", req.ID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	if err = s.repo.PurgeCache(ctx, req.UserID, keys); err != nil {
+		zap.L().Sugar().Warnf("Move: failed to purge cache after moving categoryID=%s, userID=%s: %v", req.ID, req.UserID, err)
+	}
+
+	zap.L().Sugar().Infof("Move: categoryID=%s moved under parentID=%s for userID=%s", req.ID, req.ParentID, req.UserID)
+	return &MoveCategoryResponse{}, nil
+}
+
+// Merge moves the transactions, recurring transactions and limits of a custom category to
+// the target category and deletes it. Its subcategories become top-level categories. A month
+// or week limit the target already has is combined with the one of the merged category.
+func (s *Service) Merge(ctx context.Context, req *MergeCategoryRequest) (*MergeCategoryResponse, error) {
+	if err := s.merge(ctx, req.UserID, req.ID, req.TargetID); err != nil {
+		return nil, err
+	}
+	return &MergeCategoryResponse{}, nil
+}
+
+// Delete deletes a custom category. Its subcategories become top-level categories. A category
+// that is still in use is only deleted when ReassignTo names the category to merge it into.
 func (s *Service) Delete(ctx context.Context, req *DeleteCategoryRequest) (*DeleteCategoryResponse, error) {
+	if req.ReassignTo != "" {
+		if err := s.merge(ctx, req.UserID, req.ID, req.ReassignTo); err != nil {
+			return nil, err
+		}
+		return &DeleteCategoryResponse{}, nil
+	}
+
 	if err := s.repo.Delete(ctx, req.ID, req.UserID); err != nil {
+		if errors.Is(err, category.ErrCategoryInUse) {
+			return nil, errs.CategoryInUse
+		}
 		zap.L().Sugar().Errorf("Delete: failed to delete categoryID=%s, userID=%s: %v", req.ID, req.UserID, err)
 		return nil, err
 	}
@@ -99,3 +356,110 @@ func (s *Service) Delete(ctx context.Context, req *DeleteCategoryRequest) (*Dele
 	zap.L().Sugar().Infof("Delete: successfully deleted categoryID=%s for userID=%s", req.ID, req.UserID)
 	return &DeleteCategoryResponse{}, nil
 }
+
+func (s *Service) merge(ctx context.Context, userID, sourceID, targetID string) error {
+	if sourceID == targetID {
+		return errs.SameCategory
+	}
+
+	categories, err := s.visible(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if source, ok := categories[sourceID]; !ok || !source.IsUserCategory {
+		return errs.CategoryNotFound
+	}
+	if _, ok := categories[targetID]; !ok {
+		return errs.TargetNotFound
+	}
+
+	var keys []string
+	err = s.transactionExecutor.WithTransaction(ctx, s.repo.GetDB(), func(tx *sqlx.Tx) error {
+		for _, reference := range s.references {
+			referenceKeys, err := reference.ReassignCategoryTX(ctx, tx, sourceID, targetID)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, referenceKeys...)
+		}
+
+		categoryKeys, err := s.repo.DeleteTX(ctx, tx, sourceID, userID)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, categoryKeys...)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, category.ErrCategoryInUse) {
+			return errs.CategoryInUse
+		}
+		zap.L().Sugar().Errorf("merge: failed to merge categoryID=%s into categoryID=%s, userID=%s: %v", sourceID, targetID, userID, err)
+		return errs.DatabaseError
+	}
+
+	if err = s.repo.PurgeCache(ctx, userID, keys); err != nil {
+		zap.L().Sugar().Warnf("merge: failed to purge cache after merging categoryID=%s, userID=%s: %v", sourceID, userID, err)
+	}
+	for _, reference := range s.references {
+		if versioned, ok := reference.(VersionedReference); ok {
+			if err = versioned.BumpVersion(ctx, userID); err != nil {
+				zap.L().Sugar().Warnf("merge: failed to bump version after merging categoryID=%s, userID=%s: %v", sourceID, userID, err)
+			}
+		}
+	}
+
+	zap.L().Sugar().Infof("merge: categoryID=%s merged into categoryID=%s for userID=%s", sourceID, targetID, userID)
+	return nil
+}
+
+// visible returns the default and custom categories of the user by ID.
+func (s *Service) visible(ctx context.Context, userID string) (map[string]*domain.Category, error) {
+	categories, err := s.repo.List(ctx, userID)
+	if err != nil {
+		zap.L().Sugar().Errorf("failed to list categories for userID=%s: %v", userID, err)
+		return nil, errs.DatabaseError
+	}
+
+	byID := make(map[string]*domain.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+	return byID, nil
+}
+
+// withoutHidden leaves out the hidden categories and every category below one of them.
+func withoutHidden(categories []*domain.Category) []*domain.Category {
+	byID := make(map[string]*domain.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	hidden := func(category *domain.Category) bool {
+		seen := make(map[string]bool)
+		for category != nil && !seen[category.ID] {
+			if category.Hidden {
+				return true
+			}
+			seen[category.ID] = true
+			category = byID[category.ParentID.String]
+		}
+		return false
+	}
+
+	shown := make([]*domain.Category, 0, len(categories))
+	for _, category := range categories {
+		if !hidden(category) {
+			shown = append(shown, category)
+		}
+	}
+	return shown
+}
+
+// nullString returns the value of s, or null when s is not set.
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
@@ -5,13 +5,24 @@ import (
 	"database/sql"
 	"errors"
 	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/category"
 	"finly-backend/internal/repository/category/mock"
+	mock_transaction "finly-backend/internal/repository/transaction/mock"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"testing"
 	"time"
 )
 
+type mockTransactionExecutor struct {
+	withTx func(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error
+}
+
+func (m *mockTransactionExecutor) WithTransaction(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	return m.withTx(ctx, db, fn)
+}
+
 func TestCreate(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -31,11 +42,18 @@ func TestCreate(t *testing.T) {
 			req: &CreateCategoryRequest{
 				UserID: "user123",
 				CategoryObject: CategoryObject{
-					Name: "Groceries",
+					Name:  "Groceries",
+					Icon:  "cart",
+					Color: "#2e7d32",
 				},
 			},
 			mockSetup: func() {
-				mockCategoryRepo.EXPECT().Create(ctx, "user123", "Groceries").
+				mockCategoryRepo.EXPECT().Create(ctx, &domain.Category{
+					UserID: sql.NullString{String: "user123", Valid: true},
+					Name:   "Groceries",
+					Icon:   "cart",
+					Color:  "#2e7d32",
+				}).
 					Return("cat123", nil)
 			},
 			expectedRes: &CreateCategoryResponse{Id: "cat123"},
@@ -50,19 +68,60 @@ func TestCreate(t *testing.T) {
 				},
 			},
 			mockSetup: func() {
-				mockCategoryRepo.EXPECT().Create(ctx, "user123", "Travel").
+				mockCategoryRepo.EXPECT().Create(ctx, &domain.Category{
+					UserID: sql.NullString{String: "user123", Valid: true},
+					Name:   "Travel",
+				}).
 					Return("", errors.New("db error"))
 			},
 			expectedRes: nil,
 			expectedErr: errors.New("db error"),
 		},
+		{
+			name: "Subcategory of a default category",
+			req: &CreateCategoryRequest{
+				UserID: "user123",
+				CategoryObject: CategoryObject{
+					Name:     "Restaurants",
+					ParentID: "food",
+				},
+			},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").
+					Return([]*domain.Category{{ID: "food", Name: "Food & Drink"}}, nil)
+				mockCategoryRepo.EXPECT().Create(ctx, &domain.Category{
+					UserID:   sql.NullString{String: "user123", Valid: true},
+					ParentID: sql.NullString{String: "food", Valid: true},
+					Name:     "Restaurants",
+				}).
+					Return("cat456", nil)
+			},
+			expectedRes: &CreateCategoryResponse{Id: "cat456"},
+			expectedErr: nil,
+		},
+		{
+			name: "Parent not found",
+			req: &CreateCategoryRequest{
+				UserID: "user123",
+				CategoryObject: CategoryObject{
+					Name:     "Restaurants",
+					ParentID: "missing",
+				},
+			},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").
+					Return([]*domain.Category{{ID: "food", Name: "Food & Drink"}}, nil)
+			},
+			expectedRes: nil,
+			expectedErr: errs.ParentNotFound,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			service := NewService(mockCategoryRepo)
+			service := NewService(mockCategoryRepo, nil, nil)
 
 			resp, err := service.Create(ctx, tt.req)
 
@@ -130,8 +189,8 @@ func TestGetByID(t *testing.T) {
 				mockCategoryRepo.EXPECT().GetByID(ctx, "cat123", "user123").
 					Return(nil, sql.ErrNoRows)
 			},
-			expectedRes: &GetCategoryByIDResponse{},
-			expectedErr: nil,
+			expectedRes: nil,
+			expectedErr: errs.CategoryNotFound,
 		},
 		{
 			name: "GetByID error",
@@ -152,7 +211,7 @@ func TestGetByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			service := NewService(mockCategoryRepo)
+			service := NewService(mockCategoryRepo, nil, nil)
 
 			resp, err := service.GetByID(ctx, tt.req)
 
@@ -227,6 +286,84 @@ func TestList(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "Subcategories nested under their parents",
+			req: &ListCategoriesRequest{
+				UserID: "user123",
+			},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").
+					Return([]*domain.Category{
+						{ID: "groceries", ParentID: sql.NullString{String: "food", Valid: true}, Name: "Groceries", CreatedAt: createdAt},
+						{ID: "food", Name: "Food & Drink", CreatedAt: createdAt},
+						{ID: "organic", ParentID: sql.NullString{String: "groceries", Valid: true}, Name: "Organic", CreatedAt: createdAt},
+						{ID: "bills", Name: "Bills", CreatedAt: createdAt},
+					}, nil)
+			},
+			expectedRes: &ListCategoriesResponse{
+				Categories: []CategoryObject{
+					{
+						ID:        "food",
+						Name:      "Food & Drink",
+						CreatedAt: createdAt,
+						Children: []CategoryObject{
+							{
+								ID:        "groceries",
+								ParentID:  "food",
+								Name:      "Groceries",
+								CreatedAt: createdAt,
+								Children: []CategoryObject{
+									{ID: "organic", ParentID: "groceries", Name: "Organic", CreatedAt: createdAt},
+								},
+							},
+						},
+					},
+					{ID: "bills", Name: "Bills", CreatedAt: createdAt},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Hidden categories left out with their subcategories",
+			req: &ListCategoriesRequest{
+				UserID: "user123",
+			},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").
+					Return([]*domain.Category{
+						{ID: "food", Name: "Food & Drink", Hidden: true, CreatedAt: createdAt},
+						{ID: "groceries", ParentID: sql.NullString{String: "food", Valid: true}, Name: "Groceries", CreatedAt: createdAt},
+						{ID: "bills", Name: "Bills", CreatedAt: createdAt},
+					}, nil)
+			},
+			expectedRes: &ListCategoriesResponse{
+				Categories: []CategoryObject{
+					{ID: "bills", Name: "Bills", CreatedAt: createdAt},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Hidden categories included",
+			req: &ListCategoriesRequest{
+				UserID:        "user123",
+				IncludeHidden: true,
+			},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").
+					Return([]*domain.Category{
+						{ID: "food", Name: "Food & Drink", Hidden: true, CreatedAt: createdAt},
+						{ID: "bills", Name: "Bills", CreatedAt: createdAt},
+					}, nil)
+			},
+			expectedRes: &ListCategoriesResponse{
+				Categories: []CategoryObject{
+					{ID: "food", Name: "Food & Drink", Hidden: true, CreatedAt: createdAt},
+					{ID: "bills", Name: "Bills", CreatedAt: createdAt},
+				},
+			},
+			expectedErr: nil,
+		},
 		{
 			name: "List error",
 			req: &ListCategoriesRequest{
@@ -245,7 +382,7 @@ func TestList(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			service := NewService(mockCategoryRepo)
+			service := NewService(mockCategoryRepo, nil, nil)
 
 			resp, err := service.List(ctx, tt.req)
 
@@ -324,7 +461,7 @@ func TestListCustom(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			service := NewService(mockCategoryRepo)
+			service := NewService(mockCategoryRepo, nil, nil)
 
 			resp, err := service.ListCustom(ctx, tt.req)
 
@@ -340,6 +477,588 @@ func TestListCustom(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockCategoryRepo := mock.NewMockCategory(ctrl)
+	name, color, empty := "Eating out", "#ff5722", ""
+	current := func() *domain.Category {
+		return &domain.Category{
+			ID:             "cat123",
+			UserID:         sql.NullString{String: "user123", Valid: true},
+			Name:           "Restaurants",
+			Icon:           "fork",
+			IsUserCategory: true,
+		}
+	}
+	categories := func() []*domain.Category {
+		return []*domain.Category{current(), {ID: "default1", Name: "Bills"}}
+	}
+
+	tests := []struct {
+		name        string
+		req         *UpdateCategoryRequest
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Rename and recolor",
+			req:  &UpdateCategoryRequest{UserID: "user123", ID: "cat123", Name: &name, Color: &color},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories(), nil)
+				updated := current()
+				updated.Name, updated.Color = name, color
+				mockCategoryRepo.EXPECT().Update(ctx, updated).Return(true, nil)
+			},
+		},
+		{
+			name: "Clear icon",
+			req:  &UpdateCategoryRequest{UserID: "user123", ID: "cat123", Icon: &empty},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories(), nil)
+				updated := current()
+				updated.Icon = ""
+				mockCategoryRepo.EXPECT().Update(ctx, updated).Return(true, nil)
+			},
+		},
+		{
+			name: "Default category overridden for the user",
+			req:  &UpdateCategoryRequest{UserID: "user123", ID: "default1", Name: &name, Icon: &empty},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories(), nil)
+				mockCategoryRepo.EXPECT().Override(ctx, &domain.CategoryOverride{
+					UserID:     "user123",
+					CategoryID: "default1",
+					Name:       sql.NullString{String: name, Valid: true},
+					Icon:       sql.NullString{String: "", Valid: true},
+				}).Return(nil)
+			},
+		},
+		{
+			name: "Not found",
+			req:  &UpdateCategoryRequest{UserID: "user123", ID: "other", Name: &name},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories(), nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Deleted meanwhile",
+			req:  &UpdateCategoryRequest{UserID: "user123", ID: "cat123", Name: &name},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories(), nil)
+				mockCategoryRepo.EXPECT().Update(ctx, gomock.Any()).Return(false, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Update error",
+			req:  &UpdateCategoryRequest{UserID: "user123", ID: "cat123", Name: &name},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories(), nil)
+				mockCategoryRepo.EXPECT().Update(ctx, gomock.Any()).Return(false, errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+		{
+			name: "Override error",
+			req:  &UpdateCategoryRequest{UserID: "user123", ID: "default1", Name: &name},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories(), nil)
+				mockCategoryRepo.EXPECT().Override(ctx, gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+		{
+			name: "List error",
+			req:  &UpdateCategoryRequest{UserID: "user123", ID: "cat123", Name: &name},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(nil, errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			service := NewService(mockCategoryRepo, nil, nil)
+
+			resp, err := service.Update(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &UpdateCategoryResponse{}, resp)
+			}
+		})
+	}
+}
+
+func TestSetHidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockCategoryRepo := mock.NewMockCategory(ctrl)
+	categories := []*domain.Category{{ID: "default1", Name: "Bills"}}
+
+	tests := []struct {
+		name        string
+		req         *SetCategoryHiddenRequest
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Hide",
+			req:  &SetCategoryHiddenRequest{UserID: "user123", ID: "default1", Hidden: true},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().Override(ctx, &domain.CategoryOverride{
+					UserID:     "user123",
+					CategoryID: "default1",
+					Hidden:     sql.NullBool{Bool: true, Valid: true},
+				}).Return(nil)
+			},
+		},
+		{
+			name: "Show",
+			req:  &SetCategoryHiddenRequest{UserID: "user123", ID: "default1"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().Override(ctx, &domain.CategoryOverride{
+					UserID:     "user123",
+					CategoryID: "default1",
+					Hidden:     sql.NullBool{Bool: false, Valid: true},
+				}).Return(nil)
+			},
+		},
+		{
+			name: "Not found",
+			req:  &SetCategoryHiddenRequest{UserID: "user123", ID: "other", Hidden: true},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Override error",
+			req:  &SetCategoryHiddenRequest{UserID: "user123", ID: "default1", Hidden: true},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().Override(ctx, gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			service := NewService(mockCategoryRepo, nil, nil)
+
+			resp, err := service.SetHidden(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &SetCategoryHiddenResponse{}, resp)
+			}
+		})
+	}
+}
+
+func TestResetOverride(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockCategoryRepo := mock.NewMockCategory(ctrl)
+	categories := []*domain.Category{{ID: "default1", Name: "Utilities"}}
+
+	tests := []struct {
+		name        string
+		req         *ResetCategoryOverrideRequest
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			req:  &ResetCategoryOverrideRequest{UserID: "user123", ID: "default1"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().ResetOverride(ctx, "default1", "user123").Return(nil)
+			},
+		},
+		{
+			name: "Not found",
+			req:  &ResetCategoryOverrideRequest{UserID: "user123", ID: "other"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Reset error",
+			req:  &ResetCategoryOverrideRequest{UserID: "user123", ID: "default1"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().ResetOverride(ctx, "default1", "user123").Return(errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			service := NewService(mockCategoryRepo, nil, nil)
+
+			resp, err := service.ResetOverride(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &ResetCategoryOverrideResponse{}, resp)
+			}
+		})
+	}
+}
+
+func TestReorder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockCategoryRepo := mock.NewMockCategory(ctrl)
+	categories := []*domain.Category{
+		{ID: "default1", Name: "Bills"},
+		{ID: "cat123", Name: "Restaurants", IsUserCategory: true},
+	}
+
+	tests := []struct {
+		name        string
+		req         *ReorderCategoriesRequest
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			req:  &ReorderCategoriesRequest{UserID: "user123", CategoryIDs: []string{"cat123", "default1"}},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().Reorder(ctx, "user123", []string{"cat123", "default1"}).Return(nil)
+			},
+		},
+		{
+			name: "Listed twice",
+			req:  &ReorderCategoriesRequest{UserID: "user123", CategoryIDs: []string{"cat123", "cat123"}},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.DuplicateCategory,
+		},
+		{
+			name: "Unknown category",
+			req:  &ReorderCategoriesRequest{UserID: "user123", CategoryIDs: []string{"cat123", "other"}},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Reorder error",
+			req:  &ReorderCategoriesRequest{UserID: "user123", CategoryIDs: []string{"default1"}},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().Reorder(ctx, "user123", []string{"default1"}).Return(errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			service := NewService(mockCategoryRepo, nil, nil)
+
+			resp, err := service.Reorder(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &ReorderCategoriesResponse{}, resp)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockCategoryRepo := mock.NewMockCategory(ctrl)
+	mockReference := mock_transaction.NewMockTransaction(ctrl)
+	mockDB := &sqlx.DB{}
+	mockTx := &sqlx.Tx{}
+	executor := &mockTransactionExecutor{
+		withTx: func(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+			return fn(mockTx)
+		},
+	}
+	categories := []*domain.Category{
+		{ID: "food", Name: "Food & Drink"},
+		{ID: "eating-out", UserID: sql.NullString{String: "user123", Valid: true}, Name: "Eating out", IsUserCategory: true},
+		{ID: "restaurants", UserID: sql.NullString{String: "user123", Valid: true}, Name: "Restaurants", IsUserCategory: true},
+	}
+
+	tests := []struct {
+		name        string
+		req         *MergeCategoryRequest
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Successful merge",
+			req:  &MergeCategoryRequest{UserID: "user123", ID: "restaurants", TargetID: "eating-out"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().GetDB().Return(mockDB)
+				mockReference.EXPECT().ReassignCategoryTX(ctx, mockTx, "restaurants", "eating-out").
+					Return([]string{"transaction:1"}, nil)
+				mockCategoryRepo.EXPECT().DeleteTX(ctx, mockTx, "restaurants", "user123").
+					Return([]string{"category:restaurants:user:user123"}, nil)
+				mockCategoryRepo.EXPECT().PurgeCache(ctx, "user123", []string{"transaction:1", "category:restaurants:user:user123"}).
+					Return(nil)
+				mockReference.EXPECT().BumpVersion(ctx, "user123").Return(nil)
+			},
+		},
+		{
+			name: "Into a default category",
+			req:  &MergeCategoryRequest{UserID: "user123", ID: "restaurants", TargetID: "food"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().GetDB().Return(mockDB)
+				mockReference.EXPECT().ReassignCategoryTX(ctx, mockTx, "restaurants", "food").Return(nil, nil)
+				mockCategoryRepo.EXPECT().DeleteTX(ctx, mockTx, "restaurants", "user123").Return(nil, nil)
+				mockCategoryRepo.EXPECT().PurgeCache(ctx, "user123", nil).Return(nil)
+				mockReference.EXPECT().BumpVersion(ctx, "user123").Return(nil)
+			},
+		},
+		{
+			name:        "Into itself",
+			req:         &MergeCategoryRequest{UserID: "user123", ID: "restaurants", TargetID: "restaurants"},
+			mockSetup:   func() {},
+			expectedErr: errs.SameCategory,
+		},
+		{
+			name: "Default category",
+			req:  &MergeCategoryRequest{UserID: "user123", ID: "food", TargetID: "eating-out"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Target not found",
+			req:  &MergeCategoryRequest{UserID: "user123", ID: "restaurants", TargetID: "missing"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.TargetNotFound,
+		},
+		{
+			name: "Reassign error",
+			req:  &MergeCategoryRequest{UserID: "user123", ID: "restaurants", TargetID: "eating-out"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().GetDB().Return(mockDB)
+				mockReference.EXPECT().ReassignCategoryTX(ctx, mockTx, "restaurants", "eating-out").
+					Return(nil, errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			service := NewService(mockCategoryRepo, []CategoryReference{mockReference}, executor)
+
+			resp, err := service.Merge(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &MergeCategoryResponse{}, resp)
+			}
+		})
+	}
+}
+
+func TestMove(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockCategoryRepo := mock.NewMockCategory(ctrl)
+	mockTx := &sqlx.Tx{}
+	executor := &mockTransactionExecutor{
+		withTx: func(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+			return fn(mockTx)
+		},
+	}
+	userID := sql.NullString{String: "user123", Valid: true}
+	categories := []*domain.Category{
+		{ID: "food", Name: "Food & Drink"},
+		{ID: "groceries", UserID: userID, ParentID: sql.NullString{String: "food", Valid: true}, Name: "Groceries", IsUserCategory: true},
+		{ID: "organic", UserID: userID, ParentID: sql.NullString{String: "groceries", Valid: true}, Name: "Organic", IsUserCategory: true},
+		{ID: "hobbies", UserID: userID, Name: "Hobbies", IsUserCategory: true},
+	}
+	keys := []string{"category:groceries:user:user123"}
+
+	tests := []struct {
+		name        string
+		req         *MoveCategoryRequest
+		mockSetup   func()
+		expectedRes *MoveCategoryResponse
+		expectedErr error
+	}{
+		{
+			name: "Successful move",
+			req:  &MoveCategoryRequest{UserID: "user123", ID: "groceries", ParentID: "hobbies"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().GetDB().Return(&sqlx.DB{})
+				mockCategoryRepo.EXPECT().LockTreeTX(ctx, mockTx, "user123").Return(nil)
+				mockCategoryRepo.EXPECT().IsDescendantTX(ctx, mockTx, "hobbies", "groceries").Return(false, nil)
+				mockCategoryRepo.EXPECT().MoveTX(ctx, mockTx, "groceries", "user123", sql.NullString{String: "hobbies", Valid: true}).
+					Return(keys, nil)
+				mockCategoryRepo.EXPECT().PurgeCache(ctx, "user123", keys).Return(nil)
+			},
+			expectedRes: &MoveCategoryResponse{},
+		},
+		{
+			name: "Move to the top level",
+			req:  &MoveCategoryRequest{UserID: "user123", ID: "organic"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().GetDB().Return(&sqlx.DB{})
+				mockCategoryRepo.EXPECT().LockTreeTX(ctx, mockTx, "user123").Return(nil)
+				mockCategoryRepo.EXPECT().MoveTX(ctx, mockTx, "organic", "user123", sql.NullString{}).
+					Return(keys, nil)
+				mockCategoryRepo.EXPECT().PurgeCache(ctx, "user123", keys).Return(nil)
+			},
+			expectedRes: &MoveCategoryResponse{},
+		},
+		{
+			name: "Under its own subcategory",
+			req:  &MoveCategoryRequest{UserID: "user123", ID: "groceries", ParentID: "organic"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().GetDB().Return(&sqlx.DB{})
+				mockCategoryRepo.EXPECT().LockTreeTX(ctx, mockTx, "user123").Return(nil)
+				mockCategoryRepo.EXPECT().IsDescendantTX(ctx, mockTx, "organic", "groceries").Return(true, nil)
+			},
+			expectedErr: errs.CategoryCycle,
+		},
+		{
+			name: "Under a category moved below it meanwhile",
+			req:  &MoveCategoryRequest{UserID: "user123", ID: "hobbies", ParentID: "groceries"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().GetDB().Return(&sqlx.DB{})
+				mockCategoryRepo.EXPECT().LockTreeTX(ctx, mockTx, "user123").Return(nil)
+				mockCategoryRepo.EXPECT().IsDescendantTX(ctx, mockTx, "groceries", "hobbies").Return(true, nil)
+			},
+			expectedErr: errs.CategoryCycle,
+		},
+		{
+			name: "Default category",
+			req:  &MoveCategoryRequest{UserID: "user123", ID: "food", ParentID: "hobbies"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Parent not found",
+			req:  &MoveCategoryRequest{UserID: "user123", ID: "hobbies", ParentID: "missing"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.ParentNotFound,
+		},
+		{
+			name: "Deleted meanwhile",
+			req:  &MoveCategoryRequest{UserID: "user123", ID: "hobbies", ParentID: "food"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().GetDB().Return(&sqlx.DB{})
+				mockCategoryRepo.EXPECT().LockTreeTX(ctx, mockTx, "user123").Return(nil)
+				mockCategoryRepo.EXPECT().IsDescendantTX(ctx, mockTx, "food", "hobbies").Return(false, nil)
+				mockCategoryRepo.EXPECT().MoveTX(ctx, mockTx, "hobbies", "user123", sql.NullString{String: "food", Valid: true}).
+					Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Lock error",
+			req:  &MoveCategoryRequest{UserID: "user123", ID: "hobbies", ParentID: "food"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryRepo.EXPECT().GetDB().Return(&sqlx.DB{})
+				mockCategoryRepo.EXPECT().LockTreeTX(ctx, mockTx, "user123").Return(errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+		{
+			name: "List error",
+			req:  &MoveCategoryRequest{UserID: "user123", ID: "hobbies", ParentID: "food"},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(nil, errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			service := NewService(mockCategoryRepo, nil, executor)
+
+			resp, err := service.Move(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRes, resp)
+			}
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -380,13 +1099,50 @@ func TestDelete(t *testing.T) {
 			expectedRes: nil,
 			expectedErr: errors.New("db error"),
 		},
+		{
+			name: "Category in use",
+			req: &DeleteCategoryRequest{
+				ID:     "cat123",
+				UserID: "user123",
+			},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().Delete(ctx, "cat123", "user123").
+					Return(category.ErrCategoryInUse)
+			},
+			expectedRes: nil,
+			expectedErr: errs.CategoryInUse,
+		},
+		{
+			name: "Delete with reassignment",
+			req: &DeleteCategoryRequest{
+				ID:         "cat123",
+				UserID:     "user123",
+				ReassignTo: "cat456",
+			},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").
+					Return([]*domain.Category{
+						{ID: "cat123", IsUserCategory: true},
+						{ID: "cat456", IsUserCategory: true},
+					}, nil)
+				mockCategoryRepo.EXPECT().GetDB().Return(&sqlx.DB{})
+				mockCategoryRepo.EXPECT().DeleteTX(ctx, gomock.Any(), "cat123", "user123").Return(nil, nil)
+				mockCategoryRepo.EXPECT().PurgeCache(ctx, "user123", nil).Return(nil)
+			},
+			expectedRes: &DeleteCategoryResponse{},
+			expectedErr: nil,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			service := NewService(mockCategoryRepo)
+			service := NewService(mockCategoryRepo, nil, &mockTransactionExecutor{
+				withTx: func(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+					return fn(&sqlx.Tx{})
+				},
+			})
 
 			resp, err := service.Delete(ctx, tt.req)
 
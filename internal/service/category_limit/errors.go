@@ -0,0 +1,26 @@
+package category_limit
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+var errs = struct {
+	LimitNotFound    *echo.HTTPError
+	LimitExists      *echo.HTTPError
+	BudgetNotFound   *echo.HTTPError
+	CategoryNotFound *echo.HTTPError
+	Forbidden        *echo.HTTPError
+	InvalidPeriod    *echo.HTTPError
+	TotalOutOfRange  *echo.HTTPError
+	DatabaseError    *echo.HTTPError
+}{
+	LimitNotFound:    echo.NewHTTPError(http.StatusNotFound, "Category limit not found"),
+	LimitExists:      echo.NewHTTPError(http.StatusConflict, "The category already has a limit for this period"),
+	BudgetNotFound:   echo.NewHTTPError(http.StatusNotFound, "Budget not found"),
+	CategoryNotFound: echo.NewHTTPError(http.StatusNotFound, "Category not found"),
+	Forbidden:        echo.NewHTTPError(http.StatusForbidden, "Only the budget owner can manage its limits"),
+	InvalidPeriod:    echo.NewHTTPError(http.StatusBadRequest, "Invalid limit period"),
+	TotalOutOfRange:  echo.NewHTTPError(http.StatusUnprocessableEntity, "Spending total is out of range"),
+	DatabaseError:    echo.NewHTTPError(http.StatusInternalServerError, "Database operation failed"),
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/category_limit/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/category_limit/service.go -destination=internal/service/category_limit/mock/mock_category_limit.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	category_limit "finly-backend/internal/service/category_limit"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCategoryLimit is a mock of CategoryLimit interface.
+type MockCategoryLimit struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryLimitMockRecorder
+	isgomock struct{}
+}
+
+// MockCategoryLimitMockRecorder is the mock recorder for MockCategoryLimit.
+type MockCategoryLimitMockRecorder struct {
+	mock *MockCategoryLimit
+}
+
+// NewMockCategoryLimit creates a new mock instance.
+func NewMockCategoryLimit(ctrl *gomock.Controller) *MockCategoryLimit {
+	mock := &MockCategoryLimit{ctrl: ctrl}
+	mock.recorder = &MockCategoryLimitMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryLimit) EXPECT() *MockCategoryLimitMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCategoryLimit) Create(ctx context.Context, req *category_limit.CreateLimitRequest) (*category_limit.CreateLimitResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*category_limit.CreateLimitResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCategoryLimitMockRecorder) Create(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCategoryLimit)(nil).Create), ctx, req)
+}
+
+// Delete mocks base method.
+func (m *MockCategoryLimit) Delete(ctx context.Context, req *category_limit.DeleteLimitRequest) (*category_limit.DeleteLimitResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, req)
+	ret0, _ := ret[0].(*category_limit.DeleteLimitResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCategoryLimitMockRecorder) Delete(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCategoryLimit)(nil).Delete), ctx, req)
+}
+
+// List mocks base method.
+func (m *MockCategoryLimit) List(ctx context.Context, req *category_limit.ListLimitsRequest) (*category_limit.ListLimitsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(*category_limit.ListLimitsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCategoryLimitMockRecorder) List(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCategoryLimit)(nil).List), ctx, req)
+}
+
+// Update mocks base method.
+func (m *MockCategoryLimit) Update(ctx context.Context, req *category_limit.UpdateLimitRequest) (*category_limit.UpdateLimitResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, req)
+	ret0, _ := ret[0].(*category_limit.UpdateLimitResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCategoryLimitMockRecorder) Update(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCategoryLimit)(nil).Update), ctx, req)
+}
@@ -0,0 +1,91 @@
+package category_limit
+
+import (
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_limit_period"
+	"time"
+)
+
+type LimitObject struct {
+	ID         string              `json:"id"`
+	BudgetID   string              `json:"budget_id"`
+	CategoryID string              `json:"category_id"`
+	Amount     domain.Money        `json:"amount" swaggertype:"number"`
+	Period     e_limit_period.Enum `json:"period"`
+	StartsAt   *time.Time          `json:"starts_at,omitempty"`
+	EndsAt     *time.Time          `json:"ends_at,omitempty"`
+	Enforce    bool                `json:"enforce"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// LimitStatusObject is a limit together with the spending in its current period.
+// Remaining turns negative once the limit is exceeded.
+type LimitStatusObject struct {
+	*LimitObject
+	PeriodStart time.Time    `json:"period_start"`
+	PeriodEnd   time.Time    `json:"period_end"`
+	Active      bool         `json:"active"`
+	Spent       domain.Money `json:"spent" swaggertype:"number"`
+	Remaining   domain.Money `json:"remaining" swaggertype:"number"`
+	Percentage  float64      `json:"percentage"`
+	Exceeded    bool         `json:"exceeded"`
+}
+
+// CreateLimitRequest sets a spending limit on a category of a budget. Month and week limits
+// repeat; a custom limit needs starts_at and ends_at. With enforce set, withdrawals that would
+// go over the limit are rejected instead of only being warned about.
+type CreateLimitRequest struct {
+	UserID     string              `header:"User-Id" validate:"required"`
+	BudgetID   string              `json:"budget_id" validate:"required,uuid"`
+	CategoryID string              `json:"category_id" validate:"required,uuid"`
+	Amount     domain.Money        `json:"amount" validate:"required,gt=0" swaggertype:"number"`
+	Period     e_limit_period.Enum `json:"period" validate:"required,oneof=month week custom"`
+	StartsAt   *time.Time          `json:"starts_at" validate:"required_if=Period custom"`
+	EndsAt     *time.Time          `json:"ends_at" validate:"required_if=Period custom"`
+	Enforce    bool                `json:"enforce"`
+}
+
+type CreateLimitResponse struct {
+	ID string `json:"id"`
+}
+
+type ListLimitsRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	BudgetID string `query:"budget_id" validate:"required,uuid"`
+}
+
+type ListLimitsResponse struct {
+	Limits []*LimitStatusObject `json:"limits"`
+}
+
+type UpdateLimitRequest struct {
+	UserID   string       `header:"User-Id" validate:"required"`
+	ID       string       `param:"id" validate:"required"`
+	Amount   domain.Money `json:"amount,omitempty" validate:"omitempty,gt=0" swaggertype:"number"`
+	StartsAt *time.Time   `json:"starts_at,omitempty"`
+	EndsAt   *time.Time   `json:"ends_at,omitempty"`
+	Enforce  *bool        `json:"enforce,omitempty"`
+}
+
+type UpdateLimitResponse struct{}
+
+type DeleteLimitRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	ID     string `param:"id" validate:"required"`
+}
+
+type DeleteLimitResponse struct{}
+
+func convertLimit(limit *domain.CategoryLimit) *LimitObject {
+	return &LimitObject{
+		ID:         limit.ID,
+		BudgetID:   limit.BudgetID,
+		CategoryID: limit.CategoryID,
+		Amount:     limit.Amount,
+		Period:     e_limit_period.Enum(limit.Period),
+		StartsAt:   nullTimePtr(limit.StartsAt),
+		EndsAt:     nullTimePtr(limit.EndsAt),
+		Enforce:    limit.Enforce,
+		CreatedAt:  limit.CreatedAt,
+	}
+}
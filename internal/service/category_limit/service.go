@@ -0,0 +1,210 @@
+package category_limit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_budget_role"
+	"finly-backend/internal/domain/enums/e_limit_period"
+	"finly-backend/internal/repository/budget"
+	"finly-backend/internal/repository/category"
+	"finly-backend/internal/repository/category_limit"
+	"go.uber.org/zap"
+	"slices"
+	"time"
+)
+
+type CategoryLimit interface {
+	Create(ctx context.Context, req *CreateLimitRequest) (*CreateLimitResponse, error)
+	List(ctx context.Context, req *ListLimitsRequest) (*ListLimitsResponse, error)
+	Update(ctx context.Context, req *UpdateLimitRequest) (*UpdateLimitResponse, error)
+	Delete(ctx context.Context, req *DeleteLimitRequest) (*DeleteLimitResponse, error)
+}
+
+type Service struct {
+	categoryLimitRepo category_limit.CategoryLimit
+	budgetRepo        budget.Budget
+	categoryRepo      category.Category
+}
+
+func NewService(categoryLimitRepo category_limit.CategoryLimit, budgetRepo budget.Budget, categoryRepo category.Category) *Service {
+	return &Service{
+		categoryLimitRepo: categoryLimitRepo,
+		budgetRepo:        budgetRepo,
+		categoryRepo:      categoryRepo,
+	}
+}
+
+// Create sets a limit on a budget. Limits of a shared budget are managed by its owner.
+func (s *Service) Create(ctx context.Context, req *CreateLimitRequest) (*CreateLimitResponse, error) {
+	if _, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Owner); err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.List(ctx, req.UserID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Create: failed to list categories for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+	if !slices.ContainsFunc(categories, func(category *domain.Category) bool { return category.ID == req.CategoryID }) {
+		zap.L().Sugar().Warnf("Create: categoryID=%s can't be used by userID=%s", req.CategoryID, req.UserID)
+		return nil, errs.CategoryNotFound
+	}
+
+	limit := &domain.CategoryLimit{
+		UserID:     req.UserID,
+		BudgetID:   req.BudgetID,
+		CategoryID: req.CategoryID,
+		Amount:     req.Amount,
+		Period:     req.Period.String(),
+		Enforce:    req.Enforce,
+	}
+	if req.Period == e_limit_period.Custom {
+		limit.StartsAt = sql.NullTime{Time: req.StartsAt.UTC(), Valid: true}
+		limit.EndsAt = sql.NullTime{Time: req.EndsAt.UTC(), Valid: true}
+	} else if req.StartsAt != nil || req.EndsAt != nil {
+		return nil, errs.InvalidPeriod
+	}
+	if err := validatePeriod(limit); err != nil {
+		return nil, err
+	}
+
+	id, err := s.categoryLimitRepo.Create(ctx, limit)
+	if err != nil {
+		if errors.Is(err, category_limit.ErrDuplicateLimit) {
+			return nil, errs.LimitExists
+		}
+		zap.L().Sugar().Errorf("Create: failed to create category limit for userID=%s, budgetID=%s: %v", req.UserID, req.BudgetID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("Create: category limit %s created for userID=%s, budgetID=%s", id, req.UserID, req.BudgetID)
+	return &CreateLimitResponse{ID: id}, nil
+}
+
+// List returns the limits of a budget with what has been spent in their current period.
+// Every member of a shared budget sees the limits its owner has set.
+func (s *Service) List(ctx context.Context, req *ListLimitsRequest) (*ListLimitsResponse, error) {
+	budget, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := s.categoryLimitRepo.ListByBudgetID(ctx, req.BudgetID, budget.UserID)
+	if err != nil {
+		zap.L().Sugar().Errorf("List: failed for userID=%s, budgetID=%s: %v", req.UserID, req.BudgetID, err)
+		return nil, errs.DatabaseError
+	}
+
+	now := time.Now().UTC()
+	list := make([]*LimitStatusObject, 0, len(limits))
+	for _, limit := range limits {
+		start, end, active := limit.Window(now)
+
+		spent, err := s.categoryLimitRepo.Spent(ctx, limit.BudgetID, limit.CategoryID, start, end)
+		if err != nil {
+			zap.L().Sugar().Errorf("List: failed to sum spending for limitID=%s: %v", limit.ID, err)
+			return nil, errs.DatabaseError
+		}
+
+		remaining, err := limit.Amount.Sub(spent)
+		if err != nil {
+			zap.L().Sugar().Errorf("List: spending of limitID=%s is out of range: %v", limit.ID, err)
+			return nil, errs.TotalOutOfRange
+		}
+
+		list = append(list, &LimitStatusObject{
+			LimitObject: convertLimit(limit),
+			PeriodStart: start,
+			PeriodEnd:   end,
+			Active:      active,
+			Spent:       spent,
+			Remaining:   remaining,
+			Percentage:  percentage(spent, limit.Amount),
+			Exceeded:    limit.Amount.LessThan(spent),
+		})
+	}
+
+	return &ListLimitsResponse{Limits: list}, nil
+}
+
+func (s *Service) Update(ctx context.Context, req *UpdateLimitRequest) (*UpdateLimitResponse, error) {
+	limit, err := s.getOwnedLimit(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.Amount.IsZero() {
+		limit.Amount = req.Amount
+	}
+	if req.Enforce != nil {
+		limit.Enforce = *req.Enforce
+	}
+	if req.StartsAt != nil || req.EndsAt != nil {
+		if limit.Period != e_limit_period.Custom.String() {
+			return nil, errs.InvalidPeriod
+		}
+		if req.StartsAt != nil {
+			limit.StartsAt = sql.NullTime{Time: req.StartsAt.UTC(), Valid: true}
+		}
+		if req.EndsAt != nil {
+			limit.EndsAt = sql.NullTime{Time: req.EndsAt.UTC(), Valid: true}
+		}
+	}
+	if err = validatePeriod(limit); err != nil {
+		return nil, err
+	}
+
+	if err = s.categoryLimitRepo.Update(ctx, limit); err != nil {
+		zap.L().Sugar().Errorf("Update: failed for limitID=%s, userID=%s: %v", req.ID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("Update: completed for limitID=%s, userID=%s", req.ID, req.UserID)
+	return &UpdateLimitResponse{}, nil
+}
+
+func (s *Service) Delete(ctx context.Context, req *DeleteLimitRequest) (*DeleteLimitResponse, error) {
+	limit, err := s.getOwnedLimit(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.categoryLimitRepo.Delete(ctx, limit.ID, limit.UserID, limit.BudgetID); err != nil {
+		zap.L().Sugar().Errorf("Delete: failed for limitID=%s, userID=%s: %v", req.ID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("Delete: completed for limitID=%s, userID=%s", req.ID, req.UserID)
+	return &DeleteLimitResponse{}, nil
+}
+
+func (s *Service) getBudget(ctx context.Context, budgetID, userID string, required e_budget_role.Enum) (*domain.Budget, error) {
+	budget, err := s.budgetRepo.GetByID(ctx, budgetID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.BudgetNotFound
+		}
+		zap.L().Sugar().Errorf("failed to get budgetID=%s for userID=%s: %v", budgetID, userID, err)
+		return nil, errs.DatabaseError
+	}
+	if !e_budget_role.Enum(budget.Role).Allows(required) {
+		return nil, errs.Forbidden
+	}
+	return budget, nil
+}
+
+func (s *Service) getOwnedLimit(ctx context.Context, limitID, userID string) (*domain.CategoryLimit, error) {
+	limit, err := s.categoryLimitRepo.GetByID(ctx, limitID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			zap.L().Sugar().Warnf("category limit not found for limitID=%s, userID=%s", limitID, userID)
+			return nil, errs.LimitNotFound
+		}
+		zap.L().Sugar().Errorf("failed to get limitID=%s for userID=%s: %v", limitID, userID, err)
+		return nil, errs.DatabaseError
+	}
+
+	return limit, nil
+}
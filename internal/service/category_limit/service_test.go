@@ -0,0 +1,263 @@
+package category_limit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_limit_period"
+	mock_budget "finly-backend/internal/repository/budget/mock"
+	mock_category "finly-backend/internal/repository/category/mock"
+	"finly-backend/internal/repository/category_limit"
+	mock_category_limit "finly-backend/internal/repository/category_limit/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockCategoryRepo := mock_category.NewMockCategory(ctrl)
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 14)
+	budget := &domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}
+	categories := []*domain.Category{{ID: "cat123"}, {ID: "groceries"}}
+
+	tests := []struct {
+		name        string
+		req         *CreateLimitRequest
+		setupMocks  func()
+		expectedRes *CreateLimitResponse
+		expectedErr error
+	}{
+		{
+			name: "Monthly limit",
+			req: &CreateLimitRequest{
+				UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("400.00"), Period: e_limit_period.Month,
+			},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryLimitRepo.EXPECT().Create(ctx, &domain.CategoryLimit{
+					UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+					Amount: domain.MustParseMoney("400.00"), Period: "month",
+				}).Return("limit1", nil)
+			},
+			expectedRes: &CreateLimitResponse{ID: "limit1"},
+		},
+		{
+			name: "Enforced custom limit",
+			req: &CreateLimitRequest{
+				UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("150.00"), Period: e_limit_period.Custom,
+				StartsAt: &start, EndsAt: &end, Enforce: true,
+			},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryLimitRepo.EXPECT().Create(ctx, &domain.CategoryLimit{
+					UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+					Amount: domain.MustParseMoney("150.00"), Period: "custom",
+					StartsAt: sql.NullTime{Time: start, Valid: true},
+					EndsAt:   sql.NullTime{Time: end, Valid: true},
+					Enforce:  true,
+				}).Return("limit2", nil)
+			},
+			expectedRes: &CreateLimitResponse{ID: "limit2"},
+		},
+		{
+			name: "Custom range ends before it starts",
+			req: &CreateLimitRequest{
+				UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("150.00"), Period: e_limit_period.Custom,
+				StartsAt: &end, EndsAt: &start,
+			},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.InvalidPeriod,
+		},
+		{
+			name: "Range on a monthly limit",
+			req: &CreateLimitRequest{
+				UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("150.00"), Period: e_limit_period.Month, StartsAt: &start,
+			},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.InvalidPeriod,
+		},
+		{
+			name: "Duplicate limit",
+			req: &CreateLimitRequest{
+				UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("400.00"), Period: e_limit_period.Week,
+			},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockCategoryLimitRepo.EXPECT().Create(ctx, gomock.Any()).Return("", category_limit.ErrDuplicateLimit)
+			},
+			expectedErr: errs.LimitExists,
+		},
+		{
+			name: "Budget not found",
+			req: &CreateLimitRequest{
+				UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("400.00"), Period: e_limit_period.Month,
+			},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.BudgetNotFound,
+		},
+		{
+			name: "Editor of a shared budget",
+			req: &CreateLimitRequest{
+				UserID: "user456", BudgetID: "budget123", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("400.00"), Period: e_limit_period.Month,
+			},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "editor"}, nil)
+			},
+			expectedErr: errs.Forbidden,
+		},
+		{
+			name: "Custom category of another user",
+			req: &CreateLimitRequest{
+				UserID: "user123", BudgetID: "budget123", CategoryID: "foreign",
+				Amount: domain.MustParseMoney("400.00"), Period: e_limit_period.Month,
+			},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			service := NewService(mockCategoryLimitRepo, mockBudgetRepo, mockCategoryRepo)
+			res, err := service.Create(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, res)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRes, res)
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	service := NewService(mockCategoryLimitRepo, mockBudgetRepo, nil)
+
+	req := &ListLimitsRequest{UserID: "user123", BudgetID: "budget123"}
+
+	t.Run("Spending per limit", func(t *testing.T) {
+		monthly := &domain.CategoryLimit{ID: "limit1", BudgetID: "budget123", CategoryID: "cat123", Amount: domain.MustParseMoney("400.00"), Period: "month"}
+		weekly := &domain.CategoryLimit{ID: "limit2", BudgetID: "budget123", CategoryID: "cat456", Amount: domain.MustParseMoney("30.00"), Period: "week"}
+
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+		mockCategoryLimitRepo.EXPECT().ListByBudgetID(ctx, "budget123", "user123").Return([]*domain.CategoryLimit{monthly, weekly}, nil)
+
+		monthStart, monthEnd, _ := monthly.Window(time.Now())
+		mockCategoryLimitRepo.EXPECT().Spent(ctx, "budget123", "cat123", monthStart, monthEnd).Return(domain.MustParseMoney("100.00"), nil)
+		mockCategoryLimitRepo.EXPECT().Spent(ctx, "budget123", "cat456", gomock.Any(), gomock.Any()).Return(domain.MustParseMoney("45.00"), nil)
+
+		res, err := service.List(ctx, req)
+		assert.NoError(t, err)
+		assert.Len(t, res.Limits, 2)
+
+		assert.Equal(t, "limit1", res.Limits[0].ID)
+		assert.Equal(t, monthStart, res.Limits[0].PeriodStart)
+		assert.Equal(t, domain.MustParseMoney("300.00"), res.Limits[0].Remaining)
+		assert.Equal(t, 25.0, res.Limits[0].Percentage)
+		assert.True(t, res.Limits[0].Active)
+		assert.False(t, res.Limits[0].Exceeded)
+
+		assert.Equal(t, domain.MustParseMoney("-15.00"), res.Limits[1].Remaining)
+		assert.Equal(t, 150.0, res.Limits[1].Percentage)
+		assert.True(t, res.Limits[1].Exceeded)
+	})
+
+	t.Run("Viewer sees the owner's limits", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "viewer"}, nil)
+		mockCategoryLimitRepo.EXPECT().ListByBudgetID(ctx, "budget123", "user123").Return(nil, nil)
+
+		res, err := service.List(ctx, &ListLimitsRequest{UserID: "user456", BudgetID: "budget123"})
+		assert.NoError(t, err)
+		assert.Empty(t, res.Limits)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+		mockCategoryLimitRepo.EXPECT().ListByBudgetID(ctx, "budget123", "user123").Return(nil, errors.New("db error"))
+
+		res, err := service.List(ctx, req)
+		assert.ErrorIs(t, err, errs.DatabaseError)
+		assert.Nil(t, res)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	service := NewService(mockCategoryLimitRepo, mockBudgetRepo, nil)
+
+	enforce := true
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Amount and enforcement", func(t *testing.T) {
+		mockCategoryLimitRepo.EXPECT().GetByID(ctx, "limit1", "user123").
+			Return(&domain.CategoryLimit{ID: "limit1", UserID: "user123", Amount: domain.MustParseMoney("400.00"), Period: "month"}, nil)
+		mockCategoryLimitRepo.EXPECT().Update(ctx, &domain.CategoryLimit{
+			ID: "limit1", UserID: "user123", Amount: domain.MustParseMoney("500.00"), Period: "month", Enforce: true,
+		}).Return(nil)
+
+		_, err := service.Update(ctx, &UpdateLimitRequest{UserID: "user123", ID: "limit1", Amount: domain.MustParseMoney("500.00"), Enforce: &enforce})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Range on a weekly limit", func(t *testing.T) {
+		mockCategoryLimitRepo.EXPECT().GetByID(ctx, "limit1", "user123").
+			Return(&domain.CategoryLimit{ID: "limit1", UserID: "user123", Amount: domain.MustParseMoney("400.00"), Period: "week"}, nil)
+
+		_, err := service.Update(ctx, &UpdateLimitRequest{UserID: "user123", ID: "limit1", StartsAt: &start})
+		assert.ErrorIs(t, err, errs.InvalidPeriod)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mockCategoryLimitRepo.EXPECT().GetByID(ctx, "limit1", "user123").Return(nil, sql.ErrNoRows)
+
+		_, err := service.Update(ctx, &UpdateLimitRequest{UserID: "user123", ID: "limit1"})
+		assert.ErrorIs(t, err, errs.LimitNotFound)
+	})
+}
@@ -0,0 +1,32 @@
+package category_limit
+
+import (
+	"database/sql"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_limit_period"
+	"math"
+	"time"
+)
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// percentage returns spent as a share of amount, rounded to two decimals.
+func percentage(spent, amount domain.Money) float64 {
+	if !amount.IsPositive() {
+		return 0
+	}
+	return math.Round(float64(spent.Cents())*10000/float64(amount.Cents())) / 100
+}
+
+// validatePeriod checks that a custom limit covers a non-empty range.
+func validatePeriod(limit *domain.CategoryLimit) error {
+	if limit.Period == e_limit_period.Custom.String() && !limit.StartsAt.Time.Before(limit.EndsAt.Time) {
+		return errs.InvalidPeriod
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+package data_export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/transaction"
+	transactionService "finly-backend/internal/service/transaction"
+	"io"
+	"slices"
+	"time"
+)
+
+// dataset is one kind of record in the archive. It is written twice, as name.json and as
+// name.csv, so each calls fn once per record with its JSON value and its CSV row.
+type dataset struct {
+	name   string
+	single bool
+	header []string
+	each   func(fn func(record any, row []string) error) error
+}
+
+type profileRecord struct {
+	ID              string     `json:"id"`
+	Email           string     `json:"email"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type budgetRecord struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Currency   string     `json:"currency"`
+	ArchivedAt *time.Time `json:"archived_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type budgetHistoryRecord struct {
+	ID            string       `json:"id"`
+	BudgetID      string       `json:"budget_id"`
+	TransactionID *string      `json:"transaction_id"`
+	Balance       domain.Money `json:"balance"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type transactionRecord struct {
+	ID           string        `json:"id"`
+	BudgetID     string        `json:"budget_id"`
+	CategoryID   string        `json:"category_id"`
+	CategoryName string        `json:"category"`
+	Type         string        `json:"type"`
+	Amount       domain.Money  `json:"amount"`
+	Balance      *domain.Money `json:"balance"`
+	Note         string        `json:"note"`
+	TransferID   *string       `json:"transfer_id"`
+	ExternalID   *string       `json:"external_id"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type categoryRecord struct {
+	ID        string    `json:"id"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Name      string    `json:"name"`
+	Icon      string    `json:"icon,omitempty"`
+	Color     string    `json:"color,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// writeArchive writes a ZIP archive of the profile, budgets, budget history, transactions
+// and custom categories of the user. Credentials such as the password hash are left out,
+// and so are budgets other users share with the user.
+func (s *Service) writeArchive(ctx context.Context, userID string, w io.Writer) error {
+	user, err := s.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	budgets, err := s.budgetRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	budgets = slices.DeleteFunc(budgets, func(b *domain.Budget) bool { return b.UserID != userID })
+	categories, err := s.categoryRepo.ListCustom(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, d := range []dataset{
+		profileDataset(user),
+		budgetsDataset(budgets),
+		s.budgetHistoryDataset(ctx, budgets),
+		s.transactionsDataset(ctx, userID),
+		categoriesDataset(categories),
+	} {
+		if err = d.write(zw); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func (d dataset) write(zw *zip.Writer) error {
+	f, err := zw.Create(d.name + ".json")
+	if err != nil {
+		return err
+	}
+	if err = d.writeJSON(f); err != nil {
+		return err
+	}
+
+	if f, err = zw.Create(d.name + ".csv"); err != nil {
+		return err
+	}
+	return d.writeCSV(f)
+}
+
+// writeJSON writes a single record as an object and any other dataset as an array, one
+// record at a time, so large datasets are never held in memory as a whole.
+func (d dataset) writeJSON(w io.Writer) error {
+	if d.single {
+		return d.each(func(record any, _ []string) error {
+			b, err := json.MarshalIndent(record, "", "  ")
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(append(b, '\n'))
+			return err
+		})
+	}
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	sep := "\n  "
+	err := d.each(func(record any, _ []string) error {
+		b, err := json.MarshalIndent(record, "  ", "  ")
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(w, sep); err != nil {
+			return err
+		}
+		sep = ",\n  "
+		_, err = w.Write(b)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if sep != "\n  " {
+		_, err = io.WriteString(w, "\n")
+	}
+	if err == nil {
+		_, err = io.WriteString(w, "]\n")
+	}
+	return err
+}
+
+func (d dataset) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(d.header); err != nil {
+		return err
+	}
+	if err := d.each(func(_ any, row []string) error {
+		return cw.Write(row)
+	}); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func profileDataset(user *domain.User) dataset {
+	return dataset{
+		name:   "profile",
+		single: true,
+		header: []string{"id", "email", "first_name", "last_name", "email_verified_at", "created_at", "updated_at"},
+		each: func(fn func(any, []string) error) error {
+			return fn(profileRecord{
+				ID:              user.ID,
+				Email:           user.Email,
+				FirstName:       user.FirstName,
+				LastName:        user.LastName,
+				EmailVerifiedAt: nullTimePtr(user.EmailVerifiedAt),
+				CreatedAt:       user.CreatedAt,
+				UpdatedAt:       user.UpdatedAt,
+			}, []string{
+				user.ID,
+				user.Email,
+				transactionService.CSVSafe(user.FirstName),
+				transactionService.CSVSafe(user.LastName),
+				formatNullTime(user.EmailVerifiedAt.Time, user.EmailVerifiedAt.Valid),
+				formatTime(user.CreatedAt),
+				formatTime(user.UpdatedAt),
+			})
+		},
+	}
+}
+
+func budgetsDataset(budgets []*domain.Budget) dataset {
+	return dataset{
+		name:   "budgets",
+		header: []string{"id", "name", "currency", "archived_at", "created_at", "updated_at"},
+		each: func(fn func(any, []string) error) error {
+			for _, b := range budgets {
+				if err := fn(budgetRecord{
+					ID:         b.ID,
+					Name:       b.Name,
+					Currency:   b.Currency,
+					ArchivedAt: nullTimePtr(b.ArchivedAt),
+					CreatedAt:  b.CreatedAt,
+					UpdatedAt:  b.UpdatedAt,
+				}, []string{
+					b.ID,
+					transactionService.CSVSafe(b.Name),
+					b.Currency,
+					formatNullTime(b.ArchivedAt.Time, b.ArchivedAt.Valid),
+					formatTime(b.CreatedAt),
+					formatTime(b.UpdatedAt),
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func (s *Service) budgetHistoryDataset(ctx context.Context, budgets []*domain.Budget) dataset {
+	return dataset{
+		name:   "budgets_history",
+		header: []string{"id", "budget_id", "transaction_id", "balance", "created_at"},
+		each: func(fn func(any, []string) error) error {
+			for _, b := range budgets {
+				histories, err := s.budgetHistoryRepo.List(ctx, b.ID)
+				if err != nil {
+					return err
+				}
+				for _, h := range histories {
+					if err = fn(budgetHistoryRecord{
+						ID:            h.ID,
+						BudgetID:      h.BudgetID,
+						TransactionID: nullStringPtr(h.TransactionID.String, h.TransactionID.Valid),
+						Balance:       h.Balance,
+						CreatedAt:     h.CreatedAt,
+					}, []string{
+						h.ID,
+						h.BudgetID,
+						h.TransactionID.String,
+						h.Balance.String(),
+						formatTime(h.CreatedAt),
+					}); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// transactionsDataset streams the transactions from the database, once per file.
+func (s *Service) transactionsDataset(ctx context.Context, userID string) dataset {
+	return dataset{
+		name:   "transactions",
+		header: []string{"id", "date", "budget_id", "category_id", "category", "type", "amount", "balance", "note", "transfer_id", "external_id"},
+		each: func(fn func(any, []string) error) error {
+			return s.transactionRepo.Export(ctx, transaction.ListFilter{UserID: userID}, func(row *domain.TransactionExport) error {
+				var balance string
+				if row.Balance != nil {
+					balance = row.Balance.String()
+				}
+				return fn(transactionRecord{
+					ID:           row.ID,
+					BudgetID:     row.BudgetID,
+					CategoryID:   row.CategoryID,
+					CategoryName: row.CategoryName,
+					Type:         row.TransactionType,
+					Amount:       row.Amount,
+					Balance:      row.Balance,
+					Note:         row.Note,
+					TransferID:   nullStringPtr(row.TransferID.String, row.TransferID.Valid),
+					ExternalID:   nullStringPtr(row.ExternalID.String, row.ExternalID.Valid),
+					CreatedAt:    row.CreatedAt,
+				}, []string{
+					row.ID,
+					formatTime(row.CreatedAt),
+					row.BudgetID,
+					row.CategoryID,
+					transactionService.CSVSafe(row.CategoryName),
+					row.TransactionType,
+					row.Amount.String(),
+					balance,
+					transactionService.CSVSafe(row.Note),
+					row.TransferID.String,
+					row.ExternalID.String,
+				})
+			})
+		},
+	}
+}
+
+func categoriesDataset(categories []*domain.Category) dataset {
+	return dataset{
+		name:   "categories",
+		header: []string{"id", "parent_id", "name", "icon", "color", "created_at", "updated_at"},
+		each: func(fn func(any, []string) error) error {
+			for _, c := range categories {
+				if err := fn(categoryRecord{
+					ID:        c.ID,
+					ParentID:  c.ParentID.String,
+					Name:      c.Name,
+					Icon:      c.Icon,
+					Color:     c.Color,
+					CreatedAt: c.CreatedAt,
+					UpdatedAt: c.UpdatedAt,
+				}, []string{
+					c.ID,
+					c.ParentID.String,
+					transactionService.CSVSafe(c.Name),
+					transactionService.CSVSafe(c.Icon),
+					c.Color,
+					formatTime(c.CreatedAt),
+					formatTime(c.UpdatedAt),
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatNullTime(t time.Time, valid bool) string {
+	if !valid {
+		return ""
+	}
+	return formatTime(t)
+}
+
+func nullStringPtr(s string, valid bool) *string {
+	if !valid {
+		return nil
+	}
+	return &s
+}
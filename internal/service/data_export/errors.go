@@ -0,0 +1,16 @@
+package data_export
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+var errs = struct {
+	ExportNotFound *echo.HTTPError
+	ExportNotReady *echo.HTTPError
+	ExportExpired  *echo.HTTPError
+}{
+	ExportNotFound: echo.NewHTTPError(http.StatusNotFound, "Data export not found"),
+	ExportNotReady: echo.NewHTTPError(http.StatusConflict, "Data export is not ready for download"),
+	ExportExpired:  echo.NewHTTPError(http.StatusGone, "Data export has expired, request a new one"),
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/data_export/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/data_export/service.go -destination=internal/service/data_export/mock/mock_data_export.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	data_export "finly-backend/internal/service/data_export"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDataExport is a mock of DataExport interface.
+type MockDataExport struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportMockRecorder
+	isgomock struct{}
+}
+
+// MockDataExportMockRecorder is the mock recorder for MockDataExport.
+type MockDataExportMockRecorder struct {
+	mock *MockDataExport
+}
+
+// NewMockDataExport creates a new mock instance.
+func NewMockDataExport(ctrl *gomock.Controller) *MockDataExport {
+	mock := &MockDataExport{ctrl: ctrl}
+	mock.recorder = &MockDataExportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExport) EXPECT() *MockDataExportMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDataExport) Create(ctx context.Context, req *data_export.CreateDataExportRequest) (*data_export.CreateDataExportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*data_export.CreateDataExportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDataExportMockRecorder) Create(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDataExport)(nil).Create), ctx, req)
+}
+
+// Download mocks base method.
+func (m *MockDataExport) Download(ctx context.Context, req *data_export.DownloadDataExportRequest) (*data_export.DownloadDataExportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Download", ctx, req)
+	ret0, _ := ret[0].(*data_export.DownloadDataExportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Download indicates an expected call of Download.
+func (mr *MockDataExportMockRecorder) Download(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockDataExport)(nil).Download), ctx, req)
+}
+
+// Get mocks base method.
+func (m *MockDataExport) Get(ctx context.Context, req *data_export.GetDataExportRequest) (*data_export.GetDataExportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, req)
+	ret0, _ := ret[0].(*data_export.GetDataExportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDataExportMockRecorder) Get(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDataExport)(nil).Get), ctx, req)
+}
+
+// RunPending mocks base method.
+func (m *MockDataExport) RunPending(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunPending", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunPending indicates an expected call of RunPending.
+func (mr *MockDataExportMockRecorder) RunPending(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunPending", reflect.TypeOf((*MockDataExport)(nil).RunPending), ctx, now)
+}
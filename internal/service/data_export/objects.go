@@ -0,0 +1,42 @@
+package data_export
+
+import "time"
+
+type DataExportObject struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Size        int64      `json:"size,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+type CreateDataExportRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+}
+
+type CreateDataExportResponse struct {
+	DataExportObject
+}
+
+type GetDataExportRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	ID     string `param:"id" validate:"required,uuid"`
+}
+
+type GetDataExportResponse struct {
+	DataExportObject
+}
+
+type DownloadDataExportRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	ID     string `param:"id" validate:"required,uuid"`
+}
+
+// DownloadDataExportResponse is the ZIP archive of an export. The handler sends it as a
+// file, not as JSON.
+type DownloadDataExportResponse struct {
+	Filename string
+	Archive  []byte
+}
@@ -0,0 +1,179 @@
+package data_export
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/repository/auth"
+	"finly-backend/internal/repository/budget"
+	"finly-backend/internal/repository/budget_history"
+	"finly-backend/internal/repository/category"
+	"finly-backend/internal/repository/data_export"
+	"finly-backend/internal/repository/transaction"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// exportTTL is how long a finished archive can be downloaded.
+	exportTTL = 7 * 24 * time.Hour
+	// exportStaleAfter is when a running export is taken over, as its worker is assumed dead.
+	exportStaleAfter = 30 * time.Minute
+	// maxExportAttempts is how often an export is started before it is given up.
+	maxExportAttempts = 3
+	// exportBatchSize caps the exports built per scheduler tick.
+	exportBatchSize = 5
+)
+
+type DataExport interface {
+	Create(ctx context.Context, req *CreateDataExportRequest) (*CreateDataExportResponse, error)
+	Get(ctx context.Context, req *GetDataExportRequest) (*GetDataExportResponse, error)
+	Download(ctx context.Context, req *DownloadDataExportRequest) (*DownloadDataExportResponse, error)
+	RunPending(ctx context.Context, now time.Time) error
+}
+
+type Service struct {
+	exportRepo        data_export.DataExport
+	authRepo          auth.Auth
+	budgetRepo        budget.Budget
+	budgetHistoryRepo budget_history.BudgetHistory
+	categoryRepo      category.Category
+	transactionRepo   transaction.Transaction
+}
+
+func NewService(exportRepo data_export.DataExport, authRepo auth.Auth, budgetRepo budget.Budget, budgetHistoryRepo budget_history.BudgetHistory, categoryRepo category.Category, transactionRepo transaction.Transaction) *Service {
+	return &Service{
+		exportRepo:        exportRepo,
+		authRepo:          authRepo,
+		budgetRepo:        budgetRepo,
+		budgetHistoryRepo: budgetHistoryRepo,
+		categoryRepo:      categoryRepo,
+		transactionRepo:   transactionRepo,
+	}
+}
+
+// Create queues an export of everything stored about the user. The archive is built in the
+// background by RunPending; while an export is queued or running, it is returned instead
+// of starting another one.
+func (s *Service) Create(ctx context.Context, req *CreateDataExportRequest) (*CreateDataExportResponse, error) {
+	id, err := s.exportRepo.Create(ctx, req.UserID)
+	if err != nil && !errors.Is(err, data_export.ErrExportInProgress) {
+		zap.L().Sugar().Errorf("Create: failed to queue data export for userID=%s: %v", req.UserID, err)
+		return nil, err
+	}
+
+	var export *domain.DataExport
+	if err == nil {
+		export, err = s.exportRepo.GetByID(ctx, id, req.UserID)
+	} else {
+		export, err = s.exportRepo.GetActiveByUserID(ctx, req.UserID)
+	}
+	if err != nil {
+		zap.L().Sugar().Errorf("Create: failed to fetch data export for userID=%s: %v", req.UserID, err)
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Create: data export id=%s is %s for userID=%s", export.ID, export.Status, req.UserID)
+	return &CreateDataExportResponse{DataExportObject: convertDataExport(export)}, nil
+}
+
+func (s *Service) Get(ctx context.Context, req *GetDataExportRequest) (*GetDataExportResponse, error) {
+	export, err := s.exportRepo.GetByID(ctx, req.ID, req.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ExportNotFound
+		}
+		zap.L().Sugar().Errorf("Get: failed to fetch data export id=%s for userID=%s: %v", req.ID, req.UserID, err)
+		return nil, err
+	}
+
+	return &GetDataExportResponse{DataExportObject: convertDataExport(export)}, nil
+}
+
+func (s *Service) Download(ctx context.Context, req *DownloadDataExportRequest) (*DownloadDataExportResponse, error) {
+	export, err := s.exportRepo.GetByID(ctx, req.ID, req.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ExportNotFound
+		}
+		zap.L().Sugar().Errorf("Download: failed to fetch data export id=%s for userID=%s: %v", req.ID, req.UserID, err)
+		return nil, err
+	}
+	if export.Status != data_export.StatusCompleted {
+		return nil, errs.ExportNotReady
+	}
+
+	now := time.Now().UTC()
+	if !export.ExpiresAt.Time.After(now) {
+		return nil, errs.ExportExpired
+	}
+
+	archive, err := s.exportRepo.GetArchive(ctx, export.ID, req.UserID, now)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ExportExpired
+		}
+		zap.L().Sugar().Errorf("Download: failed to fetch archive of data export id=%s: %v", export.ID, err)
+		return nil, err
+	}
+
+	return &DownloadDataExportResponse{
+		Filename: fmt.Sprintf("finly-export-%s.zip", export.CompletedAt.Time.Format("2006-01-02")),
+		Archive:  archive,
+	}, nil
+}
+
+// RunPending removes expired archives and builds queued exports. It runs on the scheduler,
+// so a large account never holds up a request.
+func (s *Service) RunPending(ctx context.Context, now time.Time) error {
+	if deleted, err := s.exportRepo.DeleteExpired(ctx, now); err != nil {
+		zap.L().Sugar().Warnf("RunPending: failed to delete expired data exports: %v", err)
+	} else if deleted > 0 {
+		zap.L().Sugar().Infof("RunPending: deleted %d expired data exports", deleted)
+	}
+
+	for i := 0; i < exportBatchSize; i++ {
+		export, err := s.exportRepo.ClaimNext(ctx, now.Add(-exportStaleAfter))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			zap.L().Sugar().Errorf("RunPending: failed to claim data export: %v", err)
+			return err
+		}
+
+		if err = s.build(ctx, export, now); err != nil {
+			zap.L().Sugar().Warnf("RunPending: data export id=%s will be retried: %v", export.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// build writes the archive of a claimed export. An export that can't be built is marked
+// as failed; one interrupted by shutdown stays running and is taken over once stale.
+func (s *Service) build(ctx context.Context, export *domain.DataExport, now time.Time) error {
+	if export.Attempts > maxExportAttempts {
+		zap.L().Sugar().Errorf("build: giving up data export id=%s after %d attempts", export.ID, maxExportAttempts)
+		return s.exportRepo.Fail(ctx, export.ID, "The export did not finish, please request a new one", now.Add(exportTTL))
+	}
+
+	var buf bytes.Buffer
+	if err := s.writeArchive(ctx, export.UserID, &buf); err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		zap.L().Sugar().Errorf("build: failed to build data export id=%s for userID=%s: %v", export.ID, export.UserID, err)
+		return s.exportRepo.Fail(ctx, export.ID, "The export could not be built, please request a new one", now.Add(exportTTL))
+	}
+
+	if err := s.exportRepo.Complete(ctx, export.ID, buf.Bytes(), now.Add(exportTTL)); err != nil {
+		return err
+	}
+
+	zap.L().Sugar().Infof("build: data export id=%s completed for userID=%s", export.ID, export.UserID)
+	return nil
+}
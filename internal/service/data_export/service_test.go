@@ -0,0 +1,302 @@
+package data_export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"finly-backend/internal/domain"
+	mock_auth "finly-backend/internal/repository/auth/mock"
+	mock_budget "finly-backend/internal/repository/budget/mock"
+	mock_budget_history "finly-backend/internal/repository/budget_history/mock"
+	mock_category "finly-backend/internal/repository/category/mock"
+	"finly-backend/internal/repository/data_export"
+	"finly-backend/internal/repository/data_export/mock"
+	"finly-backend/internal/repository/transaction"
+	mock_transaction "finly-backend/internal/repository/transaction/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExportRepo := mock.NewMockDataExport(ctrl)
+	service := NewService(mockExportRepo, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	createdAt := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	pending := &domain.DataExport{ID: "export1", UserID: "user123", Status: data_export.StatusPending, CreatedAt: createdAt}
+	running := &domain.DataExport{ID: "export0", UserID: "user123", Status: data_export.StatusRunning, CreatedAt: createdAt}
+
+	tests := []struct {
+		name         string
+		mockSetup    func()
+		expectedResp *CreateDataExportResponse
+		expectedErr  error
+	}{
+		{
+			name: "Export queued",
+			mockSetup: func() {
+				mockExportRepo.EXPECT().Create(ctx, "user123").Return("export1", nil)
+				mockExportRepo.EXPECT().GetByID(ctx, "export1", "user123").Return(pending, nil)
+			},
+			expectedResp: &CreateDataExportResponse{DataExportObject: DataExportObject{ID: "export1", Status: data_export.StatusPending, CreatedAt: createdAt}},
+		},
+		{
+			name: "Export already in progress",
+			mockSetup: func() {
+				mockExportRepo.EXPECT().Create(ctx, "user123").Return("", data_export.ErrExportInProgress)
+				mockExportRepo.EXPECT().GetActiveByUserID(ctx, "user123").Return(running, nil)
+			},
+			expectedResp: &CreateDataExportResponse{DataExportObject: DataExportObject{ID: "export0", Status: data_export.StatusRunning, CreatedAt: createdAt}},
+		},
+		{
+			name: "Database error",
+			mockSetup: func() {
+				mockExportRepo.EXPECT().Create(ctx, "user123").Return("", errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.Create(ctx, &CreateDataExportRequest{UserID: "user123"})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExportRepo := mock.NewMockDataExport(ctrl)
+	service := NewService(mockExportRepo, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	t.Run("Not found", func(t *testing.T) {
+		mockExportRepo.EXPECT().GetByID(ctx, "export1", "user123").Return(nil, sql.ErrNoRows)
+
+		resp, err := service.Get(ctx, &GetDataExportRequest{UserID: "user123", ID: "export1"})
+		assert.Equal(t, errs.ExportNotFound, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestDownload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExportRepo := mock.NewMockDataExport(ctrl)
+	service := NewService(mockExportRepo, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	completedAt := sql.NullTime{Time: time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC), Valid: true}
+	completed := &domain.DataExport{
+		ID:          "export1",
+		UserID:      "user123",
+		Status:      data_export.StatusCompleted,
+		CompletedAt: completedAt,
+		ExpiresAt:   sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+	}
+	expired := *completed
+	expired.ExpiresAt = sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}
+
+	tests := []struct {
+		name         string
+		mockSetup    func()
+		expectedResp *DownloadDataExportResponse
+		expectedErr  error
+	}{
+		{
+			name: "Archive downloaded",
+			mockSetup: func() {
+				mockExportRepo.EXPECT().GetByID(ctx, "export1", "user123").Return(completed, nil)
+				mockExportRepo.EXPECT().GetArchive(ctx, "export1", "user123", gomock.Any()).Return([]byte("zip"), nil)
+			},
+			expectedResp: &DownloadDataExportResponse{Filename: "finly-export-2025-04-01.zip", Archive: []byte("zip")},
+		},
+		{
+			name: "Export still running",
+			mockSetup: func() {
+				mockExportRepo.EXPECT().GetByID(ctx, "export1", "user123").
+					Return(&domain.DataExport{ID: "export1", Status: data_export.StatusRunning}, nil)
+			},
+			expectedErr: errs.ExportNotReady,
+		},
+		{
+			name: "Export expired",
+			mockSetup: func() {
+				mockExportRepo.EXPECT().GetByID(ctx, "export1", "user123").Return(&expired, nil)
+			},
+			expectedErr: errs.ExportExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			resp, err := service.Download(ctx, &DownloadDataExportRequest{UserID: "user123", ID: "export1"})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedResp, resp)
+		})
+	}
+}
+
+func TestRunPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExportRepo := mock.NewMockDataExport(ctrl)
+	mockAuthRepo := mock_auth.NewMockAuth(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockBudgetHistoryRepo := mock_budget_history.NewMockBudgetHistory(ctrl)
+	mockCategoryRepo := mock_category.NewMockCategory(ctrl)
+	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	service := NewService(mockExportRepo, mockAuthRepo, mockBudgetRepo, mockBudgetHistoryRepo, mockCategoryRepo, mockTransactionRepo)
+	ctx := context.Background()
+
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	staleBefore := now.Add(-exportStaleAfter)
+
+	t.Run("Archive built", func(t *testing.T) {
+		user := &domain.User{ID: "user123", Email: "test@example.com", FirstName: "John", LastName: "Doe", PasswordHash: "secret-hash", CreatedAt: now}
+		budgets := []*domain.Budget{
+			{ID: "budget1", UserID: "user123", Name: "Main", Currency: "USD", CreatedAt: now},
+			{ID: "budget2", UserID: "user456", Name: "Household", Currency: "USD", CreatedAt: now},
+		}
+		histories := []*domain.BudgetHistory{{ID: "history1", BudgetID: "budget1", Balance: domain.MustParseMoney("90.00"), CreatedAt: now}}
+		categories := []*domain.Category{{ID: "category1", Name: "=Pets", CreatedAt: now}}
+		balance := domain.MustParseMoney("90.00")
+		rows := []*domain.TransactionExport{{
+			Transaction: domain.Transaction{
+				ID:              "tx1",
+				BudgetID:        "budget1",
+				CategoryID:      "category1",
+				Amount:          domain.MustParseMoney("10.00"),
+				TransactionType: "withdrawal",
+				Note:            "Food",
+				CreatedAt:       now,
+			},
+			CategoryName: "=Pets",
+			Balance:      &balance,
+		}}
+
+		mockExportRepo.EXPECT().DeleteExpired(ctx, now).Return(int64(0), nil)
+		mockExportRepo.EXPECT().ClaimNext(ctx, staleBefore).Return(&domain.DataExport{ID: "export1", UserID: "user123", Attempts: 1}, nil)
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").Return(budgets, nil)
+		mockCategoryRepo.EXPECT().ListCustom(ctx, "user123").Return(categories, nil)
+		mockBudgetHistoryRepo.EXPECT().List(ctx, "budget1").Return(histories, nil).Times(2)
+		mockTransactionRepo.EXPECT().Export(ctx, transaction.ListFilter{UserID: "user123"}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ transaction.ListFilter, fn func(*domain.TransactionExport) error) error {
+				for _, row := range rows {
+					if err := fn(row); err != nil {
+						return err
+					}
+				}
+				return nil
+			}).Times(2)
+
+		var archive []byte
+		mockExportRepo.EXPECT().Complete(ctx, "export1", gomock.Any(), now.Add(exportTTL)).
+			DoAndReturn(func(_ context.Context, _ string, b []byte, _ time.Time) error {
+				archive = b
+				return nil
+			})
+		mockExportRepo.EXPECT().ClaimNext(ctx, staleBefore).Return(nil, sql.ErrNoRows)
+
+		err := service.RunPending(ctx, now)
+		assert.NoError(t, err)
+
+		files := readArchive(t, archive)
+		assert.Len(t, files, 10)
+		for _, name := range []string{"profile", "budgets", "budgets_history", "transactions", "categories"} {
+			assert.Contains(t, files, name+".json")
+			assert.Contains(t, files, name+".csv")
+		}
+		assert.NotContains(t, string(files["profile.json"]), "secret-hash")
+
+		var profile profileRecord
+		assert.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+		assert.Equal(t, "test@example.com", profile.Email)
+
+		var txs []transactionRecord
+		assert.NoError(t, json.Unmarshal(files["transactions.json"], &txs))
+		if assert.Len(t, txs, 1) {
+			assert.Equal(t, "=Pets", txs[0].CategoryName)
+			assert.Equal(t, "90.00", txs[0].Balance.String())
+		}
+
+		records, err := csv.NewReader(bytes.NewReader(files["categories.csv"])).ReadAll()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"id", "parent_id", "name", "icon", "color", "created_at", "updated_at"},
+			{"category1", "", "'=Pets", "", "", "2025-04-01T12:00:00Z", "0001-01-01T00:00:00Z"},
+		}, records)
+
+		var exported []budgetRecord
+		assert.NoError(t, json.Unmarshal(files["budgets.json"], &exported))
+		if assert.Len(t, exported, 1) {
+			assert.Equal(t, "budget1", exported[0].ID)
+		}
+
+		var history []budgetHistoryRecord
+		assert.NoError(t, json.Unmarshal(files["budgets_history.json"], &history))
+		assert.Len(t, history, 1)
+	})
+
+	t.Run("Failed export is given up", func(t *testing.T) {
+		mockExportRepo.EXPECT().DeleteExpired(ctx, now).Return(int64(1), nil)
+		mockExportRepo.EXPECT().ClaimNext(ctx, staleBefore).Return(&domain.DataExport{ID: "export1", UserID: "user123", Attempts: maxExportAttempts + 1}, nil)
+		mockExportRepo.EXPECT().Fail(ctx, "export1", gomock.Any(), now.Add(exportTTL)).Return(nil)
+		mockExportRepo.EXPECT().ClaimNext(ctx, staleBefore).Return(nil, sql.ErrNoRows)
+
+		err := service.RunPending(ctx, now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Build error fails the export", func(t *testing.T) {
+		mockExportRepo.EXPECT().DeleteExpired(ctx, now).Return(int64(0), nil)
+		mockExportRepo.EXPECT().ClaimNext(ctx, staleBefore).Return(&domain.DataExport{ID: "export1", UserID: "user123", Attempts: 1}, nil)
+		mockAuthRepo.EXPECT().GetUserByID(ctx, "user123").Return(nil, errors.New("db error"))
+		mockExportRepo.EXPECT().Fail(ctx, "export1", gomock.Any(), now.Add(exportTTL)).Return(nil)
+		mockExportRepo.EXPECT().ClaimNext(ctx, staleBefore).Return(nil, sql.ErrNoRows)
+
+		err := service.RunPending(ctx, now)
+		assert.NoError(t, err)
+	})
+}
+
+func readArchive(t *testing.T, archive []byte) map[string][]byte {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	files := make(map[string][]byte, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		assert.NoError(t, err)
+		files[f.Name] = b
+	}
+	return files
+}
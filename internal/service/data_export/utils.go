@@ -0,0 +1,26 @@
+package data_export
+
+import (
+	"database/sql"
+	"finly-backend/internal/domain"
+	"time"
+)
+
+func convertDataExport(export *domain.DataExport) DataExportObject {
+	return DataExportObject{
+		ID:          export.ID,
+		Status:      export.Status,
+		Size:        export.Size.Int64,
+		Error:       export.Error.String,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: nullTimePtr(export.CompletedAt),
+		ExpiresAt:   nullTimePtr(export.ExpiresAt),
+	}
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
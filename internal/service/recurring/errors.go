@@ -0,0 +1,33 @@
+package recurring
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+var errs = struct {
+	RecurringNotFound *echo.HTTPError
+	BudgetNotFound    *echo.HTTPError
+	BudgetForbidden   *echo.HTTPError
+	BudgetArchived    *echo.HTTPError
+	CategoryNotFound  *echo.HTTPError
+	InvalidSchedule   *echo.HTTPError
+	DatabaseError     *echo.HTTPError
+}{
+	RecurringNotFound: echo.NewHTTPError(http.StatusNotFound, "Recurring transaction not found"),
+	BudgetNotFound:    echo.NewHTTPError(http.StatusNotFound, "Budget not found"),
+	BudgetForbidden:   echo.NewHTTPError(http.StatusForbidden, "Your role in this budget does not allow this"),
+	BudgetArchived:    echo.NewHTTPError(http.StatusConflict, "Budget is archived; unarchive it to make changes"),
+	CategoryNotFound:  echo.NewHTTPError(http.StatusNotFound, "Category not found"),
+	InvalidSchedule:   echo.NewHTTPError(http.StatusBadRequest, "Invalid recurrence schedule"),
+	DatabaseError:     echo.NewHTTPError(http.StatusInternalServerError, "Database operation failed"),
+}
+
+// errRunInProgress stops a rule at an occurrence whose run is still pending, e.g. claimed by
+// another instance. The rule is not advanced past it until the run is completed or failed.
+var errRunInProgress = errors.New("recurring run is in progress")
+
+// errRuleChanged stops a rule that was edited, paused or deleted after it was listed as due.
+// Its occurrences are left to the next tick, which lists it with its new schedule.
+var errRuleChanged = errors.New("recurring rule changed while running")
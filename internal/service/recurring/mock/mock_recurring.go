@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/recurring/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/recurring/service.go -destination=internal/service/recurring/mock/mock_recurring.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	recurring "finly-backend/internal/service/recurring"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRecurring is a mock of Recurring interface.
+type MockRecurring struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecurringMockRecorder
+	isgomock struct{}
+}
+
+// MockRecurringMockRecorder is the mock recorder for MockRecurring.
+type MockRecurringMockRecorder struct {
+	mock *MockRecurring
+}
+
+// NewMockRecurring creates a new mock instance.
+func NewMockRecurring(ctrl *gomock.Controller) *MockRecurring {
+	mock := &MockRecurring{ctrl: ctrl}
+	mock.recorder = &MockRecurringMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecurring) EXPECT() *MockRecurringMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRecurring) Create(ctx context.Context, req *recurring.CreateRecurringRequest) (*recurring.CreateRecurringResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*recurring.CreateRecurringResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRecurringMockRecorder) Create(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRecurring)(nil).Create), ctx, req)
+}
+
+// Delete mocks base method.
+func (m *MockRecurring) Delete(ctx context.Context, req *recurring.DeleteRecurringRequest) (*recurring.DeleteRecurringResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, req)
+	ret0, _ := ret[0].(*recurring.DeleteRecurringResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRecurringMockRecorder) Delete(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRecurring)(nil).Delete), ctx, req)
+}
+
+// GetByID mocks base method.
+func (m *MockRecurring) GetByID(ctx context.Context, req *recurring.GetRecurringByIDRequest) (*recurring.GetRecurringByIDResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, req)
+	ret0, _ := ret[0].(*recurring.GetRecurringByIDResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRecurringMockRecorder) GetByID(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRecurring)(nil).GetByID), ctx, req)
+}
+
+// List mocks base method.
+func (m *MockRecurring) List(ctx context.Context, req *recurring.ListRecurringRequest) (*recurring.ListRecurringResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(*recurring.ListRecurringResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRecurringMockRecorder) List(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRecurring)(nil).List), ctx, req)
+}
+
+// RunDue mocks base method.
+func (m *MockRecurring) RunDue(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunDue", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunDue indicates an expected call of RunDue.
+func (mr *MockRecurringMockRecorder) RunDue(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunDue", reflect.TypeOf((*MockRecurring)(nil).RunDue), ctx, now)
+}
+
+// Update mocks base method.
+func (m *MockRecurring) Update(ctx context.Context, req *recurring.UpdateRecurringRequest) (*recurring.UpdateRecurringResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, req)
+	ret0, _ := ret[0].(*recurring.UpdateRecurringResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRecurringMockRecorder) Update(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRecurring)(nil).Update), ctx, req)
+}
@@ -0,0 +1,109 @@
+package recurring
+
+import (
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_recurrence_frequency"
+	"finly-backend/internal/domain/enums/e_transaction_type"
+	"time"
+)
+
+const (
+	// dueBatchSize caps how many rules a single scheduler tick picks up.
+	dueBatchSize = 100
+	// maxCatchUpRuns caps how many missed occurrences of one rule are created per tick.
+	maxCatchUpRuns = 400
+)
+
+type RecurringObject struct {
+	ID         string                      `json:"id"`
+	BudgetID   string                      `json:"budget_id"`
+	CategoryID string                      `json:"category_id"`
+	Amount     domain.Money                `json:"amount" swaggertype:"number"`
+	Type       e_transaction_type.Enum     `json:"type"`
+	Note       string                      `json:"note"`
+	Frequency  e_recurrence_frequency.Enum `json:"frequency"`
+	Interval   int                         `json:"interval"`
+	CronExpr   string                      `json:"cron_expr,omitempty"`
+	StartAt    time.Time                   `json:"start_at"`
+	EndAt      *time.Time                  `json:"end_at,omitempty"`
+	NextRunAt  *time.Time                  `json:"next_run_at,omitempty"`
+	LastRunAt  *time.Time                  `json:"last_run_at,omitempty"`
+	IsActive   bool                        `json:"is_active"`
+	CreatedAt  time.Time                   `json:"created_at"`
+}
+
+type CreateRecurringRequest struct {
+	UserID     string                      `header:"User-Id" validate:"required"`
+	BudgetID   string                      `json:"budget_id" validate:"required,uuid"`
+	CategoryID string                      `json:"category_id" validate:"required,uuid"`
+	Amount     domain.Money                `json:"amount" validate:"required,gt=0" swaggertype:"number"`
+	Type       e_transaction_type.Enum     `json:"type" validate:"required,oneof=deposit withdrawal"`
+	Note       string                      `json:"note" validate:"max=255"`
+	Frequency  e_recurrence_frequency.Enum `json:"frequency" validate:"required,oneof=daily weekly monthly cron"`
+	Interval   int                         `json:"interval" validate:"omitempty,min=1,max=365"`
+	CronExpr   string                      `json:"cron_expr" validate:"required_if=Frequency cron,max=100"`
+	StartAt    time.Time                   `json:"start_at" validate:"required"`
+	EndAt      *time.Time                  `json:"end_at"`
+}
+
+type CreateRecurringResponse struct {
+	ID        string     `json:"id"`
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+}
+
+type ListRecurringRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+}
+
+type ListRecurringResponse struct {
+	Recurring []*RecurringObject `json:"recurring"`
+}
+
+type GetRecurringByIDRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	ID     string `param:"id" validate:"required"`
+}
+
+type GetRecurringByIDResponse struct {
+	*RecurringObject
+}
+
+type UpdateRecurringRequest struct {
+	UserID     string       `header:"User-Id" validate:"required"`
+	ID         string       `param:"id" validate:"required"`
+	CategoryID string       `json:"category_id,omitempty" validate:"omitempty,uuid"`
+	Amount     domain.Money `json:"amount,omitempty" validate:"omitempty,gt=0" swaggertype:"number"`
+	Note       *string      `json:"note,omitempty" validate:"omitempty,max=255"`
+	EndAt      *time.Time   `json:"end_at,omitempty"`
+	IsActive   *bool        `json:"is_active,omitempty"`
+}
+
+type UpdateRecurringResponse struct{}
+
+type DeleteRecurringRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	ID     string `param:"id" validate:"required"`
+}
+
+type DeleteRecurringResponse struct{}
+
+func convertRecurring(rule *domain.RecurringTransaction) *RecurringObject {
+	obj := &RecurringObject{
+		ID:         rule.ID,
+		BudgetID:   rule.BudgetID,
+		CategoryID: rule.CategoryID,
+		Amount:     rule.Amount,
+		Type:       e_transaction_type.Enum(rule.TransactionType),
+		Note:       rule.Note,
+		Frequency:  e_recurrence_frequency.Enum(rule.Frequency),
+		Interval:   rule.IntervalCount,
+		CronExpr:   rule.CronExpr.String,
+		StartAt:    rule.StartAt,
+		IsActive:   rule.IsActive,
+		CreatedAt:  rule.CreatedAt,
+	}
+	obj.EndAt = nullTimePtr(rule.EndAt)
+	obj.NextRunAt = nullTimePtr(rule.NextRunAt)
+	obj.LastRunAt = nullTimePtr(rule.LastRunAt)
+	return obj
+}
@@ -0,0 +1,378 @@
+package recurring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_budget_role"
+	"finly-backend/internal/domain/enums/e_recurrence_frequency"
+	"finly-backend/internal/domain/enums/e_transaction_type"
+	"finly-backend/internal/repository/budget"
+	"finly-backend/internal/repository/category"
+	"finly-backend/internal/repository/recurring"
+	"finly-backend/internal/service/transaction"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"slices"
+	"time"
+)
+
+type Recurring interface {
+	Create(ctx context.Context, req *CreateRecurringRequest) (*CreateRecurringResponse, error)
+	List(ctx context.Context, req *ListRecurringRequest) (*ListRecurringResponse, error)
+	GetByID(ctx context.Context, req *GetRecurringByIDRequest) (*GetRecurringByIDResponse, error)
+	Update(ctx context.Context, req *UpdateRecurringRequest) (*UpdateRecurringResponse, error)
+	Delete(ctx context.Context, req *DeleteRecurringRequest) (*DeleteRecurringResponse, error)
+	RunDue(ctx context.Context, now time.Time) error
+}
+
+type Service struct {
+	recurringRepo recurring.Recurring
+	budgetRepo    budget.Budget
+	categoryRepo  category.Category
+
+	transactionService transaction.Transaction
+}
+
+func NewService(recurringRepo recurring.Recurring, budgetRepo budget.Budget, categoryRepo category.Category, transactionService transaction.Transaction) *Service {
+	return &Service{
+		recurringRepo:      recurringRepo,
+		budgetRepo:         budgetRepo,
+		categoryRepo:       categoryRepo,
+		transactionService: transactionService,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, req *CreateRecurringRequest) (*CreateRecurringResponse, error) {
+	budget, err := s.budgetRepo.GetByID(ctx, req.BudgetID, req.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.BudgetNotFound
+		}
+		zap.L().Sugar().Errorf("Create: failed to get budgetID=%s for userID=%s: %v", req.BudgetID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+	// The rule books transactions as the user, so viewers cannot schedule any.
+	if !e_budget_role.Enum(budget.Role).Allows(e_budget_role.Editor) {
+		return nil, errs.BudgetForbidden
+	}
+	if budget.ArchivedAt.Valid {
+		return nil, errs.BudgetArchived
+	}
+	if err = s.checkCategory(ctx, budget, req.CategoryID); err != nil {
+		return nil, err
+	}
+
+	rule := &domain.RecurringTransaction{
+		UserID:          req.UserID,
+		BudgetID:        req.BudgetID,
+		CategoryID:      req.CategoryID,
+		Amount:          req.Amount,
+		TransactionType: req.Type.String(),
+		Note:            req.Note,
+		Frequency:       req.Frequency.String(),
+		IntervalCount:   req.Interval,
+		StartAt:         req.StartAt.UTC(),
+		IsActive:        true,
+	}
+	if rule.IntervalCount == 0 {
+		rule.IntervalCount = 1
+	}
+	if req.Frequency == e_recurrence_frequency.Cron {
+		rule.CronExpr = sql.NullString{String: req.CronExpr, Valid: true}
+	}
+	if req.EndAt != nil {
+		if req.EndAt.Before(req.StartAt) {
+			return nil, errs.InvalidSchedule
+		}
+		rule.EndAt = sql.NullTime{Time: req.EndAt.UTC(), Valid: true}
+	}
+
+	next, err := firstOccurrence(rule)
+	if err != nil {
+		return nil, err
+	}
+	if !next.Valid {
+		zap.L().Sugar().Warnf("Create: schedule never fires for userID=%s", req.UserID)
+		return nil, errs.InvalidSchedule
+	}
+	rule.NextRunAt = next
+
+	id, err := s.recurringRepo.Create(ctx, rule)
+	if err != nil {
+		zap.L().Sugar().Errorf("Create: failed to create recurring transaction for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("Create: recurring transaction %s created for userID=%s, next run at %v", id, req.UserID, next.Time)
+	return &CreateRecurringResponse{ID: id, NextRunAt: nullTimePtr(next)}, nil
+}
+
+func (s *Service) List(ctx context.Context, req *ListRecurringRequest) (*ListRecurringResponse, error) {
+	rules, err := s.recurringRepo.ListByUserID(ctx, req.UserID)
+	if err != nil {
+		zap.L().Sugar().Errorf("List: failed for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	list := make([]*RecurringObject, 0, len(rules))
+	for _, rule := range rules {
+		list = append(list, convertRecurring(rule))
+	}
+
+	return &ListRecurringResponse{Recurring: list}, nil
+}
+
+func (s *Service) GetByID(ctx context.Context, req *GetRecurringByIDRequest) (*GetRecurringByIDResponse, error) {
+	rule, err := s.getOwnedRule(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetRecurringByIDResponse{convertRecurring(rule)}, nil
+}
+
+func (s *Service) Update(ctx context.Context, req *UpdateRecurringRequest) (*UpdateRecurringResponse, error) {
+	rule, err := s.getOwnedRule(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	budget, err := s.budgetRepo.GetByID(ctx, rule.BudgetID, req.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.BudgetNotFound
+		}
+		zap.L().Sugar().Errorf("Update: failed to get budgetID=%s for userID=%s: %v", rule.BudgetID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+	// The user may have been demoted or the budget archived since the rule was created.
+	if !e_budget_role.Enum(budget.Role).Allows(e_budget_role.Editor) {
+		return nil, errs.BudgetForbidden
+	}
+	if budget.ArchivedAt.Valid {
+		return nil, errs.BudgetArchived
+	}
+
+	if req.CategoryID != "" && req.CategoryID != rule.CategoryID {
+		if err = s.checkCategory(ctx, budget, req.CategoryID); err != nil {
+			return nil, err
+		}
+		rule.CategoryID = req.CategoryID
+	}
+	if !req.Amount.IsZero() {
+		rule.Amount = req.Amount
+	}
+	if req.Note != nil {
+		rule.Note = *req.Note
+	}
+	if req.EndAt != nil {
+		if req.EndAt.Before(rule.StartAt) {
+			return nil, errs.InvalidSchedule
+		}
+		rule.EndAt = sql.NullTime{Time: req.EndAt.UTC(), Valid: true}
+		if rule.NextRunAt.Valid {
+			rule.NextRunAt = withinEnd(rule, rule.NextRunAt.Time)
+		}
+	}
+	if req.IsActive != nil {
+		// Resuming a paused rule skips the occurrences missed while it was paused.
+		if *req.IsActive && !rule.IsActive && rule.NextRunAt.Valid {
+			if rule.NextRunAt, err = skipPast(rule, rule.NextRunAt, time.Now().UTC()); err != nil {
+				return nil, err
+			}
+		}
+		rule.IsActive = *req.IsActive
+	}
+	if !rule.NextRunAt.Valid {
+		rule.IsActive = false
+	}
+
+	if err = s.recurringRepo.Update(ctx, rule); err != nil {
+		zap.L().Sugar().Errorf("Update: failed for ruleID=%s, userID=%s: %v", req.ID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("Update: completed for ruleID=%s, userID=%s", req.ID, req.UserID)
+	return &UpdateRecurringResponse{}, nil
+}
+
+func (s *Service) Delete(ctx context.Context, req *DeleteRecurringRequest) (*DeleteRecurringResponse, error) {
+	if _, err := s.getOwnedRule(ctx, req.ID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.recurringRepo.Delete(ctx, req.ID, req.UserID); err != nil {
+		zap.L().Sugar().Errorf("Delete: failed for ruleID=%s, userID=%s: %v", req.ID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("Delete: completed for ruleID=%s, userID=%s", req.ID, req.UserID)
+	return &DeleteRecurringResponse{}, nil
+}
+
+// RunDue creates the transactions of every occurrence that is due at now, including
+// occurrences missed while the service was down. Each occurrence is claimed before its
+// transaction is created, and the transaction carries the occurrence as its external ID, so
+// overlapping ticks, instances or retries of an interrupted run never create it twice.
+func (s *Service) RunDue(ctx context.Context, now time.Time) error {
+	rules, err := s.recurringRepo.ListDue(ctx, now, dueBatchSize)
+	if err != nil {
+		zap.L().Sugar().Errorf("RunDue: failed to list due rules: %v", err)
+		return err
+	}
+
+	for _, rule := range rules {
+		if err = s.runRule(ctx, rule, now); errors.Is(err, errRunInProgress) {
+			zap.L().Sugar().Infof("RunDue: ruleID=%s waits for a run in progress", rule.ID)
+		} else if errors.Is(err, errRuleChanged) {
+			zap.L().Sugar().Infof("RunDue: ruleID=%s changed while running, its new schedule is picked up on the next tick", rule.ID)
+		} else if err != nil {
+			zap.L().Sugar().Warnf("RunDue: ruleID=%s will be retried on the next tick: %v", rule.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) runRule(ctx context.Context, rule *domain.RecurringTransaction, now time.Time) error {
+	next := rule.NextRunAt
+	for i := 0; i < maxCatchUpRuns && next.Valid && !next.Time.After(now); i++ {
+		occurrenceAt := next.Time
+
+		current, err := s.runOccurrence(ctx, rule, occurrenceAt)
+		if err != nil {
+			return err
+		}
+		rule = current
+
+		following, err := nextOccurrence(rule, occurrenceAt)
+		if err != nil {
+			return err
+		}
+
+		if err = s.recurringRepo.Advance(ctx, rule, occurrenceAt, following); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errRuleChanged
+			}
+			return err
+		}
+
+		next = following
+	}
+
+	return nil
+}
+
+// runOccurrence books the occurrence of the rule at occurrenceAt unless it was already
+// processed. It returns the rule as it is in the database once the occurrence is claimed.
+func (s *Service) runOccurrence(ctx context.Context, rule *domain.RecurringTransaction, occurrenceAt time.Time) (*domain.RecurringTransaction, error) {
+	runID, claimed, err := s.recurringRepo.ClaimRun(ctx, rule.ID, occurrenceAt)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		status, err := s.recurringRepo.RunStatus(ctx, rule.ID, occurrenceAt)
+		if errors.Is(err, sql.ErrNoRows) || status == recurring.RunStatusPending {
+			return nil, errRunInProgress
+		}
+		if err != nil {
+			return nil, err
+		}
+		zap.L().Sugar().Infof("runOccurrence: ruleID=%s at %v already processed", rule.ID, occurrenceAt)
+		return s.reloadRule(ctx, rule.ID, occurrenceAt)
+	}
+
+	// The rule was listed before the claim; it may have been edited, paused or deleted since.
+	current, err := s.reloadRule(ctx, rule.ID, occurrenceAt)
+	if err != nil {
+		if releaseErr := s.recurringRepo.ReleaseRun(ctx, runID); releaseErr != nil {
+			zap.L().Sugar().Errorf("runOccurrence: failed to release runID=%s: %v", runID, releaseErr)
+		}
+		return nil, err
+	}
+
+	res, err := s.transactionService.Create(ctx, &transaction.CreateTransactionRequest{
+		UserID:     current.UserID,
+		CategoryID: current.CategoryID,
+		BudgetID:   current.BudgetID,
+		Amount:     current.Amount,
+		Type:       e_transaction_type.Enum(current.TransactionType),
+		Note:       current.Note,
+		At:         occurrenceAt,
+		ExternalID: fmt.Sprintf("recurring:%s:%s", current.ID, occurrenceAt.UTC().Format(time.RFC3339Nano)),
+	})
+	if errors.Is(err, transaction.ErrAlreadyBooked) {
+		// An earlier attempt booked the occurrence but stopped before completing its run.
+		zap.L().Sugar().Infof("runOccurrence: ruleID=%s at %v was already booked", rule.ID, occurrenceAt)
+		return current, s.recurringRepo.CompleteRun(ctx, runID, "")
+	}
+	if err != nil {
+		// Client errors (e.g. insufficient balance) will not go away on retry: record them and move on.
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Code < 500 {
+			zap.L().Sugar().Warnf("runOccurrence: ruleID=%s at %v failed: %v", rule.ID, occurrenceAt, httpErr.Message)
+			return current, s.recurringRepo.FailRun(ctx, runID, fmt.Sprint(httpErr.Message))
+		}
+
+		if releaseErr := s.recurringRepo.ReleaseRun(ctx, runID); releaseErr != nil {
+			zap.L().Sugar().Errorf("runOccurrence: failed to release runID=%s: %v", runID, releaseErr)
+		}
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("runOccurrence: ruleID=%s at %v created transactionID=%s", rule.ID, occurrenceAt, res.ID)
+	return current, s.recurringRepo.CompleteRun(ctx, runID, res.ID)
+}
+
+// reloadRule reads the rule from the database and returns errRuleChanged unless it is still
+// active and due at occurrenceAt within its end date.
+func (s *Service) reloadRule(ctx context.Context, ruleID string, occurrenceAt time.Time) (*domain.RecurringTransaction, error) {
+	rule, err := s.recurringRepo.GetForRun(ctx, ruleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errRuleChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !rule.IsActive || !rule.NextRunAt.Valid || !rule.NextRunAt.Time.Equal(occurrenceAt) ||
+		(rule.EndAt.Valid && occurrenceAt.After(rule.EndAt.Time)) {
+		return nil, errRuleChanged
+	}
+
+	return rule, nil
+}
+
+// checkCategory makes sure the category is one the budget owner can use, since the
+// transactions the rule creates are booked into the owner's budget.
+func (s *Service) checkCategory(ctx context.Context, budget *domain.Budget, categoryID string) error {
+	categories, err := s.categoryRepo.List(ctx, budget.UserID)
+	if err != nil {
+		zap.L().Sugar().Errorf("failed to list categories for userID=%s: %v", budget.UserID, err)
+		return errs.DatabaseError
+	}
+
+	if !slices.ContainsFunc(categories, func(category *domain.Category) bool { return category.ID == categoryID }) {
+		zap.L().Sugar().Warnf("categoryID=%s can't be used in budgetID=%s", categoryID, budget.ID)
+		return errs.CategoryNotFound
+	}
+
+	return nil
+}
+
+func (s *Service) getOwnedRule(ctx context.Context, ruleID, userID string) (*domain.RecurringTransaction, error) {
+	rule, err := s.recurringRepo.GetByID(ctx, ruleID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			zap.L().Sugar().Warnf("recurring transaction not found for ruleID=%s, userID=%s", ruleID, userID)
+			return nil, errs.RecurringNotFound
+		}
+		zap.L().Sugar().Errorf("failed to get ruleID=%s for userID=%s: %v", ruleID, userID, err)
+		return nil, errs.DatabaseError
+	}
+
+	return rule, nil
+}
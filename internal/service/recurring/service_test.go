@@ -0,0 +1,568 @@
+package recurring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_recurrence_frequency"
+	"finly-backend/internal/domain/enums/e_transaction_type"
+	mock_budget "finly-backend/internal/repository/budget/mock"
+	mock_category "finly-backend/internal/repository/category/mock"
+	"finly-backend/internal/repository/recurring"
+	mock_recurring "finly-backend/internal/repository/recurring/mock"
+	"finly-backend/internal/service/transaction"
+	mock_transaction "finly-backend/internal/service/transaction/mock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	start := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rule     *domain.RecurringTransaction
+		after    time.Time
+		expected sql.NullTime
+	}{
+		{
+			name:     "Daily with interval",
+			rule:     &domain.RecurringTransaction{Frequency: "daily", IntervalCount: 3, StartAt: start},
+			after:    start,
+			expected: sql.NullTime{Time: start.AddDate(0, 0, 3), Valid: true},
+		},
+		{
+			name:     "Weekly",
+			rule:     &domain.RecurringTransaction{Frequency: "weekly", IntervalCount: 1, StartAt: start},
+			after:    start,
+			expected: sql.NullTime{Time: time.Date(2025, 2, 7, 9, 0, 0, 0, time.UTC), Valid: true},
+		},
+		{
+			name:     "Monthly clamps to end of February",
+			rule:     &domain.RecurringTransaction{Frequency: "monthly", IntervalCount: 1, StartAt: start},
+			after:    start,
+			expected: sql.NullTime{Time: time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC), Valid: true},
+		},
+		{
+			name:     "Monthly returns to anchor day",
+			rule:     &domain.RecurringTransaction{Frequency: "monthly", IntervalCount: 1, StartAt: start},
+			after:    time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC),
+			expected: sql.NullTime{Time: time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC), Valid: true},
+		},
+		{
+			name:     "Cron",
+			rule:     &domain.RecurringTransaction{Frequency: "cron", CronExpr: sql.NullString{String: "0 8 * * 1", Valid: true}, StartAt: start},
+			after:    start,
+			expected: sql.NullTime{Time: time.Date(2025, 2, 3, 8, 0, 0, 0, time.UTC), Valid: true},
+		},
+		{
+			name: "Past end date",
+			rule: &domain.RecurringTransaction{
+				Frequency: "daily", IntervalCount: 1, StartAt: start,
+				EndAt: sql.NullTime{Time: start.Add(12 * time.Hour), Valid: true},
+			},
+			after:    start,
+			expected: sql.NullTime{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := nextOccurrence(tt.rule, tt.after)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, next)
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockCategoryRepo := mock_category.NewMockCategory(ctrl)
+	mockTransactionService := mock_transaction.NewMockTransaction(ctrl)
+	service := NewService(mockRecurringRepo, mockBudgetRepo, mockCategoryRepo, mockTransactionService)
+
+	start := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	categories := []*domain.Category{{ID: "cat123"}, {ID: "groceries"}}
+
+	tests := []struct {
+		name        string
+		req         *CreateRecurringRequest
+		mockSetup   func()
+		expectedRes *CreateRecurringResponse
+		expectedErr error
+	}{
+		{
+			name: "Monthly rule starts at start date",
+			req: &CreateRecurringRequest{
+				UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("1200.00"), Type: e_transaction_type.Withdrawal, Note: "Rent",
+				Frequency: e_recurrence_frequency.Monthly, StartAt: start,
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockRecurringRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rule *domain.RecurringTransaction) (string, error) {
+					assert.Equal(t, 1, rule.IntervalCount)
+					assert.Equal(t, start, rule.NextRunAt.Time)
+					assert.False(t, rule.CronExpr.Valid)
+					return "rule1", nil
+				})
+			},
+			expectedRes: &CreateRecurringResponse{ID: "rule1", NextRunAt: &start},
+		},
+		{
+			name: "Cron rule starts at first matching time",
+			req: &CreateRecurringRequest{
+				UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("9.99"), Type: e_transaction_type.Withdrawal,
+				Frequency: e_recurrence_frequency.Cron, CronExpr: "0 12 15 * *", StartAt: start,
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockRecurringRepo.EXPECT().Create(ctx, gomock.Any()).Return("rule2", nil)
+			},
+			expectedRes: &CreateRecurringResponse{ID: "rule2", NextRunAt: func() *time.Time {
+				t := time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC)
+				return &t
+			}()},
+		},
+		{
+			name: "Invalid cron expression",
+			req: &CreateRecurringRequest{
+				UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("9.99"), Type: e_transaction_type.Withdrawal,
+				Frequency: e_recurrence_frequency.Cron, CronExpr: "every day", StartAt: start,
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.InvalidSchedule,
+		},
+		{
+			name: "Foreign budget",
+			req: &CreateRecurringRequest{
+				UserID: "user123", BudgetID: "other", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("9.99"), Type: e_transaction_type.Deposit,
+				Frequency: e_recurrence_frequency.Daily, StartAt: start,
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "other", "user123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.BudgetNotFound,
+		},
+		{
+			name: "Viewer of a shared budget",
+			req: &CreateRecurringRequest{
+				UserID: "user456", BudgetID: "budget123", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("9.99"), Type: e_transaction_type.Deposit,
+				Frequency: e_recurrence_frequency.Daily, StartAt: start,
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "viewer"}, nil)
+			},
+			expectedErr: errs.BudgetForbidden,
+		},
+		{
+			name: "Archived budget",
+			req: &CreateRecurringRequest{
+				UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+				Amount: domain.MustParseMoney("9.99"), Type: e_transaction_type.Deposit,
+				Frequency: e_recurrence_frequency.Daily, StartAt: start,
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner", ArchivedAt: sql.NullTime{Time: start, Valid: true}}, nil)
+			},
+			expectedErr: errs.BudgetArchived,
+		},
+		{
+			name: "Custom category of another user",
+			req: &CreateRecurringRequest{
+				UserID: "user456", BudgetID: "budget123", CategoryID: "foreign",
+				Amount: domain.MustParseMoney("9.99"), Type: e_transaction_type.Deposit,
+				Frequency: e_recurrence_frequency.Daily, StartAt: start,
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "editor"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := service.Create(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRes, resp)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockCategoryRepo := mock_category.NewMockCategory(ctrl)
+	service := NewService(mockRecurringRepo, mockBudgetRepo, mockCategoryRepo, mock_transaction.NewMockTransaction(ctrl))
+
+	start := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	categories := []*domain.Category{{ID: "cat123"}, {ID: "groceries"}}
+	stored := func() *domain.RecurringTransaction {
+		return &domain.RecurringTransaction{
+			ID: "rule1", UserID: "user456", BudgetID: "budget123", CategoryID: "cat123",
+			Amount: domain.MustParseMoney("9.99"), TransactionType: "withdrawal",
+			Frequency: "daily", IntervalCount: 1, StartAt: start,
+			NextRunAt: sql.NullTime{Time: start, Valid: true}, IsActive: true,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		req         *UpdateRecurringRequest
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Category of the budget owner",
+			req:  &UpdateRecurringRequest{ID: "rule1", UserID: "user456", CategoryID: "groceries"},
+			mockSetup: func() {
+				mockRecurringRepo.EXPECT().GetByID(ctx, "rule1", "user456").Return(stored(), nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "editor"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockRecurringRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rule *domain.RecurringTransaction) error {
+					assert.Equal(t, "groceries", rule.CategoryID)
+					return nil
+				})
+			},
+		},
+		{
+			name: "Custom category of another user",
+			req:  &UpdateRecurringRequest{ID: "rule1", UserID: "user456", CategoryID: "foreign"},
+			mockSetup: func() {
+				mockRecurringRepo.EXPECT().GetByID(ctx, "rule1", "user456").Return(stored(), nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "editor"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Unchanged category is not checked",
+			req:  &UpdateRecurringRequest{ID: "rule1", UserID: "user456", CategoryID: "cat123"},
+			mockSetup: func() {
+				mockRecurringRepo.EXPECT().GetByID(ctx, "rule1", "user456").Return(stored(), nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "editor"}, nil)
+				mockRecurringRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "Demoted to viewer",
+			req:  &UpdateRecurringRequest{ID: "rule1", UserID: "user456", Amount: domain.MustParseMoney("19.99")},
+			mockSetup: func() {
+				mockRecurringRepo.EXPECT().GetByID(ctx, "rule1", "user456").Return(stored(), nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "viewer"}, nil)
+			},
+			expectedErr: errs.BudgetForbidden,
+		},
+		{
+			name: "Archived budget",
+			req:  &UpdateRecurringRequest{ID: "rule1", UserID: "user456", Amount: domain.MustParseMoney("19.99")},
+			mockSetup: func() {
+				mockRecurringRepo.EXPECT().GetByID(ctx, "rule1", "user456").Return(stored(), nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user456", Role: "owner", ArchivedAt: sql.NullTime{Time: start, Valid: true}}, nil)
+			},
+			expectedErr: errs.BudgetArchived,
+		},
+		{
+			name: "Budget no longer shared",
+			req:  &UpdateRecurringRequest{ID: "rule1", UserID: "user456", Amount: domain.MustParseMoney("19.99")},
+			mockSetup: func() {
+				mockRecurringRepo.EXPECT().GetByID(ctx, "rule1", "user456").Return(stored(), nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.BudgetNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := service.Update(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &UpdateRecurringResponse{}, resp)
+			}
+		})
+	}
+}
+
+func TestRunDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	dailyRule := func() *domain.RecurringTransaction {
+		return &domain.RecurringTransaction{
+			ID: "rule1", UserID: "user123", BudgetID: "budget123", CategoryID: "cat123",
+			Amount: domain.MustParseMoney("5.00"), TransactionType: "withdrawal", Note: "Coffee",
+			Frequency: "daily", IntervalCount: 1,
+			StartAt:   time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+			NextRunAt: sql.NullTime{Time: time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC), Valid: true},
+			IsActive:  true,
+		}
+	}
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 9, 0, 0, 0, time.UTC) }
+	valid := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	dueAt := func(d int) *domain.RecurringTransaction {
+		rule := dailyRule()
+		rule.NextRunAt = valid(day(d))
+		return rule
+	}
+
+	t.Run("Catches up every missed occurrence", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+		mockTransactionService := mock_transaction.NewMockTransaction(ctrl)
+		service := NewService(mockRecurringRepo, mock_budget.NewMockBudget(ctrl), nil, mockTransactionService)
+
+		rule := dailyRule()
+		mockRecurringRepo.EXPECT().ListDue(ctx, now, dueBatchSize).Return([]*domain.RecurringTransaction{rule}, nil)
+
+		expectedReq := func(at time.Time) *transaction.CreateTransactionRequest {
+			return &transaction.CreateTransactionRequest{
+				UserID: "user123", CategoryID: "cat123", BudgetID: "budget123",
+				Amount: domain.MustParseMoney("5.00"), Type: e_transaction_type.Withdrawal, Note: "Coffee",
+				At: at, ExternalID: "recurring:rule1:" + at.Format(time.RFC3339Nano),
+			}
+		}
+		gomock.InOrder(
+			mockRecurringRepo.EXPECT().ClaimRun(ctx, "rule1", day(3)).Return("run3", true, nil),
+			mockRecurringRepo.EXPECT().GetForRun(ctx, "rule1").Return(dueAt(3), nil),
+			mockTransactionService.EXPECT().Create(ctx, expectedReq(day(3))).Return(&transaction.CreateTransactionResponse{ID: "tx3"}, nil),
+			mockRecurringRepo.EXPECT().CompleteRun(ctx, "run3", "tx3").Return(nil),
+			mockRecurringRepo.EXPECT().Advance(ctx, dueAt(3), day(3), valid(day(4))).Return(nil),
+
+			mockRecurringRepo.EXPECT().ClaimRun(ctx, "rule1", day(4)).Return("run4", true, nil),
+			mockRecurringRepo.EXPECT().GetForRun(ctx, "rule1").Return(dueAt(4), nil),
+			mockTransactionService.EXPECT().Create(ctx, expectedReq(day(4))).Return(&transaction.CreateTransactionResponse{ID: "tx4"}, nil),
+			mockRecurringRepo.EXPECT().CompleteRun(ctx, "run4", "tx4").Return(nil),
+			mockRecurringRepo.EXPECT().Advance(ctx, dueAt(4), day(4), valid(day(5))).Return(nil),
+		)
+
+		assert.NoError(t, service.RunDue(ctx, now))
+	})
+
+	t.Run("Skips occurrences that were already processed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+		mockTransactionService := mock_transaction.NewMockTransaction(ctrl)
+		service := NewService(mockRecurringRepo, mock_budget.NewMockBudget(ctrl), nil, mockTransactionService)
+
+		rule := dailyRule()
+		rule.NextRunAt = valid(day(4))
+		mockRecurringRepo.EXPECT().ListDue(ctx, now, dueBatchSize).Return([]*domain.RecurringTransaction{rule}, nil)
+		mockRecurringRepo.EXPECT().ClaimRun(ctx, "rule1", day(4)).Return("", false, nil)
+		mockRecurringRepo.EXPECT().RunStatus(ctx, "rule1", day(4)).Return(recurring.RunStatusCompleted, nil)
+		mockRecurringRepo.EXPECT().GetForRun(ctx, "rule1").Return(dueAt(4), nil)
+		mockRecurringRepo.EXPECT().Advance(ctx, rule, day(4), valid(day(5))).Return(nil)
+
+		assert.NoError(t, service.RunDue(ctx, now))
+	})
+
+	t.Run("Waits for a run in progress", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+		mockTransactionService := mock_transaction.NewMockTransaction(ctrl)
+		service := NewService(mockRecurringRepo, mock_budget.NewMockBudget(ctrl), nil, mockTransactionService)
+
+		rule := dailyRule()
+		rule.NextRunAt = valid(day(4))
+		mockRecurringRepo.EXPECT().ListDue(ctx, now, dueBatchSize).Return([]*domain.RecurringTransaction{rule}, nil)
+		mockRecurringRepo.EXPECT().ClaimRun(ctx, "rule1", day(4)).Return("", false, nil)
+		mockRecurringRepo.EXPECT().RunStatus(ctx, "rule1", day(4)).Return(recurring.RunStatusPending, nil)
+
+		assert.NoError(t, service.RunDue(ctx, now))
+	})
+
+	t.Run("Completes a run booked by an interrupted attempt", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+		mockTransactionService := mock_transaction.NewMockTransaction(ctrl)
+		service := NewService(mockRecurringRepo, mock_budget.NewMockBudget(ctrl), nil, mockTransactionService)
+
+		rule := dailyRule()
+		rule.NextRunAt = valid(day(4))
+		mockRecurringRepo.EXPECT().ListDue(ctx, now, dueBatchSize).Return([]*domain.RecurringTransaction{rule}, nil)
+		mockRecurringRepo.EXPECT().ClaimRun(ctx, "rule1", day(4)).Return("run4", true, nil)
+		mockRecurringRepo.EXPECT().GetForRun(ctx, "rule1").Return(dueAt(4), nil)
+		mockTransactionService.EXPECT().Create(ctx, gomock.Any()).Return(nil, transaction.ErrAlreadyBooked)
+		mockRecurringRepo.EXPECT().CompleteRun(ctx, "run4", "").Return(nil)
+		mockRecurringRepo.EXPECT().Advance(ctx, rule, day(4), valid(day(5))).Return(nil)
+
+		assert.NoError(t, service.RunDue(ctx, now))
+	})
+
+	t.Run("Deactivates rule after its end date", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+		mockTransactionService := mock_transaction.NewMockTransaction(ctrl)
+		service := NewService(mockRecurringRepo, mock_budget.NewMockBudget(ctrl), nil, mockTransactionService)
+
+		rule := dailyRule()
+		rule.NextRunAt = valid(day(4))
+		rule.EndAt = valid(day(4))
+		mockRecurringRepo.EXPECT().ListDue(ctx, now, dueBatchSize).Return([]*domain.RecurringTransaction{rule}, nil)
+		mockRecurringRepo.EXPECT().ClaimRun(ctx, "rule1", day(4)).Return("run4", true, nil)
+		mockRecurringRepo.EXPECT().GetForRun(ctx, "rule1").Return(rule, nil)
+		mockTransactionService.EXPECT().Create(ctx, gomock.Any()).Return(&transaction.CreateTransactionResponse{ID: "tx4"}, nil)
+		mockRecurringRepo.EXPECT().CompleteRun(ctx, "run4", "tx4").Return(nil)
+		mockRecurringRepo.EXPECT().Advance(ctx, rule, day(4), sql.NullTime{}).Return(nil)
+
+		assert.NoError(t, service.RunDue(ctx, now))
+	})
+
+	t.Run("Records client errors and moves on", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+		mockTransactionService := mock_transaction.NewMockTransaction(ctrl)
+		service := NewService(mockRecurringRepo, mock_budget.NewMockBudget(ctrl), nil, mockTransactionService)
+
+		rule := dailyRule()
+		rule.NextRunAt = valid(day(4))
+		mockRecurringRepo.EXPECT().ListDue(ctx, now, dueBatchSize).Return([]*domain.RecurringTransaction{rule}, nil)
+		mockRecurringRepo.EXPECT().ClaimRun(ctx, "rule1", day(4)).Return("run4", true, nil)
+		mockRecurringRepo.EXPECT().GetForRun(ctx, "rule1").Return(dueAt(4), nil)
+		mockTransactionService.EXPECT().Create(ctx, gomock.Any()).Return(nil, echo.NewHTTPError(http.StatusBadRequest, "Insufficient balance"))
+		mockRecurringRepo.EXPECT().FailRun(ctx, "run4", "Insufficient balance").Return(nil)
+		mockRecurringRepo.EXPECT().Advance(ctx, rule, day(4), valid(day(5))).Return(nil)
+
+		assert.NoError(t, service.RunDue(ctx, now))
+	})
+
+	t.Run("Releases claim on transient errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+		mockTransactionService := mock_transaction.NewMockTransaction(ctrl)
+		service := NewService(mockRecurringRepo, mock_budget.NewMockBudget(ctrl), nil, mockTransactionService)
+
+		rule := dailyRule()
+		mockRecurringRepo.EXPECT().ListDue(ctx, now, dueBatchSize).Return([]*domain.RecurringTransaction{rule}, nil)
+		mockRecurringRepo.EXPECT().ClaimRun(ctx, "rule1", day(3)).Return("run3", true, nil)
+		mockRecurringRepo.EXPECT().GetForRun(ctx, "rule1").Return(dueAt(3), nil)
+		mockTransactionService.EXPECT().Create(ctx, gomock.Any()).Return(nil, errors.New("connection reset"))
+		mockRecurringRepo.EXPECT().ReleaseRun(ctx, "run3").Return(nil)
+
+		assert.NoError(t, service.RunDue(ctx, now))
+	})
+
+	t.Run("Books an occurrence with the rule as edited after listing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+		mockTransactionService := mock_transaction.NewMockTransaction(ctrl)
+		service := NewService(mockRecurringRepo, mock_budget.NewMockBudget(ctrl), nil, mockTransactionService)
+
+		edited := dueAt(4)
+		edited.Amount = domain.MustParseMoney("7.50")
+		mockRecurringRepo.EXPECT().ListDue(ctx, now, dueBatchSize).Return([]*domain.RecurringTransaction{dueAt(4)}, nil)
+		mockRecurringRepo.EXPECT().ClaimRun(ctx, "rule1", day(4)).Return("run4", true, nil)
+		mockRecurringRepo.EXPECT().GetForRun(ctx, "rule1").Return(edited, nil)
+		mockTransactionService.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req *transaction.CreateTransactionRequest) (*transaction.CreateTransactionResponse, error) {
+			assert.Equal(t, domain.MustParseMoney("7.50"), req.Amount)
+			return &transaction.CreateTransactionResponse{ID: "tx4"}, nil
+		})
+		mockRecurringRepo.EXPECT().CompleteRun(ctx, "run4", "tx4").Return(nil)
+		mockRecurringRepo.EXPECT().Advance(ctx, edited, day(4), valid(day(5))).Return(nil)
+
+		assert.NoError(t, service.RunDue(ctx, now))
+	})
+
+	t.Run("Releases claim of a rule ended after listing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+		mockTransactionService := mock_transaction.NewMockTransaction(ctrl)
+		service := NewService(mockRecurringRepo, mock_budget.NewMockBudget(ctrl), nil, mockTransactionService)
+
+		ended := dueAt(4)
+		ended.EndAt = valid(day(3))
+		mockRecurringRepo.EXPECT().ListDue(ctx, now, dueBatchSize).Return([]*domain.RecurringTransaction{dueAt(4)}, nil)
+		mockRecurringRepo.EXPECT().ClaimRun(ctx, "rule1", day(4)).Return("run4", true, nil)
+		mockRecurringRepo.EXPECT().GetForRun(ctx, "rule1").Return(ended, nil)
+		mockRecurringRepo.EXPECT().ReleaseRun(ctx, "run4").Return(nil)
+
+		assert.NoError(t, service.RunDue(ctx, now))
+	})
+
+	t.Run("Releases claim of a rule deleted after listing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+		mockTransactionService := mock_transaction.NewMockTransaction(ctrl)
+		service := NewService(mockRecurringRepo, mock_budget.NewMockBudget(ctrl), nil, mockTransactionService)
+
+		mockRecurringRepo.EXPECT().ListDue(ctx, now, dueBatchSize).Return([]*domain.RecurringTransaction{dueAt(4)}, nil)
+		mockRecurringRepo.EXPECT().ClaimRun(ctx, "rule1", day(4)).Return("run4", true, nil)
+		mockRecurringRepo.EXPECT().GetForRun(ctx, "rule1").Return(nil, sql.ErrNoRows)
+		mockRecurringRepo.EXPECT().ReleaseRun(ctx, "run4").Return(nil)
+
+		assert.NoError(t, service.RunDue(ctx, now))
+	})
+
+	t.Run("Stops when the rule is rescheduled before it is advanced", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+		mockTransactionService := mock_transaction.NewMockTransaction(ctrl)
+		service := NewService(mockRecurringRepo, mock_budget.NewMockBudget(ctrl), nil, mockTransactionService)
+
+		mockRecurringRepo.EXPECT().ListDue(ctx, now, dueBatchSize).Return([]*domain.RecurringTransaction{dailyRule()}, nil)
+		mockRecurringRepo.EXPECT().ClaimRun(ctx, "rule1", day(3)).Return("run3", true, nil)
+		mockRecurringRepo.EXPECT().GetForRun(ctx, "rule1").Return(dueAt(3), nil)
+		mockTransactionService.EXPECT().Create(ctx, gomock.Any()).Return(&transaction.CreateTransactionResponse{ID: "tx3"}, nil)
+		mockRecurringRepo.EXPECT().CompleteRun(ctx, "run3", "tx3").Return(nil)
+		mockRecurringRepo.EXPECT().Advance(ctx, dueAt(3), day(3), valid(day(4))).Return(sql.ErrNoRows)
+
+		assert.NoError(t, service.RunDue(ctx, now))
+	})
+
+	t.Run("List error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRecurringRepo := mock_recurring.NewMockRecurring(ctrl)
+		service := NewService(mockRecurringRepo, mock_budget.NewMockBudget(ctrl), nil, mock_transaction.NewMockTransaction(ctrl))
+
+		mockRecurringRepo.EXPECT().ListDue(ctx, now, dueBatchSize).Return(nil, errors.New("db error"))
+
+		assert.Error(t, service.RunDue(ctx, now))
+	})
+}
@@ -0,0 +1,98 @@
+package recurring
+
+import (
+	"database/sql"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_recurrence_frequency"
+	"finly-backend/pkg/cron"
+	"time"
+)
+
+// firstOccurrence returns the first run of a rule at or after its start time.
+func firstOccurrence(rule *domain.RecurringTransaction) (sql.NullTime, error) {
+	if rule.Frequency == e_recurrence_frequency.Cron.String() {
+		return nextCron(rule, rule.StartAt.Add(-time.Nanosecond))
+	}
+	return withinEnd(rule, rule.StartAt), nil
+}
+
+// nextOccurrence returns the run that follows the given occurrence.
+// The result is invalid once the schedule passes the rule's end date.
+func nextOccurrence(rule *domain.RecurringTransaction, after time.Time) (sql.NullTime, error) {
+	interval := rule.IntervalCount
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch e_recurrence_frequency.Enum(rule.Frequency) {
+	case e_recurrence_frequency.Daily:
+		return withinEnd(rule, after.AddDate(0, 0, interval)), nil
+	case e_recurrence_frequency.Weekly:
+		return withinEnd(rule, after.AddDate(0, 0, 7*interval)), nil
+	case e_recurrence_frequency.Monthly:
+		return withinEnd(rule, addMonths(after, interval, rule.StartAt.Day())), nil
+	case e_recurrence_frequency.Cron:
+		return nextCron(rule, after)
+	default:
+		return sql.NullTime{}, errs.InvalidSchedule
+	}
+}
+
+func nextCron(rule *domain.RecurringTransaction, after time.Time) (sql.NullTime, error) {
+	schedule, err := cron.Parse(rule.CronExpr.String)
+	if err != nil {
+		return sql.NullTime{}, errs.InvalidSchedule
+	}
+
+	next := schedule.Next(after)
+	if next.IsZero() {
+		return sql.NullTime{}, nil
+	}
+	return withinEnd(rule, next), nil
+}
+
+// skipPast moves next forward to the first occurrence at or after now.
+func skipPast(rule *domain.RecurringTransaction, next sql.NullTime, now time.Time) (sql.NullTime, error) {
+	if rule.Frequency == e_recurrence_frequency.Cron.String() {
+		if !next.Valid || !next.Time.Before(now) {
+			return next, nil
+		}
+		return nextCron(rule, now.Add(-time.Nanosecond))
+	}
+
+	var err error
+	for next.Valid && next.Time.Before(now) {
+		if next, err = nextOccurrence(rule, next.Time); err != nil {
+			return sql.NullTime{}, err
+		}
+	}
+	return next, nil
+}
+
+func withinEnd(rule *domain.RecurringTransaction, t time.Time) sql.NullTime {
+	if rule.EndAt.Valid && t.After(rule.EndAt.Time) {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+// addMonths moves t forward by n months, pinning the day to anchorDay and clamping it to the
+// length of the target month, so a rule started on the 31st runs on Feb 28 and again on Mar 31.
+func addMonths(t time.Time, n, anchorDay int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month()+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+
+	day := anchorDay
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
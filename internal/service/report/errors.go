@@ -0,0 +1,20 @@
+package report
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+var errs = struct {
+	BudgetNotFound  *echo.HTTPError
+	InvalidRange    *echo.HTTPError
+	TooManyPeriods  *echo.HTTPError
+	TotalOutOfRange *echo.HTTPError
+	DatabaseError   *echo.HTTPError
+}{
+	BudgetNotFound:  echo.NewHTTPError(http.StatusNotFound, "Budget not found"),
+	InvalidRange:    echo.NewHTTPError(http.StatusBadRequest, "Invalid date range"),
+	TooManyPeriods:  echo.NewHTTPError(http.StatusBadRequest, "Date range has too many periods for this granularity"),
+	TotalOutOfRange: echo.NewHTTPError(http.StatusUnprocessableEntity, "Report totals are out of range"),
+	DatabaseError:   echo.NewHTTPError(http.StatusInternalServerError, "Database operation failed"),
+}
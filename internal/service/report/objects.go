@@ -0,0 +1,100 @@
+package report
+
+import (
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_report_granularity"
+	"time"
+)
+
+// ReportRequest selects a budget and a date range [from, to). Without from and to the
+// report covers the current calendar month.
+type ReportRequest struct {
+	UserID   string `header:"User-Id" validate:"required"`
+	BudgetID string `query:"budget_id" validate:"required,uuid"`
+	From     string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To       string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// PeriodObject is a date range [from, to).
+type PeriodObject struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Comparison holds an amount of the requested period next to the same amount of the
+// previous one. ChangePercent is omitted when the previous amount is zero.
+type Comparison struct {
+	Current       domain.Money `json:"current" swaggertype:"number"`
+	Previous      domain.Money `json:"previous" swaggertype:"number"`
+	Change        domain.Money `json:"change" swaggertype:"number"`
+	ChangePercent *float64     `json:"change_percent,omitempty"`
+}
+
+type CountComparison struct {
+	Current  int `json:"current"`
+	Previous int `json:"previous"`
+}
+
+type SummaryRequest struct {
+	ReportRequest
+}
+
+// SummaryResponse compares income, expense and net cash flow with the previous period.
+// Transfers between the user's budgets count towards the net cash flow only.
+type SummaryResponse struct {
+	Period         PeriodObject    `json:"period"`
+	PreviousPeriod PeriodObject    `json:"previous_period"`
+	Income         Comparison      `json:"income"`
+	Expense        Comparison      `json:"expense"`
+	TransfersIn    Comparison      `json:"transfers_in"`
+	TransfersOut   Comparison      `json:"transfers_out"`
+	Net            Comparison      `json:"net"`
+	Count          CountComparison `json:"count"`
+}
+
+type CategoriesRequest struct {
+	ReportRequest
+}
+
+// CategoryReportObject is the spending and income of one category. Share is the category's
+// percentage of the period's total expense.
+type CategoryReportObject struct {
+	CategoryID   string          `json:"category_id"`
+	CategoryName string          `json:"category_name"`
+	Income       Comparison      `json:"income"`
+	Expense      Comparison      `json:"expense"`
+	Count        CountComparison `json:"count"`
+	Share        float64         `json:"share"`
+}
+
+type CategoriesResponse struct {
+	Period         PeriodObject            `json:"period"`
+	PreviousPeriod PeriodObject            `json:"previous_period"`
+	Categories     []*CategoryReportObject `json:"categories"`
+}
+
+type TimelineRequest struct {
+	ReportRequest
+	Granularity e_report_granularity.Enum `query:"granularity" validate:"omitempty,oneof=day week month"`
+}
+
+// BucketObject is the cash flow of one day, week or month. It is compared with the
+// bucket at the same position in the previous period, which starts at PreviousStart.
+// The previous period can be shorter, e.g. February next to March; the buckets it
+// lacks compare against zero and have no PreviousStart.
+type BucketObject struct {
+	Start         time.Time       `json:"start"`
+	End           time.Time       `json:"end"`
+	PreviousStart *time.Time      `json:"previous_start,omitempty"`
+	Income        Comparison      `json:"income"`
+	Expense       Comparison      `json:"expense"`
+	Net           Comparison      `json:"net"`
+	Count         CountComparison `json:"count"`
+}
+
+type TimelineResponse struct {
+	Granularity    e_report_granularity.Enum `json:"granularity"`
+	Period         PeriodObject              `json:"period"`
+	PreviousPeriod PeriodObject              `json:"previous_period"`
+	Buckets        []*BucketObject           `json:"buckets"`
+}
@@ -0,0 +1,243 @@
+package report
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_report_granularity"
+	"finly-backend/internal/repository/budget"
+	"finly-backend/internal/repository/report"
+	"go.uber.org/zap"
+	"time"
+)
+
+type Report interface {
+	Summary(ctx context.Context, req *SummaryRequest) (*SummaryResponse, error)
+	Categories(ctx context.Context, req *CategoriesRequest) (*CategoriesResponse, error)
+	Timeline(ctx context.Context, req *TimelineRequest) (*TimelineResponse, error)
+}
+
+type Service struct {
+	reportRepo report.Report
+	budgetRepo budget.Budget
+}
+
+func NewService(reportRepo report.Report, budgetRepo budget.Budget) *Service {
+	return &Service{
+		reportRepo: reportRepo,
+		budgetRepo: budgetRepo,
+	}
+}
+
+func (s *Service) Summary(ctx context.Context, req *SummaryRequest) (*SummaryResponse, error) {
+	current, previous, err := s.filters(ctx, &req.ReportRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.reportRepo.Totals(ctx, current)
+	if err != nil {
+		zap.L().Sugar().Errorf("Summary: failed for userID=%s, budgetID=%s: %v", req.UserID, req.BudgetID, err)
+		return nil, errs.DatabaseError
+	}
+	previousTotals, err := s.reportRepo.Totals(ctx, previous)
+	if err != nil {
+		zap.L().Sugar().Errorf("Summary: failed to get previous period for userID=%s, budgetID=%s: %v", req.UserID, req.BudgetID, err)
+		return nil, errs.DatabaseError
+	}
+
+	var c comparer
+	res := &SummaryResponse{
+		Period:         PeriodObject{From: current.From, To: current.To},
+		PreviousPeriod: PeriodObject{From: previous.From, To: previous.To},
+		Income:         c.compare(totals.Income, previousTotals.Income),
+		Expense:        c.compare(totals.Expense, previousTotals.Expense),
+		TransfersIn:    c.compare(totals.TransfersIn, previousTotals.TransfersIn),
+		TransfersOut:   c.compare(totals.TransfersOut, previousTotals.TransfersOut),
+		Net:            c.compare(c.net(*totals), c.net(*previousTotals)),
+		Count:          CountComparison{Current: totals.Count, Previous: previousTotals.Count},
+	}
+	if c.err != nil {
+		zap.L().Sugar().Errorf("Summary: totals out of range for userID=%s, budgetID=%s: %v", req.UserID, req.BudgetID, c.err)
+		return nil, errs.TotalOutOfRange
+	}
+
+	return res, nil
+}
+
+// Categories returns the totals of every category used in the period or the one before,
+// in the order of the current period's expense.
+func (s *Service) Categories(ctx context.Context, req *CategoriesRequest) (*CategoriesResponse, error) {
+	current, previous, err := s.filters(ctx, &req.ReportRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.reportRepo.ByCategory(ctx, current)
+	if err != nil {
+		zap.L().Sugar().Errorf("Categories: failed for userID=%s, budgetID=%s: %v", req.UserID, req.BudgetID, err)
+		return nil, errs.DatabaseError
+	}
+	previousTotals, err := s.reportRepo.ByCategory(ctx, previous)
+	if err != nil {
+		zap.L().Sugar().Errorf("Categories: failed to get previous period for userID=%s, budgetID=%s: %v", req.UserID, req.BudgetID, err)
+		return nil, errs.DatabaseError
+	}
+
+	var (
+		c            comparer
+		totalExpense domain.Money
+	)
+	for _, total := range totals {
+		totalExpense = c.add(totalExpense, total.Expense)
+	}
+
+	previousByID := make(map[string]*domain.CategoryTotal, len(previousTotals))
+	for _, total := range previousTotals {
+		previousByID[total.CategoryID] = total
+	}
+
+	categories := make([]*CategoryReportObject, 0, len(totals)+len(previousTotals))
+	for _, total := range totals {
+		before := previousByID[total.CategoryID]
+		if before == nil {
+			before = &domain.CategoryTotal{}
+		}
+		delete(previousByID, total.CategoryID)
+		categories = append(categories, convertCategory(&c, total, before, share(total.Expense, totalExpense)))
+	}
+	for _, before := range previousTotals {
+		if _, ok := previousByID[before.CategoryID]; ok {
+			categories = append(categories, convertCategory(&c, &domain.CategoryTotal{
+				CategoryID:   before.CategoryID,
+				CategoryName: before.CategoryName,
+			}, before, 0))
+		}
+	}
+
+	if c.err != nil {
+		zap.L().Sugar().Errorf("Categories: totals out of range for userID=%s, budgetID=%s: %v", req.UserID, req.BudgetID, c.err)
+		return nil, errs.TotalOutOfRange
+	}
+
+	return &CategoriesResponse{
+		Period:         PeriodObject{From: current.From, To: current.To},
+		PreviousPeriod: PeriodObject{From: previous.From, To: previous.To},
+		Categories:     categories,
+	}, nil
+}
+
+// Timeline returns the cash flow of every day, week or month of the period, including
+// the ones without transactions. The first and last bucket can reach outside the period
+// but only sum the transactions inside it.
+func (s *Service) Timeline(ctx context.Context, req *TimelineRequest) (*TimelineResponse, error) {
+	current, previous, err := s.filters(ctx, &req.ReportRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	granularity := req.Granularity
+	if granularity == "" {
+		granularity = e_report_granularity.Day
+	}
+
+	starts, err := buckets(current.From, current.To, granularity)
+	if err != nil {
+		return nil, err
+	}
+	previousStarts, err := buckets(previous.From, previous.To, granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.reportRepo.ByPeriod(ctx, current, granularity)
+	if err != nil {
+		zap.L().Sugar().Errorf("Timeline: failed for userID=%s, budgetID=%s: %v", req.UserID, req.BudgetID, err)
+		return nil, errs.DatabaseError
+	}
+	previousTotals, err := s.reportRepo.ByPeriod(ctx, previous, granularity)
+	if err != nil {
+		zap.L().Sugar().Errorf("Timeline: failed to get previous period for userID=%s, budgetID=%s: %v", req.UserID, req.BudgetID, err)
+		return nil, errs.DatabaseError
+	}
+
+	byStart := indexPeriods(totals)
+	previousByStart := indexPeriods(previousTotals)
+
+	var c comparer
+	list := make([]*BucketObject, 0, len(starts))
+	for i, start := range starts {
+		flow := byStart[start.Unix()]
+		var before domain.CashFlow
+		var previousStart *time.Time
+		if i < len(previousStarts) {
+			before = previousByStart[previousStarts[i].Unix()]
+			previousStart = &previousStarts[i]
+		}
+
+		list = append(list, &BucketObject{
+			Start:         start,
+			End:           next(start, granularity),
+			PreviousStart: previousStart,
+			Income:        c.compare(flow.Income, before.Income),
+			Expense:       c.compare(flow.Expense, before.Expense),
+			Net:           c.compare(c.net(flow), c.net(before)),
+			Count:         CountComparison{Current: flow.Count, Previous: before.Count},
+		})
+	}
+
+	if c.err != nil {
+		zap.L().Sugar().Errorf("Timeline: totals out of range for userID=%s, budgetID=%s: %v", req.UserID, req.BudgetID, c.err)
+		return nil, errs.TotalOutOfRange
+	}
+
+	return &TimelineResponse{
+		Granularity:    granularity,
+		Period:         PeriodObject{From: current.From, To: current.To},
+		PreviousPeriod: PeriodObject{From: previous.From, To: previous.To},
+		Buckets:        list,
+	}, nil
+}
+
+// filters checks that the user is a member of the budget and returns the filters of the
+// requested period and of the one before it. Reports of a shared budget cover every member.
+func (s *Service) filters(ctx context.Context, req *ReportRequest) (report.Filter, report.Filter, error) {
+	from, to, err := reportRange(req, time.Now())
+	if err != nil {
+		return report.Filter{}, report.Filter{}, err
+	}
+
+	budget, err := s.budgetRepo.GetByID(ctx, req.BudgetID, req.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return report.Filter{}, report.Filter{}, errs.BudgetNotFound
+		}
+		zap.L().Sugar().Errorf("failed to get budgetID=%s for userID=%s: %v", req.BudgetID, req.UserID, err)
+		return report.Filter{}, report.Filter{}, errs.DatabaseError
+	}
+
+	previousFrom, previousTo := previousRange(from, to)
+	return report.Filter{UserID: budget.UserID, BudgetID: req.BudgetID, From: from, To: to},
+		report.Filter{UserID: budget.UserID, BudgetID: req.BudgetID, From: previousFrom, To: previousTo},
+		nil
+}
+
+func indexPeriods(totals []*domain.PeriodTotal) map[int64]domain.CashFlow {
+	byStart := make(map[int64]domain.CashFlow, len(totals))
+	for _, total := range totals {
+		byStart[total.Period.Unix()] = total.CashFlow
+	}
+	return byStart
+}
+
+func convertCategory(c *comparer, total, previous *domain.CategoryTotal, expenseShare float64) *CategoryReportObject {
+	return &CategoryReportObject{
+		CategoryID:   total.CategoryID,
+		CategoryName: total.CategoryName,
+		Income:       c.compare(total.Income, previous.Income),
+		Expense:      c.compare(total.Expense, previous.Expense),
+		Count:        CountComparison{Current: total.Count, Previous: previous.Count},
+		Share:        expenseShare,
+	}
+}
@@ -0,0 +1,277 @@
+package report
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_report_granularity"
+	mock_budget "finly-backend/internal/repository/budget/mock"
+	"finly-backend/internal/repository/report"
+	mock_report "finly-backend/internal/repository/report/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+var (
+	march    = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	april    = time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	february = time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	marchFilter    = report.Filter{UserID: "user123", BudgetID: "budget123", From: march, To: april}
+	februaryFilter = report.Filter{UserID: "user123", BudgetID: "budget123", From: february, To: march}
+
+	marchRequest = ReportRequest{UserID: "user123", BudgetID: "budget123", From: "2025-03-01T00:00:00Z", To: "2025-04-01T00:00:00Z"}
+)
+
+func percent(v float64) *float64 {
+	return &v
+}
+
+func TestSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockReportRepo := mock_report.NewMockReport(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	budget := &domain.Budget{ID: "budget123", UserID: "user123"}
+
+	tests := []struct {
+		name        string
+		req         *SummaryRequest
+		setupMocks  func()
+		expectedRes *SummaryResponse
+		expectedErr error
+	}{
+		{
+			name: "Compared with previous month",
+			req:  &SummaryRequest{ReportRequest: marchRequest},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budget, nil)
+				mockReportRepo.EXPECT().Totals(ctx, marchFilter).Return(&domain.CashFlow{
+					Income:      domain.MustParseMoney("2000.00"),
+					Expense:     domain.MustParseMoney("600.00"),
+					TransfersIn: domain.MustParseMoney("100.00"),
+					Count:       9,
+				}, nil)
+				mockReportRepo.EXPECT().Totals(ctx, februaryFilter).Return(&domain.CashFlow{
+					Income:       domain.MustParseMoney("2000.00"),
+					Expense:      domain.MustParseMoney("800.00"),
+					TransfersOut: domain.MustParseMoney("200.00"),
+					Count:        12,
+				}, nil)
+			},
+			expectedRes: &SummaryResponse{
+				Period:         PeriodObject{From: march, To: april},
+				PreviousPeriod: PeriodObject{From: february, To: march},
+				Income: Comparison{
+					Current: domain.MustParseMoney("2000.00"), Previous: domain.MustParseMoney("2000.00"),
+					ChangePercent: percent(0),
+				},
+				Expense: Comparison{
+					Current: domain.MustParseMoney("600.00"), Previous: domain.MustParseMoney("800.00"),
+					Change: domain.MustParseMoney("-200.00"), ChangePercent: percent(-25),
+				},
+				TransfersIn: Comparison{
+					Current: domain.MustParseMoney("100.00"), Change: domain.MustParseMoney("100.00"),
+				},
+				TransfersOut: Comparison{
+					Previous: domain.MustParseMoney("200.00"), Change: domain.MustParseMoney("-200.00"),
+					ChangePercent: percent(-100),
+				},
+				Net: Comparison{
+					Current: domain.MustParseMoney("1500.00"), Previous: domain.MustParseMoney("1000.00"),
+					Change: domain.MustParseMoney("500.00"), ChangePercent: percent(50),
+				},
+				Count: CountComparison{Current: 9, Previous: 12},
+			},
+		},
+		{
+			name: "Invalid range",
+			req: &SummaryRequest{ReportRequest: ReportRequest{
+				UserID: "user123", BudgetID: "budget123", From: "2025-04-01T00:00:00Z", To: "2025-03-01T00:00:00Z",
+			}},
+			setupMocks:  func() {},
+			expectedErr: errs.InvalidRange,
+		},
+		{
+			name: "Budget not found",
+			req:  &SummaryRequest{ReportRequest: marchRequest},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.BudgetNotFound,
+		},
+		{
+			name: "Database error",
+			req:  &SummaryRequest{ReportRequest: marchRequest},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budget, nil)
+				mockReportRepo.EXPECT().Totals(ctx, marchFilter).Return(nil, errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+			service := NewService(mockReportRepo, mockBudgetRepo)
+			res, err := service.Summary(ctx, tt.req)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedRes, res)
+		})
+	}
+}
+
+func TestCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockReportRepo := mock_report.NewMockReport(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	budget := &domain.Budget{ID: "budget123", UserID: "user123"}
+
+	mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budget, nil)
+	mockReportRepo.EXPECT().ByCategory(ctx, marchFilter).Return([]*domain.CategoryTotal{
+		{CategoryID: "groceries", CategoryName: "Groceries", Expense: domain.MustParseMoney("300.00"), Count: 6},
+		{CategoryID: "rent", CategoryName: "Rent", Expense: domain.MustParseMoney("100.00"), Count: 1},
+		{CategoryID: "salary", CategoryName: "Salary", Income: domain.MustParseMoney("2000.00"), Count: 1},
+	}, nil)
+	mockReportRepo.EXPECT().ByCategory(ctx, februaryFilter).Return([]*domain.CategoryTotal{
+		{CategoryID: "travel", CategoryName: "Travel", Expense: domain.MustParseMoney("50.00"), Count: 1},
+		{CategoryID: "groceries", CategoryName: "Groceries", Expense: domain.MustParseMoney("200.00"), Count: 4},
+	}, nil)
+
+	service := NewService(mockReportRepo, mockBudgetRepo)
+	res, err := service.Categories(ctx, &CategoriesRequest{ReportRequest: marchRequest})
+	assert.NoError(t, err)
+	assert.Len(t, res.Categories, 4)
+
+	groceries := res.Categories[0]
+	assert.Equal(t, "groceries", groceries.CategoryID)
+	assert.Equal(t, domain.MustParseMoney("100.00"), groceries.Expense.Change)
+	assert.Equal(t, percent(50), groceries.Expense.ChangePercent)
+	assert.Equal(t, CountComparison{Current: 6, Previous: 4}, groceries.Count)
+	assert.Equal(t, 75.0, groceries.Share)
+
+	assert.Equal(t, "rent", res.Categories[1].CategoryID)
+	assert.Equal(t, 25.0, res.Categories[1].Share)
+	assert.Nil(t, res.Categories[1].Expense.ChangePercent)
+
+	assert.Equal(t, "salary", res.Categories[2].CategoryID)
+	assert.Equal(t, 0.0, res.Categories[2].Share)
+
+	travel := res.Categories[3]
+	assert.Equal(t, "travel", travel.CategoryID)
+	assert.Equal(t, "Travel", travel.CategoryName)
+	assert.True(t, travel.Expense.Current.IsZero())
+	assert.Equal(t, domain.MustParseMoney("-50.00"), travel.Expense.Change)
+}
+
+func TestTimeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockReportRepo := mock_report.NewMockReport(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	budget := &domain.Budget{ID: "budget123", UserID: "user123"}
+
+	t.Run("Weeks filled and compared by position", func(t *testing.T) {
+		// 2025-03-03 is a Monday; two weeks compared with the two weeks before.
+		from := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 0, 14)
+		previousFrom := from.AddDate(0, 0, -14)
+		filter := report.Filter{UserID: "user123", BudgetID: "budget123", From: from, To: to}
+		previousFilter := report.Filter{UserID: "user123", BudgetID: "budget123", From: previousFrom, To: from}
+
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budget, nil)
+		mockReportRepo.EXPECT().ByPeriod(ctx, filter, e_report_granularity.Week).Return([]*domain.PeriodTotal{
+			{Period: from.AddDate(0, 0, 7), CashFlow: domain.CashFlow{Expense: domain.MustParseMoney("40.00"), Count: 2}},
+		}, nil)
+		mockReportRepo.EXPECT().ByPeriod(ctx, previousFilter, e_report_granularity.Week).Return([]*domain.PeriodTotal{
+			{Period: previousFrom, CashFlow: domain.CashFlow{Income: domain.MustParseMoney("10.00"), Count: 1}},
+		}, nil)
+
+		service := NewService(mockReportRepo, mockBudgetRepo)
+		res, err := service.Timeline(ctx, &TimelineRequest{
+			ReportRequest: ReportRequest{
+				UserID: "user123", BudgetID: "budget123", From: from.Format(time.RFC3339), To: to.Format(time.RFC3339),
+			},
+			Granularity: e_report_granularity.Week,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, PeriodObject{From: previousFrom, To: from}, res.PreviousPeriod)
+		assert.Len(t, res.Buckets, 2)
+
+		first := res.Buckets[0]
+		assert.Equal(t, from, first.Start)
+		assert.Equal(t, from.AddDate(0, 0, 7), first.End)
+		assert.Equal(t, previousFrom, *first.PreviousStart)
+		assert.True(t, first.Income.Current.IsZero())
+		assert.Equal(t, domain.MustParseMoney("10.00"), first.Income.Previous)
+		assert.Equal(t, CountComparison{Current: 0, Previous: 1}, first.Count)
+
+		second := res.Buckets[1]
+		assert.Equal(t, domain.MustParseMoney("-40.00"), second.Net.Current)
+		assert.True(t, second.Net.Previous.IsZero())
+	})
+
+	t.Run("Shorter previous month", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budget, nil)
+		mockReportRepo.EXPECT().ByPeriod(ctx, marchFilter, e_report_granularity.Day).Return(nil, nil)
+		mockReportRepo.EXPECT().ByPeriod(ctx, februaryFilter, e_report_granularity.Day).Return(nil, nil)
+
+		service := NewService(mockReportRepo, mockBudgetRepo)
+		res, err := service.Timeline(ctx, &TimelineRequest{ReportRequest: marchRequest})
+		assert.NoError(t, err)
+		assert.Equal(t, e_report_granularity.Day, res.Granularity)
+		assert.Len(t, res.Buckets, 31)
+		assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), *res.Buckets[27].PreviousStart)
+		assert.Nil(t, res.Buckets[28].PreviousStart)
+	})
+
+	t.Run("Too many periods", func(t *testing.T) {
+		mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(budget, nil)
+
+		service := NewService(mockReportRepo, mockBudgetRepo)
+		res, err := service.Timeline(ctx, &TimelineRequest{ReportRequest: ReportRequest{
+			UserID: "user123", BudgetID: "budget123", From: "2023-01-01T00:00:00Z", To: "2025-01-01T00:00:00Z",
+		}})
+		assert.Equal(t, errs.TooManyPeriods, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestReportRange(t *testing.T) {
+	now := time.Date(2025, 3, 17, 12, 30, 0, 0, time.UTC)
+
+	from, to, err := reportRange(&ReportRequest{}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, march, from)
+	assert.Equal(t, april, to)
+
+	from, to, err = reportRange(&ReportRequest{From: "2025-01-15T00:00:00+02:00"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 14, 22, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 2, 14, 22, 0, 0, 0, time.UTC), to)
+
+	_, _, err = reportRange(&ReportRequest{From: "2025-03-01T00:00:00Z", To: "2025-03-01T00:00:00Z"}, now)
+	assert.Equal(t, errs.InvalidRange, err)
+}
+
+func TestPreviousRange(t *testing.T) {
+	from, to := previousRange(march, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, march, to)
+
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	from, to = previousRange(start, start.AddDate(0, 0, 10))
+	assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, start, to)
+}
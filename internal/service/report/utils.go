@@ -0,0 +1,136 @@
+package report
+
+import (
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_report_granularity"
+	"math"
+	"time"
+)
+
+// maxBuckets caps the length of a timeline, e.g. a year of days.
+const maxBuckets = 366
+
+// reportRange resolves the requested [from, to) range. Missing bounds fall back to the
+// current calendar month; a missing end after an explicit start falls back to a month later.
+func reportRange(req *ReportRequest, now time.Time) (time.Time, time.Time, error) {
+	from := monthStart(now)
+	to := from.AddDate(0, 1, 0)
+
+	if req.From != "" {
+		parsed, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, errs.InvalidRange
+		}
+		from = parsed.UTC()
+		to = from.AddDate(0, 1, 0)
+	}
+	if req.To != "" {
+		parsed, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, errs.InvalidRange
+		}
+		to = parsed.UTC()
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errs.InvalidRange
+	}
+	return from, to, nil
+}
+
+// previousRange returns the period right before [from, to). A range of whole calendar
+// months is compared with the same number of months before it, anything else with a
+// range of the same duration.
+func previousRange(from, to time.Time) (time.Time, time.Time) {
+	if from.Equal(monthStart(from)) && to.Equal(monthStart(to)) {
+		months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+		return from.AddDate(0, -months, 0), from
+	}
+	return from.Add(-to.Sub(from)), from
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// truncate returns the start of the day, week or month that contains t, matching
+// date_trunc in Postgres. Weeks start on Monday.
+func truncate(t time.Time, granularity e_report_granularity.Enum) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch granularity {
+	case e_report_granularity.Week:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case e_report_granularity.Month:
+		return monthStart(t)
+	default:
+		return day
+	}
+}
+
+func next(t time.Time, granularity e_report_granularity.Enum) time.Time {
+	switch granularity {
+	case e_report_granularity.Week:
+		return t.AddDate(0, 0, 7)
+	case e_report_granularity.Month:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// buckets returns the starts of the periods that overlap [from, to).
+func buckets(from, to time.Time, granularity e_report_granularity.Enum) ([]time.Time, error) {
+	var starts []time.Time
+	for start := truncate(from, granularity); start.Before(to); start = next(start, granularity) {
+		if len(starts) == maxBuckets {
+			return nil, errs.TooManyPeriods
+		}
+		starts = append(starts, start)
+	}
+	return starts, nil
+}
+
+// comparer compares the totals of a period with the ones of the period before. It keeps
+// the first total that is out of range in err, so a report is assembled in one go and
+// checked once.
+type comparer struct {
+	err error
+}
+
+func (c *comparer) compare(current, previous domain.Money) Comparison {
+	comparison := Comparison{
+		Current:  current,
+		Previous: previous,
+		Change:   c.check(current.Sub(previous)),
+	}
+	if !previous.IsZero() {
+		percent := math.Round(float64(comparison.Change.Cents())*10000/float64(previous.Abs().Cents())) / 100
+		comparison.ChangePercent = &percent
+	}
+	return comparison
+}
+
+func (c *comparer) net(flow domain.CashFlow) domain.Money {
+	return c.check(flow.Net())
+}
+
+func (c *comparer) add(a, b domain.Money) domain.Money {
+	return c.check(a.Add(b))
+}
+
+func (c *comparer) check(m domain.Money, err error) domain.Money {
+	if err != nil && c.err == nil {
+		c.err = err
+	}
+	return m
+}
+
+// share returns part as a percentage of total, rounded to two decimals.
+func share(part, total domain.Money) float64 {
+	if !total.IsPositive() {
+		return 0
+	}
+	return math.Round(float64(part.Cents())*10000/float64(total.Cents())) / 100
+}
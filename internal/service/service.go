@@ -1,26 +1,55 @@
 package service
 
 import (
+	"finly-backend/internal/config"
 	"finly-backend/internal/repository"
+	"finly-backend/internal/service/api_key"
 	"finly-backend/internal/service/auth"
 	"finly-backend/internal/service/budget"
+	"finly-backend/internal/service/budget_member"
 	"finly-backend/internal/service/category"
+	"finly-backend/internal/service/category_limit"
+	"finly-backend/internal/service/data_export"
+	"finly-backend/internal/service/recurring"
+	"finly-backend/internal/service/report"
+	"finly-backend/internal/service/session"
 	"finly-backend/internal/service/transaction"
+	"finly-backend/internal/service/transaction_rule"
+	"finly-backend/pkg/mailer"
 	transactionExec "finly-backend/pkg/transaction"
 )
 
 type Service struct {
-	Auth        auth.Auth
-	Budget      budget.Budget
-	Category    category.Category
-	Transaction transaction.Transaction
+	Auth            auth.Auth
+	Budget          budget.Budget
+	Category        category.Category
+	Transaction     transaction.Transaction
+	Recurring       recurring.Recurring
+	CategoryLimit   category_limit.CategoryLimit
+	Report          report.Report
+	Session         session.Session
+	DataExport      data_export.DataExport
+	APIKey          api_key.APIKey
+	BudgetMember    budget_member.BudgetMember
+	TransactionRule transaction_rule.TransactionRule
 }
 
-func NewService(repos *repository.Repository) *Service {
+func NewService(repos *repository.Repository, cfg *config.Config, mail mailer.Mailer) *Service {
+	transactionService := transaction.NewService(repos.Transaction, repos.Budget, repos.BudgetHistory, repos.Category, repos.CategoryLimit, repos.TransactionRule, transactionExec.NewTransactionExecutor())
+	userCaches := []auth.UserCache{repos.Budget, repos.BudgetHistory, repos.Category, repos.CategoryLimit, repos.Recurring, repos.Transaction, repos.APIKey, repos.TransactionRule}
+
 	return &Service{
-		Auth:        auth.NewService(repos.Auth, repos.Budget),
-		Budget:      budget.NewService(repos.Budget, repos.BudgetHistory, transactionExec.NewTransactionExecutor()),
-		Category:    category.NewService(repos.Category),
-		Transaction: transaction.NewService(repos.Transaction, repos.BudgetHistory, transactionExec.NewTransactionExecutor()),
+		Auth:            auth.NewService(repos.Auth, repos.Budget, repos.RefreshToken, repos.Session, repos.PasswordReset, repos.EmailVerification, repos.TwoFactor, repos.LoginAttempt, userCaches, mail, cfg.AppURL, transactionExec.NewTransactionExecutor()),
+		Budget:          budget.NewService(repos.Budget, repos.BudgetHistory, transactionExec.NewTransactionExecutor()),
+		Category:        category.NewService(repos.Category, []category.CategoryReference{repos.Transaction, repos.Recurring, repos.CategoryLimit, repos.TransactionRule}, transactionExec.NewTransactionExecutor()),
+		Transaction:     transactionService,
+		Recurring:       recurring.NewService(repos.Recurring, repos.Budget, repos.Category, transactionService),
+		CategoryLimit:   category_limit.NewService(repos.CategoryLimit, repos.Budget, repos.Category),
+		Report:          report.NewService(repos.Report, repos.Budget),
+		Session:         session.NewService(repos.Session, repos.RefreshToken, transactionExec.NewTransactionExecutor()),
+		DataExport:      data_export.NewService(repos.DataExport, repos.Auth, repos.Budget, repos.BudgetHistory, repos.Category, repos.Transaction),
+		APIKey:          api_key.NewService(repos.APIKey),
+		BudgetMember:    budget_member.NewService(repos.Budget, repos.BudgetInvitation, repos.Auth, mail, cfg.AppURL, transactionExec.NewTransactionExecutor()),
+		TransactionRule: transaction_rule.NewService(repos.TransactionRule, repos.Budget, repos.Category, repos.Transaction, transactionExec.NewTransactionExecutor()),
 	}
 }
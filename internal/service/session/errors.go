@@ -0,0 +1,16 @@
+package session
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+var errs = struct {
+	SessionNotFound *echo.HTTPError
+	SessionRevoked  *echo.HTTPError
+	DatabaseError   *echo.HTTPError
+}{
+	SessionNotFound: echo.NewHTTPError(http.StatusNotFound, "Session not found"),
+	SessionRevoked:  echo.NewHTTPError(http.StatusUnauthorized, "Session has been revoked"),
+	DatabaseError:   echo.NewHTTPError(http.StatusInternalServerError, "Database operation failed"),
+}
@@ -0,0 +1,52 @@
+package session
+
+import (
+	"finly-backend/internal/domain"
+	"time"
+)
+
+type SessionObject struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+type ListSessionsRequest struct {
+	UserID    string `header:"User-Id" validate:"required"`
+	SessionID string `header:"Session-Id"`
+}
+
+type ListSessionsResponse struct {
+	Sessions []*SessionObject `json:"sessions"`
+}
+
+type RevokeSessionRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	ID     string `param:"id" validate:"required,uuid"`
+}
+
+type RevokeSessionResponse struct{}
+
+// RevokeOtherSessionsRequest ends every session of the user except the one making the request.
+type RevokeOtherSessionsRequest struct {
+	UserID    string `header:"User-Id" validate:"required"`
+	SessionID string `header:"Session-Id" validate:"required"`
+}
+
+type RevokeOtherSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+func convertSession(session *domain.Session, currentID string) *SessionObject {
+	return &SessionObject{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		Current:    session.ID == currentID,
+	}
+}
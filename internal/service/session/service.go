@@ -0,0 +1,133 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/repository/refresh_token"
+	"finly-backend/internal/repository/session"
+	"finly-backend/pkg/security"
+	"finly-backend/pkg/transaction"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+	"time"
+)
+
+type Session interface {
+	List(ctx context.Context, req *ListSessionsRequest) (*ListSessionsResponse, error)
+	Revoke(ctx context.Context, req *RevokeSessionRequest) (*RevokeSessionResponse, error)
+	RevokeOthers(ctx context.Context, req *RevokeOtherSessionsRequest) (*RevokeOtherSessionsResponse, error)
+	Validate(ctx context.Context, sessionID, userID, ip string) error
+}
+
+type Service struct {
+	sessionRepo         session.Session
+	refreshTokenRepo    refresh_token.RefreshToken
+	transactionExecutor transaction.TransactionExecutor
+}
+
+func NewService(sessionRepo session.Session, refreshTokenRepo refresh_token.RefreshToken, transactionExecutor transaction.TransactionExecutor) *Service {
+	return &Service{
+		sessionRepo:         sessionRepo,
+		refreshTokenRepo:    refreshTokenRepo,
+		transactionExecutor: transactionExecutor,
+	}
+}
+
+// List returns the sessions that can still be refreshed, most recently used first.
+func (s *Service) List(ctx context.Context, req *ListSessionsRequest) (*ListSessionsResponse, error) {
+	sessions, err := s.sessionRepo.ListActiveByUserID(ctx, req.UserID, time.Now().UTC().Add(-security.RefreshTokenTTL))
+	if err != nil {
+		zap.L().Sugar().Errorf("List: failed for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	list := make([]*SessionObject, 0, len(sessions))
+	for _, session := range sessions {
+		list = append(list, convertSession(session, req.SessionID))
+	}
+
+	return &ListSessionsResponse{Sessions: list}, nil
+}
+
+// Revoke ends one session of the user, which may be the current one.
+func (s *Service) Revoke(ctx context.Context, req *RevokeSessionRequest) (*RevokeSessionResponse, error) {
+	var revoked bool
+	err := s.transactionExecutor.WithTransaction(ctx, s.sessionRepo.GetDB(), func(tx *sqlx.Tx) error {
+		var err error
+		if revoked, err = s.sessionRepo.RevokeTX(ctx, tx, req.ID, req.UserID); err != nil || !revoked {
+			return err
+		}
+		return s.refreshTokenRepo.RevokeFamilyTX(ctx, tx, req.ID)
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("Revoke: failed for sessionID=%s, userID=%s: %v", req.ID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+	if !revoked {
+		return nil, errs.SessionNotFound
+	}
+	s.invalidateCache(ctx, req.ID)
+
+	zap.L().Sugar().Infof("Revoke: completed for sessionID=%s, userID=%s", req.ID, req.UserID)
+	return &RevokeSessionResponse{}, nil
+}
+
+func (s *Service) RevokeOthers(ctx context.Context, req *RevokeOtherSessionsRequest) (*RevokeOtherSessionsResponse, error) {
+	var ids []string
+	err := s.transactionExecutor.WithTransaction(ctx, s.sessionRepo.GetDB(), func(tx *sqlx.Tx) error {
+		var err error
+		if ids, err = s.sessionRepo.RevokeOthersTX(ctx, tx, req.UserID, req.SessionID); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err = s.refreshTokenRepo.RevokeFamilyTX(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("RevokeOthers: failed for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+	s.invalidateCache(ctx, ids...)
+
+	zap.L().Sugar().Infof("RevokeOthers: revoked %d sessions of userID=%s", len(ids), req.UserID)
+	return &RevokeOtherSessionsResponse{Revoked: len(ids)}, nil
+}
+
+// invalidateCache drops revoked sessions from the cache once the revocation is committed.
+func (s *Service) invalidateCache(ctx context.Context, sessionIDs ...string) {
+	if err := s.sessionRepo.InvalidateCache(ctx, sessionIDs...); err != nil {
+		zap.L().Sugar().Warnf("invalidateCache: failed for sessionIDs=%v: %v", sessionIDs, err)
+	}
+}
+
+// Validate checks the session of an access token on every authenticated request and
+// records it as seen. Tokens without a session are rejected.
+func (s *Service) Validate(ctx context.Context, sessionID, userID, ip string) error {
+	if sessionID == "" {
+		return errs.SessionRevoked
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.SessionRevoked
+		}
+		zap.L().Sugar().Errorf("Validate: failed to get sessionID=%s: %v", sessionID, err)
+		return errs.DatabaseError
+	}
+
+	if session.UserID != userID || session.RevokedAt.Valid {
+		zap.L().Sugar().Warnf("Validate: rejected revoked sessionID=%s for userID=%s", sessionID, userID)
+		return errs.SessionRevoked
+	}
+
+	if err = s.sessionRepo.Touch(ctx, sessionID, ip); err != nil {
+		zap.L().Sugar().Warnf("Validate: failed to record activity of sessionID=%s: %v", sessionID, err)
+	}
+
+	return nil
+}
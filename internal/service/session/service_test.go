@@ -0,0 +1,182 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	mock_refresh_token "finly-backend/internal/repository/refresh_token/mock"
+	mock_session "finly-backend/internal/repository/session/mock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type mockTransactionExecutor struct{}
+
+func (m *mockTransactionExecutor) WithTransaction(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	return fn(nil)
+}
+
+func TestList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	service := NewService(mockSessionRepo, mock_refresh_token.NewMockRefreshToken(ctrl), &mockTransactionExecutor{})
+	seen := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mockSessionRepo.EXPECT().ListActiveByUserID(ctx, "user123", gomock.Any()).Return([]*domain.Session{
+		{ID: "session1", UserID: "user123", UserAgent: "curl/8.0", IP: "10.0.0.1", CreatedAt: seen, LastSeenAt: seen},
+		{ID: "session2", UserID: "user123", CreatedAt: seen, LastSeenAt: seen},
+	}, nil)
+
+	res, err := service.List(ctx, &ListSessionsRequest{UserID: "user123", SessionID: "session2"})
+	assert.NoError(t, err)
+	assert.Equal(t, &ListSessionsResponse{Sessions: []*SessionObject{
+		{ID: "session1", UserAgent: "curl/8.0", IP: "10.0.0.1", CreatedAt: seen, LastSeenAt: seen},
+		{ID: "session2", CreatedAt: seen, LastSeenAt: seen, Current: true},
+	}}, res)
+}
+
+func TestRevoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	mockRefreshTokenRepo := mock_refresh_token.NewMockRefreshToken(ctrl)
+	service := NewService(mockSessionRepo, mockRefreshTokenRepo, &mockTransactionExecutor{})
+
+	tests := []struct {
+		name        string
+		setupMocks  func()
+		expectedRes *RevokeSessionResponse
+		expectedErr error
+	}{
+		{
+			name: "Revoked with its refresh tokens",
+			setupMocks: func() {
+				mockSessionRepo.EXPECT().GetDB().Return(nil)
+				mockSessionRepo.EXPECT().RevokeTX(ctx, gomock.Any(), "session1", "user123").Return(true, nil)
+				mockRefreshTokenRepo.EXPECT().RevokeFamilyTX(ctx, gomock.Any(), "session1").Return(nil)
+				mockSessionRepo.EXPECT().InvalidateCache(ctx, "session1").Return(nil)
+			},
+			expectedRes: &RevokeSessionResponse{},
+		},
+		{
+			name: "Unknown or already revoked session",
+			setupMocks: func() {
+				mockSessionRepo.EXPECT().GetDB().Return(nil)
+				mockSessionRepo.EXPECT().RevokeTX(ctx, gomock.Any(), "session1", "user123").Return(false, nil)
+			},
+			expectedErr: errs.SessionNotFound,
+		},
+		{
+			name: "Database error",
+			setupMocks: func() {
+				mockSessionRepo.EXPECT().GetDB().Return(nil)
+				mockSessionRepo.EXPECT().RevokeTX(ctx, gomock.Any(), "session1", "user123").Return(false, errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+			res, err := service.Revoke(ctx, &RevokeSessionRequest{UserID: "user123", ID: "session1"})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedRes, res)
+		})
+	}
+}
+
+func TestRevokeOthers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	mockRefreshTokenRepo := mock_refresh_token.NewMockRefreshToken(ctrl)
+	service := NewService(mockSessionRepo, mockRefreshTokenRepo, &mockTransactionExecutor{})
+
+	mockSessionRepo.EXPECT().GetDB().Return(nil)
+	mockSessionRepo.EXPECT().RevokeOthersTX(ctx, gomock.Any(), "user123", "current").Return([]string{"session1", "session2"}, nil)
+	mockRefreshTokenRepo.EXPECT().RevokeFamilyTX(ctx, gomock.Any(), "session1").Return(nil)
+	mockRefreshTokenRepo.EXPECT().RevokeFamilyTX(ctx, gomock.Any(), "session2").Return(nil)
+	mockSessionRepo.EXPECT().InvalidateCache(ctx, "session1", "session2").Return(nil)
+
+	res, err := service.RevokeOthers(ctx, &RevokeOtherSessionsRequest{UserID: "user123", SessionID: "current"})
+	assert.NoError(t, err)
+	assert.Equal(t, &RevokeOtherSessionsResponse{Revoked: 2}, res)
+}
+
+func TestValidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockSessionRepo := mock_session.NewMockSession(ctrl)
+	service := NewService(mockSessionRepo, mock_refresh_token.NewMockRefreshToken(ctrl), &mockTransactionExecutor{})
+
+	tests := []struct {
+		name        string
+		sessionID   string
+		setupMocks  func()
+		expectedErr error
+	}{
+		{
+			name:      "Active session",
+			sessionID: "session1",
+			setupMocks: func() {
+				mockSessionRepo.EXPECT().GetByID(ctx, "session1").Return(&domain.Session{ID: "session1", UserID: "user123"}, nil)
+				mockSessionRepo.EXPECT().Touch(ctx, "session1", "10.0.0.1").Return(nil)
+			},
+		},
+		{
+			name:        "Token without session",
+			setupMocks:  func() {},
+			expectedErr: errs.SessionRevoked,
+		},
+		{
+			name:      "Revoked session",
+			sessionID: "session1",
+			setupMocks: func() {
+				mockSessionRepo.EXPECT().GetByID(ctx, "session1").Return(&domain.Session{
+					ID:        "session1",
+					UserID:    "user123",
+					RevokedAt: sql.NullTime{Time: time.Now(), Valid: true},
+				}, nil)
+			},
+			expectedErr: errs.SessionRevoked,
+		},
+		{
+			name:      "Session of another user",
+			sessionID: "session1",
+			setupMocks: func() {
+				mockSessionRepo.EXPECT().GetByID(ctx, "session1").Return(&domain.Session{ID: "session1", UserID: "user456"}, nil)
+			},
+			expectedErr: errs.SessionRevoked,
+		},
+		{
+			name:      "Deleted session",
+			sessionID: "session1",
+			setupMocks: func() {
+				mockSessionRepo.EXPECT().GetByID(ctx, "session1").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.SessionRevoked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+			err := service.Validate(ctx, tt.sessionID, "user123", "10.0.0.1")
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
@@ -10,11 +10,49 @@ var errs = struct {
 	InsufficientBalance    *echo.HTTPError
 	InvalidTransactionType *echo.HTTPError
 	InvalidInput           *echo.HTTPError
+	InvalidCursor          *echo.HTTPError
+	BudgetNotFound         *echo.HTTPError
+	BudgetForbidden        *echo.HTTPError
+	BudgetArchived         *echo.HTTPError
+	TransactionNotFound    *echo.HTTPError
+	TransferNotFound       *echo.HTTPError
+	SameBudgetTransfer     *echo.HTTPError
+	CurrencyMismatch       *echo.HTTPError
+	InvalidImportMapping   *echo.HTTPError
+	InvalidImportFile      *echo.HTTPError
+	TooManyImportRows      *echo.HTTPError
+	ExportBudgetRequired   *echo.HTTPError
+	CategoryLimitExceeded  *echo.HTTPError
+	CategoryRequired       *echo.HTTPError
+	CategoryNotFound       *echo.HTTPError
+	FutureDate             *echo.HTTPError
+	AmountOutOfRange       *echo.HTTPError
 	DatabaseError          *echo.HTTPError
 }{
 	NotEnoughBudget:        echo.NewHTTPError(http.StatusBadRequest, "Not enough budget"),
 	InsufficientBalance:    echo.NewHTTPError(http.StatusBadRequest, "Insufficient balance"),
 	InvalidTransactionType: echo.NewHTTPError(http.StatusBadRequest, "Invalid transaction type"),
 	InvalidInput:           echo.NewHTTPError(http.StatusBadRequest, "Invalid input provided"),
+	InvalidCursor:          echo.NewHTTPError(http.StatusBadRequest, "Invalid pagination cursor"),
+	BudgetNotFound:         echo.NewHTTPError(http.StatusNotFound, "Budget not found"),
+	BudgetForbidden:        echo.NewHTTPError(http.StatusForbidden, "Your role in this budget does not allow this"),
+	BudgetArchived:         echo.NewHTTPError(http.StatusConflict, "Budget is archived; unarchive it to make changes"),
+	TransactionNotFound:    echo.NewHTTPError(http.StatusNotFound, "Transaction not found"),
+	TransferNotFound:       echo.NewHTTPError(http.StatusNotFound, "Transfer not found"),
+	SameBudgetTransfer:     echo.NewHTTPError(http.StatusBadRequest, "Cannot transfer to the same budget"),
+	CurrencyMismatch:       echo.NewHTTPError(http.StatusBadRequest, "Budgets have different currencies"),
+	InvalidImportMapping:   echo.NewHTTPError(http.StatusBadRequest, "Invalid import column mapping or format"),
+	InvalidImportFile:      echo.NewHTTPError(http.StatusBadRequest, "Import file could not be parsed"),
+	TooManyImportRows:      echo.NewHTTPError(http.StatusRequestEntityTooLarge, "Import file has too many rows"),
+	ExportBudgetRequired:   echo.NewHTTPError(http.StatusBadRequest, "OFX export requires a budget_id"),
+	CategoryLimitExceeded:  echo.NewHTTPError(http.StatusUnprocessableEntity, "Withdrawal would exceed the category spending limit"),
+	CategoryRequired:       echo.NewHTTPError(http.StatusBadRequest, "No transaction rule sets a category; category_id is required"),
+	CategoryNotFound:       echo.NewHTTPError(http.StatusNotFound, "Category not found"),
+	FutureDate:             echo.NewHTTPError(http.StatusBadRequest, "Transactions can't be booked in the future"),
+	AmountOutOfRange:       echo.NewHTTPError(http.StatusBadRequest, "Amount or resulting balance is out of range"),
 	DatabaseError:          echo.NewHTTPError(http.StatusInternalServerError, "Database operation failed"),
 }
+
+// ErrAlreadyBooked is returned by Create when the budget already has a transaction with the
+// request's ExternalID.
+var ErrAlreadyBooked = echo.NewHTTPError(http.StatusConflict, "Transaction was already booked")
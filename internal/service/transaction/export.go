@@ -0,0 +1,92 @@
+package transaction
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"finly-backend/internal/domain"
+	"io"
+	"strings"
+	"time"
+)
+
+// exporter writes exported transactions in one file format. begin is called before the
+// first row and end after the last one, also when there are no rows at all.
+type exporter interface {
+	begin() error
+	write(row *domain.TransactionExport) error
+	end() error
+}
+
+var csvExportHeader = []string{
+	"id", "date", "budget_id", "category_id", "category", "type", "amount", "balance", "note", "transfer_id", "external_id",
+}
+
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func newCSVExporter(w io.Writer) *csvExporter {
+	return &csvExporter{w: csv.NewWriter(w)}
+}
+
+func (e *csvExporter) begin() error {
+	return e.w.Write(csvExportHeader)
+}
+
+func (e *csvExporter) write(row *domain.TransactionExport) error {
+	var balance string
+	if row.Balance != nil {
+		balance = row.Balance.String()
+	}
+
+	return e.w.Write([]string{
+		row.ID,
+		row.CreatedAt.UTC().Format(time.RFC3339),
+		row.BudgetID,
+		row.CategoryID,
+		CSVSafe(row.CategoryName),
+		row.TransactionType,
+		row.Amount.String(),
+		balance,
+		CSVSafe(row.Note),
+		row.TransferID.String,
+		row.ExternalID.String,
+	})
+}
+
+func (e *csvExporter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// CSVSafe keeps spreadsheet applications from evaluating user-entered text as a formula.
+func CSVSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+type jsonlExporter struct {
+	enc *json.Encoder
+}
+
+func newJSONLExporter(w io.Writer) *jsonlExporter {
+	return &jsonlExporter{enc: json.NewEncoder(w)}
+}
+
+func (e *jsonlExporter) begin() error {
+	return nil
+}
+
+func (e *jsonlExporter) write(row *domain.TransactionExport) error {
+	return e.enc.Encode(ExportTransactionObject{
+		TransactionObject: convertTransaction(&row.Transaction),
+		CategoryName:      row.CategoryName,
+		Balance:           row.Balance,
+	})
+}
+
+func (e *jsonlExporter) end() error {
+	return nil
+}
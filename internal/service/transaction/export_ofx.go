@@ -0,0 +1,114 @@
+package transaction
+
+import (
+	"bufio"
+	"encoding/xml"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_transaction_type"
+	"io"
+	"time"
+)
+
+const (
+	ofxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n" +
+		`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+	ofxDateLayout = "20060102150405.000[0:GMT]"
+	ofxBankID     = "FINLY"
+
+	// maxOFXNameLength is the limit the OFX specification puts on NAME.
+	maxOFXNameLength = 32
+)
+
+// ofxExporter writes an OFX 2.2 bank statement of a single budget. The statement period
+// comes from the export filter; the ledger balance is the balance after the latest exported
+// transaction, or the current balance when nothing was exported.
+type ofxExporter struct {
+	w      *bufio.Writer
+	budget *domain.Budget
+	from   time.Time
+	to     time.Time
+
+	ledger   domain.Money
+	ledgerAt time.Time
+	seen     bool
+}
+
+func newOFXExporter(w io.Writer, budget *domain.Budget, from, to time.Time, current domain.Money) *ofxExporter {
+	return &ofxExporter{
+		w:        bufio.NewWriter(w),
+		budget:   budget,
+		from:     from,
+		to:       to,
+		ledger:   current,
+		ledgerAt: to,
+	}
+}
+
+func (e *ofxExporter) begin() error {
+	e.w.WriteString(ofxHeader)
+	e.w.WriteString("<OFX>\n<SIGNONMSGSRSV1><SONRS>")
+	e.w.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>")
+	e.element("DTSERVER", time.Now().UTC().Format(ofxDateLayout))
+	e.w.WriteString("<LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>\n")
+	e.w.WriteString("<BANKMSGSRSV1><STMTTRNRS><TRNUID>0</TRNUID>")
+	e.w.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n<STMTRS>")
+	e.element("CURDEF", e.budget.Currency)
+	e.w.WriteString("<BANKACCTFROM>")
+	e.element("BANKID", ofxBankID)
+	e.element("ACCTID", e.budget.ID)
+	e.w.WriteString("<ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>\n<BANKTRANLIST>")
+	e.element("DTSTART", e.from.UTC().Format(ofxDateLayout))
+	e.element("DTEND", e.to.UTC().Format(ofxDateLayout))
+	_, err := e.w.WriteString("\n")
+	return err
+}
+
+func (e *ofxExporter) write(row *domain.TransactionExport) error {
+	amount, err := calculateDelta(row.TransactionType, row.Amount)
+	if err != nil {
+		return err
+	}
+
+	trnType := "DEBIT"
+	switch {
+	case row.TransferID.Valid:
+		trnType = "XFER"
+	case row.TransactionType == e_transaction_type.Deposit.String():
+		trnType = "CREDIT"
+	}
+
+	e.w.WriteString("<STMTTRN>")
+	e.element("TRNTYPE", trnType)
+	e.element("DTPOSTED", row.CreatedAt.UTC().Format(ofxDateLayout))
+	e.element("TRNAMT", amount.String())
+	e.element("FITID", row.ID)
+	if name := []rune(row.CategoryName); len(name) > 0 {
+		e.element("NAME", string(name[:min(len(name), maxOFXNameLength)]))
+	}
+	if row.Note != "" {
+		e.element("MEMO", row.Note)
+	}
+	_, err = e.w.WriteString("</STMTTRN>\n")
+
+	if row.Balance != nil && (!e.seen || row.CreatedAt.After(e.ledgerAt)) {
+		e.ledger, e.ledgerAt, e.seen = *row.Balance, row.CreatedAt, true
+	}
+
+	return err
+}
+
+func (e *ofxExporter) end() error {
+	e.w.WriteString("</BANKTRANLIST>\n<LEDGERBAL>")
+	e.element("BALAMT", e.ledger.String())
+	e.element("DTASOF", e.ledgerAt.UTC().Format(ofxDateLayout))
+	e.w.WriteString("</LEDGERBAL>\n</STMTRS></STMTTRNRS></BANKMSGSRSV1>\n</OFX>\n")
+	return e.w.Flush()
+}
+
+// element writes a closed leaf element. Write errors are sticky in bufio.Writer and
+// surface from the next explicit write or Flush.
+func (e *ofxExporter) element(tag, value string) {
+	e.w.WriteString("<" + tag + ">")
+	xml.EscapeText(e.w, []byte(value))
+	e.w.WriteString("</" + tag + ">")
+}
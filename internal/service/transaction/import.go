@@ -0,0 +1,275 @@
+package transaction
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_transaction_type"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxNoteLength = 255
+
+// importRow is one statement line after parsing. Rows that could not be parsed or
+// booked carry the reason in err and are never written.
+type importRow struct {
+	line       int
+	date       time.Time
+	amount     domain.Money
+	typ        e_transaction_type.Enum
+	note       string
+	externalID string
+	categoryID string
+	tags       []string
+	err        string
+
+	duplicate     bool
+	transactionID string
+}
+
+// bookable reports whether the row should be written to the budget.
+func (r *importRow) bookable() bool {
+	return r.err == "" && !r.duplicate
+}
+
+// assignExternalIDs gives every row without a bank-provided ID (such as an OFX FITID) a
+// content hash of its date, amount and memo, so re-importing an overlapping statement
+// recognises rows it has already seen. Identical rows in one file are told apart by their
+// occurrence, which is stable across exports of the same period. Repeated IDs within the
+// file are marked as duplicates.
+func assignExternalIDs(rows []*importRow) {
+	occurrences := make(map[string]int)
+	seen := make(map[string]bool)
+
+	for _, row := range rows {
+		if row.err != "" {
+			continue
+		}
+
+		if row.externalID == "" {
+			key := fmt.Sprintf("%s|%s|%s|%s", row.date.Format("2006-01-02"), row.typ, row.amount, strings.ToLower(row.note))
+			occurrences[key]++
+			sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", key, occurrences[key])))
+			row.externalID = "sha256:" + hex.EncodeToString(sum[:])
+		}
+
+		if seen[row.externalID] {
+			row.duplicate = true
+		}
+		seen[row.externalID] = true
+	}
+}
+
+// categorizeRows books every parsed row into the category given with the import or, when
+// none is, into the one the user's rules pick. Rules may also tag the rows. A row no rule
+// picks a category for cannot be booked.
+func categorizeRows(rows []*importRow, budgetID, categoryID string, rules *domain.RuleSet) {
+	for _, row := range rows {
+		if row.err != "" {
+			continue
+		}
+		if categoryID != "" {
+			row.categoryID = categoryID
+			continue
+		}
+
+		row.categoryID, row.tags = rules.Match(&domain.Transaction{
+			BudgetID:        budgetID,
+			Amount:          row.amount,
+			TransactionType: row.typ.String(),
+			Note:            row.note,
+		})
+		if row.categoryID == "" {
+			row.err = errorMessage(errs.CategoryRequired)
+		}
+	}
+}
+
+// externalIDs returns the IDs of the rows that are still candidates for booking.
+func externalIDs(rows []*importRow) []string {
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.bookable() {
+			ids = append(ids, row.externalID)
+		}
+	}
+	return ids
+}
+
+// ledgerEntry is one step of a budget's balance history: either an existing
+// budgets_history row or an imported row that is about to be written.
+type ledgerEntry struct {
+	history *domain.BudgetHistory
+	row     *importRow
+	at      time.Time
+	balance domain.Money
+	dirty   bool
+}
+
+// planImport books the rows into the balance history in chronological order, using the
+// same balance rules as Create. Every later entry is shifted by the row's delta, and a row
+// that would leave any balance negative is rejected instead of applied. Rows dated after now
+// are rejected too, as the latest entry must stay the current balance.
+func planImport(history []*domain.BudgetHistory, rows []*importRow, now time.Time) []*ledgerEntry {
+	ledger := make([]*ledgerEntry, 0, len(history)+len(rows))
+	for _, h := range history {
+		ledger = append(ledger, &ledgerEntry{history: h, at: h.CreatedAt, balance: h.Balance})
+	}
+
+	ordered := make([]*importRow, 0, len(rows))
+	for _, row := range rows {
+		if row.bookable() {
+			ordered = append(ordered, row)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].date.Before(ordered[j].date) })
+
+	for _, row := range ordered {
+		if row.date.After(now) {
+			row.err = errorMessage(errs.FutureDate)
+			continue
+		}
+
+		idx := sort.Search(len(ledger), func(i int) bool { return ledger[i].at.After(row.date) })
+
+		var previous *domain.BudgetHistory
+		at := row.date
+		if idx > 0 {
+			previous = &domain.BudgetHistory{Balance: ledger[idx-1].balance}
+			// Keep booking times strictly increasing so the latest balance stays unambiguous.
+			if !at.After(ledger[idx-1].at) {
+				at = ledger[idx-1].at.Add(time.Microsecond)
+			}
+		}
+
+		balance, err := calculateNewAmount(previous, row.amount, row.typ.String())
+		if err != nil {
+			row.err = errorMessage(err)
+			continue
+		}
+
+		delta, err := calculateDelta(row.typ.String(), row.amount)
+		if err != nil {
+			row.err = errorMessage(err)
+			continue
+		}
+
+		later := ledger[idx:]
+		shifted, err := shiftBalances(later, delta)
+		if err != nil {
+			row.err = errorMessage(err)
+			continue
+		}
+		for i, e := range later {
+			e.balance = shifted[i]
+			e.dirty = true
+		}
+
+		ledger = slices.Insert(ledger, idx, &ledgerEntry{row: row, at: at, balance: balance})
+	}
+
+	return ledger
+}
+
+// shiftBalances returns the balances of the entries shifted by delta, or an error if any of
+// them would go negative or out of range.
+func shiftBalances(entries []*ledgerEntry, delta domain.Money) ([]domain.Money, error) {
+	shifted := make([]domain.Money, len(entries))
+	for i, e := range entries {
+		balance, err := e.balance.Add(delta)
+		if err != nil {
+			return nil, errs.AmountOutOfRange
+		}
+		if balance.IsNegative() {
+			return nil, errs.InsufficientBalance
+		}
+		shifted[i] = balance
+	}
+	return shifted, nil
+}
+
+// importResults builds the per-row report in file order.
+func importResults(rows []*importRow, ledger []*ledgerEntry) []ImportRowResult {
+	booked := make(map[*importRow]*ledgerEntry, len(rows))
+	for _, e := range ledger {
+		if e.row != nil {
+			booked[e.row] = e
+		}
+	}
+
+	results := make([]ImportRowResult, 0, len(rows))
+	for _, row := range rows {
+		result := ImportRowResult{
+			Line:          row.line,
+			Amount:        row.amount,
+			Type:          row.typ,
+			Note:          row.note,
+			ExternalID:    row.externalID,
+			CategoryID:    row.categoryID,
+			Tags:          row.tags,
+			TransactionID: row.transactionID,
+			Duplicate:     row.duplicate,
+			Error:         row.err,
+		}
+		if !row.date.IsZero() {
+			date := row.date
+			result.Date = &date
+		}
+		if e, ok := booked[row]; ok {
+			balance := e.balance
+			result.Balance = &balance
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// setDirection derives the transaction type from the sign of a parsed amount.
+func setDirection(row *importRow) error {
+	if row.amount.IsZero() {
+		return errors.New("amount must not be zero")
+	}
+
+	row.typ = e_transaction_type.Deposit
+	if row.amount.IsNegative() {
+		row.typ = e_transaction_type.Withdrawal
+	}
+	row.amount = row.amount.Abs()
+
+	return nil
+}
+
+// joinNote combines the payee and memo of a statement entry into a transaction note.
+func joinNote(parts ...string) string {
+	kept := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" && !slices.Contains(kept, part) {
+			kept = append(kept, part)
+		}
+	}
+	return truncateNote(strings.Join(kept, " - "))
+}
+
+// truncateNote cuts notes to the length of the transactions.note column.
+func truncateNote(note string) string {
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		return string([]rune(note)[:maxNoteLength])
+	}
+	return note
+}
+
+func errorMessage(err error) string {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return fmt.Sprint(httpErr.Message)
+	}
+	return err.Error()
+}
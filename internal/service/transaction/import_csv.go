@@ -0,0 +1,330 @@
+package transaction
+
+import (
+	"encoding/csv"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_transaction_type"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// dateFormatTokens translates human-friendly date formats such as "DD.MM.YYYY" into Go layouts.
+// Longer tokens come first so "YYYY" is not consumed as two "YY".
+var dateFormatTokens = strings.NewReplacer(
+	"YYYY", "2006",
+	"YY", "06",
+	"MM", "01",
+	"DD", "02",
+	"HH", "15",
+	"mm", "04",
+	"ss", "05",
+)
+
+// typeAliases maps the direction markers banks put into a type column onto transaction types.
+var typeAliases = map[string]e_transaction_type.Enum{
+	"deposit":    e_transaction_type.Deposit,
+	"credit":     e_transaction_type.Deposit,
+	"cr":         e_transaction_type.Deposit,
+	"c":          e_transaction_type.Deposit,
+	"income":     e_transaction_type.Deposit,
+	"in":         e_transaction_type.Deposit,
+	"+":          e_transaction_type.Deposit,
+	"withdrawal": e_transaction_type.Withdrawal,
+	"debit":      e_transaction_type.Withdrawal,
+	"dr":         e_transaction_type.Withdrawal,
+	"d":          e_transaction_type.Withdrawal,
+	"expense":    e_transaction_type.Withdrawal,
+	"out":        e_transaction_type.Withdrawal,
+	"-":          e_transaction_type.Withdrawal,
+}
+
+// csvMapping is the validated column mapping and formats of a CSV import.
+type csvMapping struct {
+	date, amount, debit, credit, kind, note string
+
+	layout    string
+	decimal   byte
+	delimiter rune
+	header    bool
+}
+
+func newCSVMapping(req *ImportTransactionsRequest) (csvMapping, error) {
+	if req.DateColumn == "" || (req.AmountColumn == "" && (req.DebitColumn == "" || req.CreditColumn == "")) {
+		return csvMapping{}, errs.InvalidImportMapping
+	}
+
+	m := csvMapping{
+		date:      req.DateColumn,
+		amount:    req.AmountColumn,
+		debit:     req.DebitColumn,
+		credit:    req.CreditColumn,
+		kind:      req.TypeColumn,
+		note:      req.NoteColumn,
+		layout:    dateFormatTokens.Replace(defaultImportDateFormat),
+		decimal:   '.',
+		delimiter: ',',
+		header:    !req.NoHeader,
+	}
+
+	if req.DateFormat != "" {
+		m.layout = dateFormatTokens.Replace(req.DateFormat)
+	}
+	if req.DecimalSeparator != "" {
+		m.decimal = req.DecimalSeparator[0]
+		if m.decimal != '.' && m.decimal != ',' {
+			return m, errs.InvalidImportMapping
+		}
+	}
+	if req.Delimiter != "" {
+		m.delimiter, _ = utf8.DecodeRuneInString(req.Delimiter)
+		if m.delimiter == '"' || m.delimiter == '\n' || m.delimiter == '\r' || rune(m.decimal) == m.delimiter {
+			return m, errs.InvalidImportMapping
+		}
+	}
+
+	return m, nil
+}
+
+// csvColumns holds the resolved 0-based positions of the mapped columns; -1 means unmapped.
+type csvColumns struct {
+	date, amount, debit, credit, kind, note int
+}
+
+func (m csvMapping) resolve(header []string) (csvColumns, error) {
+	names := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		names[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	column := func(ref string) (int, error) {
+		if ref == "" {
+			return -1, nil
+		}
+		if i, ok := names[strings.ToLower(strings.TrimSpace(ref))]; ok {
+			return i, nil
+		}
+		n, err := strconv.Atoi(ref)
+		if err != nil || n < 1 || (m.header && n > len(header)) {
+			return -1, errs.InvalidImportMapping
+		}
+		return n - 1, nil
+	}
+
+	var (
+		cols csvColumns
+		err  error
+	)
+	for _, c := range []struct {
+		ref string
+		dst *int
+	}{
+		{m.date, &cols.date}, {m.amount, &cols.amount}, {m.debit, &cols.debit},
+		{m.credit, &cols.credit}, {m.kind, &cols.kind}, {m.note, &cols.note},
+	} {
+		if *c.dst, err = column(c.ref); err != nil {
+			return cols, err
+		}
+	}
+
+	return cols, nil
+}
+
+// parseCSV reads a bank statement. Structural problems fail the whole file, while
+// rows with bad values are returned with their error so they show up in the report.
+func parseCSV(r io.Reader, m csvMapping) ([]*importRow, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = m.delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var (
+		header []string
+		cols   csvColumns
+		rows   []*importRow
+		err    error
+	)
+
+	if m.header {
+		if header, err = reader.Read(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
+			return nil, errs.InvalidImportFile
+		}
+	}
+	if cols, err = m.resolve(header); err != nil {
+		return nil, err
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errs.InvalidImportFile
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		if len(rows) == maxImportRows {
+			return nil, errs.TooManyImportRows
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := &importRow{line: line}
+		if err = m.parseRecord(record, cols, row); err != nil {
+			row.err = err.Error()
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (m csvMapping) parseRecord(record []string, cols csvColumns, row *importRow) error {
+	field := func(i int) (string, error) {
+		if i < 0 {
+			return "", nil
+		}
+		if i >= len(record) {
+			return "", fmt.Errorf("missing column %d", i+1)
+		}
+		return strings.TrimSpace(record[i]), nil
+	}
+
+	raw, err := field(cols.date)
+	if err != nil {
+		return err
+	}
+	if row.date, err = time.ParseInLocation(m.layout, raw, time.UTC); err != nil {
+		return fmt.Errorf("invalid date %q", raw)
+	}
+
+	if cols.amount >= 0 {
+		if raw, err = field(cols.amount); err != nil {
+			return err
+		}
+		if row.amount, err = parseDecimal(raw, m.decimal); err != nil {
+			return fmt.Errorf("invalid amount %q", raw)
+		}
+		row.typ = e_transaction_type.Deposit
+		if row.amount.IsNegative() {
+			row.typ = e_transaction_type.Withdrawal
+		}
+	} else if err = m.parseDebitCredit(field, cols, row); err != nil {
+		return err
+	}
+	row.amount = row.amount.Abs()
+
+	if cols.kind >= 0 {
+		if raw, err = field(cols.kind); err != nil {
+			return err
+		}
+		typ, ok := typeAliases[strings.ToLower(raw)]
+		if !ok {
+			return fmt.Errorf("unknown transaction type %q", raw)
+		}
+		row.typ = typ
+	}
+
+	if row.amount.IsZero() {
+		return errors.New("amount must not be zero")
+	}
+
+	if row.note, err = field(cols.note); err != nil {
+		return err
+	}
+	row.note = truncateNote(row.note)
+
+	return nil
+}
+
+// parseDebitCredit reads statements that keep outgoing and incoming amounts in separate columns.
+func (m csvMapping) parseDebitCredit(field func(int) (string, error), cols csvColumns, row *importRow) error {
+	var amounts [2]domain.Money
+	for i, col := range []int{cols.debit, cols.credit} {
+		raw, err := field(col)
+		if err != nil {
+			return err
+		}
+		if raw == "" {
+			continue
+		}
+		if amounts[i], err = parseDecimal(raw, m.decimal); err != nil {
+			return fmt.Errorf("invalid amount %q", raw)
+		}
+	}
+
+	debit, credit := amounts[0], amounts[1]
+	switch {
+	case !debit.IsZero() && !credit.IsZero():
+		return errors.New("both debit and credit are set")
+	case !debit.IsZero():
+		row.amount, row.typ = debit, e_transaction_type.Withdrawal
+	default:
+		row.amount, row.typ = credit, e_transaction_type.Deposit
+	}
+
+	return nil
+}
+
+// parseDecimal parses amounts as banks print them: "1,234.56", "1.234,56", "-12.00",
+// "12.00-" or "(12.00)". The separator that is not the decimal one is treated as grouping.
+func parseDecimal(raw string, decimal byte) (domain.Money, error) {
+	s := strings.TrimSpace(raw)
+	negative := false
+
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative, s = true, s[1:len(s)-1]
+	}
+	if strings.HasSuffix(s, "-") {
+		negative, s = !negative, strings.TrimSuffix(s, "-")
+	}
+
+	grouping := ","
+	if decimal == ',' {
+		grouping = "."
+	}
+	s = strings.NewReplacer(" ", "", "\u00a0", "", "'", "", grouping, "").Replace(s)
+	if decimal == ',' {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+
+	// ParseMoney only takes plain decimals, while statements also write "+3" and ".50".
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = strings.TrimPrefix(s[:1], "+"), s[1:]
+	}
+	if strings.HasPrefix(s, ".") {
+		s = "0" + s
+	}
+
+	m, err := domain.ParseMoney(sign + s)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if negative {
+		m = m.Neg()
+	}
+
+	return m, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseOFX reads OFX 1.x (SGML) and 2.x (XML) statements; QFX files are OFX with an Intuit
+// header. Only the STMTTRN entries are used, and leaf elements may or may not be closed.
+func parseOFX(r io.Reader) ([]*importRow, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxImportFileSize+1))
+	if err != nil {
+		return nil, errs.InvalidImportFile
+	}
+
+	content := string(data)
+	start := strings.Index(strings.ToUpper(content), "<OFX>")
+	if start < 0 {
+		return nil, errs.InvalidImportFile
+	}
+	line := 1 + strings.Count(content[:start], "\n")
+	content = content[start:]
+
+	var (
+		rows    []*importRow
+		current map[string]string
+		entry   int
+	)
+
+	for {
+		open := strings.IndexByte(content, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(content[open:], '>')
+		if end < 0 {
+			return nil, errs.InvalidImportFile
+		}
+
+		line += strings.Count(content[:open], "\n")
+		tag := strings.ToUpper(strings.TrimSpace(content[open+1 : open+end]))
+		content = content[open+end+1:]
+
+		next := strings.IndexByte(content, '<')
+		if next < 0 {
+			next = len(content)
+		}
+		value := strings.TrimSpace(html.UnescapeString(content[:next]))
+
+		switch {
+		case tag == "STMTTRN":
+			current, entry = make(map[string]string), line
+		case tag == "/STMTTRN" && current != nil:
+			if len(rows) == maxImportRows {
+				return nil, errs.TooManyImportRows
+			}
+			rows = append(rows, ofxRow(entry, current))
+			current = nil
+		case current != nil && !strings.HasPrefix(tag, "/") && value != "":
+			current[tag] = value
+		}
+	}
+
+	return rows, nil
+}
+
+// ofxRow maps one STMTTRN aggregate onto an import row. The sign of TRNAMT decides the
+// direction; TRNTYPE is not reliable enough across banks to be used for that.
+func ofxRow(line int, fields map[string]string) *importRow {
+	row := &importRow{line: line}
+	if fitID := fields["FITID"]; fitID != "" {
+		row.externalID = "fitid:" + fitID
+	}
+
+	var err error
+	if row.date, err = parseOFXDate(fields["DTPOSTED"]); err != nil {
+		row.err = fmt.Sprintf("invalid date %q", fields["DTPOSTED"])
+		return row
+	}
+
+	raw := fields["TRNAMT"]
+	if row.amount, err = parseDecimal(raw, '.'); err != nil {
+		if row.amount, err = parseDecimal(raw, ','); err != nil {
+			row.err = fmt.Sprintf("invalid amount %q", raw)
+			return row
+		}
+	}
+	if err = setDirection(row); err != nil {
+		row.err = err.Error()
+		return row
+	}
+
+	row.note = joinNote(fields["NAME"], fields["MEMO"])
+	return row
+}
+
+// parseOFXDate parses OFX datetimes such as 20250105, 20250105120000 or
+// 20250105120000.000[-5:EST] and returns them in UTC.
+func parseOFXDate(raw string) (time.Time, error) {
+	value, zone, _ := strings.Cut(raw, "[")
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value = value[:i]
+	}
+
+	var layout string
+	switch len(value) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, errors.New("unsupported OFX date")
+	}
+
+	t, err := time.ParseInLocation(layout, value, time.UTC)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if zone != "" {
+		offset, _, _ := strings.Cut(strings.TrimSuffix(zone, "]"), ":")
+		hours, err := strconv.ParseFloat(offset, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		t = t.Add(-time.Duration(hours * float64(time.Hour)))
+	}
+
+	return t, nil
+}
@@ -56,6 +56,21 @@ func (mr *MockTransactionMockRecorder) Create(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransaction)(nil).Create), ctx, req)
 }
 
+// CreateTransfer mocks base method.
+func (m *MockTransaction) CreateTransfer(ctx context.Context, req *transaction.CreateTransferRequest) (*transaction.CreateTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", ctx, req)
+	ret0, _ := ret[0].(*transaction.CreateTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockTransactionMockRecorder) CreateTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockTransaction)(nil).CreateTransfer), ctx, req)
+}
+
 // Delete mocks base method.
 func (m *MockTransaction) Delete(ctx context.Context, req *transaction.DeleteTransactionRequest) (*transaction.DeleteTransactionResponse, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +86,51 @@ func (mr *MockTransactionMockRecorder) Delete(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTransaction)(nil).Delete), ctx, req)
 }
 
+// DeleteTransfer mocks base method.
+func (m *MockTransaction) DeleteTransfer(ctx context.Context, req *transaction.DeleteTransferRequest) (*transaction.DeleteTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTransfer", ctx, req)
+	ret0, _ := ret[0].(*transaction.DeleteTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTransfer indicates an expected call of DeleteTransfer.
+func (mr *MockTransactionMockRecorder) DeleteTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransfer", reflect.TypeOf((*MockTransaction)(nil).DeleteTransfer), ctx, req)
+}
+
+// Export mocks base method.
+func (m *MockTransaction) Export(ctx context.Context, req *transaction.ExportTransactionsRequest) (*transaction.ExportTransactionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, req)
+	ret0, _ := ret[0].(*transaction.ExportTransactionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockTransactionMockRecorder) Export(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockTransaction)(nil).Export), ctx, req)
+}
+
+// Import mocks base method.
+func (m *MockTransaction) Import(ctx context.Context, req *transaction.ImportTransactionsRequest) (*transaction.ImportTransactionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, req)
+	ret0, _ := ret[0].(*transaction.ImportTransactionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockTransactionMockRecorder) Import(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockTransaction)(nil).Import), ctx, req)
+}
+
 // List mocks base method.
 func (m *MockTransaction) List(ctx context.Context, req *transaction.ListTransactionRequest) (*transaction.ListTransactionResponse, error) {
 	m.ctrl.T.Helper()
@@ -100,3 +160,18 @@ func (mr *MockTransactionMockRecorder) Update(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTransaction)(nil).Update), ctx, req)
 }
+
+// UpdateTransfer mocks base method.
+func (m *MockTransaction) UpdateTransfer(ctx context.Context, req *transaction.UpdateTransferRequest) (*transaction.UpdateTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransfer", ctx, req)
+	ret0, _ := ret[0].(*transaction.UpdateTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTransfer indicates an expected call of UpdateTransfer.
+func (mr *MockTransactionMockRecorder) UpdateTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransfer", reflect.TypeOf((*MockTransaction)(nil).UpdateTransfer), ctx, req)
+}
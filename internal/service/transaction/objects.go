@@ -1,53 +1,107 @@
 package transaction
 
 import (
+	"finly-backend/internal/domain"
 	"finly-backend/internal/domain/enums/e_transaction_type"
+	"io"
 	"time"
 )
 
 type TransactionObject struct {
 	ID         string                  `json:"id"`
 	UserID     string                  `json:"user_id"`
+	CreatedBy  string                  `json:"created_by,omitempty"`
 	CategoryID string                  `json:"category_id"`
 	BudgetID   string                  `json:"budget_id"`
-	Amount     float64                 `json:"amount"`
+	Amount     domain.Money            `json:"amount" swaggertype:"number"`
 	Type       e_transaction_type.Enum `json:"type"`
 	Note       string                  `json:"note"`
+	TransferID string                  `json:"transfer_id,omitempty"`
+	ExternalID string                  `json:"external_id,omitempty"`
+	Tags       []string                `json:"tags,omitempty"`
 	CreatedAt  time.Time               `json:"created_at"`
 }
 
+// CreateTransactionRequest may leave out the category for the user's transaction rules to pick.
 type CreateTransactionRequest struct {
 	UserID     string                  `header:"User-Id" validate:"required"`
-	CategoryID string                  `json:"category_id" validate:"required"`
+	CategoryID string                  `json:"category_id"`
 	BudgetID   string                  `json:"budget_id" validate:"required"`
-	Amount     float64                 `json:"amount" validate:"required"`
+	Amount     domain.Money            `json:"amount" validate:"required,gt=0" swaggertype:"number"`
 	Type       e_transaction_type.Enum `json:"type" validate:"required,oneof=deposit withdrawal"`
 	Note       string                  `json:"note"`
+	// At books the transaction at an earlier time instead of now. It is set by recurring
+	// runs for the occurrence being booked and cannot be sent by clients.
+	At time.Time `json:"-"`
+	// ExternalID is stored with a transaction booked at At. Create returns ErrAlreadyBooked
+	// when the budget already has a transaction with it, so a run is never booked twice.
+	ExternalID string `json:"-"`
 }
 
+// CreateTransactionResponse reports the category and tags the transaction was booked with and
+// lists the category limits a withdrawal went over, if any.
 type CreateTransactionResponse struct {
-	ID string `json:"id"`
+	ID         string         `json:"id"`
+	CategoryID string         `json:"category_id"`
+	Tags       []string       `json:"tags,omitempty"`
+	Warnings   []LimitWarning `json:"warnings,omitempty"`
+}
+
+// LimitWarning reports a category limit exceeded by a transaction. Spent includes the transaction.
+type LimitWarning struct {
+	LimitID    string       `json:"limit_id"`
+	CategoryID string       `json:"category_id"`
+	Period     string       `json:"period"`
+	Limit      domain.Money `json:"limit" swaggertype:"number"`
+	Spent      domain.Money `json:"spent" swaggertype:"number"`
+	OverBy     domain.Money `json:"over_by" swaggertype:"number"`
 }
 
+const (
+	defaultListLimit = 50
+	maxListLimit     = 100
+
+	SortCreatedAtDesc = "created_at_desc"
+	SortCreatedAtAsc  = "created_at_asc"
+	SortAmountDesc    = "amount_desc"
+	SortAmountAsc     = "amount_asc"
+)
+
 type ListTransactionRequest struct {
-	UserID string `header:"User-Id" validate:"required"`
+	UserID     string `header:"User-Id" validate:"required"`
+	From       string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	BudgetID   string `query:"budget_id" validate:"omitempty,uuid"`
+	CategoryID string `query:"category_id" validate:"omitempty,uuid"`
+	Type       string `query:"type" validate:"omitempty,oneof=deposit withdrawal transfer_in transfer_out"`
+	MinAmount  string `query:"min_amount" validate:"omitempty,numeric"`
+	MaxAmount  string `query:"max_amount" validate:"omitempty,numeric"`
+	Query      string `query:"q" validate:"omitempty,max=255"`
+	Sort       string `query:"sort" validate:"omitempty,oneof=created_at_desc created_at_asc amount_desc amount_asc"`
+	Cursor     string `query:"cursor"`
+	Limit      int    `query:"limit" validate:"omitempty,min=1,max=100"`
 }
 
 type ListTransactionResponse struct {
 	Transactions []TransactionObject `json:"transactions"`
+	NextCursor   string              `json:"next_cursor,omitempty"`
+	Total        int                 `json:"total"`
 }
 
 type UpdateTransactionRequest struct {
-	UserID        string  `header:"User-Id" validate:"required"`
-	TransactionID string  `param:"id" validate:"required"`
-	CategoryID    string  `json:"category_id,omitempty"`
-	BudgetID      int64   `json:"budget_id,omitempty"`
-	Amount        float64 `json:"amount,omitempty"`
-	Type          string  `json:"type,omitempty"`
-	Note          string  `json:"note,omitempty"`
+	UserID        string       `header:"User-Id" validate:"required"`
+	TransactionID string       `param:"id" validate:"required"`
+	CategoryID    string       `json:"category_id,omitempty"`
+	BudgetID      int64        `json:"budget_id,omitempty"`
+	Amount        domain.Money `json:"amount,omitempty" validate:"omitempty,gt=0" swaggertype:"number"`
+	Type          string       `json:"type,omitempty" validate:"omitempty,oneof=deposit withdrawal"`
+	Note          string       `json:"note,omitempty"`
 }
 
-type UpdateTransactionResponse struct{}
+// UpdateTransactionResponse lists the category limits an updated withdrawal went over, if any.
+type UpdateTransactionResponse struct {
+	Warnings []LimitWarning `json:"warnings,omitempty"`
+}
 
 type DeleteTransactionRequest struct {
 	UserID        string `header:"User-Id" validate:"required"`
@@ -55,3 +109,140 @@ type DeleteTransactionRequest struct {
 }
 
 type DeleteTransactionResponse struct{}
+
+type CreateTransferRequest struct {
+	UserID       string       `header:"User-Id" validate:"required"`
+	FromBudgetID string       `json:"from_budget_id" validate:"required,uuid"`
+	ToBudgetID   string       `json:"to_budget_id" validate:"required,uuid"`
+	CategoryID   string       `json:"category_id" validate:"omitempty,uuid"`
+	Amount       domain.Money `json:"amount" validate:"required,gt=0" swaggertype:"number"`
+	Note         string       `json:"note" validate:"max=255"`
+}
+
+type CreateTransferResponse struct {
+	TransferID string `json:"transfer_id"`
+	OutgoingID string `json:"outgoing_transaction_id"`
+	IncomingID string `json:"incoming_transaction_id"`
+}
+
+type UpdateTransferRequest struct {
+	UserID     string       `header:"User-Id" validate:"required"`
+	TransferID string       `param:"transfer_id" validate:"required"`
+	Amount     domain.Money `json:"amount,omitempty" validate:"omitempty,gt=0" swaggertype:"number"`
+	Note       string       `json:"note,omitempty" validate:"max=255"`
+}
+
+type UpdateTransferResponse struct{}
+
+type DeleteTransferRequest struct {
+	UserID     string `header:"User-Id" validate:"required"`
+	TransferID string `param:"transfer_id" validate:"required"`
+}
+
+type DeleteTransferResponse struct{}
+
+const (
+	MaxImportFileSize = 10 << 20
+	maxImportRows     = 10000
+
+	defaultImportDateFormat = "YYYY-MM-DD"
+
+	ImportFormatCSV = "csv"
+	ImportFormatOFX = "ofx"
+	ImportFormatQFX = "qfx"
+	ImportFormatQIF = "qif"
+)
+
+// ImportTransactionsRequest describes a bank statement upload in CSV, OFX/QFX or QIF format.
+// For CSV, columns are referenced by header name or by 1-based position; the amount comes
+// either from a single signed column or from separate debit and credit columns. Without a
+// category_id, the user's transaction rules categorize every row.
+type ImportTransactionsRequest struct {
+	UserID           string `header:"User-Id" validate:"required"`
+	BudgetID         string `form:"budget_id" validate:"required,uuid"`
+	CategoryID       string `form:"category_id" validate:"omitempty,uuid"`
+	Format           string `form:"format" validate:"omitempty,oneof=csv ofx qfx qif"`
+	DateColumn       string `form:"date_column"`
+	AmountColumn     string `form:"amount_column"`
+	DebitColumn      string `form:"debit_column"`
+	CreditColumn     string `form:"credit_column"`
+	TypeColumn       string `form:"type_column"`
+	NoteColumn       string `form:"note_column"`
+	DateFormat       string `form:"date_format" validate:"max=32"`
+	DecimalSeparator string `form:"decimal_separator" validate:"omitempty,len=1"`
+	Delimiter        string `form:"delimiter" validate:"omitempty,len=1"`
+	NoHeader         bool   `form:"no_header"`
+	DryRun           bool   `form:"dry_run"`
+
+	File io.Reader `json:"-" form:"-" validate:"required" swaggerignore:"true"`
+}
+
+type ImportRowResult struct {
+	Line          int                     `json:"line"`
+	Date          *time.Time              `json:"date,omitempty"`
+	Amount        domain.Money            `json:"amount" swaggertype:"number"`
+	Type          e_transaction_type.Enum `json:"type,omitempty"`
+	Note          string                  `json:"note,omitempty"`
+	Balance       *domain.Money           `json:"balance,omitempty" swaggertype:"number"`
+	ExternalID    string                  `json:"external_id,omitempty"`
+	CategoryID    string                  `json:"category_id,omitempty"`
+	Tags          []string                `json:"tags,omitempty"`
+	TransactionID string                  `json:"transaction_id,omitempty"`
+	Duplicate     bool                    `json:"duplicate,omitempty"`
+	Error         string                  `json:"error,omitempty"`
+}
+
+// ImportTransactionsResponse reports the outcome of every row. Nothing is imported
+// when any row fails or when the request is a dry run. Rows already imported into the
+// budget by an earlier statement are skipped as duplicates.
+type ImportTransactionsResponse struct {
+	DryRun   bool              `json:"dry_run"`
+	Total    int               `json:"total"`
+	Imported int               `json:"imported"`
+	Skipped  int               `json:"skipped"`
+	Failed   int               `json:"failed"`
+	Rows     []ImportRowResult `json:"rows"`
+}
+
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+	ExportFormatOFX   = "ofx"
+)
+
+// ExportContentTypes maps the export formats onto the media types they are served with.
+var ExportContentTypes = map[string]string{
+	ExportFormatCSV:   "text/csv; charset=utf-8",
+	ExportFormatJSONL: "application/x-ndjson",
+	ExportFormatOFX:   "application/x-ofx",
+}
+
+// ExportTransactionsRequest takes the same filters as ListTransactionRequest, without paging.
+// Rows are oldest first unless another sort is given. An OFX statement covers a single budget,
+// so budget_id is required for that format.
+type ExportTransactionsRequest struct {
+	UserID     string `header:"User-Id" validate:"required"`
+	Format     string `query:"format" validate:"omitempty,oneof=csv jsonl ofx"`
+	From       string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	BudgetID   string `query:"budget_id" validate:"omitempty,uuid"`
+	CategoryID string `query:"category_id" validate:"omitempty,uuid"`
+	Type       string `query:"type" validate:"omitempty,oneof=deposit withdrawal transfer_in transfer_out"`
+	MinAmount  string `query:"min_amount" validate:"omitempty,numeric"`
+	MaxAmount  string `query:"max_amount" validate:"omitempty,numeric"`
+	Query      string `query:"q" validate:"omitempty,max=255"`
+	Sort       string `query:"sort" validate:"omitempty,oneof=created_at_desc created_at_asc amount_desc amount_asc"`
+
+	Output io.Writer `json:"-" query:"-" validate:"required" swaggerignore:"true"`
+}
+
+// ExportTransactionObject is one exported transaction, as written to JSON Lines.
+type ExportTransactionObject struct {
+	TransactionObject
+	CategoryName string        `json:"category_name"`
+	Balance      *domain.Money `json:"balance,omitempty" swaggertype:"number"`
+}
+
+type ExportTransactionsResponse struct {
+	Exported int `json:"exported"`
+}
@@ -4,12 +4,21 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_budget_role"
 	"finly-backend/internal/domain/enums/e_transaction_type"
+	"finly-backend/internal/repository/budget"
 	"finly-backend/internal/repository/budget_history"
+	"finly-backend/internal/repository/category"
+	"finly-backend/internal/repository/category_limit"
 	"finly-backend/internal/repository/transaction"
+	"finly-backend/internal/repository/transaction_rule"
 	transactionExec "finly-backend/pkg/transaction"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
+	"io"
+	"slices"
 	"time"
 )
 
@@ -18,46 +27,123 @@ type Transaction interface {
 	List(ctx context.Context, req *ListTransactionRequest) (*ListTransactionResponse, error)
 	Update(ctx context.Context, req *UpdateTransactionRequest) (*UpdateTransactionResponse, error)
 	Delete(ctx context.Context, req *DeleteTransactionRequest) (*DeleteTransactionResponse, error)
+	CreateTransfer(ctx context.Context, req *CreateTransferRequest) (*CreateTransferResponse, error)
+	UpdateTransfer(ctx context.Context, req *UpdateTransferRequest) (*UpdateTransferResponse, error)
+	DeleteTransfer(ctx context.Context, req *DeleteTransferRequest) (*DeleteTransferResponse, error)
+	Import(ctx context.Context, req *ImportTransactionsRequest) (*ImportTransactionsResponse, error)
+	Export(ctx context.Context, req *ExportTransactionsRequest) (*ExportTransactionsResponse, error)
 }
 
 type Service struct {
 	transactionRepo   transaction.Transaction
+	budgetRepo        budget.Budget
 	budgetHistoryRepo budget_history.BudgetHistory
+	categoryRepo      category.Category
+	categoryLimitRepo category_limit.CategoryLimit
+	ruleRepo          transaction_rule.TransactionRule
 
 	transactionExecutor transactionExec.TransactionExecutor
 }
 
-func NewService(transactionRepo transaction.Transaction, budgetHistoryRepo budget_history.BudgetHistory, transactionExecutor transactionExec.TransactionExecutor) *Service {
+func NewService(transactionRepo transaction.Transaction, budgetRepo budget.Budget, budgetHistoryRepo budget_history.BudgetHistory, categoryRepo category.Category, categoryLimitRepo category_limit.CategoryLimit, ruleRepo transaction_rule.TransactionRule, transactionExecutor transactionExec.TransactionExecutor) *Service {
 	return &Service{
 		transactionRepo:     transactionRepo,
+		budgetRepo:          budgetRepo,
 		budgetHistoryRepo:   budgetHistoryRepo,
+		categoryRepo:        categoryRepo,
+		categoryLimitRepo:   categoryLimitRepo,
+		ruleRepo:            ruleRepo,
 		transactionExecutor: transactionExecutor,
 	}
 }
 
+// Create books a transaction into a budget the user can edit. It belongs to the owner of
+// the budget and records the user as the member who created it. Without a category, the
+// user's transaction rules pick the category and may tag the transaction. The category must
+// be a default category or a custom category of the budget owner.
 func (s *Service) Create(ctx context.Context, req *CreateTransactionRequest) (*CreateTransactionResponse, error) {
 	var (
 		transactionID string
-		err           error
+		warnings      []LimitWarning
+		tags          []string
 	)
 
+	budget, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Editor)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categories(ctx, budget.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryID := req.CategoryID
+	if categoryID != "" && !categories[categoryID] {
+		zap.L().Sugar().Warnf("categoryID=%s can't be used in budgetID=%s", categoryID, req.BudgetID)
+		return nil, errs.CategoryNotFound
+	}
+	if categoryID == "" {
+		rules, err := s.ruleSet(ctx, req.UserID, categories)
+		if err != nil {
+			return nil, err
+		}
+
+		categoryID, tags = rules.Match(&domain.Transaction{BudgetID: req.BudgetID, Amount: req.Amount, TransactionType: req.Type.String(), Note: req.Note})
+		if categoryID == "" {
+			return nil, errs.CategoryRequired
+		}
+	}
+
+	at := time.Now().UTC()
+	if !req.At.IsZero() {
+		if req.At.After(at) {
+			return nil, errs.FutureDate
+		}
+		at = req.At.UTC()
+	}
+
 	if err = s.transactionExecutor.WithTransaction(ctx, s.transactionRepo.GetDB(), func(tx *sqlx.Tx) error {
-		transactionID, err = s.transactionRepo.CreateTX(ctx, tx, req.UserID, req.BudgetID, req.CategoryID, req.Type.String(), req.Note, req.Amount)
+		if req.Type == e_transaction_type.Withdrawal {
+			if warnings, err = s.checkCategoryLimitsTX(ctx, tx, req.BudgetID, categoryID, req.Amount, at); err != nil {
+				return err
+			}
+		}
+
+		if !req.At.IsZero() {
+			transactionID, err = s.createAtTX(ctx, tx, &domain.Transaction{
+				UserID:          budget.UserID,
+				CreatedBy:       sql.NullString{String: req.UserID, Valid: true},
+				BudgetID:        req.BudgetID,
+				CategoryID:      categoryID,
+				Amount:          req.Amount,
+				TransactionType: req.Type.String(),
+				Note:            req.Note,
+				ExternalID:      sql.NullString{String: req.ExternalID, Valid: req.ExternalID != ""},
+				Tags:            tags,
+				CreatedAt:       at,
+			})
+			return err
+		}
+
+		transactionID, err = s.transactionRepo.CreateTX(ctx, tx, budget.UserID, req.UserID, req.BudgetID, categoryID, req.Type.String(), req.Note, req.Amount)
 		if err != nil {
 			zap.L().Sugar().Errorf("Failed to create transaction for userID=%s, budgetID=%s: %v", req.UserID, req.BudgetID, err)
 			return errs.DatabaseError
 		}
 
-		lastBudgetHistory, err := s.budgetHistoryRepo.GetLastByBudgetID(ctx, req.BudgetID)
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				lastBudgetHistory = nil
-			} else {
-				zap.L().Sugar().Errorf("Failed to get last budget history for budgetID=%s: %v", req.BudgetID, err)
+		if len(tags) > 0 {
+			if err = s.transactionRepo.CategorizeTX(ctx, tx, transactionID, budget.UserID, categoryID, tags); err != nil {
+				zap.L().Sugar().Errorf("Failed to tag transactionID=%s: %v", transactionID, err)
 				return errs.DatabaseError
 			}
 		}
 
+		lastBudgetHistory, err := s.lastHistoryTX(ctx, tx, req.BudgetID, at)
+		if err != nil {
+			return err
+		}
+
 		newAmount, err := calculateNewAmount(lastBudgetHistory, req.Amount, req.Type.String())
 		if err != nil {
 			zap.L().Sugar().Errorf("Failed to calculate new amount for budgetID=%s: %v", req.BudgetID, err)
@@ -76,48 +162,425 @@ func (s *Service) Create(ctx context.Context, req *CreateTransactionRequest) (*C
 		return nil, err
 	}
 
-	return &CreateTransactionResponse{ID: transactionID}, nil
+	return &CreateTransactionResponse{ID: transactionID, CategoryID: categoryID, Tags: tags, Warnings: warnings}, nil
+}
+
+// createAtTX books a transaction at t.CreatedAt, which may be in the past. The balance history
+// entry is inserted at that time and every later entry is shifted by the transaction.
+func (s *Service) createAtTX(ctx context.Context, tx *sqlx.Tx, t *domain.Transaction) (string, error) {
+	history, err := s.historySinceTX(ctx, tx, t.BudgetID, t.CreatedAt)
+	if err != nil {
+		return "", err
+	}
+
+	var previous *domain.BudgetHistory
+	later := history
+	if len(history) > 0 && !history[0].CreatedAt.After(t.CreatedAt) {
+		previous, later = history[0], history[1:]
+		// Keep booking times strictly increasing so the latest balance stays unambiguous.
+		if previous.CreatedAt.Equal(t.CreatedAt) {
+			t.CreatedAt = t.CreatedAt.Add(time.Microsecond)
+		}
+	}
+
+	balance, err := calculateNewAmount(previous, t.Amount, t.TransactionType)
+	if err != nil {
+		zap.L().Sugar().Warnf("Failed to calculate balance at %v for budgetID=%s: %v", t.CreatedAt, t.BudgetID, err)
+		return "", err
+	}
+	delta, err := calculateDelta(t.TransactionType, t.Amount)
+	if err != nil {
+		return "", err
+	}
+
+	for _, h := range later {
+		shifted, err := h.Balance.Add(delta)
+		if err != nil {
+			zap.L().Sugar().Warnf("Balance out of range for budgetID=%s at historyID=%s", t.BudgetID, h.ID)
+			return "", errs.AmountOutOfRange
+		}
+		if shifted.IsNegative() {
+			zap.L().Sugar().Warnf("Insufficient balance for budgetID=%s at historyID=%s", t.BudgetID, h.ID)
+			return "", errs.InsufficientBalance
+		}
+		if err = s.budgetHistoryRepo.UpdateBalanceByIDTX(ctx, tx, t.BudgetID, h.ID, shifted); err != nil {
+			zap.L().Sugar().Errorf("Failed to update budget historyID=%s: %v", h.ID, err)
+			return "", errs.DatabaseError
+		}
+	}
+
+	transactionID, err := s.transactionRepo.ImportTX(ctx, tx, t)
+	if err != nil {
+		if errors.Is(err, transaction.ErrDuplicateExternalID) {
+			zap.L().Sugar().Infof("Transaction with externalID=%s already booked in budgetID=%s", t.ExternalID.String, t.BudgetID)
+			return "", ErrAlreadyBooked
+		}
+		zap.L().Sugar().Errorf("Failed to create transaction at %v for budgetID=%s: %v", t.CreatedAt, t.BudgetID, err)
+		return "", errs.DatabaseError
+	}
+
+	if _, err = s.budgetHistoryRepo.CreateAtTX(ctx, tx, t.BudgetID, transactionID, balance, t.CreatedAt); err != nil {
+		zap.L().Sugar().Errorf("Failed to create budget history for transactionID=%s: %v", transactionID, err)
+		return "", errs.DatabaseError
+	}
+
+	return transactionID, nil
+}
+
+// historySinceTX returns the balance history of the budget from the entry in effect at the
+// given time on, locked and read past the cache. The budget is locked first, so concurrent
+// writers of its history wait for each other and each reads what the other wrote.
+func (s *Service) historySinceTX(ctx context.Context, tx *sqlx.Tx, budgetID string, at time.Time) ([]*domain.BudgetHistory, error) {
+	if err := s.budgetRepo.LockTX(ctx, tx, budgetID); err != nil {
+		return nil, errs.DatabaseError
+	}
+
+	history, err := s.budgetHistoryRepo.ListSinceTX(ctx, tx, budgetID, at)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to load budget history for budgetID=%s: %v", budgetID, err)
+		return nil, errs.DatabaseError
+	}
+	return history, nil
+}
+
+// lastHistoryTX returns the latest balance history entry of the budget, or nil when it has
+// none, locked like historySinceTX.
+func (s *Service) lastHistoryTX(ctx context.Context, tx *sqlx.Tx, budgetID string, at time.Time) (*domain.BudgetHistory, error) {
+	history, err := s.historySinceTX(ctx, tx, budgetID, at)
+	if err != nil || len(history) == 0 {
+		return nil, err
+	}
+	return history[len(history)-1], nil
+}
+
+// ruleSet loads the transaction rules of the user, limited to setting the given categories.
+func (s *Service) ruleSet(ctx context.Context, userID string, categories map[string]bool) (*domain.RuleSet, error) {
+	rules, err := s.ruleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to list transaction rules for userID=%s: %v", userID, err)
+		return nil, errs.DatabaseError
+	}
+	return domain.NewRuleSet(rules).OnlyCategories(categories), nil
+}
+
+// categories returns the IDs of the categories transactions of the owner's budgets can be
+// booked into: the default categories and the owner's custom ones. Custom categories of
+// other members are left out, so they never end up in a budget they don't own.
+func (s *Service) categories(ctx context.Context, ownerID string) (map[string]bool, error) {
+	categories, err := s.categoryRepo.List(ctx, ownerID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to list categories for userID=%s: %v", ownerID, err)
+		return nil, errs.DatabaseError
+	}
+
+	ids := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		ids[category.ID] = true
+	}
+	return ids, nil
+}
+
+// checkCategoryLimitsTX reports the limits of the category that a withdrawal of amount at the
+// given time would go over. Going over an enforced limit rejects the withdrawal instead; the
+// budget is locked first so concurrent withdrawals cannot both pass the check.
+func (s *Service) checkCategoryLimitsTX(ctx context.Context, tx *sqlx.Tx, budgetID, categoryID string, amount domain.Money, at time.Time) ([]LimitWarning, error) {
+	limits, err := s.categoryLimitRepo.ListByCategoryTX(ctx, tx, budgetID, categoryID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to get category limits for budgetID=%s, categoryID=%s: %v", budgetID, categoryID, err)
+		return nil, errs.DatabaseError
+	}
+
+	if slices.ContainsFunc(limits, func(limit *domain.CategoryLimit) bool { return limit.Enforce }) {
+		if err = s.budgetRepo.LockTX(ctx, tx, budgetID); err != nil {
+			zap.L().Sugar().Errorf("Failed to lock budgetID=%s for the category limit check: %v", budgetID, err)
+			return nil, errs.DatabaseError
+		}
+	}
+
+	var warnings []LimitWarning
+	for _, limit := range limits {
+		start, end, active := limit.Window(at)
+		if !active {
+			continue
+		}
+
+		spent, err := s.categoryLimitRepo.SpentTX(ctx, tx, budgetID, categoryID, start, end)
+		if err != nil {
+			zap.L().Sugar().Errorf("Failed to sum spending for limitID=%s: %v", limit.ID, err)
+			return nil, errs.DatabaseError
+		}
+
+		if spent, err = spent.Add(amount); err != nil {
+			return nil, errs.AmountOutOfRange
+		}
+		if !limit.Amount.LessThan(spent) {
+			continue
+		}
+		if limit.Enforce {
+			zap.L().Sugar().Warnf("Withdrawal rejected by limitID=%s: %s of %s spent", limit.ID, spent, limit.Amount)
+			return nil, errs.CategoryLimitExceeded
+		}
+
+		overBy, err := spent.Sub(limit.Amount)
+		if err != nil {
+			return nil, errs.AmountOutOfRange
+		}
+		warnings = append(warnings, LimitWarning{
+			LimitID:    limit.ID,
+			CategoryID: limit.CategoryID,
+			Period:     limit.Period,
+			Limit:      limit.Amount,
+			Spent:      spent,
+			OverBy:     overBy,
+		})
+	}
+
+	return warnings, nil
 }
 
 func (s *Service) List(ctx context.Context, req *ListTransactionRequest) (*ListTransactionResponse, error) {
-	transactions, err := s.transactionRepo.List(ctx, req.UserID)
+	filter, err := buildListFilter(req)
+	if err != nil {
+		zap.L().Sugar().Warnf("Invalid list filter for userID=%s: %v", req.UserID, err)
+		return nil, err
+	}
+	if _, err = s.scopeFilter(ctx, &filter); err != nil {
+		return nil, err
+	}
+
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	transactions, total, err := s.transactionRepo.List(ctx, filter)
 	if err != nil {
 		zap.L().Sugar().Errorf("Failed to list transactions for userID=%s: %v", req.UserID, err)
 		return nil, errs.DatabaseError
 	}
 
+	var nextCursor string
+	if len(transactions) > pageSize {
+		transactions = transactions[:pageSize]
+		nextCursor = encodeCursor(listSort(req.Sort), transactions[pageSize-1])
+	}
+
 	transactionList := make([]TransactionObject, 0, len(transactions))
 	for _, t := range transactions {
-		transactionList = append(transactionList, TransactionObject{
-			ID:         t.ID,
-			UserID:     t.UserID,
-			BudgetID:   t.BudgetID,
-			CategoryID: t.CategoryID,
-			Type:       e_transaction_type.Enum(t.TransactionType),
-			Note:       t.Note,
-			Amount:     t.Amount,
-			CreatedAt:  t.CreatedAt,
+		transactionList = append(transactionList, convertTransaction(t))
+	}
+
+	return &ListTransactionResponse{
+		Transactions: transactionList,
+		NextCursor:   nextCursor,
+		Total:        total,
+	}, nil
+}
+
+// Export writes the transactions matching the list filters to req.Output while they are
+// read from the database. Encoders buffer their output, so failures before the first rows
+// are flushed still reach the client as regular errors; later ones truncate the file.
+func (s *Service) Export(ctx context.Context, req *ExportTransactionsRequest) (*ExportTransactionsResponse, error) {
+	sort := req.Sort
+	if sort == "" {
+		sort = SortCreatedAtAsc
+	}
+
+	filter, err := buildListFilter(&ListTransactionRequest{
+		UserID:     req.UserID,
+		From:       req.From,
+		To:         req.To,
+		BudgetID:   req.BudgetID,
+		CategoryID: req.CategoryID,
+		Type:       req.Type,
+		MinAmount:  req.MinAmount,
+		MaxAmount:  req.MaxAmount,
+		Query:      req.Query,
+		Sort:       sort,
+	})
+	if err != nil {
+		zap.L().Sugar().Warnf("Export: invalid filter for userID=%s: %v", req.UserID, err)
+		return nil, err
+	}
+
+	format := req.Format
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	if format == ExportFormatOFX && filter.BudgetID == "" {
+		return nil, errs.ExportBudgetRequired
+	}
+
+	budget, err := s.scopeFilter(ctx, &filter)
+	if err != nil {
+		return nil, err
+	}
+	filter.Limit = 0
+
+	var out exporter
+	switch format {
+	case ExportFormatCSV:
+		out = newCSVExporter(req.Output)
+	case ExportFormatJSONL:
+		out = newJSONLExporter(req.Output)
+	case ExportFormatOFX:
+		if out, err = s.newOFXExporter(ctx, req.Output, budget, filter); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errs.InvalidInput
+	}
+
+	var (
+		exported int
+		writeErr error
+	)
+
+	if writeErr = out.begin(); writeErr == nil {
+		err = s.transactionRepo.Export(ctx, filter, func(row *domain.TransactionExport) error {
+			if writeErr = out.write(row); writeErr != nil {
+				return writeErr
+			}
+			exported++
+			return nil
 		})
+		if err == nil {
+			writeErr = out.end()
+		}
+	}
+
+	switch {
+	case writeErr != nil:
+		zap.L().Sugar().Warnf("Export: writing failed for userID=%s after %d rows: %v", req.UserID, exported, writeErr)
+		return nil, writeErr
+	case err != nil:
+		zap.L().Sugar().Errorf("Export: failed for userID=%s after %d rows: %v", req.UserID, exported, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("Export: %d transactions exported as %s for userID=%s", exported, format, req.UserID)
+	return &ExportTransactionsResponse{Exported: exported}, nil
+}
+
+// scopeFilter limits the filter to budgets the user can read. A filter by budget reads the
+// transactions of that budget's owner and returns the budget; otherwise the transactions of
+// every budget the user is a member of are read.
+func (s *Service) scopeFilter(ctx context.Context, filter *transaction.ListFilter) (*domain.Budget, error) {
+	if filter.BudgetID != "" {
+		budget, err := s.getBudget(ctx, filter.BudgetID, filter.UserID, e_budget_role.Viewer)
+		if err != nil {
+			return nil, err
+		}
+		filter.UserID = budget.UserID
+		return budget, nil
+	}
+
+	budgets, err := s.budgetRepo.ListByUserID(ctx, filter.UserID)
+	if err != nil {
+		zap.L().Sugar().Errorf("failed to list budgets for userID=%s: %v", filter.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	// Matching by user is enough unless a budget is shared with the user.
+	if slices.ContainsFunc(budgets, func(b *domain.Budget) bool { return b.UserID != filter.UserID }) {
+		filter.BudgetIDs = make([]string, 0, len(budgets))
+		for _, b := range budgets {
+			filter.BudgetIDs = append(filter.BudgetIDs, b.ID)
+		}
+	}
+	return nil, nil
+}
+
+// newOFXExporter prepares a statement of the budget the export is filtered by.
+func (s *Service) newOFXExporter(ctx context.Context, w io.Writer, budget *domain.Budget, filter transaction.ListFilter) (exporter, error) {
+	current, err := s.budgetHistoryRepo.GetCurrentBalance(ctx, budget.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		zap.L().Sugar().Errorf("Export: failed to get balance of budgetID=%s: %v", budget.ID, err)
+		return nil, errs.DatabaseError
 	}
 
-	return &ListTransactionResponse{Transactions: transactionList}, nil
+	from, to := budget.CreatedAt, time.Now().UTC()
+	if filter.From != nil {
+		from = *filter.From
+	}
+	if filter.To != nil {
+		to = *filter.To
+	}
+
+	return newOFXExporter(w, budget, from, to, current), nil
 }
 
 func (s *Service) Update(ctx context.Context, req *UpdateTransactionRequest) (*UpdateTransactionResponse, error) {
+	var (
+		warnings []LimitWarning
+		changed  []*domain.Transaction
+	)
 	if err := s.transactionExecutor.WithTransaction(ctx, s.transactionRepo.GetDB(), func(tx *sqlx.Tx) error {
-		if err := s.transactionRepo.UpdateTX(ctx, tx, req.TransactionID, req.UserID, req.CategoryID, req.Type, req.Note, req.Amount); err != nil {
-			zap.L().Sugar().Errorf("Failed to update transactionID=%s for userID=%s: %v", req.TransactionID, req.UserID, err)
-			return errs.DatabaseError
+		transaction, err := s.getTransaction(ctx, req.TransactionID, req.UserID, e_budget_role.Editor)
+		if err != nil {
+			return err
 		}
 
-		transaction, err := s.transactionRepo.GetByID(ctx, req.TransactionID, req.UserID)
-		if err != nil {
-			zap.L().Sugar().Errorf("Failed to get transaction by ID for transactionID=%s, userID=%s: %v", req.TransactionID, req.UserID, err)
+		// A transfer leg is edited together with its counterpart so both budgets stay in sync.
+		if transaction.TransferID.Valid {
+			changed, err = s.updateTransferTX(ctx, tx, req.UserID, transaction.TransferID.String, req.Amount, req.Note)
+			return err
+		}
+
+		if transaction, err = s.lockTransactionTX(ctx, tx, transaction); err != nil {
+			return err
+		}
+
+		// Fields left out of the request keep their current value, as on transfer legs.
+		categoryID, transactionType, note, amount := transaction.CategoryID, transaction.TransactionType, transaction.Note, transaction.Amount
+		if req.CategoryID != "" {
+			categoryID = req.CategoryID
+		}
+		if req.Type != "" {
+			transactionType = req.Type
+		}
+		if req.Note != "" {
+			note = req.Note
+		}
+		if !req.Amount.IsZero() {
+			amount = req.Amount
+		}
+
+		if categoryID != transaction.CategoryID {
+			categories, err := s.categories(ctx, transaction.UserID)
+			if err != nil {
+				return err
+			}
+			if !categories[categoryID] {
+				zap.L().Sugar().Warnf("categoryID=%s can't be used in budgetID=%s", categoryID, transaction.BudgetID)
+				return errs.CategoryNotFound
+			}
+		}
+
+		if transactionType == e_transaction_type.Withdrawal.String() {
+			// The spending of the category still includes the transaction as it was.
+			added := amount
+			if transaction.TransactionType == transactionType && transaction.CategoryID == categoryID {
+				if added, err = added.Sub(transaction.Amount); err != nil {
+					return errs.AmountOutOfRange
+				}
+			}
+			if added.IsPositive() {
+				if warnings, err = s.checkCategoryLimitsTX(ctx, tx, transaction.BudgetID, categoryID, added, transaction.CreatedAt); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err = s.transactionRepo.UpdateTX(ctx, tx, req.TransactionID, transaction.UserID, categoryID, transactionType, note, amount); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.TransactionNotFound
+			}
+			zap.L().Sugar().Errorf("Failed to update transactionID=%s for userID=%s: %v", req.TransactionID, req.UserID, err)
 			return errs.DatabaseError
 		}
+		changed = []*domain.Transaction{transaction}
 
-		if transaction.TransactionType != req.Type || transaction.Amount != req.Amount {
-			difference, err := calculateDeltaChange(transaction.TransactionType, transaction.Amount, req.Type, req.Amount)
+		if transaction.TransactionType != transactionType || !transaction.Amount.Equal(amount) {
+			difference, err := calculateDeltaChange(transaction.TransactionType, transaction.Amount, transactionType, amount)
 			if err != nil {
 				zap.L().Sugar().Errorf("Failed to calculate delta change for transactionID=%s: %v", req.TransactionID, err)
 				return errs.InvalidTransactionType
@@ -134,17 +597,27 @@ func (s *Service) Update(ctx context.Context, req *UpdateTransactionRequest) (*U
 		zap.L().Sugar().Errorf("TransactionObject update failed for transactionID=%s, userID=%s: %v", req.TransactionID, req.UserID, err)
 		return nil, err
 	}
+	s.invalidate(ctx, changed)
 
 	zap.L().Sugar().Infof("Successfully updated transactionID=%s for userID=%s", req.TransactionID, req.UserID)
-	return &UpdateTransactionResponse{}, nil
+	return &UpdateTransactionResponse{Warnings: warnings}, nil
 }
 
 func (s *Service) Delete(ctx context.Context, req *DeleteTransactionRequest) (*DeleteTransactionResponse, error) {
+	var deleted []*domain.Transaction
 	if err := s.transactionExecutor.WithTransaction(ctx, s.transactionRepo.GetDB(), func(tx *sqlx.Tx) error {
-		transaction, err := s.transactionRepo.GetByID(ctx, req.TransactionID, req.UserID)
+		transaction, err := s.getTransaction(ctx, req.TransactionID, req.UserID, e_budget_role.Editor)
 		if err != nil {
-			zap.L().Sugar().Errorf("Failed to get transaction by ID for transactionID=%s, userID=%s: %v", req.TransactionID, req.UserID, err)
-			return errs.DatabaseError
+			return err
+		}
+
+		if transaction.TransferID.Valid {
+			deleted, err = s.deleteTransferTX(ctx, tx, req.UserID, transaction.TransferID.String)
+			return err
+		}
+
+		if transaction, err = s.lockTransactionTX(ctx, tx, transaction); err != nil {
+			return err
 		}
 
 		difference, err := invertDelta(transaction.TransactionType, transaction.Amount, false)
@@ -158,36 +631,50 @@ func (s *Service) Delete(ctx context.Context, req *DeleteTransactionRequest) (*D
 			return err
 		}
 
-		if err = s.transactionRepo.DeleteTX(ctx, tx, req.TransactionID, req.UserID); err != nil {
+		if err = s.transactionRepo.DeleteTX(ctx, tx, req.TransactionID, transaction.UserID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.TransactionNotFound
+			}
 			zap.L().Sugar().Errorf("Failed to delete transactionID=%s for userID=%s: %v", req.TransactionID, req.UserID, err)
 			return errs.DatabaseError
 		}
+		deleted = []*domain.Transaction{transaction}
 
 		return nil
 	}); err != nil {
 		zap.L().Sugar().Errorf("TransactionObject deletion failed for transactionID=%s, userID=%s: %v", req.TransactionID, req.UserID, err)
 		return nil, err
 	}
+	s.invalidate(ctx, deleted)
 
 	zap.L().Sugar().Infof("Successfully deleted transactionID=%s for userID=%s", req.TransactionID, req.UserID)
 	return &DeleteTransactionResponse{}, nil
 }
 
-func (s *Service) updateBudgetHistory(ctx context.Context, tx *sqlx.Tx, budgetID string, fromDate time.Time, difference float64, inclusiveDate bool) error {
-	budgetHistory, err := s.budgetHistoryRepo.ListFromDate(ctx, budgetID, fromDate, inclusiveDate)
+// updateBudgetHistory shifts every balance history entry from fromDate on by difference. The
+// entry at fromDate itself is shifted only if inclusiveDate is set.
+func (s *Service) updateBudgetHistory(ctx context.Context, tx *sqlx.Tx, budgetID string, fromDate time.Time, difference domain.Money, inclusiveDate bool) error {
+	budgetHistory, err := s.historySinceTX(ctx, tx, budgetID, fromDate)
 	if err != nil {
-		zap.L().Sugar().Errorf("Failed to list budget history for budgetID=%s: %v", budgetID, err)
-		return errs.DatabaseError
+		return err
 	}
 
 	for _, history := range budgetHistory {
-		newAmount := history.Balance + difference
-		if newAmount < 0 {
-			zap.L().Sugar().Errorf("Insufficient balance for budgetID=%s, transactionID=%s", budgetID, history.TransactionID.String)
+		if history.CreatedAt.Before(fromDate) || (!inclusiveDate && history.CreatedAt.Equal(fromDate)) {
+			continue
+		}
+
+		newAmount, err := history.Balance.Add(difference)
+		if err != nil {
+			zap.L().Sugar().Warnf("Balance out of range for budgetID=%s, historyID=%s", budgetID, history.ID)
+			return errs.AmountOutOfRange
+		}
+		if newAmount.IsNegative() {
+			zap.L().Sugar().Errorf("Insufficient balance for budgetID=%s, historyID=%s", budgetID, history.ID)
 			return errs.InsufficientBalance
 		}
-		if err = s.budgetHistoryRepo.UpdateBalanceTX(ctx, tx, history.TransactionID.String, newAmount); err != nil {
-			zap.L().Sugar().Errorf("Failed to update balance for transactionID=%s: %v", history.TransactionID.String, err)
+		if err = s.budgetHistoryRepo.UpdateBalanceByIDTX(ctx, tx, budgetID, history.ID, newAmount); err != nil {
+			zap.L().Sugar().Errorf("Failed to update balance for historyID=%s: %v", history.ID, err)
 			return errs.DatabaseError
 		}
 	}
@@ -195,3 +682,466 @@ func (s *Service) updateBudgetHistory(ctx context.Context, tx *sqlx.Tx, budgetID
 	zap.L().Sugar().Infof("Successfully updated budget history for budgetID=%s", budgetID)
 	return nil
 }
+
+// CreateTransfer moves money between two budgets the user can edit. Each leg belongs to the
+// owner of its budget. Both legs and their budget history rows are written in a single
+// database transaction.
+func (s *Service) CreateTransfer(ctx context.Context, req *CreateTransferRequest) (*CreateTransferResponse, error) {
+	if req.FromBudgetID == req.ToBudgetID {
+		return nil, errs.SameBudgetTransfer
+	}
+
+	from, err := s.getBudget(ctx, req.FromBudgetID, req.UserID, e_budget_role.Editor)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.getBudget(ctx, req.ToBudgetID, req.UserID, e_budget_role.Editor)
+	if err != nil {
+		return nil, err
+	}
+
+	if from.Currency != to.Currency {
+		zap.L().Sugar().Warnf("Transfer currency mismatch for userID=%s: %s -> %s", req.UserID, from.Currency, to.Currency)
+		return nil, errs.CurrencyMismatch
+	}
+
+	categoryID := req.CategoryID
+	if categoryID == "" {
+		categoryID = domain.TransferCategoryID
+	} else {
+		// Each leg is booked for the owner of its budget, so both owners must be able to use the category.
+		for _, ownerID := range slices.Compact([]string{from.UserID, to.UserID}) {
+			categories, err := s.categories(ctx, ownerID)
+			if err != nil {
+				return nil, err
+			}
+			if !categories[categoryID] {
+				zap.L().Sugar().Warnf("categoryID=%s can't be used by userID=%s in a transfer", categoryID, ownerID)
+				return nil, errs.CategoryNotFound
+			}
+		}
+	}
+
+	res := &CreateTransferResponse{TransferID: uuid.NewString()}
+	if err = s.transactionExecutor.WithTransaction(ctx, s.transactionRepo.GetDB(), func(tx *sqlx.Tx) error {
+		if err := s.lockBudgetsTX(ctx, tx, from.ID, to.ID); err != nil {
+			return err
+		}
+
+		var err error
+		res.OutgoingID, err = s.createTransferLegTX(ctx, tx, res.TransferID, from.UserID, req.UserID, from.ID, categoryID, e_transaction_type.TransferOut, req.Note, req.Amount)
+		if err != nil {
+			return err
+		}
+
+		res.IncomingID, err = s.createTransferLegTX(ctx, tx, res.TransferID, to.UserID, req.UserID, to.ID, categoryID, e_transaction_type.TransferIn, req.Note, req.Amount)
+		return err
+	}); err != nil {
+		zap.L().Sugar().Errorf("Transfer creation failed for userID=%s, from=%s, to=%s: %v", req.UserID, req.FromBudgetID, req.ToBudgetID, err)
+		return nil, err
+	}
+
+	zap.L().Sugar().Infof("Transfer %s created for userID=%s", res.TransferID, req.UserID)
+	return res, nil
+}
+
+func (s *Service) UpdateTransfer(ctx context.Context, req *UpdateTransferRequest) (*UpdateTransferResponse, error) {
+	var legs []*domain.Transaction
+	if err := s.transactionExecutor.WithTransaction(ctx, s.transactionRepo.GetDB(), func(tx *sqlx.Tx) error {
+		var err error
+		legs, err = s.updateTransferTX(ctx, tx, req.UserID, req.TransferID, req.Amount, req.Note)
+		return err
+	}); err != nil {
+		zap.L().Sugar().Errorf("Transfer update failed for transferID=%s, userID=%s: %v", req.TransferID, req.UserID, err)
+		return nil, err
+	}
+	s.invalidate(ctx, legs)
+
+	zap.L().Sugar().Infof("Successfully updated transferID=%s for userID=%s", req.TransferID, req.UserID)
+	return &UpdateTransferResponse{}, nil
+}
+
+func (s *Service) DeleteTransfer(ctx context.Context, req *DeleteTransferRequest) (*DeleteTransferResponse, error) {
+	var legs []*domain.Transaction
+	if err := s.transactionExecutor.WithTransaction(ctx, s.transactionRepo.GetDB(), func(tx *sqlx.Tx) error {
+		var err error
+		legs, err = s.deleteTransferTX(ctx, tx, req.UserID, req.TransferID)
+		return err
+	}); err != nil {
+		zap.L().Sugar().Errorf("Transfer deletion failed for transferID=%s, userID=%s: %v", req.TransferID, req.UserID, err)
+		return nil, err
+	}
+	s.invalidate(ctx, legs)
+
+	zap.L().Sugar().Infof("Successfully deleted transferID=%s for userID=%s", req.TransferID, req.UserID)
+	return &DeleteTransferResponse{}, nil
+}
+
+func (s *Service) createTransferLegTX(ctx context.Context, tx *sqlx.Tx, transferID, ownerID, userID, budgetID, categoryID string, transactionType e_transaction_type.Enum, note string, amount domain.Money) (string, error) {
+	transactionID, err := s.transactionRepo.CreateTransferLegTX(ctx, tx, transferID, ownerID, userID, budgetID, categoryID, transactionType.String(), note, amount)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to create %s leg for transferID=%s: %v", transactionType, transferID, err)
+		return "", errs.DatabaseError
+	}
+
+	lastBudgetHistory, err := s.lastHistoryTX(ctx, tx, budgetID, time.Now().UTC())
+	if err != nil {
+		return "", err
+	}
+
+	newAmount, err := calculateNewAmount(lastBudgetHistory, amount, transactionType.String())
+	if err != nil {
+		zap.L().Sugar().Warnf("Failed to calculate new amount for budgetID=%s, transferID=%s: %v", budgetID, transferID, err)
+		return "", err
+	}
+
+	if _, err = s.budgetHistoryRepo.CreateTX(ctx, tx, budgetID, transactionID, newAmount); err != nil {
+		zap.L().Sugar().Errorf("Failed to create budget history for transactionID=%s, budgetID=%s: %v", transactionID, budgetID, err)
+		return "", errs.DatabaseError
+	}
+
+	return transactionID, nil
+}
+
+// updateTransferTX applies a new amount and/or note to both legs of a transfer and returns
+// the legs, whose cache is invalidated once tx is committed. Zero amount or empty note keep
+// the current values.
+func (s *Service) updateTransferTX(ctx context.Context, tx *sqlx.Tx, userID, transferID string, amount domain.Money, note string) ([]*domain.Transaction, error) {
+	legs, err := s.getTransferLegsTX(ctx, tx, userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, leg := range legs {
+		newAmount, newNote := leg.Amount, leg.Note
+		if !amount.IsZero() {
+			newAmount = amount
+		}
+		if note != "" {
+			newNote = note
+		}
+
+		if err = s.transactionRepo.UpdateTX(ctx, tx, leg.ID, leg.UserID, leg.CategoryID, leg.TransactionType, newNote, newAmount); err != nil {
+			zap.L().Sugar().Errorf("Failed to update transfer leg transactionID=%s: %v", leg.ID, err)
+			return nil, errs.DatabaseError
+		}
+
+		if leg.Amount.Equal(newAmount) {
+			continue
+		}
+
+		difference, err := calculateDeltaChange(leg.TransactionType, leg.Amount, leg.TransactionType, newAmount)
+		if err != nil {
+			zap.L().Sugar().Errorf("Failed to calculate delta change for transactionID=%s: %v", leg.ID, err)
+			return nil, errs.InvalidTransactionType
+		}
+
+		if err = s.updateBudgetHistory(ctx, tx, leg.BudgetID, leg.CreatedAt, difference, true); err != nil {
+			zap.L().Sugar().Errorf("Failed to update budget history for transactionID=%s: %v", leg.ID, err)
+			return nil, err
+		}
+	}
+
+	return legs, nil
+}
+
+// deleteTransferTX removes both legs of a transfer and rolls their effect out of the later
+// balances. Like updateTransferTX, it returns the legs.
+func (s *Service) deleteTransferTX(ctx context.Context, tx *sqlx.Tx, userID, transferID string) ([]*domain.Transaction, error) {
+	legs, err := s.getTransferLegsTX(ctx, tx, userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, leg := range legs {
+		difference, err := invertDelta(leg.TransactionType, leg.Amount, false)
+		if err != nil {
+			zap.L().Sugar().Errorf("Failed to invert delta for transactionID=%s: %v", leg.ID, err)
+			return nil, errs.InvalidTransactionType
+		}
+
+		if err = s.updateBudgetHistory(ctx, tx, leg.BudgetID, leg.CreatedAt, difference, false); err != nil {
+			zap.L().Sugar().Errorf("Failed to update budget history for transactionID=%s: %v", leg.ID, err)
+			return nil, err
+		}
+
+		if err = s.transactionRepo.DeleteTX(ctx, tx, leg.ID, leg.UserID); err != nil {
+			zap.L().Sugar().Errorf("Failed to delete transfer leg transactionID=%s: %v", leg.ID, err)
+			return nil, errs.DatabaseError
+		}
+	}
+
+	return legs, nil
+}
+
+// getTransferLegsTX loads both legs of a transfer if the user can edit the budgets of both.
+func (s *Service) getTransferLegsTX(ctx context.Context, tx *sqlx.Tx, userID, transferID string) ([]*domain.Transaction, error) {
+	legs, err := s.transactionRepo.ListByTransferIDTX(ctx, tx, transferID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to load transferID=%s for userID=%s: %v", transferID, userID, err)
+		return nil, errs.DatabaseError
+	}
+
+	if len(legs) != 2 {
+		zap.L().Sugar().Warnf("Transfer not found for transferID=%s, userID=%s (legs=%d)", transferID, userID, len(legs))
+		return nil, errs.TransferNotFound
+	}
+
+	for _, leg := range legs {
+		if _, err = s.getBudget(ctx, leg.BudgetID, userID, e_budget_role.Editor); err != nil {
+			if errors.Is(err, errs.BudgetNotFound) {
+				return nil, errs.TransferNotFound
+			}
+			return nil, err
+		}
+	}
+
+	if err = s.lockBudgetsTX(ctx, tx, legs[0].BudgetID, legs[1].BudgetID); err != nil {
+		return nil, err
+	}
+
+	return legs, nil
+}
+
+// lockBudgetsTX locks the budgets of a transfer in a fixed order, before the history of
+// either is read, so two transfers between the same budgets can't deadlock.
+func (s *Service) lockBudgetsTX(ctx context.Context, tx *sqlx.Tx, budgetIDs ...string) error {
+	budgetIDs = slices.Clone(budgetIDs)
+	slices.Sort(budgetIDs)
+	for _, budgetID := range budgetIDs {
+		if err := s.budgetRepo.LockTX(ctx, tx, budgetID); err != nil {
+			return errs.DatabaseError
+		}
+	}
+	return nil
+}
+
+// Import books the rows of a bank statement into a budget at their statement dates.
+// The whole file is applied in one database transaction: if any row fails, or the request
+// is a dry run, nothing is written and the report shows what would have happened.
+func (s *Service) Import(ctx context.Context, req *ImportTransactionsRequest) (*ImportTransactionsResponse, error) {
+	budget, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Editor)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categories(ctx, budget.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if req.CategoryID != "" && !categories[req.CategoryID] {
+		zap.L().Sugar().Warnf("categoryID=%s can't be used in budgetID=%s", req.CategoryID, req.BudgetID)
+		return nil, errs.CategoryNotFound
+	}
+
+	var rows []*importRow
+
+	switch req.Format {
+	case ImportFormatOFX, ImportFormatQFX:
+		rows, err = parseOFX(req.File)
+	case ImportFormatQIF:
+		rows, err = parseQIF(req.File, req.DateFormat, req.DecimalSeparator)
+	default:
+		var mapping csvMapping
+		if mapping, err = newCSVMapping(req); err == nil {
+			rows, err = parseCSV(req.File, mapping)
+		}
+	}
+	if err != nil {
+		zap.L().Sugar().Warnf("Failed to parse %s import file for userID=%s: %v", req.Format, req.UserID, err)
+		return nil, err
+	}
+
+	assignExternalIDs(rows)
+
+	var rules *domain.RuleSet
+	if req.CategoryID == "" {
+		if rules, err = s.ruleSet(ctx, req.UserID, categories); err != nil {
+			return nil, err
+		}
+	}
+	categorizeRows(rows, req.BudgetID, req.CategoryID, rules)
+
+	return s.importRows(ctx, req.UserID, budget.UserID, req.BudgetID, req.DryRun, rows)
+}
+
+func (s *Service) importRows(ctx context.Context, userID, ownerID, budgetID string, dryRun bool, rows []*importRow) (*ImportTransactionsResponse, error) {
+	var ledger []*ledgerEntry
+
+	if err := s.transactionExecutor.WithTransaction(ctx, s.transactionRepo.GetDB(), func(tx *sqlx.Tx) error {
+		if ids := externalIDs(rows); len(ids) > 0 {
+			existing, err := s.transactionRepo.ListExternalIDsTX(ctx, tx, budgetID, ids)
+			if err != nil {
+				zap.L().Sugar().Errorf("Failed to check for already imported rows in budgetID=%s: %v", budgetID, err)
+				return errs.DatabaseError
+			}
+
+			imported := make(map[string]bool, len(existing))
+			for _, id := range existing {
+				imported[id] = true
+			}
+			for _, row := range rows {
+				if row.bookable() && imported[row.externalID] {
+					row.duplicate = true
+				}
+			}
+		}
+
+		var earliest time.Time
+		for _, row := range rows {
+			if row.bookable() && (earliest.IsZero() || row.date.Before(earliest)) {
+				earliest = row.date
+			}
+		}
+		if earliest.IsZero() {
+			return nil
+		}
+
+		history, err := s.historySinceTX(ctx, tx, budgetID, earliest)
+		if err != nil {
+			return err
+		}
+
+		ledger = planImport(history, rows, time.Now().UTC())
+		if dryRun || slices.ContainsFunc(rows, func(row *importRow) bool { return row.err != "" }) {
+			return nil
+		}
+
+		for _, entry := range ledger {
+			switch {
+			case entry.row != nil:
+				entry.row.transactionID, err = s.transactionRepo.ImportTX(ctx, tx, &domain.Transaction{
+					UserID:          ownerID,
+					CreatedBy:       sql.NullString{String: userID, Valid: true},
+					BudgetID:        budgetID,
+					CategoryID:      entry.row.categoryID,
+					Amount:          entry.row.amount,
+					TransactionType: entry.row.typ.String(),
+					Note:            entry.row.note,
+					ExternalID:      sql.NullString{String: entry.row.externalID, Valid: true},
+					Tags:            entry.row.tags,
+					CreatedAt:       entry.at,
+				})
+				if err != nil {
+					zap.L().Sugar().Errorf("Failed to import line %d for budgetID=%s: %v", entry.row.line, budgetID, err)
+					return errs.DatabaseError
+				}
+
+				if _, err = s.budgetHistoryRepo.CreateAtTX(ctx, tx, budgetID, entry.row.transactionID, entry.balance, entry.at); err != nil {
+					zap.L().Sugar().Errorf("Failed to create budget history for transactionID=%s: %v", entry.row.transactionID, err)
+					return errs.DatabaseError
+				}
+			case entry.dirty:
+				if err = s.budgetHistoryRepo.UpdateBalanceByIDTX(ctx, tx, budgetID, entry.history.ID, entry.balance); err != nil {
+					zap.L().Sugar().Errorf("Failed to update budget historyID=%s: %v", entry.history.ID, err)
+					return errs.DatabaseError
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		zap.L().Sugar().Errorf("Import failed for userID=%s, budgetID=%s: %v", userID, budgetID, err)
+		return nil, err
+	}
+
+	res := &ImportTransactionsResponse{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   importResults(rows, ledger),
+	}
+	for _, row := range rows {
+		switch {
+		case row.err != "":
+			res.Failed++
+		case row.duplicate:
+			res.Skipped++
+		case row.transactionID != "":
+			res.Imported++
+		}
+	}
+
+	zap.L().Sugar().Infof("Import for userID=%s, budgetID=%s: total=%d, imported=%d, skipped=%d, failed=%d, dryRun=%t",
+		userID, budgetID, res.Total, res.Imported, res.Skipped, res.Failed, dryRun)
+	return res, nil
+}
+
+// getBudget loads the budget if the user is a member whose role allows what requires role.
+// A budget the user isn't a member of is indistinguishable from a missing one.
+func (s *Service) getBudget(ctx context.Context, budgetID, userID string, role e_budget_role.Enum) (*domain.Budget, error) {
+	budget, err := s.budgetRepo.GetByID(ctx, budgetID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			zap.L().Sugar().Warnf("budget not found for budgetID=%s, userID=%s", budgetID, userID)
+			return nil, errs.BudgetNotFound
+		}
+		zap.L().Sugar().Errorf("failed to get budgetID=%s for userID=%s: %v", budgetID, userID, err)
+		return nil, errs.DatabaseError
+	}
+
+	if !e_budget_role.Enum(budget.Role).Allows(role) {
+		zap.L().Sugar().Warnf("role %s of userID=%s in budgetID=%s does not allow %s access", budget.Role, userID, budgetID, role)
+		return nil, errs.BudgetForbidden
+	}
+	// An archived budget is read-only until it is unarchived.
+	if role != e_budget_role.Viewer && budget.ArchivedAt.Valid {
+		zap.L().Sugar().Warnf("budgetID=%s is archived, userID=%s cannot change it", budgetID, userID)
+		return nil, errs.BudgetArchived
+	}
+
+	return budget, nil
+}
+
+// lockTransactionTX locks the budget of a transaction that was read from the cache and
+// reads the transaction again past the cache, so its amount and type are the ones the
+// budget history was shifted by. It returns errs.TransactionNotFound when the transaction
+// was deleted in the meantime.
+func (s *Service) lockTransactionTX(ctx context.Context, tx *sqlx.Tx, cached *domain.Transaction) (*domain.Transaction, error) {
+	if err := s.budgetRepo.LockTX(ctx, tx, cached.BudgetID); err != nil {
+		return nil, errs.DatabaseError
+	}
+
+	transaction, err := s.transactionRepo.GetByIDTX(ctx, tx, cached.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			zap.L().Sugar().Warnf("transactionID=%s was deleted before it was locked", cached.ID)
+			return nil, errs.TransactionNotFound
+		}
+		zap.L().Sugar().Errorf("Failed to lock transactionID=%s: %v", cached.ID, err)
+		return nil, errs.DatabaseError
+	}
+	return transaction, nil
+}
+
+// invalidate drops the cached copies of transactions changed by a committed database
+// transaction. It runs after the commit, so a read in between can't cache the old rows again.
+func (s *Service) invalidate(ctx context.Context, transactions []*domain.Transaction) {
+	for _, transaction := range transactions {
+		if err := s.transactionRepo.InvalidateCache(ctx, transaction.UserID, transaction.ID); err != nil {
+			zap.L().Sugar().Warnf("Failed to invalidate cache of transactionID=%s: %v", transaction.ID, err)
+		}
+	}
+}
+
+// getTransaction loads the transaction if the user's role in its budget allows what
+// requires role.
+func (s *Service) getTransaction(ctx context.Context, transactionID, userID string, role e_budget_role.Enum) (*domain.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.TransactionNotFound
+		}
+		zap.L().Sugar().Errorf("Failed to get transaction by ID for transactionID=%s, userID=%s: %v", transactionID, userID, err)
+		return nil, errs.DatabaseError
+	}
+
+	if _, err = s.getBudget(ctx, transaction.BudgetID, userID, role); err != nil {
+		if errors.Is(err, errs.BudgetNotFound) {
+			return nil, errs.TransactionNotFound
+		}
+		return nil, err
+	}
+
+	return transaction, nil
+}
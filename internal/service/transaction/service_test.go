@@ -6,12 +6,19 @@ import (
 	"errors"
 	"finly-backend/internal/domain"
 	"finly-backend/internal/domain/enums/e_transaction_type"
+	mock_budget "finly-backend/internal/repository/budget/mock"
 	"finly-backend/internal/repository/budget_history/mock"
+	mock_category "finly-backend/internal/repository/category/mock"
+	mock_category_limit "finly-backend/internal/repository/category_limit/mock"
+	"finly-backend/internal/repository/transaction"
 	mock_transaction "finly-backend/internal/repository/transaction/mock"
+	mock_transaction_rule "finly-backend/internal/repository/transaction_rule/mock"
 	transactionExec "finly-backend/pkg/transaction"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -30,9 +37,15 @@ func TestCreate(t *testing.T) {
 
 	ctx := context.Background()
 	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
 	mockBudgetHistoryRepo := mock.NewMockBudgetHistory(ctrl)
+	mockCategoryRepo := mock_category.NewMockCategory(ctrl)
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
+	mockRuleRepo := mock_transaction_rule.NewMockTransactionRule(ctrl)
 	mockDB := &sqlx.DB{}
 	mockTx := &sqlx.Tx{}
+	ownedBudget := &domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}
+	categories := []*domain.Category{{ID: "cat123"}, {ID: "transport"}, {ID: "misc"}}
 
 	tests := []struct {
 		name        string
@@ -49,18 +62,21 @@ func TestCreate(t *testing.T) {
 				CategoryID: "cat123",
 				Type:       e_transaction_type.Deposit,
 				Note:       "Test deposit",
-				Amount:     100.00,
+				Amount:     domain.MustParseMoney("100.00"),
 			},
 			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "budget123", "cat123", "deposit", "Test deposit", 100.00).
+				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "user123", "budget123", "cat123", "deposit", "Test deposit", domain.MustParseMoney("100.00")).
 					Return("trans123", nil)
-				mockBudgetHistoryRepo.EXPECT().GetLastByBudgetID(ctx, "budget123").
-					Return(&domain.BudgetHistory{Balance: 50.00}, nil)
-				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "budget123", "trans123", 150.00).
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", gomock.Any()).
+					Return([]*domain.BudgetHistory{{Balance: domain.MustParseMoney("50.00")}}, nil)
+				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "budget123", "trans123", domain.MustParseMoney("150.00")).
 					Return("history123", nil)
 			},
-			expectedRes: &CreateTransactionResponse{ID: "trans123"},
+			expectedRes: &CreateTransactionResponse{ID: "trans123", CategoryID: "cat123"},
 			expectedErr: nil,
 		},
 		{
@@ -71,20 +87,202 @@ func TestCreate(t *testing.T) {
 				CategoryID: "cat123",
 				Type:       e_transaction_type.Withdrawal,
 				Note:       "Test withdrawal",
-				Amount:     50.00,
+				Amount:     domain.MustParseMoney("50.00"),
 			},
 			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "budget123", "cat123", "withdrawal", "Test withdrawal", 50.00).
+				mockCategoryLimitRepo.EXPECT().ListByCategoryTX(ctx, mockTx, "budget123", "cat123").Return(nil, nil)
+				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "user123", "budget123", "cat123", "withdrawal", "Test withdrawal", domain.MustParseMoney("50.00")).
 					Return("trans123", nil)
-				mockBudgetHistoryRepo.EXPECT().GetLastByBudgetID(ctx, "budget123").
-					Return(&domain.BudgetHistory{Balance: 100.00}, nil)
-				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "budget123", "trans123", 50.00).
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", gomock.Any()).
+					Return([]*domain.BudgetHistory{{Balance: domain.MustParseMoney("100.00")}}, nil)
+				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "budget123", "trans123", domain.MustParseMoney("50.00")).
 					Return("history123", nil)
 			},
-			expectedRes: &CreateTransactionResponse{ID: "trans123"},
+			expectedRes: &CreateTransactionResponse{ID: "trans123", CategoryID: "cat123"},
 			expectedErr: nil,
 		},
+		{
+			name: "Back-dated withdrawal shifts later balances",
+			req: &CreateTransactionRequest{
+				UserID:     "user123",
+				BudgetID:   "budget123",
+				CategoryID: "cat123",
+				Type:       e_transaction_type.Withdrawal,
+				Note:       "Rent",
+				Amount:     domain.MustParseMoney("30.00"),
+				At:         time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+			},
+			mockSetup: func() {
+				at := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockCategoryLimitRepo.EXPECT().ListByCategoryTX(ctx, mockTx, "budget123", "cat123").Return(nil, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", at).Return([]*domain.BudgetHistory{
+					{ID: "h1", Balance: domain.MustParseMoney("100.00"), CreatedAt: at.Add(-time.Hour)},
+					{ID: "h2", Balance: domain.MustParseMoney("120.00"), CreatedAt: at.Add(time.Hour)},
+				}, nil)
+				mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "budget123", "h2", domain.MustParseMoney("90.00")).Return(nil)
+				mockTransactionRepo.EXPECT().ImportTX(ctx, mockTx, &domain.Transaction{
+					UserID:          "user123",
+					CreatedBy:       sql.NullString{String: "user123", Valid: true},
+					BudgetID:        "budget123",
+					CategoryID:      "cat123",
+					Amount:          domain.MustParseMoney("30.00"),
+					TransactionType: "withdrawal",
+					Note:            "Rent",
+					CreatedAt:       at,
+				}).Return("trans123", nil)
+				mockBudgetHistoryRepo.EXPECT().CreateAtTX(ctx, mockTx, "budget123", "trans123", domain.MustParseMoney("70.00"), at).
+					Return("history123", nil)
+			},
+			expectedRes: &CreateTransactionResponse{ID: "trans123", CategoryID: "cat123"},
+		},
+		{
+			name: "Future-dated transaction is rejected",
+			req: &CreateTransactionRequest{
+				UserID:     "user123",
+				BudgetID:   "budget123",
+				CategoryID: "cat123",
+				Type:       e_transaction_type.Deposit,
+				Amount:     domain.MustParseMoney("30.00"),
+				At:         time.Now().Add(24 * time.Hour),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.FutureDate,
+		},
+		{
+			name: "Recurring run already booked",
+			req: &CreateTransactionRequest{
+				UserID:     "user123",
+				BudgetID:   "budget123",
+				CategoryID: "cat123",
+				Type:       e_transaction_type.Deposit,
+				Amount:     domain.MustParseMoney("30.00"),
+				At:         time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+				ExternalID: "recurring:rule1:2025-03-01T09:00:00Z",
+			},
+			mockSetup: func() {
+				at := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", at).Return(nil, nil)
+				mockTransactionRepo.EXPECT().ImportTX(ctx, mockTx, &domain.Transaction{
+					UserID:          "user123",
+					CreatedBy:       sql.NullString{String: "user123", Valid: true},
+					BudgetID:        "budget123",
+					CategoryID:      "cat123",
+					Amount:          domain.MustParseMoney("30.00"),
+					TransactionType: "deposit",
+					ExternalID:      sql.NullString{String: "recurring:rule1:2025-03-01T09:00:00Z", Valid: true},
+					CreatedAt:       at,
+				}).Return("", transaction.ErrDuplicateExternalID)
+			},
+			expectedErr: ErrAlreadyBooked,
+		},
+		{
+			name: "Back-dated withdrawal leaving a later balance negative",
+			req: &CreateTransactionRequest{
+				UserID:     "user123",
+				BudgetID:   "budget123",
+				CategoryID: "cat123",
+				Type:       e_transaction_type.Withdrawal,
+				Amount:     domain.MustParseMoney("30.00"),
+				At:         time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+			},
+			mockSetup: func() {
+				at := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockCategoryLimitRepo.EXPECT().ListByCategoryTX(ctx, mockTx, "budget123", "cat123").Return(nil, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", at).Return([]*domain.BudgetHistory{
+					{ID: "h1", Balance: domain.MustParseMoney("100.00"), CreatedAt: at.Add(-time.Hour)},
+					{ID: "h2", Balance: domain.MustParseMoney("10.00"), CreatedAt: at.Add(time.Hour)},
+				}, nil)
+			},
+			expectedErr: errs.InsufficientBalance,
+		},
+		{
+			name: "Withdrawal over a category limit warns",
+			req: &CreateTransactionRequest{
+				UserID:     "user123",
+				BudgetID:   "budget123",
+				CategoryID: "cat123",
+				Type:       e_transaction_type.Withdrawal,
+				Note:       "Dinner",
+				Amount:     domain.MustParseMoney("50.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockCategoryLimitRepo.EXPECT().ListByCategoryTX(ctx, mockTx, "budget123", "cat123").Return([]*domain.CategoryLimit{
+					{ID: "limit1", CategoryID: "cat123", Amount: domain.MustParseMoney("400.00"), Period: "month"},
+					{ID: "limit2", CategoryID: "cat123", Amount: domain.MustParseMoney("200.00"), Period: "week"},
+					{
+						ID: "limit3", CategoryID: "cat123", Amount: domain.MustParseMoney("10.00"), Period: "custom", Enforce: true,
+						StartsAt: sql.NullTime{Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+						EndsAt:   sql.NullTime{Time: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					},
+				}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockCategoryLimitRepo.EXPECT().SpentTX(ctx, mockTx, "budget123", "cat123", gomock.Any(), gomock.Any()).
+					Return(domain.MustParseMoney("370.00"), nil)
+				mockCategoryLimitRepo.EXPECT().SpentTX(ctx, mockTx, "budget123", "cat123", gomock.Any(), gomock.Any()).
+					Return(domain.MustParseMoney("150.00"), nil)
+				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "user123", "budget123", "cat123", "withdrawal", "Dinner", domain.MustParseMoney("50.00")).
+					Return("trans123", nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", gomock.Any()).
+					Return([]*domain.BudgetHistory{{Balance: domain.MustParseMoney("100.00")}}, nil)
+				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "budget123", "trans123", domain.MustParseMoney("50.00")).
+					Return("history123", nil)
+			},
+			expectedRes: &CreateTransactionResponse{ID: "trans123", CategoryID: "cat123", Warnings: []LimitWarning{{
+				LimitID:    "limit1",
+				CategoryID: "cat123",
+				Period:     "month",
+				Limit:      domain.MustParseMoney("400.00"),
+				Spent:      domain.MustParseMoney("420.00"),
+				OverBy:     domain.MustParseMoney("20.00"),
+			}}},
+			expectedErr: nil,
+		},
+		{
+			name: "Withdrawal over an enforced category limit is rejected",
+			req: &CreateTransactionRequest{
+				UserID:     "user123",
+				BudgetID:   "budget123",
+				CategoryID: "cat123",
+				Type:       e_transaction_type.Withdrawal,
+				Amount:     domain.MustParseMoney("50.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockCategoryLimitRepo.EXPECT().ListByCategoryTX(ctx, mockTx, "budget123", "cat123").Return([]*domain.CategoryLimit{
+					{ID: "limit1", CategoryID: "cat123", Amount: domain.MustParseMoney("400.00"), Period: "month", Enforce: true},
+				}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockCategoryLimitRepo.EXPECT().SpentTX(ctx, mockTx, "budget123", "cat123", gomock.Any(), gomock.Any()).
+					Return(domain.MustParseMoney("370.00"), nil)
+			},
+			expectedRes: nil,
+			expectedErr: errs.CategoryLimitExceeded,
+		},
 		{
 			name: "No previous budget history",
 			req: &CreateTransactionRequest{
@@ -93,18 +291,21 @@ func TestCreate(t *testing.T) {
 				CategoryID: "cat123",
 				Type:       e_transaction_type.Deposit,
 				Note:       "Test deposit",
-				Amount:     100.00,
+				Amount:     domain.MustParseMoney("100.00"),
 			},
 			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "budget123", "cat123", "deposit", "Test deposit", 100.00).
+				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "user123", "budget123", "cat123", "deposit", "Test deposit", domain.MustParseMoney("100.00")).
 					Return("trans123", nil)
-				mockBudgetHistoryRepo.EXPECT().GetLastByBudgetID(ctx, "budget123").
-					Return(nil, sql.ErrNoRows)
-				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "budget123", "trans123", 100.00).
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", gomock.Any()).
+					Return(nil, nil)
+				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "budget123", "trans123", domain.MustParseMoney("100.00")).
 					Return("history123", nil)
 			},
-			expectedRes: &CreateTransactionResponse{ID: "trans123"},
+			expectedRes: &CreateTransactionResponse{ID: "trans123", CategoryID: "cat123"},
 			expectedErr: nil,
 		},
 		{
@@ -114,30 +315,35 @@ func TestCreate(t *testing.T) {
 				BudgetID:   "budget123",
 				CategoryID: "cat123",
 				Type:       e_transaction_type.Deposit,
-				Amount:     100.00,
+				Amount:     domain.MustParseMoney("100.00"),
 			},
 			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "budget123", "cat123", "deposit", "", 100.00).
+				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "user123", "budget123", "cat123", "deposit", "", domain.MustParseMoney("100.00")).
 					Return("", errors.New("create error"))
 			},
 			expectedRes: nil,
 			expectedErr: errs.DatabaseError,
 		},
 		{
-			name: "GetLastByBudgetID error",
+			name: "ListSinceTX error",
 			req: &CreateTransactionRequest{
 				UserID:     "user123",
 				BudgetID:   "budget123",
 				CategoryID: "cat123",
 				Type:       e_transaction_type.Deposit,
-				Amount:     100.00,
+				Amount:     domain.MustParseMoney("100.00"),
 			},
 			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "budget123", "cat123", "deposit", "", 100.00).
+				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "user123", "budget123", "cat123", "deposit", "", domain.MustParseMoney("100.00")).
 					Return("trans123", nil)
-				mockBudgetHistoryRepo.EXPECT().GetLastByBudgetID(ctx, "budget123").
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", gomock.Any()).
 					Return(nil, errors.New("db error"))
 			},
 			expectedRes: nil,
@@ -150,20 +356,186 @@ func TestCreate(t *testing.T) {
 				BudgetID:   "budget123",
 				CategoryID: "cat123",
 				Type:       e_transaction_type.Deposit,
-				Amount:     100.00,
+				Amount:     domain.MustParseMoney("100.00"),
 			},
 			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "budget123", "cat123", "deposit", "", 100.00).
+				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "user123", "budget123", "cat123", "deposit", "", domain.MustParseMoney("100.00")).
 					Return("trans123", nil)
-				mockBudgetHistoryRepo.EXPECT().GetLastByBudgetID(ctx, "budget123").
-					Return(&domain.BudgetHistory{Balance: 50.00}, nil)
-				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "budget123", "trans123", 150.00).
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", gomock.Any()).
+					Return([]*domain.BudgetHistory{{Balance: domain.MustParseMoney("50.00")}}, nil)
+				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "budget123", "trans123", domain.MustParseMoney("150.00")).
 					Return("", errors.New("history error"))
 			},
 			expectedRes: nil,
 			expectedErr: errs.DatabaseError,
 		},
+		{
+			name: "Editor of a shared budget books for the owner",
+			req: &CreateTransactionRequest{
+				UserID:     "user456",
+				BudgetID:   "budget123",
+				CategoryID: "cat123",
+				Type:       e_transaction_type.Deposit,
+				Amount:     domain.MustParseMoney("100.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "editor"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "user456", "budget123", "cat123", "deposit", "", domain.MustParseMoney("100.00")).
+					Return("trans123", nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", gomock.Any()).
+					Return(nil, nil)
+				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "budget123", "trans123", domain.MustParseMoney("100.00")).
+					Return("history123", nil)
+			},
+			expectedRes: &CreateTransactionResponse{ID: "trans123", CategoryID: "cat123"},
+		},
+		{
+			name: "Viewer cannot create",
+			req: &CreateTransactionRequest{
+				UserID:     "user456",
+				BudgetID:   "budget123",
+				CategoryID: "cat123",
+				Type:       e_transaction_type.Deposit,
+				Amount:     domain.MustParseMoney("100.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "viewer"}, nil)
+			},
+			expectedErr: errs.BudgetForbidden,
+		},
+		{
+			name: "Archived budget",
+			req: &CreateTransactionRequest{
+				UserID:     "user123",
+				BudgetID:   "budget123",
+				CategoryID: "cat123",
+				Type:       e_transaction_type.Deposit,
+				Amount:     domain.MustParseMoney("100.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner", ArchivedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+			},
+			expectedErr: errs.BudgetArchived,
+		},
+		{
+			name: "Budget of another user",
+			req: &CreateTransactionRequest{
+				UserID:     "user456",
+				BudgetID:   "budget123",
+				CategoryID: "cat123",
+				Type:       e_transaction_type.Deposit,
+				Amount:     domain.MustParseMoney("100.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.BudgetNotFound,
+		},
+		{
+			name: "Rules pick the category and tags",
+			req: &CreateTransactionRequest{
+				UserID:   "user123",
+				BudgetID: "budget123",
+				Type:     e_transaction_type.Withdrawal,
+				Note:     "UBER *TRIP",
+				Amount:   domain.MustParseMoney("12.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user123").Return([]*domain.TransactionRule{
+					{ID: "rule1", NoteContains: sql.NullString{String: "uber", Valid: true}, CategoryID: sql.NullString{String: "transport", Valid: true}, Tag: sql.NullString{String: "rides", Valid: true}},
+				}, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockCategoryLimitRepo.EXPECT().ListByCategoryTX(ctx, mockTx, "budget123", "transport").Return(nil, nil)
+				mockTransactionRepo.EXPECT().CreateTX(ctx, mockTx, "user123", "user123", "budget123", "transport", "withdrawal", "UBER *TRIP", domain.MustParseMoney("12.00")).
+					Return("trans123", nil)
+				mockTransactionRepo.EXPECT().CategorizeTX(ctx, mockTx, "trans123", "user123", "transport", []string{"rides"}).Return(nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", gomock.Any()).
+					Return([]*domain.BudgetHistory{{Balance: domain.MustParseMoney("100.00")}}, nil)
+				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "budget123", "trans123", domain.MustParseMoney("88.00")).
+					Return("history123", nil)
+			},
+			expectedRes: &CreateTransactionResponse{ID: "trans123", CategoryID: "transport", Tags: []string{"rides"}},
+		},
+		{
+			name: "Custom category of another user",
+			req: &CreateTransactionRequest{
+				UserID:     "user123",
+				BudgetID:   "budget123",
+				CategoryID: "cat456",
+				Type:       e_transaction_type.Withdrawal,
+				Amount:     domain.MustParseMoney("12.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Rule category the owner can't use is skipped",
+			req: &CreateTransactionRequest{
+				UserID:   "user456",
+				BudgetID: "budget123",
+				Type:     e_transaction_type.Withdrawal,
+				Note:     "UBER *TRIP",
+				Amount:   domain.MustParseMoney("12.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "editor"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user456").Return([]*domain.TransactionRule{
+					{ID: "rule1", NoteContains: sql.NullString{String: "uber", Valid: true}, CategoryID: sql.NullString{String: "cat456", Valid: true}},
+				}, nil)
+			},
+			expectedErr: errs.CategoryRequired,
+		},
+		{
+			name: "No rule sets a category",
+			req: &CreateTransactionRequest{
+				UserID:   "user123",
+				BudgetID: "budget123",
+				Type:     e_transaction_type.Deposit,
+				Note:     "Cash",
+				Amount:   domain.MustParseMoney("20.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user123").Return([]*domain.TransactionRule{
+					{ID: "rule1", TransactionType: sql.NullString{String: "withdrawal", Valid: true}, CategoryID: sql.NullString{String: "misc", Valid: true}},
+				}, nil)
+			},
+			expectedErr: errs.CategoryRequired,
+		},
+		{
+			name: "Rules cannot be loaded",
+			req: &CreateTransactionRequest{
+				UserID:   "user123",
+				BudgetID: "budget123",
+				Type:     e_transaction_type.Deposit,
+				Amount:   domain.MustParseMoney("20.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user123").Return(nil, errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
 	}
 
 	for _, tt := range tests {
@@ -176,7 +548,7 @@ func TestCreate(t *testing.T) {
 				},
 			}
 
-			service := NewService(mockTransactionRepo, mockBudgetHistoryRepo, mockTxExec)
+			service := NewService(mockTransactionRepo, mockBudgetRepo, mockBudgetHistoryRepo, mockCategoryRepo, mockCategoryLimitRepo, mockRuleRepo, mockTxExec)
 
 			resp, err := service.Create(ctx, tt.req)
 
@@ -198,10 +570,13 @@ func TestList(t *testing.T) {
 
 	ctx := context.Background()
 	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
 	mockBudgetHistoryRepo := mock.NewMockBudgetHistory(ctrl)
-	service := NewService(mockTransactionRepo, mockBudgetHistoryRepo, transactionExec.NewTransactionExecutor())
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
+	service := NewService(mockTransactionRepo, mockBudgetRepo, mockBudgetHistoryRepo, nil, mockCategoryLimitRepo, nil, transactionExec.NewTransactionExecutor())
 
 	createdAt := time.Now()
+	owned := []*domain.Budget{{ID: "budget123", UserID: "user123", Role: "owner"}}
 
 	tests := []struct {
 		name        string
@@ -216,7 +591,13 @@ func TestList(t *testing.T) {
 				UserID: "user123",
 			},
 			mockSetup: func() {
-				mockTransactionRepo.EXPECT().List(ctx, "user123").Return([]*domain.Transaction{
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").Return(owned, nil)
+				mockTransactionRepo.EXPECT().List(ctx, transaction.ListFilter{
+					UserID:   "user123",
+					SortBy:   transaction.SortByCreatedAt,
+					SortDesc: true,
+					Limit:    defaultListLimit + 1,
+				}).Return([]*domain.Transaction{
 					{
 						ID:              "trans1",
 						UserID:          "user123",
@@ -224,7 +605,7 @@ func TestList(t *testing.T) {
 						CategoryID:      "cat123",
 						TransactionType: "deposit",
 						Note:            "Test deposit",
-						Amount:          100.00,
+						Amount:          domain.MustParseMoney("100.00"),
 						CreatedAt:       createdAt,
 					},
 					{
@@ -234,10 +615,10 @@ func TestList(t *testing.T) {
 						CategoryID:      "cat123",
 						TransactionType: "withdrawal",
 						Note:            "Test withdrawal",
-						Amount:          50.00,
+						Amount:          domain.MustParseMoney("50.00"),
 						CreatedAt:       createdAt.Add(time.Hour),
 					},
-				}, nil)
+				}, 2, nil)
 			},
 			expectedRes: &ListTransactionResponse{
 				Transactions: []TransactionObject{
@@ -248,7 +629,7 @@ func TestList(t *testing.T) {
 						CategoryID: "cat123",
 						Type:       e_transaction_type.Deposit,
 						Note:       "Test deposit",
-						Amount:     100.00,
+						Amount:     domain.MustParseMoney("100.00"),
 						CreatedAt:  createdAt,
 					},
 					{
@@ -258,20 +639,132 @@ func TestList(t *testing.T) {
 						CategoryID: "cat123",
 						Type:       e_transaction_type.Withdrawal,
 						Note:       "Test withdrawal",
-						Amount:     50.00,
+						Amount:     domain.MustParseMoney("50.00"),
 						CreatedAt:  createdAt.Add(time.Hour),
 					},
 				},
+				Total: 2,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Filtered page with next cursor",
+			req: &ListTransactionRequest{
+				UserID:    "user123",
+				Type:      "withdrawal",
+				MinAmount: "10",
+				Sort:      SortAmountAsc,
+				Limit:     1,
+			},
+			mockSetup: func() {
+				minAmount := domain.MustParseMoney("10.00")
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").Return(owned, nil)
+				mockTransactionRepo.EXPECT().List(ctx, transaction.ListFilter{
+					UserID:    "user123",
+					Type:      "withdrawal",
+					MinAmount: &minAmount,
+					SortBy:    transaction.SortByAmount,
+					Limit:     2,
+				}).Return([]*domain.Transaction{
+					{ID: "trans1", UserID: "user123", TransactionType: "withdrawal", Amount: domain.MustParseMoney("10.00"), CreatedAt: createdAt},
+					{ID: "trans2", UserID: "user123", TransactionType: "withdrawal", Amount: domain.MustParseMoney("20.00"), CreatedAt: createdAt},
+				}, 5, nil)
+			},
+			expectedRes: &ListTransactionResponse{
+				Transactions: []TransactionObject{
+					{ID: "trans1", UserID: "user123", Type: e_transaction_type.Withdrawal, Amount: domain.MustParseMoney("10.00"), CreatedAt: createdAt},
+				},
+				NextCursor: encodeCursor(SortAmountAsc, &domain.Transaction{ID: "trans1", Amount: domain.MustParseMoney("10.00"), CreatedAt: createdAt}),
+				Total:      5,
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "Shared budgets are listed with the own ones",
+			req: &ListTransactionRequest{
+				UserID: "user456",
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user456").Return([]*domain.Budget{
+					{ID: "budget456", UserID: "user456", Role: "owner"},
+					{ID: "budget123", UserID: "user123", Role: "viewer"},
+				}, nil)
+				mockTransactionRepo.EXPECT().List(ctx, transaction.ListFilter{
+					UserID:    "user456",
+					BudgetIDs: []string{"budget456", "budget123"},
+					SortBy:    transaction.SortByCreatedAt,
+					SortDesc:  true,
+					Limit:     defaultListLimit + 1,
+				}).Return([]*domain.Transaction{
+					{ID: "trans1", UserID: "user123", CreatedBy: sql.NullString{String: "user456", Valid: true}, BudgetID: "budget123", TransactionType: "deposit", CreatedAt: createdAt},
+				}, 1, nil)
+			},
+			expectedRes: &ListTransactionResponse{
+				Transactions: []TransactionObject{
+					{ID: "trans1", UserID: "user123", CreatedBy: "user456", BudgetID: "budget123", Type: e_transaction_type.Deposit, CreatedAt: createdAt},
+				},
+				Total: 1,
+			},
+		},
+		{
+			name: "Budget filter reads the owner's transactions",
+			req: &ListTransactionRequest{
+				UserID:   "user456",
+				BudgetID: "budget123",
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "viewer"}, nil)
+				mockTransactionRepo.EXPECT().List(ctx, transaction.ListFilter{
+					UserID:   "user123",
+					BudgetID: "budget123",
+					SortBy:   transaction.SortByCreatedAt,
+					SortDesc: true,
+					Limit:    defaultListLimit + 1,
+				}).Return(nil, 0, nil)
+			},
+			expectedRes: &ListTransactionResponse{Transactions: []TransactionObject{}},
+		},
+		{
+			name: "Budget filter of a budget the user is not a member of",
+			req: &ListTransactionRequest{
+				UserID:   "user456",
+				BudgetID: "budget789",
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget789", "user456").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.BudgetNotFound,
+		},
+		{
+			name: "Cursor from another sort order",
+			req: &ListTransactionRequest{
+				UserID: "user123",
+				Cursor: encodeCursor(SortAmountDesc, &domain.Transaction{ID: "trans1"}),
+			},
+			mockSetup:   func() {},
+			expectedRes: nil,
+			expectedErr: errs.InvalidCursor,
+		},
+		{
+			name: "Inverted date range",
+			req: &ListTransactionRequest{
+				UserID: "user123",
+				From:   "2025-02-01T00:00:00Z",
+				To:     "2025-01-01T00:00:00Z",
+			},
+			mockSetup:   func() {},
+			expectedRes: nil,
+			expectedErr: errs.InvalidInput,
+		},
 		{
 			name: "List error",
 			req: &ListTransactionRequest{
 				UserID: "user123",
 			},
 			mockSetup: func() {
-				mockTransactionRepo.EXPECT().List(ctx, "user123").Return(nil, errors.New("db error"))
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").Return(owned, nil)
+				mockTransactionRepo.EXPECT().List(ctx, gomock.Any()).Return(nil, 0, errors.New("db error"))
 			},
 			expectedRes: nil,
 			expectedErr: errs.DatabaseError,
@@ -302,9 +795,14 @@ func TestUpdate(t *testing.T) {
 
 	ctx := context.Background()
 	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
 	mockBudgetHistoryRepo := mock.NewMockBudgetHistory(ctrl)
+	mockCategoryRepo := mock_category.NewMockCategory(ctrl)
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
 	mockDB := &sqlx.DB{}
 	mockTx := &sqlx.Tx{}
+	createdAt := time.Now()
+	ownedBudget := &domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}
 
 	tests := []struct {
 		name        string
@@ -321,27 +819,75 @@ func TestUpdate(t *testing.T) {
 				CategoryID:    "cat123",
 				Type:          "withdrawal",
 				Note:          "Updated note",
-				Amount:        50.00,
+				Amount:        domain.MustParseMoney("50.00"),
 			},
 			mockSetup: func() {
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "withdrawal", "Updated note", 50.00).
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "withdrawal", "Updated note", domain.MustParseMoney("50.00")).
 					Return(nil)
-				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123", "user123").
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{
+						ID:              "trans123",
+						UserID:          "user123",
+						BudgetID:        "budget123",
+						CategoryID:      "cat123",
+						TransactionType: "deposit",
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
+					}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
 					Return(&domain.Transaction{
 						ID:              "trans123",
 						UserID:          "user123",
 						BudgetID:        "budget123",
+						CategoryID:      "cat123",
 						TransactionType: "deposit",
-						Amount:          100.00,
-						CreatedAt:       time.Now(),
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
 					}, nil)
-				mockBudgetHistoryRepo.EXPECT().ListFromDate(ctx, "budget123", gomock.Any(), true).
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "trans123").Return(nil)
+				mockCategoryLimitRepo.EXPECT().ListByCategoryTX(ctx, mockTx, "budget123", "cat123").Return(nil, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", createdAt).
 					Return([]*domain.BudgetHistory{
-						{TransactionID: sql.NullString{String: "history1", Valid: true}, Balance: 200.00},
+						{ID: "history1", Balance: domain.MustParseMoney("200.00"), CreatedAt: createdAt},
 					}, nil)
-				mockBudgetHistoryRepo.EXPECT().UpdateBalanceTX(ctx, mockTx, "history1", 50.00).
+				mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "budget123", "history1", domain.MustParseMoney("50.00")).
+					Return(nil)
+			},
+			expectedRes: &UpdateTransactionResponse{},
+			expectedErr: nil,
+		},
+		{
+			name: "Note-only update keeps the amount and the balance history",
+			req: &UpdateTransactionRequest{
+				UserID:        "user123",
+				TransactionID: "trans123",
+				Note:          "Updated note",
+			},
+			mockSetup: func() {
+				stored := func() *domain.Transaction {
+					return &domain.Transaction{
+						ID:              "trans123",
+						UserID:          "user123",
+						BudgetID:        "budget123",
+						CategoryID:      "cat123",
+						TransactionType: "withdrawal",
+						Note:            "Old note",
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
+					}
+				}
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").Return(stored(), nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").Return(stored(), nil)
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "withdrawal", "Updated note", domain.MustParseMoney("100.00")).
 					Return(nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "trans123").Return(nil)
 			},
 			expectedRes: &UpdateTransactionResponse{},
 			expectedErr: nil,
@@ -354,21 +900,35 @@ func TestUpdate(t *testing.T) {
 				CategoryID:    "cat123",
 				Type:          "deposit",
 				Note:          "Updated note",
-				Amount:        100.00,
+				Amount:        domain.MustParseMoney("100.00"),
 			},
 			mockSetup: func() {
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "deposit", "Updated note", 100.00).
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "deposit", "Updated note", domain.MustParseMoney("100.00")).
 					Return(nil)
-				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123", "user123").
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{
+						ID:              "trans123",
+						UserID:          "user123",
+						BudgetID:        "budget123",
+						CategoryID:      "cat123",
+						TransactionType: "deposit",
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
+					}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
 					Return(&domain.Transaction{
 						ID:              "trans123",
 						UserID:          "user123",
 						BudgetID:        "budget123",
+						CategoryID:      "cat123",
 						TransactionType: "deposit",
-						Amount:          100.00,
-						CreatedAt:       time.Now(),
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
 					}, nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "trans123").Return(nil)
 			},
 			expectedRes: &UpdateTransactionResponse{},
 			expectedErr: nil,
@@ -380,30 +940,50 @@ func TestUpdate(t *testing.T) {
 				TransactionID: "trans123",
 				CategoryID:    "cat123",
 				Type:          "deposit",
-				Amount:        100.00,
+				Amount:        domain.MustParseMoney("100.00"),
 			},
 			mockSetup: func() {
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "deposit", "", 100.00).
-					Return(errors.New("update error"))
-			},
-			expectedRes: nil,
-			expectedErr: errs.DatabaseError,
-		},
-		{
-			name: "GetByID error",
-			req: &UpdateTransactionRequest{
-				UserID:        "user123",
-				TransactionID: "trans123",
-				CategoryID:    "cat123",
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{
+						ID:              "trans123",
+						UserID:          "user123",
+						BudgetID:        "budget123",
+						CategoryID:      "cat123",
+						TransactionType: "deposit",
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
+					}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
+					Return(&domain.Transaction{
+						ID:              "trans123",
+						UserID:          "user123",
+						BudgetID:        "budget123",
+						CategoryID:      "cat123",
+						TransactionType: "deposit",
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
+					}, nil)
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "deposit", "", domain.MustParseMoney("100.00")).
+					Return(errors.New("update error"))
+			},
+			expectedRes: nil,
+			expectedErr: errs.DatabaseError,
+		},
+		{
+			name: "GetByID error",
+			req: &UpdateTransactionRequest{
+				UserID:        "user123",
+				TransactionID: "trans123",
+				CategoryID:    "cat123",
 				Type:          "withdrawal",
-				Amount:        50.00,
+				Amount:        domain.MustParseMoney("50.00"),
 			},
 			mockSetup: func() {
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "withdrawal", "", 50.00).
-					Return(nil)
-				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123", "user123").
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
 					Return(nil, errors.New("get error"))
 			},
 			expectedRes: nil,
@@ -416,30 +996,242 @@ func TestUpdate(t *testing.T) {
 				TransactionID: "trans123",
 				CategoryID:    "cat123",
 				Type:          "withdrawal",
-				Amount:        300.00,
+				Amount:        domain.MustParseMoney("300.00"),
 			},
 			mockSetup: func() {
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "withdrawal", "", 300.00).
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "withdrawal", "", domain.MustParseMoney("300.00")).
 					Return(nil)
-				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123", "user123").
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{
+						ID:              "trans123",
+						UserID:          "user123",
+						BudgetID:        "budget123",
+						CategoryID:      "cat123",
+						TransactionType: "deposit",
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
+					}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
 					Return(&domain.Transaction{
 						ID:              "trans123",
 						UserID:          "user123",
 						BudgetID:        "budget123",
+						CategoryID:      "cat123",
 						TransactionType: "deposit",
-						Amount:          100.00,
-						CreatedAt:       time.Now(),
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
 					}, nil)
-				mockBudgetHistoryRepo.EXPECT().ListFromDate(ctx, "budget123", gomock.Any(), true).
+				mockCategoryLimitRepo.EXPECT().ListByCategoryTX(ctx, mockTx, "budget123", "cat123").Return(nil, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", createdAt).
 					Return([]*domain.BudgetHistory{
-						{TransactionID: sql.NullString{String: "history1", Valid: true}, Balance: 200.00},
+						{ID: "history1", Balance: domain.MustParseMoney("200.00"), CreatedAt: createdAt},
 					}, nil)
 				// Delta: -100 (revert deposit) - 300 (new withdrawal) = -400, new balance = 200 - 400 = -200 (insufficient)
 			},
 			expectedRes: nil,
 			expectedErr: errs.InsufficientBalance,
 		},
+		{
+			name: "Withdrawal over an enforced category limit is rejected",
+			req: &UpdateTransactionRequest{
+				UserID:        "user123",
+				TransactionID: "trans123",
+				CategoryID:    "cat123",
+				Type:          "withdrawal",
+				Amount:        domain.MustParseMoney("50.00"),
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", CategoryID: "cat123", TransactionType: "deposit", Amount: domain.MustParseMoney("100.00"), CreatedAt: createdAt}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", CategoryID: "cat123", TransactionType: "deposit", Amount: domain.MustParseMoney("100.00"), CreatedAt: createdAt}, nil)
+				mockCategoryLimitRepo.EXPECT().ListByCategoryTX(ctx, mockTx, "budget123", "cat123").Return([]*domain.CategoryLimit{
+					{ID: "limit1", CategoryID: "cat123", Amount: domain.MustParseMoney("400.00"), Period: "month", Enforce: true},
+				}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockCategoryLimitRepo.EXPECT().SpentTX(ctx, mockTx, "budget123", "cat123", gomock.Any(), gomock.Any()).
+					Return(domain.MustParseMoney("370.00"), nil)
+			},
+			expectedErr: errs.CategoryLimitExceeded,
+		},
+		{
+			name: "Raised withdrawal only counts the difference against the limit",
+			req: &UpdateTransactionRequest{
+				UserID:        "user123",
+				TransactionID: "trans123",
+				CategoryID:    "cat123",
+				Type:          "withdrawal",
+				Amount:        domain.MustParseMoney("120.00"),
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", CategoryID: "cat123", TransactionType: "withdrawal", Amount: domain.MustParseMoney("100.00"), CreatedAt: createdAt}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", CategoryID: "cat123", TransactionType: "withdrawal", Amount: domain.MustParseMoney("100.00"), CreatedAt: createdAt}, nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "trans123").Return(nil)
+				// The 100.00 already spent includes the transaction, so only 20.00 is added.
+				mockCategoryLimitRepo.EXPECT().ListByCategoryTX(ctx, mockTx, "budget123", "cat123").Return([]*domain.CategoryLimit{
+					{ID: "limit1", CategoryID: "cat123", Amount: domain.MustParseMoney("130.00"), Period: "month", Enforce: true},
+				}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockCategoryLimitRepo.EXPECT().SpentTX(ctx, mockTx, "budget123", "cat123", gomock.Any(), gomock.Any()).
+					Return(domain.MustParseMoney("100.00"), nil)
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "withdrawal", "", domain.MustParseMoney("120.00")).
+					Return(nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", createdAt).
+					Return([]*domain.BudgetHistory{
+						{ID: "history1", Balance: domain.MustParseMoney("200.00"), CreatedAt: createdAt},
+					}, nil)
+				mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "budget123", "history1", domain.MustParseMoney("180.00")).
+					Return(nil)
+			},
+			expectedRes: &UpdateTransactionResponse{},
+		},
+		{
+			name: "Lowered withdrawal skips the limit check",
+			req: &UpdateTransactionRequest{
+				UserID:        "user123",
+				TransactionID: "trans123",
+				CategoryID:    "cat123",
+				Type:          "withdrawal",
+				Amount:        domain.MustParseMoney("80.00"),
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", CategoryID: "cat123", TransactionType: "withdrawal", Amount: domain.MustParseMoney("100.00"), CreatedAt: createdAt}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", CategoryID: "cat123", TransactionType: "withdrawal", Amount: domain.MustParseMoney("100.00"), CreatedAt: createdAt}, nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "trans123").Return(nil)
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "withdrawal", "", domain.MustParseMoney("80.00")).
+					Return(nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", createdAt).
+					Return([]*domain.BudgetHistory{
+						{ID: "history1", Balance: domain.MustParseMoney("200.00"), CreatedAt: createdAt},
+					}, nil)
+				mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "budget123", "history1", domain.MustParseMoney("220.00")).
+					Return(nil)
+			},
+			expectedRes: &UpdateTransactionResponse{},
+		},
+		{
+			name: "Editor moves a transaction of a shared budget to a category of the owner",
+			req: &UpdateTransactionRequest{
+				UserID:        "user456",
+				TransactionID: "trans123",
+				CategoryID:    "cat123",
+				Type:          "deposit",
+				Amount:        domain.MustParseMoney("100.00"),
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", CategoryID: "misc", TransactionType: "deposit", Amount: domain.MustParseMoney("100.00")}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", CategoryID: "misc", TransactionType: "deposit", Amount: domain.MustParseMoney("100.00")}, nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "trans123").Return(nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "editor"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return([]*domain.Category{{ID: "cat123"}, {ID: "misc"}}, nil)
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "deposit", "", domain.MustParseMoney("100.00")).
+					Return(nil)
+			},
+			expectedRes: &UpdateTransactionResponse{},
+		},
+		{
+			name: "Editor cannot move a transaction to their own custom category",
+			req: &UpdateTransactionRequest{
+				UserID:        "user456",
+				TransactionID: "trans123",
+				CategoryID:    "cat456",
+				Type:          "deposit",
+				Amount:        domain.MustParseMoney("100.00"),
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", CategoryID: "misc", TransactionType: "deposit", Amount: domain.MustParseMoney("100.00")}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", CategoryID: "misc", TransactionType: "deposit", Amount: domain.MustParseMoney("100.00")}, nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "editor"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return([]*domain.Category{{ID: "cat123"}, {ID: "misc"}}, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Viewer cannot update",
+			req: &UpdateTransactionRequest{
+				UserID:        "user456",
+				TransactionID: "trans123",
+				CategoryID:    "cat123",
+				Type:          "deposit",
+				Amount:        domain.MustParseMoney("100.00"),
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123"}, nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "viewer"}, nil)
+			},
+			expectedErr: errs.BudgetForbidden,
+		},
+		{
+			name: "Transaction not found",
+			req: &UpdateTransactionRequest{
+				UserID:        "user123",
+				TransactionID: "trans123",
+				CategoryID:    "cat123",
+				Type:          "deposit",
+				Amount:        domain.MustParseMoney("100.00"),
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.TransactionNotFound,
+		},
+		{
+			name: "Budget history is shifted by the locked row, not the cached one",
+			req: &UpdateTransactionRequest{
+				UserID:        "user123",
+				TransactionID: "trans123",
+				CategoryID:    "cat123",
+				Type:          "deposit",
+				Amount:        domain.MustParseMoney("100.00"),
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				// A concurrent update has already raised the amount to 100.00.
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", CategoryID: "cat123", TransactionType: "deposit", Amount: domain.MustParseMoney("60.00"), CreatedAt: createdAt}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", CategoryID: "cat123", TransactionType: "deposit", Amount: domain.MustParseMoney("100.00"), CreatedAt: createdAt}, nil)
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "trans123", "user123", "cat123", "deposit", "", domain.MustParseMoney("100.00")).
+					Return(nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "trans123").Return(nil)
+			},
+			expectedRes: &UpdateTransactionResponse{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -452,7 +1244,7 @@ func TestUpdate(t *testing.T) {
 				},
 			}
 
-			service := NewService(mockTransactionRepo, mockBudgetHistoryRepo, mockTxExec)
+			service := NewService(mockTransactionRepo, mockBudgetRepo, mockBudgetHistoryRepo, mockCategoryRepo, mockCategoryLimitRepo, nil, mockTxExec)
 
 			resp, err := service.Update(ctx, tt.req)
 
@@ -474,9 +1266,13 @@ func TestDelete(t *testing.T) {
 
 	ctx := context.Background()
 	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
 	mockBudgetHistoryRepo := mock.NewMockBudgetHistory(ctrl)
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
 	mockDB := &sqlx.DB{}
 	mockTx := &sqlx.Tx{}
+	createdAt := time.Now()
+	ownedBudget := &domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}
 
 	tests := []struct {
 		name        string
@@ -493,20 +1289,33 @@ func TestDelete(t *testing.T) {
 			},
 			mockSetup: func() {
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123", "user123").
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{
+						ID:              "trans123",
+						UserID:          "user123",
+						BudgetID:        "budget123",
+						TransactionType: "deposit",
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
+					}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
 					Return(&domain.Transaction{
 						ID:              "trans123",
 						UserID:          "user123",
 						BudgetID:        "budget123",
 						TransactionType: "deposit",
-						Amount:          100.00,
-						CreatedAt:       time.Now(),
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
 					}, nil)
-				mockBudgetHistoryRepo.EXPECT().ListFromDate(ctx, "budget123", gomock.Any(), false).
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "trans123").Return(nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", createdAt).
 					Return([]*domain.BudgetHistory{
-						{TransactionID: sql.NullString{String: "history1", Valid: true}, Balance: 200.00},
+						{ID: "history1", Balance: domain.MustParseMoney("200.00"), CreatedAt: createdAt.Add(time.Hour)},
 					}, nil)
-				mockBudgetHistoryRepo.EXPECT().UpdateBalanceTX(ctx, mockTx, "history1", 100.00).
+				mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "budget123", "history1", domain.MustParseMoney("100.00")).
 					Return(nil)
 				mockTransactionRepo.EXPECT().DeleteTX(ctx, mockTx, "trans123", "user123").
 					Return(nil)
@@ -522,7 +1331,7 @@ func TestDelete(t *testing.T) {
 			},
 			mockSetup: func() {
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123", "user123").
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
 					Return(nil, errors.New("get error"))
 			},
 			expectedRes: nil,
@@ -536,20 +1345,32 @@ func TestDelete(t *testing.T) {
 			},
 			mockSetup: func() {
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123", "user123").
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{
+						ID:              "trans123",
+						UserID:          "user123",
+						BudgetID:        "budget123",
+						TransactionType: "deposit",
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
+					}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
 					Return(&domain.Transaction{
 						ID:              "trans123",
 						UserID:          "user123",
 						BudgetID:        "budget123",
 						TransactionType: "deposit",
-						Amount:          100.00,
-						CreatedAt:       time.Now(),
+						Amount:          domain.MustParseMoney("100.00"),
+						CreatedAt:       createdAt,
 					}, nil)
-				mockBudgetHistoryRepo.EXPECT().ListFromDate(ctx, "budget123", gomock.Any(), false).
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", createdAt).
 					Return([]*domain.BudgetHistory{
-						{TransactionID: sql.NullString{String: "history1", Valid: true}, Balance: 200.00},
+						{ID: "history1", Balance: domain.MustParseMoney("200.00"), CreatedAt: createdAt.Add(time.Hour)},
 					}, nil)
-				mockBudgetHistoryRepo.EXPECT().UpdateBalanceTX(ctx, mockTx, "history1", 100.00).
+				mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "budget123", "history1", domain.MustParseMoney("100.00")).
 					Return(nil)
 				mockTransactionRepo.EXPECT().DeleteTX(ctx, mockTx, "trans123", "user123").
 					Return(errors.New("delete error"))
@@ -565,23 +1386,66 @@ func TestDelete(t *testing.T) {
 			},
 			mockSetup: func() {
 				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
-				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123", "user123").
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{
+						ID:              "trans123",
+						UserID:          "user123",
+						BudgetID:        "budget123",
+						TransactionType: "deposit",
+						Amount:          domain.MustParseMoney("300.00"),
+						CreatedAt:       createdAt,
+					}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").
 					Return(&domain.Transaction{
 						ID:              "trans123",
 						UserID:          "user123",
 						BudgetID:        "budget123",
 						TransactionType: "deposit",
-						Amount:          300.00,
-						CreatedAt:       time.Now(),
+						Amount:          domain.MustParseMoney("300.00"),
+						CreatedAt:       createdAt,
 					}, nil)
-				mockBudgetHistoryRepo.EXPECT().ListFromDate(ctx, "budget123", gomock.Any(), false).
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", createdAt).
 					Return([]*domain.BudgetHistory{
-						{TransactionID: sql.NullString{String: "history1", Valid: true}, Balance: 200.00},
+						{ID: "history1", Balance: domain.MustParseMoney("200.00"), CreatedAt: createdAt.Add(time.Hour)},
 					}, nil)
 			},
 			expectedRes: nil,
 			expectedErr: errs.InsufficientBalance,
 		},
+		{
+			name: "Transaction of a budget the user is not a member of",
+			req: &DeleteTransactionRequest{
+				UserID:        "user456",
+				TransactionID: "trans123",
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123"}, nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.TransactionNotFound,
+		},
+		{
+			name: "Transaction deleted by a concurrent request is not reversed twice",
+			req: &DeleteTransactionRequest{
+				UserID:        "user123",
+				TransactionID: "trans123",
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(ownedBudget, nil)
+				// The cache still holds the transaction the other request has just deleted.
+				mockTransactionRepo.EXPECT().GetByID(ctx, "trans123").
+					Return(&domain.Transaction{ID: "trans123", UserID: "user123", BudgetID: "budget123", TransactionType: "deposit", Amount: domain.MustParseMoney("100.00"), CreatedAt: createdAt}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "trans123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.TransactionNotFound,
+		},
 	}
 
 	for _, tt := range tests {
@@ -594,7 +1458,7 @@ func TestDelete(t *testing.T) {
 				},
 			}
 
-			service := NewService(mockTransactionRepo, mockBudgetHistoryRepo, mockTxExec)
+			service := NewService(mockTransactionRepo, mockBudgetRepo, mockBudgetHistoryRepo, nil, mockCategoryLimitRepo, nil, mockTxExec)
 
 			resp, err := service.Delete(ctx, tt.req)
 
@@ -616,7 +1480,9 @@ func TestUpdateBudgetHistory(t *testing.T) {
 
 	ctx := context.Background()
 	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
 	mockBudgetHistoryRepo := mock.NewMockBudgetHistory(ctrl)
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
 	mockTx := &sqlx.Tx{}
 	fromDate := time.Now()
 
@@ -624,7 +1490,7 @@ func TestUpdateBudgetHistory(t *testing.T) {
 		name        string
 		budgetID    string
 		fromDate    time.Time
-		difference  float64
+		difference  domain.Money
 		inclusive   bool
 		mockSetup   func()
 		expectedErr error
@@ -633,17 +1499,18 @@ func TestUpdateBudgetHistory(t *testing.T) {
 			name:       "Successful update budget history",
 			budgetID:   "budget123",
 			fromDate:   fromDate,
-			difference: -50.00,
+			difference: domain.MustParseMoney("-50.00"),
 			inclusive:  true,
 			mockSetup: func() {
-				mockBudgetHistoryRepo.EXPECT().ListFromDate(ctx, "budget123", fromDate, true).
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", fromDate).
 					Return([]*domain.BudgetHistory{
-						{TransactionID: sql.NullString{String: "history1", Valid: true}, Balance: 200.00},
-						{TransactionID: sql.NullString{String: "history2", Valid: true}, Balance: 150.00},
+						{ID: "history1", Balance: domain.MustParseMoney("200.00"), CreatedAt: fromDate},
+						{ID: "history2", Balance: domain.MustParseMoney("150.00"), CreatedAt: fromDate},
 					}, nil)
-				mockBudgetHistoryRepo.EXPECT().UpdateBalanceTX(ctx, mockTx, "history1", 150.00).
+				mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "budget123", "history1", domain.MustParseMoney("150.00")).
 					Return(nil)
-				mockBudgetHistoryRepo.EXPECT().UpdateBalanceTX(ctx, mockTx, "history2", 100.00).
+				mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "budget123", "history2", domain.MustParseMoney("100.00")).
 					Return(nil)
 			},
 			expectedErr: nil,
@@ -652,40 +1519,43 @@ func TestUpdateBudgetHistory(t *testing.T) {
 			name:       "Insufficient balance",
 			budgetID:   "budget123",
 			fromDate:   fromDate,
-			difference: -200.00,
+			difference: domain.MustParseMoney("-200.00"),
 			inclusive:  true,
 			mockSetup: func() {
-				mockBudgetHistoryRepo.EXPECT().ListFromDate(ctx, "budget123", fromDate, true).
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", fromDate).
 					Return([]*domain.BudgetHistory{
-						{TransactionID: sql.NullString{String: "history1", Valid: true}, Balance: 100.00},
+						{ID: "history1", Balance: domain.MustParseMoney("100.00"), CreatedAt: fromDate},
 					}, nil)
 			},
 			expectedErr: errs.InsufficientBalance,
 		},
 		{
-			name:       "ListFromDate error",
+			name:       "ListSinceTX error",
 			budgetID:   "budget123",
 			fromDate:   fromDate,
-			difference: 50.00,
+			difference: domain.MustParseMoney("50.00"),
 			inclusive:  true,
 			mockSetup: func() {
-				mockBudgetHistoryRepo.EXPECT().ListFromDate(ctx, "budget123", fromDate, true).
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", fromDate).
 					Return(nil, errors.New("db error"))
 			},
 			expectedErr: errs.DatabaseError,
 		},
 		{
-			name:       "UpdateBalanceTX error",
+			name:       "UpdateBalanceByIDTX error",
 			budgetID:   "budget123",
 			fromDate:   fromDate,
-			difference: 50.00,
+			difference: domain.MustParseMoney("50.00"),
 			inclusive:  true,
 			mockSetup: func() {
-				mockBudgetHistoryRepo.EXPECT().ListFromDate(ctx, "budget123", fromDate, true).
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", fromDate).
 					Return([]*domain.BudgetHistory{
-						{TransactionID: sql.NullString{String: "history1", Valid: true}, Balance: 100.00},
+						{ID: "history1", Balance: domain.MustParseMoney("100.00"), CreatedAt: fromDate},
 					}, nil)
-				mockBudgetHistoryRepo.EXPECT().UpdateBalanceTX(ctx, mockTx, "history1", 150.00).
+				mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "budget123", "history1", domain.MustParseMoney("150.00")).
 					Return(errors.New("update error"))
 			},
 			expectedErr: errs.DatabaseError,
@@ -696,7 +1566,7 @@ func TestUpdateBudgetHistory(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			service := NewService(mockTransactionRepo, mockBudgetHistoryRepo, transactionExec.NewTransactionExecutor())
+			service := NewService(mockTransactionRepo, mockBudgetRepo, mockBudgetHistoryRepo, nil, mockCategoryLimitRepo, nil, transactionExec.NewTransactionExecutor())
 
 			err := service.updateBudgetHistory(ctx, mockTx, tt.budgetID, tt.fromDate, tt.difference, tt.inclusive)
 
@@ -709,3 +1579,983 @@ func TestUpdateBudgetHistory(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockBudgetHistoryRepo := mock.NewMockBudgetHistory(ctrl)
+	mockCategoryRepo := mock_category.NewMockCategory(ctrl)
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
+	mockDB := &sqlx.DB{}
+	mockTx := &sqlx.Tx{}
+
+	savings := &domain.Budget{ID: "savings", UserID: "user123", Currency: "USD", Role: "owner"}
+	checking := &domain.Budget{ID: "checking", UserID: "user123", Currency: "USD", Role: "owner"}
+
+	tests := []struct {
+		name        string
+		req         *CreateTransferRequest
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Successful transfer",
+			req: &CreateTransferRequest{
+				UserID:       "user123",
+				FromBudgetID: "savings",
+				ToBudgetID:   "checking",
+				Amount:       domain.MustParseMoney("40.00"),
+				Note:         "Rent top-up",
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "savings", "user123").Return(savings, nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "checking", "user123").Return(checking, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				gomock.InOrder(
+					mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "checking").Return(nil),
+					mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "savings").Return(nil),
+				)
+
+				mockTransactionRepo.EXPECT().CreateTransferLegTX(ctx, mockTx, gomock.Any(), "user123", "user123", "savings", domain.TransferCategoryID, "transfer_out", "Rent top-up", domain.MustParseMoney("40.00")).
+					Return("out1", nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "savings").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "savings", gomock.Any()).
+					Return([]*domain.BudgetHistory{{Balance: domain.MustParseMoney("100.00")}}, nil)
+				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "savings", "out1", domain.MustParseMoney("60.00")).
+					Return("history1", nil)
+
+				mockTransactionRepo.EXPECT().CreateTransferLegTX(ctx, mockTx, gomock.Any(), "user123", "user123", "checking", domain.TransferCategoryID, "transfer_in", "Rent top-up", domain.MustParseMoney("40.00")).
+					Return("in1", nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "checking").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "checking", gomock.Any()).
+					Return(nil, nil)
+				mockBudgetHistoryRepo.EXPECT().CreateTX(ctx, mockTx, "checking", "in1", domain.MustParseMoney("40.00")).
+					Return("history2", nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Same budget",
+			req: &CreateTransferRequest{
+				UserID:       "user123",
+				FromBudgetID: "savings",
+				ToBudgetID:   "savings",
+				Amount:       domain.MustParseMoney("40.00"),
+			},
+			mockSetup:   func() {},
+			expectedErr: errs.SameBudgetTransfer,
+		},
+		{
+			name: "Foreign destination budget",
+			req: &CreateTransferRequest{
+				UserID:       "user123",
+				FromBudgetID: "savings",
+				ToBudgetID:   "other",
+				Amount:       domain.MustParseMoney("40.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "savings", "user123").Return(savings, nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "other", "user123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.BudgetNotFound,
+		},
+		{
+			name: "Destination budget shared as viewer",
+			req: &CreateTransferRequest{
+				UserID:       "user123",
+				FromBudgetID: "savings",
+				ToBudgetID:   "household",
+				Amount:       domain.MustParseMoney("40.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "savings", "user123").Return(savings, nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "household", "user123").
+					Return(&domain.Budget{ID: "household", UserID: "user456", Currency: "USD", Role: "viewer"}, nil)
+			},
+			expectedErr: errs.BudgetForbidden,
+		},
+		{
+			name: "Destination budget archived",
+			req: &CreateTransferRequest{
+				UserID:       "user123",
+				FromBudgetID: "savings",
+				ToBudgetID:   "old",
+				Amount:       domain.MustParseMoney("40.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "savings", "user123").Return(savings, nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "old", "user123").
+					Return(&domain.Budget{ID: "old", Currency: "USD", Role: "owner", ArchivedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+			},
+			expectedErr: errs.BudgetArchived,
+		},
+		{
+			name: "Currency mismatch",
+			req: &CreateTransferRequest{
+				UserID:       "user123",
+				FromBudgetID: "savings",
+				ToBudgetID:   "euro",
+				Amount:       domain.MustParseMoney("40.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "savings", "user123").Return(savings, nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "euro", "user123").Return(&domain.Budget{ID: "euro", Currency: "EUR", Role: "owner"}, nil)
+			},
+			expectedErr: errs.CurrencyMismatch,
+		},
+		{
+			name: "Category the destination owner can't use",
+			req: &CreateTransferRequest{
+				UserID:       "user123",
+				FromBudgetID: "savings",
+				ToBudgetID:   "household",
+				CategoryID:   "custom",
+				Amount:       domain.MustParseMoney("40.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "savings", "user123").Return(savings, nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "household", "user123").
+					Return(&domain.Budget{ID: "household", UserID: "user456", Currency: "USD", Role: "editor"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return([]*domain.Category{{ID: "custom"}}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user456").Return([]*domain.Category{{ID: "food"}}, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Unknown category",
+			req: &CreateTransferRequest{
+				UserID:       "user123",
+				FromBudgetID: "savings",
+				ToBudgetID:   "checking",
+				CategoryID:   "missing",
+				Amount:       domain.MustParseMoney("40.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "savings", "user123").Return(savings, nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "checking", "user123").Return(checking, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return([]*domain.Category{{ID: "custom"}}, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Insufficient balance in source budget",
+			req: &CreateTransferRequest{
+				UserID:       "user123",
+				FromBudgetID: "savings",
+				ToBudgetID:   "checking",
+				Amount:       domain.MustParseMoney("400.00"),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "savings", "user123").Return(savings, nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "checking", "user123").Return(checking, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				gomock.InOrder(
+					mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "checking").Return(nil),
+					mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "savings").Return(nil),
+				)
+				mockTransactionRepo.EXPECT().CreateTransferLegTX(ctx, mockTx, gomock.Any(), "user123", "user123", "savings", domain.TransferCategoryID, "transfer_out", "", domain.MustParseMoney("400.00")).
+					Return("out1", nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "savings").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "savings", gomock.Any()).
+					Return([]*domain.BudgetHistory{{Balance: domain.MustParseMoney("100.00")}}, nil)
+			},
+			expectedErr: errs.InsufficientBalance,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			mockTxExec := &mockTransactionExecutor{
+				withTx: func(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+					return fn(mockTx)
+				},
+			}
+
+			service := NewService(mockTransactionRepo, mockBudgetRepo, mockBudgetHistoryRepo, mockCategoryRepo, mockCategoryLimitRepo, nil, mockTxExec)
+
+			resp, err := service.CreateTransfer(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, resp.TransferID)
+				assert.Equal(t, "out1", resp.OutgoingID)
+				assert.Equal(t, "in1", resp.IncomingID)
+			}
+		})
+	}
+}
+
+func TestUpdateTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockBudgetHistoryRepo := mock.NewMockBudgetHistory(ctrl)
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
+	mockDB := &sqlx.DB{}
+	mockTx := &sqlx.Tx{}
+	createdAt := time.Now()
+	owned := func(id string) *domain.Budget {
+		return &domain.Budget{ID: id, UserID: "user123", Role: "owner"}
+	}
+
+	legs := func() []*domain.Transaction {
+		transferID := sql.NullString{String: "transfer1", Valid: true}
+		return []*domain.Transaction{
+			{ID: "out1", UserID: "user123", BudgetID: "savings", CategoryID: "cat", TransactionType: "transfer_out", Amount: domain.MustParseMoney("40.00"), Note: "old", TransferID: transferID, CreatedAt: createdAt},
+			{ID: "in1", UserID: "user123", BudgetID: "checking", CategoryID: "cat", TransactionType: "transfer_in", Amount: domain.MustParseMoney("40.00"), Note: "old", TransferID: transferID, CreatedAt: createdAt},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		req         *UpdateTransferRequest
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Successful amount change updates both budgets",
+			req: &UpdateTransferRequest{
+				UserID:     "user123",
+				TransferID: "transfer1",
+				Amount:     domain.MustParseMoney("50.00"),
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().ListByTransferIDTX(ctx, mockTx, "transfer1").Return(legs(), nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "savings", "user123").Return(owned("savings"), nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "checking", "user123").Return(owned("checking"), nil)
+				gomock.InOrder(
+					mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "checking").Return(nil),
+					mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "savings").Return(nil),
+				)
+
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "out1", "user123", "cat", "transfer_out", "old", domain.MustParseMoney("50.00")).Return(nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "savings").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "savings", createdAt).
+					Return([]*domain.BudgetHistory{
+						{ID: "out1", Balance: domain.MustParseMoney("60.00"), CreatedAt: createdAt},
+					}, nil)
+				mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "savings", "out1", domain.MustParseMoney("50.00")).Return(nil)
+
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "in1", "user123", "cat", "transfer_in", "old", domain.MustParseMoney("50.00")).Return(nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "checking").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "checking", createdAt).
+					Return([]*domain.BudgetHistory{
+						{ID: "in1", Balance: domain.MustParseMoney("40.00"), CreatedAt: createdAt},
+					}, nil)
+				mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "checking", "in1", domain.MustParseMoney("50.00")).Return(nil)
+
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "out1").Return(nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "in1").Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Note only change keeps balances",
+			req: &UpdateTransferRequest{
+				UserID:     "user123",
+				TransferID: "transfer1",
+				Note:       "new",
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().ListByTransferIDTX(ctx, mockTx, "transfer1").Return(legs(), nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "savings", "user123").Return(owned("savings"), nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "checking", "user123").Return(owned("checking"), nil)
+				gomock.InOrder(
+					mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "checking").Return(nil),
+					mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "savings").Return(nil),
+				)
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "out1", "user123", "cat", "transfer_out", "new", domain.MustParseMoney("40.00")).Return(nil)
+				mockTransactionRepo.EXPECT().UpdateTX(ctx, mockTx, "in1", "user123", "cat", "transfer_in", "new", domain.MustParseMoney("40.00")).Return(nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "out1").Return(nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "in1").Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Transfer not found",
+			req: &UpdateTransferRequest{
+				UserID:     "user123",
+				TransferID: "missing",
+				Note:       "new",
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().ListByTransferIDTX(ctx, mockTx, "missing").Return(nil, nil)
+			},
+			expectedErr: errs.TransferNotFound,
+		},
+		{
+			name: "Transfer into a budget the user is no longer a member of",
+			req: &UpdateTransferRequest{
+				UserID:     "user123",
+				TransferID: "transfer1",
+				Note:       "new",
+			},
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().ListByTransferIDTX(ctx, mockTx, "transfer1").Return(legs(), nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "savings", "user123").Return(owned("savings"), nil)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "checking", "user123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.TransferNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			mockTxExec := &mockTransactionExecutor{
+				withTx: func(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+					return fn(mockTx)
+				},
+			}
+
+			service := NewService(mockTransactionRepo, mockBudgetRepo, mockBudgetHistoryRepo, nil, mockCategoryLimitRepo, nil, mockTxExec)
+
+			resp, err := service.UpdateTransfer(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &UpdateTransferResponse{}, resp)
+			}
+		})
+	}
+}
+
+func TestDeleteTransferLeg(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockBudgetHistoryRepo := mock.NewMockBudgetHistory(ctrl)
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
+	mockDB := &sqlx.DB{}
+	mockTx := &sqlx.Tx{}
+	createdAt := time.Now()
+	transferID := sql.NullString{String: "transfer1", Valid: true}
+
+	out := &domain.Transaction{ID: "out1", UserID: "user123", BudgetID: "savings", TransactionType: "transfer_out", Amount: domain.MustParseMoney("40.00"), TransferID: transferID, CreatedAt: createdAt}
+	in := &domain.Transaction{ID: "in1", UserID: "user123", BudgetID: "checking", TransactionType: "transfer_in", Amount: domain.MustParseMoney("40.00"), TransferID: transferID, CreatedAt: createdAt}
+
+	// Deleting either leg through the regular endpoint removes the whole transfer.
+	mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+	mockTransactionRepo.EXPECT().GetByID(ctx, "in1").Return(in, nil)
+	mockTransactionRepo.EXPECT().ListByTransferIDTX(ctx, mockTx, "transfer1").Return([]*domain.Transaction{out, in}, nil)
+	mockBudgetRepo.EXPECT().GetByID(ctx, "savings", "user123").Return(&domain.Budget{ID: "savings", UserID: "user123", Role: "owner"}, nil)
+	mockBudgetRepo.EXPECT().GetByID(ctx, "checking", "user123").Return(&domain.Budget{ID: "checking", UserID: "user123", Role: "owner"}, nil).Times(2)
+	gomock.InOrder(
+		mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "checking").Return(nil),
+		mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "savings").Return(nil),
+	)
+
+	mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "savings").Return(nil)
+	mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "savings", createdAt).
+		Return([]*domain.BudgetHistory{
+			{ID: "later1", Balance: domain.MustParseMoney("10.00"), CreatedAt: createdAt.Add(time.Hour)},
+		}, nil)
+	mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "savings", "later1", domain.MustParseMoney("50.00")).Return(nil)
+	mockTransactionRepo.EXPECT().DeleteTX(ctx, mockTx, "out1", "user123").Return(nil)
+
+	mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "checking").Return(nil)
+	mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "checking", createdAt).
+		Return([]*domain.BudgetHistory{
+			{ID: "later2", Balance: domain.MustParseMoney("45.00"), CreatedAt: createdAt.Add(time.Hour)},
+		}, nil)
+	mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "checking", "later2", domain.MustParseMoney("5.00")).Return(nil)
+	mockTransactionRepo.EXPECT().DeleteTX(ctx, mockTx, "in1", "user123").Return(nil)
+
+	mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "out1").Return(nil)
+	mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "in1").Return(nil)
+
+	mockTxExec := &mockTransactionExecutor{
+		withTx: func(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+			return fn(mockTx)
+		},
+	}
+
+	service := NewService(mockTransactionRepo, mockBudgetRepo, mockBudgetHistoryRepo, nil, mockCategoryLimitRepo, nil, mockTxExec)
+
+	resp, err := service.Delete(ctx, &DeleteTransactionRequest{UserID: "user123", TransactionID: "in1"})
+	assert.NoError(t, err)
+	assert.Equal(t, &DeleteTransactionResponse{}, resp)
+}
+
+func TestImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockBudgetHistoryRepo := mock.NewMockBudgetHistory(ctrl)
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
+	mockCategoryRepo := mock_category.NewMockCategory(ctrl)
+	mockRuleRepo := mock_transaction_rule.NewMockTransactionRule(ctrl)
+	mockDB := &sqlx.DB{}
+	mockTx := &sqlx.Tx{}
+	categories := []*domain.Category{{ID: "category123"}, {ID: "entertainment"}}
+
+	date := func(month time.Month, day int) time.Time { return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC) }
+	history := func() []*domain.BudgetHistory {
+		return []*domain.BudgetHistory{
+			{ID: "h0", BudgetID: "budget123", Balance: domain.MustParseMoney("100.00"), CreatedAt: date(time.January, 10)},
+			{ID: "h1", BudgetID: "budget123", TransactionID: sql.NullString{String: "t1", Valid: true}, Balance: domain.MustParseMoney("80.00"), CreatedAt: date(time.February, 1)},
+		}
+	}
+	request := func(csv string, dryRun bool) *ImportTransactionsRequest {
+		return &ImportTransactionsRequest{
+			UserID:           "user123",
+			BudgetID:         "budget123",
+			CategoryID:       "category123",
+			DateColumn:       "Date",
+			AmountColumn:     "Amount",
+			NoteColumn:       "Description",
+			DateFormat:       "DD.MM.YYYY",
+			DecimalSeparator: ",",
+			Delimiter:        ";",
+			DryRun:           dryRun,
+			File:             strings.NewReader(csv),
+		}
+	}
+	money := func(s string) *domain.Money {
+		m := domain.MustParseMoney(s)
+		return &m
+	}
+	// imported matches a transaction regardless of its generated content-hash external ID.
+	imported := func(expected domain.Transaction) gomock.Matcher {
+		return gomock.Cond(func(actual *domain.Transaction) bool {
+			got := *actual
+			if !got.ExternalID.Valid || !strings.HasPrefix(got.ExternalID.String, "sha256:") {
+				return false
+			}
+			got.ExternalID = sql.NullString{}
+			return reflect.DeepEqual(got, expected)
+		})
+	}
+
+	tests := []struct {
+		name        string
+		req         *ImportTransactionsRequest
+		mockSetup   func()
+		expectedRes *ImportTransactionsResponse
+		expectedErr error
+	}{
+		{
+			name: "Back-dated rows shift later balances",
+			req:  request("Date;Amount;Description\n15.01.2025;-30,00;Groceries\n05.01.2025;1.000,50;Salary\n", false),
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().ListExternalIDsTX(ctx, mockTx, "budget123", gomock.Len(2)).Return(nil, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", date(time.January, 5)).Return(history(), nil)
+				gomock.InOrder(
+					mockTransactionRepo.EXPECT().ImportTX(ctx, mockTx, imported(domain.Transaction{
+						UserID: "user123", CreatedBy: sql.NullString{String: "user123", Valid: true}, BudgetID: "budget123", CategoryID: "category123",
+						Amount: domain.MustParseMoney("1000.50"), TransactionType: "deposit", Note: "Salary", CreatedAt: date(time.January, 5),
+					})).Return("n1", nil),
+					mockBudgetHistoryRepo.EXPECT().CreateAtTX(ctx, mockTx, "budget123", "n1", domain.MustParseMoney("1000.50"), date(time.January, 5)).Return("hn1", nil),
+					mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "budget123", "h0", domain.MustParseMoney("1100.50")).Return(nil),
+					mockTransactionRepo.EXPECT().ImportTX(ctx, mockTx, imported(domain.Transaction{
+						UserID: "user123", CreatedBy: sql.NullString{String: "user123", Valid: true}, BudgetID: "budget123", CategoryID: "category123",
+						Amount: domain.MustParseMoney("30.00"), TransactionType: "withdrawal", Note: "Groceries", CreatedAt: date(time.January, 15),
+					})).Return("n2", nil),
+					mockBudgetHistoryRepo.EXPECT().CreateAtTX(ctx, mockTx, "budget123", "n2", domain.MustParseMoney("1070.50"), date(time.January, 15)).Return("hn2", nil),
+					mockBudgetHistoryRepo.EXPECT().UpdateBalanceByIDTX(ctx, mockTx, "budget123", "h1", domain.MustParseMoney("1050.50")).Return(nil),
+				)
+			},
+			expectedRes: &ImportTransactionsResponse{
+				Total:    2,
+				Imported: 2,
+				Rows: []ImportRowResult{
+					{Line: 2, Date: func() *time.Time { d := date(time.January, 15); return &d }(), Amount: domain.MustParseMoney("30.00"), Type: e_transaction_type.Withdrawal, Note: "Groceries", CategoryID: "category123", Balance: money("1070.50"), TransactionID: "n2"},
+					{Line: 3, Date: func() *time.Time { d := date(time.January, 5); return &d }(), Amount: domain.MustParseMoney("1000.50"), Type: e_transaction_type.Deposit, Note: "Salary", CategoryID: "category123", Balance: money("1000.50"), TransactionID: "n1"},
+				},
+			},
+		},
+		{
+			name: "Dry run writes nothing",
+			req:  request("Date;Amount;Description\n20.01.2025;10;Refund\n", true),
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().ListExternalIDsTX(ctx, mockTx, "budget123", gomock.Len(1)).Return(nil, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", date(time.January, 20)).Return(history(), nil)
+			},
+			expectedRes: &ImportTransactionsResponse{
+				DryRun: true,
+				Total:  1,
+				Rows: []ImportRowResult{
+					{Line: 2, Date: func() *time.Time { d := date(time.January, 20); return &d }(), Amount: domain.MustParseMoney("10.00"), Type: e_transaction_type.Deposit, Note: "Refund", CategoryID: "category123", Balance: money("110.00")},
+				},
+			},
+		},
+		{
+			name: "Failed rows abort the import",
+			req:  request("Date;Amount;Description\n2025-01-20;10;Wrong date\n20.01.2025;-90;Would overdraw a later balance\n", false),
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().ListExternalIDsTX(ctx, mockTx, "budget123", gomock.Len(1)).Return(nil, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", date(time.January, 20)).Return(history(), nil)
+			},
+			expectedRes: &ImportTransactionsResponse{
+				Total:  2,
+				Failed: 2,
+				Rows: []ImportRowResult{
+					{Line: 2, Error: `invalid date "2025-01-20"`},
+					{Line: 3, Date: func() *time.Time { d := date(time.January, 20); return &d }(), Amount: domain.MustParseMoney("90.00"), Type: e_transaction_type.Withdrawal, Note: "Would overdraw a later balance", CategoryID: "category123", Error: "Insufficient balance"},
+				},
+			},
+		},
+		{
+			name: "Future-dated rows fail",
+			req:  request("Date;Amount;Description\n01.01.2999;10;Not yet\n", false),
+			mockSetup: func() {
+				future := time.Date(2999, time.January, 1, 0, 0, 0, 0, time.UTC)
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().ListExternalIDsTX(ctx, mockTx, "budget123", gomock.Len(1)).Return(nil, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", future).Return(history()[1:], nil)
+			},
+			expectedRes: &ImportTransactionsResponse{
+				Total:  1,
+				Failed: 1,
+				Rows: []ImportRowResult{
+					{Line: 2, Date: func() *time.Time { d := time.Date(2999, time.January, 1, 0, 0, 0, 0, time.UTC); return &d }(), Amount: domain.MustParseMoney("10.00"), Type: e_transaction_type.Deposit, Note: "Not yet", CategoryID: "category123", Error: "Transactions can't be booked in the future"},
+				},
+			},
+		},
+		{
+			name: "OFX rows already imported are skipped",
+			req: &ImportTransactionsRequest{
+				UserID:     "user123",
+				BudgetID:   "budget123",
+				CategoryID: "category123",
+				Format:     ImportFormatOFX,
+				File: strings.NewReader(`OFXHEADER:100
+DATA:OFXSGML
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250120120000.000[-5:EST]
+<TRNAMT>-25.00
+<FITID>A1
+<NAME>Coffee &amp; Co
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20250121
+<TRNAMT>40.00
+<FITID>A2
+<NAME>Refund
+<MEMO>Order 42
+</STMTTRN>
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().ListExternalIDsTX(ctx, mockTx, "budget123", []string{"fitid:A1", "fitid:A2"}).Return([]string{"fitid:A1"}, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", date(time.January, 21)).Return(history()[:1], nil)
+				mockTransactionRepo.EXPECT().ImportTX(ctx, mockTx, &domain.Transaction{
+					UserID: "user123", CreatedBy: sql.NullString{String: "user123", Valid: true}, BudgetID: "budget123", CategoryID: "category123",
+					Amount: domain.MustParseMoney("40.00"), TransactionType: "deposit", Note: "Refund - Order 42",
+					ExternalID: sql.NullString{String: "fitid:A2", Valid: true}, CreatedAt: date(time.January, 21),
+				}).Return("n1", nil)
+				mockBudgetHistoryRepo.EXPECT().CreateAtTX(ctx, mockTx, "budget123", "n1", domain.MustParseMoney("140.00"), date(time.January, 21)).Return("hn1", nil)
+			},
+			expectedRes: &ImportTransactionsResponse{
+				Total:    2,
+				Imported: 1,
+				Skipped:  1,
+				Rows: []ImportRowResult{
+					{Line: 6, Date: func() *time.Time { d := time.Date(2025, time.January, 20, 17, 0, 0, 0, time.UTC); return &d }(), Amount: domain.MustParseMoney("25.00"), Type: e_transaction_type.Withdrawal, Note: "Coffee & Co", CategoryID: "category123", ExternalID: "fitid:A1", Duplicate: true},
+					{Line: 13, Date: func() *time.Time { d := date(time.January, 21); return &d }(), Amount: domain.MustParseMoney("40.00"), Type: e_transaction_type.Deposit, Note: "Refund - Order 42", CategoryID: "category123", Balance: money("140.00"), ExternalID: "fitid:A2", TransactionID: "n1"},
+				},
+			},
+		},
+		{
+			name: "QIF dry run",
+			req: &ImportTransactionsRequest{
+				UserID:     "user123",
+				BudgetID:   "budget123",
+				CategoryID: "category123",
+				Format:     ImportFormatQIF,
+				DryRun:     true,
+				File: strings.NewReader(`!Type:Cat
+NGroceries
+^
+!Type:Bank
+D1/20'25
+T-1,250.00
+PLandlord
+MRent
+^
+`),
+			},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().ListExternalIDsTX(ctx, mockTx, "budget123", gomock.Len(1)).Return(nil, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", date(time.January, 20)).Return(history()[:1], nil)
+			},
+			expectedRes: &ImportTransactionsResponse{
+				DryRun: true,
+				Total:  1,
+				Failed: 1,
+				Rows: []ImportRowResult{
+					{Line: 5, Date: func() *time.Time { d := date(time.January, 20); return &d }(), Amount: domain.MustParseMoney("1250.00"), Type: e_transaction_type.Withdrawal, Note: "Landlord - Rent", CategoryID: "category123", Error: "Insufficient balance"},
+				},
+			},
+		},
+		{
+			name: "Rules categorize rows without a category",
+			req: func() *ImportTransactionsRequest {
+				req := request("Date;Amount;Description\n20.01.2025;-9,99;NETFLIX.COM\n21.01.2025;-5,00;Kiosk\n", true)
+				req.CategoryID = ""
+				return req
+			}(),
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user123").Return([]*domain.TransactionRule{
+					{ID: "rule1", NotePattern: sql.NullString{String: "^netflix", Valid: true}, MinAmount: money("9.99"), MaxAmount: money("9.99"), CategoryID: sql.NullString{String: "entertainment", Valid: true}, Tag: sql.NullString{String: "subscription", Valid: true}},
+				}, nil)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockTransactionRepo.EXPECT().ListExternalIDsTX(ctx, mockTx, "budget123", gomock.Len(1)).Return(nil, nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget123").Return(nil)
+				mockBudgetHistoryRepo.EXPECT().ListSinceTX(ctx, mockTx, "budget123", date(time.January, 20)).Return(history(), nil)
+			},
+			expectedRes: &ImportTransactionsResponse{
+				DryRun: true,
+				Total:  2,
+				Failed: 1,
+				Rows: []ImportRowResult{
+					{Line: 2, Date: func() *time.Time { d := date(time.January, 20); return &d }(), Amount: domain.MustParseMoney("9.99"), Type: e_transaction_type.Withdrawal, Note: "NETFLIX.COM", CategoryID: "entertainment", Tags: []string{"subscription"}, Balance: money("90.01")},
+					{Line: 3, Date: func() *time.Time { d := date(time.January, 21); return &d }(), Amount: domain.MustParseMoney("5.00"), Type: e_transaction_type.Withdrawal, Note: "Kiosk", Error: "No transaction rule sets a category; category_id is required"},
+				},
+			},
+		},
+		{
+			name: "Unknown column",
+			req: func() *ImportTransactionsRequest {
+				req := request("Date;Sum\n20.01.2025;10\n", false)
+				req.AmountColumn = "Amount"
+				return req
+			}(),
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.InvalidImportMapping,
+		},
+		{
+			name: "Custom category of another user",
+			req: func() *ImportTransactionsRequest {
+				req := request("Date;Amount\n20.01.2025;10\n", false)
+				req.CategoryID = "foreign"
+				return req
+			}(),
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Foreign budget",
+			req:  request("Date;Amount\n", false),
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.BudgetNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			mockTxExec := &mockTransactionExecutor{
+				withTx: func(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+					return fn(mockTx)
+				},
+			}
+
+			service := NewService(mockTransactionRepo, mockBudgetRepo, mockBudgetHistoryRepo, mockCategoryRepo, mockCategoryLimitRepo, mockRuleRepo, mockTxExec)
+
+			resp, err := service.Import(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				for i, row := range resp.Rows {
+					if i < len(tt.expectedRes.Rows) && tt.expectedRes.Rows[i].ExternalID == "" && strings.HasPrefix(row.ExternalID, "sha256:") {
+						resp.Rows[i].ExternalID = ""
+					}
+				}
+				assert.Equal(t, tt.expectedRes, resp)
+			}
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockBudgetHistoryRepo := mock.NewMockBudgetHistory(ctrl)
+	mockCategoryLimitRepo := mock_category_limit.NewMockCategoryLimit(ctrl)
+
+	balance := domain.MustParseMoney("70.00")
+	rows := []*domain.TransactionExport{
+		{
+			Transaction: domain.Transaction{
+				ID: "t1", UserID: "user123", BudgetID: "budget123", CategoryID: "category123",
+				Amount: domain.MustParseMoney("30.00"), TransactionType: "withdrawal", Note: "=SUM(A1:A2) & <co>",
+				CreatedAt: time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC),
+			},
+			CategoryName: "Food & Drink",
+			Balance:      &balance,
+		},
+		{
+			Transaction: domain.Transaction{
+				ID: "t2", UserID: "user123", BudgetID: "budget123", CategoryID: "category123",
+				Amount: domain.MustParseMoney("12.50"), TransactionType: "deposit",
+				CreatedAt: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	stream := func(filter transaction.ListFilter, fn func(*domain.TransactionExport) error) error {
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name          string
+		req           *ExportTransactionsRequest
+		setupMocks    func()
+		expectedErr   error
+		expectedCount int
+		contains      []string
+	}{
+		{
+			name: "CSV oldest first",
+			req:  &ExportTransactionsRequest{UserID: "user123", BudgetID: "budget123"},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner"}, nil)
+				mockTransactionRepo.EXPECT().Export(ctx, transaction.ListFilter{
+					UserID: "user123", BudgetID: "budget123", SortBy: transaction.SortByCreatedAt,
+				}, gomock.Any()).DoAndReturn(func(_ context.Context, filter transaction.ListFilter, fn func(*domain.TransactionExport) error) error {
+					return stream(filter, fn)
+				})
+			},
+			expectedCount: 2,
+			contains: []string{
+				"id,date,budget_id,category_id,category,type,amount,balance,note,transfer_id,external_id\n",
+				"t1,2025-01-05T10:00:00Z,budget123,category123,Food & Drink,withdrawal,30.00,70.00,'=SUM(A1:A2) & <co>,,\n",
+				"t2,2025-01-06T00:00:00Z,budget123,category123,,deposit,12.50,,,,\n",
+			},
+		},
+		{
+			name: "JSON Lines",
+			req:  &ExportTransactionsRequest{UserID: "user123", Format: ExportFormatJSONL, Type: "withdrawal", Sort: SortAmountDesc},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").Return([]*domain.Budget{{ID: "budget123", UserID: "user123"}}, nil)
+				mockTransactionRepo.EXPECT().Export(ctx, transaction.ListFilter{
+					UserID: "user123", Type: "withdrawal", SortBy: transaction.SortByAmount, SortDesc: true,
+				}, gomock.Any()).DoAndReturn(func(_ context.Context, filter transaction.ListFilter, fn func(*domain.TransactionExport) error) error {
+					return stream(filter, fn)
+				})
+			},
+			expectedCount: 2,
+			contains: []string{
+				`"id":"t1"`, `"category_name":"Food \u0026 Drink","balance":70.00}` + "\n",
+				`"id":"t2"`, `"category_name":""}` + "\n",
+			},
+		},
+		{
+			name: "OFX statement",
+			req:  &ExportTransactionsRequest{UserID: "user123", Format: ExportFormatOFX, BudgetID: "budget123", From: "2025-01-01T00:00:00Z", To: "2025-01-31T00:00:00Z"},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Currency: "EUR", Role: "owner"}, nil)
+				mockBudgetHistoryRepo.EXPECT().GetCurrentBalance(ctx, "budget123").Return(domain.MustParseMoney("82.50"), nil)
+				mockTransactionRepo.EXPECT().Export(ctx, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, filter transaction.ListFilter, fn func(*domain.TransactionExport) error) error {
+						return stream(filter, fn)
+					})
+			},
+			expectedCount: 2,
+			contains: []string{
+				"<CURDEF>EUR</CURDEF>",
+				"<ACCTID>budget123</ACCTID>",
+				"<DTSTART>20250101000000.000[0:GMT]</DTSTART><DTEND>20250131000000.000[0:GMT]</DTEND>",
+				"<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20250105100000.000[0:GMT]</DTPOSTED><TRNAMT>-30.00</TRNAMT><FITID>t1</FITID>" +
+					"<NAME>Food &amp; Drink</NAME><MEMO>=SUM(A1:A2) &amp; &lt;co&gt;</MEMO></STMTTRN>",
+				"<STMTTRN><TRNTYPE>CREDIT</TRNTYPE><DTPOSTED>20250106000000.000[0:GMT]</DTPOSTED><TRNAMT>12.50</TRNAMT><FITID>t2</FITID></STMTTRN>",
+				"<LEDGERBAL><BALAMT>70.00</BALAMT><DTASOF>20250105100000.000[0:GMT]</DTASOF></LEDGERBAL>",
+				"</OFX>\n",
+			},
+		},
+		{
+			name:        "OFX requires a budget",
+			req:         &ExportTransactionsRequest{UserID: "user123", Format: ExportFormatOFX},
+			setupMocks:  func() {},
+			expectedErr: errs.ExportBudgetRequired,
+		},
+		{
+			name: "OFX budget not found",
+			req:  &ExportTransactionsRequest{UserID: "user123", Format: ExportFormatOFX, BudgetID: "budget123"},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.BudgetNotFound,
+		},
+		{
+			name:        "Invalid date range",
+			req:         &ExportTransactionsRequest{UserID: "user123", From: "2025-02-01T00:00:00Z", To: "2025-01-01T00:00:00Z"},
+			setupMocks:  func() {},
+			expectedErr: errs.InvalidInput,
+		},
+		{
+			name: "Database error",
+			req:  &ExportTransactionsRequest{UserID: "user123"},
+			setupMocks: func() {
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").Return(nil, nil)
+				mockTransactionRepo.EXPECT().Export(ctx, gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			var out strings.Builder
+			tt.req.Output = &out
+
+			service := NewService(mockTransactionRepo, mockBudgetRepo, mockBudgetHistoryRepo, nil, mockCategoryLimitRepo, nil, transactionExec.NewTransactionExecutor())
+			res, err := service.Export(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, res)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCount, res.Exported)
+			for _, fragment := range tt.contains {
+				assert.Contains(t, out.String(), fragment)
+			}
+		})
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		raw      string
+		decimal  byte
+		expected string
+		wantErr  bool
+	}{
+		{raw: "1,234.56", decimal: '.', expected: "1234.56"},
+		{raw: "1.234,56", decimal: ',', expected: "1234.56"},
+		{raw: "-12.00", decimal: '.', expected: "-12.00"},
+		{raw: "12.00-", decimal: '.', expected: "-12.00"},
+		{raw: "(7.5)", decimal: '.', expected: "-7.50"},
+		{raw: "1 000,00", decimal: ',', expected: "1000.00"},
+		{raw: "+3", decimal: '.', expected: "3.00"},
+		{raw: "-.5", decimal: '.', expected: "-0.50"},
+		{raw: ",25", decimal: ',', expected: "0.25"},
+		{raw: "1.005", decimal: '.', wantErr: true},
+		{raw: "abc", decimal: '.', wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			m, err := parseDecimal(tt.raw, tt.decimal)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, m.String())
+		})
+	}
+}
+
+func TestAssignExternalIDs(t *testing.T) {
+	newRows := func() []*importRow {
+		date := time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)
+		return []*importRow{
+			{line: 1, date: date, amount: domain.MustParseMoney("3.50"), typ: e_transaction_type.Withdrawal, note: "Coffee"},
+			{line: 2, date: date, amount: domain.MustParseMoney("3.50"), typ: e_transaction_type.Withdrawal, note: "Coffee"},
+			{line: 3, date: date, amount: domain.MustParseMoney("9.00"), typ: e_transaction_type.Withdrawal, externalID: "fitid:X"},
+			{line: 4, date: date, amount: domain.MustParseMoney("9.00"), typ: e_transaction_type.Withdrawal, externalID: "fitid:X"},
+			{line: 5, err: "invalid date"},
+		}
+	}
+
+	first, second := newRows(), newRows()
+	assignExternalIDs(first)
+	assignExternalIDs(second)
+
+	assert.NotEqual(t, first[0].externalID, first[1].externalID, "identical rows in one file are distinct")
+	assert.False(t, first[1].duplicate)
+	assert.Equal(t, first[0].externalID, second[0].externalID, "hashes are stable across imports")
+	assert.Equal(t, first[1].externalID, second[1].externalID)
+	assert.False(t, first[2].duplicate)
+	assert.True(t, first[3].duplicate, "a repeated FITID is a duplicate")
+	assert.Empty(t, first[4].externalID)
+}
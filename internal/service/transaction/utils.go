@@ -1,76 +1,221 @@
 package transaction
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"finly-backend/internal/domain"
 	"finly-backend/internal/domain/enums/e_transaction_type"
+	"finly-backend/internal/repository/transaction"
+	"time"
 )
 
 // invertDelta calculates the signed value of a transaction based on its type.
 // If invert is true, the sign of the delta is reversed.
-func invertDelta(transactionType string, amount float64, invert bool) (float64, error) {
+func invertDelta(transactionType string, amount domain.Money, invert bool) (domain.Money, error) {
 	switch transactionType {
-	case e_transaction_type.Deposit.String():
+	case e_transaction_type.Deposit.String(), e_transaction_type.TransferIn.String():
 		if invert {
 			return amount, nil
 		}
-		return -amount, nil
-	case e_transaction_type.Withdrawal.String():
+		return amount.Neg(), nil
+	case e_transaction_type.Withdrawal.String(), e_transaction_type.TransferOut.String():
 		if invert {
-			return -amount, nil
+			return amount.Neg(), nil
 		}
 		return amount, nil
 	default:
-		return 0, errs.InvalidTransactionType
+		return domain.Money{}, errs.InvalidTransactionType
 	}
 }
 
 // calculateNewAmount returns the new balance after applying the transaction.
 // It takes into account the transaction type and current budget balance.
-func calculateNewAmount(budgetHistory *domain.BudgetHistory, amount float64, transactionType string) (float64, error) {
-	var newAmount float64
+func calculateNewAmount(budgetHistory *domain.BudgetHistory, amount domain.Money, transactionType string) (domain.Money, error) {
+	var (
+		newAmount domain.Money
+		err       error
+	)
 	switch transactionType {
-	case e_transaction_type.Deposit.String():
+	case e_transaction_type.Deposit.String(), e_transaction_type.TransferIn.String():
 		if budgetHistory == nil {
 			newAmount = amount
 		} else {
-			newAmount = budgetHistory.Balance + amount
+			newAmount, err = budgetHistory.Balance.Add(amount)
 		}
-	case e_transaction_type.Withdrawal.String():
-		if budgetHistory == nil || budgetHistory.Balance < amount {
-			return 0, errs.InsufficientBalance
+	case e_transaction_type.Withdrawal.String(), e_transaction_type.TransferOut.String():
+		if budgetHistory == nil || budgetHistory.Balance.LessThan(amount) {
+			return domain.Money{}, errs.InsufficientBalance
 		}
-		newAmount = budgetHistory.Balance - amount
+		newAmount, err = budgetHistory.Balance.Sub(amount)
 	default:
-		return 0, errs.InvalidTransactionType
+		return domain.Money{}, errs.InvalidTransactionType
+	}
+	if err != nil {
+		return domain.Money{}, errs.AmountOutOfRange
 	}
 	return newAmount, nil
 }
 
 // calculateDelta returns the balance delta for a transaction based on its type.
 // Deposit increases balance, Withdrawal decreases it.
-func calculateDelta(transactionType string, amount float64) (float64, error) {
+func calculateDelta(transactionType string, amount domain.Money) (domain.Money, error) {
 	switch transactionType {
-	case e_transaction_type.Deposit.String():
+	case e_transaction_type.Deposit.String(), e_transaction_type.TransferIn.String():
 		return amount, nil
-	case e_transaction_type.Withdrawal.String():
-		return -amount, nil
+	case e_transaction_type.Withdrawal.String(), e_transaction_type.TransferOut.String():
+		return amount.Neg(), nil
 	default:
-		return 0, errs.InvalidTransactionType
+		return domain.Money{}, errs.InvalidTransactionType
 	}
 }
 
 // calculateDeltaChange returns the net balance difference caused by changing a transaction.
 // Useful when updating a transaction from one type/amount to another.
-func calculateDeltaChange(oldType string, oldAmount float64, newType string, newAmount float64) (float64, error) {
+func calculateDeltaChange(oldType string, oldAmount domain.Money, newType string, newAmount domain.Money) (domain.Money, error) {
 	oldDelta, err := calculateDelta(oldType, oldAmount)
 	if err != nil {
-		return 0, err
+		return domain.Money{}, err
 	}
 
 	newDelta, err := calculateDelta(newType, newAmount)
 	if err != nil {
-		return 0, err
+		return domain.Money{}, err
+	}
+
+	difference, err := newDelta.Sub(oldDelta)
+	if err != nil {
+		return domain.Money{}, errs.AmountOutOfRange
+	}
+	return difference, nil
+}
+
+func convertTransaction(t *domain.Transaction) TransactionObject {
+	return TransactionObject{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		CreatedBy:  t.CreatedBy.String,
+		BudgetID:   t.BudgetID,
+		CategoryID: t.CategoryID,
+		Type:       e_transaction_type.Enum(t.TransactionType),
+		Note:       t.Note,
+		Amount:     t.Amount,
+		TransferID: t.TransferID.String,
+		ExternalID: t.ExternalID.String,
+		Tags:       t.Tags,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+// listCursor is the opaque pagination token handed out as next_cursor.
+// The sort order is embedded so a cursor cannot be replayed against a different ordering.
+type listCursor struct {
+	Sort      string       `json:"s"`
+	CreatedAt time.Time    `json:"t"`
+	Amount    domain.Money `json:"a"`
+	ID        string       `json:"id"`
+}
+
+func encodeCursor(sort string, last *domain.Transaction) string {
+	data, _ := json.Marshal(listCursor{
+		Sort:      sort,
+		CreatedAt: last.CreatedAt,
+		Amount:    last.Amount,
+		ID:        last.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(sort, raw string) (*transaction.ListCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errs.InvalidCursor
+	}
+
+	var c listCursor
+	if err = json.Unmarshal(data, &c); err != nil || c.Sort != sort || c.ID == "" {
+		return nil, errs.InvalidCursor
+	}
+
+	return &transaction.ListCursor{CreatedAt: c.CreatedAt, Amount: c.Amount, ID: c.ID}, nil
+}
+
+// listSort returns the requested sort order, defaulting to newest first.
+func listSort(sort string) string {
+	if sort == "" {
+		return SortCreatedAtDesc
+	}
+	return sort
+}
+
+// buildListFilter translates the query parameters of a list request into a repository filter.
+func buildListFilter(req *ListTransactionRequest) (transaction.ListFilter, error) {
+	filter := transaction.ListFilter{
+		UserID:     req.UserID,
+		BudgetID:   req.BudgetID,
+		CategoryID: req.CategoryID,
+		Type:       req.Type,
+		Note:       req.Query,
+		Limit:      req.Limit,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultListLimit
+	}
+
+	sort := listSort(req.Sort)
+	switch sort {
+	case SortCreatedAtDesc, SortCreatedAtAsc:
+		filter.SortBy = transaction.SortByCreatedAt
+	case SortAmountDesc, SortAmountAsc:
+		filter.SortBy = transaction.SortByAmount
+	default:
+		return filter, errs.InvalidInput
+	}
+	filter.SortDesc = sort == SortCreatedAtDesc || sort == SortAmountDesc
+
+	for _, bound := range []struct {
+		raw string
+		dst **time.Time
+	}{{req.From, &filter.From}, {req.To, &filter.To}} {
+		if bound.raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.raw)
+		if err != nil {
+			return filter, errs.InvalidInput
+		}
+		t = t.UTC()
+		*bound.dst = &t
+	}
+
+	for _, bound := range []struct {
+		raw string
+		dst **domain.Money
+	}{{req.MinAmount, &filter.MinAmount}, {req.MaxAmount, &filter.MaxAmount}} {
+		if bound.raw == "" {
+			continue
+		}
+		m, err := domain.ParseMoney(bound.raw)
+		if err != nil {
+			return filter, errs.InvalidInput
+		}
+		*bound.dst = &m
+	}
+
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return filter, errs.InvalidInput
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && filter.MaxAmount.LessThan(*filter.MinAmount) {
+		return filter, errs.InvalidInput
+	}
+
+	if req.Cursor != "" {
+		after, err := decodeCursor(sort, req.Cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = after
 	}
 
-	return newDelta - oldDelta, nil
+	return filter, nil
 }
@@ -0,0 +1,30 @@
+package transaction_rule
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+var errs = struct {
+	RuleNotFound       *echo.HTTPError
+	BudgetNotFound     *echo.HTTPError
+	BudgetForbidden    *echo.HTTPError
+	BudgetArchived     *echo.HTTPError
+	CategoryNotFound   *echo.HTTPError
+	MissingAction      *echo.HTTPError
+	InvalidAmountRange *echo.HTTPError
+	InvalidNotePattern *echo.HTTPError
+	InvalidDateRange   *echo.HTTPError
+	DatabaseError      *echo.HTTPError
+}{
+	RuleNotFound:       echo.NewHTTPError(http.StatusNotFound, "Transaction rule not found"),
+	BudgetNotFound:     echo.NewHTTPError(http.StatusNotFound, "Budget not found"),
+	BudgetForbidden:    echo.NewHTTPError(http.StatusForbidden, "Your role in this budget does not allow this"),
+	BudgetArchived:     echo.NewHTTPError(http.StatusConflict, "Budget is archived; unarchive it to make changes"),
+	CategoryNotFound:   echo.NewHTTPError(http.StatusNotFound, "Category not found"),
+	MissingAction:      echo.NewHTTPError(http.StatusBadRequest, "A rule must set a category or a tag"),
+	InvalidAmountRange: echo.NewHTTPError(http.StatusBadRequest, "Invalid amount range"),
+	InvalidNotePattern: echo.NewHTTPError(http.StatusBadRequest, "Invalid note pattern"),
+	InvalidDateRange:   echo.NewHTTPError(http.StatusBadRequest, "Invalid date range"),
+	DatabaseError:      echo.NewHTTPError(http.StatusInternalServerError, "Database operation failed"),
+}
@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/transaction_rule/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/transaction_rule/service.go -destination=internal/service/transaction_rule/mock/mock_transaction_rule.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	transaction_rule "finly-backend/internal/service/transaction_rule"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransactionRule is a mock of TransactionRule interface.
+type MockTransactionRule struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionRuleMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionRuleMockRecorder is the mock recorder for MockTransactionRule.
+type MockTransactionRuleMockRecorder struct {
+	mock *MockTransactionRule
+}
+
+// NewMockTransactionRule creates a new mock instance.
+func NewMockTransactionRule(ctrl *gomock.Controller) *MockTransactionRule {
+	mock := &MockTransactionRule{ctrl: ctrl}
+	mock.recorder = &MockTransactionRuleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionRule) EXPECT() *MockTransactionRuleMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockTransactionRule) Apply(ctx context.Context, req *transaction_rule.ApplyRulesRequest) (*transaction_rule.ApplyRulesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, req)
+	ret0, _ := ret[0].(*transaction_rule.ApplyRulesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockTransactionRuleMockRecorder) Apply(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockTransactionRule)(nil).Apply), ctx, req)
+}
+
+// Create mocks base method.
+func (m *MockTransactionRule) Create(ctx context.Context, req *transaction_rule.CreateRuleRequest) (*transaction_rule.CreateRuleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*transaction_rule.CreateRuleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransactionRuleMockRecorder) Create(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactionRule)(nil).Create), ctx, req)
+}
+
+// Delete mocks base method.
+func (m *MockTransactionRule) Delete(ctx context.Context, req *transaction_rule.DeleteRuleRequest) (*transaction_rule.DeleteRuleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, req)
+	ret0, _ := ret[0].(*transaction_rule.DeleteRuleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTransactionRuleMockRecorder) Delete(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTransactionRule)(nil).Delete), ctx, req)
+}
+
+// GetByID mocks base method.
+func (m *MockTransactionRule) GetByID(ctx context.Context, req *transaction_rule.GetRuleByIDRequest) (*transaction_rule.GetRuleByIDResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, req)
+	ret0, _ := ret[0].(*transaction_rule.GetRuleByIDResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTransactionRuleMockRecorder) GetByID(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTransactionRule)(nil).GetByID), ctx, req)
+}
+
+// List mocks base method.
+func (m *MockTransactionRule) List(ctx context.Context, req *transaction_rule.ListRulesRequest) (*transaction_rule.ListRulesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(*transaction_rule.ListRulesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransactionRuleMockRecorder) List(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransactionRule)(nil).List), ctx, req)
+}
+
+// Update mocks base method.
+func (m *MockTransactionRule) Update(ctx context.Context, req *transaction_rule.UpdateRuleRequest) (*transaction_rule.UpdateRuleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, req)
+	ret0, _ := ret[0].(*transaction_rule.UpdateRuleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTransactionRuleMockRecorder) Update(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTransactionRule)(nil).Update), ctx, req)
+}
@@ -0,0 +1,122 @@
+package transaction_rule
+
+import (
+	"database/sql"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_transaction_type"
+	"time"
+)
+
+type RuleObject struct {
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	Priority     int                     `json:"priority"`
+	NoteContains string                  `json:"note_contains,omitempty"`
+	NotePattern  string                  `json:"note_pattern,omitempty"`
+	MinAmount    *domain.Money           `json:"min_amount,omitempty" swaggertype:"number"`
+	MaxAmount    *domain.Money           `json:"max_amount,omitempty" swaggertype:"number"`
+	Type         e_transaction_type.Enum `json:"type,omitempty"`
+	BudgetID     string                  `json:"budget_id,omitempty"`
+	CategoryID   string                  `json:"category_id,omitempty"`
+	Tag          string                  `json:"tag,omitempty"`
+	CreatedAt    time.Time               `json:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+// RuleDefinition holds the conditions and actions of a rule. Conditions left empty match
+// every transaction; at least one action must be set. Rules run in ascending priority, and
+// a rule created without one runs after the existing rules.
+type RuleDefinition struct {
+	Name         string                  `json:"name" validate:"required,max=100"`
+	Priority     *int                    `json:"priority,omitempty" validate:"omitempty,min=0"`
+	NoteContains string                  `json:"note_contains,omitempty" validate:"max=255"`
+	NotePattern  string                  `json:"note_pattern,omitempty" validate:"max=255"`
+	MinAmount    *domain.Money           `json:"min_amount,omitempty" swaggertype:"number"`
+	MaxAmount    *domain.Money           `json:"max_amount,omitempty" swaggertype:"number"`
+	Type         e_transaction_type.Enum `json:"type,omitempty" validate:"omitempty,oneof=deposit withdrawal"`
+	BudgetID     string                  `json:"budget_id,omitempty" validate:"omitempty,uuid"`
+	CategoryID   string                  `json:"category_id,omitempty" validate:"omitempty,uuid"`
+	Tag          string                  `json:"tag,omitempty" validate:"max=50"`
+}
+
+type CreateRuleRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	RuleDefinition
+}
+
+type CreateRuleResponse struct {
+	ID string `json:"id"`
+}
+
+type ListRulesRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+}
+
+type ListRulesResponse struct {
+	Rules []*RuleObject `json:"rules"`
+}
+
+type GetRuleByIDRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	ID     string `param:"id" validate:"required"`
+}
+
+type GetRuleByIDResponse struct {
+	*RuleObject
+}
+
+// UpdateRuleRequest replaces the definition of a rule. A rule updated without a priority
+// keeps its current one.
+type UpdateRuleRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	ID     string `param:"id" validate:"required"`
+	RuleDefinition
+}
+
+type UpdateRuleResponse struct{}
+
+type DeleteRuleRequest struct {
+	UserID string `header:"User-Id" validate:"required"`
+	ID     string `param:"id" validate:"required"`
+}
+
+type DeleteRuleResponse struct{}
+
+// ApplyRulesRequest selects the past transactions to run the rules over: those of a budget
+// the user can edit, or otherwise every transaction the user owns, optionally within a
+// creation date range.
+type ApplyRulesRequest struct {
+	UserID   string     `header:"User-Id" validate:"required"`
+	BudgetID string     `json:"budget_id,omitempty" validate:"omitempty,uuid"`
+	From     *time.Time `json:"from,omitempty"`
+	To       *time.Time `json:"to,omitempty"`
+}
+
+// ApplyRulesResponse reports how many transactions were checked and how many of them got
+// a different category or new tags. Transfers are never changed.
+type ApplyRulesResponse struct {
+	Checked int `json:"checked"`
+	Updated int `json:"updated"`
+}
+
+func convertRule(rule *domain.TransactionRule) *RuleObject {
+	return &RuleObject{
+		ID:           rule.ID,
+		Name:         rule.Name,
+		Priority:     rule.Priority,
+		NoteContains: rule.NoteContains.String,
+		NotePattern:  rule.NotePattern.String,
+		MinAmount:    rule.MinAmount,
+		MaxAmount:    rule.MaxAmount,
+		Type:         e_transaction_type.Enum(rule.TransactionType.String),
+		BudgetID:     rule.BudgetID.String,
+		CategoryID:   rule.CategoryID.String,
+		Tag:          rule.Tag.String,
+		CreatedAt:    rule.CreatedAt,
+		UpdatedAt:    rule.UpdatedAt,
+	}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
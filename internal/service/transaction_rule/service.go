@@ -0,0 +1,362 @@
+package transaction_rule
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_budget_role"
+	"finly-backend/internal/repository/budget"
+	"finly-backend/internal/repository/category"
+	"finly-backend/internal/repository/transaction"
+	"finly-backend/internal/repository/transaction_rule"
+	transactionExec "finly-backend/pkg/transaction"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+	"slices"
+)
+
+type TransactionRule interface {
+	Create(ctx context.Context, req *CreateRuleRequest) (*CreateRuleResponse, error)
+	List(ctx context.Context, req *ListRulesRequest) (*ListRulesResponse, error)
+	GetByID(ctx context.Context, req *GetRuleByIDRequest) (*GetRuleByIDResponse, error)
+	Update(ctx context.Context, req *UpdateRuleRequest) (*UpdateRuleResponse, error)
+	Delete(ctx context.Context, req *DeleteRuleRequest) (*DeleteRuleResponse, error)
+	Apply(ctx context.Context, req *ApplyRulesRequest) (*ApplyRulesResponse, error)
+}
+
+type Service struct {
+	ruleRepo        transaction_rule.TransactionRule
+	budgetRepo      budget.Budget
+	categoryRepo    category.Category
+	transactionRepo transaction.Transaction
+
+	transactionExecutor transactionExec.TransactionExecutor
+}
+
+func NewService(ruleRepo transaction_rule.TransactionRule, budgetRepo budget.Budget, categoryRepo category.Category, transactionRepo transaction.Transaction, transactionExecutor transactionExec.TransactionExecutor) *Service {
+	return &Service{
+		ruleRepo:            ruleRepo,
+		budgetRepo:          budgetRepo,
+		categoryRepo:        categoryRepo,
+		transactionRepo:     transactionRepo,
+		transactionExecutor: transactionExecutor,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, req *CreateRuleRequest) (*CreateRuleResponse, error) {
+	rule := &domain.TransactionRule{UserID: req.UserID}
+	if err := s.define(ctx, rule, &req.RuleDefinition); err != nil {
+		return nil, err
+	}
+
+	if req.Priority == nil {
+		rules, err := s.ruleRepo.ListByUserID(ctx, req.UserID)
+		if err != nil {
+			zap.L().Sugar().Errorf("Create: failed to list rules for userID=%s: %v", req.UserID, err)
+			return nil, errs.DatabaseError
+		}
+		if len(rules) > 0 {
+			rule.Priority = rules[len(rules)-1].Priority + 1
+		}
+	}
+
+	id, err := s.ruleRepo.Create(ctx, rule)
+	if err != nil {
+		zap.L().Sugar().Errorf("Create: failed to create transaction rule for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("Create: transaction rule %s created for userID=%s", id, req.UserID)
+	return &CreateRuleResponse{ID: id}, nil
+}
+
+func (s *Service) List(ctx context.Context, req *ListRulesRequest) (*ListRulesResponse, error) {
+	rules, err := s.ruleRepo.ListByUserID(ctx, req.UserID)
+	if err != nil {
+		zap.L().Sugar().Errorf("List: failed for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	list := make([]*RuleObject, 0, len(rules))
+	for _, rule := range rules {
+		list = append(list, convertRule(rule))
+	}
+
+	return &ListRulesResponse{Rules: list}, nil
+}
+
+func (s *Service) GetByID(ctx context.Context, req *GetRuleByIDRequest) (*GetRuleByIDResponse, error) {
+	rule, err := s.getOwnedRule(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetRuleByIDResponse{convertRule(rule)}, nil
+}
+
+func (s *Service) Update(ctx context.Context, req *UpdateRuleRequest) (*UpdateRuleResponse, error) {
+	rule, err := s.getOwnedRule(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.define(ctx, rule, &req.RuleDefinition); err != nil {
+		return nil, err
+	}
+
+	if err = s.ruleRepo.Update(ctx, rule); err != nil {
+		zap.L().Sugar().Errorf("Update: failed for ruleID=%s, userID=%s: %v", req.ID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("Update: completed for ruleID=%s, userID=%s", req.ID, req.UserID)
+	return &UpdateRuleResponse{}, nil
+}
+
+func (s *Service) Delete(ctx context.Context, req *DeleteRuleRequest) (*DeleteRuleResponse, error) {
+	if _, err := s.getOwnedRule(ctx, req.ID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Delete(ctx, req.ID, req.UserID); err != nil {
+		zap.L().Sugar().Errorf("Delete: failed for ruleID=%s, userID=%s: %v", req.ID, req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	zap.L().Sugar().Infof("Delete: completed for ruleID=%s, userID=%s", req.ID, req.UserID)
+	return &DeleteRuleResponse{}, nil
+}
+
+// Apply runs the user's rules over past transactions. A matching rule that sets a category
+// replaces the current one, and the tags of matching rules are added to those already set.
+// The transactions are read first, then locked, read again and written in one database transaction.
+func (s *Service) Apply(ctx context.Context, req *ApplyRulesRequest) (*ApplyRulesResponse, error) {
+	if req.From != nil && req.To != nil && req.To.Before(*req.From) {
+		return nil, errs.InvalidDateRange
+	}
+
+	filter := transaction.ListFilter{UserID: req.UserID, BudgetID: req.BudgetID, From: req.From, To: req.To}
+	if req.BudgetID != "" {
+		budget, err := s.getBudget(ctx, req.BudgetID, req.UserID, e_budget_role.Editor)
+		if err != nil {
+			return nil, err
+		}
+		filter.UserID = budget.UserID
+	}
+
+	rules, err := s.ruleRepo.ListByUserID(ctx, req.UserID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Apply: failed to list rules for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+	if len(rules) == 0 {
+		return &ApplyRulesResponse{}, nil
+	}
+
+	// Transactions can only be moved into categories of the owner of their budget.
+	categories, err := s.categoryRepo.List(ctx, filter.UserID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Apply: failed to list categories for userID=%s: %v", filter.UserID, err)
+		return nil, errs.DatabaseError
+	}
+	categoryIDs := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		categoryIDs[category.ID] = true
+	}
+	set := domain.NewRuleSet(rules).OnlyCategories(categoryIDs)
+
+	// Transactions of an archived budget are read-only until it is unarchived.
+	archived := make(map[string]bool)
+	if req.BudgetID == "" {
+		budgets, err := s.budgetRepo.ListByUserID(ctx, req.UserID)
+		if err != nil {
+			zap.L().Sugar().Errorf("Apply: failed to list budgets for userID=%s: %v", req.UserID, err)
+			return nil, errs.DatabaseError
+		}
+		for _, budget := range budgets {
+			if budget.ArchivedAt.Valid {
+				archived[budget.ID] = true
+			}
+		}
+	}
+
+	var (
+		checked    int
+		candidates []*domain.Transaction
+	)
+
+	if err = s.transactionRepo.Export(ctx, filter, func(row *domain.TransactionExport) error {
+		if archived[row.BudgetID] {
+			return nil
+		}
+		checked++
+
+		t := row.Transaction
+		if categorize(set, &t) {
+			candidates = append(candidates, &t)
+		}
+		return nil
+	}); err != nil {
+		zap.L().Sugar().Errorf("Apply: failed to read transactions for userID=%s: %v", req.UserID, err)
+		return nil, errs.DatabaseError
+	}
+
+	var changed []*domain.Transaction
+	if len(candidates) > 0 {
+		budgetIDs := make([]string, 0, len(candidates))
+		for _, t := range candidates {
+			budgetIDs = append(budgetIDs, t.BudgetID)
+		}
+		slices.Sort(budgetIDs)
+		budgetIDs = slices.Compact(budgetIDs)
+
+		if err = s.transactionExecutor.WithTransaction(ctx, s.transactionRepo.GetDB(), func(tx *sqlx.Tx) error {
+			changed = nil
+
+			// Budgets are locked in a fixed order so concurrent writers can't deadlock. The
+			// transactions are read again under the lock, as they may have changed since the export.
+			for _, budgetID := range budgetIDs {
+				if err := s.budgetRepo.LockTX(ctx, tx, budgetID); err != nil {
+					zap.L().Sugar().Errorf("Apply: failed to lock budgetID=%s: %v", budgetID, err)
+					return errs.DatabaseError
+				}
+			}
+
+			for _, candidate := range candidates {
+				t, err := s.transactionRepo.GetByIDTX(ctx, tx, candidate.ID)
+				if err != nil {
+					if errors.Is(err, sql.ErrNoRows) {
+						continue
+					}
+					zap.L().Sugar().Errorf("Apply: failed to get transactionID=%s: %v", candidate.ID, err)
+					return errs.DatabaseError
+				}
+				if !categorize(set, t) {
+					continue
+				}
+
+				if err := s.transactionRepo.CategorizeTX(ctx, tx, t.ID, t.UserID, t.CategoryID, []string(t.Tags)); err != nil {
+					zap.L().Sugar().Errorf("Apply: failed to categorize transactionID=%s: %v", t.ID, err)
+					return errs.DatabaseError
+				}
+				changed = append(changed, t)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, t := range changed {
+		if err := s.transactionRepo.InvalidateCache(ctx, t.UserID, t.ID); err != nil {
+			zap.L().Sugar().Warnf("Apply: failed to invalidate cache for transactionID=%s: %v", t.ID, err)
+		}
+	}
+
+	zap.L().Sugar().Infof("Apply: %d of %d transactions updated for userID=%s", len(changed), checked, req.UserID)
+	return &ApplyRulesResponse{Checked: checked, Updated: len(changed)}, nil
+}
+
+// categorize applies the matching rules of set to t and reports whether t changed.
+func categorize(set *domain.RuleSet, t *domain.Transaction) bool {
+	categoryID, tags := set.Match(t)
+	updated := t.AddTags(tags...)
+	if categoryID != "" && categoryID != t.CategoryID {
+		t.CategoryID = categoryID
+		updated = true
+	}
+	return updated
+}
+
+// define validates a rule definition and copies it onto rule.
+func (s *Service) define(ctx context.Context, rule *domain.TransactionRule, def *RuleDefinition) error {
+	if def.CategoryID == "" && def.Tag == "" {
+		return errs.MissingAction
+	}
+	if (def.MinAmount != nil && def.MinAmount.IsNegative()) || (def.MaxAmount != nil && def.MaxAmount.IsNegative()) ||
+		(def.MinAmount != nil && def.MaxAmount != nil && def.MaxAmount.LessThan(*def.MinAmount)) {
+		return errs.InvalidAmountRange
+	}
+	if def.NotePattern != "" {
+		if _, err := domain.CompileNotePattern(def.NotePattern); err != nil {
+			zap.L().Sugar().Warnf("invalid note pattern %q for userID=%s: %v", def.NotePattern, rule.UserID, err)
+			return errs.InvalidNotePattern
+		}
+	}
+	if def.BudgetID != "" {
+		if _, err := s.getBudget(ctx, def.BudgetID, rule.UserID, e_budget_role.Viewer); err != nil {
+			return err
+		}
+	}
+	if def.CategoryID != "" {
+		if err := s.checkCategory(ctx, def.CategoryID, rule.UserID); err != nil {
+			return err
+		}
+	}
+
+	rule.Name = def.Name
+	if def.Priority != nil {
+		rule.Priority = *def.Priority
+	}
+	rule.NoteContains = nullString(def.NoteContains)
+	rule.NotePattern = nullString(def.NotePattern)
+	rule.MinAmount = def.MinAmount
+	rule.MaxAmount = def.MaxAmount
+	rule.TransactionType = nullString(def.Type.String())
+	rule.BudgetID = nullString(def.BudgetID)
+	rule.CategoryID = nullString(def.CategoryID)
+	rule.Tag = nullString(def.Tag)
+
+	return nil
+}
+
+// checkCategory makes sure the category is one the user can pick: a default or custom
+// category that the user has not hidden.
+func (s *Service) checkCategory(ctx context.Context, categoryID, userID string) error {
+	categories, err := s.categoryRepo.List(ctx, userID)
+	if err != nil {
+		zap.L().Sugar().Errorf("failed to list categories for userID=%s: %v", userID, err)
+		return errs.DatabaseError
+	}
+
+	if !slices.ContainsFunc(categories, func(c *domain.Category) bool { return c.ID == categoryID && !c.Hidden }) {
+		return errs.CategoryNotFound
+	}
+	return nil
+}
+
+func (s *Service) getBudget(ctx context.Context, budgetID, userID string, required e_budget_role.Enum) (*domain.Budget, error) {
+	budget, err := s.budgetRepo.GetByID(ctx, budgetID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.BudgetNotFound
+		}
+		zap.L().Sugar().Errorf("failed to get budgetID=%s for userID=%s: %v", budgetID, userID, err)
+		return nil, errs.DatabaseError
+	}
+	if !e_budget_role.Enum(budget.Role).Allows(required) {
+		return nil, errs.BudgetForbidden
+	}
+	// An archived budget is read-only until it is unarchived.
+	if required != e_budget_role.Viewer && budget.ArchivedAt.Valid {
+		zap.L().Sugar().Warnf("budgetID=%s is archived, userID=%s cannot change it", budgetID, userID)
+		return nil, errs.BudgetArchived
+	}
+
+	return budget, nil
+}
+
+func (s *Service) getOwnedRule(ctx context.Context, ruleID, userID string) (*domain.TransactionRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, ruleID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			zap.L().Sugar().Warnf("transaction rule not found for ruleID=%s, userID=%s", ruleID, userID)
+			return nil, errs.RuleNotFound
+		}
+		zap.L().Sugar().Errorf("failed to get ruleID=%s for userID=%s: %v", ruleID, userID, err)
+		return nil, errs.DatabaseError
+	}
+
+	return rule, nil
+}
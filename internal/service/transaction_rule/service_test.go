@@ -0,0 +1,418 @@
+package transaction_rule
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_transaction_type"
+	mock_budget "finly-backend/internal/repository/budget/mock"
+	mock_category "finly-backend/internal/repository/category/mock"
+	"finly-backend/internal/repository/transaction"
+	mock_transaction "finly-backend/internal/repository/transaction/mock"
+	mock_transaction_rule "finly-backend/internal/repository/transaction_rule/mock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type mockTransactionExecutor struct {
+	withTx func(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error
+}
+
+func (m *mockTransactionExecutor) WithTransaction(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	return m.withTx(ctx, db, fn)
+}
+
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockRuleRepo := mock_transaction_rule.NewMockTransactionRule(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockCategoryRepo := mock_category.NewMockCategory(ctrl)
+	service := NewService(mockRuleRepo, mockBudgetRepo, mockCategoryRepo, nil, nil)
+
+	amount := domain.MustParseMoney("9.99")
+	priority := 3
+	categories := []*domain.Category{{ID: "entertainment"}, {ID: "archived", Hidden: true}}
+
+	tests := []struct {
+		name        string
+		req         *CreateRuleRequest
+		mockSetup   func()
+		expectedRes *CreateRuleResponse
+		expectedErr error
+	}{
+		{
+			name: "Rule runs after the existing ones",
+			req: &CreateRuleRequest{UserID: "user123", RuleDefinition: RuleDefinition{
+				Name: "Netflix", NotePattern: "netflix", MinAmount: &amount, MaxAmount: &amount,
+				Type: e_transaction_type.Withdrawal, CategoryID: "entertainment",
+			}},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user123").Return([]*domain.TransactionRule{{Priority: 0}, {Priority: 4}}, nil)
+				mockRuleRepo.EXPECT().Create(ctx, &domain.TransactionRule{
+					UserID: "user123", Name: "Netflix", Priority: 5,
+					NotePattern: sql.NullString{String: "netflix", Valid: true}, MinAmount: &amount, MaxAmount: &amount,
+					TransactionType: sql.NullString{String: "withdrawal", Valid: true},
+					CategoryID:      sql.NullString{String: "entertainment", Valid: true},
+				}).Return("rule1", nil)
+			},
+			expectedRes: &CreateRuleResponse{ID: "rule1"},
+		},
+		{
+			name: "Tag-only rule with a priority and a budget",
+			req: &CreateRuleRequest{UserID: "user456", RuleDefinition: RuleDefinition{
+				Name: "Shared", Priority: &priority, BudgetID: "budget123", Tag: "household",
+			}},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "viewer"}, nil)
+				mockRuleRepo.EXPECT().Create(ctx, &domain.TransactionRule{
+					UserID: "user456", Name: "Shared", Priority: 3,
+					BudgetID: sql.NullString{String: "budget123", Valid: true},
+					Tag:      sql.NullString{String: "household", Valid: true},
+				}).Return("rule2", nil)
+			},
+			expectedRes: &CreateRuleResponse{ID: "rule2"},
+		},
+		{
+			name:        "No action",
+			req:         &CreateRuleRequest{UserID: "user123", RuleDefinition: RuleDefinition{Name: "Nothing", NoteContains: "uber"}},
+			mockSetup:   func() {},
+			expectedErr: errs.MissingAction,
+		},
+		{
+			name: "Minimum above maximum",
+			req: &CreateRuleRequest{UserID: "user123", RuleDefinition: RuleDefinition{
+				Name: "Range", MinAmount: func() *domain.Money { m := domain.MustParseMoney("10.00"); return &m }(), MaxAmount: &amount, Tag: "x",
+			}},
+			mockSetup:   func() {},
+			expectedErr: errs.InvalidAmountRange,
+		},
+		{
+			name:        "Pattern does not compile",
+			req:         &CreateRuleRequest{UserID: "user123", RuleDefinition: RuleDefinition{Name: "Broken", NotePattern: "(netflix", Tag: "x"}},
+			mockSetup:   func() {},
+			expectedErr: errs.InvalidNotePattern,
+		},
+		{
+			name: "Hidden category",
+			req:  &CreateRuleRequest{UserID: "user123", RuleDefinition: RuleDefinition{Name: "Old", CategoryID: "archived"}},
+			mockSetup: func() {
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+			},
+			expectedErr: errs.CategoryNotFound,
+		},
+		{
+			name: "Foreign budget",
+			req:  &CreateRuleRequest{UserID: "user123", RuleDefinition: RuleDefinition{Name: "Other", BudgetID: "other", Tag: "x"}},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "other", "user123").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.BudgetNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := service.Create(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRes, resp)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockRuleRepo := mock_transaction_rule.NewMockTransactionRule(ctrl)
+	service := NewService(mockRuleRepo, nil, nil, nil, nil)
+
+	existing := func() *domain.TransactionRule {
+		return &domain.TransactionRule{
+			ID: "rule1", UserID: "user123", Name: "Uber", Priority: 2,
+			NoteContains: sql.NullString{String: "uber", Valid: true},
+			CategoryID:   sql.NullString{String: "transport", Valid: true},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		req         *UpdateRuleRequest
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "Definition is replaced and the priority kept",
+			req:  &UpdateRuleRequest{UserID: "user123", ID: "rule1", RuleDefinition: RuleDefinition{Name: "Rides", NoteContains: "uber", Tag: "rides"}},
+			mockSetup: func() {
+				mockRuleRepo.EXPECT().GetByID(ctx, "rule1", "user123").Return(existing(), nil)
+				mockRuleRepo.EXPECT().Update(ctx, &domain.TransactionRule{
+					ID: "rule1", UserID: "user123", Name: "Rides", Priority: 2,
+					NoteContains: sql.NullString{String: "uber", Valid: true},
+					Tag:          sql.NullString{String: "rides", Valid: true},
+				}).Return(nil)
+			},
+		},
+		{
+			name: "Rule of another user",
+			req:  &UpdateRuleRequest{UserID: "user456", ID: "rule1", RuleDefinition: RuleDefinition{Name: "Rides", Tag: "rides"}},
+			mockSetup: func() {
+				mockRuleRepo.EXPECT().GetByID(ctx, "rule1", "user456").Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: errs.RuleNotFound,
+		},
+		{
+			name: "Update error",
+			req:  &UpdateRuleRequest{UserID: "user123", ID: "rule1", RuleDefinition: RuleDefinition{Name: "Rides", Tag: "rides"}},
+			mockSetup: func() {
+				mockRuleRepo.EXPECT().GetByID(ctx, "rule1", "user123").Return(existing(), nil)
+				mockRuleRepo.EXPECT().Update(ctx, gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := service.Update(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &UpdateRuleResponse{}, resp)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockRuleRepo := mock_transaction_rule.NewMockTransactionRule(ctrl)
+	mockBudgetRepo := mock_budget.NewMockBudget(ctrl)
+	mockCategoryRepo := mock_category.NewMockCategory(ctrl)
+	mockTransactionRepo := mock_transaction.NewMockTransaction(ctrl)
+	mockDB := &sqlx.DB{}
+	mockTx := &sqlx.Tx{}
+	service := NewService(mockRuleRepo, mockBudgetRepo, mockCategoryRepo, mockTransactionRepo, &mockTransactionExecutor{
+		withTx: func(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+			return fn(mockTx)
+		},
+	})
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rules := []*domain.TransactionRule{
+		{ID: "rule1", NoteContains: sql.NullString{String: "uber", Valid: true}, CategoryID: sql.NullString{String: "transport", Valid: true}, Tag: sql.NullString{String: "rides", Valid: true}},
+	}
+	categories := []*domain.Category{{ID: "transport"}, {ID: "groceries"}, {ID: "other"}}
+	budgets := []*domain.Budget{{ID: "budget1"}, {ID: "budget2"}}
+	rows := func() []*domain.TransactionExport {
+		return []*domain.TransactionExport{
+			{Transaction: domain.Transaction{ID: "t1", BudgetID: "budget1", UserID: "user123", CategoryID: "other", Note: "Uber trip"}},
+			{Transaction: domain.Transaction{ID: "t2", BudgetID: "budget1", UserID: "user123", CategoryID: "transport", Note: "UBER", Tags: []string{"rides"}}},
+			{Transaction: domain.Transaction{ID: "t3", BudgetID: "budget2", UserID: "user123", CategoryID: "groceries", Note: "Market"}},
+			{Transaction: domain.Transaction{ID: "t4", BudgetID: "budget2", UserID: "user123", CategoryID: "transport", Note: "uber eats", Tags: []string{"food"}}},
+		}
+	}
+	stored := func(id string) *domain.Transaction {
+		for _, row := range rows() {
+			if row.ID == id {
+				return &row.Transaction
+			}
+		}
+		return nil
+	}
+	export := func(_ context.Context, _ transaction.ListFilter, fn func(*domain.TransactionExport) error) error {
+		for _, row := range rows() {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name        string
+		req         *ApplyRulesRequest
+		mockSetup   func()
+		expectedRes *ApplyRulesResponse
+		expectedErr error
+	}{
+		{
+			name: "Changed transactions are categorized",
+			req:  &ApplyRulesRequest{UserID: "user123", From: &from},
+			mockSetup: func() {
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user123").Return(rules, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").Return(budgets, nil)
+				mockTransactionRepo.EXPECT().Export(ctx, transaction.ListFilter{UserID: "user123", From: &from}, gomock.Any()).DoAndReturn(export)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				gomock.InOrder(
+					mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget1").Return(nil),
+					mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget2").Return(nil),
+				)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "t1").Return(stored("t1"), nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "t4").Return(stored("t4"), nil)
+				mockTransactionRepo.EXPECT().CategorizeTX(ctx, mockTx, "t1", "user123", "transport", []string{"rides"}).Return(nil)
+				mockTransactionRepo.EXPECT().CategorizeTX(ctx, mockTx, "t4", "user123", "transport", []string{"food", "rides"}).Return(nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "t1").Return(nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "t4").Return(nil)
+			},
+			expectedRes: &ApplyRulesResponse{Checked: 4, Updated: 2},
+		},
+		{
+			name: "Transactions are matched again as stored under the lock",
+			req:  &ApplyRulesRequest{UserID: "user123"},
+			mockSetup: func() {
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user123").Return(rules, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").Return(budgets, nil)
+				mockTransactionRepo.EXPECT().Export(ctx, gomock.Any(), gomock.Any()).DoAndReturn(export)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget1").Return(nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget2").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "t1").
+					Return(&domain.Transaction{ID: "t1", BudgetID: "budget1", UserID: "user123", CategoryID: "transport", Note: "Uber trip", Tags: []string{"rides"}}, nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "t4").Return(nil, sql.ErrNoRows)
+			},
+			expectedRes: &ApplyRulesResponse{Checked: 4},
+		},
+		{
+			name: "Archived budgets are left as they are",
+			req:  &ApplyRulesRequest{UserID: "user123"},
+			mockSetup: func() {
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user123").Return(rules, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").
+					Return([]*domain.Budget{{ID: "budget1"}, {ID: "budget2", ArchivedAt: sql.NullTime{Time: time.Now(), Valid: true}}}, nil)
+				mockTransactionRepo.EXPECT().Export(ctx, gomock.Any(), gomock.Any()).DoAndReturn(export)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget1").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "t1").Return(stored("t1"), nil)
+				mockTransactionRepo.EXPECT().CategorizeTX(ctx, mockTx, "t1", "user123", "transport", []string{"rides"}).Return(nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "t1").Return(nil)
+			},
+			expectedRes: &ApplyRulesResponse{Checked: 2, Updated: 1},
+		},
+		{
+			name: "Shared budget reads the owner's transactions",
+			req:  &ApplyRulesRequest{UserID: "user456", BudgetID: "budget123"},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "editor"}, nil)
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user456").Return(rules, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockTransactionRepo.EXPECT().Export(ctx, transaction.ListFilter{UserID: "user123", BudgetID: "budget123"}, gomock.Any()).Return(nil)
+			},
+			expectedRes: &ApplyRulesResponse{},
+		},
+		{
+			name: "Category the owner can't use is skipped",
+			req:  &ApplyRulesRequest{UserID: "user456", BudgetID: "budget123"},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "editor"}, nil)
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user456").Return(rules, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return([]*domain.Category{{ID: "groceries"}, {ID: "other"}}, nil)
+				mockTransactionRepo.EXPECT().Export(ctx, transaction.ListFilter{UserID: "user123", BudgetID: "budget123"}, gomock.Any()).DoAndReturn(export)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget1").Return(nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget2").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "t1").Return(stored("t1"), nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "t4").Return(stored("t4"), nil)
+				mockTransactionRepo.EXPECT().CategorizeTX(ctx, mockTx, "t1", "user123", "other", []string{"rides"}).Return(nil)
+				mockTransactionRepo.EXPECT().CategorizeTX(ctx, mockTx, "t4", "user123", "transport", []string{"food", "rides"}).Return(nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "t1").Return(nil)
+				mockTransactionRepo.EXPECT().InvalidateCache(ctx, "user123", "t4").Return(nil)
+			},
+			expectedRes: &ApplyRulesResponse{Checked: 4, Updated: 2},
+		},
+		{
+			name: "Viewer cannot apply rules",
+			req:  &ApplyRulesRequest{UserID: "user456", BudgetID: "budget123"},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user456").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "viewer"}, nil)
+			},
+			expectedErr: errs.BudgetForbidden,
+		},
+		{
+			name: "Archived budget",
+			req:  &ApplyRulesRequest{UserID: "user123", BudgetID: "budget123"},
+			mockSetup: func() {
+				mockBudgetRepo.EXPECT().GetByID(ctx, "budget123", "user123").
+					Return(&domain.Budget{ID: "budget123", UserID: "user123", Role: "owner", ArchivedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+			},
+			expectedErr: errs.BudgetArchived,
+		},
+		{
+			name: "No rules",
+			req:  &ApplyRulesRequest{UserID: "user123"},
+			mockSetup: func() {
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user123").Return(nil, nil)
+			},
+			expectedRes: &ApplyRulesResponse{},
+		},
+		{
+			name:        "Reversed date range",
+			req:         &ApplyRulesRequest{UserID: "user123", From: &from, To: func() *time.Time { t := from.AddDate(0, 0, -1); return &t }()},
+			mockSetup:   func() {},
+			expectedErr: errs.InvalidDateRange,
+		},
+		{
+			name: "Write error",
+			req:  &ApplyRulesRequest{UserID: "user123", From: &from},
+			mockSetup: func() {
+				mockRuleRepo.EXPECT().ListByUserID(ctx, "user123").Return(rules, nil)
+				mockCategoryRepo.EXPECT().List(ctx, "user123").Return(categories, nil)
+				mockBudgetRepo.EXPECT().ListByUserID(ctx, "user123").Return(budgets, nil)
+				mockTransactionRepo.EXPECT().Export(ctx, gomock.Any(), gomock.Any()).DoAndReturn(export)
+				mockTransactionRepo.EXPECT().GetDB().Return(mockDB)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget1").Return(nil)
+				mockBudgetRepo.EXPECT().LockTX(ctx, mockTx, "budget2").Return(nil)
+				mockTransactionRepo.EXPECT().GetByIDTX(ctx, mockTx, "t1").Return(stored("t1"), nil)
+				mockTransactionRepo.EXPECT().CategorizeTX(ctx, mockTx, "t1", "user123", "transport", []string{"rides"}).Return(errors.New("db error"))
+			},
+			expectedErr: errs.DatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := service.Apply(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRes, resp)
+			}
+		})
+	}
+}
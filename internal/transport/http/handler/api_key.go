@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/api_key"
+	"finly-backend/internal/transport/http/middleware"
+	"finly-backend/pkg/bind"
+	"finly-backend/pkg/server"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type APIKey struct {
+	service *service.Service
+}
+
+func NewAPIKey(s *service.Service) *APIKey {
+	return &APIKey{
+		service: s,
+	}
+}
+
+func (s *APIKey) Register(server *server.Server, mw middleware.Auth) {
+	group := server.Group("/api-key", mw.JWT)
+
+	group.POST("", s.Create, mw.VerifiedEmail)
+	group.GET("", s.List)
+	// Revoking stays open to unverified users, so a key issued before can always be taken back.
+	group.DELETE("/:id", s.Delete)
+}
+
+// @Summary Create an API key
+// @Description Issues a key for scripts and integrations, sent as "Authorization: Bearer fk_...". The key is returned only once.
+// @Description Scopes: read allows every read; budgets:write, categories:write, transactions:write, recurring:write, limits:write and rules:write
+// @Description each allow changes to one part of the API. Account and session endpoints, budget members, invitations and budget deletion always need a signed-in session.
+// @Tags API Key
+// @ID create-api-key
+// @Accept json
+// @Produce json
+// @Param request body api_key.CreateAPIKeyRequest true "API key"
+// @Success 201 {object} api_key.CreateAPIKeyResponse
+// @Failure 403 {object} echo.HTTPError "Email address is not verified"
+// @Router /api-key [post]
+func (s *APIKey) Create(c echo.Context) error {
+	var (
+		err error
+		obj api_key.CreateAPIKeyRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.APIKey.Create(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error creating API key", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// @Summary List API keys
+// @Description Retrieves the API keys of the user with their scopes, expiry and when they were last used
+// @Tags API Key
+// @ID list-api-keys
+// @Produce json
+// @Success 200 {object} api_key.ListAPIKeysResponse
+// @Router /api-key [get]
+func (s *APIKey) List(c echo.Context) error {
+	var (
+		err error
+		obj api_key.ListAPIKeysRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.APIKey.List(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error listing API keys", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Delete an API key
+// @Description Revokes an API key. Requests made with it are rejected immediately.
+// @Tags API Key
+// @ID delete-api-key
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} api_key.DeleteAPIKeyResponse
+// @Router /api-key/{id} [delete]
+func (s *APIKey) Delete(c echo.Context) error {
+	var (
+		err error
+		obj api_key.DeleteAPIKeyRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.APIKey.Delete(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error deleting API key", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"finly-backend/internal/domain/enums/e_api_key_scope"
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/api_key"
+	"finly-backend/internal/service/api_key/mock"
+	"finly-backend/internal/transport/http/validation"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupAPIKeyTest(t *testing.T) (*echo.Echo, *mock.MockAPIKey, *APIKey) {
+	var err error
+
+	ctrl := gomock.NewController(t)
+	mockAPIKey := mock.NewMockAPIKey(ctrl)
+	service := &service.Service{APIKey: mockAPIKey}
+	handler := NewAPIKey(service)
+	e := echo.New()
+
+	if e.Validator, err = validation.New(); err != nil {
+		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
+	}
+
+	return e, mockAPIKey, handler
+}
+
+func TestAPIKey_Create(t *testing.T) {
+	e, mockAPIKey, handler := setupAPIKeyTest(t)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "create key",
+			body:           `{"name":"Import script","scopes":["read","transactions:write"]}`,
+			expectCall:     true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown scope",
+			body:           `{"name":"Import script","scopes":["admin"]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no scopes",
+			body:           `{"name":"Import script","scopes":[]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api-key", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.expectCall {
+				mockAPIKey.EXPECT().
+					Create(gomock.Any(), &api_key.CreateAPIKeyRequest{
+						UserID: "user123",
+						Name:   "Import script",
+						Scopes: []e_api_key_scope.Enum{e_api_key_scope.Read, e_api_key_scope.TransactionsWrite},
+					}).
+					Return(&api_key.CreateAPIKeyResponse{APIKeyObject: api_key.APIKeyObject{ID: "key1"}, Key: "fk_key"}, nil)
+			}
+
+			err := handler.Create(c)
+			if tt.expectedStatus == http.StatusCreated {
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusCreated, rec.Code)
+
+				var res api_key.CreateAPIKeyResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+				assert.Equal(t, "fk_key", res.Key)
+			} else {
+				he, ok := err.(*echo.HTTPError)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, he.Code)
+			}
+		})
+	}
+}
+
+func TestAPIKey_List(t *testing.T) {
+	e, mockAPIKey, handler := setupAPIKeyTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api-key", nil)
+	req.Header.Set("User-Id", "user123")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockResponse := &api_key.ListAPIKeysResponse{Keys: []*api_key.APIKeyObject{{ID: "key1", Name: "Import script", Prefix: "fk_abcdef", Scopes: []string{"read"}}}}
+	mockAPIKey.EXPECT().
+		List(gomock.Any(), &api_key.ListAPIKeysRequest{UserID: "user123"}).
+		Return(mockResponse, nil)
+
+	err := handler.List(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var res api_key.ListAPIKeysResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, *mockResponse, res)
+}
+
+func TestAPIKey_Delete(t *testing.T) {
+	e, mockAPIKey, handler := setupAPIKeyTest(t)
+
+	keyID := "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f"
+	req := httptest.NewRequest(http.MethodDelete, "/api-key/"+keyID, nil)
+	req.Header.Set("User-Id", "user123")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(keyID)
+
+	mockAPIKey.EXPECT().
+		Delete(gomock.Any(), &api_key.DeleteAPIKeyRequest{UserID: "user123", ID: keyID}).
+		Return(&api_key.DeleteAPIKeyResponse{}, nil)
+
+	err := handler.Delete(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -1,15 +1,21 @@
 package handler
 
 import (
+	"errors"
 	"finly-backend/internal/service"
 	"finly-backend/internal/service/auth"
+	"finly-backend/internal/transport/http/middleware"
 	"finly-backend/pkg/bind"
 	"finly-backend/pkg/server"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
+	"math"
 	"net/http"
+	"strconv"
 )
 
+const jwksCacheControl = "public, max-age=300"
+
 type Auth struct {
 	service *service.Service
 }
@@ -20,14 +26,29 @@ func NewAuth(s *service.Service) *Auth {
 	}
 }
 
-func (s *Auth) Register(server *server.Server) {
+func (s *Auth) Register(server *server.Server, mw middleware.Auth) {
 	group := server.Group("/auth")
 
 	group.POST("/register", s.RegisterUser)
 	group.POST("/login", s.Login)
 	group.POST("/logout", s.Logout)
 	group.POST("/refresh", s.Refresh)
-	group.GET("/me", s.Me)
+	group.GET("/me", s.Me, mw.JWT)
+	group.PATCH("/me", s.UpdateMe, mw.JWT)
+	group.DELETE("/me", s.DeleteMe, mw.JWT)
+	group.POST("/password/change", s.ChangePassword, mw.JWT)
+	group.POST("/email/change", s.ChangeEmail, mw.JWT)
+	group.POST("/password/forgot", s.ForgotPassword)
+	group.POST("/password/reset", s.ResetPassword)
+	group.GET("/verify", s.VerifyEmail)
+	group.POST("/verify/resend", s.ResendVerification, mw.JWT)
+	group.POST("/2fa/verify", s.VerifyTwoFactor)
+	group.POST("/2fa/enroll", s.EnrollTwoFactor, mw.JWT)
+	group.POST("/2fa/confirm", s.ConfirmTwoFactor, mw.JWT)
+	group.POST("/2fa/disable", s.DisableTwoFactor, mw.JWT)
+	group.POST("/2fa/recovery-codes", s.RegenerateRecoveryCodes, mw.JWT)
+
+	server.GET("/.well-known/jwks.json", s.Keys)
 }
 
 // @Summary Register a new user
@@ -44,10 +65,11 @@ func (s *Auth) RegisterUser(c echo.Context) error {
 		obj auth.RegisterRequest
 	)
 
-	if err = bind.Validate(c, &obj); err != nil {
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
 		zap.L().Error("error binding and validating request", zap.Error(err))
 		return err
 	}
+	obj.IP = c.RealIP()
 
 	res, err := s.service.Auth.Register(c.Request().Context(), &obj)
 	if err != nil {
@@ -59,12 +81,13 @@ func (s *Auth) RegisterUser(c echo.Context) error {
 }
 
 // @Summary Login a user
-// @Description Authenticates a user with the provided credentials
+// @Description Authenticates a user with the provided credentials. For a user with two-factor authentication the response holds a challenge token instead of the token pair, to be exchanged at /auth/2fa/verify.
 // @Tags User
 // @ID login-user
 // @Produce json
 // @Param credentials body auth.LoginRequest true "User Credentials"
 // @Success 200 {object} auth.LoginResponse
+// @Failure 429 {object} echo.HTTPError "Too many failed attempts, see the Retry-After header"
 // @Router /auth/login [post]
 func (s *Auth) Login(c echo.Context) error {
 	var (
@@ -72,22 +95,23 @@ func (s *Auth) Login(c echo.Context) error {
 		obj auth.LoginRequest
 	)
 
-	if err = bind.Validate(c, &obj); err != nil {
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
 		zap.L().Error("error binding and validating request", zap.Error(err))
 		return err
 	}
+	obj.IP = c.RealIP()
 
 	res, err := s.service.Auth.Login(c.Request().Context(), &obj)
 	if err != nil {
 		zap.L().Error("error login user", zap.Error(err))
-		return err
+		return retryAfter(c, err)
 	}
 
 	return c.JSON(http.StatusOK, res)
 }
 
 // @Summary Logout a user
-// @Description Logs out a user by invalidating their authentication token
+// @Description Logs out a user by ending the session of their authentication token
 // @Tags User
 // @ID logout-user
 // @Produce json
@@ -115,11 +139,12 @@ func (s *Auth) Logout(c echo.Context) error {
 }
 
 // @Summary Refresh a user token
-// @Description Refreshes a user's authentication token by generating a new one
+// @Description Exchanges a refresh token for a new access token and refresh token.
+// @Description Every refresh token can be used once; using it again revokes the whole session.
 // @Tags User
 // @ID refresh-token
 // @Produce json
-// @Param token body auth.RefreshTokenRequest true "Authentication Token"
+// @Param token body auth.RefreshTokenRequest true "Refresh Token"
 // @Success 200 {object} auth.RefreshTokenResponse
 // @Router /auth/refresh [post]
 func (s *Auth) Refresh(c echo.Context) error {
@@ -132,6 +157,7 @@ func (s *Auth) Refresh(c echo.Context) error {
 		zap.L().Error("error binding and validating request", zap.Error(err))
 		return err
 	}
+	obj.IP = c.RealIP()
 
 	res, err := s.service.Auth.RefreshToken(c.Request().Context(), &obj)
 	if err != nil {
@@ -169,3 +195,416 @@ func (s *Auth) Me(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, res)
 }
+
+// @Summary Request a password reset
+// @Description Sends a link to reset the password to the email, if it belongs to an account. The response doesn't tell whether it does.
+// @Tags User
+// @ID forgot-password
+// @Produce json
+// @Param request body auth.ForgotPasswordRequest true "Account email"
+// @Success 200 {object} auth.ForgotPasswordResponse
+// @Router /auth/password/forgot [post]
+func (s *Auth) ForgotPassword(c echo.Context) error {
+	var (
+		err error
+		obj auth.ForgotPasswordRequest
+	)
+
+	if err = bind.Validate(c, &obj); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Auth.ForgotPassword(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error requesting password reset", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Reset the password
+// @Description Sets a new password with the token of a reset link. The link works once and every session of the user is signed out.
+// @Tags User
+// @ID reset-password
+// @Produce json
+// @Param request body auth.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} auth.ResetPasswordResponse
+// @Router /auth/password/reset [post]
+func (s *Auth) ResetPassword(c echo.Context) error {
+	var (
+		err error
+		obj auth.ResetPasswordRequest
+	)
+
+	if err = bind.Validate(c, &obj); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Auth.ResetPassword(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error resetting password", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Verify the email address
+// @Description Confirms the email address of an account with the token of a verification link
+// @Tags User
+// @ID verify-email
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} auth.VerifyEmailResponse
+// @Router /auth/verify [get]
+func (s *Auth) VerifyEmail(c echo.Context) error {
+	var (
+		err error
+		obj auth.VerifyEmailRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromQuery()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Auth.VerifyEmail(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error verifying email", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Resend the verification link
+// @Description Sends a new verification link to the email address of the authenticated user
+// @Tags User
+// @ID resend-verification
+// @Produce json
+// @Param token header string true "Authentication Token"
+// @Success 200 {object} auth.ResendVerificationResponse
+// @Router /auth/verify/resend [post]
+func (s *Auth) ResendVerification(c echo.Context) error {
+	var (
+		err error
+		obj auth.ResendVerificationRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Auth.ResendVerification(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error resending verification link", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Complete a two-factor sign-in
+// @Description Exchanges the challenge token of a login and an authenticator or recovery code for a token pair
+// @Tags User
+// @ID verify-two-factor
+// @Produce json
+// @Param request body auth.VerifyTwoFactorRequest true "Challenge token and code"
+// @Success 200 {object} auth.VerifyTwoFactorResponse
+// @Router /auth/2fa/verify [post]
+func (s *Auth) VerifyTwoFactor(c echo.Context) error {
+	var (
+		err error
+		obj auth.VerifyTwoFactorRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+	obj.IP = c.RealIP()
+
+	res, err := s.service.Auth.VerifyTwoFactor(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error verifying two-factor code", zap.Error(err))
+		return retryAfter(c, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Start two-factor enrolment
+// @Description Creates an authenticator secret and its otpauth URI. Two-factor authentication is enabled once a code is confirmed.
+// @Tags User
+// @ID enroll-two-factor
+// @Produce json
+// @Param token header string true "Authentication Token"
+// @Success 200 {object} auth.EnrollTwoFactorResponse
+// @Router /auth/2fa/enroll [post]
+func (s *Auth) EnrollTwoFactor(c echo.Context) error {
+	var (
+		err error
+		obj auth.EnrollTwoFactorRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Auth.EnrollTwoFactor(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error enrolling two-factor authentication", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Enable two-factor authentication
+// @Description Confirms the enrolled secret with a code of the authenticator app and returns the recovery codes, which are shown only once
+// @Tags User
+// @ID confirm-two-factor
+// @Produce json
+// @Param token header string true "Authentication Token"
+// @Param request body auth.ConfirmTwoFactorRequest true "Authenticator code"
+// @Success 200 {object} auth.ConfirmTwoFactorResponse
+// @Router /auth/2fa/confirm [post]
+func (s *Auth) ConfirmTwoFactor(c echo.Context) error {
+	var (
+		err error
+		obj auth.ConfirmTwoFactorRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Auth.ConfirmTwoFactor(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error confirming two-factor authentication", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Disable two-factor authentication
+// @Description Turns two-factor authentication off after checking the password and an authenticator or recovery code
+// @Tags User
+// @ID disable-two-factor
+// @Produce json
+// @Param token header string true "Authentication Token"
+// @Param request body auth.DisableTwoFactorRequest true "Password and code"
+// @Success 200 {object} auth.DisableTwoFactorResponse
+// @Failure 429 {object} echo.HTTPError "Too many failed attempts, see the Retry-After header"
+// @Router /auth/2fa/disable [post]
+func (s *Auth) DisableTwoFactor(c echo.Context) error {
+	var (
+		err error
+		obj auth.DisableTwoFactorRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+	obj.IP = c.RealIP()
+
+	res, err := s.service.Auth.DisableTwoFactor(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error disabling two-factor authentication", zap.Error(err))
+		return retryAfter(c, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Regenerate recovery codes
+// @Description Replaces all recovery codes with new ones after checking an authenticator or recovery code
+// @Tags User
+// @ID regenerate-recovery-codes
+// @Produce json
+// @Param token header string true "Authentication Token"
+// @Param request body auth.RegenerateRecoveryCodesRequest true "Authenticator or recovery code"
+// @Success 200 {object} auth.RegenerateRecoveryCodesResponse
+// @Failure 429 {object} echo.HTTPError "Too many failed attempts, see the Retry-After header"
+// @Router /auth/2fa/recovery-codes [post]
+func (s *Auth) RegenerateRecoveryCodes(c echo.Context) error {
+	var (
+		err error
+		obj auth.RegenerateRecoveryCodesRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+	obj.IP = c.RealIP()
+
+	res, err := s.service.Auth.RegenerateRecoveryCodes(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error regenerating recovery codes", zap.Error(err))
+		return retryAfter(c, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Update profile
+// @Description Changes the first and last name of the current user; fields left out keep their value
+// @Tags User
+// @ID update-me
+// @Produce json
+// @Param token header string true "Authentication Token"
+// @Param request body auth.UpdateMeRequest true "Profile fields"
+// @Success 200 {object} auth.UpdateMeResponse
+// @Router /auth/me [patch]
+func (s *Auth) UpdateMe(c echo.Context) error {
+	var (
+		err error
+		obj auth.UpdateMeRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Auth.UpdateMe(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error updating profile", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Change password
+// @Description Sets a new password after checking the current one and signs out every other session
+// @Tags User
+// @ID change-password
+// @Produce json
+// @Param token header string true "Authentication Token"
+// @Param request body auth.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} auth.ChangePasswordResponse
+// @Failure 429 {object} echo.HTTPError "Too many failed attempts, see the Retry-After header"
+// @Router /auth/password/change [post]
+func (s *Auth) ChangePassword(c echo.Context) error {
+	var (
+		err error
+		obj auth.ChangePasswordRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+	obj.IP = c.RealIP()
+
+	res, err := s.service.Auth.ChangePassword(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error changing password", zap.Error(err))
+		return retryAfter(c, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Change email
+// @Description Mails a confirmation link to the new address; the email changes once the link is opened through GET /auth/verify
+// @Tags User
+// @ID change-email
+// @Produce json
+// @Param token header string true "Authentication Token"
+// @Param request body auth.ChangeEmailRequest true "Password and new email"
+// @Success 200 {object} auth.ChangeEmailResponse
+// @Failure 429 {object} echo.HTTPError "Too many failed attempts, see the Retry-After header"
+// @Router /auth/email/change [post]
+func (s *Auth) ChangeEmail(c echo.Context) error {
+	var (
+		err error
+		obj auth.ChangeEmailRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+	obj.IP = c.RealIP()
+
+	res, err := s.service.Auth.ChangeEmail(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error changing email", zap.Error(err))
+		return retryAfter(c, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Delete account
+// @Description Deletes the current user with all budgets, transactions and other data after checking the password, and a two-factor code when enabled. Every session is signed out.
+// @Tags User
+// @ID delete-me
+// @Produce json
+// @Param token header string true "Authentication Token"
+// @Param request body auth.DeleteMeRequest true "Password and code"
+// @Success 200 {object} auth.DeleteMeResponse
+// @Failure 409 {object} echo.HTTPError "Custom categories of the user are still used in budgets of other users"
+// @Failure 429 {object} echo.HTTPError "Too many failed attempts, see the Retry-After header"
+// @Router /auth/me [delete]
+func (s *Auth) DeleteMe(c echo.Context) error {
+	var (
+		err error
+		obj auth.DeleteMeRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+	obj.IP = c.RealIP()
+
+	res, err := s.service.Auth.DeleteMe(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error deleting account", zap.Error(err))
+		return retryAfter(c, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Get token verification keys
+// @Description Returns the public keys access tokens are signed with as a JSON Web Key Set, so other services can verify them. Tokens name their key in the kid header.
+// @Tags User
+// @ID get-jwks
+// @Produce json
+// @Success 200 {object} auth.KeysResponse
+// @Router /.well-known/jwks.json [get]
+func (s *Auth) Keys(c echo.Context) error {
+	res, err := s.service.Auth.Keys(c.Request().Context())
+	if err != nil {
+		zap.L().Error("error getting signing keys", zap.Error(err))
+		return err
+	}
+
+	// Verifiers may cache the keys for a while; a new key is published before it signs.
+	c.Response().Header().Set(echo.HeaderCacheControl, jwksCacheControl)
+	return c.JSON(http.StatusOK, res)
+}
+
+// retryAfter tells a throttled client in the Retry-After header when it may sign in again.
+func retryAfter(c echo.Context, err error) error {
+	var throttled *auth.ThrottledError
+	if !errors.As(err, &throttled) {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	return throttled.HTTPError
+}
@@ -6,7 +6,8 @@ import (
 	"finly-backend/internal/service"
 	"finly-backend/internal/service/auth"
 	"finly-backend/internal/service/auth/mock"
-	validator2 "finly-backend/pkg/validator"
+	"finly-backend/internal/transport/http/validation"
+	"finly-backend/pkg/security"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -14,6 +15,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func setupAuthTest(t *testing.T) (*echo.Echo, *mock.MockAuth, *Auth) {
@@ -25,7 +27,7 @@ func setupAuthTest(t *testing.T) (*echo.Echo, *mock.MockAuth, *Auth) {
 	handler := NewAuth(service)
 	e := echo.New()
 
-	if e.Validator, err = validator2.CustomValidator(); err != nil {
+	if e.Validator, err = validation.New(); err != nil {
 		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
 	}
 
@@ -53,7 +55,7 @@ func TestAuth_RegisterUser(t *testing.T) {
 				},
 				Password: "password123",
 			},
-			mockResponse:   &auth.RegisterResponse{Token: "jwt_token"},
+			mockResponse:   &auth.RegisterResponse{TokenPair: auth.TokenPair{Token: "jwt_token", RefreshToken: "refresh_token"}},
 			mockError:      nil,
 			expectedStatus: http.StatusCreated,
 		},
@@ -102,6 +104,7 @@ func TestAuth_RegisterUser(t *testing.T) {
 				err = json.Unmarshal(rec.Body.Bytes(), &response)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.mockResponse.Token, response.Token)
+				assert.Equal(t, tt.mockResponse.RefreshToken, response.RefreshToken)
 			}
 		})
 	}
@@ -124,7 +127,17 @@ func TestAuth_Login(t *testing.T) {
 				Email:    "john.doe@example.com",
 				Password: "password123",
 			},
-			mockResponse:   &auth.LoginResponse{Token: "jwt_token"},
+			mockResponse:   &auth.LoginResponse{TokenPair: &auth.TokenPair{Token: "jwt_token", RefreshToken: "refresh_token"}},
+			mockError:      nil,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "two-factor challenge",
+			input: auth.LoginRequest{
+				Email:    "john.doe@example.com",
+				Password: "password123",
+			},
+			mockResponse:   &auth.LoginResponse{TwoFactorRequired: true, ChallengeToken: "challenge_token", ChallengeExpiresIn: 300},
 			mockError:      nil,
 			expectedStatus: http.StatusOK,
 		},
@@ -168,12 +181,32 @@ func TestAuth_Login(t *testing.T) {
 				var response auth.LoginResponse
 				err = json.Unmarshal(rec.Body.Bytes(), &response)
 				assert.NoError(t, err)
-				assert.Equal(t, tt.mockResponse.Token, response.Token)
+				assert.Equal(t, tt.mockResponse, &response)
 			}
 		})
 	}
 }
 
+func TestAuth_Login_Throttled(t *testing.T) {
+	e, mockAuth, handler := setupAuthTest(t)
+
+	body, _ := json.Marshal(auth.LoginRequest{Email: "john.doe@example.com", Password: "password123"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	throttled := &auth.ThrottledError{
+		HTTPError:  echo.NewHTTPError(http.StatusTooManyRequests, "Too many sign-in attempts, try again later"),
+		RetryAfter: 1500 * time.Millisecond,
+	}
+	mockAuth.EXPECT().Login(gomock.Any(), gomock.Any()).Return(nil, throttled)
+
+	err := handler.Login(c)
+	assert.Equal(t, throttled.HTTPError, err)
+	assert.Equal(t, "2", rec.Header().Get(echo.HeaderRetryAfter))
+}
+
 func TestAuth_Logout(t *testing.T) {
 	e, mockAuth, handler := setupAuthTest(t)
 	defer gomock.NewController(t).Finish()
@@ -247,14 +280,14 @@ func TestAuth_Refresh(t *testing.T) {
 	}{
 		{
 			name:           "successful refresh",
-			input:          auth.RefreshTokenRequest{AuthToken: "Bearer jwt_token"},
-			mockResponse:   &auth.RefreshTokenResponse{Token: "new_jwt_token"},
+			input:          auth.RefreshTokenRequest{RefreshToken: "refresh_token"},
+			mockResponse:   &auth.RefreshTokenResponse{TokenPair: auth.TokenPair{Token: "new_jwt_token", RefreshToken: "new_refresh_token"}},
 			mockError:      nil,
 			expectedStatus: http.StatusOK,
 		},
 		{
 			name:           "missing token",
-			input:          auth.RefreshTokenRequest{AuthToken: ""},
+			input:          auth.RefreshTokenRequest{RefreshToken: ""},
 			mockResponse:   nil,
 			mockError:      nil,
 			expectedStatus: http.StatusBadRequest,
@@ -263,8 +296,9 @@ func TestAuth_Refresh(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req := httptest.NewRequest(http.MethodPost, "/auth/refresh", nil)
-			req.Header.Set("Authorization", tt.input.AuthToken)
+			body, _ := json.Marshal(tt.input)
+			req := httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 
@@ -289,6 +323,7 @@ func TestAuth_Refresh(t *testing.T) {
 				err = json.Unmarshal(rec.Body.Bytes(), &response)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.mockResponse.Token, response.Token)
+				assert.Equal(t, tt.mockResponse.RefreshToken, response.RefreshToken)
 			}
 		})
 	}
@@ -359,3 +394,460 @@ func TestAuth_Me(t *testing.T) {
 		})
 	}
 }
+
+func TestAuth_Keys(t *testing.T) {
+	e, mockAuth, handler := setupAuthTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockAuth.EXPECT().Keys(gomock.Any()).Return(&auth.KeysResponse{JWKS: security.JWKS{Keys: []security.JWK{
+		{KeyType: "OKP", KeyID: "2025-03", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+	}}}, nil)
+
+	err := handler.Keys(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "public, max-age=300", rec.Header().Get(echo.HeaderCacheControl))
+	assert.JSONEq(t, `{"keys":[{"kty":"OKP","kid":"2025-03","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`, rec.Body.String())
+}
+
+func TestAuth_ForgotPassword(t *testing.T) {
+	e, mockAuth, handler := setupAuthTest(t)
+
+	tests := []struct {
+		name           string
+		input          auth.ForgotPasswordRequest
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "link requested",
+			input:          auth.ForgotPasswordRequest{Email: "john.doe@example.com"},
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid email",
+			input:          auth.ForgotPasswordRequest{Email: "john.doe"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.input)
+			req := httptest.NewRequest(http.MethodPost, "/auth/password/forgot", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.expectCall {
+				mockAuth.EXPECT().
+					ForgotPassword(gomock.Any(), &tt.input).
+					Return(&auth.ForgotPasswordResponse{Message: "sent"}, nil)
+			}
+
+			err := handler.ForgotPassword(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuth_ResetPassword(t *testing.T) {
+	e, mockAuth, handler := setupAuthTest(t)
+
+	tests := []struct {
+		name           string
+		input          auth.ResetPasswordRequest
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "password reset",
+			input:          auth.ResetPasswordRequest{Token: "reset_token", Password: "newpassword"},
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "password too short",
+			input:          auth.ResetPasswordRequest{Token: "reset_token", Password: "short"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing token",
+			input:          auth.ResetPasswordRequest{Password: "newpassword"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.input)
+			req := httptest.NewRequest(http.MethodPost, "/auth/password/reset", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.expectCall {
+				mockAuth.EXPECT().
+					ResetPassword(gomock.Any(), &tt.input).
+					Return(&auth.ResetPasswordResponse{Message: "reset"}, nil)
+			}
+
+			err := handler.ResetPassword(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuth_VerifyEmail(t *testing.T) {
+	e, mockAuth, handler := setupAuthTest(t)
+
+	tests := []struct {
+		name           string
+		target         string
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "email verified",
+			target:         "/auth/verify?token=verify_token",
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing token",
+			target:         "/auth/verify",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.expectCall {
+				mockAuth.EXPECT().
+					VerifyEmail(gomock.Any(), &auth.VerifyEmailRequest{Token: "verify_token"}).
+					Return(&auth.VerifyEmailResponse{Message: "verified"}, nil)
+			}
+
+			err := handler.VerifyEmail(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuth_ResendVerification(t *testing.T) {
+	e, mockAuth, handler := setupAuthTest(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/verify/resend", nil)
+	req.Header.Set("User-Id", "user123")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockAuth.EXPECT().
+		ResendVerification(gomock.Any(), &auth.ResendVerificationRequest{UserID: "user123"}).
+		Return(&auth.ResendVerificationResponse{Message: "sent"}, nil)
+
+	err := handler.ResendVerification(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAuth_VerifyTwoFactor(t *testing.T) {
+	e, mockAuth, handler := setupAuthTest(t)
+
+	tests := []struct {
+		name           string
+		input          auth.VerifyTwoFactorRequest
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "signed in",
+			input:          auth.VerifyTwoFactorRequest{ChallengeToken: "challenge_token", Code: "123456"},
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing challenge token",
+			input:          auth.VerifyTwoFactorRequest{Code: "123456"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.input)
+			req := httptest.NewRequest(http.MethodPost, "/auth/2fa/verify", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.expectCall {
+				mockAuth.EXPECT().
+					VerifyTwoFactor(gomock.Any(), gomock.Any()).
+					Return(&auth.VerifyTwoFactorResponse{TokenPair: auth.TokenPair{Token: "jwt_token", RefreshToken: "refresh_token"}}, nil)
+			}
+
+			err := handler.VerifyTwoFactor(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuth_ConfirmTwoFactor(t *testing.T) {
+	e, mockAuth, handler := setupAuthTest(t)
+
+	tests := []struct {
+		name           string
+		code           string
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "enabled",
+			code:           "123456",
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "code is not six digits",
+			code:           "12345a",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"code": tt.code})
+			req := httptest.NewRequest(http.MethodPost, "/auth/2fa/confirm", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.expectCall {
+				mockAuth.EXPECT().
+					ConfirmTwoFactor(gomock.Any(), &auth.ConfirmTwoFactorRequest{UserID: "user123", Code: tt.code}).
+					Return(&auth.ConfirmTwoFactorResponse{RecoveryCodes: []string{"abcd-efgh-ijkl-mnop"}}, nil)
+			}
+
+			err := handler.ConfirmTwoFactor(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, `{"recovery_codes":["abcd-efgh-ijkl-mnop"]}`, rec.Body.String())
+		})
+	}
+}
+
+func TestAuth_UpdateMe(t *testing.T) {
+	e, mockAuth, handler := setupAuthTest(t)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "updated",
+			body:           `{"first_name":"Jane"}`,
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "empty first name",
+			body:           `{"first_name":""}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/auth/me", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.expectCall {
+				firstName := "Jane"
+				mockAuth.EXPECT().
+					UpdateMe(gomock.Any(), &auth.UpdateMeRequest{UserID: "user123", FirstName: &firstName}).
+					Return(&auth.UpdateMeResponse{MeResponse: auth.MeResponse{UserInfo: auth.UserInfo{FirstName: "Jane", LastName: "Doe", Email: "test@example.com"}}}, nil)
+			}
+
+			err := handler.UpdateMe(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, `{"first_name":"Jane","last_name":"Doe","email":"test@example.com","email_verified":false}`, rec.Body.String())
+		})
+	}
+}
+
+func TestAuth_ChangePassword(t *testing.T) {
+	e, mockAuth, handler := setupAuthTest(t)
+
+	tests := []struct {
+		name           string
+		newPassword    string
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "changed",
+			newPassword:    "newpassword",
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "new password too short",
+			newPassword:    "short",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"current_password": "password123", "new_password": tt.newPassword})
+			req := httptest.NewRequest(http.MethodPost, "/auth/password/change", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", "user123")
+			req.Header.Set("Session-Id", "session1")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.expectCall {
+				mockAuth.EXPECT().
+					ChangePassword(gomock.Any(), &auth.ChangePasswordRequest{UserID: "user123", SessionID: "session1", IP: "192.0.2.1", CurrentPassword: "password123", NewPassword: tt.newPassword}).
+					Return(&auth.ChangePasswordResponse{Message: "changed"}, nil)
+			}
+
+			err := handler.ChangePassword(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuth_ChangeEmail(t *testing.T) {
+	e, mockAuth, handler := setupAuthTest(t)
+
+	tests := []struct {
+		name           string
+		email          string
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "link sent",
+			email:          "new@example.com",
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid email",
+			email:          "not-an-email",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"password": "password123", "email": tt.email})
+			req := httptest.NewRequest(http.MethodPost, "/auth/email/change", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.expectCall {
+				mockAuth.EXPECT().
+					ChangeEmail(gomock.Any(), &auth.ChangeEmailRequest{UserID: "user123", IP: "192.0.2.1", Password: "password123", Email: tt.email}).
+					Return(&auth.ChangeEmailResponse{Message: "sent"}, nil)
+			}
+
+			err := handler.ChangeEmail(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuth_DeleteMe(t *testing.T) {
+	e, mockAuth, handler := setupAuthTest(t)
+
+	body, _ := json.Marshal(map[string]string{"password": "password123", "code": "123456"})
+	req := httptest.NewRequest(http.MethodDelete, "/auth/me", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("User-Id", "user123")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockAuth.EXPECT().
+		DeleteMe(gomock.Any(), &auth.DeleteMeRequest{UserID: "user123", IP: "192.0.2.1", Password: "password123", Code: "123456"}).
+		Return(&auth.DeleteMeResponse{Message: "Account has been deleted"}, nil)
+
+	err := handler.DeleteMe(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"message":"Account has been deleted"}`, rec.Body.String())
+}
@@ -21,11 +21,16 @@ func NewBudget(s *service.Service) *Budget {
 	}
 }
 
-func (s *Budget) Register(server *server.Server) {
-	group := server.Group("/budget", middleware.JWT())
+func (s *Budget) Register(server *server.Server, mw middleware.Auth) {
+	group := server.Group("/budget", mw.JWT, mw.VerifiedEmail)
 
 	group.POST("", s.Create)
-	group.GET("", s.GetByUserID)
+	group.GET("", s.List)
+	group.GET("/:budget_id", s.GetByID)
+	group.PATCH("/:budget_id", s.Update)
+	group.DELETE("/:budget_id", s.Delete)
+	group.POST("/:budget_id/archive", s.Archive)
+	group.POST("/:budget_id/unarchive", s.Unarchive)
 	group.GET("/:budget_id/history", s.GetBudgetHistory)
 	group.GET("/:budget_id/balance", s.GetCurrentBalance)
 }
@@ -58,6 +63,35 @@ func (s *Budget) Create(c echo.Context) error {
 	return c.JSON(http.StatusCreated, res)
 }
 
+// @Summary List budgets
+// @Description Retrieves all budgets of the user, optionally including archived ones
+// @Tags Budget
+// @ID list-budgets
+// @Produce json
+// @Param user_id header string true "User ID"
+// @Param include_archived query bool false "Include archived budgets"
+// @Success 200 {object} budget.ListBudgetsResponse
+// @Router /budget [get]
+func (s *Budget) List(c echo.Context) error {
+	var (
+		err error
+		obj budget.ListBudgetsRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Budget.List(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error listing budgets", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
 // @Summary Get budget by ID
 // @Description Retrieves a budget by its ID for the specified user
 // @Tags Budget
@@ -67,7 +101,7 @@ func (s *Budget) Create(c echo.Context) error {
 // @Param user_id header string true "User ID"
 // @Success 200 {object} budget.GetBudgetByIDResponse
 // @Router /budget/{budget_id} [get]
-func (s *Budget) GetByUserID(c echo.Context) error {
+func (s *Budget) GetByID(c echo.Context) error {
 	var (
 		err error
 		obj budget.GetBudgetByIDRequest
@@ -78,7 +112,7 @@ func (s *Budget) GetByUserID(c echo.Context) error {
 		return err
 	}
 
-	res, err := s.service.Budget.GetByUserID(c.Request().Context(), &obj)
+	res, err := s.service.Budget.GetByID(c.Request().Context(), &obj)
 	if err != nil {
 		zap.L().Error("error getting budget by id", zap.Error(err))
 		return err
@@ -87,6 +121,123 @@ func (s *Budget) GetByUserID(c echo.Context) error {
 	return c.JSON(http.StatusOK, res)
 }
 
+// @Summary Update a budget
+// @Description Updates the name or currency of a budget. The currency cannot change once the budget has transactions
+// @Tags Budget
+// @ID update-budget
+// @Produce json
+// @Param budget_id path string true "BudgetObject ID"
+// @Param budget body budget.UpdateBudgetRequest true "Budget Details"
+// @Success 200 {object} budget.UpdateBudgetResponse
+// @Failure 409 {object} echo.HTTPError "The currency of a budget with transactions cannot change"
+// @Router /budget/{budget_id} [patch]
+func (s *Budget) Update(c echo.Context) error {
+	var (
+		err error
+		obj budget.UpdateBudgetRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Budget.Update(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error updating budget", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Archive a budget
+// @Description Hides a budget from the default list without deleting its data. An archived budget is read-only: transactions, transfers, imports and recurring runs against it are rejected until it is unarchived
+// @Tags Budget
+// @ID archive-budget
+// @Produce json
+// @Param budget_id path string true "BudgetObject ID"
+// @Param user_id header string true "User ID"
+// @Success 200 {object} budget.ArchiveBudgetResponse
+// @Router /budget/{budget_id}/archive [post]
+func (s *Budget) Archive(c echo.Context) error {
+	var (
+		err error
+		obj budget.ArchiveBudgetRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Budget.Archive(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error archiving budget", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Unarchive a budget
+// @Description Restores an archived budget to the default list
+// @Tags Budget
+// @ID unarchive-budget
+// @Produce json
+// @Param budget_id path string true "BudgetObject ID"
+// @Param user_id header string true "User ID"
+// @Success 200 {object} budget.UnarchiveBudgetResponse
+// @Router /budget/{budget_id}/unarchive [post]
+func (s *Budget) Unarchive(c echo.Context) error {
+	var (
+		err error
+		obj budget.UnarchiveBudgetRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Budget.Unarchive(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error unarchiving budget", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Delete a budget
+// @Description Deletes a budget together with its history and transactions
+// @Tags Budget
+// @ID delete-budget
+// @Produce json
+// @Param budget_id path string true "BudgetObject ID"
+// @Param user_id header string true "User ID"
+// @Success 200 {object} budget.DeleteBudgetResponse
+// @Router /budget/{budget_id} [delete]
+func (s *Budget) Delete(c echo.Context) error {
+	var (
+		err error
+		obj budget.DeleteBudgetRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Budget.Delete(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error deleting budget", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
 // @Summary Get budget history
 // @Description Retrieves the history of a budget for the specified user
 // @Tags Budget
@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/budget_member"
+	"finly-backend/internal/transport/http/middleware"
+	"finly-backend/pkg/bind"
+	"finly-backend/pkg/server"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type BudgetMember struct {
+	service *service.Service
+}
+
+func NewBudgetMember(s *service.Service) *BudgetMember {
+	return &BudgetMember{
+		service: s,
+	}
+}
+
+func (s *BudgetMember) Register(server *server.Server, mw middleware.Auth) {
+	group := server.Group("/budget", mw.JWT, mw.VerifiedEmail)
+
+	group.GET("/invitations", s.ListMyInvitations)
+	group.POST("/invitations/:id/accept", s.AcceptInvitation)
+	group.POST("/invitations/:id/decline", s.DeclineInvitation)
+	group.GET("/:budget_id/members", s.ListMembers)
+	group.PATCH("/:budget_id/members/:user_id", s.UpdateMember)
+	group.DELETE("/:budget_id/members/:user_id", s.RemoveMember)
+	group.POST("/:budget_id/invitations", s.CreateInvitation)
+	group.GET("/:budget_id/invitations", s.ListBudgetInvitations)
+	group.DELETE("/:budget_id/invitations/:id", s.RevokeInvitation)
+}
+
+// @Summary List budget members
+// @Description Lists the members of a budget with their roles, the owner first. Any member can list them.
+// @Tags Budget
+// @ID list-budget-members
+// @Produce json
+// @Param budget_id path string true "Budget ID"
+// @Success 200 {object} budget_member.ListMembersResponse
+// @Router /budget/{budget_id}/members [get]
+func (s *BudgetMember) ListMembers(c echo.Context) error {
+	var (
+		err error
+		obj budget_member.ListMembersRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.BudgetMember.ListMembers(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error listing budget members", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Change the role of a member
+// @Description Makes a member an editor or a viewer. Only the owner can change roles, and the owner's role can't be changed.
+// @Tags Budget
+// @ID update-budget-member
+// @Produce json
+// @Param budget_id path string true "Budget ID"
+// @Param user_id path string true "Member user ID"
+// @Param member body budget_member.UpdateMemberRequest true "New role"
+// @Success 200 {object} budget_member.UpdateMemberResponse
+// @Router /budget/{budget_id}/members/{user_id} [patch]
+func (s *BudgetMember) UpdateMember(c echo.Context) error {
+	var (
+		err error
+		obj budget_member.UpdateMemberRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.BudgetMember.UpdateMember(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error updating budget member", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Remove a member
+// @Description The owner removes a member, or a member removes themselves to leave the budget.
+// @Description Transactions the member created stay in the budget.
+// @Tags Budget
+// @ID remove-budget-member
+// @Produce json
+// @Param budget_id path string true "Budget ID"
+// @Param user_id path string true "Member user ID"
+// @Success 200 {object} budget_member.RemoveMemberResponse
+// @Router /budget/{budget_id}/members/{user_id} [delete]
+func (s *BudgetMember) RemoveMember(c echo.Context) error {
+	var (
+		err error
+		obj budget_member.RemoveMemberRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.BudgetMember.RemoveMember(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error removing budget member", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Invite someone to a budget
+// @Description Emails an invitation to join the budget as an editor or a viewer. It can be answered for 7 days by whoever signs in
+// @Description with the email. Inviting the same email again renews the pending invitation. Only the owner can invite.
+// @Tags Budget
+// @ID create-budget-invitation
+// @Produce json
+// @Param budget_id path string true "Budget ID"
+// @Param invitation body budget_member.CreateInvitationRequest true "Invitee"
+// @Success 201 {object} budget_member.CreateInvitationResponse
+// @Router /budget/{budget_id}/invitations [post]
+func (s *BudgetMember) CreateInvitation(c echo.Context) error {
+	var (
+		err error
+		obj budget_member.CreateInvitationRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.BudgetMember.CreateInvitation(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error creating budget invitation", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// @Summary List the pending invitations of a budget
+// @Description Lists the invitations to the budget that have not been answered and have not expired. Only the owner can list them.
+// @Tags Budget
+// @ID list-budget-invitations
+// @Produce json
+// @Param budget_id path string true "Budget ID"
+// @Success 200 {object} budget_member.ListBudgetInvitationsResponse
+// @Router /budget/{budget_id}/invitations [get]
+func (s *BudgetMember) ListBudgetInvitations(c echo.Context) error {
+	var (
+		err error
+		obj budget_member.ListBudgetInvitationsRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.BudgetMember.ListBudgetInvitations(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error listing budget invitations", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Revoke an invitation
+// @Description Withdraws a pending invitation to the budget. Only the owner can revoke invitations.
+// @Tags Budget
+// @ID revoke-budget-invitation
+// @Produce json
+// @Param budget_id path string true "Budget ID"
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} budget_member.RevokeInvitationResponse
+// @Router /budget/{budget_id}/invitations/{id} [delete]
+func (s *BudgetMember) RevokeInvitation(c echo.Context) error {
+	var (
+		err error
+		obj budget_member.RevokeInvitationRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.BudgetMember.RevokeInvitation(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error revoking budget invitation", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary List my invitations
+// @Description Lists the pending invitations sent to the email of the user, once it is verified
+// @Tags Budget
+// @ID list-my-budget-invitations
+// @Produce json
+// @Success 200 {object} budget_member.ListMyInvitationsResponse
+// @Failure 403 {object} echo.HTTPError "Email address is not verified"
+// @Router /budget/invitations [get]
+func (s *BudgetMember) ListMyInvitations(c echo.Context) error {
+	var (
+		err error
+		obj budget_member.ListMyInvitationsRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.BudgetMember.ListMyInvitations(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error listing invitations of the user", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Accept an invitation
+// @Description Joins the budget with the role of the invitation. The invitation must have been sent to the email of the user.
+// @Tags Budget
+// @ID accept-budget-invitation
+// @Produce json
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} budget_member.AcceptInvitationResponse
+// @Failure 403 {object} echo.HTTPError "Email address is not verified"
+// @Router /budget/invitations/{id}/accept [post]
+func (s *BudgetMember) AcceptInvitation(c echo.Context) error {
+	var (
+		err error
+		obj budget_member.RespondInvitationRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.BudgetMember.AcceptInvitation(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error accepting budget invitation", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Decline an invitation
+// @Description Declines an invitation sent to the email of the user
+// @Tags Budget
+// @ID decline-budget-invitation
+// @Produce json
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} budget_member.DeclineInvitationResponse
+// @Failure 403 {object} echo.HTTPError "Email address is not verified"
+// @Router /budget/invitations/{id}/decline [post]
+func (s *BudgetMember) DeclineInvitation(c echo.Context) error {
+	var (
+		err error
+		obj budget_member.RespondInvitationRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.BudgetMember.DeclineInvitation(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error declining budget invitation", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
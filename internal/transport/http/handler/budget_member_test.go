@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"bytes"
+	"finly-backend/internal/domain/enums/e_budget_role"
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/budget_member"
+	"finly-backend/internal/service/budget_member/mock"
+	"finly-backend/internal/transport/http/validation"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const invitationID = "0b7e6f4a-2c1d-4e5f-9a8b-7c6d5e4f3a2b"
+
+func setupBudgetMemberTest(t *testing.T) (*echo.Echo, *mock.MockBudgetMember, *BudgetMember) {
+	var err error
+
+	ctrl := gomock.NewController(t)
+	mockBudgetMember := mock.NewMockBudgetMember(ctrl)
+	service := &service.Service{BudgetMember: mockBudgetMember}
+	handler := NewBudgetMember(service)
+	e := echo.New()
+
+	if e.Validator, err = validation.New(); err != nil {
+		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
+	}
+
+	return e, mockBudgetMember, handler
+}
+
+func TestBudgetMember_UpdateMember(t *testing.T) {
+	e, mockBudgetMember, handler := setupBudgetMemberTest(t)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "make editor",
+			body:           `{"role":"editor"}`,
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "owner role cannot be given",
+			body:           `{"role":"owner"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/budget/budget123/members/user456", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("budget_id", "user_id")
+			c.SetParamValues("budget123", "user456")
+
+			if tt.expectCall {
+				mockBudgetMember.EXPECT().
+					UpdateMember(gomock.Any(), &budget_member.UpdateMemberRequest{
+						UserID: "user123", BudgetID: "budget123", MemberID: "user456", Role: e_budget_role.Editor,
+					}).
+					Return(&budget_member.UpdateMemberResponse{}, nil)
+			}
+
+			err := handler.UpdateMember(c)
+			if tt.expectedStatus == http.StatusOK {
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+			} else {
+				he, ok := err.(*echo.HTTPError)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, he.Code)
+			}
+		})
+	}
+}
+
+func TestBudgetMember_CreateInvitation(t *testing.T) {
+	e, mockBudgetMember, handler := setupBudgetMemberTest(t)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "invite viewer",
+			body:           `{"email":"partner@example.com","role":"viewer"}`,
+			expectCall:     true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid email",
+			body:           `{"email":"partner","role":"viewer"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing role",
+			body:           `{"email":"partner@example.com"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/budget/budget123/invitations", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("budget_id")
+			c.SetParamValues("budget123")
+
+			if tt.expectCall {
+				mockBudgetMember.EXPECT().
+					CreateInvitation(gomock.Any(), &budget_member.CreateInvitationRequest{
+						UserID: "user123", BudgetID: "budget123", Email: "partner@example.com", Role: e_budget_role.Viewer,
+					}).
+					Return(&budget_member.CreateInvitationResponse{ID: invitationID}, nil)
+			}
+
+			err := handler.CreateInvitation(c)
+			if tt.expectedStatus == http.StatusCreated {
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusCreated, rec.Code)
+			} else {
+				he, ok := err.(*echo.HTTPError)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, he.Code)
+			}
+		})
+	}
+}
+
+func TestBudgetMember_AcceptInvitation(t *testing.T) {
+	e, mockBudgetMember, handler := setupBudgetMemberTest(t)
+
+	tests := []struct {
+		name           string
+		invitationID   string
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "accept invitation",
+			invitationID:   invitationID,
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid invitation id",
+			invitationID:   "invitation1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/budget/invitations/"+tt.invitationID+"/accept", nil)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.invitationID)
+
+			if tt.expectCall {
+				mockBudgetMember.EXPECT().
+					AcceptInvitation(gomock.Any(), &budget_member.RespondInvitationRequest{UserID: "user123", ID: tt.invitationID}).
+					Return(&budget_member.AcceptInvitationResponse{BudgetID: "budget123", Role: "editor"}, nil)
+			}
+
+			err := handler.AcceptInvitation(c)
+			if tt.expectedStatus == http.StatusOK {
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+			} else {
+				he, ok := err.(*echo.HTTPError)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, he.Code)
+			}
+		})
+	}
+}
@@ -3,10 +3,11 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"finly-backend/internal/domain"
 	"finly-backend/internal/service"
 	"finly-backend/internal/service/budget"
 	"finly-backend/internal/service/budget/mock"
-	"finly-backend/pkg/validator"
+	"finly-backend/internal/transport/http/validation"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -26,7 +27,7 @@ func setupBudgetTest(t *testing.T) (*echo.Echo, *mock.MockBudget, *Budget) {
 	handler := NewBudget(service)
 	e := echo.New()
 
-	if e.Validator, err = validator.CustomValidator(); err != nil {
+	if e.Validator, err = validation.New(); err != nil {
 		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
 	}
 
@@ -50,7 +51,7 @@ func TestBudget_Create(t *testing.T) {
 			input: budget.CreateBudgetRequest{
 				UserID:   "user123",
 				Currency: "USD",
-				Amount:   1000.0,
+				Amount:   domain.MustParseMoney("1000.00"),
 			},
 			userID:         "user123",
 			mockResponse:   &budget.CreateBudgetResponse{ID: "budget123"},
@@ -62,7 +63,7 @@ func TestBudget_Create(t *testing.T) {
 			input: budget.CreateBudgetRequest{
 				UserID:   "",
 				Currency: "",
-				Amount:   0,
+				Amount:   domain.Money{},
 			},
 			userID:         "",
 			mockResponse:   nil,
@@ -106,28 +107,92 @@ func TestBudget_Create(t *testing.T) {
 	}
 }
 
-func TestBudget_GetByUserID(t *testing.T) {
+func TestBudget_List(t *testing.T) {
 	e, mockBudget, handler := setupBudgetTest(t)
 	defer gomock.NewController(t).Finish()
 
 	tests := []struct {
 		name           string
-		input          budget.GetBudgetByIDRequest
 		userID         string
-		mockResponse   *budget.GetBudgetByIDResponse
+		query          string
+		mockResponse   *budget.ListBudgetsResponse
 		mockError      error
 		expectedStatus int
 	}{
 		{
-			name: "successful budget retrieval",
-			input: budget.GetBudgetByIDRequest{
-				UserID: "user123",
-			},
+			name:   "successful budgets listing",
 			userID: "user123",
+			query:  "?include_archived=true",
+			mockResponse: &budget.ListBudgetsResponse{
+				Budgets: []*budget.BudgetObject{
+					{ID: "budget1", UserID: "user123", Name: "Cash", Currency: "USD"},
+					{ID: "budget2", UserID: "user123", Name: "Card", Currency: "USD"},
+				},
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid input",
+			userID:         "",
+			mockResponse:   nil,
+			mockError:      nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/budget"+tt.query, nil)
+			req.Header.Set("User-Id", tt.userID)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.mockResponse != nil {
+				mockBudget.EXPECT().
+					List(gomock.Any(), &budget.ListBudgetsRequest{UserID: tt.userID, IncludeArchived: true}).
+					Return(tt.mockResponse, tt.mockError)
+			}
+
+			err := handler.List(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var response budget.ListBudgetsResponse
+			err = json.Unmarshal(rec.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Len(t, response.Budgets, len(tt.mockResponse.Budgets))
+		})
+	}
+}
+
+func TestBudget_GetByID(t *testing.T) {
+	e, mockBudget, handler := setupBudgetTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		budgetID       string
+		userID         string
+		mockResponse   *budget.GetBudgetByIDResponse
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:     "successful budget retrieval",
+			budgetID: "budget123",
+			userID:   "user123",
 			mockResponse: &budget.GetBudgetByIDResponse{
 				BudgetObject: &budget.BudgetObject{
 					ID:        "budget123",
 					UserID:    "user123",
+					Name:      "Main",
 					Currency:  "USD",
 					CreatedAt: time.Now(),
 					UpdatedAt: time.Now(),
@@ -137,10 +202,8 @@ func TestBudget_GetByUserID(t *testing.T) {
 			expectedStatus: http.StatusOK,
 		},
 		{
-			name: "invalid input",
-			input: budget.GetBudgetByIDRequest{
-				UserID: "",
-			},
+			name:           "invalid input",
+			budgetID:       "budget123",
 			userID:         "",
 			mockResponse:   nil,
 			mockError:      nil,
@@ -150,18 +213,20 @@ func TestBudget_GetByUserID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req := httptest.NewRequest(http.MethodGet, "/budget", nil)
+			req := httptest.NewRequest(http.MethodGet, "/budget/"+tt.budgetID, nil)
 			req.Header.Set("User-Id", tt.userID)
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
+			c.SetParamNames("budget_id")
+			c.SetParamValues(tt.budgetID)
 
 			if tt.mockResponse != nil {
 				mockBudget.EXPECT().
-					GetByUserID(gomock.Any(), gomock.Any()).
+					GetByID(gomock.Any(), gomock.Any()).
 					Return(tt.mockResponse, tt.mockError)
 			}
 
-			err := handler.GetByUserID(c)
+			err := handler.GetByID(c)
 
 			if tt.expectedStatus == http.StatusBadRequest {
 				assert.Error(t, err)
@@ -181,6 +246,73 @@ func TestBudget_GetByUserID(t *testing.T) {
 	}
 }
 
+func TestBudget_Update(t *testing.T) {
+	e, mockBudget, handler := setupBudgetTest(t)
+	defer gomock.NewController(t).Finish()
+
+	body, _ := json.Marshal(map[string]string{"name": "Savings"})
+	req := httptest.NewRequest(http.MethodPatch, "/budget/budget123", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("User-Id", "user123")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("budget_id")
+	c.SetParamValues("budget123")
+
+	mockBudget.EXPECT().
+		Update(gomock.Any(), &budget.UpdateBudgetRequest{UserID: "user123", BudgetID: "budget123", Name: "Savings"}).
+		Return(&budget.UpdateBudgetResponse{}, nil)
+
+	err := handler.Update(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestBudget_ArchiveAndDelete(t *testing.T) {
+	e, mockBudget, handler := setupBudgetTest(t)
+	defer gomock.NewController(t).Finish()
+
+	newContext := func(method, path string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("User-Id", "user123")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("budget_id")
+		c.SetParamValues("budget123")
+		return c, rec
+	}
+
+	t.Run("archive", func(t *testing.T) {
+		c, rec := newContext(http.MethodPost, "/budget/budget123/archive")
+		mockBudget.EXPECT().
+			Archive(gomock.Any(), &budget.ArchiveBudgetRequest{UserID: "user123", BudgetID: "budget123"}).
+			Return(&budget.ArchiveBudgetResponse{}, nil)
+
+		assert.NoError(t, handler.Archive(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("unarchive", func(t *testing.T) {
+		c, rec := newContext(http.MethodPost, "/budget/budget123/unarchive")
+		mockBudget.EXPECT().
+			Unarchive(gomock.Any(), &budget.UnarchiveBudgetRequest{UserID: "user123", BudgetID: "budget123"}).
+			Return(&budget.UnarchiveBudgetResponse{}, nil)
+
+		assert.NoError(t, handler.Unarchive(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		c, rec := newContext(http.MethodDelete, "/budget/budget123")
+		mockBudget.EXPECT().
+			Delete(gomock.Any(), &budget.DeleteBudgetRequest{UserID: "user123", BudgetID: "budget123"}).
+			Return(&budget.DeleteBudgetResponse{}, nil)
+
+		assert.NoError(t, handler.Delete(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
 func TestBudget_GetBudgetHistory(t *testing.T) {
 	e, mockBudget, handler := setupBudgetTest(t)
 	defer gomock.NewController(t).Finish()
@@ -197,6 +329,7 @@ func TestBudget_GetBudgetHistory(t *testing.T) {
 			name:     "successful budget history retrieval",
 			budgetID: "budget123",
 			input: budget.GetBudgetHistoryRequest{
+				UserID:   "user123",
 				BudgetID: "budget123",
 			},
 			mockResponse: &budget.GetBudgetHistoryResponse{
@@ -204,7 +337,7 @@ func TestBudget_GetBudgetHistory(t *testing.T) {
 					{
 						ID:        "history1",
 						BudgetID:  "budget123",
-						Balance:   1000.0,
+						Balance:   domain.MustParseMoney("1000.00"),
 						CreatedAt: time.Now(),
 					},
 				},
@@ -227,6 +360,7 @@ func TestBudget_GetBudgetHistory(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			req := httptest.NewRequest(http.MethodGet, "/budget/"+tt.budgetID+"/history", nil)
+			req.Header.Set("User-Id", "user123")
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 			c.SetParamNames("budget_id")
@@ -277,10 +411,11 @@ func TestBudget_GetCurrentBalance(t *testing.T) {
 			name:     "successful balance retrieval",
 			budgetID: "budget123",
 			input: budget.GetCurrentBalanceRequest{
+				UserID:   "user123",
 				BudgetID: "budget123",
 			},
 			mockResponse: &budget.GetCurrentBalanceResponse{
-				Balance: 1000.0,
+				Balance: domain.MustParseMoney("1000.00"),
 			},
 			mockError:      nil,
 			expectedStatus: http.StatusOK,
@@ -300,6 +435,7 @@ func TestBudget_GetCurrentBalance(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			req := httptest.NewRequest(http.MethodGet, "/budget/"+tt.budgetID+"/balance", nil)
+			req.Header.Set("User-Id", "user123")
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 			c.SetParamNames("budget_id")
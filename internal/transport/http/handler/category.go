@@ -21,13 +21,19 @@ func NewCategory(s *service.Service) *Category {
 	}
 }
 
-func (s *Category) Register(server *server.Server) {
-	group := server.Group("/category", middleware.JWT())
+func (s *Category) Register(server *server.Server, mw middleware.Auth) {
+	group := server.Group("/category", mw.JWT, mw.VerifiedEmail)
 
 	group.POST("", s.Create)
 	group.GET("/:id", s.GetByID)
 	group.GET("", s.List)
 	group.GET("/custom", s.ListCustom)
+	group.PUT("/order", s.Reorder)
+	group.PATCH("/:id", s.Update)
+	group.PUT("/:id/parent", s.Move)
+	group.PUT("/:id/hidden", s.SetHidden)
+	group.DELETE("/:id/override", s.ResetOverride)
+	group.POST("/:id/merge", s.Merge)
 	group.DELETE("/:id", s.Delete)
 }
 
@@ -66,6 +72,7 @@ func (s *Category) Create(c echo.Context) error {
 // @Produce json
 // @Param id path string true "CategoryObject ID"
 // @Success 200 {object} category.GetCategoryByIDResponse
+// @Failure 404 {object} echo.HTTPError "Category not found"
 // @Router /category/{id} [get]
 func (s *Category) GetByID(c echo.Context) error {
 	var (
@@ -88,10 +95,12 @@ func (s *Category) GetByID(c echo.Context) error {
 }
 
 // @Summary List all categories
-// @Description Retrieves all categories for the user
+// @Description Retrieves all categories for the user as a tree, with subcategories nested under their parents.
+// @Description Hidden categories are left out unless include_hidden is true.
 // @Tags Category
 // @ID list-categories
 // @Produce json
+// @Param include_hidden query bool false "Include hidden categories"
 // @Success 200 {object} []category.GetCategoryByIDResponse
 // @Router /category [get]
 func (s *Category) List(c echo.Context) error {
@@ -114,12 +123,189 @@ func (s *Category) List(c echo.Context) error {
 	return c.JSON(http.StatusOK, res)
 }
 
+// @Summary Update a category
+// @Description Renames a category or changes its icon or color. Fields left out are kept.
+// @Description Changes to a default category only apply to the user.
+// @Tags Category
+// @ID update-category
+// @Produce json
+// @Param id path string true "CategoryObject ID"
+// @Param category body category.UpdateCategoryRequest true "Changed fields"
+// @Success 200 {object} category.UpdateCategoryResponse
+// @Router /category/{id} [patch]
+func (s *Category) Update(c echo.Context) error {
+	var (
+		err error
+		obj category.UpdateCategoryRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Category.Update(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error updating category", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Hide or show a category
+// @Description Hides a category and its subcategories from the category lists of the user, or shows them again
+// @Tags Category
+// @ID set-category-hidden
+// @Produce json
+// @Param id path string true "CategoryObject ID"
+// @Param hidden body category.SetCategoryHiddenRequest true "Whether the category is hidden"
+// @Success 200 {object} category.SetCategoryHiddenResponse
+// @Router /category/{id}/hidden [put]
+func (s *Category) SetHidden(c echo.Context) error {
+	var (
+		err error
+		obj category.SetCategoryHiddenRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Category.SetHidden(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error hiding category", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Reset a category override
+// @Description Drops the name, icon, color and hiding the user set on a default category
+// @Tags Category
+// @ID reset-category-override
+// @Produce json
+// @Param id path string true "CategoryObject ID"
+// @Success 200 {object} category.ResetCategoryOverrideResponse
+// @Router /category/{id}/override [delete]
+func (s *Category) ResetOverride(c echo.Context) error {
+	var (
+		err error
+		obj category.ResetCategoryOverrideRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Category.ResetOverride(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error resetting category override", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Reorder categories
+// @Description Lists the given categories first, in the given order. Categories left out are listed after them, oldest first.
+// @Tags Category
+// @ID reorder-categories
+// @Produce json
+// @Param order body category.ReorderCategoriesRequest true "Category IDs in order"
+// @Success 200 {object} category.ReorderCategoriesResponse
+// @Router /category/order [put]
+func (s *Category) Reorder(c echo.Context) error {
+	var (
+		err error
+		obj category.ReorderCategoriesRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Category.Reorder(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error reordering categories", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Merge a category into another
+// @Description Moves the transactions, recurring transactions and limits of a custom category to target_id
+// @Description and deletes it. Its subcategories become top-level categories.
+// @Tags Category
+// @ID merge-category
+// @Produce json
+// @Param id path string true "CategoryObject ID"
+// @Param target body category.MergeCategoryRequest true "Target category"
+// @Success 200 {object} category.MergeCategoryResponse
+// @Router /category/{id}/merge [post]
+func (s *Category) Merge(c echo.Context) error {
+	var (
+		err error
+		obj category.MergeCategoryRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Category.Merge(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error merging category", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Move a category
+// @Description Makes a custom category a subcategory of parent_id, or a top-level category when parent_id is empty.
+// @Description A category cannot be moved under itself or one of its subcategories.
+// @Tags Category
+// @ID move-category
+// @Produce json
+// @Param id path string true "CategoryObject ID"
+// @Param parent body category.MoveCategoryRequest true "New parent"
+// @Success 200 {object} category.MoveCategoryResponse
+// @Router /category/{id}/parent [put]
+func (s *Category) Move(c echo.Context) error {
+	var (
+		err error
+		obj category.MoveCategoryRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Category.Move(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error moving category", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
 // @Summary Delete a category
-// @Description Deletes the category with the given ID
+// @Description Deletes the category with the given ID. Its subcategories become top-level categories.
+// @Description A category used by transactions is only deleted with reassign_to, which moves them to that category.
 // @Tags Category
 // @ID delete-category
 // @Produce json
 // @Param id path string true "CategoryObject ID"
+// @Param reassign_to query string false "Category to move the transactions to"
 // @Success 200 {object} category.DeleteCategoryResponse
 // @Router /category/{id} [delete]
 func (s *Category) Delete(c echo.Context) error {
@@ -143,10 +329,11 @@ func (s *Category) Delete(c echo.Context) error {
 }
 
 // @Summary List custom categories
-// @Description Retrieves custom categories for the user
+// @Description Retrieves custom categories for the user. Hidden categories are left out unless include_hidden is true.
 // @Tags Category
 // @ID list-custom-categories
 // @Produce json
+// @Param include_hidden query bool false "Include hidden categories"
 // @Success 200 {object} []category.ListCustomCategoriesResponse
 func (s *Category) ListCustom(c echo.Context) error {
 	var (
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/category_limit"
+	"finly-backend/internal/transport/http/middleware"
+	"finly-backend/pkg/bind"
+	"finly-backend/pkg/server"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type CategoryLimit struct {
+	service *service.Service
+}
+
+func NewCategoryLimit(s *service.Service) *CategoryLimit {
+	return &CategoryLimit{
+		service: s,
+	}
+}
+
+func (s *CategoryLimit) Register(server *server.Server, mw middleware.Auth) {
+	group := server.Group("/limit", mw.JWT, mw.VerifiedEmail)
+
+	group.POST("", s.Create)
+	group.GET("", s.List)
+	group.PATCH("/:id", s.Update)
+	group.DELETE("/:id", s.Delete)
+}
+
+// @Summary Create a category spending limit
+// @Description Limits what may be spent on a category of a budget per month, per week or within a custom range.
+// @Description Withdrawals over the limit return warnings, or are rejected when the limit is enforced.
+// @Tags Limit
+// @ID create-limit
+// @Produce json
+// @Param limit body category_limit.CreateLimitRequest true "Limit Details"
+// @Success 201 {object} category_limit.CreateLimitResponse
+// @Router /limit [post]
+func (s *CategoryLimit) Create(c echo.Context) error {
+	var (
+		err error
+		obj category_limit.CreateLimitRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.CategoryLimit.Create(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error creating category limit", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// @Summary List category limits with their status
+// @Description Retrieves the limits of a budget with the amount spent, remaining and used percentage in the current period
+// @Tags Limit
+// @ID list-limits
+// @Produce json
+// @Param budget_id query string true "Budget ID"
+// @Success 200 {object} category_limit.ListLimitsResponse
+// @Router /limit [get]
+func (s *CategoryLimit) List(c echo.Context) error {
+	var (
+		err error
+		obj category_limit.ListLimitsRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders(), bind.FromQuery()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.CategoryLimit.List(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error listing category limits", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Update a category limit
+// @Description Changes the amount or enforcement of a limit, or the range of a custom limit
+// @Tags Limit
+// @ID update-limit
+// @Produce json
+// @Param id path string true "Limit ID"
+// @Param limit body category_limit.UpdateLimitRequest true "Limit Details"
+// @Success 200 {object} category_limit.UpdateLimitResponse
+// @Router /limit/{id} [patch]
+func (s *CategoryLimit) Update(c echo.Context) error {
+	var (
+		err error
+		obj category_limit.UpdateLimitRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.CategoryLimit.Update(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error updating category limit", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Delete a category limit
+// @Tags Limit
+// @ID delete-limit
+// @Produce json
+// @Param id path string true "Limit ID"
+// @Success 200 {object} category_limit.DeleteLimitResponse
+// @Router /limit/{id} [delete]
+func (s *CategoryLimit) Delete(c echo.Context) error {
+	var (
+		err error
+		obj category_limit.DeleteLimitRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.CategoryLimit.Delete(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error deleting category limit", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_limit_period"
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/category_limit"
+	"finly-backend/internal/service/category_limit/mock"
+	"finly-backend/internal/transport/http/validation"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupCategoryLimitTest(t *testing.T) (*echo.Echo, *mock.MockCategoryLimit, *CategoryLimit) {
+	var err error
+
+	ctrl := gomock.NewController(t)
+	mockCategoryLimit := mock.NewMockCategoryLimit(ctrl)
+	service := &service.Service{CategoryLimit: mockCategoryLimit}
+	handler := NewCategoryLimit(service)
+	e := echo.New()
+
+	if e.Validator, err = validation.New(); err != nil {
+		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
+	}
+
+	return e, mockCategoryLimit, handler
+}
+
+func TestCategoryLimit_Create(t *testing.T) {
+	e, mockCategoryLimit, handler := setupCategoryLimitTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		input          category_limit.CreateLimitRequest
+		mockResponse   *category_limit.CreateLimitResponse
+		expectedStatus int
+	}{
+		{
+			name: "successful limit creation",
+			input: category_limit.CreateLimitRequest{
+				BudgetID:   "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f",
+				CategoryID: "7a8b9c0d-1e2f-4a3b-9c4d-5e6f7a8b9c0d",
+				Amount:     domain.MustParseMoney("400.00"),
+				Period:     e_limit_period.Month,
+			},
+			mockResponse:   &category_limit.CreateLimitResponse{ID: "limit123"},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "custom period without range",
+			input: category_limit.CreateLimitRequest{
+				BudgetID:   "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f",
+				CategoryID: "7a8b9c0d-1e2f-4a3b-9c4d-5e6f7a8b9c0d",
+				Amount:     domain.MustParseMoney("400.00"),
+				Period:     e_limit_period.Custom,
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown period",
+			input: category_limit.CreateLimitRequest{
+				BudgetID:   "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f",
+				CategoryID: "7a8b9c0d-1e2f-4a3b-9c4d-5e6f7a8b9c0d",
+				Amount:     domain.MustParseMoney("400.00"),
+				Period:     "year",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.input)
+			req := httptest.NewRequest(http.MethodPost, "/limit", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.mockResponse != nil {
+				mockCategoryLimit.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(tt.mockResponse, nil)
+			}
+
+			err := handler.Create(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var response category_limit.CreateLimitResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.mockResponse.ID, response.ID)
+		})
+	}
+}
+
+func TestCategoryLimit_List(t *testing.T) {
+	e, mockCategoryLimit, handler := setupCategoryLimitTest(t)
+	defer gomock.NewController(t).Finish()
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockResponse   *category_limit.ListLimitsResponse
+		expectedStatus int
+	}{
+		{
+			name:  "limits with status",
+			query: "budget_id=6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f",
+			mockResponse: &category_limit.ListLimitsResponse{Limits: []*category_limit.LimitStatusObject{{
+				LimitObject: &category_limit.LimitObject{ID: "limit123", Amount: domain.MustParseMoney("400.00"), Period: e_limit_period.Month},
+				PeriodStart: start,
+				PeriodEnd:   start.AddDate(0, 1, 0),
+				Active:      true,
+				Spent:       domain.MustParseMoney("100.00"),
+				Remaining:   domain.MustParseMoney("300.00"),
+				Percentage:  25,
+			}}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing budget",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/limit?"+tt.query, nil)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.mockResponse != nil {
+				mockCategoryLimit.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					Return(tt.mockResponse, nil)
+			}
+
+			err := handler.List(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var response map[string][]map[string]any
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Len(t, response["limits"], 1)
+			assert.Equal(t, "limit123", response["limits"][0]["id"])
+			assert.Equal(t, 300.0, response["limits"][0]["remaining"])
+			assert.Equal(t, 25.0, response["limits"][0]["percentage"])
+		})
+	}
+}
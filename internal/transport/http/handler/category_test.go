@@ -6,7 +6,7 @@ import (
 	"finly-backend/internal/service"
 	"finly-backend/internal/service/category"
 	"finly-backend/internal/service/category/mock"
-	"finly-backend/pkg/validator"
+	"finly-backend/internal/transport/http/validation"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -26,7 +26,7 @@ func setupCategoryTest(t *testing.T) (*echo.Echo, *mock.MockCategory, *Category)
 	handler := NewCategory(service)
 	e := echo.New()
 
-	if e.Validator, err = validator.CustomValidator(); err != nil {
+	if e.Validator, err = validation.New(); err != nil {
 		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
 	}
 
@@ -351,6 +351,199 @@ func TestCategory_ListCustom(t *testing.T) {
 	}
 }
 
+func TestCategory_Update(t *testing.T) {
+	e, mockCategory, handler := setupCategoryTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		userID         string
+		body           map[string]string
+		callService    bool
+		expectedStatus int
+	}{
+		{
+			name:           "successful category update",
+			userID:         "user123",
+			body:           map[string]string{"name": "Eating out", "color": "#ff5722"},
+			callService:    true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "clear color",
+			userID:         "user123",
+			body:           map[string]string{"color": ""},
+			callService:    true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid color",
+			userID:         "user123",
+			body:           map[string]string{"color": "red"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "empty name",
+			userID:         "user123",
+			body:           map[string]string{"name": ""},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPatch, "/category/category123", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", tt.userID)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("category123")
+
+			if tt.callService {
+				mockCategory.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					Return(&category.UpdateCategoryResponse{}, nil)
+			}
+
+			err := handler.Update(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestCategory_Merge(t *testing.T) {
+	e, mockCategory, handler := setupCategoryTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		targetID       string
+		callService    bool
+		expectedStatus int
+	}{
+		{
+			name:           "successful category merge",
+			targetID:       "category456",
+			callService:    true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing target",
+			targetID:       "",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"target_id": tt.targetID})
+			req := httptest.NewRequest(http.MethodPost, "/category/category123/merge", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("category123")
+
+			if tt.callService {
+				mockCategory.EXPECT().
+					Merge(gomock.Any(), &category.MergeCategoryRequest{UserID: "user123", ID: "category123", TargetID: tt.targetID}).
+					Return(&category.MergeCategoryResponse{}, nil)
+			}
+
+			err := handler.Merge(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestCategory_Move(t *testing.T) {
+	e, mockCategory, handler := setupCategoryTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		categoryID     string
+		userID         string
+		parentID       string
+		mockResponse   *category.MoveCategoryResponse
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "successful category move",
+			categoryID:     "category123",
+			userID:         "user123",
+			parentID:       "category456",
+			mockResponse:   &category.MoveCategoryResponse{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "cycle",
+			categoryID:     "category123",
+			userID:         "user123",
+			parentID:       "category123",
+			mockError:      echo.NewHTTPError(http.StatusBadRequest, "cycle"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid input",
+			categoryID:     "category123",
+			userID:         "",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"parent_id": tt.parentID})
+			req := httptest.NewRequest(http.MethodPut, "/category/"+tt.categoryID+"/parent", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", tt.userID)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.categoryID)
+
+			if tt.mockResponse != nil || tt.mockError != nil {
+				mockCategory.EXPECT().
+					Move(gomock.Any(), &category.MoveCategoryRequest{
+						UserID:   tt.userID,
+						ID:       tt.categoryID,
+						ParentID: tt.parentID,
+					}).
+					Return(tt.mockResponse, tt.mockError)
+			}
+
+			err := handler.Move(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
 func TestCategory_Delete(t *testing.T) {
 	e, mockCategory, handler := setupCategoryTest(t)
 	defer gomock.NewController(t).Finish()
@@ -423,3 +616,194 @@ func TestCategory_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestCategory_SetHidden(t *testing.T) {
+	e, mockCategory, handler := setupCategoryTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		categoryID     string
+		userID         string
+		hidden         bool
+		mockResponse   *category.SetCategoryHiddenResponse
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "successful category hiding",
+			categoryID:     "category123",
+			userID:         "user123",
+			hidden:         true,
+			mockResponse:   &category.SetCategoryHiddenResponse{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "not found",
+			categoryID:     "category123",
+			userID:         "user123",
+			hidden:         true,
+			mockError:      echo.NewHTTPError(http.StatusNotFound, "not found"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid input",
+			categoryID:     "category123",
+			userID:         "",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]bool{"hidden": tt.hidden})
+			req := httptest.NewRequest(http.MethodPut, "/category/"+tt.categoryID+"/hidden", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", tt.userID)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.categoryID)
+
+			if tt.mockResponse != nil || tt.mockError != nil {
+				mockCategory.EXPECT().
+					SetHidden(gomock.Any(), &category.SetCategoryHiddenRequest{
+						UserID: tt.userID,
+						ID:     tt.categoryID,
+						Hidden: tt.hidden,
+					}).
+					Return(tt.mockResponse, tt.mockError)
+			}
+
+			err := handler.SetHidden(c)
+
+			if tt.mockError != nil || tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestCategory_ResetOverride(t *testing.T) {
+	e, mockCategory, handler := setupCategoryTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		categoryID     string
+		userID         string
+		mockResponse   *category.ResetCategoryOverrideResponse
+		expectedStatus int
+	}{
+		{
+			name:           "successful override reset",
+			categoryID:     "category123",
+			userID:         "user123",
+			mockResponse:   &category.ResetCategoryOverrideResponse{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid input",
+			categoryID:     "",
+			userID:         "",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/category/"+tt.categoryID+"/override", nil)
+			req.Header.Set("User-Id", tt.userID)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.categoryID)
+
+			if tt.mockResponse != nil {
+				mockCategory.EXPECT().
+					ResetOverride(gomock.Any(), &category.ResetCategoryOverrideRequest{
+						UserID: tt.userID,
+						ID:     tt.categoryID,
+					}).
+					Return(tt.mockResponse, nil)
+			}
+
+			err := handler.ResetOverride(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestCategory_Reorder(t *testing.T) {
+	e, mockCategory, handler := setupCategoryTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		userID         string
+		categoryIDs    []string
+		mockResponse   *category.ReorderCategoriesResponse
+		expectedStatus int
+	}{
+		{
+			name:           "successful reorder",
+			userID:         "user123",
+			categoryIDs:    []string{"category456", "category123"},
+			mockResponse:   &category.ReorderCategoriesResponse{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "empty order",
+			userID:         "user123",
+			categoryIDs:    []string{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "empty category ID",
+			userID:         "user123",
+			categoryIDs:    []string{"category123", ""},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string][]string{"category_ids": tt.categoryIDs})
+			req := httptest.NewRequest(http.MethodPut, "/category/order", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", tt.userID)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.mockResponse != nil {
+				mockCategory.EXPECT().
+					Reorder(gomock.Any(), &category.ReorderCategoriesRequest{
+						UserID:      tt.userID,
+						CategoryIDs: tt.categoryIDs,
+					}).
+					Return(tt.mockResponse, nil)
+			}
+
+			err := handler.Reorder(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
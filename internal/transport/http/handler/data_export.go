@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/data_export"
+	"finly-backend/internal/transport/http/middleware"
+	"finly-backend/pkg/bind"
+	"finly-backend/pkg/server"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type DataExport struct {
+	service *service.Service
+}
+
+func NewDataExport(s *service.Service) *DataExport {
+	return &DataExport{
+		service: s,
+	}
+}
+
+func (s *DataExport) Register(server *server.Server, mw middleware.Auth) {
+	group := server.Group("/export", mw.JWT, mw.VerifiedEmail)
+
+	group.POST("", s.Create)
+	group.GET("/:id", s.Get)
+	group.GET("/:id/download", s.Download)
+}
+
+// @Summary Request a personal data export
+// @Description Queues a ZIP archive of the profile, budgets, budget history, transactions and custom categories,
+// @Description each as JSON and CSV. The archive is built in the background; poll the export until it is completed.
+// @Description While an export is queued or running, it is returned instead of starting another one.
+// @Tags Export
+// @ID create-data-export
+// @Produce json
+// @Success 202 {object} data_export.CreateDataExportResponse
+// @Failure 403 {object} echo.HTTPError "Email address is not verified"
+// @Router /export [post]
+func (s *DataExport) Create(c echo.Context) error {
+	var (
+		err error
+		obj data_export.CreateDataExportRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.DataExport.Create(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error creating data export", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, res)
+}
+
+// @Summary Get a data export
+// @Description Retrieves the status of a data export: pending, running, completed or failed
+// @Tags Export
+// @ID get-data-export
+// @Produce json
+// @Param id path string true "Export ID"
+// @Success 200 {object} data_export.GetDataExportResponse
+// @Router /export/{id} [get]
+func (s *DataExport) Get(c echo.Context) error {
+	var (
+		err error
+		obj data_export.GetDataExportRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.DataExport.Get(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error getting data export", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Download a data export
+// @Description Downloads the ZIP archive of a completed export. Archives can be downloaded until they expire.
+// @Tags Export
+// @ID download-data-export
+// @Produce application/zip
+// @Param id path string true "Export ID"
+// @Success 200 {file} file
+// @Router /export/{id}/download [get]
+func (s *DataExport) Download(c echo.Context) error {
+	var (
+		err error
+		obj data_export.DownloadDataExportRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.DataExport.Download(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error downloading data export", zap.Error(err))
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", res.Filename))
+	return c.Blob(http.StatusOK, "application/zip", res.Archive)
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/json"
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/data_export"
+	"finly-backend/internal/service/data_export/mock"
+	"finly-backend/internal/transport/http/validation"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const exportID = "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f"
+
+func setupDataExportTest(t *testing.T) (*echo.Echo, *mock.MockDataExport, *DataExport) {
+	var err error
+
+	ctrl := gomock.NewController(t)
+	mockDataExport := mock.NewMockDataExport(ctrl)
+	service := &service.Service{DataExport: mockDataExport}
+	handler := NewDataExport(service)
+	e := echo.New()
+
+	if e.Validator, err = validation.New(); err != nil {
+		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
+	}
+
+	return e, mockDataExport, handler
+}
+
+func TestDataExport_Create(t *testing.T) {
+	e, mockDataExport, handler := setupDataExportTest(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/export", nil)
+	req.Header.Set("User-Id", "user123")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockResponse := &data_export.CreateDataExportResponse{DataExportObject: data_export.DataExportObject{ID: exportID, Status: "pending"}}
+	mockDataExport.EXPECT().
+		Create(gomock.Any(), &data_export.CreateDataExportRequest{UserID: "user123"}).
+		Return(mockResponse, nil)
+
+	err := handler.Create(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	var res data_export.CreateDataExportResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, mockResponse.ID, res.ID)
+	assert.Equal(t, mockResponse.Status, res.Status)
+}
+
+func TestDataExport_Get(t *testing.T) {
+	e, mockDataExport, handler := setupDataExportTest(t)
+
+	tests := []struct {
+		name           string
+		exportID       string
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "get export",
+			exportID:       exportID,
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid export id",
+			exportID:       "export1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/export/"+tt.exportID, nil)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.exportID)
+
+			if tt.expectCall {
+				mockDataExport.EXPECT().
+					Get(gomock.Any(), &data_export.GetDataExportRequest{UserID: "user123", ID: tt.exportID}).
+					Return(&data_export.GetDataExportResponse{}, nil)
+			}
+
+			err := handler.Get(c)
+			if tt.expectedStatus == http.StatusOK {
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+			} else {
+				he, ok := err.(*echo.HTTPError)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, he.Code)
+			}
+		})
+	}
+}
+
+func TestDataExport_Download(t *testing.T) {
+	e, mockDataExport, handler := setupDataExportTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/export/"+exportID+"/download", nil)
+	req.Header.Set("User-Id", "user123")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(exportID)
+
+	mockDataExport.EXPECT().
+		Download(gomock.Any(), &data_export.DownloadDataExportRequest{UserID: "user123", ID: exportID}).
+		Return(&data_export.DownloadDataExportResponse{Filename: "finly-export-2025-04-01.zip", Archive: []byte("zip")}, nil)
+
+	err := handler.Download(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename="finly-export-2025-04-01.zip"`, rec.Header().Get(echo.HeaderContentDisposition))
+	assert.Equal(t, "zip", rec.Body.String())
+}
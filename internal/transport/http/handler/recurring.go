@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/recurring"
+	"finly-backend/internal/transport/http/middleware"
+	"finly-backend/pkg/bind"
+	"finly-backend/pkg/server"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type Recurring struct {
+	service *service.Service
+}
+
+func NewRecurring(s *service.Service) *Recurring {
+	return &Recurring{
+		service: s,
+	}
+}
+
+func (s *Recurring) Register(server *server.Server, mw middleware.Auth) {
+	group := server.Group("/recurring", mw.JWT, mw.VerifiedEmail)
+
+	group.POST("", s.Create)
+	group.GET("", s.List)
+	group.GET("/:id", s.GetByID)
+	group.PATCH("/:id", s.Update)
+	group.DELETE("/:id", s.Delete)
+}
+
+// @Summary Create a recurring transaction
+// @Description Creates a rule that books a transaction on a daily, weekly, monthly or cron schedule
+// @Tags Recurring
+// @ID create-recurring
+// @Produce json
+// @Param recurring body recurring.CreateRecurringRequest true "Recurring Transaction Details"
+// @Success 201 {object} recurring.CreateRecurringResponse
+// @Router /recurring [post]
+func (s *Recurring) Create(c echo.Context) error {
+	var (
+		err error
+		obj recurring.CreateRecurringRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Recurring.Create(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error creating recurring transaction", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// @Summary List recurring transactions
+// @Description Retrieves all recurring transaction rules of the user
+// @Tags Recurring
+// @ID list-recurring
+// @Produce json
+// @Success 200 {object} recurring.ListRecurringResponse
+// @Router /recurring [get]
+func (s *Recurring) List(c echo.Context) error {
+	var (
+		err error
+		obj recurring.ListRecurringRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Recurring.List(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error listing recurring transactions", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Get a recurring transaction
+// @Description Retrieves a recurring transaction rule by its ID
+// @Tags Recurring
+// @ID get-recurring
+// @Produce json
+// @Param id path string true "Recurring Transaction ID"
+// @Success 200 {object} recurring.GetRecurringByIDResponse
+// @Router /recurring/{id} [get]
+func (s *Recurring) GetByID(c echo.Context) error {
+	var (
+		err error
+		obj recurring.GetRecurringByIDRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Recurring.GetByID(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error getting recurring transaction", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Update a recurring transaction
+// @Description Changes the amount, note, category or end date of a rule, or pauses and resumes it
+// @Tags Recurring
+// @ID update-recurring
+// @Produce json
+// @Param id path string true "Recurring Transaction ID"
+// @Param recurring body recurring.UpdateRecurringRequest true "Recurring Transaction Details"
+// @Success 200 {object} recurring.UpdateRecurringResponse
+// @Router /recurring/{id} [patch]
+func (s *Recurring) Update(c echo.Context) error {
+	var (
+		err error
+		obj recurring.UpdateRecurringRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Recurring.Update(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error updating recurring transaction", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Delete a recurring transaction
+// @Description Deletes a recurring transaction rule; transactions it already created are kept
+// @Tags Recurring
+// @ID delete-recurring
+// @Produce json
+// @Param id path string true "Recurring Transaction ID"
+// @Success 200 {object} recurring.DeleteRecurringResponse
+// @Router /recurring/{id} [delete]
+func (s *Recurring) Delete(c echo.Context) error {
+	var (
+		err error
+		obj recurring.DeleteRecurringRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Recurring.Delete(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error deleting recurring transaction", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_recurrence_frequency"
+	"finly-backend/internal/domain/enums/e_transaction_type"
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/recurring"
+	"finly-backend/internal/service/recurring/mock"
+	"finly-backend/internal/transport/http/validation"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupRecurringTest(t *testing.T) (*echo.Echo, *mock.MockRecurring, *Recurring) {
+	var err error
+
+	ctrl := gomock.NewController(t)
+	mockRecurring := mock.NewMockRecurring(ctrl)
+	service := &service.Service{Recurring: mockRecurring}
+	handler := NewRecurring(service)
+	e := echo.New()
+
+	if e.Validator, err = validation.New(); err != nil {
+		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
+	}
+
+	return e, mockRecurring, handler
+}
+
+func TestRecurring_Create(t *testing.T) {
+	e, mockRecurring, handler := setupRecurringTest(t)
+	defer gomock.NewController(t).Finish()
+
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		input          recurring.CreateRecurringRequest
+		userID         string
+		mockResponse   *recurring.CreateRecurringResponse
+		expectedStatus int
+	}{
+		{
+			name: "successful recurring creation",
+			input: recurring.CreateRecurringRequest{
+				BudgetID:   "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f",
+				CategoryID: "7a8b9c0d-1e2f-4a3b-9c4d-5e6f7a8b9c0d",
+				Amount:     domain.MustParseMoney("1200.00"),
+				Type:       e_transaction_type.Withdrawal,
+				Note:       "Rent",
+				Frequency:  e_recurrence_frequency.Monthly,
+				StartAt:    start,
+			},
+			userID:         "user123",
+			mockResponse:   &recurring.CreateRecurringResponse{ID: "rule123", NextRunAt: &start},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "cron frequency without expression",
+			input: recurring.CreateRecurringRequest{
+				BudgetID:   "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f",
+				CategoryID: "7a8b9c0d-1e2f-4a3b-9c4d-5e6f7a8b9c0d",
+				Amount:     domain.MustParseMoney("10.00"),
+				Type:       e_transaction_type.Withdrawal,
+				Frequency:  e_recurrence_frequency.Cron,
+				StartAt:    start,
+			},
+			userID:         "user123",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.input)
+			req := httptest.NewRequest(http.MethodPost, "/recurring", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", tt.userID)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.mockResponse != nil {
+				mockRecurring.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(tt.mockResponse, nil)
+			}
+
+			err := handler.Create(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var response recurring.CreateRecurringResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.mockResponse.ID, response.ID)
+		})
+	}
+}
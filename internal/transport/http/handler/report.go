@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/report"
+	"finly-backend/internal/transport/http/middleware"
+	"finly-backend/pkg/bind"
+	"finly-backend/pkg/server"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type Report struct {
+	service *service.Service
+}
+
+func NewReport(s *service.Service) *Report {
+	return &Report{
+		service: s,
+	}
+}
+
+func (s *Report) Register(server *server.Server, mw middleware.Auth) {
+	group := server.Group("/report", mw.JWT)
+
+	group.GET("/summary", s.Summary)
+	group.GET("/categories", s.Categories)
+	group.GET("/timeline", s.Timeline)
+}
+
+// @Summary Income, expense and net cash flow of a budget
+// @Description Sums the transactions of a budget within [from, to) and compares them with the previous period.
+// @Description Without from and to the current calendar month is reported.
+// @Tags Report
+// @ID report-summary
+// @Produce json
+// @Param budget_id query string true "Budget ID"
+// @Param from query string false "Start of the period (RFC 3339, inclusive)"
+// @Param to query string false "End of the period (RFC 3339, exclusive)"
+// @Success 200 {object} report.SummaryResponse
+// @Router /report/summary [get]
+func (s *Report) Summary(c echo.Context) error {
+	var (
+		err error
+		obj report.SummaryRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders(), bind.FromQuery()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Report.Summary(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error building summary report", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Totals of a budget by category
+// @Description Sums deposits and withdrawals per category within [from, to) and compares them with the previous period
+// @Tags Report
+// @ID report-categories
+// @Produce json
+// @Param budget_id query string true "Budget ID"
+// @Param from query string false "Start of the period (RFC 3339, inclusive)"
+// @Param to query string false "End of the period (RFC 3339, exclusive)"
+// @Success 200 {object} report.CategoriesResponse
+// @Router /report/categories [get]
+func (s *Report) Categories(c echo.Context) error {
+	var (
+		err error
+		obj report.CategoriesRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders(), bind.FromQuery()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Report.Categories(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error building category report", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Cash flow of a budget over time
+// @Description Sums the transactions of a budget per day, week or month within [from, to),
+// @Description compared bucket by bucket with the previous period
+// @Tags Report
+// @ID report-timeline
+// @Produce json
+// @Param budget_id query string true "Budget ID"
+// @Param from query string false "Start of the period (RFC 3339, inclusive)"
+// @Param to query string false "End of the period (RFC 3339, exclusive)"
+// @Param granularity query string false "Bucket size" Enums(day, week, month) default(day)
+// @Success 200 {object} report.TimelineResponse
+// @Router /report/timeline [get]
+func (s *Report) Timeline(c echo.Context) error {
+	var (
+		err error
+		obj report.TimelineRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders(), bind.FromQuery()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Report.Timeline(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error building timeline report", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"encoding/json"
+	"finly-backend/internal/domain"
+	"finly-backend/internal/domain/enums/e_report_granularity"
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/report"
+	"finly-backend/internal/service/report/mock"
+	"finly-backend/internal/transport/http/validation"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupReportTest(t *testing.T) (*echo.Echo, *mock.MockReport, *Report) {
+	var err error
+
+	ctrl := gomock.NewController(t)
+	mockReport := mock.NewMockReport(ctrl)
+	service := &service.Service{Report: mockReport}
+	handler := NewReport(service)
+	e := echo.New()
+
+	if e.Validator, err = validation.New(); err != nil {
+		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
+	}
+
+	return e, mockReport, handler
+}
+
+func TestReport_Summary(t *testing.T) {
+	e, mockReport, handler := setupReportTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		query          string
+		mockResponse   *report.SummaryResponse
+		expectedStatus int
+	}{
+		{
+			name:  "summary of a month",
+			query: "budget_id=6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f&from=2025-03-01T00:00:00Z&to=2025-04-01T00:00:00Z",
+			mockResponse: &report.SummaryResponse{
+				Net: report.Comparison{
+					Current:  domain.MustParseMoney("1500.00"),
+					Previous: domain.MustParseMoney("1000.00"),
+					Change:   domain.MustParseMoney("500.00"),
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing budget",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid date",
+			query:          "budget_id=6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f&from=2025-03-01",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/report/summary?"+tt.query, nil)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.mockResponse != nil {
+				mockReport.EXPECT().
+					Summary(gomock.Any(), &report.SummaryRequest{ReportRequest: report.ReportRequest{
+						UserID:   "user123",
+						BudgetID: "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f",
+						From:     "2025-03-01T00:00:00Z",
+						To:       "2025-04-01T00:00:00Z",
+					}}).
+					Return(tt.mockResponse, nil)
+			}
+
+			err := handler.Summary(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var response map[string]map[string]any
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, 1500.0, response["net"]["current"])
+			assert.Equal(t, 500.0, response["net"]["change"])
+			assert.NotContains(t, response["net"], "change_percent")
+		})
+	}
+}
+
+func TestReport_Categories(t *testing.T) {
+	e, mockReport, handler := setupReportTest(t)
+	defer gomock.NewController(t).Finish()
+
+	req := httptest.NewRequest(http.MethodGet, "/report/categories?budget_id=6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f", nil)
+	req.Header.Set("User-Id", "user123")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockReport.EXPECT().
+		Categories(gomock.Any(), gomock.Any()).
+		Return(&report.CategoriesResponse{Categories: []*report.CategoryReportObject{{
+			CategoryID:   "groceries",
+			CategoryName: "Groceries",
+			Expense:      report.Comparison{Current: domain.MustParseMoney("300.00")},
+			Share:        75,
+		}}}, nil)
+
+	assert.NoError(t, handler.Categories(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	categories := response["categories"].([]any)
+	assert.Len(t, categories, 1)
+	assert.Equal(t, "Groceries", categories[0].(map[string]any)["category_name"])
+	assert.Equal(t, 75.0, categories[0].(map[string]any)["share"])
+}
+
+func TestReport_Timeline(t *testing.T) {
+	e, mockReport, handler := setupReportTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		query          string
+		mockResponse   *report.TimelineResponse
+		expectedStatus int
+	}{
+		{
+			name:           "weekly timeline",
+			query:          "budget_id=6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f&granularity=week",
+			mockResponse:   &report.TimelineResponse{Granularity: e_report_granularity.Week, Buckets: []*report.BucketObject{{}}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unsupported granularity",
+			query:          "budget_id=6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f&granularity=year",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/report/timeline?"+tt.query, nil)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.mockResponse != nil {
+				mockReport.EXPECT().
+					Timeline(gomock.Any(), gomock.Cond(func(req *report.TimelineRequest) bool {
+						return req.Granularity == e_report_granularity.Week && req.UserID == "user123"
+					})).
+					Return(tt.mockResponse, nil)
+			}
+
+			err := handler.Timeline(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var response map[string]any
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, "week", response["granularity"])
+			assert.Len(t, response["buckets"], 1)
+		})
+	}
+}
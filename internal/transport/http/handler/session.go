@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/session"
+	"finly-backend/internal/transport/http/middleware"
+	"finly-backend/pkg/bind"
+	"finly-backend/pkg/server"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type Session struct {
+	service *service.Service
+}
+
+func NewSession(s *service.Service) *Session {
+	return &Session{
+		service: s,
+	}
+}
+
+func (s *Session) Register(server *server.Server, mw middleware.Auth) {
+	group := server.Group("/session", mw.JWT)
+
+	group.GET("", s.List)
+	group.DELETE("/others", s.RevokeOthers)
+	group.DELETE("/:id", s.Revoke)
+}
+
+// @Summary List active sessions
+// @Description Retrieves the devices the user is signed in on, with their IP address and when they were last used
+// @Tags Session
+// @ID list-sessions
+// @Produce json
+// @Success 200 {object} session.ListSessionsResponse
+// @Router /session [get]
+func (s *Session) List(c echo.Context) error {
+	var (
+		err error
+		obj session.ListSessionsRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Session.List(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error listing sessions", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Revoke a session
+// @Description Signs a device out. Its access and refresh tokens stop working immediately.
+// @Tags Session
+// @ID revoke-session
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} session.RevokeSessionResponse
+// @Router /session/{id} [delete]
+func (s *Session) Revoke(c echo.Context) error {
+	var (
+		err error
+		obj session.RevokeSessionRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Session.Revoke(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error revoking session", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Revoke all other sessions
+// @Description Signs out every device except the one making the request
+// @Tags Session
+// @ID revoke-other-sessions
+// @Produce json
+// @Success 200 {object} session.RevokeOtherSessionsResponse
+// @Router /session/others [delete]
+func (s *Session) RevokeOthers(c echo.Context) error {
+	var (
+		err error
+		obj session.RevokeOtherSessionsRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Session.RevokeOthers(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error revoking other sessions", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
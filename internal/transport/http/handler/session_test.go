@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/session"
+	"finly-backend/internal/service/session/mock"
+	"finly-backend/internal/transport/http/validation"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupSessionTest(t *testing.T) (*echo.Echo, *mock.MockSession, *Session) {
+	var err error
+
+	ctrl := gomock.NewController(t)
+	mockSession := mock.NewMockSession(ctrl)
+	service := &service.Service{Session: mockSession}
+	handler := NewSession(service)
+	e := echo.New()
+
+	if e.Validator, err = validation.New(); err != nil {
+		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
+	}
+
+	return e, mockSession, handler
+}
+
+func TestSession_List(t *testing.T) {
+	e, mockSession, handler := setupSessionTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/session", nil)
+	req.Header.Set("User-Id", "user123")
+	req.Header.Set("Session-Id", "session1")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockResponse := &session.ListSessionsResponse{Sessions: []*session.SessionObject{{ID: "session1", Current: true}}}
+	mockSession.EXPECT().
+		List(gomock.Any(), &session.ListSessionsRequest{UserID: "user123", SessionID: "session1"}).
+		Return(mockResponse, nil)
+
+	err := handler.List(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var res session.ListSessionsResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, *mockResponse, res)
+}
+
+func TestSession_Revoke(t *testing.T) {
+	e, mockSession, handler := setupSessionTest(t)
+
+	tests := []struct {
+		name           string
+		sessionID      string
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "revoke session",
+			sessionID:      "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f",
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid session id",
+			sessionID:      "session1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/session/"+tt.sessionID, nil)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.sessionID)
+
+			if tt.expectCall {
+				mockSession.EXPECT().
+					Revoke(gomock.Any(), &session.RevokeSessionRequest{UserID: "user123", ID: tt.sessionID}).
+					Return(&session.RevokeSessionResponse{}, nil)
+			}
+
+			err := handler.Revoke(c)
+			if tt.expectedStatus == http.StatusOK {
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+			} else {
+				he, ok := err.(*echo.HTTPError)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, he.Code)
+			}
+		})
+	}
+}
+
+func TestSession_RevokeOthers(t *testing.T) {
+	e, mockSession, handler := setupSessionTest(t)
+
+	req := httptest.NewRequest(http.MethodDelete, "/session/others", nil)
+	req.Header.Set("User-Id", "user123")
+	req.Header.Set("Session-Id", "session1")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockSession.EXPECT().
+		RevokeOthers(gomock.Any(), &session.RevokeOtherSessionsRequest{UserID: "user123", SessionID: "session1"}).
+		Return(&session.RevokeOtherSessionsResponse{Revoked: 2}, nil)
+
+	err := handler.RevokeOthers(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"revoked":2}`, rec.Body.String())
+}
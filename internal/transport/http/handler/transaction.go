@@ -6,6 +6,7 @@ import (
 	"finly-backend/internal/transport/http/middleware"
 	"finly-backend/pkg/bind"
 	"finly-backend/pkg/server"
+	"fmt"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 	"net/http"
@@ -21,17 +22,24 @@ func NewTransaction(s *service.Service) *Transaction {
 	}
 }
 
-func (s *Transaction) Register(server *server.Server) {
-	group := server.Group("/transaction", middleware.JWT())
+func (s *Transaction) Register(server *server.Server, mw middleware.Auth) {
+	group := server.Group("/transaction", mw.JWT, mw.VerifiedEmail)
 
 	group.POST("", s.Create)
 	group.GET("", s.List)
 	group.PATCH("/:id", s.Update)
 	group.DELETE("/:id", s.Delete)
+
+	group.POST("/transfer", s.CreateTransfer)
+	group.PATCH("/transfer/:transfer_id", s.UpdateTransfer)
+	group.DELETE("/transfer/:transfer_id", s.DeleteTransfer)
+
+	group.POST("/import", s.Import)
+	group.GET("/export", s.Export)
 }
 
 // @Summary Create a new transaction
-// @Description Creates a new transaction for the user with the provided details
+// @Description Creates a new transaction for the user with the provided details. Without a category_id, the user's transaction rules pick the category and tags.
 // @Tags Transaction
 // @ID create-transaction
 // @Produce json
@@ -59,11 +67,21 @@ func (s *Transaction) Create(c echo.Context) error {
 }
 
 // @Summary List transactions
-// @Description Retrieves a list of transactions for the user
+// @Description Retrieves a filtered, sorted page of the user's transactions
 // @Tags Transaction
 // @ID list-transactions
 // @Produce json
-// @Param user_id query string true "User ID"
+// @Param from query string false "Created at or after (RFC 3339)"
+// @Param to query string false "Created at or before (RFC 3339)"
+// @Param budget_id query string false "Budget ID"
+// @Param category_id query string false "Category ID"
+// @Param type query string false "Transaction type" Enums(deposit, withdrawal)
+// @Param min_amount query number false "Minimum amount"
+// @Param max_amount query number false "Maximum amount"
+// @Param q query string false "Text search in note"
+// @Param sort query string false "Sort order" Enums(created_at_desc, created_at_asc, amount_desc, amount_asc)
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param limit query int false "Page size (1-100, default 50)"
 // @Success 200 {object} transaction.ListTransactionResponse
 // @Router /transaction [get]
 func (s *Transaction) List(c echo.Context) error {
@@ -72,7 +90,7 @@ func (s *Transaction) List(c echo.Context) error {
 		obj transaction.ListTransactionRequest
 	)
 
-	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+	if err = bind.Validate(c, &obj, bind.FromHeaders(), bind.FromQuery()); err != nil {
 		zap.L().Error("error binding and validating request", zap.Error(err))
 		return err
 	}
@@ -142,3 +160,228 @@ func (s *Transaction) Delete(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, res)
 }
+
+// @Summary Create a transfer
+// @Description Moves money from one of the user's budgets to another as a linked pair of transactions
+// @Tags Transaction
+// @ID create-transfer
+// @Produce json
+// @Param transfer body transaction.CreateTransferRequest true "Transfer Details"
+// @Success 201 {object} transaction.CreateTransferResponse
+// @Router /transaction/transfer [post]
+func (s *Transaction) CreateTransfer(c echo.Context) error {
+	var (
+		err error
+		obj transaction.CreateTransferRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Transaction.CreateTransfer(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error creating transfer", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// @Summary Update a transfer
+// @Description Updates the amount and/or note of both legs of a transfer
+// @Tags Transaction
+// @ID update-transfer
+// @Produce json
+// @Param transfer_id path string true "Transfer ID"
+// @Param transfer body transaction.UpdateTransferRequest true "Transfer Details"
+// @Success 200 {object} transaction.UpdateTransferResponse
+// @Router /transaction/transfer/{transfer_id} [patch]
+func (s *Transaction) UpdateTransfer(c echo.Context) error {
+	var (
+		err error
+		obj transaction.UpdateTransferRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Transaction.UpdateTransfer(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error updating transfer", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Delete a transfer
+// @Description Deletes both legs of a transfer and restores the affected balances
+// @Tags Transaction
+// @ID delete-transfer
+// @Produce json
+// @Param transfer_id path string true "Transfer ID"
+// @Success 200 {object} transaction.DeleteTransferResponse
+// @Router /transaction/transfer/{transfer_id} [delete]
+func (s *Transaction) DeleteTransfer(c echo.Context) error {
+	var (
+		err error
+		obj transaction.DeleteTransferRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Transaction.DeleteTransfer(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error deleting transfer", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Import transactions from a CSV bank statement
+// @Description Books every row of the uploaded statement into a budget at its statement date, all or nothing.
+// @Description CSV columns are referenced by header name or 1-based position. Rows with errors are reported and nothing is imported.
+// @Description Rows already imported into the budget (same FITID, or same date, amount, type and note) are skipped.
+// @Tags Transaction
+// @ID import-transactions
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Bank statement (CSV, OFX/QFX or QIF)"
+// @Param budget_id formData string true "Budget to import into"
+// @Param category_id formData string false "Category assigned to the imported transactions; without it, transaction rules categorize each row"
+// @Param format formData string false "File format: csv, ofx, qfx or qif (default csv)"
+// @Param date_column formData string false "Date column (CSV only, required there)"
+// @Param amount_column formData string false "Signed amount column"
+// @Param debit_column formData string false "Outgoing amount column, used with credit_column"
+// @Param credit_column formData string false "Incoming amount column, used with debit_column"
+// @Param type_column formData string false "Column with the direction (debit/credit, withdrawal/deposit)"
+// @Param note_column formData string false "Note column"
+// @Param date_format formData string false "Date format for CSV and QIF, e.g. DD.MM.YYYY (default YYYY-MM-DD for CSV)"
+// @Param decimal_separator formData string false "Decimal separator: . or , (default .)"
+// @Param delimiter formData string false "Field delimiter (default ,)"
+// @Param no_header formData bool false "The file has no header row"
+// @Param dry_run formData bool false "Only preview the import"
+// @Success 200 {object} transaction.ImportTransactionsResponse "Dry run preview"
+// @Success 201 {object} transaction.ImportTransactionsResponse
+// @Failure 422 {object} transaction.ImportTransactionsResponse "Some rows failed, nothing was imported"
+// @Router /transaction/import [post]
+func (s *Transaction) Import(c echo.Context) error {
+	var (
+		err error
+		obj transaction.ImportTransactionsRequest
+	)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "CSV file is required")
+	}
+	if header.Size > transaction.MaxImportFileSize {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "CSV file is too large")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		zap.L().Error("error opening uploaded file", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "CSV file could not be read")
+	}
+	defer file.Close()
+	obj.File = file
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.Transaction.Import(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error importing transactions", zap.Error(err))
+		return err
+	}
+
+	status := http.StatusCreated
+	switch {
+	case res.Failed > 0:
+		status = http.StatusUnprocessableEntity
+	case res.DryRun:
+		status = http.StatusOK
+	}
+
+	return c.JSON(status, res)
+}
+
+// @Summary Export transactions
+// @Description Streams every transaction matching the list filters as CSV, JSON Lines or an OFX statement,
+// @Description with category names and the budget balance after each transaction. Rows are oldest first by default.
+// @Tags Transaction
+// @ID export-transactions
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/x-ofx
+// @Param format query string false "Export format (default csv)" Enums(csv, jsonl, ofx)
+// @Param from query string false "Created at or after (RFC 3339)"
+// @Param to query string false "Created at or before (RFC 3339)"
+// @Param budget_id query string false "Budget ID, required for OFX"
+// @Param category_id query string false "Category ID"
+// @Param type query string false "Transaction type" Enums(deposit, withdrawal, transfer_in, transfer_out)
+// @Param min_amount query number false "Minimum amount"
+// @Param max_amount query number false "Maximum amount"
+// @Param q query string false "Text search in note"
+// @Param sort query string false "Sort order (default created_at_asc)" Enums(created_at_desc, created_at_asc, amount_desc, amount_asc)
+// @Success 200 {file} file
+// @Router /transaction/export [get]
+func (s *Transaction) Export(c echo.Context) error {
+	var (
+		err error
+		obj transaction.ExportTransactionsRequest
+	)
+
+	out := &exportWriter{res: c.Response()}
+	obj.Output = out
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders(), bind.FromQuery()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+	if obj.Format == "" {
+		obj.Format = transaction.ExportFormatCSV
+	}
+	out.contentType = transaction.ExportContentTypes[obj.Format]
+	out.filename = "transactions." + obj.Format
+
+	if _, err = s.service.Transaction.Export(c.Request().Context(), &obj); err != nil {
+		zap.L().Error("error exporting transactions", zap.Error(err))
+		if c.Response().Committed {
+			// The status line is already sent; dropping the connection is all that is left.
+			return nil
+		}
+		return err
+	}
+
+	_, err = out.Write(nil)
+	return err
+}
+
+// exportWriter sends the download headers together with the first bytes of the export,
+// so errors raised before any output still reach the client as regular JSON errors.
+type exportWriter struct {
+	res         *echo.Response
+	contentType string
+	filename    string
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.res.Committed {
+		w.res.Header().Set(echo.HeaderContentType, w.contentType)
+		w.res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", w.filename))
+		w.res.WriteHeader(http.StatusOK)
+	}
+	return w.res.Write(p)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/transaction_rule"
+	"finly-backend/internal/transport/http/middleware"
+	"finly-backend/pkg/bind"
+	"finly-backend/pkg/server"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type TransactionRule struct {
+	service *service.Service
+}
+
+func NewTransactionRule(s *service.Service) *TransactionRule {
+	return &TransactionRule{
+		service: s,
+	}
+}
+
+func (s *TransactionRule) Register(server *server.Server, mw middleware.Auth) {
+	group := server.Group("/rule", mw.JWT, mw.VerifiedEmail)
+
+	group.POST("", s.Create)
+	group.GET("", s.List)
+	group.POST("/apply", s.Apply)
+	group.GET("/:id", s.GetByID)
+	group.PUT("/:id", s.Update)
+	group.DELETE("/:id", s.Delete)
+}
+
+// @Summary Create a transaction rule
+// @Description Creates a rule that sets the category or adds a tag to matching transactions created or imported without a category
+// @Tags Rules
+// @ID create-rule
+// @Produce json
+// @Param rule body transaction_rule.CreateRuleRequest true "Transaction Rule Details"
+// @Success 201 {object} transaction_rule.CreateRuleResponse
+// @Router /rule [post]
+func (s *TransactionRule) Create(c echo.Context) error {
+	var (
+		err error
+		obj transaction_rule.CreateRuleRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.TransactionRule.Create(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error creating transaction rule", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// @Summary List transaction rules
+// @Description Retrieves the transaction rules of the user in the order they run
+// @Tags Rules
+// @ID list-rules
+// @Produce json
+// @Success 200 {object} transaction_rule.ListRulesResponse
+// @Router /rule [get]
+func (s *TransactionRule) List(c echo.Context) error {
+	var (
+		err error
+		obj transaction_rule.ListRulesRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.TransactionRule.List(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error listing transaction rules", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Get a transaction rule
+// @Description Retrieves a transaction rule by its ID
+// @Tags Rules
+// @ID get-rule
+// @Produce json
+// @Param id path string true "Transaction Rule ID"
+// @Success 200 {object} transaction_rule.GetRuleByIDResponse
+// @Router /rule/{id} [get]
+func (s *TransactionRule) GetByID(c echo.Context) error {
+	var (
+		err error
+		obj transaction_rule.GetRuleByIDRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.TransactionRule.GetByID(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error getting transaction rule", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Update a transaction rule
+// @Description Replaces the conditions, actions and name of a rule; the priority is kept when none is given
+// @Tags Rules
+// @ID update-rule
+// @Produce json
+// @Param id path string true "Transaction Rule ID"
+// @Param rule body transaction_rule.UpdateRuleRequest true "Transaction Rule Details"
+// @Success 200 {object} transaction_rule.UpdateRuleResponse
+// @Router /rule/{id} [put]
+func (s *TransactionRule) Update(c echo.Context) error {
+	var (
+		err error
+		obj transaction_rule.UpdateRuleRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.TransactionRule.Update(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error updating transaction rule", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Delete a transaction rule
+// @Description Deletes a transaction rule; transactions it already categorized are kept as they are
+// @Tags Rules
+// @ID delete-rule
+// @Produce json
+// @Param id path string true "Transaction Rule ID"
+// @Success 200 {object} transaction_rule.DeleteRuleResponse
+// @Router /rule/{id} [delete]
+func (s *TransactionRule) Delete(c echo.Context) error {
+	var (
+		err error
+		obj transaction_rule.DeleteRuleRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.TransactionRule.Delete(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error deleting transaction rule", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Summary Re-apply transaction rules
+// @Description Runs the rules over past transactions of a budget or of the user, optionally within a date range, and updates their categories and tags
+// @Tags Rules
+// @ID apply-rules
+// @Produce json
+// @Param apply body transaction_rule.ApplyRulesRequest true "Transactions to re-apply the rules to"
+// @Success 200 {object} transaction_rule.ApplyRulesResponse
+// @Router /rule/apply [post]
+func (s *TransactionRule) Apply(c echo.Context) error {
+	var (
+		err error
+		obj transaction_rule.ApplyRulesRequest
+	)
+
+	if err = bind.Validate(c, &obj, bind.FromHeaders()); err != nil {
+		zap.L().Error("error binding and validating request", zap.Error(err))
+		return err
+	}
+
+	res, err := s.service.TransactionRule.Apply(c.Request().Context(), &obj)
+	if err != nil {
+		zap.L().Error("error applying transaction rules", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
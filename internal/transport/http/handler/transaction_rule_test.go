@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"finly-backend/internal/domain/enums/e_transaction_type"
+	"finly-backend/internal/service"
+	"finly-backend/internal/service/transaction_rule"
+	"finly-backend/internal/service/transaction_rule/mock"
+	"finly-backend/internal/transport/http/validation"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupTransactionRuleTest(t *testing.T) (*echo.Echo, *mock.MockTransactionRule, *TransactionRule) {
+	var err error
+
+	ctrl := gomock.NewController(t)
+	mockRule := mock.NewMockTransactionRule(ctrl)
+	service := &service.Service{TransactionRule: mockRule}
+	handler := NewTransactionRule(service)
+	e := echo.New()
+
+	if e.Validator, err = validation.New(); err != nil {
+		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
+	}
+
+	return e, mockRule, handler
+}
+
+func TestTransactionRule_Create(t *testing.T) {
+	e, mockRule, handler := setupTransactionRuleTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		input          transaction_rule.RuleDefinition
+		userID         string
+		mockResponse   *transaction_rule.CreateRuleResponse
+		expectedStatus int
+	}{
+		{
+			name: "successful rule creation",
+			input: transaction_rule.RuleDefinition{
+				Name:         "Uber",
+				NoteContains: "UBER",
+				Type:         e_transaction_type.Withdrawal,
+				CategoryID:   "7a8b9c0d-1e2f-4a3b-9c4d-5e6f7a8b9c0d",
+			},
+			userID:         "user123",
+			mockResponse:   &transaction_rule.CreateRuleResponse{ID: "rule123"},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing name",
+			input:          transaction_rule.RuleDefinition{Tag: "rides"},
+			userID:         "user123",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "transfer type",
+			input:          transaction_rule.RuleDefinition{Name: "Transfers", Type: e_transaction_type.TransferOut, Tag: "moved"},
+			userID:         "user123",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.input)
+			req := httptest.NewRequest(http.MethodPost, "/rule", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", tt.userID)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.mockResponse != nil {
+				mockRule.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, req *transaction_rule.CreateRuleRequest) (*transaction_rule.CreateRuleResponse, error) {
+						assert.Equal(t, tt.userID, req.UserID)
+						assert.Equal(t, tt.input, req.RuleDefinition)
+						return tt.mockResponse, nil
+					})
+			}
+
+			err := handler.Create(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var response transaction_rule.CreateRuleResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.mockResponse.ID, response.ID)
+		})
+	}
+}
+
+func TestTransactionRule_Apply(t *testing.T) {
+	e, mockRule, handler := setupTransactionRuleTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockResponse   *transaction_rule.ApplyRulesResponse
+		expectedStatus int
+	}{
+		{
+			name:           "rules applied to a budget",
+			body:           `{"budget_id":"6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f","from":"2025-01-01T00:00:00Z"}`,
+			mockResponse:   &transaction_rule.ApplyRulesResponse{Checked: 10, Updated: 3},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid budget id",
+			body:           `{"budget_id":"budget123"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/rule/apply", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.mockResponse != nil {
+				mockRule.EXPECT().
+					Apply(gomock.Any(), gomock.Any()).
+					Return(tt.mockResponse, nil)
+			}
+
+			err := handler.Apply(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var response transaction_rule.ApplyRulesResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, *tt.mockResponse, response)
+		})
+	}
+}
@@ -2,16 +2,20 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"finly-backend/internal/domain"
 	"finly-backend/internal/domain/enums/e_transaction_type"
 	"finly-backend/internal/service"
 	"finly-backend/internal/service/transaction"
 	"finly-backend/internal/service/transaction/mock"
-	"finly-backend/pkg/validator"
+	"finly-backend/internal/transport/http/validation"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -27,7 +31,7 @@ func setupTransactionTest(t *testing.T) (*echo.Echo, *mock.MockTransaction, *Tra
 	handler := NewTransaction(service)
 	e := echo.New()
 
-	if e.Validator, err = validator.CustomValidator(); err != nil {
+	if e.Validator, err = validation.New(); err != nil {
 		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
 	}
 
@@ -52,7 +56,7 @@ func TestTransaction_Create(t *testing.T) {
 				UserID:     "user123",
 				CategoryID: "category123",
 				BudgetID:   "budget123",
-				Amount:     100.0,
+				Amount:     domain.MustParseMoney("100.00"),
 				Type:       e_transaction_type.Deposit,
 				Note:       "Grocery purchase",
 			},
@@ -67,7 +71,7 @@ func TestTransaction_Create(t *testing.T) {
 				UserID:     "",
 				CategoryID: "",
 				BudgetID:   "",
-				Amount:     0,
+				Amount:     domain.Money{},
 				Type:       "",
 			},
 			userID:         "",
@@ -119,6 +123,7 @@ func TestTransaction_List(t *testing.T) {
 	tests := []struct {
 		name           string
 		userID         string
+		query          string
 		input          transaction.ListTransactionRequest
 		mockResponse   *transaction.ListTransactionResponse
 		mockError      error
@@ -137,7 +142,7 @@ func TestTransaction_List(t *testing.T) {
 						UserID:     "user123",
 						CategoryID: "category123",
 						BudgetID:   "budget123",
-						Amount:     100.0,
+						Amount:     domain.MustParseMoney("100.00"),
 						Type:       e_transaction_type.Deposit,
 						Note:       "Grocery purchase",
 						CreatedAt:  time.Now(),
@@ -147,6 +152,18 @@ func TestTransaction_List(t *testing.T) {
 			mockError:      nil,
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:   "filtered transactions list retrieval",
+			userID: "user123",
+			query:  "type=withdrawal&min_amount=10.50&sort=amount_desc&limit=20",
+			mockResponse: &transaction.ListTransactionResponse{
+				Transactions: []transaction.TransactionObject{},
+				NextCursor:   "abc",
+				Total:        3,
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusOK,
+		},
 		{
 			name:   "invalid input",
 			userID: "",
@@ -157,11 +174,19 @@ func TestTransaction_List(t *testing.T) {
 			mockError:      nil,
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:           "invalid query",
+			userID:         "user123",
+			query:          "type=transfer&limit=500",
+			mockResponse:   nil,
+			mockError:      nil,
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req := httptest.NewRequest(http.MethodGet, "/transaction?user_id="+tt.userID, nil)
+			req := httptest.NewRequest(http.MethodGet, "/transaction?"+tt.query, nil)
 			req.Header.Set("User-Id", tt.userID)
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
@@ -187,6 +212,8 @@ func TestTransaction_List(t *testing.T) {
 				err = json.Unmarshal(rec.Body.Bytes(), &response)
 				assert.NoError(t, err)
 				assert.Equal(t, len(tt.mockResponse.Transactions), len(response.Transactions))
+				assert.Equal(t, tt.mockResponse.NextCursor, response.NextCursor)
+				assert.Equal(t, tt.mockResponse.Total, response.Total)
 				if len(tt.mockResponse.Transactions) > 0 {
 					assert.Equal(t, tt.mockResponse.Transactions[0].ID, response.Transactions[0].ID)
 				}
@@ -217,7 +244,7 @@ func TestTransaction_Update(t *testing.T) {
 				TransactionID: "transaction123",
 				CategoryID:    "category456",
 				BudgetID:      456,
-				Amount:        200.0,
+				Amount:        domain.MustParseMoney("200.00"),
 				Type:          string(e_transaction_type.Withdrawal),
 				Note:          "Updated note",
 			},
@@ -225,6 +252,16 @@ func TestTransaction_Update(t *testing.T) {
 			mockError:      nil,
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:          "transfer type",
+			transactionID: "transaction123",
+			userID:        "user123",
+			input: transaction.UpdateTransactionRequest{
+				Amount: domain.MustParseMoney("200.00"),
+				Type:   string(e_transaction_type.TransferIn),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:          "invalid input",
 			transactionID: "",
@@ -347,3 +384,276 @@ func TestTransaction_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestTransaction_CreateTransfer(t *testing.T) {
+	e, mockTransaction, handler := setupTransactionTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		body           string
+		userID         string
+		mockResponse   *transaction.CreateTransferResponse
+		expectedStatus int
+	}{
+		{
+			name:   "successful transfer creation",
+			body:   `{"from_budget_id":"0f8fad5b-d9cb-469f-a165-70867728950e","to_budget_id":"7c9e6679-7425-40de-944b-e07fc1f90ae7","amount":25.50}`,
+			userID: "user123",
+			mockResponse: &transaction.CreateTransferResponse{
+				TransferID: "transfer123",
+				OutgoingID: "out123",
+				IncomingID: "in123",
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "negative amount",
+			body:           `{"from_budget_id":"0f8fad5b-d9cb-469f-a165-70867728950e","to_budget_id":"7c9e6679-7425-40de-944b-e07fc1f90ae7","amount":-5}`,
+			userID:         "user123",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing destination budget",
+			body:           `{"from_budget_id":"0f8fad5b-d9cb-469f-a165-70867728950e","amount":5}`,
+			userID:         "user123",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/transaction/transfer", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("User-Id", tt.userID)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.mockResponse != nil {
+				mockTransaction.EXPECT().
+					CreateTransfer(gomock.Any(), gomock.Any()).
+					Return(tt.mockResponse, nil)
+			}
+
+			err := handler.CreateTransfer(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var response transaction.CreateTransferResponse
+			err = json.Unmarshal(rec.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, *tt.mockResponse, response)
+		})
+	}
+}
+
+func TestTransaction_Import(t *testing.T) {
+	e, mockTransaction, handler := setupTransactionTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name           string
+		fields         map[string]string
+		file           string
+		mockResponse   *transaction.ImportTransactionsResponse
+		expectedStatus int
+	}{
+		{
+			name: "successful import",
+			fields: map[string]string{
+				"budget_id":     "0f8fad5b-d9cb-469f-a165-70867728950e",
+				"category_id":   "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+				"date_column":   "Date",
+				"amount_column": "Amount",
+			},
+			file:           "Date,Amount\n2025-01-05,12.50\n",
+			mockResponse:   &transaction.ImportTransactionsResponse{Total: 1, Imported: 1},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "dry run",
+			fields: map[string]string{
+				"budget_id":     "0f8fad5b-d9cb-469f-a165-70867728950e",
+				"category_id":   "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+				"date_column":   "1",
+				"debit_column":  "2",
+				"credit_column": "3",
+				"dry_run":       "true",
+			},
+			file:           "Date,Debit,Credit\n2025-01-05,,12.50\n",
+			mockResponse:   &transaction.ImportTransactionsResponse{DryRun: true, Total: 1},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "rows with errors",
+			fields: map[string]string{
+				"budget_id":     "0f8fad5b-d9cb-469f-a165-70867728950e",
+				"category_id":   "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+				"date_column":   "Date",
+				"amount_column": "Amount",
+			},
+			file:           "Date,Amount\nyesterday,12.50\n",
+			mockResponse:   &transaction.ImportTransactionsResponse{Total: 1, Failed: 1},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "unsupported format",
+			fields: map[string]string{
+				"budget_id":   "0f8fad5b-d9cb-469f-a165-70867728950e",
+				"category_id": "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+				"format":      "xls",
+				"date_column": "Date",
+			},
+			file:           "Date,Amount\n2025-01-05,12.50\n",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "missing file",
+			fields: map[string]string{
+				"budget_id":     "0f8fad5b-d9cb-469f-a165-70867728950e",
+				"category_id":   "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+				"date_column":   "Date",
+				"amount_column": "Amount",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			for key, value := range tt.fields {
+				assert.NoError(t, writer.WriteField(key, value))
+			}
+			if tt.file != "" {
+				part, err := writer.CreateFormFile("file", "statement.csv")
+				assert.NoError(t, err)
+				_, err = part.Write([]byte(tt.file))
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, writer.Close())
+
+			req := httptest.NewRequest(http.MethodPost, "/transaction/import", &body)
+			req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.mockResponse != nil {
+				mockTransaction.EXPECT().
+					Import(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, req *transaction.ImportTransactionsRequest) (*transaction.ImportTransactionsResponse, error) {
+						assert.Equal(t, "user123", req.UserID)
+						content, err := io.ReadAll(req.File)
+						assert.NoError(t, err)
+						assert.Equal(t, tt.file, string(content))
+						return tt.mockResponse, nil
+					})
+			}
+
+			err := handler.Import(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var response transaction.ImportTransactionsResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.mockResponse.Total, response.Total)
+		})
+	}
+}
+
+func TestTransaction_Export(t *testing.T) {
+	e, mockTransaction, handler := setupTransactionTest(t)
+	defer gomock.NewController(t).Finish()
+
+	tests := []struct {
+		name                string
+		query               string
+		output              string
+		mockCalled          bool
+		mockError           error
+		expectedStatus      int
+		expectedContentType string
+		expectedFilename    string
+	}{
+		{
+			name:                "csv by default",
+			output:              "id,date\n",
+			mockCalled:          true,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedFilename:    `attachment; filename="transactions.csv"`,
+		},
+		{
+			name:                "empty json lines export",
+			query:               "format=jsonl&type=withdrawal",
+			mockCalled:          true,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedFilename:    `attachment; filename="transactions.jsonl"`,
+		},
+		{
+			name:           "unsupported format",
+			query:          "format=xls",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "service error before any output",
+			query:          "format=ofx",
+			mockCalled:     true,
+			mockError:      echo.NewHTTPError(http.StatusBadRequest, "OFX export requires a budget_id"),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/transaction/export?"+tt.query, nil)
+			req.Header.Set("User-Id", "user123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.mockCalled {
+				mockTransaction.EXPECT().
+					Export(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, req *transaction.ExportTransactionsRequest) (*transaction.ExportTransactionsResponse, error) {
+						if tt.mockError != nil {
+							return nil, tt.mockError
+						}
+						_, err := io.WriteString(req.Output, tt.output)
+						return &transaction.ExportTransactionsResponse{}, err
+					})
+			}
+
+			err := handler.Export(c)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				var httpErr *echo.HTTPError
+				assert.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+				assert.False(t, c.Response().Committed)
+				assert.Empty(t, rec.Header().Get(echo.HeaderContentDisposition))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedContentType, rec.Header().Get(echo.HeaderContentType))
+			assert.Equal(t, tt.expectedFilename, rec.Header().Get(echo.HeaderContentDisposition))
+			assert.Equal(t, tt.output, rec.Body.String())
+		})
+	}
+}
@@ -1,14 +1,70 @@
 package middleware
 
 import (
+	"context"
+	"finly-backend/internal/domain/enums/e_api_key_scope"
 	jwt "finly-backend/pkg/security"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/zap"
+	"net/http"
+	"slices"
+	"strings"
 )
 
-const headerUserId = "User-Id"
+const (
+	headerUserId    = "User-Id"
+	headerSessionId = "Session-Id"
+)
+
+// SessionValidator rejects the access tokens of revoked sessions.
+type SessionValidator func(ctx context.Context, sessionID, userID, ip string) error
+
+// EmailVerifiedChecker tells whether a user has verified their email address.
+type EmailVerifiedChecker func(ctx context.Context, userID string) (bool, error)
+
+// APIKeyAuthenticator resolves an API key to the user it belongs to and its scopes.
+type APIKeyAuthenticator func(ctx context.Context, key string) (userID string, scopes []string, err error)
+
+// Auth holds the authentication middlewares the router builds once and hands to every
+// handler to guard its routes with.
+type Auth struct {
+	JWT           echo.MiddlewareFunc
+	VerifiedEmail echo.MiddlewareFunc
+}
+
+// apiKeyWriteScopes maps the route groups an API key can reach to the scope that allows
+// changing them; reads need the read scope. Every other group, such as /auth, /session,
+// /api-key and /export, needs a signed-in session, so a leaked key can't take over the
+// account.
+var apiKeyWriteScopes = map[string]e_api_key_scope.Enum{
+	"/budget":      e_api_key_scope.BudgetsWrite,
+	"/category":    e_api_key_scope.CategoriesWrite,
+	"/transaction": e_api_key_scope.TransactionsWrite,
+	"/recurring":   e_api_key_scope.RecurringWrite,
+	"/limit":       e_api_key_scope.LimitsWrite,
+	"/rule":        e_api_key_scope.RulesWrite,
+	"/report":      "",
+}
+
+// apiKeySessionRoutes are routes within the groups above that still need a signed-in
+// session: sharing a budget and deleting it are left to the owner at the keyboard.
+var apiKeySessionRoutes = []struct {
+	method string
+	prefix string
+}{
+	{prefix: "/budget/invitations"},
+	{prefix: "/budget/:budget_id/members"},
+	{prefix: "/budget/:budget_id/invitations"},
+	{method: http.MethodDelete, prefix: "/budget/:budget_id"},
+}
+
+// apiKeyClaims is stored in place of the JWT claims for requests made with an API key.
+type apiKeyClaims struct {
+	UserID string
+	Scopes []string
+}
 
 func RecoverMiddleware() echo.MiddlewareFunc {
 	config := middleware.DefaultRecoverConfig
@@ -35,15 +91,98 @@ func CORSMiddleware() echo.MiddlewareFunc {
 	})
 }
 
-func JWT() func(next echo.HandlerFunc) echo.HandlerFunc {
-	return echojwt.WithConfig(echojwt.Config{
+// JWT authenticates the request with an access token or, when authenticator is set, an
+// API key, and sets the User-Id header. When validator is set, the session of every
+// access token is checked as well. Requests made with an API key have no Session-Id and
+// are limited to the routes their scopes allow.
+func JWT(validator SessionValidator, authenticator APIKeyAuthenticator) echo.MiddlewareFunc {
+	parse := echojwt.WithConfig(echojwt.Config{
 		ParseTokenFunc: func(c echo.Context, auth string) (interface{}, error) {
+			if authenticator != nil && jwt.IsAPIKey(auth) {
+				userID, scopes, err := authenticator(c.Request().Context(), auth)
+				if err != nil {
+					return nil, err
+				}
+				c.Request().Header.Set(headerUserId, userID)
+				c.Request().Header.Del(headerSessionId)
+				return &apiKeyClaims{UserID: userID, Scopes: scopes}, nil
+			}
+
 			claims, err := jwt.Verify(auth)
 			if err != nil {
 				return nil, err
 			}
+			if validator != nil {
+				if err = validator(c.Request().Context(), claims.SessionID, claims.UserID, c.RealIP()); err != nil {
+					return nil, err
+				}
+			}
 			c.Request().Header.Set(headerUserId, claims.UserID)
+			c.Request().Header.Set(headerSessionId, claims.SessionID)
 			return claims, nil
 		},
 	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return parse(func(c echo.Context) error {
+			if key, ok := c.Get("user").(*apiKeyClaims); ok && !key.allows(c.Request().Method, c.Path()) {
+				return echo.NewHTTPError(http.StatusForbidden, "API key is not allowed to access this endpoint")
+			}
+			return next(c)
+		})
+	}
+}
+
+// allows tells whether the scopes of the key cover a request to the route path.
+func (k *apiKeyClaims) allows(method, path string) bool {
+	group := path
+	if i := strings.IndexByte(path[min(1, len(path)):], '/'); i >= 0 {
+		group = path[:i+1]
+	}
+
+	writeScope, ok := apiKeyWriteScopes[group]
+	if !ok {
+		return false
+	}
+	for _, route := range apiKeySessionRoutes {
+		if (route.method == "" || route.method == method) && (path == route.prefix || strings.HasPrefix(path, route.prefix+"/")) {
+			return false
+		}
+	}
+
+	scope := writeScope
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		scope = e_api_key_scope.Read
+	}
+	return scope != "" && slices.Contains(k.Scopes, scope.String())
+}
+
+// VerifiedEmail rejects requests that change data while the email address of the user
+// is not verified. Reads stay allowed, so an unverified user can still look around. It
+// must run after JWT, which sets the User-Id header. Without a checker, as when
+// REQUIRE_VERIFIED_EMAIL is off, every request is let through.
+func VerifiedEmail(checker EmailVerifiedChecker) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if checker == nil {
+				return next(c)
+			}
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(c)
+			}
+
+			verified, err := checker(c.Request().Context(), c.Request().Header.Get(headerUserId))
+			if err != nil {
+				zap.L().Sugar().Errorf("Failed to check email verification, error: %v", err)
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check email verification")
+			}
+			if !verified {
+				return echo.NewHTTPError(http.StatusForbidden, "Email address is not verified")
+			}
+
+			return next(c)
+		}
+	}
 }
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"github.com/golang-jwt/jwt/v5"
 	"net/http"
 	"net/http/httptest"
@@ -8,6 +10,7 @@ import (
 	"time"
 
 	"finly-backend/pkg/security"
+	"finly-backend/pkg/testutil"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -50,10 +53,11 @@ func TestCORSMiddleware(t *testing.T) {
 }
 
 func TestJWTMiddleware_Success(t *testing.T) {
+	testutil.SetupSigningKeys(t)
 	e := echo.New()
-	e.Use(JWT())
+	e.Use(JWT(nil, nil))
 
-	token, err := security.GenerateJWT("user123", "user@example.com")
+	token, err := security.GenerateJWT("user123", "user@example.com", "session123")
 	assert.NoError(t, err)
 
 	e.GET("/protected", func(c echo.Context) error {
@@ -71,9 +75,45 @@ func TestJWTMiddleware_Success(t *testing.T) {
 	assert.Equal(t, "user123", rec.Body.String())
 }
 
+func TestJWTMiddleware_RevokedSession(t *testing.T) {
+	testutil.SetupSigningKeys(t)
+	validator := func(ctx context.Context, sessionID, userID, ip string) error {
+		if sessionID == "revoked" {
+			return errors.New("session has been revoked")
+		}
+		return nil
+	}
+
+	e := echo.New()
+	e.Use(JWT(validator, nil))
+
+	e.GET("/protected", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.Request().Header.Get("Session-Id"))
+	})
+
+	for sessionID, expectedStatus := range map[string]int{
+		"active":  http.StatusOK,
+		"revoked": http.StatusUnauthorized,
+	} {
+		token, err := security.GenerateJWT("user123", "user@example.com", sessionID)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, expectedStatus, rec.Code)
+		if expectedStatus == http.StatusOK {
+			assert.Equal(t, sessionID, rec.Body.String())
+		}
+	}
+}
+
 func TestJWTMiddleware_MissingToken(t *testing.T) {
 	e := echo.New()
-	e.Use(JWT())
+	e.Use(JWT(nil, nil))
 
 	e.GET("/protected", func(c echo.Context) error {
 		return c.String(http.StatusOK, "should not reach here")
@@ -88,8 +128,9 @@ func TestJWTMiddleware_MissingToken(t *testing.T) {
 }
 
 func TestJWTMiddleware_InvalidToken(t *testing.T) {
+	testutil.SetupSigningKeys(t)
 	e := echo.New()
-	e.Use(JWT())
+	e.Use(JWT(nil, nil))
 
 	e.GET("/protected", func(c echo.Context) error {
 		return c.String(http.StatusOK, "should not reach here")
@@ -105,8 +146,9 @@ func TestJWTMiddleware_InvalidToken(t *testing.T) {
 }
 
 func TestJWTMiddleware_ExpiredToken(t *testing.T) {
+	testutil.SetupSigningKeys(t)
 	e := echo.New()
-	e.Use(JWT())
+	e.Use(JWT(nil, nil))
 
 	// Manually create an expired token
 	expiredClaims := &security.Claims{
@@ -117,7 +159,8 @@ func TestJWTMiddleware_ExpiredToken(t *testing.T) {
 		},
 	}
 	tokenObj := jwt.NewWithClaims(jwt.SigningMethodHS256, expiredClaims)
-	expiredToken, err := tokenObj.SignedString([]byte("your_secret_key"))
+	tokenObj.Header["kid"] = testutil.SigningKeyID
+	expiredToken, err := tokenObj.SignedString([]byte(testutil.SigningKeySecret))
 	assert.NoError(t, err)
 
 	e.GET("/protected", func(c echo.Context) error {
@@ -132,3 +175,138 @@ func TestJWTMiddleware_ExpiredToken(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, rec.Code)
 }
+
+func TestVerifiedEmailMiddleware(t *testing.T) {
+	checker := func(ctx context.Context, userID string) (bool, error) {
+		return userID == "verified", nil
+	}
+
+	e := echo.New()
+	e.Use(VerifiedEmail(checker))
+
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	}
+	e.GET("/budget", handler)
+	e.POST("/budget", handler)
+
+	tests := []struct {
+		name           string
+		method         string
+		userID         string
+		expectedStatus int
+	}{
+		{name: "Verified user writes", method: http.MethodPost, userID: "verified", expectedStatus: http.StatusOK},
+		{name: "Unverified user writes", method: http.MethodPost, userID: "unverified", expectedStatus: http.StatusForbidden},
+		{name: "Unverified user reads", method: http.MethodGet, userID: "unverified", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/budget", nil)
+			req.Header.Set("User-Id", tt.userID)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestVerifiedEmailMiddleware_NotRequired(t *testing.T) {
+	e := echo.New()
+	e.Use(VerifiedEmail(nil))
+	e.POST("/budget", func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/budget", nil)
+	req.Header.Set("User-Id", "unverified")
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestJWTMiddleware_APIKey(t *testing.T) {
+	testutil.SetupSigningKeys(t)
+	authenticator := func(ctx context.Context, key string) (string, []string, error) {
+		switch key {
+		case "fk_reader":
+			return "user123", []string{"read"}, nil
+		case "fk_importer":
+			return "user123", []string{"read", "transactions:write"}, nil
+		case "fk_budgets":
+			return "user123", []string{"read", "budgets:write"}, nil
+		}
+		return "", nil, errors.New("invalid API key")
+	}
+
+	e := echo.New()
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, c.Request().Header.Get("User-Id")+"|"+c.Request().Header.Get("Session-Id"))
+	}
+	e.Group("/transaction", JWT(nil, authenticator)).POST("", handler)
+	e.Group("/transaction", JWT(nil, authenticator)).GET("/:id", handler)
+	e.Group("/budget", JWT(nil, authenticator)).POST("", handler)
+	e.Group("/budget", JWT(nil, authenticator)).PATCH("/:budget_id", handler)
+	e.Group("/budget", JWT(nil, authenticator)).DELETE("/:budget_id", handler)
+	e.Group("/budget", JWT(nil, authenticator)).GET("/:budget_id/members", handler)
+	e.Group("/budget", JWT(nil, authenticator)).POST("/:budget_id/invitations", handler)
+	e.Group("/budget", JWT(nil, authenticator)).POST("/invitations/:id/accept", handler)
+	e.Group("/report", JWT(nil, authenticator)).GET("/summary", handler)
+	e.Group("/session", JWT(nil, authenticator)).GET("", handler)
+
+	tests := []struct {
+		name           string
+		key            string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{name: "read scope reads", key: "fk_reader", method: http.MethodGet, path: "/transaction/tx1", expectedStatus: http.StatusOK},
+		{name: "read scope reads reports", key: "fk_reader", method: http.MethodGet, path: "/report/summary", expectedStatus: http.StatusOK},
+		{name: "read scope can't write", key: "fk_reader", method: http.MethodPost, path: "/transaction", expectedStatus: http.StatusForbidden},
+		{name: "write scope writes", key: "fk_importer", method: http.MethodPost, path: "/transaction", expectedStatus: http.StatusOK},
+		{name: "write scope is limited to its group", key: "fk_importer", method: http.MethodPost, path: "/budget", expectedStatus: http.StatusForbidden},
+		{name: "budget scope updates budgets", key: "fk_budgets", method: http.MethodPatch, path: "/budget/b1", expectedStatus: http.StatusOK},
+		{name: "budget deletion needs a session", key: "fk_budgets", method: http.MethodDelete, path: "/budget/b1", expectedStatus: http.StatusForbidden},
+		{name: "members need a session", key: "fk_budgets", method: http.MethodGet, path: "/budget/b1/members", expectedStatus: http.StatusForbidden},
+		{name: "invitations need a session", key: "fk_budgets", method: http.MethodPost, path: "/budget/b1/invitations", expectedStatus: http.StatusForbidden},
+		{name: "accepting invitations needs a session", key: "fk_budgets", method: http.MethodPost, path: "/budget/invitations/i1/accept", expectedStatus: http.StatusForbidden},
+		{name: "account routes need a session", key: "fk_importer", method: http.MethodGet, path: "/session", expectedStatus: http.StatusForbidden},
+		{name: "unknown key", key: "fk_unknown", method: http.MethodGet, path: "/transaction/tx1", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+tt.key)
+			req.Header.Set("Session-Id", "forged")
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "user123|", rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("access tokens keep working", func(t *testing.T) {
+		token, err := security.GenerateJWT("user123", "user@example.com", "session123")
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/session", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "user123|session123", rec.Body.String())
+	})
+}
@@ -4,18 +4,38 @@ import (
 	_ "finly-backend/docs"
 	"finly-backend/internal/service"
 	"finly-backend/internal/transport/http/handler"
+	"finly-backend/internal/transport/http/middleware"
 	"finly-backend/pkg/server"
 	"github.com/labstack/echo/v4"
 	echoSwagger "github.com/swaggo/echo-swagger"
 	"net/http"
 )
 
-func RegisterRoutes(server *server.Server, services *service.Service) {
+// RegisterRoutes registers every handler. Writes of users who haven't verified their
+// email are only blocked when requireVerifiedEmail is set.
+func RegisterRoutes(server *server.Server, services *service.Service, requireVerifiedEmail bool) {
+	var emailVerified middleware.EmailVerifiedChecker
+	if requireVerifiedEmail {
+		emailVerified = services.Auth.IsEmailVerified
+	}
+	mw := middleware.Auth{
+		JWT:           middleware.JWT(services.Session.Validate, services.APIKey.Authenticate),
+		VerifiedEmail: middleware.VerifiedEmail(emailVerified),
+	}
+
 	// Register handlers
-	handler.NewAuth(services).Register(server)
-	handler.NewCategory(services).Register(server)
-	handler.NewBudget(services).Register(server)
-	handler.NewTransaction(services).Register(server)
+	handler.NewAuth(services).Register(server, mw)
+	handler.NewCategory(services).Register(server, mw)
+	handler.NewBudget(services).Register(server, mw)
+	handler.NewBudgetMember(services).Register(server, mw)
+	handler.NewTransaction(services).Register(server, mw)
+	handler.NewRecurring(services).Register(server, mw)
+	handler.NewTransactionRule(services).Register(server, mw)
+	handler.NewCategoryLimit(services).Register(server, mw)
+	handler.NewReport(services).Register(server, mw)
+	handler.NewSession(services).Register(server, mw)
+	handler.NewDataExport(services).Register(server, mw)
+	handler.NewAPIKey(services).Register(server, mw)
 
 	server.GET("/swagger/*", echoSwagger.WrapHandler)
 	server.GET("/health", func(c echo.Context) error {
@@ -0,0 +1,28 @@
+package validation
+
+import (
+	"finly-backend/internal/domain"
+	"finly-backend/pkg/validator"
+	"reflect"
+)
+
+// New returns the request validator with the rules for the domain types registered.
+func New() (*validator.Validator, error) {
+	v, err := validator.CustomValidator()
+	if err != nil {
+		return nil, err
+	}
+
+	v.RegisterCustomType(MoneyValue, domain.Money{})
+
+	return v, nil
+}
+
+// MoneyValue exposes domain.Money to validation rules as its amount in cents,
+// so tags like required and gt=0 work on money fields.
+func MoneyValue(field reflect.Value) any {
+	if m, ok := field.Interface().(domain.Money); ok {
+		return m.Cents()
+	}
+	return nil
+}
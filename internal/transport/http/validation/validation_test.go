@@ -0,0 +1,48 @@
+package validation
+
+import (
+	"finly-backend/internal/domain"
+	"testing"
+)
+
+func TestMoneyValue(t *testing.T) {
+	type request struct {
+		Amount domain.Money `validate:"required,gt=0"`
+	}
+
+	tests := []struct {
+		name     string
+		input    domain.Money
+		expected bool
+	}{
+		{
+			name:     "Positive amount",
+			input:    domain.MustParseMoney("10.50"),
+			expected: true,
+		},
+		{
+			name:     "Zero amount",
+			input:    domain.Money{},
+			expected: false,
+		},
+		{
+			name:     "Negative amount",
+			input:    domain.MustParseMoney("-1.00"),
+			expected: false,
+		},
+	}
+
+	v, err := New()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(&request{Amount: tt.input})
+			if (err == nil) != tt.expected {
+				t.Errorf("MoneyValue() = %v, want %v", err == nil, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE budgets
+    ADD COLUMN name        VARCHAR(100) NOT NULL DEFAULT 'Main',
+    ADD COLUMN archived_at TIMESTAMP;
+
+CREATE INDEX idx_budgets_user_id ON budgets (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_budgets_user_id;
+
+ALTER TABLE budgets
+    DROP COLUMN archived_at,
+    DROP COLUMN name;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_transactions_user_created_at ON transactions (user_id, created_at, id);
+CREATE INDEX idx_transactions_user_amount ON transactions (user_id, amount, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_user_amount;
+DROP INDEX IF EXISTS idx_transactions_user_created_at;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS transactions_transaction_type_check;
+
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_transaction_type_check
+        CHECK (transaction_type IN ('deposit', 'withdrawal', 'transfer_in', 'transfer_out')),
+    ADD COLUMN transfer_id UUID;
+
+CREATE INDEX idx_transactions_transfer_id ON transactions (transfer_id) WHERE transfer_id IS NOT NULL;
+
+INSERT INTO categories (id, name, created_at, updated_at)
+VALUES ('0b7c6a1e-4f3d-4e2a-9c8b-5d6e7f8a9b0c', 'Transfer', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM transactions WHERE transfer_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_transactions_transfer_id;
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS transactions_transaction_type_check;
+
+ALTER TABLE transactions
+    DROP COLUMN transfer_id,
+    ADD CONSTRAINT transactions_transaction_type_check
+        CHECK (transaction_type IN ('deposit', 'withdrawal'));
+
+DELETE FROM categories WHERE id = '0b7c6a1e-4f3d-4e2a-9c8b-5d6e7f8a9b0c';
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE recurring_transactions
+(
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id          UUID           NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    budget_id        UUID           NOT NULL REFERENCES budgets (id) ON DELETE CASCADE,
+    category_id      UUID           NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    amount           DECIMAL(15, 2) NOT NULL,
+    transaction_type VARCHAR(20)    NOT NULL CHECK (transaction_type IN ('deposit', 'withdrawal')),
+    note             VARCHAR(255)   NOT NULL DEFAULT '',
+    frequency        VARCHAR(10)    NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly', 'cron')),
+    interval_count   INT            NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    cron_expr        VARCHAR(100),
+    start_at         TIMESTAMP      NOT NULL,
+    end_at           TIMESTAMP,
+    next_run_at      TIMESTAMP,
+    last_run_at      TIMESTAMP,
+    is_active        BOOLEAN        NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_recurring_transactions_user_id ON recurring_transactions (user_id);
+CREATE INDEX idx_recurring_transactions_due ON recurring_transactions (next_run_at) WHERE is_active;
+
+CREATE TABLE recurring_transaction_runs
+(
+    id                       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    recurring_transaction_id UUID        NOT NULL REFERENCES recurring_transactions (id) ON DELETE CASCADE,
+    occurrence_at            TIMESTAMP   NOT NULL,
+    transaction_id           UUID REFERENCES transactions (id) ON DELETE SET NULL,
+    status                   VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'completed', 'failed')),
+    error                    TEXT,
+    created_at               TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (recurring_transaction_id, occurrence_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS recurring_transaction_runs;
+DROP TABLE IF EXISTS recurring_transactions;
+-- +goose StatementEnd
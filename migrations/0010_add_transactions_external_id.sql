@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transactions
+    ADD COLUMN external_id VARCHAR(255);
+
+CREATE UNIQUE INDEX idx_transactions_budget_external_id ON transactions (budget_id, external_id) WHERE external_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_budget_external_id;
+
+ALTER TABLE transactions
+    DROP COLUMN external_id;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE category_limits
+(
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID           NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    budget_id   UUID           NOT NULL REFERENCES budgets (id) ON DELETE CASCADE,
+    category_id UUID           NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    amount      DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    period      VARCHAR(10)    NOT NULL CHECK (period IN ('month', 'week', 'custom')),
+    starts_at   TIMESTAMP,
+    ends_at     TIMESTAMP,
+    enforce     BOOLEAN        NOT NULL DEFAULT FALSE,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (period <> 'custom' OR (starts_at IS NOT NULL AND ends_at IS NOT NULL AND starts_at < ends_at))
+);
+
+CREATE INDEX idx_category_limits_budget_category ON category_limits (budget_id, category_id);
+CREATE UNIQUE INDEX idx_category_limits_repeating ON category_limits (budget_id, category_id, period) WHERE period <> 'custom';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS category_limits;
+-- +goose StatementEnd
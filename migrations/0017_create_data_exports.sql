@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE data_exports
+(
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status       VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    attempts     INT         NOT NULL DEFAULT 0,
+    archive      BYTEA,
+    size         BIGINT,
+    error        TEXT,
+    started_at   TIMESTAMP,
+    completed_at TIMESTAMP,
+    expires_at   TIMESTAMP,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_data_exports_user ON data_exports (user_id, created_at);
+CREATE INDEX idx_data_exports_queue ON data_exports (created_at) WHERE status IN ('pending', 'running');
+CREATE UNIQUE INDEX idx_data_exports_active ON data_exports (user_id) WHERE status IN ('pending', 'running');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS data_exports;
+-- +goose StatementEnd
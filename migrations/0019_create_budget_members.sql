@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE budget_members
+(
+    budget_id  UUID        NOT NULL REFERENCES budgets (id) ON DELETE CASCADE,
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role       VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (budget_id, user_id)
+);
+
+CREATE INDEX idx_budget_members_user ON budget_members (user_id);
+
+INSERT INTO budget_members (budget_id, user_id, role, created_at)
+SELECT id, user_id, 'owner', created_at
+FROM budgets;
+
+CREATE TABLE budget_invitations
+(
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    budget_id    UUID         NOT NULL REFERENCES budgets (id) ON DELETE CASCADE,
+    invited_by   UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email        VARCHAR(255) NOT NULL,
+    role         VARCHAR(16)  NOT NULL CHECK (role IN ('editor', 'viewer')),
+    status       VARCHAR(16)  NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined')),
+    expires_at   TIMESTAMP    NOT NULL,
+    responded_at TIMESTAMP,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_budget_invitations_pending ON budget_invitations (budget_id, lower(email)) WHERE status = 'pending';
+CREATE INDEX idx_budget_invitations_email ON budget_invitations (lower(email), status);
+
+ALTER TABLE transactions
+    ADD COLUMN created_by UUID REFERENCES users (id) ON DELETE SET NULL;
+
+UPDATE transactions
+SET created_by = user_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transactions
+    DROP COLUMN created_by;
+
+DROP TABLE IF EXISTS budget_invitations;
+DROP TABLE IF EXISTS budget_members;
+-- +goose StatementEnd
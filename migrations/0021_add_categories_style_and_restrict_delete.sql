@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE categories
+    ADD COLUMN icon  VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN color VARCHAR(7)  NOT NULL DEFAULT '';
+
+-- Deleting a category used by transactions or recurring transactions is refused instead of
+-- deleting them; NO ACTION still lets a user deletion cascade through both tables.
+ALTER TABLE transactions
+    DROP CONSTRAINT transactions_category_id_fkey,
+    ADD CONSTRAINT transactions_category_id_fkey FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE NO ACTION;
+
+ALTER TABLE recurring_transactions
+    DROP CONSTRAINT recurring_transactions_category_id_fkey,
+    ADD CONSTRAINT recurring_transactions_category_id_fkey FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE NO ACTION;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE recurring_transactions
+    DROP CONSTRAINT recurring_transactions_category_id_fkey,
+    ADD CONSTRAINT recurring_transactions_category_id_fkey FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE CASCADE;
+
+ALTER TABLE transactions
+    DROP CONSTRAINT transactions_category_id_fkey,
+    ADD CONSTRAINT transactions_category_id_fkey FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE CASCADE;
+
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS color,
+    DROP COLUMN IF EXISTS icon;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE category_overrides
+(
+    user_id     UUID      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    category_id UUID      NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    name        VARCHAR(255),
+    icon        VARCHAR(64),
+    color       VARCHAR(7),
+    hidden      BOOLEAN   NOT NULL DEFAULT FALSE,
+    position    INTEGER,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, category_id)
+);
+
+CREATE INDEX idx_category_overrides_category ON category_overrides (category_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS category_overrides;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transactions
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE transaction_rules
+(
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id          UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name             VARCHAR(100) NOT NULL,
+    priority         INT          NOT NULL DEFAULT 0 CHECK (priority >= 0),
+    note_contains    VARCHAR(255),
+    note_pattern     VARCHAR(255),
+    min_amount       DECIMAL(15, 2),
+    max_amount       DECIMAL(15, 2),
+    transaction_type VARCHAR(20) CHECK (transaction_type IN ('deposit', 'withdrawal')),
+    budget_id        UUID REFERENCES budgets (id) ON DELETE CASCADE,
+    category_id      UUID REFERENCES categories (id) ON DELETE SET NULL,
+    tag              VARCHAR(50),
+    created_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_transaction_rules_user ON transaction_rules (user_id, priority);
+CREATE INDEX idx_transaction_rules_category ON transaction_rules (category_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transaction_rules;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS tags;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Budgets may only use default categories and custom categories of their owner. Custom
+-- categories of another user that a shared budget already uses are copied to the budget
+-- owner, and the budget's transactions, recurring transactions and limits move to the copy,
+-- so the original can still be deleted by its user.
+CREATE TEMPORARY TABLE category_handovers AS
+SELECT category_id, owner_id, gen_random_uuid() AS copy_id
+FROM (SELECT DISTINCT c.id AS category_id, b.user_id AS owner_id
+      FROM categories c
+               JOIN (SELECT budget_id, category_id FROM transactions
+                     UNION
+                     SELECT budget_id, category_id FROM recurring_transactions
+                     UNION
+                     SELECT budget_id, category_id FROM category_limits) used ON used.category_id = c.id
+               JOIN budgets b ON b.id = used.budget_id
+      WHERE c.user_id IS NOT NULL
+        AND c.user_id <> b.user_id) foreign_uses;
+
+INSERT INTO categories (id, user_id, name, icon, color)
+SELECT h.copy_id, h.owner_id, c.name, c.icon, c.color
+FROM category_handovers h
+         JOIN categories c ON c.id = h.category_id;
+
+UPDATE transactions t
+SET category_id = h.copy_id
+FROM budgets b,
+     category_handovers h
+WHERE b.id = t.budget_id
+  AND h.category_id = t.category_id
+  AND h.owner_id = b.user_id;
+
+UPDATE recurring_transactions r
+SET category_id = h.copy_id
+FROM budgets b,
+     category_handovers h
+WHERE b.id = r.budget_id
+  AND h.category_id = r.category_id
+  AND h.owner_id = b.user_id;
+
+UPDATE category_limits l
+SET category_id = h.copy_id
+FROM budgets b,
+     category_handovers h
+WHERE b.id = l.budget_id
+  AND h.category_id = l.category_id
+  AND h.owner_id = b.user_id;
+
+DROP TABLE category_handovers;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The copies are ordinary custom categories of the budget owners and are kept.
+SELECT 1;
+-- +goose StatementEnd
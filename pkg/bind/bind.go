@@ -9,11 +9,22 @@ import (
 
 type Option func(c echo.Context, obj any) error
 
+// invalidValue is implemented by errors a field's unmarshaler rejects a well-formed
+// but unacceptable value with, e.g. an amount out of range.
+type invalidValue interface {
+	error
+	InvalidValue() bool
+}
+
 func Validate(c echo.Context, obj any, opts ...Option) error {
 	var err error
 
 	err = c.Bind(obj)
 	if err != nil {
+		var invalid invalidValue
+		if errors.As(err, &invalid) && invalid.InvalidValue() {
+			return echo.NewHTTPError(http.StatusBadRequest, invalid.Error()).SetInternal(err)
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, "Internal server error").SetInternal(err)
 	}
 
@@ -45,6 +56,6 @@ func FromHeaders() func(c echo.Context, obj any) error {
 
 func FromQuery() func(c echo.Context, obj any) error {
 	return func(c echo.Context, obj any) error {
-		return (&echo.DefaultBinder{}).BindQueryParams(c, &obj)
+		return (&echo.DefaultBinder{}).BindQueryParams(c, obj)
 	}
 }
@@ -63,6 +63,33 @@ func TestValidate_BindError(t *testing.T) {
 	assert.Equal(t, "Internal server error", httpErr.Message)
 }
 
+type rejectedValue string
+
+func (e rejectedValue) Error() string { return string(e) }
+
+func (e rejectedValue) InvalidValue() bool { return true }
+
+type rejectingStruct struct {
+	Amount rejectingField `json:"amount"`
+}
+
+type rejectingField struct{}
+
+func (f *rejectingField) UnmarshalJSON([]byte) error {
+	return rejectedValue("amount is out of range")
+}
+
+func TestValidate_InvalidValue(t *testing.T) {
+	_, c, _ := setupEchoContext(http.MethodPost, "/test", `{"amount":"100000000000000"}`, nil)
+	obj := &rejectingStruct{}
+	err := Validate(c, obj)
+
+	var httpErr *echo.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	assert.Equal(t, "amount is out of range", httpErr.Message)
+}
+
 func TestValidate_ValidationError(t *testing.T) {
 	_, c, _ := setupEchoContext(http.MethodPost, "/test", `{"name":"","email":"invalid"}`, nil)
 	obj := &testStruct{}
@@ -96,3 +123,12 @@ func TestFromHeaders_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 25, obj.Age)
 }
+
+func TestFromQuery_Success(t *testing.T) {
+	_, c, _ := setupEchoContext(http.MethodPost, "/test?age=41", `{"name":"John","email":"john@example.com"}`, nil)
+	obj := &testStruct{}
+	err := FromQuery()(c, obj)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 41, obj.Age)
+}
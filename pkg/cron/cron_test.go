@@ -0,0 +1,44 @@
+package cron
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := Parse(expr)
+		assert.ErrorIs(t, err, ErrInvalidExpression, expr)
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	base := time.Date(2025, 1, 31, 10, 17, 42, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{name: "Every minute", expr: "* * * * *", from: base, expected: time.Date(2025, 1, 31, 10, 18, 0, 0, time.UTC)},
+		{name: "Step minutes", expr: "*/15 * * * *", from: base, expected: time.Date(2025, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{name: "Daily macro", expr: "@daily", from: base, expected: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "First of month", expr: "0 9 1 * *", from: base, expected: time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{name: "Skips short months", expr: "0 0 31 * *", from: base, expected: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "Weekdays only", expr: "0 8 * * 1-5", from: time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC), expected: time.Date(2025, 2, 3, 8, 0, 0, 0, time.UTC)},
+		{name: "Sunday as seven", expr: "0 0 * * 7", from: base, expected: time.Date(2025, 2, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "Day of month or weekday", expr: "0 0 15 * 1", from: base, expected: time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)},
+		{name: "List", expr: "0 6,18 * * *", from: base, expected: time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC)},
+		{name: "Never fires", expr: "0 0 30 2 *", from: base, expected: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, s.Next(tt.from))
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+)
+
+// SecurityEvent logs an event that matters for the security of accounts, such as a
+// lockout. Every such entry has the "security" field, so they can be filtered and alerted on.
+func SecurityEvent(event string, fields ...zap.Field) {
+	zap.L().Warn("security event", append([]zap.Field{zap.Bool("security", true), zap.String("event", event)}, fields...)...)
+}
@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"finly-backend/internal/config"
+	"fmt"
+)
+
+const (
+	DriverLog  = "log"
+	DriverFile = "file"
+
+	defaultFrom = "Finly <no-reply@finly.click>"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email. The log and file mailers are meant for local runs and tests; a
+// provider backed mailer only has to implement Send.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewMailer picks the mailer named by MAILER, the log mailer by default.
+func NewMailer(cfg *config.Config) (Mailer, error) {
+	from := cfg.MailFrom
+	if from == "" {
+		from = defaultFrom
+	}
+
+	switch cfg.Mailer {
+	case "", DriverLog:
+		return NewLogMailer(from), nil
+	case DriverFile:
+		return NewFileMailer(from, cfg.MailDir)
+	}
+
+	return nil, fmt.Errorf("unknown mailer %q", cfg.Mailer)
+}
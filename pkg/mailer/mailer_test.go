@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"context"
+	"finly-backend/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewMailer(t *testing.T) {
+	m, err := NewMailer(&config.Config{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok := m.(*LogMailer); !ok {
+		t.Errorf("expected the log mailer by default, got %T", m)
+	}
+
+	if _, err = NewMailer(&config.Config{Mailer: DriverFile}); err == nil {
+		t.Errorf("expected error for a file mailer without directory, got nil")
+	}
+	if _, err = NewMailer(&config.Config{Mailer: "smtp"}); err == nil {
+		t.Errorf("expected error for an unknown mailer, got nil")
+	}
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+
+	m, err := NewMailer(&config.Config{Mailer: DriverFile, MailDir: dir})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = m.Send(context.Background(), Message{To: "user@example.com", Subject: "Hello", Body: "line 1\nline 2"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("expected one mail file, got %d", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	for _, want := range []string{"From: " + defaultFrom, "To: user@example.com", "Subject: Hello", "line 1\r\nline 2"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected mail to contain %q, got %q", want, data)
+		}
+	}
+
+	if err = m.Send(context.Background(), Message{To: "not an address"}); err == nil {
+		t.Errorf("expected error for an invalid recipient, got nil")
+	}
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// Job is a unit of periodic work. now is the tick time in UTC.
+type Job func(ctx context.Context, now time.Time) error
+
+// Scheduler runs a job in-process at a fixed interval. The first run happens
+// immediately on Start, so work missed while the process was down is picked up right away.
+type Scheduler struct {
+	name     string
+	interval time.Duration
+	job      Job
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(name string, interval time.Duration, job Job) *Scheduler {
+	return &Scheduler{
+		name:     name,
+		interval: interval,
+		job:      job,
+	}
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		zap.L().Sugar().Infof("Scheduler %s started with interval %s", s.name, s.interval)
+		s.run(ctx)
+
+		for {
+			select {
+			case <-ctx.Done():
+				zap.L().Sugar().Infof("Scheduler %s stopped", s.name)
+				return
+			case <-ticker.C:
+				s.run(ctx)
+			}
+		}
+	}()
+}
+
+// Stop cancels the scheduler and waits for the running job to return.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			zap.L().Sugar().Errorf("Scheduler %s job panicked: %v", s.name, r)
+		}
+	}()
+
+	if err := s.job(ctx, time.Now().UTC()); err != nil {
+		zap.L().Sugar().Errorf("Scheduler %s job failed: %v", s.name, err)
+	}
+}
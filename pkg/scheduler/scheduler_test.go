@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_RunsImmediatelyAndOnTick(t *testing.T) {
+	var runs atomic.Int32
+	s := New("test", 10*time.Millisecond, func(ctx context.Context, now time.Time) error {
+		assert.Equal(t, time.UTC, now.Location())
+		if runs.Add(1) == 2 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+
+	s.Start(context.Background())
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+	s.Stop()
+
+	stopped := runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load())
+}
+
+func TestScheduler_RecoversFromPanic(t *testing.T) {
+	var runs atomic.Int32
+	s := New("panicky", 5*time.Millisecond, func(ctx context.Context, now time.Time) error {
+		runs.Add(1)
+		panic("boom")
+	})
+
+	s.Start(context.Background())
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond)
+	s.Stop()
+}
@@ -7,42 +7,48 @@ import (
 	"time"
 )
 
-const TokenTTL = 72 * time.Hour
-
-// TODO: move to secret
-var jwtSecret = []byte("your_secret_key")
+const (
+	// AccessTokenTTL is kept short, as an access token stays valid until it expires unless
+	// its session is revoked. Clients renew it with their refresh token.
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is how long a refresh token can be used. Every refresh issues a new
+	// refresh token, so a session stays alive as long as it is used within this time.
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
 
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWT(userID string, email string) (string, error) {
-	expirationTime := time.Now().Add(TokenTTL)
+// GenerateJWT issues an access token of a session. The session ID lets a revoked
+// session be rejected before its access tokens expire.
+func GenerateJWT(userID, email, sessionID string) (string, error) {
+	now := time.Now()
 
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	if keys == nil {
+		return "", ErrKeysNotConfigured
+	}
+	return keys.sign(claims)
 }
 
 func GetUserFromToken(tokenStr string) (*Claims, error) {
 	parsedToken := strings.TrimPrefix(tokenStr, "Bearer ")
 
 	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(parsedToken, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return jwtSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(parsedToken, claims, keyFunc)
 
 	if err != nil {
 		return nil, err
@@ -59,12 +65,7 @@ func Verify(tokenStr string) (*Claims, error) {
 	parsedToken := strings.TrimPrefix(tokenStr, "Bearer ")
 
 	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(parsedToken, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return jwtSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(parsedToken, claims, keyFunc)
 
 	if err != nil {
 		return nil, errors.New("failed to parse token: " + err.Error())
@@ -80,3 +81,10 @@ func Verify(tokenStr string) (*Claims, error) {
 
 	return claims, nil
 }
+
+func keyFunc(token *jwt.Token) (interface{}, error) {
+	if keys == nil {
+		return nil, ErrKeysNotConfigured
+	}
+	return keys.keyFunc(token)
+}
@@ -6,11 +6,31 @@ import (
 	"time"
 )
 
+const testSecret = "test-signing-key-0123456789abcdef"
+
+func useTestKeys(t *testing.T) {
+	t.Helper()
+
+	key, err := ParseKey("test", testSecret)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	ks, err := NewKeySet(key)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	UseKeys(ks)
+	t.Cleanup(func() { UseKeys(nil) })
+}
+
 func TestGenerateJWT(t *testing.T) {
+	useTestKeys(t)
 	userID := "12345"
 	email := "user@example.com"
+	sessionID := "session123"
 
-	token, err := GenerateJWT(userID, email)
+	token, err := GenerateJWT(userID, email, sessionID)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -30,13 +50,18 @@ func TestGenerateJWT(t *testing.T) {
 	if claims.Email != email {
 		t.Errorf("expected email %v, got %v", email, claims.Email)
 	}
+	if claims.SessionID != sessionID {
+		t.Errorf("expected sessionID %v, got %v", sessionID, claims.SessionID)
+	}
 }
 
 func TestGetUserFromToken(t *testing.T) {
+	useTestKeys(t)
 	userID := "12345"
 	email := "user@example.com"
+	sessionID := "session123"
 
-	token, err := GenerateJWT(userID, email)
+	token, err := GenerateJWT(userID, email, sessionID)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -61,10 +86,12 @@ func TestGetUserFromToken(t *testing.T) {
 }
 
 func TestVerify(t *testing.T) {
+	useTestKeys(t)
 	userID := "12345"
 	email := "user@example.com"
+	sessionID := "session123"
 
-	token, err := GenerateJWT(userID, email)
+	token, err := GenerateJWT(userID, email, sessionID)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -81,12 +108,6 @@ func TestVerify(t *testing.T) {
 		t.Errorf("expected email %v, got %v", email, claims.Email)
 	}
 
-	oldJWTSecret := jwtSecret
-	jwtSecret = []byte("temporary_secret_key")
-	defer func() {
-		jwtSecret = oldJWTSecret
-	}()
-
 	// Create a token that has expired
 	expiredToken := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
 		UserID: userID,
@@ -95,7 +116,8 @@ func TestVerify(t *testing.T) {
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)), // expired
 		},
 	})
-	signedString, err := expiredToken.SignedString(jwtSecret)
+	expiredToken.Header["kid"] = "test"
+	signedString, err := expiredToken.SignedString([]byte(testSecret))
 
 	_, err = Verify("Bearer " + signedString)
 	if err == nil {
@@ -0,0 +1,226 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"strings"
+)
+
+const (
+	minHMACSecretLength = 32
+	minRSAKeyBits       = 2048
+)
+
+var (
+	ErrKeysNotConfigured = errors.New("signing keys are not configured")
+
+	errUnknownKey       = errors.New("unknown signing key")
+	errUnexpectedMethod = errors.New("unexpected signing method")
+)
+
+// Key is a key tokens are signed or verified with. Its algorithm follows from the key
+// material: an RSA key signs with RS256, an Ed25519 key with EdDSA and a plain secret
+// with HS256. Public keys can only verify.
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+
+	sign   any
+	verify any
+}
+
+// ParseKey reads an HMAC secret or a PEM encoded RSA or Ed25519 key. Literal "\n"
+// sequences are expanded, so a PEM key fits on a single line of an env file.
+func ParseKey(id, material string) (*Key, error) {
+	if id == "" {
+		return nil, errors.New("key ID cannot be empty")
+	}
+
+	material = strings.TrimSpace(strings.ReplaceAll(material, `\n`, "\n"))
+	if !strings.HasPrefix(material, "-----BEGIN") {
+		if len(material) < minHMACSecretLength {
+			return nil, fmt.Errorf("key %s: HMAC secret must be at least %d bytes", id, minHMACSecretLength)
+		}
+		secret := []byte(material)
+		return &Key{ID: id, Method: jwt.SigningMethodHS256, sign: secret, verify: secret}, nil
+	}
+
+	block, _ := pem.Decode([]byte(material))
+	if block == nil {
+		return nil, fmt.Errorf("key %s: invalid PEM block", id)
+	}
+
+	var (
+		parsed any
+		err    error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		err = fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", id, err)
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("key %s: RSA key must be at least %d bits", id, minRSAKeyBits)
+		}
+		return &Key{ID: id, Method: jwt.SigningMethodRS256, sign: k, verify: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("key %s: RSA key must be at least %d bits", id, minRSAKeyBits)
+		}
+		return &Key{ID: id, Method: jwt.SigningMethodRS256, verify: k}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: id, Method: jwt.SigningMethodEdDSA, sign: k, verify: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: id, Method: jwt.SigningMethodEdDSA, verify: k}, nil
+	}
+
+	return nil, fmt.Errorf("key %s: unsupported key type %T", id, parsed)
+}
+
+// KeySet signs new tokens with one key and verifies tokens of any of its keys, which
+// lets a key be rotated without signing everybody out: the previous key stays in the
+// set as a retired key until the tokens it signed have expired.
+type KeySet struct {
+	signing *Key
+	keys    []*Key
+	byID    map[string]*Key
+}
+
+func NewKeySet(signing *Key, retired ...*Key) (*KeySet, error) {
+	if signing.sign == nil {
+		return nil, fmt.Errorf("key %s: a public key cannot sign tokens", signing.ID)
+	}
+
+	ks := &KeySet{signing: signing, byID: make(map[string]*Key, len(retired)+1)}
+	for _, key := range append([]*Key{signing}, retired...) {
+		if _, ok := ks.byID[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key ID %s", key.ID)
+		}
+		ks.keys = append(ks.keys, key)
+		ks.byID[key.ID] = key
+	}
+
+	return ks, nil
+}
+
+// LoadKeySet builds the key set from the signing key id and material. retiredKeys lists
+// the keys that still verify as comma separated kid=key pairs.
+func LoadKeySet(keyID, signingKey, retiredKeys string) (*KeySet, error) {
+	signing, err := ParseKey(keyID, signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var retired []*Key
+	for _, entry := range strings.Split(retiredKeys, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		id, material, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, errors.New("JWT_RETIRED_KEYS entries must have the form kid=key")
+		}
+		key, err := ParseKey(strings.TrimSpace(id), material)
+		if err != nil {
+			return nil, err
+		}
+		retired = append(retired, key)
+	}
+
+	return NewKeySet(signing, retired...)
+}
+
+func (ks *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.sign)
+}
+
+// keyFunc picks the verification key by the kid header of a token. The token must use
+// the algorithm of that key, so a public key can never be used as an HMAC secret.
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	id, _ := token.Header["kid"].(string)
+	key, ok := ks.byID[id]
+	if !ok {
+		return nil, errUnknownKey
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errUnexpectedMethod
+	}
+	return key.verify, nil
+}
+
+// JWK is the public part of a key as described in RFC 7517.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the set, signing key first. HMAC secrets are never
+// published, so a set of HMAC keys has an empty JWKS.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range ks.keys {
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Method.Alg()}
+		switch k := key.verify.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(k)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+var keys *KeySet
+
+// UseKeys sets the keys tokens are signed and verified with. It is called once at
+// start-up, before any token is issued.
+func UseKeys(ks *KeySet) {
+	keys = ks
+}
+
+// PublicJWKS returns the public keys other services verify Finly tokens with.
+func PublicJWKS() JWKS {
+	if keys == nil {
+		return JWKS{Keys: []JWK{}}
+	}
+	return keys.JWKS()
+}
@@ -0,0 +1,50 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const opaqueTokenBytes = 32
+
+// GenerateOpaqueToken returns a random URL-safe token. Unlike a JWT it carries no data;
+// the server looks it up by its hash.
+func GenerateOpaqueToken() (string, error) {
+	b := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex SHA-256 of an opaque token. Only the hash is stored, so a
+// leaked table cannot be used to sign in. A fast hash is enough, as the tokens are random.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyPrefix starts every API key, so the middleware can tell keys from JWTs and
+// secret scanners can spot leaked keys.
+const APIKeyPrefix = "fk_"
+
+// apiKeyDisplayLength is how much of a key is kept in clear to tell keys apart.
+const apiKeyDisplayLength = len(APIKeyPrefix) + 6
+
+// GenerateAPIKey returns a new API key and the prefix of it that can be shown later.
+func GenerateAPIKey() (key, display string, err error) {
+	token, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+	key = APIKeyPrefix + token
+	return key, key[:apiKeyDisplayLength], nil
+}
+
+// IsAPIKey reports whether a bearer credential is an API key rather than a JWT.
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
@@ -0,0 +1,55 @@
+package security
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateOpaqueToken(t *testing.T) {
+	first, err := GenerateOpaqueToken()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, err := GenerateOpaqueToken()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(first) != 43 {
+		t.Errorf("expected a 43 character token, got %d", len(first))
+	}
+	if first == second {
+		t.Errorf("expected different tokens")
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	hash := HashToken("token")
+
+	if len(hash) != 64 {
+		t.Errorf("expected a 64 character hash, got %d", len(hash))
+	}
+	if hash != HashToken("token") {
+		t.Errorf("expected the same hash for the same token")
+	}
+	if hash == HashToken("other") {
+		t.Errorf("expected different hashes for different tokens")
+	}
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, display, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !IsAPIKey(key) {
+		t.Errorf("expected %q to be recognized as an API key", key)
+	}
+	if len(display) != 9 || !strings.HasPrefix(key, display) {
+		t.Errorf("expected a 9 character prefix of the key, got %q", display)
+	}
+	if IsAPIKey("eyJhbGciOiJSUzI1NiJ9.e30.sig") {
+		t.Errorf("expected a JWT not to be recognized as an API key")
+	}
+}
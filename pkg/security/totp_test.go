@@ -0,0 +1,82 @@
+package security
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 test secret of RFC 6238, appendix B.
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestValidateTOTP(t *testing.T) {
+	tests := []struct {
+		name  string
+		code  string
+		at    time.Time
+		step  int64
+		valid bool
+	}{
+		{name: "RFC 6238 vector at 59", code: "287082", at: time.Unix(59, 0), step: 1, valid: true},
+		{name: "RFC 6238 vector at 1111111109", code: "081804", at: time.Unix(1111111109, 0), step: 37037036, valid: true},
+		{name: "RFC 6238 vector at 1234567890", code: "005924", at: time.Unix(1234567890, 0), step: 41152263, valid: true},
+		{name: "Code of the previous period", code: "081804", at: time.Unix(1111111109+30, 0), step: 37037036, valid: true},
+		{name: "Code from two periods ago", code: "081804", at: time.Unix(1111111109+60, 0)},
+		{name: "Wrong code", code: "123456", at: time.Unix(59, 0)},
+		{name: "Wrong length", code: "28708", at: time.Unix(59, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, valid := ValidateTOTP(rfc6238Secret, tt.code, tt.at)
+			if valid != tt.valid {
+				t.Fatalf("expected valid %v, got %v", tt.valid, valid)
+			}
+			if step != tt.step {
+				t.Errorf("expected step %d, got %d", tt.step, step)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("expected a 32 character secret, got %d", len(secret))
+	}
+
+	code, err := GenerateTOTPCode(secret, time.Now())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, valid := ValidateTOTP(secret, code, time.Now()); !valid {
+		t.Errorf("expected the current code of a new secret to be valid")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := TOTPURI("Finly", "john@example.com", "SECRET")
+
+	expected := "otpauth://totp/Finly:john@example.com?algorithm=SHA1&digits=6&issuer=Finly&period=30&secret=SECRET"
+	if uri != expected {
+		t.Errorf("expected %s, got %s", expected, uri)
+	}
+}
+
+func TestRecoveryCode(t *testing.T) {
+	code, err := GenerateRecoveryCode()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(code) != 19 || strings.Count(code, "-") != 3 {
+		t.Errorf("expected a code of four groups, got %s", code)
+	}
+
+	if NormalizeRecoveryCode(" "+strings.ToUpper(code)+" ") != strings.ReplaceAll(code, "-", "") {
+		t.Errorf("expected normalization to drop case, spaces and separators")
+	}
+}
@@ -2,10 +2,8 @@ package server
 
 import (
 	"finly-backend/internal/transport/http/middleware"
-	validator2 "finly-backend/pkg/validator"
 	"fmt"
 	"github.com/labstack/echo/v4"
-	"go.uber.org/zap"
 )
 
 type Server struct {
@@ -13,16 +11,11 @@ type Server struct {
 	*echo.Echo
 }
 
-func NewServer(port string) *Server {
-	var err error
-
+func NewServer(port string, validator echo.Validator) *Server {
 	server := echo.New()
 	server.Use(middleware.RecoverMiddleware())
 	server.Use(middleware.CORSMiddleware())
-
-	if server.Validator, err = validator2.CustomValidator(); err != nil {
-		zap.L().Fatal("Error setting up custom validator", zap.Error(err))
-	}
+	server.Validator = validator
 
 	return &Server{
 		Port: port,
@@ -1,6 +1,7 @@
 package testutil
 
 import (
+	"finly-backend/pkg/security"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/redis/go-redis/v9"
 	"testing"
@@ -29,3 +30,27 @@ func SetupRepositoryTest(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, *redis.Client
 
 	return sqlxDB, mock, redisClient, mr, logger
 }
+
+// SigningKeyID and SigningKeySecret are the key SetupSigningKeys installs, for tests that
+// craft tokens themselves.
+const (
+	SigningKeyID     = "test"
+	SigningKeySecret = "test-signing-key-0123456789abcdef"
+)
+
+// SetupSigningKeys makes the test sign and verify tokens with a throwaway HMAC key.
+func SetupSigningKeys(t *testing.T) {
+	t.Helper()
+
+	key, err := security.ParseKey(SigningKeyID, SigningKeySecret)
+	if err != nil {
+		t.Fatalf("Failed to parse signing key: %v", err)
+	}
+	keys, err := security.NewKeySet(key)
+	if err != nil {
+		t.Fatalf("Failed to create key set: %v", err)
+	}
+
+	security.UseKeys(keys)
+	t.Cleanup(func() { security.UseKeys(nil) })
+}
@@ -65,6 +65,11 @@ func (v *Validator) Register(tag string, fn validator.Func) error {
 	return v.validator.RegisterValidation(tag, fn)
 }
 
+// RegisterCustomType makes rules on fields of the given types validate the value returned by fn.
+func (v *Validator) RegisterCustomType(fn validator.CustomTypeFunc, types ...any) {
+	v.validator.RegisterCustomTypeFunc(fn, types...)
+}
+
 // ValidateUUID is a custom validation function for UUIDs.
 func ValidateUUID(fl validator.FieldLevel) bool {
 	_, err := uuid.Parse(fl.Field().String())